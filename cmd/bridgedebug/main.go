@@ -0,0 +1,57 @@
+// Package bridgedebug provides tools to debug the bridge offline.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	clientIdentifier = "bridgedebug"
+	// Git SHA1 commit hash of the release (set via linker flags)
+	gitCommit = ""
+	gitDate   = ""
+	// The app that holds all commands and flags.
+	app = utils.NewApp(clientIdentifier, gitCommit, gitDate, "the bridgedebug command line interface")
+)
+
+func initApp() {
+	// Initialize the CLI app and start action
+	app.Action = bridgedebug
+	app.HideVersion = true // we have a command to print the version
+	app.Copyright = "Copyright 2017-2020 The CrossChain-Bridge Authors"
+	app.Commands = []*cli.Command{
+		replayCommand,
+		utils.LicenseCommand,
+		utils.VersionCommand,
+	}
+	app.Flags = []cli.Flag{
+		utils.VerbosityFlag,
+		utils.JSONFormatFlag,
+		utils.ColorFormatFlag,
+	}
+}
+
+func main() {
+	initApp()
+	if err := app.Run(os.Args); err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+}
+
+func bridgedebug(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	if ctx.NArg() > 0 {
+		return fmt.Errorf("invalid command: %q", ctx.Args().Get(0))
+	}
+
+	_ = cli.ShowAppHelp(ctx)
+	fmt.Println()
+	log.Fatalf("please specify a sub command to run")
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/internal/replay"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	replayCommand = &cli.Command{
+		Action:    replayBundle,
+		Name:      "replay",
+		Usage:     "replay verification of a captured bundle",
+		ArgsUsage: "<bundle>",
+		Description: `
+replay verification of a bundle captured by oracle when it disagreed a sign
+which other oracles agreed (see 'ReplayBundleDir' in oracle config).
+bridges are initialized from the bundled config, and all rpc calls are served
+from the bundled data by a local stub gateway, no network access is needed.
+`,
+	}
+)
+
+func replayBundle(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	if ctx.NArg() != 1 {
+		_ = cli.ShowCommandHelp(ctx, "replay")
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	bundle, err := replay.LoadBundle(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	args := bundle.Args
+	fmt.Println("keyID:", bundle.KeyID)
+	fmt.Println("swap:", args.SwapType.String(), args.PairID, args.SwapID, args.Bind)
+	fmt.Println("identifier:", args.Identifier)
+	fmt.Println("disagree reason:", bundle.DisagreeReason)
+	fmt.Println("capture result:", bundle.CaptureResult)
+	fmt.Println("recorded calls:", len(bundle.Calls), "truncated:", bundle.Truncated)
+
+	result, err := replay.Replay(bundle)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\ndecision trace:")
+	for i, trace := range result.Trace {
+		fmt.Printf("%3d %v\n", i, trace)
+	}
+
+	fmt.Println("\nreplay result:")
+	if result.Err != nil {
+		fmt.Println("verify failed:", result.Err)
+	} else {
+		fmt.Println("verify success")
+	}
+	if result.SwapInfo != nil {
+		data, _ := json.MarshalIndent(result.SwapInfo, "", "  ")
+		fmt.Println(string(data))
+	}
+	return nil
+}
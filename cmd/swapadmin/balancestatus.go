@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	balancestatusCommand = &cli.Command{
+		Action:    balancestatus,
+		Name:      "balancestatus",
+		Usage:     "admin get balance status of payer accounts",
+		ArgsUsage: " ",
+		Description: `
+admin get native balance status of dcrm payer accounts checked by balance monitor,
+including alert level, estimated burn per hour and projected time to empty
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func balancestatus(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "balancestatus"
+	if ctx.NArg() != 0 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("admin %v", method)
+
+	result, err := adminCall(method, []string{})
+
+	log.Printf("result is '%v'", result)
+	return err
+}
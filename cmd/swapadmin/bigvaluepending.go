@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	bigvaluePendingCommand = &cli.Command{
+		Action:    bigvaluePending,
+		Name:      "bigvaluepending",
+		Usage:     "admin list pending big value swaps",
+		ArgsUsage: "<swapin|swapout> [offset] [limit]",
+		Description: `
+admin list swaps waiting for big value review (oldest first, default limit 20, max 100),
+with sender info: first seen time, bridge volume per pair, distinct bind addresses and
+whether the sender is a contract
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func bigvaluePending(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "bigvaluepending"
+	if ctx.NArg() < 1 || ctx.NArg() > 3 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	switch operation := ctx.Args().Get(0); operation {
+	case swapinOp, swapoutOp:
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := ctx.Args().Slice()
+
+	log.Printf("admin %v %v", method, params)
+
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	bulkregisterCommand = &cli.Command{
+		Action:    bulkregister,
+		Name:      "bulkregister",
+		Usage:     "admin bulk register missed swaps",
+		ArgsUsage: "<swapin|swapout> <pairID> <txidsFile>",
+		Description: `
+admin bulk register missed swaps (eg. collected from an external indexer after an outage),
+txidsFile contains one txid per line (empty lines and lines starting with '#' are ignored).
+the txids are registered in background at the server's 'BulkRegisterRate',
+returns a job ID which can be queried by the 'bulkjobstatus' command
+`,
+		Flags: commonAdminFlags,
+	}
+
+	bulkjobstatusCommand = &cli.Command{
+		Action:    bulkjobstatus,
+		Name:      "bulkjobstatus",
+		Usage:     "admin get bulk register job status",
+		ArgsUsage: "<jobID>",
+		Description: `
+admin get bulk register job progress and per txid failures
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func bulkregister(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "bulkregister"
+	if ctx.NArg() != 3 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	direction := ctx.Args().Get(0)
+	pairID := ctx.Args().Get(1)
+	txids, err := readTxidsFile(ctx.Args().Get(2))
+	if err != nil {
+		return err
+	}
+
+	err = prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := append([]string{direction, pairID}, txids...)
+
+	log.Printf("admin %v: %v %v with %v txids", method, direction, pairID, len(txids))
+
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func bulkjobstatus(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "bulkjobstatus"
+	if ctx.NArg() != 1 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := []string{ctx.Args().Get(0)}
+
+	log.Printf("admin %v: %v", method, params)
+
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func readTxidsFile(fileName string) (txids []string, err error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		txids = append(txids, line)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(txids) == 0 {
+		return nil, fmt.Errorf("no txids in file %v", fileName)
+	}
+	return txids, nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	dailyreportCommand = &cli.Command{
+		Action:    dailyreport,
+		Name:      "dailyreport",
+		Usage:     "admin regenerate daily report",
+		ArgsUsage: "<date>",
+		Description: `
+admin regenerate daily summary report of an ended date (format 2006-01-02),
+the stored report is replaced and pushed again if 'PushURL' is configured
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func dailyreport(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "dailyreport"
+	if ctx.NArg() != 1 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := ctx.Args().Slice()
+
+	log.Printf("admin %v: %v", method, params)
+
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	debugverifyCommand = &cli.Command{
+		Action:    debugverify,
+		Name:      "debugverify",
+		Usage:     "admin dry run verification of tx",
+		ArgsUsage: "<swapin|swapout> <pairID> <txid> [unstable]",
+		Description: `
+admin dry run the bridge's verification of tx without registering swap,
+returns the decoded swap info (bind, value, from, txTo, height) and each check annotated pass/fail.
+pass 'unstable' to verify tx not reaching stable confirmations yet
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func debugverify(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "debugverify"
+	if ctx.NArg() < 3 || ctx.NArg() > 4 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := ctx.Args().Slice()
+
+	log.Printf("admin %v: %v", method, params)
+
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	disagreementsCommand = &cli.Command{
+		Action:    disagreements,
+		Name:      "disagreements",
+		Usage:     "admin get recent sign disagreements",
+		ArgsUsage: "[limit]",
+		Description: `
+admin get recent agree/disagree splits between sign initiator and acceptors,
+grouped by the differing check (value, gas, confirmations, msghash, config, other, unknown)
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func disagreements(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "disagreements"
+	if ctx.NArg() > 1 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := ctx.Args().Slice()
+
+	log.Printf("admin %v %v", method, params)
+
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
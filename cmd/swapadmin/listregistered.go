@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	listregisteredCommand = &cli.Command{
+		Action:    listregistered,
+		Name:      "listregistered",
+		Usage:     "admin list registered addresses",
+		ArgsUsage: "[prefix] [offset] [limit]",
+		Description: `
+admin list registered addresses with address prefix (empty string matches all),
+with their linked p2sh addresses and the total count (for reconciliation)
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func listregistered(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "listregistered"
+	if ctx.NArg() > 3 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := ctx.Args().Slice()
+
+	log.Printf("admin %v: %v", method, params)
+
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
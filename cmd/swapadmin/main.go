@@ -30,8 +30,31 @@ func initApp() {
 		blacklistCommand,
 		reverifyCommand,
 		reswapCommand,
+		requeueCommand,
+		addnoteCommand,
+		getnotesCommand,
+		reconcileCommand,
+		signattemptsCommand,
+		signsearchCommand,
+		reloadgatewayCommand,
+		p2shCommand,
+		listregisteredCommand,
+		bulkregisterCommand,
+		bulkjobstatusCommand,
+		debugverifyCommand,
+		dailyreportCommand,
+		balancestatusCommand,
+		disagreementsCommand,
+		bigvaluePendingCommand,
+		unsupportedDepositsCommand,
+		refundCommand,
 		replaceswapCommand,
 		manualCommand,
+		passswapCommand,
+		failswapCommand,
+		adminauditsCommand,
+		swapdetailCommand,
+		audittrailCommand,
 		setnonceCommand,
 		addpairCommand,
 		utils.LicenseCommand,
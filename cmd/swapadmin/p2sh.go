@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	p2shCommand = &cli.Command{
+		Action:    p2sh,
+		Name:      "p2sh",
+		Usage:     "admin p2sh addresses",
+		ArgsUsage: "<stats|reactivate|list> [bindAddress|offset] [limit]",
+		Description: `
+admin query active and inactive p2sh address counts,
+or force reactivation of inactive p2sh address (and backfill its recent swapins),
+or list registered p2sh addresses with their bind addresses (for reconciliation)
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func p2sh(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "p2sh"
+	if ctx.NArg() < 1 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	operation := ctx.Args().Get(0)
+	params := []string{operation}
+	switch operation {
+	case "stats":
+	case "reactivate":
+		if ctx.NArg() != 2 {
+			return fmt.Errorf("reactivate need bind address argument")
+		}
+		params = append(params, ctx.Args().Get(1))
+	case "list":
+		if ctx.NArg() > 3 {
+			return fmt.Errorf("list need at most offset and limit arguments")
+		}
+		params = append(params, ctx.Args().Slice()[1:]...)
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+
+	log.Printf("admin %v: %v", method, params)
+
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
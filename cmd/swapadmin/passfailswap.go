@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	passswapCommand = &cli.Command{
+		Action:    passOrFailSwap,
+		Name:      "passswap",
+		Usage:     "admin pass stuck swap",
+		ArgsUsage: "<swapin|swapout> <txid> <pairID> <bind> <reason>",
+		Description: `
+admin pass stuck swap with audit. swap verified at stable depth goes to TxNotSwapped,
+otherwise it goes to TxNotStable to reverify. swap with stable swap tx is refused.
+`,
+		Flags: commonAdminFlags,
+	}
+
+	failswapCommand = &cli.Command{
+		Action:    passOrFailSwap,
+		Name:      "failswap",
+		Usage:     "admin fail stuck swap",
+		ArgsUsage: "<swapin|swapout> <txid> <pairID> <bind> <reason>",
+		Description: `
+admin fail stuck swap with audit, the status becomes ManualMakeFail. swap with stable swap tx is refused.
+`,
+		Flags: commonAdminFlags,
+	}
+
+	adminauditsCommand = &cli.Command{
+		Action:    adminaudits,
+		Name:      "adminaudits",
+		Usage:     "admin get audits of swap",
+		ArgsUsage: "<swapin|swapout> <txid> <pairID> <bind>",
+		Description: `
+admin get audits of passswap and failswap operations on swap
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func passOrFailSwap(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := ctx.Command.Name
+	if ctx.NArg() != 5 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	operation := ctx.Args().Get(0)
+	txid := ctx.Args().Get(1)
+	pairID := ctx.Args().Get(2)
+	bind := ctx.Args().Get(3)
+	reason := ctx.Args().Get(4)
+
+	switch operation {
+	case swapinOp, swapoutOp:
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+
+	log.Printf("admin %v: %v %v %v %v", method, operation, txid, pairID, bind)
+
+	params := []string{operation, txid, pairID, bind, reason}
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func adminaudits(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "adminaudits"
+	if ctx.NArg() != 4 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+	return reverifyOrReswap(ctx, method)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	reconcileCommand = &cli.Command{
+		Action:    reconcile,
+		Name:      "reconcile",
+		Usage:     "admin startup reconcile",
+		ArgsUsage: "<report|confirm>",
+		Description: `
+admin query startup reconcile report, or confirm it to resume swap processing
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func reconcile(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "reconcile"
+	if ctx.NArg() != 1 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	operation := ctx.Args().Get(0)
+	switch operation {
+	case "report", "confirm":
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+
+	log.Printf("admin %v: %v", method, operation)
+
+	result, err := adminCall(method, []string{operation})
+
+	log.Printf("result is '%v'", result)
+	return err
+}
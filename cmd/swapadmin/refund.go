@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	refundCommand = &cli.Command{
+		Action:    refund,
+		Name:      "refund",
+		Usage:     "admin refund swap which can never complete",
+		ArgsUsage: "<swapin|swapout> <txid> <pairID> <bind>",
+		Description: `
+admin refund deposit (minus refund fee) to its sender on the deposit chain,
+only swaps with status TxWithWrongValue, TxWithWrongMemo or BindAddrIsContract can refund,
+refund whose tx failed to be sent can be initiated again
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func refund(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "refund"
+	if ctx.NArg() != 4 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+	return reverifyOrReswap(ctx, method)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	reloadgatewayCommand = &cli.Command{
+		Action: reloadgateway,
+		Name:   "reloadgateway",
+		Usage:  "admin reload gateway api addresses",
+		Description: `
+admin reload source and dest gateway api addresses from server config file without restart
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func reloadgateway(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "reloadgateway"
+	if ctx.NArg() != 0 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("admin %v", method)
+
+	result, err := adminCall(method, []string{})
+
+	log.Printf("result is '%v'", result)
+	return err
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	requeueCommand = &cli.Command{
+		Action:    requeue,
+		Name:      "requeue",
+		Usage:     "admin requeue quarantined swap",
+		ArgsUsage: "<swapin|swapout> <txid> <pairID> <bind>",
+		Description: `
+admin requeue quarantined swap to its previous status
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func requeue(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "requeue"
+	if ctx.NArg() != 4 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+	return reverifyOrReswap(ctx, method)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	signattemptsCommand = &cli.Command{
+		Action:    signattempts,
+		Name:      "signattempts",
+		Usage:     "admin get dcrm sign attempts of swap",
+		ArgsUsage: "<swapin|swapout> <txid> <pairID> <bind>",
+		Description: `
+admin get dcrm sign attempts (keyID, initiate time, dcrm status, finish time) of swap
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func signattempts(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "signattempts"
+	if ctx.NArg() != 4 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+	return reverifyOrReswap(ctx, method)
+}
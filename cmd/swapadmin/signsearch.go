@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	signsearchCommand = &cli.Command{
+		Action:    signsearch,
+		Name:      "signsearch",
+		Usage:     "admin search swaps with dcrm sign attempts of initiator",
+		ArgsUsage: "<swapin|swapout> <initiator> [offset] [limit]",
+		Description: `
+admin search latest swaps with dcrm sign attempts initiated by the dcrm account,
+limit is 20 by default and at most 100
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func signsearch(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "signsearch"
+	if ctx.NArg() < 2 || ctx.NArg() > 4 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	operation := ctx.Args().Get(0)
+	switch operation {
+	case swapinOp, swapoutOp:
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := ctx.Args().Slice()
+	log.Printf("admin %v: %v", method, params)
+
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	swapdetailCommand = &cli.Command{
+		Action:    swapdetail,
+		Name:      "swapdetail",
+		Usage:     "admin get detail of swap",
+		ArgsUsage: "<swapin|swapout> <txid> <pairID> <bind>",
+		Description: `
+admin get swap with its operator notes and admin audits
+`,
+		Flags: commonAdminFlags,
+	}
+
+	audittrailCSVFlag = &cli.StringFlag{
+		Name:  "csv",
+		Usage: "export audit trail to this csv file",
+	}
+
+	audittrailCommand = &cli.Command{
+		Action:    audittrail,
+		Name:      "audittrail",
+		Usage:     "admin export audit trail of swap",
+		ArgsUsage: "<swapin|swapout> <txid> <pairID> <bind>",
+		Description: `
+admin export admin audits and operator notes of swap in time order,
+with '--csv' the audit trail is exported to the csv file.
+`,
+		Flags: append([]cli.Flag{audittrailCSVFlag}, commonAdminFlags...),
+	}
+)
+
+type auditTrailEntry struct {
+	Timestamp int64  `json:"timestamp"`
+	Kind      string `json:"kind"`
+	Operator  string `json:"operator"`
+	Operation string `json:"operation"`
+	OldStatus uint16 `json:"oldstatus"`
+	NewStatus uint16 `json:"newstatus"`
+	Text      string `json:"text"`
+}
+
+func swapdetail(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "swapdetail"
+	if ctx.NArg() != 4 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+	return reverifyOrReswap(ctx, method)
+}
+
+func audittrail(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "audittrail"
+	if ctx.NArg() != 4 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+	csvFile := ctx.String(audittrailCSVFlag.Name)
+	if csvFile == "" {
+		return reverifyOrReswap(ctx, method)
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+	operation := ctx.Args().Get(0)
+	switch operation {
+	case swapinOp, swapoutOp:
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	result, err := adminCall(method, ctx.Args().Slice())
+	if err != nil {
+		return err
+	}
+	data, ok := result.(string)
+	if !ok {
+		return fmt.Errorf("wrong result type %T", result)
+	}
+	var trail []*auditTrailEntry
+	if err = json.Unmarshal([]byte(data), &trail); err != nil {
+		return err
+	}
+	return exportAuditTrail(trail, csvFile)
+}
+
+func exportAuditTrail(trail []*auditTrailEntry, csvFile string) error {
+	file, err := os.Create(csvFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	err = writer.Write([]string{"timestamp", "kind", "operator", "operation", "oldstatus", "newstatus", "text"})
+	if err != nil {
+		return err
+	}
+	for _, entry := range trail {
+		err = writer.Write([]string{
+			strconv.FormatInt(entry.Timestamp, 10),
+			entry.Kind,
+			entry.Operator,
+			entry.Operation,
+			strconv.FormatUint(uint64(entry.OldStatus), 10),
+			strconv.FormatUint(uint64(entry.NewStatus), 10),
+			entry.Text,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		return err
+	}
+	log.Printf("exported %v audit trail entries to %v", len(trail), csvFile)
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	addnoteCommand = &cli.Command{
+		Action:    addnote,
+		Name:      "addnote",
+		Usage:     "admin add note to swap",
+		ArgsUsage: "<swapin|swapout> <txid> <pairID> <bind> <note>",
+		Description: `
+admin append operator note to swap, notes can not be modified or deleted
+`,
+		Flags: commonAdminFlags,
+	}
+
+	getnotesCommand = &cli.Command{
+		Action:    getnotes,
+		Name:      "getnotes",
+		Usage:     "admin get notes of swap",
+		ArgsUsage: "<swapin|swapout> <txid> <pairID> <bind>",
+		Description: `
+admin get operator notes of swap
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func addnote(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "addnote"
+	if ctx.NArg() != 5 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	operation := ctx.Args().Get(0)
+	txid := ctx.Args().Get(1)
+	pairID := ctx.Args().Get(2)
+	bind := ctx.Args().Get(3)
+	note := ctx.Args().Get(4)
+
+	switch operation {
+	case swapinOp, swapoutOp:
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+
+	log.Printf("admin %v: %v %v %v %v", method, operation, txid, pairID, bind)
+
+	params := []string{operation, txid, pairID, bind, note}
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func getnotes(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "getnotes"
+	if ctx.NArg() != 4 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+	return reverifyOrReswap(ctx, method)
+}
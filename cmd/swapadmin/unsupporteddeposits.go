@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+const exportUnsupportedDepositsBatch = 1000 // max limit of admin call
+
+var (
+	unsupportedDepositsCSVFlag = &cli.StringFlag{
+		Name:  "csv",
+		Usage: "export all unsupported deposits to this csv file",
+	}
+
+	unsupportedDepositsCommand = &cli.Command{
+		Action:    unsupportedDeposits,
+		Name:      "unsupporteddeposits",
+		Usage:     "admin list deposits of unsupported tokens",
+		ArgsUsage: "[offset] [limit]",
+		Description: `
+admin list erc20 transfers of unsupported tokens to deposit addresses (in block height order,
+default limit 100, max 1000), with token contract, sender, value and txid.
+with '--csv' all of them are exported to the csv file for recovery campaigns.
+`,
+		Flags: append([]cli.Flag{unsupportedDepositsCSVFlag}, commonAdminFlags...),
+	}
+)
+
+type unsupportedDeposit struct {
+	TxID        string `json:"txid"`
+	LogIndex    uint   `json:"logIndex"`
+	Token       string `json:"token"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Value       string `json:"value"`
+	BlockHeight uint64 `json:"blockHeight"`
+	Timestamp   int64  `json:"timestamp"`
+}
+
+func unsupportedDeposits(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "unsupporteddeposits"
+	csvFile := ctx.String(unsupportedDepositsCSVFlag.Name)
+	if ctx.NArg() > 2 || (csvFile != "" && ctx.NArg() > 0) {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	if csvFile != "" {
+		return exportUnsupportedDeposits(method, csvFile)
+	}
+
+	params := ctx.Args().Slice()
+
+	log.Printf("admin %v: %v", method, params)
+
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func exportUnsupportedDeposits(method, csvFile string) error {
+	file, err := os.Create(csvFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	err = writer.Write([]string{"txid", "logIndex", "token", "from", "to", "value", "blockHeight", "timestamp"})
+	if err != nil {
+		return err
+	}
+	total := 0
+	for offset := 0; ; offset += exportUnsupportedDepositsBatch {
+		params := []string{strconv.Itoa(offset), strconv.Itoa(exportUnsupportedDepositsBatch)}
+		result, err := adminCall(method, params)
+		if err != nil {
+			return err
+		}
+		data, ok := result.(string)
+		if !ok {
+			return fmt.Errorf("wrong result type %T", result)
+		}
+		var deposits []*unsupportedDeposit
+		if err = json.Unmarshal([]byte(data), &deposits); err != nil {
+			return err
+		}
+		for _, d := range deposits {
+			err = writer.Write([]string{
+				d.TxID,
+				strconv.FormatUint(uint64(d.LogIndex), 10),
+				d.Token,
+				d.From,
+				d.To,
+				d.Value,
+				strconv.FormatUint(d.BlockHeight, 10),
+				strconv.FormatInt(d.Timestamp, 10),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		total += len(deposits)
+		if len(deposits) < exportUnsupportedDepositsBatch {
+			break
+		}
+	}
+	writer.Flush()
+	if err = writer.Error(); err != nil {
+		return err
+	}
+	log.Printf("exported %v unsupported deposits to %v", total, csvFile)
+	return nil
+}
@@ -6,7 +6,9 @@ import (
 	"os"
 
 	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/internal/metrics"
 	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
 	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/worker"
@@ -59,10 +61,27 @@ func swaporacle(ctx *cli.Context) error {
 	}
 	params.SetDataDir(utils.GetDataDir(ctx))
 	configFile := utils.GetConfigFilePath(ctx)
-	params.LoadConfig(configFile, false)
+	config := params.LoadConfig(configFile, false)
 
 	tokens.SetTokenPairsDir(utils.GetTokenPairsDir(ctx))
 
+	if dbConfig := config.Oracle.MongoDB; dbConfig != nil && !params.IsTestMode() {
+		mongodb.MongoServerInit(
+			params.GetIdentifier(),
+			dbConfig.DBURLs,
+			dbConfig.DBName,
+			dbConfig.UserName,
+			dbConfig.Password,
+		)
+	}
+
+	if params.IsMetricsEnabled() {
+		metrics.Enable()
+		if port := params.GetMetricsPort(); port != 0 {
+			metrics.StartServer(port)
+		}
+	}
+
 	worker.StartWork(false)
 
 	utils.TopWaitGroup.Wait()
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/internal/metrics"
+	"github.com/anyswap/CrossChain-Bridge/internal/swapapi"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/mongodb"
 	"github.com/anyswap/CrossChain-Bridge/params"
@@ -77,6 +79,21 @@ func swapserver(ctx *cli.Context) error {
 			dbConfig.UserName,
 			dbConfig.Password,
 		)
+		mongodb.SetMaxMemoLength(dbConfig.MaxMemoLength)
+		if config.Server.EnableSwapEvents {
+			mongodb.EnableSwapEvents()
+		}
+	}
+
+	if err := swapapi.InitResponseSigner(); err != nil {
+		log.Fatal("init response signer failed", "err", err)
+	}
+
+	if params.IsMetricsEnabled() {
+		metrics.Enable()
+		if port := params.GetMetricsPort(); port != 0 {
+			metrics.StartServer(port)
+		}
 	}
 
 	worker.StartWork(true)
@@ -23,6 +23,9 @@ const (
 	successStatus = "Success"
 )
 
+// sign infos are responded with their msg context, cap the size before decoding
+var maxSignInfoResponseSize int64 = 4 * 1024 * 1024 // 4M
+
 func newWrongStatusError(subject, status, errInfo string) error {
 	return fmt.Errorf("[%v] Wrong status \"%v\", err=\"%v\"", subject, status, errInfo)
 }
@@ -71,19 +74,9 @@ func GetSignNonce(dcrmUser, rpcAddr string) (uint64, error) {
 
 // GetSignStatus call getSignStatus
 func GetSignStatus(key, rpcAddr string) (*SignStatus, error) {
-	var result DataResultResp
-	err := httpPostTo(&result, rpcAddr, "getSignStatus", key)
-	if err != nil {
-		return nil, wrapPostError("getSignStatus", err)
-	}
-	if result.Status != successStatus {
-		return nil, newWrongStatusError("getSignStatus", result.Status, "response error "+result.Error)
-	}
-	data := result.Data.Result
-	var signStatus SignStatus
-	err = json.Unmarshal([]byte(data), &signStatus)
+	signStatus, data, err := getSignStatus(key, rpcAddr)
 	if err != nil {
-		return nil, wrapPostError("getSignStatus", err)
+		return nil, err
 	}
 	switch signStatus.Status {
 	case "Failure":
@@ -96,26 +89,47 @@ func GetSignStatus(key, rpcAddr string) (*SignStatus, error) {
 		log.Info("getSignStatus Timeout", "keyID", key, "status", data)
 		return nil, ErrGetSignStatusTimeout
 	case successStatus:
-		return &signStatus, nil
+		return signStatus, nil
 	default:
 		return nil, newWrongStatusError("getSignStatus", signStatus.Status, "sign status error "+signStatus.Error)
 	}
 }
 
+// GetSignStatusDetail call getSignStatus of default dcrm node,
+// return status with replies of all nodes even if it's failure or timeout
+func GetSignStatusDetail(key string) (*SignStatus, error) {
+	signStatus, _, err := getSignStatus(key, defaultDcrmNode.dcrmRPCAddress)
+	return signStatus, err
+}
+
+func getSignStatus(key, rpcAddr string) (*SignStatus, string, error) {
+	var result DataResultResp
+	err := httpPostTo(&result, rpcAddr, "getSignStatus", key)
+	if err != nil {
+		return nil, "", wrapPostError("getSignStatus", err)
+	}
+	if result.Status != successStatus {
+		return nil, "", newWrongStatusError("getSignStatus", result.Status, "response error "+result.Error)
+	}
+	data := result.Data.Result
+	var signStatus SignStatus
+	err = json.Unmarshal([]byte(data), &signStatus)
+	if err != nil {
+		return nil, "", wrapPostError("getSignStatus", err)
+	}
+	return &signStatus, data, nil
+}
+
 // GetCurNodeSignInfo call getCurNodeSignInfo
 // filter out invalid sign info and
 // filter out expired sign info if `expiredInterval` is greater than 0
 func GetCurNodeSignInfo(expiredInterval int64) ([]*SignInfoData, error) {
-	var result SignInfoResp
-	err := httpPost(&result, "getCurNodeSignInfo", defaultDcrmNode.keyWrapper.Address.String())
+	signInfos, err := getCurNodeSignInfo(defaultDcrmNode.keyWrapper.Address.String(), defaultDcrmNode.dcrmRPCAddress)
 	if err != nil {
-		return nil, wrapPostError("getCurNodeSignInfo", err)
-	}
-	if result.Status != successStatus {
-		return nil, newWrongStatusError("getCurNodeSignInfo", result.Status, result.Error)
+		return nil, err
 	}
-	signInfoSortedSlice := make(SignInfoSortedSlice, 0, len(result.Data))
-	for _, signInfo := range result.Data {
+	signInfoSortedSlice := make(SignInfoSortedSlice, 0, len(signInfos))
+	for _, signInfo := range signInfos {
 		if !signInfo.IsValid() {
 			log.Trace("filter out invalid sign info", "signInfo", signInfo)
 			continue
@@ -131,6 +145,20 @@ func GetCurNodeSignInfo(expiredInterval int64) ([]*SignInfoData, error) {
 	return signInfoSortedSlice, nil
 }
 
+func getCurNodeSignInfo(account, rpcAddr string) ([]*SignInfoData, error) {
+	var result SignInfoResp
+	req := client.NewRequestWithTimeoutAndID(dcrmRPCTimeout, 1, dcrmAPIPrefix+"getCurNodeSignInfo", account)
+	req.MaxResponseSize = maxSignInfoResponseSize
+	err := client.RPCPostRequest(rpcAddr, req, &result)
+	if err != nil {
+		return nil, wrapPostError("getCurNodeSignInfo", err)
+	}
+	if result.Status != successStatus {
+		return nil, newWrongStatusError("getCurNodeSignInfo", result.Status, result.Error)
+	}
+	return result.Data, nil
+}
+
 // Sign call sign
 func Sign(raw, rpcAddr string) (string, error) {
 	var result DataResultResp
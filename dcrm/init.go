@@ -10,6 +10,7 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/common"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
 	"github.com/anyswap/CrossChain-Bridge/tools"
 	"github.com/anyswap/CrossChain-Bridge/tools/keystore"
 	"github.com/anyswap/CrossChain-Bridge/types"
@@ -42,6 +43,9 @@ var (
 
 	selfEnode string
 	allEnodes []string
+
+	// dcrm group profiles, key is public key hex (lower case, no 0x prefix)
+	groupProfiles = make(map[string]*params.DcrmGroupProfile)
 )
 
 func isECDSA() bool {
@@ -80,6 +84,8 @@ func Init(dcrmConfig *params.DcrmConfig, isServer bool) {
 	verifySignatureInAccept = dcrmConfig.VerifySignatureInAccept
 
 	setDcrmGroup(*dcrmConfig.GroupID, dcrmConfig.Mode, *dcrmConfig.NeededOracles, *dcrmConfig.TotalOracles)
+	setGroupProfiles(dcrmConfig.GroupProfiles)
+	initDcrmProxy(dcrmConfig)
 	setDefaultDcrmNodeInfo(initDcrmNodeInfo(dcrmConfig.DefaultNode, isServer))
 
 	if isServer {
@@ -140,6 +146,27 @@ func setDcrmGroup(group string, mode, neededOracles, totalOracles uint32) {
 	log.Info("Init dcrm group", "group", dcrmGroupID, "threshold", dcrmThreshold, "mode", dcrmMode)
 }
 
+// setGroupProfiles set dcrm group profiles
+func setGroupProfiles(profiles map[string]*params.DcrmGroupProfile) {
+	for name, profile := range profiles {
+		groupProfiles[getPubkeyKey(profile.Pubkey)] = profile
+		log.Info("Init dcrm group profile", "name", name, "group", profile.GroupID, "threshold", profile.GetThreshold(), "signGroups", len(profile.SignGroups))
+	}
+}
+
+func getPubkeyKey(pubkey string) string {
+	return common.Bytes2Hex(common.FromHex(pubkey))
+}
+
+// getSignGroups get sign subgroups and threshold to sign with public key,
+// keys of dcrm group profiles are signed by the profile's sign groups.
+func getSignGroups(dcrmNode *NodeInfo, signPubkey string) (signGroups []string, threshold string) {
+	if profile, exist := groupProfiles[getPubkeyKey(signPubkey)]; exist {
+		return profile.SignGroups, profile.GetThreshold()
+	}
+	return dcrmNode.signGroups, dcrmThreshold
+}
+
 // GetGroupID return dcrm group id
 func GetGroupID() string {
 	return dcrmGroupID
@@ -283,6 +310,22 @@ func verifyInitiators(initiators []string) {
 	}
 }
 
+// initDcrmProxy set proxy of all configed dcrm nodes (including other nodes not used by oracles)
+func initDcrmProxy(dcrmConfig *params.DcrmConfig) {
+	proxy := params.GetDcrmProxy()
+	if proxy == "" {
+		return
+	}
+	rpcAddresses := []string{*dcrmConfig.DefaultNode.RPCAddress}
+	for _, nodeCfg := range dcrmConfig.OtherNodes {
+		rpcAddresses = append(rpcAddresses, *nodeCfg.RPCAddress)
+	}
+	if err := client.SetProxy(rpcAddresses, proxy); err != nil {
+		log.Fatal("set dcrm proxy failed", "err", err)
+	}
+	log.Info("Init dcrm proxy", "rpcaddresses", rpcAddresses, "proxy", client.RedactProxy(proxy))
+}
+
 func initDcrmNodeInfo(dcrmNodeCfg *params.DcrmNodeConfig, isServer bool) *NodeInfo {
 	dcrmNodeInfo := &NodeInfo{}
 	dcrmNodeInfo.setDcrmRPCAddress(*dcrmNodeCfg.RPCAddress)
@@ -0,0 +1,82 @@
+package dcrm
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+// check initiator account of at most this number of signs created by each initiator node,
+// the key may be not listed in sign infos if the node need not accept its own sign.
+const maxInitiatorAccountChecks = 3
+
+var (
+	errInitiatorAccountMismatch = errors.New("dcrm reports different initiator account")
+
+	initiatorChecks     = make(map[string]*initiatorCheck) // key is lower case dcrm user
+	initiatorChecksLock sync.Mutex
+)
+
+type initiatorCheck struct {
+	checks   int
+	done     bool
+	mismatch bool
+}
+
+// checkInitiatorMismatched returns error if dcrm has reported different initiator account
+func checkInitiatorMismatched(dcrmNode *NodeInfo) error {
+	initiatorChecksLock.Lock()
+	defer initiatorChecksLock.Unlock()
+	if state, exist := initiatorChecks[strings.ToLower(dcrmNode.dcrmUser.String())]; exist && state.mismatch {
+		return errInitiatorAccountMismatch
+	}
+	return nil
+}
+
+// checkInitiatorAccount check the configured dcrm user is the initiator account
+// which dcrm reports in sign info of the key created by this node.
+func checkInitiatorAccount(dcrmNode *NodeInfo, keyID string) error {
+	dcrmUser := dcrmNode.dcrmUser.String()
+	key := strings.ToLower(dcrmUser)
+
+	initiatorChecksLock.Lock()
+	state, exist := initiatorChecks[key]
+	if !exist {
+		state = &initiatorCheck{}
+		initiatorChecks[key] = state
+	}
+	if state.mismatch {
+		initiatorChecksLock.Unlock()
+		return errInitiatorAccountMismatch
+	}
+	if state.done || state.checks >= maxInitiatorAccountChecks {
+		initiatorChecksLock.Unlock()
+		return nil
+	}
+	state.checks++
+	initiatorChecksLock.Unlock()
+
+	signInfos, err := getCurNodeSignInfo(dcrmUser, dcrmNode.dcrmRPCAddress)
+	if err != nil {
+		log.Warn("check initiator account failed", "keyID", keyID, "user", dcrmUser, "err", err)
+		return nil
+	}
+	for _, signInfo := range signInfos {
+		if signInfo.Key != keyID {
+			continue
+		}
+		initiatorChecksLock.Lock()
+		defer initiatorChecksLock.Unlock()
+		if !strings.EqualFold(signInfo.Account, dcrmUser) {
+			state.mismatch = true
+			log.Error("dcrm initiator account mismatch, stop signing with this node", "keyID", keyID, "configured", dcrmUser, "reported", signInfo.Account, "rpcAddr", dcrmNode.dcrmRPCAddress)
+			return errInitiatorAccountMismatch
+		}
+		state.done = true
+		log.Info("check initiator account success", "keyID", keyID, "user", dcrmUser)
+		return nil
+	}
+	return nil
+}
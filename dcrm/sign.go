@@ -1,6 +1,7 @@
 package dcrm
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/json"
 	"errors"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/internal/retry"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/mongodb"
 	"github.com/anyswap/CrossChain-Bridge/params"
@@ -18,20 +20,57 @@ import (
 )
 
 const (
-	pingCount     = 3
-	retrySignLoop = 3
+	pingCount = 3
 )
 
 var (
-	errSignIsDisabled       = errors.New("sign is disabled")
-	errSignTimerTimeout     = errors.New("sign timer timeout")
-	errDoSignFailed         = errors.New("do sign failed")
-	errSignWithoutPublickey = errors.New("sign without public key")
-	errGetSignResultFailed  = errors.New("get sign result failed")
-	errRValueIsUsed         = errors.New("r value is already used")
-	errWrongSignatureLength = errors.New("wrong signature length")
+	errSignIsDisabled        = errors.New("sign is disabled")
+	errSignTimerTimeout      = errors.New("sign timer timeout")
+	errDoSignFailed          = errors.New("do sign failed")
+	errSignWithoutPublickey  = errors.New("sign without public key")
+	errGetSignResultFailed   = errors.New("get sign result failed")
+	errRValueIsUsed          = errors.New("r value is already used")
+	errWrongSignatureLength  = errors.New("wrong signature length")
+	errWrongSignatureContext = errors.New("wrong signature in msg context")
+
+	signAttemptHandler func(msgContext []string, attempt *SignAttempt)
+)
+
+const (
+	// pending sign status before getting the sign result
+	pendingSignStatus = "Pending"
+
+	// SignStatusDisagree sign status when some nodes disagree
+	SignStatusDisagree = "DisAgree"
 )
 
+// SetSignAttemptHandler set handler to observe sign attempts,
+// the handler is called when sign is initiated and when it is finished.
+func SetSignAttemptHandler(handler func(msgContext []string, attempt *SignAttempt)) {
+	signAttemptHandler = handler
+}
+
+func notifySignAttempt(msgContext []string, attempt *SignAttempt) {
+	if signAttemptHandler != nil {
+		signAttemptHandler(msgContext, attempt)
+	}
+}
+
+func getSignStatusString(err error) string {
+	switch {
+	case err == nil:
+		return successStatus
+	case errors.Is(err, ErrGetSignStatusHasDisagree):
+		return SignStatusDisagree
+	case errors.Is(err, ErrGetSignStatusFailed):
+		return "Failure"
+	case errors.Is(err, ErrGetSignStatusTimeout):
+		return "Timeout"
+	default:
+		return err.Error()
+	}
+}
+
 func pingDcrmNode(nodeInfo *NodeInfo) (err error) {
 	rpcAddr := nodeInfo.dcrmRPCAddress
 	for j := 0; j < pingCount; j++ {
@@ -45,6 +84,24 @@ func pingDcrmNode(nodeInfo *NodeInfo) (err error) {
 	return err
 }
 
+// PingDcrmNodes ping initiator nodes (default node if not server) once,
+// returns nil if any node is reachable.
+func PingDcrmNodes() (err error) {
+	nodes := allInitiatorNodes
+	if len(nodes) == 0 && defaultDcrmNode != nil {
+		nodes = []*NodeInfo{defaultDcrmNode}
+	}
+	if len(nodes) == 0 {
+		return errors.New("no dcrm node")
+	}
+	for _, nodeInfo := range nodes {
+		if _, err = GetEnode(nodeInfo.dcrmRPCAddress); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
 // DoSignOne dcrm sign single msgHash with context msgContext
 func DoSignOne(signPubkey, msgHash, msgContext string) (keyID string, rsvs []string, err error) {
 	return DoSign(signPubkey, []string{msgHash}, []string{msgContext})
@@ -59,20 +116,21 @@ func DoSign(signPubkey string, msgHash, msgContext []string) (keyID string, rsvs
 	if signPubkey == "" {
 		return "", nil, errSignWithoutPublickey
 	}
-	for i := 0; i < retrySignLoop; i++ {
+	err = retry.Do(context.Background(), "dcrm.DoSign", retry.GetPolicy(retry.SubsystemDcrm), nil, func() (err error) {
 		for _, dcrmNode := range allInitiatorNodes {
 			if err = pingDcrmNode(dcrmNode); err != nil {
 				continue
 			}
-			signGroupsCount := int64(len(dcrmNode.signGroups))
+			signGroups, threshold := getSignGroups(dcrmNode, signPubkey)
+			signGroupsCount := int64(len(signGroups))
 			// randomly pick first subgroup to sign
 			randIndex, _ := rand.Int(rand.Reader, big.NewInt(signGroupsCount))
 			startIndex := randIndex.Int64()
 			i := startIndex
 			for {
-				keyID, rsvs, err = doSignImpl(dcrmNode, i, signPubkey, msgHash, msgContext)
+				keyID, rsvs, err = doSignImpl(dcrmNode, signGroups[i], threshold, signPubkey, msgHash, msgContext)
 				if err == nil {
-					return keyID, rsvs, nil
+					return nil
 				}
 				i = (i + 1) % signGroupsCount
 				if i == startIndex {
@@ -80,13 +138,19 @@ func DoSign(signPubkey string, msgHash, msgContext []string) (keyID string, rsvs
 				}
 			}
 		}
-		time.Sleep(2 * time.Second)
+		return err
+	})
+	if err != nil {
+		log.Warn("dcrm DoSign failed", "msgHash", msgHash, "msgContext", msgContext, "err", err)
+		return "", nil, errDoSignFailed
 	}
-	log.Warn("dcrm DoSign failed", "msgHash", msgHash, "msgContext", msgContext, "err", err)
-	return "", nil, errDoSignFailed
+	return keyID, rsvs, nil
 }
 
-func doSignImpl(dcrmNode *NodeInfo, signGroupIndex int64, signPubkey string, msgHash, msgContext []string) (keyID string, rsvs []string, err error) {
+func doSignImpl(dcrmNode *NodeInfo, signGroup, threshold, signPubkey string, msgHash, msgContext []string) (keyID string, rsvs []string, err error) {
+	if err = checkInitiatorMismatched(dcrmNode); err != nil {
+		return "", nil, err
+	}
 	nonce, err := GetSignNonce(dcrmNode.dcrmUser.String(), dcrmNode.dcrmRPCAddress)
 	if err != nil {
 		return "", nil, err
@@ -97,8 +161,8 @@ func doSignImpl(dcrmNode *NodeInfo, signGroupIndex int64, signPubkey string, msg
 		MsgHash:    msgHash,
 		MsgContext: msgContext,
 		Keytype:    dcrmSignType,
-		GroupID:    dcrmNode.signGroups[signGroupIndex],
-		ThresHold:  dcrmThreshold,
+		GroupID:    signGroup,
+		ThresHold:  threshold,
 		Mode:       dcrmMode,
 		TimeStamp:  common.NowMilliStr(),
 	}
@@ -127,8 +191,23 @@ func doSignImpl(dcrmNode *NodeInfo, signGroupIndex int64, signPubkey string, msg
 	if err != nil {
 		return "", nil, err
 	}
+	if err = checkInitiatorAccount(dcrmNode, keyID); err != nil {
+		return "", nil, err
+	}
 
-	rsvs, err = getSignResult(keyID, rpcAddr)
+	attempt := &SignAttempt{
+		KeyID:       keyID,
+		Initiator:   dcrmNode.dcrmUser.String(),
+		InitiatedAt: time.Now().Unix(),
+		DcrmStatus:  pendingSignStatus,
+	}
+	notifySignAttempt(msgContext, attempt)
+
+	rsvs, signStatus, err := getSignResultWithStatus(keyID, rpcAddr)
+	attempt.FinishedAt = time.Now().Unix()
+	attempt.DcrmStatus = signStatus
+	attempt.RsvReceived = len(rsvs) > 0
+	notifySignAttempt(msgContext, attempt)
 	if err != nil {
 		return "", nil, err
 	}
@@ -156,6 +235,11 @@ func GetSignStatusByKeyID(keyID string) (rsvs []string, err error) {
 }
 
 func getSignResult(keyID, rpcAddr string) (rsvs []string, err error) {
+	rsvs, _, err = getSignResultWithStatus(keyID, rpcAddr)
+	return rsvs, err
+}
+
+func getSignResultWithStatus(keyID, rpcAddr string) (rsvs []string, status string, err error) {
 	log.Info("start get sign status", "keyID", keyID)
 	var signStatus *SignStatus
 	i := 0
@@ -188,10 +272,13 @@ LOOP_GET_SIGN_STATUS:
 	}
 	if len(rsvs) == 0 || err != nil {
 		log.Info("get sign status failed", "keyID", keyID, "retryCount", i, "err", err)
-		return nil, errGetSignResultFailed
+		if err == nil {
+			err = errGetSignResultFailed
+		}
+		return nil, getSignStatusString(err), errGetSignResultFailed
 	}
 	log.Info("get sign status success", "keyID", keyID, "retryCount", i)
-	return rsvs, nil
+	return rsvs, successStatus, nil
 }
 
 // BuildDcrmRawTx build dcrm raw tx
@@ -222,13 +309,32 @@ func BuildDcrmRawTx(nonce uint64, payload []byte, keyWrapper *keystore.Key) (str
 
 // HasValidSignature has valid signature
 func (s *SignInfoData) HasValidSignature() bool {
-	msgContextLen := len(s.MsgContext)
 	if !verifySignatureInAccept {
-		return msgContextLen == 1
+		return len(s.MsgContext) == 1
+	}
+	signer, err := s.recoverSigner()
+	return err == nil && signer == common.HexToAddress(s.Account)
+}
+
+// GetInitiator get initiator account of sign info, which is recovered from
+// the payload signature in msg context if signatures are verified in accept,
+// otherwise it is the account reported by dcrm.
+func (s *SignInfoData) GetInitiator() (string, error) {
+	if !verifySignatureInAccept {
+		return s.Account, nil
 	}
+	signer, err := s.recoverSigner()
+	if err != nil {
+		return "", err
+	}
+	return signer.String(), nil
+}
 
+// recoverSigner recover signer of payload signature appended into msg context
+func (s *SignInfoData) recoverSigner() (common.Address, error) {
+	msgContextLen := len(s.MsgContext)
 	if msgContextLen != 2 {
-		return false
+		return common.Address{}, errWrongSignatureContext
 	}
 	msgContext := s.MsgContext[:msgContextLen-1]
 	msgSig := common.FromHex(s.MsgContext[msgContextLen-1])
@@ -250,12 +356,12 @@ func (s *SignInfoData) HasValidSignature() bool {
 	// recover the public key from the signature
 	pub, err := crypto.Ecrecover(sighash[:], msgSig)
 	if err != nil {
-		return false
+		return common.Address{}, err
 	}
 	if len(pub) == 0 || pub[0] != 4 {
-		return false
+		return common.Address{}, errWrongSignatureContext
 	}
 	var addr common.Address
 	copy(addr[:], crypto.Keccak256(pub[1:])[12:])
-	return addr == common.HexToAddress(s.Account)
+	return addr, nil
 }
@@ -58,6 +58,26 @@ func (s *SignStatus) HasDisagree() bool {
 	return false
 }
 
+// CountAgree count agree replies
+func (s *SignStatus) CountAgree() (count int) {
+	for _, reply := range s.AllReply {
+		if strings.EqualFold(reply.Status, "Agree") {
+			count++
+		}
+	}
+	return count
+}
+
+// IsFinished is sign finished (success, failure or timeout)
+func (s *SignStatus) IsFinished() bool {
+	switch s.Status {
+	case "Success", "Failure", "Timeout":
+		return true
+	default:
+		return false
+	}
+}
+
 // SignInfoData sign info
 type SignInfoData struct {
 	Account    string
@@ -147,3 +167,13 @@ type GetGroupByIDResp struct {
 	Error  string
 	Data   *GroupInfo
 }
+
+// SignAttempt dcrm sign attempt (session) info
+type SignAttempt struct {
+	KeyID       string
+	Initiator   string // dcrm account of initiator node
+	InitiatedAt int64
+	FinishedAt  int64
+	DcrmStatus  string
+	RsvReceived bool
+}
@@ -0,0 +1,29 @@
+package apiversion
+
+import (
+	"net/http"
+
+	"github.com/gorilla/rpc/v2"
+)
+
+// NewCodec wrap json rpc codec to render replies in the requested api version,
+// requires the response writer passed through Middleware.
+func NewCodec(codec rpc.Codec) rpc.Codec {
+	return &versionedCodec{codec: codec}
+}
+
+type versionedCodec struct {
+	codec rpc.Codec
+}
+
+func (c *versionedCodec) NewRequest(r *http.Request) rpc.CodecRequest {
+	return &versionedCodecRequest{CodecRequest: c.codec.NewRequest(r)}
+}
+
+type versionedCodecRequest struct {
+	rpc.CodecRequest
+}
+
+func (c *versionedCodecRequest) WriteResponse(w http.ResponseWriter, reply interface{}) {
+	c.CodecRequest.WriteResponse(w, RenderFor(w, reply))
+}
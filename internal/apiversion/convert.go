@@ -0,0 +1,302 @@
+package apiversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/anyswap/CrossChain-Bridge/internal/swapapi"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tools/respsign"
+)
+
+// SwapInfoV2 swap info of api version 2, status is an object
+type SwapInfoV2 struct {
+	PairID        string                  `json:"pairid"`
+	TxID          string                  `json:"txid"`
+	TxTo          string                  `json:"txto"`
+	TxHeight      uint64                  `json:"txheight"`
+	From          string                  `json:"from"`
+	To            string                  `json:"to"`
+	Bind          string                  `json:"bind"`
+	Value         string                  `json:"value"`
+	SwapTx        string                  `json:"swaptx"`
+	SwapHeight    uint64                  `json:"swapheight"`
+	SwapValue     string                  `json:"swapvalue"`
+	SwapType      uint32                  `json:"swaptype"`
+	SwapNonce     uint64                  `json:"swapnonce"`
+	Status        *swapapi.SwapStatusInfo `json:"status"`
+	InitTime      int64                   `json:"inittime"`
+	Timestamp     int64                   `json:"timestamp"`
+	Memo          string                  `json:"memo"`
+	ReplaceCount  int                     `json:"replaceCount"`
+	Confirmations uint64                  `json:"confirmations"`
+
+	RefundTx       string `json:"refundtx,omitempty"`
+	MatchedAddress string `json:"matchedAddress,omitempty"`
+	Direction      string `json:"direction,omitempty"`
+
+	TxConfirmations       *int64 `json:"txConfirmations,omitempty"`
+	RequiredConfirmations uint64 `json:"requiredConfirmations,omitempty"`
+
+	Proof *respsign.Proof `json:"proof,omitempty"` // signed replies are not converted, see isSigned
+}
+
+// SwapInfoToV2 convert swap info from version 1 to 2
+func SwapInfoToV2(info *swapapi.SwapInfo) *SwapInfoV2 {
+	status := info.Status.Info()
+	if status == nil {
+		status = &swapapi.SwapStatusInfo{Code: info.Status, Name: info.Status.String()}
+	}
+	return &SwapInfoV2{
+		PairID:         info.PairID,
+		TxID:           info.TxID,
+		TxTo:           info.TxTo,
+		TxHeight:       info.TxHeight,
+		From:           info.From,
+		To:             info.To,
+		Bind:           info.Bind,
+		Value:          info.Value,
+		SwapTx:         info.SwapTx,
+		SwapHeight:     info.SwapHeight,
+		SwapValue:      info.SwapValue,
+		SwapType:       info.SwapType,
+		SwapNonce:      info.SwapNonce,
+		Status:         status,
+		InitTime:       info.InitTime,
+		Timestamp:      info.Timestamp,
+		Memo:           info.Memo,
+		ReplaceCount:   info.ReplaceCount,
+		Confirmations:  info.Confirmations,
+		RefundTx:       info.RefundTx,
+		MatchedAddress: info.MatchedAddress,
+		Direction:      info.Direction,
+
+		TxConfirmations:       info.TxConfirmations,
+		RequiredConfirmations: info.RequiredConfirmations,
+
+		Proof: info.Proof,
+	}
+}
+
+// SwapInfoFromV2 convert swap info from version 2 to 1
+func SwapInfoFromV2(info *SwapInfoV2) *swapapi.SwapInfo {
+	var status swapapi.SwapStatus
+	if info.Status != nil {
+		status = info.Status.Code
+	}
+	return &swapapi.SwapInfo{
+		PairID:         info.PairID,
+		TxID:           info.TxID,
+		TxTo:           info.TxTo,
+		TxHeight:       info.TxHeight,
+		From:           info.From,
+		To:             info.To,
+		Bind:           info.Bind,
+		Value:          info.Value,
+		SwapTx:         info.SwapTx,
+		SwapHeight:     info.SwapHeight,
+		SwapValue:      info.SwapValue,
+		SwapType:       info.SwapType,
+		SwapNonce:      info.SwapNonce,
+		Status:         status,
+		StatusMsg:      status.String(),
+		StatusInfo:     status.Info(),
+		InitTime:       info.InitTime,
+		Timestamp:      info.Timestamp,
+		Memo:           info.Memo,
+		ReplaceCount:   info.ReplaceCount,
+		Confirmations:  info.Confirmations,
+		RefundTx:       info.RefundTx,
+		MatchedAddress: info.MatchedAddress,
+		Direction:      info.Direction,
+
+		TxConfirmations:       info.TxConfirmations,
+		RequiredConfirmations: info.RequiredConfirmations,
+
+		Proof: info.Proof,
+	}
+}
+
+func swapInfosToV2(infos []*swapapi.SwapInfo) []*SwapInfoV2 {
+	result := make([]*SwapInfoV2, len(infos))
+	for i, info := range infos {
+		result[i] = SwapInfoToV2(info)
+	}
+	return result
+}
+
+// token config amounts which are rendered as decimal strings since version 2
+var tokenAmountFields = []string{
+	"MaximumSwap",
+	"MinimumSwap",
+	"BigValueThreshold",
+	"SwapFeeRate",
+	"MaximumSwapFee",
+	"MinimumSwapFee",
+	"RefundFee",
+	"TokenPrice",
+	"BigValueThresholdUSD",
+}
+
+var tokenConfigFields = []string{"SrcToken", "DestToken"}
+
+// TokenPairToV2 convert token pair config from version 1 to 2
+func TokenPairToV2(pair *tokens.TokenPairConfig) (map[string]interface{}, error) {
+	data, err := json.Marshal(pair)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var result map[string]interface{}
+	if err = decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+	convertTokenAmounts(result, func(amount interface{}) interface{} {
+		if num, ok := amount.(json.Number); ok {
+			return num.String()
+		}
+		return amount
+	})
+	return result, nil
+}
+
+// TokenPairFromV2 convert token pair config from version 2 to 1
+func TokenPairFromV2(pair map[string]interface{}) (*tokens.TokenPairConfig, error) {
+	converted := make(map[string]interface{}, len(pair))
+	for k, v := range pair {
+		converted[k] = v
+	}
+	convertTokenAmounts(converted, func(amount interface{}) interface{} {
+		if str, ok := amount.(string); ok {
+			return json.Number(str)
+		}
+		return amount
+	})
+	data, err := json.Marshal(converted)
+	if err != nil {
+		return nil, err
+	}
+	var result tokens.TokenPairConfig
+	if err = json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// convertTokenAmounts convert amounts of token configs in pair, token configs are copied
+func convertTokenAmounts(pair map[string]interface{}, convert func(interface{}) interface{}) {
+	for _, tokenField := range tokenConfigFields {
+		token, ok := pair[tokenField].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		converted := make(map[string]interface{}, len(token))
+		for k, v := range token {
+			converted[k] = v
+		}
+		for _, field := range tokenAmountFields {
+			if amount, exist := converted[field]; exist && amount != nil {
+				converted[field] = convert(amount)
+			}
+		}
+		pair[tokenField] = converted
+	}
+}
+
+func tokenPairsToV2(pairs map[string]*tokens.TokenPairConfig) (map[string]map[string]interface{}, error) {
+	result := make(map[string]map[string]interface{}, len(pairs))
+	for pairID, pair := range pairs {
+		if pair == nil {
+			result[pairID] = nil
+			continue
+		}
+		converted, err := TokenPairToV2(pair)
+		if err != nil {
+			return nil, err
+		}
+		result[pairID] = converted
+	}
+	return result, nil
+}
+
+// Render render reply in the shape of version, the oldest version is used if version is 0
+func Render(version int, reply interface{}) interface{} {
+	if version == 0 {
+		version = OldestVersion
+	}
+	if info, ok := reply.(*swapapi.ServerInfo); ok && info != nil {
+		result := *info
+		result.APIVersions = SupportedVersions
+		return &result
+	}
+	if version < V2 || isSigned(reply) {
+		return reply
+	}
+	var result interface{}
+	var err error
+	switch r := reply.(type) {
+	case *swapapi.SwapInfo:
+		if r != nil {
+			result = SwapInfoToV2(r)
+		}
+	case *[]*swapapi.SwapInfo:
+		if r != nil {
+			result = swapInfosToV2(*r)
+		}
+	case []*swapapi.SwapInfo:
+		result = swapInfosToV2(r)
+	case *tokens.TokenPairConfig:
+		if r != nil {
+			result, err = TokenPairToV2(r)
+		}
+	case *map[string]*tokens.TokenPairConfig:
+		if r != nil {
+			result, err = tokenPairsToV2(*r)
+		}
+	case map[string]*tokens.TokenPairConfig:
+		result, err = tokenPairsToV2(r)
+	}
+	if err != nil {
+		log.Warn("render api version failed", "version", version, "err", err)
+		return reply
+	}
+	if result == nil {
+		return reply
+	}
+	return result
+}
+
+// RenderFor render reply in the api version requested of response writer,
+// replies of unversioned requests which are json objects have a 'deprecation' field.
+func RenderFor(w http.ResponseWriter, reply interface{}) interface{} {
+	version := VersionOf(w)
+	rendered := Render(version, reply)
+	if version != 0 || isSigned(reply) {
+		return rendered
+	}
+	return withDeprecationNotice(rendered)
+}
+
+// isSigned signed replies are returned as they are signed (in version 1 shape
+// and without deprecation notice), otherwise their proof can not be verified.
+func isSigned(reply interface{}) bool {
+	info, ok := reply.(*swapapi.SwapInfo)
+	return ok && info != nil && info.Proof != nil
+}
+
+func withDeprecationNotice(reply interface{}) interface{} {
+	data, err := json.Marshal(reply)
+	if err != nil || len(data) == 0 || data[0] != '{' {
+		return reply
+	}
+	var result map[string]json.RawMessage
+	if err = json.Unmarshal(data, &result); err != nil {
+		return reply
+	}
+	notice, _ := json.Marshal(DeprecationNotice)
+	result["deprecation"] = notice
+	return result
+}
@@ -0,0 +1,143 @@
+package apiversion
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/internal/swapapi"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+	"github.com/anyswap/CrossChain-Bridge/tools/respsign"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]int{"": 0, "1": V1, "v2": V2, " V2 ": V2}
+	for s, want := range cases {
+		if have, err := Parse(s); err != nil || have != want {
+			t.Errorf("Parse(%q) want %v, have %v, err %v", s, want, have, err)
+		}
+	}
+	for _, s := range []string{"0", "3", "latest"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) should fail", s)
+		}
+	}
+}
+
+func TestSwapInfoRoundTrip(t *testing.T) {
+	status := mongodb.MatchTxStable
+	v1 := &swapapi.SwapInfo{
+		PairID:     "eth",
+		TxID:       "0x1234",
+		Bind:       "0xabcd",
+		Value:      "1000000",
+		SwapValue:  "999000",
+		Status:     status,
+		StatusMsg:  status.String(),
+		StatusInfo: status.Info(),
+		Timestamp:  1600000000,
+		Direction:  "swapin",
+	}
+	v2 := SwapInfoToV2(v1)
+	if v2.Status == nil || v2.Status.Code != status || v2.Status.Name != "MatchTxStable" {
+		t.Fatalf("wrong version 2 status %+v", v2.Status)
+	}
+	if back := SwapInfoFromV2(v2); !reflect.DeepEqual(back, v1) {
+		t.Errorf("round trip 1->2->1 mismatch\nwant %+v\nhave %+v", v1, back)
+	}
+	if back := SwapInfoToV2(SwapInfoFromV2(v2)); !reflect.DeepEqual(back, v2) {
+		t.Errorf("round trip 2->1->2 mismatch\nwant %+v\nhave %+v", v2, back)
+	}
+}
+
+func TestTokenPairRoundTrip(t *testing.T) {
+	decimals := uint8(18)
+	maxSwap, minSwap, feeRate := 1000.5, 0.001, 0.0015
+	v1 := &tokens.TokenPairConfig{
+		PairID: "eth",
+		SrcToken: &tokens.TokenConfig{
+			Symbol:      "ETH",
+			Decimals:    &decimals,
+			MaximumSwap: &maxSwap,
+			MinimumSwap: &minSwap,
+			SwapFeeRate: &feeRate,
+		},
+		DestToken: &tokens.TokenConfig{Symbol: "anyETH", Decimals: &decimals},
+	}
+	v2, err := TokenPairToV2(v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srcToken := v2["SrcToken"].(map[string]interface{})
+	if srcToken["MaximumSwap"] != "1000.5" || srcToken["MinimumSwap"] != "0.001" || srcToken["SwapFeeRate"] != "0.0015" {
+		t.Errorf("version 2 amounts should be decimal strings, have %v", srcToken)
+	}
+	back, err := TokenPairFromV2(v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, v1) {
+		t.Errorf("round trip 1->2->1 mismatch\nwant %+v\nhave %+v", v1, back)
+	}
+	again, err := TokenPairToV2(back)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, v2) {
+		t.Errorf("round trip 2->1->2 mismatch\nwant %v\nhave %v", v2, again)
+	}
+}
+
+func TestRenderForUnversioned(t *testing.T) {
+	info := &swapapi.SwapInfo{PairID: "eth", Status: mongodb.TxNotStable}
+	rendered := RenderFor(httptest.NewRecorder(), info)
+	data, err := json.Marshal(rendered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err = json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["deprecation"] != DeprecationNotice {
+		t.Errorf("unversioned reply should have deprecation notice, have %v", m["deprecation"])
+	}
+	if _, ok := m["status"].(float64); !ok {
+		t.Errorf("unversioned reply should be in the oldest version shape, have status %v", m["status"])
+	}
+
+	w := &ResponseWriter{ResponseWriter: httptest.NewRecorder(), Version: V2}
+	if _, ok := RenderFor(w, info).(*SwapInfoV2); !ok {
+		t.Errorf("version 2 reply should be rendered as SwapInfoV2")
+	}
+}
+
+func TestRenderSignedReply(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.PubkeyToAddress(key.PublicKey).String()
+	info := &swapapi.SwapInfo{PairID: "eth", TxID: "0x1234", Value: "1000", Status: mongodb.MatchTxStable}
+	if info.Proof, err = respsign.Sign(info, "test-bridge", 1600000000, key); err != nil {
+		t.Fatal(err)
+	}
+	writers := map[string]http.ResponseWriter{
+		"unversioned": httptest.NewRecorder(),
+		"version 1":   &ResponseWriter{ResponseWriter: httptest.NewRecorder(), Version: V1},
+		"version 2":   &ResponseWriter{ResponseWriter: httptest.NewRecorder(), Version: V2},
+	}
+	for name, w := range writers {
+		data, err := json.Marshal(RenderFor(w, info))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = respsign.VerifyJSON(data, signer); err != nil {
+			t.Errorf("%v signed reply can not be verified: %v", name, err)
+		}
+	}
+}
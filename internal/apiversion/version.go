@@ -0,0 +1,87 @@
+// Package apiversion renders api responses in the shape of the requested api version.
+//
+// Clients request a version with the 'X-API-Version' header (or the 'apiversion'
+// query parameter of restful api). Unversioned requests get the oldest supported
+// version with a deprecation notice. Response shape changes are done by adding
+// a new version and its converters in this package.
+package apiversion
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// api versions
+const (
+	V1 = 1 // numeric swap status with statusmsg and statusinfo, float token amounts
+	V2 = 2 // swap status object, decimal string token amounts
+
+	OldestVersion = V1
+	LatestVersion = V2
+)
+
+// request version from
+const (
+	HeaderName = "X-API-Version"
+	QueryName  = "apiversion"
+)
+
+// DeprecationNotice notice of unversioned requests
+const DeprecationNotice = "unversioned api request is deprecated and gets the oldest api version 1, please send 'X-API-Version' header"
+
+// SupportedVersions supported api versions
+var SupportedVersions = []int{V1, V2}
+
+// Parse parse version like '2' or 'v2', returns 0 if version is empty
+func Parse(version string) (int, error) {
+	version = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "v")
+	if version == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(version)
+	if err != nil || v < OldestVersion || v > LatestVersion {
+		return 0, fmt.Errorf("unsupported api version '%v', supported versions are %v", version, SupportedVersions)
+	}
+	return v, nil
+}
+
+// FromRequest get requested api version, returns 0 if unversioned
+func FromRequest(r *http.Request) (int, error) {
+	version := r.Header.Get(HeaderName)
+	if version == "" {
+		version = r.URL.Query().Get(QueryName)
+	}
+	return Parse(version)
+}
+
+// ResponseWriter response writer carrying requested api version
+type ResponseWriter struct {
+	http.ResponseWriter
+	Version int // 0 if unversioned
+}
+
+// VersionOf get requested api version of response writer, returns 0 if unversioned
+func VersionOf(w http.ResponseWriter) int {
+	if vw, ok := w.(*ResponseWriter); ok {
+		return vw.Version
+	}
+	return 0
+}
+
+// Middleware parse requested api version and pass it through response writer
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version, err := FromRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if version == 0 {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Warning", fmt.Sprintf("299 - %q", DeprecationNotice))
+		}
+		next.ServeHTTP(&ResponseWriter{ResponseWriter: w, Version: version}, r)
+	})
+}
@@ -0,0 +1,36 @@
+package metrics
+
+// metrics of bridge
+var (
+	SwapsRegistered = NewCounterVec("bridge_swaps_registered_total",
+		"Swaps registered into database.", "pairid", "swaptype")
+
+	VerifyErrors = NewCounterVec("bridge_verify_errors_total",
+		"Swap verification errors, stage is 'register' or 'verify'.", "stage", "swaptype", "error")
+
+	DcrmAccepts = NewCounterVec("bridge_dcrm_accepts_total",
+		"Dcrm sign accepts by result 'agree' or 'disagree'.", "result")
+
+	SignDisagreements = NewCounterVec("bridge_sign_disagreements_total",
+		"Agree/disagree splits of dcrm sign group, side is 'initiator' or 'acceptor'.", "side", "check")
+
+	MongodbCommandDuration = NewHistogramVec("bridge_mongodb_command_duration_seconds",
+		"Latency of mongodb commands.", DefaultBuckets, "command", "result")
+
+	RPCCallDuration = NewHistogramVec("bridge_rpc_call_duration_seconds",
+		"Latency of chain rpc calls by gateway host, result is 'ok', 'notfound' or 'error'.", DefaultBuckets, "host", "result")
+
+	SwapEventsDropped = NewCounterVec("bridge_swap_events_dropped_total",
+		"Swap events not persisted, reason is 'queuefull' or 'writefailed'.", "reason")
+
+	RPCCoalescedCalls = NewCounterVec("bridge_rpc_coalesced_calls_total",
+		"Chain rpc calls served by an identical in-flight call instead of a request of their own, by gateway host.", "host")
+)
+
+// ResultLabel label of call result
+func ResultLabel(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+type textWriter struct {
+	w *bufio.Writer
+}
+
+func (w *textWriter) header(d *desc, metricType string) {
+	fmt.Fprintf(w.w, "# HELP %s %s\n", d.name, strings.ReplaceAll(d.help, "\n", " "))
+	fmt.Fprintf(w.w, "# TYPE %s %s\n", d.name, metricType)
+}
+
+// sample write sample line, extra label (eg. 'le' of histogram bucket) is appended if not empty
+func (w *textWriter) sample(name string, labels []string, key, extraLabel, extraValue string, value float64) {
+	_, _ = w.w.WriteString(name)
+	var pairs []string
+	if len(labels) > 0 {
+		for i, labelValue := range strings.Split(key, "\xff") {
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", labels[i], labelValueEscaper.Replace(labelValue)))
+		}
+	}
+	if extraLabel != "" {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", extraLabel, extraValue))
+	}
+	if len(pairs) > 0 {
+		_, _ = w.w.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+	_, _ = w.w.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+}
+
+// WriteText write all registered metrics in prometheus text format
+func WriteText(out io.Writer) error {
+	registryLock.Lock()
+	collectors := append([]collector(nil), registry...)
+	registryLock.Unlock()
+
+	w := &textWriter{w: bufio.NewWriter(out)}
+	for _, c := range collectors {
+		c.writeTo(w)
+	}
+	return w.w.Flush()
+}
+
+// Handler http handler of '/metrics'
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		if err := WriteText(w); err != nil {
+			log.Debug("write metrics failed", "err", err)
+		}
+	})
+}
+
+// StartServer serve '/metrics' on port, used by programs without api server
+func StartServer(port int) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	svr := &http.Server{
+		Addr:         fmt.Sprintf(":%v", port),
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		Handler:      mux,
+	}
+	log.Info("metrics service listen and serving", "port", port)
+	go func() {
+		if err := svr.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("metrics service stopped", "port", port, "err", err)
+		}
+	}()
+}
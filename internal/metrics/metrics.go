@@ -0,0 +1,189 @@
+// Package metrics provides counters and histograms exposed in prometheus text format.
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBuckets default histogram buckets of latency in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+var (
+	enabled uint32
+
+	registry     []collector
+	registryLock sync.Mutex
+)
+
+type collector interface {
+	writeTo(w *textWriter)
+}
+
+// Enable start recording metrics, recordings are ignored before enabled
+func Enable() {
+	atomic.StoreUint32(&enabled, 1)
+}
+
+// IsEnabled is metrics enabled
+func IsEnabled() bool {
+	return atomic.LoadUint32(&enabled) == 1
+}
+
+func register(c collector) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	registry = append(registry, c)
+}
+
+// desc describe metric family, values are keyed by joined label values
+type desc struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func (d *desc) key(labelValues []string) string {
+	if len(labelValues) != len(d.labels) {
+		panic("metrics: wrong count of label values of " + d.name)
+	}
+	return strings.Join(labelValues, "\xff")
+}
+
+func sortedKeys(m *sync.Map) []string {
+	var keys []string
+	m.Range(func(k, v interface{}) bool {
+		keys = append(keys, k.(string))
+		return true
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+// CounterVec counters labeled by label values
+type CounterVec struct {
+	desc
+	values sync.Map // key -> *uint64
+}
+
+// NewCounterVec new and register counter vec
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{desc: desc{name: name, help: help, labels: labels}}
+	register(c)
+	return c
+}
+
+// Inc increase counter of label values by 1
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increase counter of label values by delta
+func (c *CounterVec) Add(delta uint64, labelValues ...string) {
+	if !IsEnabled() {
+		return
+	}
+	key := c.key(labelValues)
+	v, exist := c.values.Load(key)
+	if !exist {
+		v, _ = c.values.LoadOrStore(key, new(uint64))
+	}
+	atomic.AddUint64(v.(*uint64), delta)
+}
+
+// Get get counter of label values
+func (c *CounterVec) Get(labelValues ...string) uint64 {
+	if v, exist := c.values.Load(c.key(labelValues)); exist {
+		return atomic.LoadUint64(v.(*uint64))
+	}
+	return 0
+}
+
+func (c *CounterVec) writeTo(w *textWriter) {
+	w.header(&c.desc, "counter")
+	for _, key := range sortedKeys(&c.values) {
+		v, _ := c.values.Load(key)
+		w.sample(c.name, c.labels, key, "", "", float64(atomic.LoadUint64(v.(*uint64))))
+	}
+}
+
+type histogram struct {
+	lock   sync.Mutex
+	counts []uint64 // count of observations <= bucket bound
+	count  uint64
+	sum    float64
+}
+
+// HistogramVec histograms labeled by label values
+type HistogramVec struct {
+	desc
+	buckets []float64
+	values  sync.Map // key -> *histogram
+}
+
+// NewHistogramVec new and register histogram vec, buckets are upper bounds in increasing order
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{desc: desc{name: name, help: help, labels: labels}, buckets: buckets}
+	register(h)
+	return h
+}
+
+// Observe add observation of label values
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	if !IsEnabled() {
+		return
+	}
+	key := h.key(labelValues)
+	v, exist := h.values.Load(key)
+	if !exist {
+		v, _ = h.values.LoadOrStore(key, &histogram{counts: make([]uint64, len(h.buckets))})
+	}
+	hist := v.(*histogram)
+	hist.lock.Lock()
+	defer hist.lock.Unlock()
+	for i, bound := range h.buckets {
+		if value <= bound {
+			hist.counts[i]++
+		}
+	}
+	hist.count++
+	hist.sum += value
+}
+
+// ObserveSince add elapsed seconds since start as observation of label values
+func (h *HistogramVec) ObserveSince(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+// GetCount get count of observations of label values
+func (h *HistogramVec) GetCount(labelValues ...string) uint64 {
+	if v, exist := h.values.Load(h.key(labelValues)); exist {
+		hist := v.(*histogram)
+		hist.lock.Lock()
+		defer hist.lock.Unlock()
+		return hist.count
+	}
+	return 0
+}
+
+func (h *HistogramVec) writeTo(w *textWriter) {
+	w.header(&h.desc, "histogram")
+	for _, key := range sortedKeys(&h.values) {
+		v, _ := h.values.Load(key)
+		hist := v.(*histogram)
+		hist.lock.Lock()
+		counts := append([]uint64(nil), hist.counts...)
+		count, sum := hist.count, hist.sum
+		hist.lock.Unlock()
+
+		for i, bound := range h.buckets {
+			w.sample(h.name+"_bucket", h.labels, key, "le", formatFloat(bound), float64(counts[i]))
+		}
+		w.sample(h.name+"_bucket", h.labels, key, "le", "+Inf", float64(count))
+		w.sample(h.name+"_sum", h.labels, key, "", "", sum)
+		w.sample(h.name+"_count", h.labels, key, "", "", float64(count))
+	}
+}
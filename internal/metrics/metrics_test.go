@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	counter := NewCounterVec("test_counter_total", "Test counter.", "pairid")
+	histogram := NewHistogramVec("test_histogram_seconds", "Test histogram.", []float64{0.1, 1}, "host")
+
+	counter.Inc("eth")
+	if have := counter.Get("eth"); have != 0 {
+		t.Fatalf("want not recorded before enabled, have %v", have)
+	}
+
+	Enable()
+	counter.Inc("eth")
+	counter.Add(2, "eth")
+	counter.Inc(`a"b`)
+	histogram.Observe(0.0625, "node1")
+	histogram.Observe(0.5, "node1")
+	histogram.Observe(4, "node1")
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	output := buf.String()
+	for _, want := range []string{
+		"# TYPE test_counter_total counter\n",
+		`test_counter_total{pairid="a\"b"} 1` + "\n",
+		`test_counter_total{pairid="eth"} 3` + "\n",
+		"# TYPE test_histogram_seconds histogram\n",
+		`test_histogram_seconds_bucket{host="node1",le="0.1"} 1` + "\n",
+		`test_histogram_seconds_bucket{host="node1",le="1"} 2` + "\n",
+		`test_histogram_seconds_bucket{host="node1",le="+Inf"} 3` + "\n",
+		`test_histogram_seconds_sum{host="node1"} 4.5625` + "\n",
+		`test_histogram_seconds_count{host="node1"} 3` + "\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("want %q in output:\n%v", want, output)
+		}
+	}
+	if have := histogram.GetCount("node1"); have != 3 {
+		t.Errorf("want 3 observations, have %v", have)
+	}
+}
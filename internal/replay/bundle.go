@@ -0,0 +1,169 @@
+// Package replay captures chain data used in verifying a swap into a bundle,
+// and replays the verification against the bundled data offline.
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/bridge"
+)
+
+// DefaultMaxBundleSize default max bytes of bundle
+const DefaultMaxBundleSize = 4 * 1024 * 1024
+
+var (
+	errBundleTooLarge   = errors.New("replay bundle is too large")
+	errBundleExist      = errors.New("replay bundle already exist")
+	errNotRecordable    = errors.New("deposit chain is not json rpc chain, can not record")
+	errIncompleteBundle = errors.New("incomplete replay bundle")
+
+	unsafeFileNameChars = regexp.MustCompile(`[^0-9a-zA-Z_-]`)
+)
+
+// Bundle chain data and config snapshot used in verifying a swap
+type Bundle struct {
+	KeyID          string                  `json:"keyID"`
+	Initiator      string                  `json:"initiator,omitempty"` // dcrm account of sign initiator
+	CaptureTime    int64                   `json:"captureTime"`
+	Args           *tokens.BuildTxArgs     `json:"args"`
+	DisagreeReason string                  `json:"disagreeReason"`
+	CaptureResult  string                  `json:"captureResult"` // result of re-verifying in capturing
+	Config         *params.BridgeConfig    `json:"config"`        // without gateways, dcrm and server/oracle config
+	PairConfig     *tokens.TokenPairConfig `json:"pairConfig"`
+	Calls          []*RecordedCall         `json:"calls"`
+	Truncated      bool                    `json:"truncated,omitempty"`
+}
+
+// Capture re-run VerifyTransaction while recording json rpc calls to gateways
+// of the deposit chain (source chain of swapin, dest chain of swapout) into a bundle.
+// verification is run by a separate bridge instance using a local recording gateway,
+// so that calls made by other jobs at the same time are not recorded.
+// gateway urls (may contain credentials) are never stored in bundle.
+func Capture(keyID string, args *tokens.BuildTxArgs, disagreeReason string, maxSize int) (*Bundle, error) {
+	isSwapin := args.IsSwapin()
+	depositBridge := tokens.GetCrossChainBridge(isSwapin)
+	if _, ok := depositBridge.(chainSigner); !ok {
+		return nil, errNotRecordable
+	}
+	pairCfg := tokens.GetTokenPairConfig(args.PairID)
+	if pairCfg == nil {
+		return nil, tokens.ErrUnknownPairID
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxBundleSize
+	}
+
+	chainCfg := *depositBridge.GetChainConfig()
+	gateway := depositBridge.GetGatewayConfig()
+	apiAddress := gateway.GetAPIAddress()
+	apiAddressExt := gateway.GetAPIAddressExt()
+	urls := make([]string, 0, len(apiAddress)+len(apiAddressExt)+len(chainCfg.CheckpointAPIAddress))
+	urls = append(urls, apiAddress...)
+	urls = append(urls, apiAddressExt...)
+	urls = append(urls, chainCfg.CheckpointAPIAddress...)
+
+	recorder, err := startRecorder(urls, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	localURLs := recorder.localURLs()
+	extStart := len(apiAddress)
+	checkpointStart := extStart + len(apiAddressExt)
+	localGateway := &tokens.GatewayConfig{
+		APIAddress:    localURLs[:extStart],
+		APIAddressExt: localURLs[extStart:checkpointStart],
+	}
+	chainCfg.CheckpointAPIAddress = localURLs[checkpointStart:]
+
+	verifyBridge := bridge.NewCrossChainBridge(chainCfg.BlockChain, depositBridge.IsSrcEndpoint())
+	setter, ok := verifyBridge.(configSetter)
+	if !ok {
+		_, _ = recorder.stop()
+		return nil, errCanNotSetConfig
+	}
+	setter.SetChainAndGatewayConfig(&chainCfg, localGateway)
+	// calls used in initializing bridge when replaying
+	signer := verifyBridge.(chainSigner)
+	chainID, err := signer.GetSignerChainID()
+	if err != nil {
+		_, _ = recorder.stop()
+		return nil, err
+	}
+	signer.MakeSigner(chainID)
+	_, _ = verifyBridge.GetLatestBlockNumber()
+	_, err = verifyBridge.VerifyTransaction(args.PairID, args.SwapID, false)
+	calls, truncated := recorder.stop()
+
+	captureResult := "success"
+	if err != nil {
+		captureResult = err.Error()
+	}
+	cfg := params.GetConfig()
+	return &Bundle{
+		KeyID:          keyID,
+		CaptureTime:    time.Now().Unix(),
+		Args:           args,
+		DisagreeReason: recorder.redact(disagreeReason),
+		CaptureResult:  recorder.redact(captureResult),
+		Config: &params.BridgeConfig{
+			Identifier: cfg.Identifier,
+			SrcChain:   cfg.SrcChain,
+			DestChain:  cfg.DestChain,
+			Extra:      cfg.Extra,
+		},
+		PairConfig: pairCfg,
+		Calls:      calls,
+		Truncated:  truncated,
+	}, nil
+}
+
+// Save save bundle to file `<keyID>.json` in directory
+func (b *Bundle) Save(dir string, maxSize int) (string, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxBundleSize
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxSize {
+		return "", fmt.Errorf("%w: %v > %v", errBundleTooLarge, len(data), maxSize)
+	}
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	fileName := unsafeFileNameChars.ReplaceAllString(b.KeyID, "_") + ".json"
+	file := filepath.Join(dir, fileName)
+	if common.FileExist(file) {
+		return file, errBundleExist
+	}
+	return file, ioutil.WriteFile(file, data, 0600)
+}
+
+// LoadBundle load bundle from file
+func LoadBundle(file string) (*Bundle, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var bundle Bundle
+	if err = json.Unmarshal(data, &bundle); err != nil {
+		return nil, err
+	}
+	if bundle.Args == nil || bundle.Config == nil || bundle.PairConfig == nil ||
+		bundle.Config.SrcChain == nil || bundle.Config.DestChain == nil ||
+		bundle.PairConfig.SrcToken == nil || bundle.PairConfig.DestToken == nil {
+		return nil, errIncompleteBundle
+	}
+	return &bundle, nil
+}
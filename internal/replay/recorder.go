@@ -0,0 +1,149 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+)
+
+const (
+	redactedURL    = "<redacted-url>"
+	forwardTimeout = 60 // seconds, callers timeout earlier by their own
+)
+
+// RecordedCall json rpc call recorded for replaying (url is not recorded)
+type RecordedCall struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// recorder is a local gateway forwarding json rpc calls to the upstream urls
+// and recording them up to a size limit. request path '/<index>' is forwarded
+// to upstream url of the index, so only the calls made through the local urls
+// (eg. by a bridge instance configured with them) are recorded.
+type recorder struct {
+	upstreams []string
+	maxSize   int
+	listener  net.Listener
+
+	lock      sync.Mutex
+	calls     []*RecordedCall
+	size      int
+	truncated bool
+}
+
+// startRecorder start a local recording gateway of the upstream urls
+func startRecorder(upstreams []string, maxSize int) (*recorder, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	r := &recorder{
+		upstreams: upstreams,
+		maxSize:   maxSize,
+		listener:  listener,
+	}
+	go func() {
+		_ = http.Serve(listener, r)
+	}()
+	return r, nil
+}
+
+// localURLs local urls of the upstream urls in same order
+func (r *recorder) localURLs() []string {
+	urls := make([]string, len(r.upstreams))
+	for i := range r.upstreams {
+		urls[i] = "http://" + r.listener.Addr().String() + "/" + strconv.Itoa(i)
+	}
+	return urls
+}
+
+// stop stop recording and return recorded calls and whether it's truncated
+func (r *recorder) stop() (calls []*RecordedCall, truncated bool) {
+	_ = r.listener.Close()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.calls, r.truncated
+}
+
+// redact replace upstream urls in message (may contain credentials)
+func (r *recorder) redact(msg string) string {
+	for _, url := range r.upstreams {
+		if url != "" {
+			msg = strings.ReplaceAll(msg, url, redactedURL)
+		}
+	}
+	return msg
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	index, err := strconv.Atoi(strings.TrimPrefix(req.URL.Path, "/"))
+	if err != nil || index < 0 || index >= len(r.upstreams) {
+		http.NotFound(w, req)
+		return
+	}
+	var stubReq stubRequest
+	if err = json.NewDecoder(req.Body).Decode(&stubReq); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := r.forward(req.Context(), r.upstreams[index], &stubReq)
+
+	resp := &stubResponse{Version: "2.0", ID: stubReq.ID}
+	if err != nil {
+		resp.Error = &stubError{Code: -32000, Message: r.redact(err.Error())}
+	} else {
+		resp.Result = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (r *recorder) forward(ctx context.Context, url string, stubReq *stubRequest) (result json.RawMessage, err error) {
+	params := stubReq.Params
+	if len(params) == 0 {
+		params = json.RawMessage("[]")
+	}
+	req := &client.Request{
+		Method:  stubReq.Method,
+		Params:  params,
+		Timeout: forwardTimeout,
+		ID:      1,
+	}
+	err = client.RPCPostRequestWithContext(ctx, url, req, &result)
+	if err == nil && result == nil {
+		result = json.RawMessage("null")
+	}
+	r.record(stubReq, result, err)
+	return result, err
+}
+
+func (r *recorder) record(req *stubRequest, result json.RawMessage, err error) {
+	call := &RecordedCall{
+		Method: req.Method,
+		Params: req.Params,
+	}
+	if err != nil {
+		call.Error = r.redact(err.Error())
+	} else {
+		call.Result = result
+	}
+	callSize := len(call.Method) + len(call.Params) + len(call.Result) + len(call.Error)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.maxSize > 0 && r.size+callSize > r.maxSize {
+		r.truncated = true
+		return
+	}
+	r.size += callSize
+	r.calls = append(r.calls, call)
+}
@@ -0,0 +1,186 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/bridge"
+)
+
+const maxTraceResultLength = 200
+
+var errCanNotSetConfig = errors.New("bridge does not support setting config offline")
+
+// Result replay result
+type Result struct {
+	SwapInfo *tokens.TxSwapInfo
+	Err      error
+	Trace    []string // served rpc calls in order
+}
+
+type configSetter interface {
+	SetChainAndGatewayConfig(*tokens.ChainConfig, *tokens.GatewayConfig)
+}
+
+type chainSigner interface {
+	GetSignerChainID() (*big.Int, error)
+	MakeSigner(chainID *big.Int)
+}
+
+// Replay run VerifyTransaction of the bundled swap against the recorded rpc calls,
+// bridges are initialized from the config snapshot with a local stub gateway.
+func Replay(bundle *Bundle) (*Result, error) {
+	result := &Result{}
+	stub := newStubServer(bundle.Calls, func(msg string) {
+		result.Trace = append(result.Trace, msg)
+	})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	defer listener.Close()
+	go func() {
+		_ = http.Serve(listener, stub)
+	}()
+	stubURL := "http://" + listener.Addr().String()
+	gateway := &tokens.GatewayConfig{APIAddress: []string{stubURL}}
+
+	cfg := bundle.Config
+	for _, chainCfg := range []*tokens.ChainConfig{cfg.SrcChain, cfg.DestChain} {
+		if chainCfg.GetFinality() == tokens.FinalityCheckpoint {
+			chainCfg.CheckpointAPIAddress = []string{stubURL}
+		}
+		if err = chainCfg.CheckConfig(false); err != nil {
+			return nil, err
+		}
+	}
+	params.SetConfig(cfg)
+
+	isSwapin := bundle.Args.IsSwapin()
+	tokens.SrcBridge = bridge.NewCrossChainBridge(cfg.SrcChain.BlockChain, true)
+	tokens.DstBridge = bridge.NewCrossChainBridge(cfg.DestChain.BlockChain, false)
+	tokens.SrcStableConfirmations = *cfg.SrcChain.Confirmations
+	tokens.DstStableConfirmations = *cfg.DestChain.Confirmations
+
+	depositBridge := tokens.GetCrossChainBridge(isSwapin)
+	otherBridge := tokens.GetCrossChainBridge(!isSwapin)
+	setter, ok := otherBridge.(configSetter)
+	if !ok {
+		return nil, errCanNotSetConfig
+	}
+	if isSwapin {
+		setter.SetChainAndGatewayConfig(cfg.DestChain, gateway)
+		depositBridge.SetChainAndGateway(cfg.SrcChain, gateway)
+	} else {
+		setter.SetChainAndGatewayConfig(cfg.SrcChain, gateway)
+		depositBridge.SetChainAndGateway(cfg.DestChain, gateway)
+	}
+
+	pairCfg := bundle.PairConfig
+	pairCfg.SrcToken.CalcAndStoreValue()
+	pairCfg.DestToken.CalcAndStoreValue()
+	tokens.SetTokenPairsConfig(map[string]*tokens.TokenPairConfig{
+		strings.ToLower(pairCfg.PairID): pairCfg,
+	}, false)
+
+	result.SwapInfo, result.Err = depositBridge.VerifyTransaction(bundle.Args.PairID, bundle.Args.SwapID, false)
+	return result, nil
+}
+
+type stubRequest struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	ID     json.RawMessage `json:"id"`
+}
+
+type stubError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type stubResponse struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *stubError      `json:"error,omitempty"`
+}
+
+// stubServer serve json rpc requests from recorded calls,
+// calls of same method and params are served in recorded order, and the last one is repeated.
+type stubServer struct {
+	lock   sync.Mutex
+	calls  map[string][]*RecordedCall
+	served map[string]int
+	trace  func(string)
+}
+
+func newStubServer(calls []*RecordedCall, trace func(string)) *stubServer {
+	s := &stubServer{
+		calls:  make(map[string][]*RecordedCall),
+		served: make(map[string]int),
+		trace:  trace,
+	}
+	for _, call := range calls {
+		key := callKey(call.Method, call.Params)
+		s.calls[key] = append(s.calls[key], call)
+	}
+	return s
+}
+
+func callKey(method string, params json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, params); err != nil {
+		return method + string(params)
+	}
+	return method + buf.String()
+}
+
+func (s *stubServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req stubRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp := &stubResponse{Version: "2.0", ID: req.ID}
+	key := callKey(req.Method, req.Params)
+
+	s.lock.Lock()
+	recorded := s.calls[key]
+	index := s.served[key]
+	s.served[key]++
+	if len(recorded) == 0 {
+		resp.Error = &stubError{Code: -32000, Message: "call not recorded in replay bundle"}
+		s.trace(fmt.Sprintf("MISS %v %s", req.Method, req.Params))
+	} else {
+		if index >= len(recorded) {
+			index = len(recorded) - 1
+		}
+		call := recorded[index]
+		if call.Result != nil {
+			resp.Result = call.Result
+		} else {
+			resp.Error = &stubError{Code: -32000, Message: call.Error}
+		}
+		s.trace(fmt.Sprintf("HIT  %v %s #%v result=%v error=%q", req.Method, req.Params, index, abbrev(string(call.Result)), call.Error))
+	}
+	s.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func abbrev(s string) string {
+	if len(s) > maxTraceResultLength {
+		return s[:maxTraceResultLength] + "..."
+	}
+	return s
+}
@@ -0,0 +1,91 @@
+package replay
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+)
+
+func TestRecordAndServe(t *testing.T) {
+	blockNumber := "0x10"
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req stubRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch req.Method {
+		case "eth_blockNumber":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"` + blockNumber + `"}`))
+		case "eth_getTransactionByHash":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":null}`))
+		default:
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`))
+		}
+	}))
+	defer gateway.Close()
+	gatewayURL := gateway.URL + "/secret-api-key"
+
+	recorder, err := startRecorder([]string{gatewayURL}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	localURL := recorder.localURLs()[0]
+
+	// calls made by other jobs directly to the gateway at the same time are not recorded
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var other string
+		for i := 0; i < 10; i++ {
+			_ = client.RPCPost(&other, gatewayURL, "eth_getTransactionByHash", "0xabcd")
+		}
+	}()
+
+	var result string
+	_ = client.RPCPost(&result, localURL, "eth_blockNumber")
+	blockNumber = "0x11"
+	_ = client.RPCPost(&result, localURL, "eth_blockNumber")
+	_ = client.RPCPost(&result, localURL, "eth_getTransactionByHash", "0x1234")
+	_ = client.RPCPost(&result, localURL, "eth_unknown")
+	wg.Wait()
+	calls, truncated := recorder.stop()
+	if len(calls) != 4 || truncated {
+		t.Fatalf("want 4 recorded calls, have %v, truncated %v", len(calls), truncated)
+	}
+	for _, call := range calls {
+		if strings.Contains(call.Error, "secret-api-key") {
+			t.Errorf("recorded error is not redacted: %v", call.Error)
+		}
+		if strings.Contains(string(call.Params), "0xabcd") {
+			t.Errorf("unrelated call is recorded: %v %s", call.Method, call.Params)
+		}
+	}
+
+	var trace []string
+	stub := httptest.NewServer(newStubServer(calls, func(msg string) { trace = append(trace, msg) }))
+	defer stub.Close()
+
+	for _, want := range []string{"0x10", "0x11", "0x11"} {
+		err := client.RPCPost(&result, stub.URL, "eth_blockNumber")
+		if err != nil || result != want {
+			t.Errorf("replay eth_blockNumber want %v, have %v (err %v)", want, result, err)
+		}
+	}
+	var tx *json.RawMessage
+	if err := client.RPCPost(&tx, stub.URL, "eth_getTransactionByHash", "0x1234"); err != nil || tx != nil {
+		t.Errorf("replay null result failed, have %v (err %v)", tx, err)
+	}
+	if err := client.RPCPost(&result, stub.URL, "eth_unknown"); err == nil {
+		t.Errorf("replay error result should fail")
+	}
+	if err := client.RPCPost(&result, stub.URL, "eth_getTransactionByHash", "0x5678"); err == nil {
+		t.Errorf("replay not recorded call should fail")
+	}
+	if len(trace) != 6 || !strings.HasPrefix(trace[5], "MISS") {
+		t.Errorf("wrong trace %v", trace)
+	}
+}
@@ -0,0 +1,50 @@
+package retry
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Metrics retry metrics of call site
+type Metrics struct {
+	Attempts uint64 `json:"attempts"` // all calls including retries
+	Retries  uint64 `json:"retries"`
+	GaveUp   uint64 `json:"gaveUp"` // still failed after max attempts or canceled
+}
+
+var siteMetrics sync.Map // site -> *Metrics
+
+func getSiteMetrics(site string) *Metrics {
+	if m, exist := siteMetrics.Load(site); exist {
+		return m.(*Metrics)
+	}
+	m, _ := siteMetrics.LoadOrStore(site, &Metrics{})
+	return m.(*Metrics)
+}
+
+func recordAttempt(site string, isRetry bool) {
+	m := getSiteMetrics(site)
+	atomic.AddUint64(&m.Attempts, 1)
+	if isRetry {
+		atomic.AddUint64(&m.Retries, 1)
+	}
+}
+
+func recordGaveUp(site string) {
+	atomic.AddUint64(&getSiteMetrics(site).GaveUp, 1)
+}
+
+// GetMetrics get retry metrics labeled by call site
+func GetMetrics() map[string]*Metrics {
+	result := make(map[string]*Metrics)
+	siteMetrics.Range(func(k, v interface{}) bool {
+		m := v.(*Metrics)
+		result[k.(string)] = &Metrics{
+			Attempts: atomic.LoadUint64(&m.Attempts),
+			Retries:  atomic.LoadUint64(&m.Retries),
+			GaveUp:   atomic.LoadUint64(&m.GaveUp),
+		}
+		return true
+	})
+	return result
+}
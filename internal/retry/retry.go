@@ -0,0 +1,141 @@
+// Package retry provides policy configured retry helpers shared by workers and bridges.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+// subsystems which have their own retry policy
+const (
+	SubsystemDcrm    = "dcrm"
+	SubsystemRPC     = "rpc"
+	SubsystemMongoDB = "mongodb"
+)
+
+// Policy retry policy
+type Policy struct {
+	MaxAttempts  int           // total attempts including the first one
+	InitialDelay time.Duration // delay before the first retry
+	MaxDelay     time.Duration // cap of backoff delay
+	Multiplier   float64       // delay is multiplied by this after each retry
+	Jitter       float64       // randomize delay by up to this fraction, in [0, 1]
+}
+
+// Classifier return true if error is retryable
+type Classifier func(err error) bool
+
+var (
+	defaultPolicies = map[string]*Policy{
+		SubsystemDcrm:    {MaxAttempts: 3, InitialDelay: 2 * time.Second, MaxDelay: 8 * time.Second, Multiplier: 2, Jitter: 0.2},
+		SubsystemRPC:     {MaxAttempts: 1, InitialDelay: time.Second, MaxDelay: 5 * time.Second, Multiplier: 2, Jitter: 0.2},
+		SubsystemMongoDB: {MaxAttempts: 3, InitialDelay: 200 * time.Millisecond, MaxDelay: 2 * time.Second, Multiplier: 2, Jitter: 0.2},
+	}
+
+	policies     = defaultPolicies
+	policiesLock sync.RWMutex
+)
+
+// CheckPolicy check policy values
+func (p *Policy) CheckPolicy() error {
+	switch {
+	case p.MaxAttempts < 1:
+		return fmt.Errorf("retry 'MaxAttempts' %v is less than 1", p.MaxAttempts)
+	case p.InitialDelay < 0 || p.MaxDelay < 0:
+		return fmt.Errorf("retry delay is negative")
+	case p.MaxDelay != 0 && p.MaxDelay < p.InitialDelay:
+		return fmt.Errorf("retry 'MaxDelay' %v is less than 'InitialDelay' %v", p.MaxDelay, p.InitialDelay)
+	case p.Multiplier != 0 && p.Multiplier < 1:
+		return fmt.Errorf("retry 'Multiplier' %v is less than 1", p.Multiplier)
+	case p.Jitter < 0 || p.Jitter > 1:
+		return fmt.Errorf("retry 'Jitter' %v is not in range [0, 1]", p.Jitter)
+	}
+	return nil
+}
+
+// SetPolicy override retry policy of subsystem
+func SetPolicy(subsystem string, policy *Policy) error {
+	if _, exist := defaultPolicies[subsystem]; !exist {
+		return fmt.Errorf("unknown retry subsystem '%v'", subsystem)
+	}
+	if err := policy.CheckPolicy(); err != nil {
+		return fmt.Errorf("wrong retry policy of '%v': %w", subsystem, err)
+	}
+	policiesLock.Lock()
+	defer policiesLock.Unlock()
+	newPolicies := make(map[string]*Policy, len(policies))
+	for k, v := range policies {
+		newPolicies[k] = v
+	}
+	newPolicies[subsystem] = policy
+	policies = newPolicies
+	return nil
+}
+
+// GetPolicy get retry policy of subsystem
+func GetPolicy(subsystem string) *Policy {
+	policiesLock.RLock()
+	defer policiesLock.RUnlock()
+	if policy, exist := policies[subsystem]; exist {
+		return policy
+	}
+	return &Policy{MaxAttempts: 1}
+}
+
+// delay return the backoff delay before the retry-th retry (start from 1)
+func (p *Policy) delay(retry int) time.Duration {
+	delay := float64(p.InitialDelay)
+	for i := 1; i < retry && p.Multiplier > 1; i++ {
+		delay *= p.Multiplier
+		if p.MaxDelay > 0 && delay >= float64(p.MaxDelay) {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		delay *= 1 + p.Jitter*(2*rand.Float64()-1) // nolint:gosec // jitter need not be secure
+	}
+	return time.Duration(delay)
+}
+
+// Do call fn until it succeeds, returns non retryable error, exhausts
+// the policy's max attempts, or ctx is done. site labels the metrics.
+// isRetryable is optional, all errors are retryable if it is nil.
+func Do(ctx context.Context, site string, policy *Policy, isRetryable Classifier, fn func() error) (err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for attempt := 1; ; attempt++ {
+		recordAttempt(site, attempt > 1)
+		err = fn()
+		if err == nil {
+			return nil
+		}
+		if isRetryable != nil && !isRetryable(err) {
+			return err
+		}
+		if attempt >= policy.MaxAttempts {
+			recordGaveUp(site)
+			if policy.MaxAttempts > 1 {
+				log.Warn("retry gave up", "site", site, "attempts", attempt, "err", err)
+			}
+			return err
+		}
+		timer := time.NewTimer(policy.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			recordGaveUp(site)
+			return err
+		case <-timer.C:
+		}
+		log.Debug("retry call", "site", site, "attempt", attempt+1, "err", err)
+	}
+}
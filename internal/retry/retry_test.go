@@ -0,0 +1,74 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errTest = errors.New("test error")
+
+func TestPolicyDelay(t *testing.T) {
+	p := &Policy{MaxAttempts: 5, InitialDelay: time.Second, MaxDelay: 5 * time.Second, Multiplier: 2}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if have := p.delay(i + 1); have != w {
+			t.Errorf("retry %v: want delay %v, have %v", i+1, w, have)
+		}
+	}
+	p.Jitter = 0.5
+	for i := 1; i <= 5; i++ {
+		if have := p.delay(i); have < 500*time.Millisecond || have > 7500*time.Millisecond {
+			t.Errorf("retry %v: jittered delay %v out of range", i, have)
+		}
+	}
+}
+
+func TestDo(t *testing.T) {
+	policy := &Policy{MaxAttempts: 3, InitialDelay: time.Millisecond}
+
+	calls := 0
+	err := Do(context.Background(), "test.succeed", policy, nil, func() error {
+		calls++
+		if calls < 2 {
+			return errTest
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("want success after 2 calls, have %v calls, err %v", calls, err)
+	}
+
+	calls = 0
+	err = Do(context.Background(), "test.gaveup", policy, nil, func() error {
+		calls++
+		return errTest
+	})
+	if !errors.Is(err, errTest) || calls != 3 {
+		t.Errorf("want gave up after 3 calls, have %v calls, err %v", calls, err)
+	}
+	if m := GetMetrics()["test.gaveup"]; m == nil || m.Attempts != 3 || m.Retries != 2 || m.GaveUp != 1 {
+		t.Errorf("wrong metrics %+v", m)
+	}
+
+	calls = 0
+	err = Do(context.Background(), "test.notretryable", policy, func(error) bool { return false }, func() error {
+		calls++
+		return errTest
+	})
+	if !errors.Is(err, errTest) || calls != 1 {
+		t.Errorf("non retryable error should not be retried, have %v calls", calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	err = Do(ctx, "test.canceled", &Policy{MaxAttempts: 3, InitialDelay: time.Hour}, nil, func() error {
+		calls++
+		return errTest
+	})
+	if !errors.Is(err, errTest) || calls != 1 {
+		t.Errorf("canceled context should stop retrying, have %v calls", calls)
+	}
+}
@@ -0,0 +1,123 @@
+package swapapi
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const maxAddressBatchSize = 200
+
+// register results of address batch
+const (
+	AddressBatchRegistered = "registered"
+	AddressBatchDuplicate  = "duplicate"
+	AddressBatchInvalid    = "invalid"
+)
+
+var (
+	errAddressBatchEmpty     = newRPCError(-32051, "empty batch addresses")
+	errAddressBatchTooLarge  = newRPCError(-32050, "too many addresses in batch")
+	errAddressBatchDirection = newRPCError(-32049, "direction must be swapin or swapout")
+
+	// replaced in tests
+	addRegisteredAddresses = mongodb.AddRegisteredAddresses
+)
+
+// AddressBatchResult register result of each address,
+// 'registered', 'duplicate', 'invalid' or error message.
+type AddressBatchResult map[string]string
+
+// BindAddressBatchValidation validation result of each address
+type BindAddressBatchValidation map[string]*BindAddressValidation
+
+func checkAddressBatchSize(addresses []string) error {
+	if len(addresses) == 0 {
+		return errAddressBatchEmpty
+	}
+	if len(addresses) > maxAddressBatchSize {
+		return errAddressBatchTooLarge
+	}
+	return nil
+}
+
+// RegisterAddresses api, register addresses as the single address api does,
+// addresses are stored in lower case and duplicates in the batch are registered once.
+func RegisterAddresses(addresses []string) (AddressBatchResult, error) {
+	if err := checkAddressBatchSize(addresses); err != nil {
+		return nil, err
+	}
+	result := make(AddressBatchResult, len(addresses))
+	if !params.MustRegisterAccount() {
+		for _, address := range addresses {
+			result[address] = AddressBatchRegistered
+		}
+		return result, nil
+	}
+	if params.IsRegisterAddressProofRequired() {
+		return nil, errRegisterProofRequired
+	}
+
+	seen := make(map[string]struct{}, len(addresses))
+	var toAdd, toAddKeys []string
+	for _, address := range addresses {
+		key := strings.ToLower(address)
+		if _, isDup := seen[key]; isDup {
+			result[address] = AddressBatchDuplicate
+			continue
+		}
+		seen[key] = struct{}{}
+		if checkRegisterAddress(address) != nil {
+			result[address] = AddressBatchInvalid
+			continue
+		}
+		toAdd = append(toAdd, address)
+		toAddKeys = append(toAddKeys, key)
+	}
+	errs, err := addRegisteredAddresses(toAddKeys)
+	if err != nil {
+		return nil, err
+	}
+	for i, address := range toAdd {
+		switch {
+		case errs[i] == nil:
+			result[address] = AddressBatchRegistered
+		case errors.Is(errs[i], mongodb.ErrItemIsDup):
+			result[address] = AddressBatchDuplicate
+		default:
+			result[address] = errs[i].Error()
+		}
+	}
+	log.Info("[api] register addresses", "count", len(addresses), "added", len(toAdd))
+	return result, nil
+}
+
+// ValidateBindAddresses api, validate bind addresses by the bridge of payout chain
+func ValidateBindAddresses(direction string, addresses []string) (BindAddressBatchValidation, error) {
+	var isSwapin bool
+	switch direction {
+	case DirectionSwapin:
+		isSwapin = true
+	case DirectionSwapout:
+	default:
+		return nil, errAddressBatchDirection
+	}
+	if err := checkAddressBatchSize(addresses); err != nil {
+		return nil, err
+	}
+	bridge := tokens.GetCrossChainBridge(!isSwapin)
+	result := make(BindAddressBatchValidation, len(addresses))
+	for _, address := range addresses {
+		normalized, reasons := tokens.ValidateAddress(bridge, address)
+		result[address] = &BindAddressValidation{
+			Valid:      len(reasons) == 0,
+			Normalized: normalized,
+			Reasons:    reasons,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package swapapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+func TestRegisterAddresses(t *testing.T) {
+	params.SetConfig(&params.BridgeConfig{Extra: &params.ExtraConfig{MustRegisterAccount: true}})
+	oldIsValid, oldAdd := isValidRegisterAddress, addRegisteredAddresses
+	defer func() { isValidRegisterAddress, addRegisteredAddresses = oldIsValid, oldAdd }()
+	isValidRegisterAddress = common.IsHexAddress
+
+	const (
+		address1 = "0x1111111111111111111111111111111111111111"
+		address2 = "0xABCDEFABCDEFABCDEFABCDEFABCDEFABCDEFABCD"
+		address3 = "0x2222222222222222222222222222222222222222"
+	)
+	var added []string
+	addRegisteredAddresses = func(addresses []string) ([]error, error) {
+		added = addresses
+		errs := make([]error, len(addresses))
+		errs[2] = mongodb.ErrItemIsDup // already registered
+		return errs, nil
+	}
+
+	result, err := RegisterAddresses([]string{address1, address2, "0xinvalid", strings.ToLower(address2), address3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := AddressBatchResult{
+		address1:                  AddressBatchRegistered,
+		address2:                  AddressBatchRegistered,
+		"0xinvalid":               AddressBatchInvalid,
+		strings.ToLower(address2): AddressBatchDuplicate,
+		address3:                  AddressBatchDuplicate,
+	}
+	if len(result) != len(want) {
+		t.Fatalf("wrong result %v", result)
+	}
+	for address, res := range want {
+		if result[address] != res {
+			t.Errorf("address %v want %v, have %v", address, res, result[address])
+		}
+	}
+	if len(added) != 3 || added[1] != strings.ToLower(address2) {
+		t.Errorf("want lower case addresses added once, have %v", added)
+	}
+}
+
+func TestAddressBatchSize(t *testing.T) {
+	if _, err := RegisterAddresses(nil); err != errAddressBatchEmpty {
+		t.Errorf("want %v, have %v", errAddressBatchEmpty, err)
+	}
+	if _, err := ValidateBindAddresses(DirectionSwapin, make([]string, maxAddressBatchSize+1)); err != errAddressBatchTooLarge {
+		t.Errorf("want %v, have %v", errAddressBatchTooLarge, err)
+	}
+	if _, err := ValidateBindAddresses("both", []string{"0x"}); err != errAddressBatchDirection {
+		t.Errorf("want %v, have %v", errAddressBatchDirection, err)
+	}
+}
@@ -2,26 +2,34 @@ package swapapi
 
 import (
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/anyswap/CrossChain-Bridge/common"
 	"github.com/anyswap/CrossChain-Bridge/dcrm"
+	"github.com/anyswap/CrossChain-Bridge/internal/metrics"
+	"github.com/anyswap/CrossChain-Bridge/internal/retry"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/mongodb"
 	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
-	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
-	"github.com/btcsuite/btcd/txscript"
 	rpcjson "github.com/gorilla/rpc/v2/json2"
 )
 
 var (
-	errNotBtcBridge      = newRPCError(-32096, "bridge is not btc")
+	errNoP2shBridge      = newRPCError(-32096, "no P2SH-capable bridge configured")
 	errTokenPairNotExist = newRPCError(-32095, "token pair not exist")
 	errSwapCannotRetry   = newRPCError(-32094, "swap can not retry")
+	errTooManyAddresses  = newRPCError(-32086, fmt.Sprintf("too many addresses in history query, max %v", maxHistoryAddresses))
+	errNoHistoryAddress  = newRPCError(-32072, "no address in history query")
+	errHistoryTimeRange  = newRPCError(-32065, "wrong time range in history query")
+	errNoNativePrice     = newRPCError(-32085, "native price is not configured")
 
 	oraclesHeartbeats sync.Map // string -> int64 // key is enode
+	oraclesJobStatus  sync.Map // string -> *AcceptJobStatus // key is enode
 )
 
 func newRPCError(ec rpcjson.ErrorCode, message string) error {
@@ -42,6 +50,7 @@ func GetServerInfo() (*ServerInfo, error) {
 	if config == nil {
 		return nil, nil
 	}
+	srcStatus, dstStatus := getChainsStatus()
 	return &ServerInfo{
 		Identifier:          config.Identifier,
 		MustRegisterAccount: params.MustRegisterAccount(),
@@ -49,11 +58,13 @@ func GetServerInfo() (*ServerInfo, error) {
 		DestChain:           config.DestChain,
 		PairIDs:             tokens.GetAllPairIDs(),
 		Version:             params.VersionWithMeta,
+		SrcChainStatus:      srcStatus,
+		DestChainStatus:     dstStatus,
 	}, nil
 }
 
 // UpdateOracleHeartbeat api
-func UpdateOracleHeartbeat(oracle string, timestamp int64) error {
+func UpdateOracleHeartbeat(oracle string, timestamp int64, acceptJob *AcceptJobStatus) error {
 	var exist bool
 	for _, enode := range dcrm.GetAllEnodes() {
 		if strings.EqualFold(oracle, enode) {
@@ -76,32 +87,83 @@ func UpdateOracleHeartbeat(oracle string, timestamp int64) error {
 	} else {
 		oraclesHeartbeats.Store(key, timestamp)
 	}
+	if acceptJob != nil {
+		oraclesJobStatus.Store(key, acceptJob)
+	}
 	return nil
 }
 
+func getEnodeID(enode string) string {
+	startIndex := strings.Index(enode, "enode://")
+	endIndex := strings.Index(enode, "@")
+	if startIndex != -1 && endIndex != -1 {
+		return strings.ToLower(enode[startIndex+8 : endIndex])
+	}
+	return ""
+}
+
 // GetOraclesHeartbeat api
 func GetOraclesHeartbeat() map[string]string {
 	result := make(map[string]string, 4)
 	oraclesHeartbeats.Range(func(k, v interface{}) bool {
-		enode := k.(string)
-		startIndex := strings.Index(enode, "enode://")
-		endIndex := strings.Index(enode, "@")
-		if startIndex != -1 && endIndex != -1 {
-			enodeID := enode[startIndex+8 : endIndex]
+		if enodeID := getEnodeID(k.(string)); enodeID != "" {
 			timestamp := v.(int64)
 			timeStr := time.Unix(timestamp, 0).Format(time.RFC3339)
-			result[strings.ToLower(enodeID)] = timeStr
+			result[enodeID] = timeStr
+		}
+		return true
+	})
+	return result
+}
+
+// GetOraclesJobStatus api, key is enode ID
+func GetOraclesJobStatus() map[string]*AcceptJobStatus {
+	result := make(map[string]*AcceptJobStatus, 4)
+	oraclesJobStatus.Range(func(k, v interface{}) bool {
+		if enodeID := getEnodeID(k.(string)); enodeID != "" {
+			result[enodeID] = v.(*AcceptJobStatus)
 		}
 		return true
 	})
 	return result
 }
 
+// GetRetryMetrics api, key is call site
+func GetRetryMetrics() map[string]*RetryMetrics {
+	return retry.GetMetrics()
+}
+
+// GetQuarantineMetrics api
+func GetQuarantineMetrics() (*QuarantineMetrics, error) {
+	return mongodb.GetQuarantineMetrics()
+}
+
 // GetStatusInfo api
 func GetStatusInfo(status string) (map[string]map[string]interface{}, error) {
 	return mongodb.GetStatusInfo(status)
 }
 
+// GetStatusCatalog api
+func GetStatusCatalog() []*SwapStatusInfo {
+	return mongodb.GetStatusCatalog()
+}
+
+// GetRegisterErrorTable api
+func GetRegisterErrorTable() []*RegisterErrorEntry {
+	return tokens.GetRegisterErrorTable()
+}
+
+// GetNativePrices api
+func GetNativePrices() (*NativePricesInfo, error) {
+	if tokens.NativePriceCfg == nil {
+		return nil, errNoNativePrice
+	}
+	return &NativePricesInfo{
+		Src:  tokens.GetNativePrice(true),
+		Dest: tokens.GetNativePrice(false),
+	}, nil
+}
+
 // GetTokenPairInfo api
 func GetTokenPairInfo(pairID string) (*tokens.TokenPairConfig, error) {
 	pairCfg := tokens.GetTokenPairConfig(pairID)
@@ -113,12 +175,7 @@ func GetTokenPairInfo(pairID string) (*tokens.TokenPairConfig, error) {
 
 // GetTokenPairsInfo api
 func GetTokenPairsInfo(pairIDs string) (map[string]*tokens.TokenPairConfig, error) {
-	var pairIDSlice []string
-	if strings.EqualFold(pairIDs, "all") {
-		pairIDSlice = tokens.GetAllPairIDs()
-	} else {
-		pairIDSlice = strings.Split(pairIDs, ",")
-	}
+	pairIDSlice := tokens.SplitPairIDs(pairIDs)
 	result := make(map[string]*tokens.TokenPairConfig, len(pairIDSlice))
 	for _, pairID := range pairIDSlice {
 		result[pairID] = tokens.GetTokenPairConfig(pairID)
@@ -137,56 +194,61 @@ func GetNonceInfo() (*SwapNonceInfo, error) {
 
 // GetRawSwapin api
 func GetRawSwapin(txid, pairID, bindAddr *string) (*Swap, error) {
-	return mongodb.FindSwapin(*txid, *pairID, *bindAddr)
+	return getRawSwap(SwapinDirection, *txid, *pairID, *bindAddr)
 }
 
 // GetRawSwapinResult api
 func GetRawSwapinResult(txid, pairID, bindAddr *string) (*SwapResult, error) {
-	return mongodb.FindSwapinResult(*txid, *pairID, *bindAddr)
+	return getRawSwapResult(SwapinDirection, *txid, *pairID, *bindAddr)
 }
 
 // GetSwapin api
 func GetSwapin(txid, pairID, bindAddr *string) (*SwapInfo, error) {
-	txidstr := *txid
-	pairIDStr := *pairID
-	bindStr := *bindAddr
-	result, err := mongodb.FindSwapinResult(txidstr, pairIDStr, bindStr)
-	if err == nil {
-		return ConvertMgoSwapResultToSwapInfo(result), nil
-	}
-	register, err := mongodb.FindSwapin(txidstr, pairIDStr, bindStr)
-	if err == nil {
-		return ConvertMgoSwapToSwapInfo(register), nil
-	}
-	return nil, mongodb.ErrSwapNotFound
+	return getSwap(SwapinDirection, *txid, *pairID, *bindAddr)
 }
 
 // GetRawSwapout api
 func GetRawSwapout(txid, pairID, bindAddr *string) (*Swap, error) {
-	return mongodb.FindSwapout(*txid, *pairID, *bindAddr)
+	return getRawSwap(SwapoutDirection, *txid, *pairID, *bindAddr)
 }
 
 // GetRawSwapoutResult api
 func GetRawSwapoutResult(txid, pairID, bindAddr *string) (*SwapResult, error) {
-	return mongodb.FindSwapoutResult(*txid, *pairID, *bindAddr)
+	return getRawSwapResult(SwapoutDirection, *txid, *pairID, *bindAddr)
 }
 
 // GetSwapout api
 func GetSwapout(txid, pairID, bindAddr *string) (*SwapInfo, error) {
-	txidstr := *txid
-	pairIDStr := *pairID
-	bindStr := *bindAddr
-	result, err := mongodb.FindSwapoutResult(txidstr, pairIDStr, bindStr)
-	if err == nil {
-		return ConvertMgoSwapResultToSwapInfo(result), nil
+	return getSwap(SwapoutDirection, *txid, *pairID, *bindAddr)
+}
+
+// GetSwap api, find swapin and swapout of txid,
+// returns both if the txid is found in both directions.
+func GetSwap(txid, pairID, bindAddr *string) ([]*SwapInfo, error) {
+	var result []*SwapInfo
+	for _, dir := range []SwapDirection{SwapinDirection, SwapoutDirection} {
+		info, err := getSwap(dir, *txid, *pairID, *bindAddr)
+		if errors.Is(err, mongodb.ErrSwapBindAmbiguous) {
+			return nil, err
+		}
+		if err == nil {
+			result = append(result, info)
+		}
 	}
-	register, err := mongodb.FindSwapout(txidstr, pairIDStr, bindStr)
-	if err == nil {
-		return ConvertMgoSwapToSwapInfo(register), nil
+	if len(result) == 0 {
+		return nil, mongodb.ErrSwapNotFound
 	}
-	return nil, mongodb.ErrSwapNotFound
+	return result, nil
 }
 
+const (
+	allAddresses = "all"
+
+	// maxHistoryAddresses max number of addresses in one history query,
+	// the limit applies to the combined results of all addresses.
+	maxHistoryAddresses = 20
+)
+
 func processHistoryLimit(limit int) int {
 	switch {
 	case limit == 0:
@@ -199,68 +261,107 @@ func processHistoryLimit(limit int) int {
 	return limit
 }
 
-// GetSwapinHistory api
-func GetSwapinHistory(address, pairID string, offset, limit int, status string) ([]*SwapInfo, error) {
-	log.Debug("[api] receive GetSwapinHistory", "address", address, "pairID", pairID, "offset", offset, "limit", limit, "status", status)
-	limit = processHistoryLimit(limit)
-	result, err := mongodb.FindSwapinResults(address, pairID, offset, limit, status)
-	if err != nil {
-		return nil, err
+// checkHistoryStatus check comma separated status names or codes,
+// empty status queries all statuses.
+func checkHistoryStatus(status string) error {
+	if _, err := mongodb.ParseSwapStatusList(status); err != nil {
+		return newRPCError(-32071, "history query with "+err.Error())
 	}
-	return ConvertMgoSwapResultsToSwapInfos(result), nil
+	return nil
 }
 
-// GetSwapoutHistory api
-func GetSwapoutHistory(address, pairID string, offset, limit int, status string) ([]*SwapInfo, error) {
-	log.Debug("[api] receive GetSwapoutHistory", "address", address, "pairID", pairID, "offset", offset, "limit", limit)
-	limit = processHistoryLimit(limit)
-	result, err := mongodb.FindSwapoutResults(address, pairID, offset, limit, status)
-	if err != nil {
-		return nil, err
+// checkHistoryTimeRange check time range of history query,
+// zero time means the range is open at that side.
+func checkHistoryTimeRange(fromTime, toTime int64) error {
+	if fromTime < 0 || toTime < 0 || (toTime > 0 && fromTime > toTime) {
+		return errHistoryTimeRange
 	}
-	return ConvertMgoSwapResultsToSwapInfos(result), nil
-}
-
-// Swapin api
-func Swapin(txid, pairID *string) (*PostResult, error) {
-	log.Debug("[api] receive Swapin", "txid", *txid, "pairID", *pairID)
-	return swap(txid, pairID, true)
+	return nil
 }
 
-// RetrySwapin api
-func RetrySwapin(txid, pairID *string) (*PostResult, error) {
-	log.Debug("[api] retry Swapin", "txid", *txid, "pairID", *pairID)
-	if _, ok := tokens.SrcBridge.(tokens.NonceSetter); !ok {
-		return nil, errSwapCannotRetry
+// splitHistoryAddresses split comma separated addresses,
+// returns nil if address is empty or 'all', and error if there's no address
+// after splitting (eg. ',') to not query all addresses unexpectedly.
+func splitHistoryAddresses(address string) ([]string, error) {
+	if address == "" || address == allAddresses {
+		return nil, nil
 	}
-	txidstr := *txid
-	pairIDStr := *pairID
-	if err := basicCheckSwapRegister(tokens.SrcBridge, pairIDStr); err != nil {
-		return nil, err
+	parts := strings.Split(address, ",")
+	addresses := make([]string, 0, len(parts))
+	exist := make(map[string]struct{}, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if common.IsHexAddress(part) {
+			part = strings.ToLower(part)
+		}
+		if _, ok := exist[part]; ok {
+			continue
+		}
+		exist[part] = struct{}{}
+		addresses = append(addresses, part)
 	}
-	swapInfo, err := tokens.SrcBridge.VerifyTransaction(pairIDStr, txidstr, true)
-	if err != nil {
-		return nil, newRPCError(-32099, "retry swapin failed! "+err.Error())
+	if len(addresses) == 0 {
+		return nil, errNoHistoryAddress
 	}
-	bindStr := swapInfo.Bind
-	swap, _ := mongodb.FindSwapin(txidstr, pairIDStr, bindStr)
-	if swap == nil {
-		return nil, mongodb.ErrItemNotFound
+	if len(addresses) > maxHistoryAddresses {
+		return nil, errTooManyAddresses
 	}
-	if !swap.Status.CanRetry() {
-		return nil, errSwapCannotRetry
+	return addresses, nil
+}
+
+// tagMatchedAddresses set which requested address the swap matched
+func tagMatchedAddresses(swaps []*SwapInfo, addresses []string) []*SwapInfo {
+	if len(addresses) == 0 {
+		return swaps
 	}
-	err = mongodb.UpdateSwapinStatus(txidstr, pairIDStr, bindStr, mongodb.TxNotStable, time.Now().Unix(), "")
-	if err != nil {
-		return nil, err
+	for _, swap := range swaps {
+		for _, address := range addresses {
+			if swap.From == address {
+				swap.MatchedAddress = address
+				break
+			}
+		}
 	}
-	return &SuccessPostResult, nil
+	return swaps
+}
+
+// GetSwapinHistory api, address can be up to `maxHistoryAddresses` comma separated addresses
+func GetSwapinHistory(address, pairID string, offset, limit int, status string, fromTime, toTime int64) ([]*SwapInfo, error) {
+	return getSwapHistory(SwapinDirection, address, pairID, offset, limit, status, fromTime, toTime)
+}
+
+// GetSwapoutHistory api, address can be up to `maxHistoryAddresses` comma separated addresses
+func GetSwapoutHistory(address, pairID string, offset, limit int, status string, fromTime, toTime int64) ([]*SwapInfo, error) {
+	return getSwapHistory(SwapoutDirection, address, pairID, offset, limit, status, fromTime, toTime)
+}
+
+// Swapin api
+func Swapin(clientIP string, txid, pairID *string) (*PostResult, error) {
+	log.Debug("[api] receive Swapin", "txid", *txid, "pairID", *pairID, "clientIP", clientIP)
+	return limitRegister(RegisterMethodSwapin, clientIP, "", *txid, func() (*PostResult, error) {
+		return swap(txid, pairID, true)
+	})
+}
+
+// RetrySwapin api
+func RetrySwapin(txid, pairID *string) (*PostResult, error) {
+	return retrySwap(SwapinDirection, *txid, *pairID)
+}
+
+// RetrySwapout api
+func RetrySwapout(txid, pairID *string) (*PostResult, error) {
+	return retrySwap(SwapoutDirection, *txid, *pairID)
 }
 
 // Swapout api
-func Swapout(txid, pairID *string) (*PostResult, error) {
-	log.Debug("[api] receive Swapout", "txid", *txid, "pairID", *pairID)
-	return swap(txid, pairID, false)
+func Swapout(clientIP string, txid, pairID *string) (*PostResult, error) {
+	log.Debug("[api] receive Swapout", "txid", *txid, "pairID", *pairID, "clientIP", clientIP)
+	return limitRegister(RegisterMethodSwapout, clientIP, "", *txid, func() (*PostResult, error) {
+		return swap(txid, pairID, false)
+	})
 }
 
 func basicCheckSwapRegister(bridge tokens.CrossChainBridge, pairIDStr string) error {
@@ -277,33 +378,53 @@ func basicCheckSwapRegister(bridge tokens.CrossChainBridge, pairIDStr string) er
 func swap(txid, pairID *string, isSwapin bool) (*PostResult, error) {
 	txidstr := *txid
 	pairIDStr := *pairID
-	bridge := tokens.GetCrossChainBridge(isSwapin)
+	dir := SwapDirection(isSwapin)
+	bridge := dir.bridge()
 	if err := basicCheckSwapRegister(bridge, pairIDStr); err != nil {
 		return nil, err
 	}
+	if err := checkCachedTxNotFound(dir.registerMethod(), txidstr, isSwapin); err != nil {
+		return nil, err
+	}
 	swapInfo, err := bridge.VerifyTransaction(pairIDStr, txidstr, true)
-	var txType tokens.SwapTxType
-	if isSwapin {
-		txType = tokens.SwapinTx
-	} else {
-		txType = tokens.SwapoutTx
+	if errors.Is(err, tokens.ErrTxNotFound) {
+		cacheTxNotFound(txidstr, isSwapin)
+	}
+	err = addSwapToDatabase(txidstr, dir.txType(), swapInfo, err)
+	if errors.Is(err, mongodb.ErrItemIsDup) {
+		return getAlreadyRegisteredResult(isSwapin, txidstr, pairIDStr, swapInfo.Bind), nil
 	}
-	err = addSwapToDatabase(txidstr, txType, swapInfo, err)
 	if err != nil {
 		return nil, err
 	}
-	if isSwapin {
-		log.Info("[api] receive swapin register", "txid", txidstr, "pairID", pairIDStr)
-	} else {
-		log.Info("[api] receive swapout register", "txid", txidstr, "pairID", pairIDStr)
-	}
+	log.Info("[api] receive "+dir.String()+" register", "txid", txidstr, "pairID", pairIDStr)
 	return &SuccessPostResult, nil
 }
 
+// getAlreadyRegisteredResult result of registering existing swap, with its current status
+func getAlreadyRegisteredResult(isSwapin bool, txid, pairID, bind string) *PostResult {
+	result := PostResult(AlreadyRegisteredPostResult)
+	if info, err := getSwap(SwapDirection(isSwapin), txid, pairID, bind); err == nil {
+		result = PostResult(fmt.Sprintf("%v, status=%v", AlreadyRegisteredPostResult, info.Status.String()))
+	}
+	return &result
+}
+
+// addSwapToDatabase register swap if verify error is in the register swap error table,
+// all register paths should call this to classify verify errors identically.
 func addSwapToDatabase(txid string, txType tokens.SwapTxType, swapInfo *tokens.TxSwapInfo, verifyError error) (err error) {
+	if verifyError != nil {
+		metrics.VerifyErrors.Inc("register", txType.String(), mongodb.GetVerifyErrorName(verifyError))
+	}
+	if errors.Is(verifyError, tokens.ErrTxTooRecent) {
+		return newRPCError(-32062, verifyError.Error())
+	}
 	if !tokens.ShouldRegisterSwapForError(verifyError) {
 		return newRPCError(-32099, "verify swap failed! "+verifyError.Error())
 	}
+	if swapInfo == nil {
+		return newRPCError(-32099, "verify swap failed! no swap info")
+	}
 	var memo string
 	if verifyError != nil {
 		memo = verifyError.Error()
@@ -319,13 +440,16 @@ func addSwapToDatabase(txid string, txType tokens.SwapTxType, swapInfo *tokens.T
 		Timestamp: time.Now().Unix(),
 		Memo:      memo,
 	}
-	isSwapin := txType == tokens.SwapinTx
+	isSwapin := txType != tokens.SwapoutTx
 	log.Info("[api] add swap", "isSwapin", isSwapin, "swap", swap)
 	if isSwapin {
 		err = mongodb.AddSwapin(swap)
 	} else {
 		err = mongodb.AddSwapout(swap)
 	}
+	if err == nil {
+		metrics.SwapsRegistered.Inc(swap.PairID, txType.String())
+	}
 	return err
 }
 
@@ -339,6 +463,27 @@ func IsValidSwapoutBindAddress(address *string) bool {
 	return tokens.SrcBridge.IsValidAddress(*address)
 }
 
+// BindAddressValidation bind address validation result,
+// reasons are machine readable codes of invalid address.
+type BindAddressValidation struct {
+	Valid      bool     `json:"valid"`
+	Normalized string   `json:"normalized,omitempty"`
+	Reasons    []string `json:"reasons,omitempty"`
+}
+
+// ValidateBindAddress api, validate bind address by the bridge of payout chain
+func ValidateBindAddress(pairID, address string, isSwapin bool) (*BindAddressValidation, error) {
+	if tokens.GetTokenPairConfig(pairID) == nil {
+		return nil, errTokenPairNotExist
+	}
+	normalized, reasons := tokens.ValidateAddress(tokens.GetCrossChainBridge(!isSwapin), address)
+	return &BindAddressValidation{
+		Valid:      len(reasons) == 0,
+		Normalized: normalized,
+		Reasons:    reasons,
+	}, nil
+}
+
 // RegisterP2shAddress api
 func RegisterP2shAddress(bindAddress string) (*tokens.P2shAddressInfo, error) {
 	return calcP2shAddress(bindAddress, true)
@@ -354,23 +499,25 @@ func GetP2shAddressInfo(p2shAddress string) (*tokens.P2shAddressInfo, error) {
 }
 
 func calcP2shAddress(bindAddress string, addToDatabase bool) (*tokens.P2shAddressInfo, error) {
-	if btc.BridgeInstance == nil {
-		return nil, errNotBtcBridge
-	}
-	p2shAddr, redeemScript, err := btc.BridgeInstance.GetP2shAddress(bindAddress)
-	if err != nil {
-		return nil, newRPCInternalError(err)
+	provider := tokens.GetP2shAddressProvider()
+	if provider == nil {
+		return nil, errNoP2shBridge
 	}
-	disasm, err := txscript.DisasmString(redeemScript)
+	p2shAddr, redeemScript, err := provider.GetP2shAddress(bindAddress)
 	if err != nil {
 		return nil, newRPCInternalError(err)
 	}
+	disasm, disasmErr := disasmRedeemScript(redeemScript)
 	if addToDatabase {
 		result, _ := mongodb.FindP2shAddress(bindAddress)
+		if result != nil && result.Inactive {
+			_ = mongodb.ReactivateP2shAddress(bindAddress)
+		}
 		if result == nil {
 			_ = mongodb.AddP2shAddress(&mongodb.MgoP2shAddress{
 				Key:         bindAddress,
 				P2shAddress: p2shAddr,
+				Timestamp:   time.Now().Unix(),
 			})
 		}
 	}
@@ -379,47 +526,47 @@ func calcP2shAddress(bindAddress string, addToDatabase bool) (*tokens.P2shAddres
 		P2shAddress:        p2shAddr,
 		RedeemScript:       hex.EncodeToString(redeemScript),
 		RedeemScriptDisasm: disasm,
+		DisasmError:        disasmErr,
 	}, nil
 }
 
 // P2shSwapin api
-func P2shSwapin(txid, bindAddr *string) (*PostResult, error) {
-	log.Debug("[api] receive P2shSwapin", "txid", *txid, "bindAddress", *bindAddr)
-	if btc.BridgeInstance == nil {
-		return nil, errNotBtcBridge
+func P2shSwapin(clientIP string, txid, bindAddr *string) (*PostResult, error) {
+	log.Debug("[api] receive P2shSwapin", "txid", *txid, "bindAddress", *bindAddr, "clientIP", clientIP)
+	return limitRegister(RegisterMethodP2shSwapin, clientIP, *bindAddr, *txid, func() (*PostResult, error) {
+		return p2shSwapin(txid, bindAddr)
+	})
+}
+
+func p2shSwapin(txid, bindAddr *string) (*PostResult, error) {
+	provider := tokens.GetP2shAddressProvider()
+	if provider == nil {
+		return nil, errNoP2shBridge
 	}
 	txidstr := *txid
-	pairID := btc.PairID
+	pairID := provider.GetP2shPairID()
 	if swap, _ := mongodb.FindSwapin(txidstr, pairID, *bindAddr); swap != nil {
-		return nil, mongodb.ErrItemIsDup
+		return getAlreadyRegisteredResult(true, txidstr, pairID, *bindAddr), nil
 	}
-	if err := basicCheckSwapRegister(btc.BridgeInstance, pairID); err != nil {
+	if err := basicCheckSwapRegister(provider, pairID); err != nil {
 		return nil, err
 	}
-	swapInfo, err := btc.BridgeInstance.VerifyP2shTransaction(pairID, txidstr, *bindAddr, true)
-	if !tokens.ShouldRegisterSwapForError(err) {
-		return nil, newRPCError(-32099, "verify p2sh swapin failed! "+err.Error())
+	if err := checkCachedTxNotFound(RegisterMethodP2shSwapin, txidstr, true); err != nil {
+		return nil, err
 	}
-	var memo string
-	if err != nil {
-		memo = err.Error()
+	swapInfo, err := provider.VerifyP2shTransaction(pairID, txidstr, *bindAddr, true)
+	if errors.Is(err, tokens.ErrTxNotFound) {
+		cacheTxNotFound(txidstr, true)
 	}
-	swap := &mongodb.MgoSwap{
-		PairID:    swapInfo.PairID,
-		TxID:      txidstr,
-		TxTo:      swapInfo.TxTo,
-		TxType:    uint32(tokens.P2shSwapinTx),
-		From:      swapInfo.From,
-		Bind:      *bindAddr,
-		Status:    mongodb.GetStatusByTokenVerifyError(err),
-		Timestamp: time.Now().Unix(),
-		Memo:      memo,
+	err = addSwapToDatabase(txidstr, tokens.P2shSwapinTx, swapInfo, err)
+	if errors.Is(err, mongodb.ErrItemIsDup) {
+		return getAlreadyRegisteredResult(true, txidstr, pairID, *bindAddr), nil
 	}
-	err = mongodb.AddSwapin(swap)
 	if err != nil {
 		return nil, err
 	}
-	log.Info("[api] add p2sh swapin", "swap", swap)
+	_ = mongodb.UpdateP2shDeposit(*bindAddr)
+	log.Info("[api] receive p2sh swapin register", "txid", txidstr, "bind", *bindAddr)
 	return &SuccessPostResult, nil
 }
 
@@ -433,8 +580,18 @@ func RegisterAddress(address string) (*PostResult, error) {
 	if !params.MustRegisterAccount() {
 		return &SuccessPostResult, nil
 	}
+	if params.IsRegisterAddressProofRequired() {
+		return nil, errRegisterProofRequired
+	}
+	return addRegisteredAddress(address)
+}
+
+func addRegisteredAddress(address string) (*PostResult, error) {
+	if err := checkRegisterAddress(address); err != nil {
+		return nil, err
+	}
 	address = strings.ToLower(address)
-	err := mongodb.AddRegisteredAddress(address)
+	err := mongodb.AddRegisteredAddress(address, params.GetRegisterPrecedence())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,146 @@
+package swapapi
+
+import (
+	"crypto/subtle"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+// APITokenHeader http header of api token
+const APITokenHeader = "X-Api-Token"
+
+var (
+	errAPITokenInvalid     = newRPCError(-32070, "invalid api token")
+	errAPITokenNotInScope  = newRPCError(-32069, "method is not in scope of api token")
+	errAPITokenRateLimited = newRPCError(-32068, "api token rate limited, retry later")
+	errAPITokenRequired    = newRPCError(-32053, "method requires api token")
+
+	apiTokenRateLock        sync.Mutex
+	apiTokenRateStart       int64          // start of current rate window (minute)
+	apiTokenRateCount       map[string]int // token name -> calls in current window
+	apiTokenMetrics         sync.Map       // token name -> *APITokenMetrics
+	apiTokenInvalidRequests uint64
+
+	apiTokenRequiredMethods = make(map[string]bool) // registered at init
+)
+
+// APITokenMetrics usage counters of api token
+type APITokenMetrics struct {
+	Calls               uint64 `json:"calls"`
+	RejectedByScope     uint64 `json:"rejectedByScope"`
+	RejectedByRateLimit uint64 `json:"rejectedByRateLimit"`
+	LastCallTime        int64  `json:"lastCallTime"`
+}
+
+func getAPITokenMetrics(name string) *APITokenMetrics {
+	if m, exist := apiTokenMetrics.Load(name); exist {
+		return m.(*APITokenMetrics)
+	}
+	m, _ := apiTokenMetrics.LoadOrStore(name, &APITokenMetrics{})
+	return m.(*APITokenMetrics)
+}
+
+// GetAPITokenMetrics api, key is token name,
+// requests with unknown token are counted with empty key.
+func GetAPITokenMetrics() map[string]*APITokenMetrics {
+	result := make(map[string]*APITokenMetrics)
+	apiTokenMetrics.Range(func(k, v interface{}) bool {
+		m := v.(*APITokenMetrics)
+		result[k.(string)] = &APITokenMetrics{
+			Calls:               atomic.LoadUint64(&m.Calls),
+			RejectedByScope:     atomic.LoadUint64(&m.RejectedByScope),
+			RejectedByRateLimit: atomic.LoadUint64(&m.RejectedByRateLimit),
+			LastCallTime:        atomic.LoadInt64(&m.LastCallTime),
+		}
+		return true
+	})
+	if invalid := atomic.LoadUint64(&apiTokenInvalidRequests); invalid > 0 {
+		result[""] = &APITokenMetrics{Calls: invalid}
+	}
+	return result
+}
+
+func findAPIToken(token string) *params.APITokenConfig {
+	apiServer := params.GetServerConfig().APIServer
+	if apiServer == nil {
+		return nil
+	}
+	for _, apiToken := range apiServer.APITokens {
+		if subtle.ConstantTimeCompare([]byte(apiToken.Token), []byte(token)) == 1 {
+			return apiToken
+		}
+	}
+	return nil
+}
+
+func isMethodInScope(apiToken *params.APITokenConfig, method string) bool {
+	for _, scope := range apiToken.Methods {
+		if strings.HasSuffix(scope, "*") {
+			if strings.HasPrefix(method, strings.TrimSuffix(scope, "*")) {
+				return true
+			}
+		} else if scope == method {
+			return true
+		}
+	}
+	return false
+}
+
+// allowAPITokenRate allow at most 'RatePerMinute' calls per minute of api token
+func allowAPITokenRate(apiToken *params.APITokenConfig) bool {
+	if apiToken.RatePerMinute == 0 {
+		return true
+	}
+	window := time.Now().Unix() / 60
+	apiTokenRateLock.Lock()
+	defer apiTokenRateLock.Unlock()
+	if window != apiTokenRateStart || apiTokenRateCount == nil {
+		apiTokenRateStart = window
+		apiTokenRateCount = make(map[string]int)
+	}
+	if apiTokenRateCount[apiToken.Name] >= apiToken.RatePerMinute {
+		return false
+	}
+	apiTokenRateCount[apiToken.Name]++
+	return true
+}
+
+// RequireAPIToken require rpc method to be called with api token,
+// it should be called at init as the registered methods are not locked.
+func RequireAPIToken(method string) {
+	apiTokenRequiredMethods[method] = true
+}
+
+// CheckAPIToken check rpc method is allowed to call with api token,
+// calls without api token are not restricted unless the method requires api token.
+func CheckAPIToken(token, method string) error {
+	if token == "" {
+		if apiTokenRequiredMethods[method] {
+			return errAPITokenRequired
+		}
+		return nil
+	}
+	apiToken := findAPIToken(token)
+	if apiToken == nil {
+		atomic.AddUint64(&apiTokenInvalidRequests, 1)
+		return errAPITokenInvalid
+	}
+	metrics := getAPITokenMetrics(apiToken.Name)
+	if !isMethodInScope(apiToken, method) {
+		atomic.AddUint64(&metrics.RejectedByScope, 1)
+		log.Debug("[api] method is not in scope of api token", "name", apiToken.Name, "method", method)
+		return errAPITokenNotInScope
+	}
+	if !allowAPITokenRate(apiToken) {
+		atomic.AddUint64(&metrics.RejectedByRateLimit, 1)
+		return errAPITokenRateLimited
+	}
+	atomic.AddUint64(&metrics.Calls, 1)
+	atomic.StoreInt64(&metrics.LastCallTime, time.Now().Unix())
+	return nil
+}
@@ -0,0 +1,49 @@
+package swapapi
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+func TestCheckAPIToken(t *testing.T) {
+	params.SetConfig(&params.BridgeConfig{Server: &params.ServerConfig{
+		APIServer: &params.APIServerConfig{
+			APITokens: []*params.APITokenConfig{
+				{Name: "partner", Token: "secret", Methods: []string{"swap.Swapin", "swap.Get*"}, RatePerMinute: 2},
+			},
+		},
+	}})
+
+	if err := CheckAPIToken("", "swap.AdminCall"); err != nil {
+		t.Errorf("call without api token should not be restricted, have %v", err)
+	}
+	RequireAPIToken("swap.GetAllRegistered")
+	defer delete(apiTokenRequiredMethods, "swap.GetAllRegistered")
+	if err := CheckAPIToken("", "swap.GetAllRegistered"); err != errAPITokenRequired {
+		t.Errorf("want api token required error, have %v", err)
+	}
+	if err := CheckAPIToken("wrong", "swap.Swapin"); err != errAPITokenInvalid {
+		t.Errorf("want invalid api token error, have %v", err)
+	}
+	if err := CheckAPIToken("secret", "swap.AdminCall"); err != errAPITokenNotInScope {
+		t.Errorf("want not in scope error, have %v", err)
+	}
+	if err := CheckAPIToken("secret", "swap.Swapin"); err != nil {
+		t.Errorf("scoped method is rejected, err %v", err)
+	}
+	if err := CheckAPIToken("secret", "swap.GetSwapinHistory"); err != nil {
+		t.Errorf("prefix scoped method is rejected, err %v", err)
+	}
+	if err := CheckAPIToken("secret", "swap.GetSwapin"); err != errAPITokenRateLimited {
+		t.Errorf("want rate limited error, have %v", err)
+	}
+
+	metrics := GetAPITokenMetrics()
+	if m := metrics["partner"]; m == nil || m.Calls != 2 || m.RejectedByScope != 1 || m.RejectedByRateLimit != 1 || m.LastCallTime == 0 {
+		t.Errorf("unexpected api token metrics %+v", m)
+	}
+	if m := metrics[""]; m == nil || m.Calls != 1 {
+		t.Errorf("unexpected invalid api token metrics %+v", m)
+	}
+}
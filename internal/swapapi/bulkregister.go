@@ -0,0 +1,211 @@
+package swapapi
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const (
+	maxBulkRegisterSize     = 20000
+	maxBulkRegisterJobs     = 20
+	bulkRegisterQueueSize   = 5
+	defaultBulkRegisterRate = 5 // txids per second
+)
+
+var (
+	errBulkRegisterEmpty     = newRPCError(-32084, "empty bulk register txids")
+	errBulkRegisterTooLarge  = newRPCError(-32083, "too many txids in bulk register")
+	errBulkRegisterBusy      = newRPCError(-32082, "bulk register queue is full, retry later")
+	errBulkJobNotFound       = newRPCError(-32081, "bulk register job not found")
+	errBulkRegisterDirection = newRPCError(-32080, "bulk register direction must be swapin or swapout")
+
+	bulkRegisterQueue     = make(chan *BulkRegisterJob, bulkRegisterQueueSize)
+	bulkRegisterJobs      = make(map[string]*BulkRegisterJob)
+	bulkRegisterJobIDs    []string
+	bulkRegisterJobsLock  sync.RWMutex
+	bulkRegisterStartOnce sync.Once
+)
+
+// BulkRegisterJob bulk swap registration job
+type BulkRegisterJob struct {
+	JobID      string            `json:"jobid"`
+	PairID     string            `json:"pairid"`
+	Direction  string            `json:"direction"`
+	Total      int               `json:"total"`
+	Processed  int               `json:"processed"`
+	Registered int               `json:"registered"`
+	Known      int               `json:"known"` // already registered or duplicate in list
+	Failed     int               `json:"failed"`
+	Done       bool              `json:"done"`
+	Errors     map[string]string `json:"errors,omitempty"`
+	Timestamp  int64             `json:"timestamp"`
+
+	isSwapin bool
+	txids    []string
+}
+
+// BulkRegister register missed swaps in background at the configured rate
+func BulkRegister(pairID, direction string, txids []string) (string, error) {
+	var isSwapin bool
+	switch direction {
+	case tokens.SwapinType.String():
+		isSwapin = true
+	case tokens.SwapoutType.String():
+	default:
+		return "", errBulkRegisterDirection
+	}
+	if len(txids) == 0 {
+		return "", errBulkRegisterEmpty
+	}
+	if len(txids) > maxBulkRegisterSize {
+		return "", errBulkRegisterTooLarge
+	}
+	if err := basicCheckSwapRegister(tokens.GetCrossChainBridge(isSwapin), pairID); err != nil {
+		return "", err
+	}
+	bulkRegisterStartOnce.Do(func() {
+		go processBulkRegisterJobs()
+	})
+	job := &BulkRegisterJob{
+		JobID:     newP2shBatchJobID(),
+		PairID:    pairID,
+		Direction: direction,
+		Total:     len(txids),
+		Timestamp: time.Now().Unix(),
+		isSwapin:  isSwapin,
+		txids:     txids,
+	}
+	select {
+	case bulkRegisterQueue <- job:
+	default:
+		return "", errBulkRegisterBusy
+	}
+	addBulkRegisterJob(job)
+	log.Info("[api] add bulk register job", "jobid", job.JobID, "pairID", pairID, "direction", direction, "total", job.Total)
+	return job.JobID, nil
+}
+
+// GetBulkJobStatus api
+func GetBulkJobStatus(jobID string) (*BulkRegisterJob, error) {
+	bulkRegisterJobsLock.RLock()
+	defer bulkRegisterJobsLock.RUnlock()
+	job, exist := bulkRegisterJobs[jobID]
+	if !exist {
+		return nil, errBulkJobNotFound
+	}
+	result := *job
+	result.Errors = make(map[string]string, len(job.Errors))
+	for k, v := range job.Errors {
+		result.Errors[k] = v
+	}
+	return &result, nil
+}
+
+// keep only the latest jobs in memory
+func addBulkRegisterJob(job *BulkRegisterJob) {
+	bulkRegisterJobsLock.Lock()
+	defer bulkRegisterJobsLock.Unlock()
+	bulkRegisterJobs[job.JobID] = job
+	bulkRegisterJobIDs = append(bulkRegisterJobIDs, job.JobID)
+	if len(bulkRegisterJobIDs) > maxBulkRegisterJobs {
+		delete(bulkRegisterJobs, bulkRegisterJobIDs[0])
+		bulkRegisterJobIDs = bulkRegisterJobIDs[1:]
+	}
+}
+
+func getBulkRegisterRate() int {
+	rate := params.GetServerConfig().BulkRegisterRate
+	if rate <= 0 {
+		rate = defaultBulkRegisterRate
+	}
+	return rate
+}
+
+// bulkRegisterer registration backend of bulk register job
+type bulkRegisterer interface {
+	IsRegistered(isSwapin bool, txid, pairID string) bool
+	Register(isSwapin bool, txid, pairID string) error
+}
+
+type swapBulkRegisterer struct{}
+
+func (swapBulkRegisterer) IsRegistered(isSwapin bool, txid, pairID string) bool {
+	existing, err := mongodb.FindSwap(isSwapin, txid, pairID, "")
+	return existing != nil || errors.Is(err, mongodb.ErrSwapBindAmbiguous)
+}
+
+func (swapBulkRegisterer) Register(isSwapin bool, txid, pairID string) error {
+	result, err := swap(&txid, &pairID, isSwapin)
+	if err == nil && *result != SuccessPostResult {
+		return mongodb.ErrItemIsDup // registered concurrently by others
+	}
+	return err
+}
+
+func processBulkRegisterJobs() {
+	for job := range bulkRegisterQueue {
+		processBulkRegisterJob(job, swapBulkRegisterer{})
+	}
+}
+
+func processBulkRegisterJob(job *BulkRegisterJob, registerer bulkRegisterer) {
+	ticker := time.NewTicker(time.Second / time.Duration(getBulkRegisterRate()))
+	defer ticker.Stop()
+
+	seen := make(map[string]struct{}, len(job.txids))
+	for _, txid := range job.txids {
+		txid = strings.TrimSpace(txid)
+		key := strings.ToLower(txid)
+		_, isDup := seen[key]
+		seen[key] = struct{}{}
+
+		var isKnown bool
+		var err error
+		switch {
+		case isDup:
+			isKnown = true
+		case txid == "":
+			err = errors.New("empty txid")
+		default:
+			// cheap database lookup before calling chain rpc
+			if registerer.IsRegistered(job.isSwapin, txid, job.PairID) {
+				isKnown = true
+				break
+			}
+			<-ticker.C
+			err = registerer.Register(job.isSwapin, txid, job.PairID)
+			if errors.Is(err, mongodb.ErrItemIsDup) {
+				isKnown, err = true, nil
+			}
+		}
+
+		bulkRegisterJobsLock.Lock()
+		job.Processed++
+		switch {
+		case err != nil:
+			job.Failed++
+			if job.Errors == nil {
+				job.Errors = make(map[string]string)
+			}
+			job.Errors[txid] = err.Error()
+		case isKnown:
+			job.Known++
+		default:
+			job.Registered++
+		}
+		bulkRegisterJobsLock.Unlock()
+	}
+	bulkRegisterJobsLock.Lock()
+	job.Done = true
+	job.txids = nil
+	bulkRegisterJobsLock.Unlock()
+	log.Info("[api] bulk register job finished", "jobid", job.JobID, "total", job.Total,
+		"registered", job.Registered, "known", job.Known, "failed", job.Failed)
+}
@@ -0,0 +1,92 @@
+package swapapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+type memBulkRegisterer struct {
+	registered map[string]bool
+	failures   map[string]error
+	calls      []string
+}
+
+func (r *memBulkRegisterer) IsRegistered(isSwapin bool, txid, pairID string) bool {
+	return r.registered[txid]
+}
+
+func (r *memBulkRegisterer) Register(isSwapin bool, txid, pairID string) error {
+	r.calls = append(r.calls, txid)
+	if err := r.failures[txid]; err != nil {
+		return err
+	}
+	r.registered[txid] = true
+	return nil
+}
+
+func TestProcessBulkRegisterJob(t *testing.T) {
+	params.SetConfig(&params.BridgeConfig{Server: &params.ServerConfig{BulkRegisterRate: 1000}})
+	verifyErr := errors.New("verify failed")
+	registerer := &memBulkRegisterer{
+		registered: map[string]bool{"0xknown": true},
+		failures: map[string]error{
+			"0xfail": verifyErr,
+			"0xdup":  mongodb.ErrItemIsDup, // registered concurrently by others
+		},
+	}
+	job := &BulkRegisterJob{
+		isSwapin: true,
+		txids:    []string{"0xnew", " 0xNEW ", "0xknown", "", "0xfail", "0xdup", "0xnew2"},
+	}
+	job.Total = len(job.txids)
+	processBulkRegisterJob(job, registerer)
+
+	if !job.Done || job.Processed != job.Total {
+		t.Errorf("job should be done, processed %v of %v", job.Processed, job.Total)
+	}
+	if job.Registered != 2 || job.Known != 3 || job.Failed != 2 {
+		t.Errorf("want registered 2, known 3, failed 2, have %+v", job)
+	}
+	if job.Errors["0xfail"] != verifyErr.Error() || job.Errors[""] == "" || len(job.Errors) != 2 {
+		t.Errorf("errors should be collected per txid, have %v", job.Errors)
+	}
+	// duplicates in list and registered txids are not registered again
+	if len(registerer.calls) != 4 {
+		t.Errorf("want 4 register calls, have %v", registerer.calls)
+	}
+}
+
+func TestProcessBulkRegisterJobRateLimited(t *testing.T) {
+	const rate = 50
+	params.SetConfig(&params.BridgeConfig{Server: &params.ServerConfig{BulkRegisterRate: rate}})
+	registerer := &memBulkRegisterer{registered: map[string]bool{"0xknown": true}}
+
+	// known and duplicate txids are not rate limited
+	job := &BulkRegisterJob{isSwapin: true, txids: make([]string, 0, 100)}
+	for i := 0; i < 100; i++ {
+		job.txids = append(job.txids, "0xknown")
+	}
+	start := time.Now()
+	processBulkRegisterJob(job, registerer)
+	if elapsed := time.Since(start); elapsed > time.Second/rate*10 {
+		t.Errorf("known txids should not wait for rate limit, elapsed %v", elapsed)
+	}
+
+	const count = 10
+	job = &BulkRegisterJob{isSwapin: false}
+	for i := 0; i < count; i++ {
+		job.txids = append(job.txids, "0x"+string(rune('a'+i)))
+	}
+	start = time.Now()
+	processBulkRegisterJob(job, registerer)
+	if elapsed, want := time.Since(start), time.Second/rate*count; elapsed < want*9/10 {
+		t.Errorf("registers should be rate limited to %v per second, elapsed %v want at least %v", rate, elapsed, want)
+	}
+	if job.Registered != count {
+		t.Errorf("want %v registered, have %v", count, job.Registered)
+	}
+}
@@ -8,15 +8,17 @@ import (
 // ConvertMgoSwapToSwapInfo convert
 func ConvertMgoSwapToSwapInfo(ms *mongodb.MgoSwap) *SwapInfo {
 	return &SwapInfo{
-		PairID:    ms.PairID,
-		TxID:      ms.TxID,
-		TxTo:      ms.TxTo,
-		Bind:      ms.Bind,
-		Status:    ms.Status,
-		StatusMsg: ms.Status.String(),
-		InitTime:  ms.InitTime,
-		Timestamp: ms.Timestamp,
-		Memo:      ms.Memo,
+		PairID:     ms.PairID,
+		TxID:       ms.TxID,
+		TxTo:       ms.TxTo,
+		Bind:       ms.Bind,
+		Status:     ms.Status,
+		StatusMsg:  ms.Status.String(),
+		StatusInfo: ms.Status.Info(),
+		InitTime:   ms.InitTime,
+		Timestamp:  ms.Timestamp,
+		Memo:       ms.Memo,
+		RefundTx:   ms.RefundTx,
 	}
 }
 
@@ -60,6 +62,7 @@ func ConvertMgoSwapResultToSwapInfo(mr *mongodb.MgoSwapResult) *SwapInfo {
 		SwapNonce:     mr.SwapNonce,
 		Status:        mr.Status,
 		StatusMsg:     mr.Status.String(),
+		StatusInfo:    mr.Status.Info(),
 		InitTime:      mr.InitTime,
 		Timestamp:     mr.Timestamp,
 		Memo:          mr.Memo,
@@ -0,0 +1,25 @@
+package swapapi
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+)
+
+var (
+	errWrongReportDate     = newRPCError(-32077, "wrong report date, want format 2006-01-02")
+	errDailyReportNotFound = newRPCError(-32076, "daily report not found, it is generated after the day ends")
+)
+
+// GetDailyReport api, date format is 2006-01-02
+func GetDailyReport(date string) (*DailyReport, error) {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, errWrongReportDate
+	}
+	report, err := mongodb.FindDailyReport(date)
+	if errors.Is(err, mongodb.ErrItemNotFound) {
+		return nil, errDailyReportNotFound
+	}
+	return report, err
+}
@@ -0,0 +1,101 @@
+package swapapi
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const defaultDebugVerifyRate = 1 // public calls per second
+
+var (
+	errDebugVerifyNotPublic   = newRPCError(-32079, "debug verify is not public, use admin 'debugverify' instead")
+	errDebugVerifyRateLimited = newRPCError(-32078, "debug verify rate limited, retry later")
+
+	debugVerifyWindow     int64
+	debugVerifyCount      int
+	debugVerifyWindowLock sync.Mutex
+)
+
+// DebugVerifyResult dry run verification result
+type DebugVerifyResult struct {
+	PairID        string                `json:"pairid"`
+	TxID          string                `json:"txid"`
+	IsSwapin      bool                  `json:"isSwapin"`
+	AllowUnstable bool                  `json:"allowUnstable"`
+	SwapInfo      *tokens.TxSwapInfo    `json:"swapInfo"`
+	Checks        []*tokens.VerifyCheck `json:"checks"`               // empty if bridge does not support trace
+	Error         string                `json:"error,omitempty"`      // verify error
+	ErrorChain    []string              `json:"errorChain,omitempty"` // verify error unwrapped from outermost
+	WouldRegister bool                  `json:"wouldRegister"`        // register swap accepts this verify result
+}
+
+// DebugVerifyTransaction run bridge's VerifyTransaction and return the decoded
+// swap info and annotated checks, nothing is written to database.
+func DebugVerifyTransaction(pairID, txid string, isSwapin, allowUnstable bool) (*DebugVerifyResult, error) {
+	bridge := tokens.GetCrossChainBridge(isSwapin)
+	if bridge.GetTokenConfig(pairID) == nil {
+		return nil, errTokenPairNotExist
+	}
+	result := &DebugVerifyResult{
+		PairID:        pairID,
+		TxID:          txid,
+		IsSwapin:      isSwapin,
+		AllowUnstable: allowUnstable,
+	}
+	var err error
+	if verifier, ok := bridge.(tokens.TraceVerifier); ok {
+		trace := &tokens.VerifyTrace{}
+		result.SwapInfo, err = verifier.VerifyTransactionWithTrace(pairID, txid, allowUnstable, trace)
+		result.Checks = trace.Checks
+	} else {
+		result.SwapInfo, err = bridge.VerifyTransaction(pairID, txid, allowUnstable)
+	}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		result.ErrorChain = append(result.ErrorChain, e.Error())
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	result.WouldRegister = result.SwapInfo != nil && tokens.ShouldRegisterSwapForError(err)
+	return result, nil
+}
+
+// PublicDebugVerifyTransaction debug verify for public api,
+// only allowed if 'DebugVerifyPublic' is configured and rate limited.
+func PublicDebugVerifyTransaction(pairID, txid string, isSwapin, allowUnstable bool) (*DebugVerifyResult, error) {
+	if !params.GetServerConfig().DebugVerifyPublic {
+		return nil, errDebugVerifyNotPublic
+	}
+	if !allowDebugVerify() {
+		return nil, errDebugVerifyRateLimited
+	}
+	return DebugVerifyTransaction(pairID, txid, isSwapin, allowUnstable)
+}
+
+func getDebugVerifyRate() int {
+	rate := params.GetServerConfig().DebugVerifyRate
+	if rate <= 0 {
+		rate = defaultDebugVerifyRate
+	}
+	return rate
+}
+
+// allowDebugVerify allow at most 'DebugVerifyRate' public calls per second
+func allowDebugVerify() bool {
+	now := time.Now().Unix()
+	debugVerifyWindowLock.Lock()
+	defer debugVerifyWindowLock.Unlock()
+	if now != debugVerifyWindow {
+		debugVerifyWindow = now
+		debugVerifyCount = 0
+	}
+	if debugVerifyCount >= getDebugVerifyRate() {
+		return false
+	}
+	debugVerifyCount++
+	return true
+}
@@ -0,0 +1,199 @@
+package swapapi
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// SwapDirection direction of swap, the swapin and swapout variants of apis
+// delegate to the functions of this file, so behavior is defined once.
+type SwapDirection bool
+
+// swap directions
+const (
+	SwapinDirection  SwapDirection = true
+	SwapoutDirection SwapDirection = false
+)
+
+// swap direction names
+const (
+	DirectionSwapin  = "swapin"
+	DirectionSwapout = "swapout"
+)
+
+// IsSwapin is swapin direction
+func (d SwapDirection) IsSwapin() bool {
+	return bool(d)
+}
+
+func (d SwapDirection) String() string {
+	if d.IsSwapin() {
+		return DirectionSwapin
+	}
+	return DirectionSwapout
+}
+
+// bridge of the chain where the deposit or burn tx is
+func (d SwapDirection) bridge() tokens.CrossChainBridge {
+	return tokens.GetCrossChainBridge(d.IsSwapin())
+}
+
+func (d SwapDirection) txType() tokens.SwapTxType {
+	if d.IsSwapin() {
+		return tokens.SwapinTx
+	}
+	return tokens.SwapoutTx
+}
+
+func (d SwapDirection) registerMethod() string {
+	if d.IsSwapin() {
+		return RegisterMethodSwapin
+	}
+	return RegisterMethodSwapout
+}
+
+// swapStore swap storage of both directions
+type swapStore interface {
+	FindSwap(isSwapin bool, txid, pairID, bind string) (*mongodb.MgoSwap, error)
+	FindSwapResult(isSwapin bool, txid, pairID, bind string) (*mongodb.MgoSwapResult, error)
+	FindSwapResults(isSwapin bool, addresses []string, pairID string, offset, limit int, status string, fromTime, toTime int64) ([]*mongodb.MgoSwapResult, error)
+	FindSwapResultsAfterCursor(isSwapin bool, addresses []string, pairID string, cursor *mongodb.HistoryCursor, limit int, status string, fromTime, toTime int64) ([]*mongodb.MgoSwapResult, error)
+	CountSwapResults(isSwapin bool, addresses []string, pairID, status string, fromTime, toTime int64, estimated bool) (total int64, isEstimated bool, err error)
+	UpdateSwapStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error
+}
+
+type mgoSwapStore struct{}
+
+func (mgoSwapStore) FindSwap(isSwapin bool, txid, pairID, bind string) (*mongodb.MgoSwap, error) {
+	return mongodb.FindSwap(isSwapin, txid, pairID, bind)
+}
+
+func (mgoSwapStore) FindSwapResult(isSwapin bool, txid, pairID, bind string) (*mongodb.MgoSwapResult, error) {
+	return mongodb.FindSwapResult(isSwapin, txid, pairID, bind)
+}
+
+func (mgoSwapStore) FindSwapResults(isSwapin bool, addresses []string, pairID string, offset, limit int, status string, fromTime, toTime int64) ([]*mongodb.MgoSwapResult, error) {
+	return mongodb.FindSwapResults(isSwapin, addresses, pairID, offset, limit, status, fromTime, toTime)
+}
+
+func (mgoSwapStore) FindSwapResultsAfterCursor(isSwapin bool, addresses []string, pairID string, cursor *mongodb.HistoryCursor, limit int, status string, fromTime, toTime int64) ([]*mongodb.MgoSwapResult, error) {
+	return mongodb.FindSwapResultsAfterCursor(isSwapin, addresses, pairID, cursor, limit, status, fromTime, toTime)
+}
+
+func (mgoSwapStore) CountSwapResults(isSwapin bool, addresses []string, pairID, status string, fromTime, toTime int64, estimated bool) (total int64, isEstimated bool, err error) {
+	return mongodb.CountSwapResults(isSwapin, addresses, pairID, status, fromTime, toTime, estimated)
+}
+
+func (mgoSwapStore) UpdateSwapStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
+	return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, status, timestamp, memo)
+}
+
+var swapDataStore swapStore = mgoSwapStore{}
+
+func getRawSwap(dir SwapDirection, txid, pairID, bind string) (*Swap, error) {
+	return swapDataStore.FindSwap(dir.IsSwapin(), txid, pairID, bind)
+}
+
+func getRawSwapResult(dir SwapDirection, txid, pairID, bind string) (*SwapResult, error) {
+	return swapDataStore.FindSwapResult(dir.IsSwapin(), txid, pairID, bind)
+}
+
+// getSwap get swap result, or registered swap if it has no result yet.
+// empty bind matches the only registered swap of txid.
+func getSwap(dir SwapDirection, txid, pairID, bind string) (*SwapInfo, error) {
+	if bind == "" {
+		register, err := swapDataStore.FindSwap(dir.IsSwapin(), txid, pairID, "")
+		if errors.Is(err, mongodb.ErrSwapBindAmbiguous) {
+			return nil, err
+		}
+		if err == nil {
+			bind = register.Bind
+		}
+	}
+	var info *SwapInfo
+	if result, err := swapDataStore.FindSwapResult(dir.IsSwapin(), txid, pairID, bind); err == nil {
+		info = ConvertMgoSwapResultToSwapInfo(result)
+	} else if register, err := swapDataStore.FindSwap(dir.IsSwapin(), txid, pairID, bind); err == nil {
+		info = ConvertMgoSwapToSwapInfo(register)
+	} else {
+		return nil, mongodb.ErrSwapNotFound
+	}
+	info.Direction = dir.String()
+	return info, nil
+}
+
+// AddSwapConfirmations add deposit tx confirmations and the required
+// confirmations to swap info (verbose reply), confirmations is -1 if
+// the rpc node is unreachable.
+func AddSwapConfirmations(info *SwapInfo) {
+	dir := SwapDirection(info.Direction == DirectionSwapin)
+	info.RequiredConfirmations = tokens.GetPairStableConfirmations(info.PairID, dir.IsSwapin())
+	confirmations := int64(-1)
+	if bridge := dir.bridge(); bridge != nil {
+		if txStatus, err := bridge.GetTransactionStatus(info.TxID); err == nil {
+			confirmations = int64(txStatus.Confirmations)
+		} else {
+			log.Debug("[api] get swap tx confirmations failed", "direction", dir, "txid", info.TxID, "err", err)
+		}
+	}
+	info.TxConfirmations = &confirmations
+}
+
+// getSwapHistory address can be up to `maxHistoryAddresses` comma separated addresses
+func getSwapHistory(dir SwapDirection, address, pairID string, offset, limit int, status string, fromTime, toTime int64) ([]*SwapInfo, error) {
+	log.Debug("[api] receive get swap history", "direction", dir, "address", address, "pairID", pairID, "offset", offset, "limit", limit, "status", status, "fromTime", fromTime, "toTime", toTime)
+	addresses, err := splitHistoryAddresses(address)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkHistoryStatus(status); err != nil {
+		return nil, err
+	}
+	if err = checkHistoryTimeRange(fromTime, toTime); err != nil {
+		return nil, err
+	}
+	limit = processHistoryLimit(limit)
+	result, err := swapDataStore.FindSwapResults(dir.IsSwapin(), addresses, pairID, offset, limit, status, fromTime, toTime)
+	if err != nil {
+		return nil, err
+	}
+	swaps := tagMatchedAddresses(ConvertMgoSwapResultsToSwapInfos(result), addresses)
+	for _, swap := range swaps {
+		swap.Direction = dir.String()
+	}
+	return swaps, nil
+}
+
+// retrySwap reset retryable swap to be verified again,
+// only bridges which are NonceSetter (eth like) support retrying.
+func retrySwap(dir SwapDirection, txid, pairID string) (*PostResult, error) {
+	log.Debug("[api] retry swap", "direction", dir, "txid", txid, "pairID", pairID)
+	bridge := dir.bridge()
+	if _, ok := bridge.(tokens.NonceSetter); !ok {
+		return nil, errSwapCannotRetry
+	}
+	if err := basicCheckSwapRegister(bridge, pairID); err != nil {
+		return nil, err
+	}
+	swapInfo, err := bridge.VerifyTransaction(pairID, txid, true)
+	if err != nil {
+		return nil, newRPCError(-32099, "retry "+dir.String()+" failed! "+err.Error())
+	}
+	bind := swapInfo.Bind
+	swap, _ := swapDataStore.FindSwap(dir.IsSwapin(), txid, pairID, bind)
+	if swap == nil {
+		return nil, mongodb.ErrItemNotFound
+	}
+	if !swap.Status.CanRetry() {
+		return nil, errSwapCannotRetry
+	}
+	err = swapDataStore.UpdateSwapStatus(dir.IsSwapin(), txid, pairID, bind, mongodb.TxNotStable, time.Now().Unix(), "")
+	if err != nil {
+		return nil, err
+	}
+	return &SuccessPostResult, nil
+}
@@ -0,0 +1,325 @@
+package swapapi
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// memSwapStore in memory swap store, swapin and swapout are kept apart
+type memSwapStore struct {
+	swaps   map[bool]map[string]*mongodb.MgoSwap
+	results map[bool]map[string]*mongodb.MgoSwapResult
+}
+
+func newMemSwapStore() *memSwapStore {
+	return &memSwapStore{
+		swaps:   map[bool]map[string]*mongodb.MgoSwap{true: {}, false: {}},
+		results: map[bool]map[string]*mongodb.MgoSwapResult{true: {}, false: {}},
+	}
+}
+
+func (s *memSwapStore) FindSwap(isSwapin bool, txid, pairID, bind string) (*mongodb.MgoSwap, error) {
+	if bind == "" {
+		var found *mongodb.MgoSwap
+		for _, swap := range s.swaps[isSwapin] {
+			if swap.TxID == txid && swap.PairID == pairID {
+				if found != nil {
+					return nil, mongodb.ErrSwapBindAmbiguous
+				}
+				copied := *swap
+				found = &copied
+			}
+		}
+		if found == nil {
+			return nil, mongodb.ErrItemNotFound
+		}
+		return found, nil
+	}
+	if swap, exist := s.swaps[isSwapin][mongodb.GetSwapKey(txid, pairID, bind)]; exist {
+		copied := *swap
+		return &copied, nil
+	}
+	return nil, mongodb.ErrItemNotFound
+}
+
+func (s *memSwapStore) FindSwapResult(isSwapin bool, txid, pairID, bind string) (*mongodb.MgoSwapResult, error) {
+	if result, exist := s.results[isSwapin][mongodb.GetSwapKey(txid, pairID, bind)]; exist {
+		copied := *result
+		return &copied, nil
+	}
+	return nil, mongodb.ErrItemNotFound
+}
+
+func (s *memSwapStore) FindSwapResults(isSwapin bool, addresses []string, pairID string, offset, limit int, status string, fromTime, toTime int64) ([]*mongodb.MgoSwapResult, error) {
+	var result []*mongodb.MgoSwapResult
+	for _, res := range s.results[isSwapin] {
+		copied := *res
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+// FindSwapResultsAfterCursor ascending order only
+func (s *memSwapStore) FindSwapResultsAfterCursor(isSwapin bool, addresses []string, pairID string, cursor *mongodb.HistoryCursor, limit int, status string, fromTime, toTime int64) ([]*mongodb.MgoSwapResult, error) {
+	var result []*mongodb.MgoSwapResult
+	for key, res := range s.results[isSwapin] {
+		if cursor != nil && (res.InitTime < cursor.InitTime || (res.InitTime == cursor.InitTime && key <= cursor.Key)) {
+			continue
+		}
+		copied := *res
+		copied.Key = key
+		result = append(result, &copied)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].InitTime != result[j].InitTime {
+			return result[i].InitTime < result[j].InitTime
+		}
+		return result[i].Key < result[j].Key
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (s *memSwapStore) CountSwapResults(isSwapin bool, addresses []string, pairID, status string, fromTime, toTime int64, estimated bool) (total int64, isEstimated bool, err error) {
+	return int64(len(s.results[isSwapin])), false, nil
+}
+
+func (s *memSwapStore) UpdateSwapStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
+	swap, exist := s.swaps[isSwapin][mongodb.GetSwapKey(txid, pairID, bind)]
+	if !exist {
+		return mongodb.ErrItemNotFound
+	}
+	swap.Status = status
+	return nil
+}
+
+func useMemSwapStore() (*memSwapStore, func()) {
+	memStore := newMemSwapStore()
+	swapDataStore = memStore
+	return memStore, func() { swapDataStore = mgoSwapStore{} }
+}
+
+// addMirroredSwaps add the same records to swapin and swapout
+func addMirroredSwaps(memStore *memSwapStore) {
+	for _, isSwapin := range []bool{true, false} {
+		memStore.swaps[isSwapin][mongodb.GetSwapKey("0xregistered", "pair", "bind")] = &mongodb.MgoSwap{
+			TxID: "0xregistered", PairID: "pair", Bind: "bind", Status: mongodb.TxNotStable,
+		}
+		memStore.swaps[isSwapin][mongodb.GetSwapKey("0xswapped", "pair", "bind")] = &mongodb.MgoSwap{
+			TxID: "0xswapped", PairID: "pair", Bind: "bind", Status: mongodb.TxProcessed,
+		}
+		memStore.results[isSwapin][mongodb.GetSwapKey("0xswapped", "pair", "bind")] = &mongodb.MgoSwapResult{
+			TxID: "0xswapped", PairID: "pair", Bind: "bind", From: "addr", Value: "100", SwapTx: "0xswaptx", Status: mongodb.MatchTxStable,
+		}
+	}
+}
+
+// withoutDirection clear fields which differ by direction
+func withoutDirection(info *SwapInfo) *SwapInfo {
+	if info == nil {
+		return nil
+	}
+	copied := *info
+	copied.Direction = ""
+	return &copied
+}
+
+func TestSwapinSwapoutEquivalence(t *testing.T) {
+	memStore, restore := useMemSwapStore()
+	defer restore()
+	addMirroredSwaps(memStore)
+
+	pairID, bind := "pair", "bind"
+	for _, txid := range []string{"0xregistered", "0xswapped", "0xunknown"} {
+		txid := txid
+		swapin, errin := GetSwapin(&txid, &pairID, &bind)
+		swapout, errout := GetSwapout(&txid, &pairID, &bind)
+		if errin != errout || !reflect.DeepEqual(withoutDirection(swapin), withoutDirection(swapout)) {
+			t.Errorf("%v: GetSwapin and GetSwapout differ, %+v %v, %+v %v", txid, swapin, errin, swapout, errout)
+		}
+		if swapin != nil && (swapin.Direction != DirectionSwapin || swapout.Direction != DirectionSwapout) {
+			t.Errorf("%v: wrong directions %v %v", txid, swapin.Direction, swapout.Direction)
+		}
+
+		rawin, errin := GetRawSwapin(&txid, &pairID, &bind)
+		rawout, errout := GetRawSwapout(&txid, &pairID, &bind)
+		if errin != errout || !reflect.DeepEqual(rawin, rawout) {
+			t.Errorf("%v: GetRawSwapin and GetRawSwapout differ, %+v %v, %+v %v", txid, rawin, errin, rawout, errout)
+		}
+
+		resin, errin := GetRawSwapinResult(&txid, &pairID, &bind)
+		resout, errout := GetRawSwapoutResult(&txid, &pairID, &bind)
+		if errin != errout || !reflect.DeepEqual(resin, resout) {
+			t.Errorf("%v: GetRawSwapinResult and GetRawSwapoutResult differ, %+v %v, %+v %v", txid, resin, errin, resout, errout)
+		}
+	}
+
+	for _, status := range []string{"", "MatchTxStable", "unknown"} {
+		historyin, errin := GetSwapinHistory("addr", pairID, 0, 20, status, 0, 0)
+		historyout, errout := GetSwapoutHistory("addr", pairID, 0, 20, status, 0, 0)
+		if (errin == nil) != (errout == nil) || len(historyin) != len(historyout) {
+			t.Fatalf("status %q: history differ, %v %v, %v %v", status, historyin, errin, historyout, errout)
+		}
+		for i := range historyin {
+			if !reflect.DeepEqual(withoutDirection(historyin[i]), withoutDirection(historyout[i])) {
+				t.Errorf("status %q: history item differ, %+v, %+v", status, historyin[i], historyout[i])
+			}
+			if historyin[i].Direction != DirectionSwapin || historyout[i].Direction != DirectionSwapout {
+				t.Errorf("status %q: history items have wrong directions", status)
+			}
+		}
+	}
+}
+
+func TestGetSwapBothDirections(t *testing.T) {
+	memStore, restore := useMemSwapStore()
+	defer restore()
+
+	txid, pairID, bind := "0xswapped", "pair", "bind"
+	if _, err := GetSwap(&txid, &pairID, &bind); err != mongodb.ErrSwapNotFound {
+		t.Errorf("want swap not found error, have %v", err)
+	}
+	memStore.results[false][mongodb.GetSwapKey(txid, pairID, bind)] = &mongodb.MgoSwapResult{TxID: txid, PairID: pairID, Bind: bind}
+	if res, err := GetSwap(&txid, &pairID, &bind); err != nil || len(res) != 1 || res[0].Direction != DirectionSwapout {
+		t.Errorf("want only swapout, have %v, err %v", res, err)
+	}
+	addMirroredSwaps(memStore)
+	if res, err := GetSwap(&txid, &pairID, &bind); err != nil || len(res) != 2 {
+		t.Errorf("want both directions, have %v, err %v", res, err)
+	}
+}
+
+type confirmationsBridge struct {
+	tokens.CrossChainBridge
+	confirmations uint64
+	err           error
+}
+
+func (b *confirmationsBridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return &tokens.TxStatus{Confirmations: b.confirmations}, nil
+}
+
+func TestAddSwapConfirmations(t *testing.T) {
+	oldSrcBridge, oldDstBridge := tokens.SrcBridge, tokens.DstBridge
+	oldSrcStable, oldDstStable := tokens.SrcStableConfirmations, tokens.DstStableConfirmations
+	defer func() {
+		tokens.SrcBridge, tokens.DstBridge = oldSrcBridge, oldDstBridge
+		tokens.SrcStableConfirmations, tokens.DstStableConfirmations = oldSrcStable, oldDstStable
+	}()
+	tokens.SrcBridge = &confirmationsBridge{confirmations: 4}
+	tokens.DstBridge = &confirmationsBridge{err: errors.New("connection refused")}
+	tokens.SrcStableConfirmations, tokens.DstStableConfirmations = 6, 30
+
+	swapin := &SwapInfo{TxID: "tx1", PairID: "unknownpair", Direction: DirectionSwapin}
+	AddSwapConfirmations(swapin)
+	if swapin.TxConfirmations == nil || *swapin.TxConfirmations != 4 || swapin.RequiredConfirmations != 6 {
+		t.Errorf("want swapin confirmations 4/6, have %v/%v", swapin.TxConfirmations, swapin.RequiredConfirmations)
+	}
+
+	// unreachable rpc does not fail the call
+	swapout := &SwapInfo{TxID: "tx2", PairID: "unknownpair", Direction: DirectionSwapout}
+	AddSwapConfirmations(swapout)
+	if swapout.TxConfirmations == nil || *swapout.TxConfirmations != -1 || swapout.RequiredConfirmations != 30 {
+		t.Errorf("want swapout confirmations -1/30, have %v/%v", swapout.TxConfirmations, swapout.RequiredConfirmations)
+	}
+}
+
+func TestGetAlreadyRegisteredResult(t *testing.T) {
+	memStore, restore := useMemSwapStore()
+	defer restore()
+	addMirroredSwaps(memStore)
+
+	for _, isSwapin := range []bool{true, false} {
+		for txid, want := range map[string]string{
+			"0xregistered": "already registered, status=TxNotStable",
+			"0xswapped":    "already registered, status=MatchTxStable",
+			"0xunknown":    "already registered",
+		} {
+			if have := getAlreadyRegisteredResult(isSwapin, txid, "pair", "bind"); string(*have) != want {
+				t.Errorf("%v: want %q, have %q", txid, want, *have)
+			}
+		}
+	}
+}
+
+func TestGetSwapWithoutBind(t *testing.T) {
+	memStore, restore := useMemSwapStore()
+	defer restore()
+	addMirroredSwaps(memStore)
+	memStore.swaps[true][mongodb.GetSwapKey("0xregistered", "pair", "bind2")] = &mongodb.MgoSwap{
+		TxID: "0xregistered", PairID: "pair", Bind: "bind2", Status: mongodb.TxNotStable,
+	}
+
+	info, err := getSwap(SwapinDirection, "0xswapped", "pair", "")
+	if err != nil || info.Bind != "bind" || info.Status != mongodb.MatchTxStable {
+		t.Errorf("want the only swap of txid, have %+v %v", info, err)
+	}
+	if _, err = getSwap(SwapinDirection, "0xregistered", "pair", ""); !errors.Is(err, mongodb.ErrSwapBindAmbiguous) {
+		t.Errorf("want ambiguous bind error, have %v", err)
+	}
+	if info, err = getSwap(SwapinDirection, "0xregistered", "pair", "bind2"); err != nil || info.Bind != "bind2" {
+		t.Errorf("want swap of specified bind, have %+v %v", info, err)
+	}
+	if _, err = getSwap(SwapinDirection, "0xunknown", "pair", ""); !errors.Is(err, mongodb.ErrSwapNotFound) {
+		t.Errorf("want not found error, have %v", err)
+	}
+}
+
+type retryBridge struct {
+	tokens.CrossChainBridge
+	tokens.NonceSetter
+	bind string
+}
+
+func (b *retryBridge) GetTokenConfig(pairID string) *tokens.TokenConfig {
+	return &tokens.TokenConfig{}
+}
+
+func (b *retryBridge) VerifyTransaction(pairID, txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+	return &tokens.TxSwapInfo{PairID: pairID, Hash: txHash, Bind: b.bind}, nil
+}
+
+func TestRetrySwapout(t *testing.T) {
+	memStore, restore := useMemSwapStore()
+	defer restore()
+	oldSrcBridge, oldDstBridge := tokens.SrcBridge, tokens.DstBridge
+	defer func() { tokens.SrcBridge, tokens.DstBridge = oldSrcBridge, oldDstBridge }()
+	tokens.SrcBridge = &confirmationsBridge{} // not a nonce setter
+	tokens.DstBridge = &retryBridge{bind: "bind"}
+
+	txid, pairID := "0xtx", "pair"
+	key := mongodb.GetSwapKey(txid, pairID, "bind")
+	if _, err := RetrySwapout(&txid, &pairID); err != mongodb.ErrItemNotFound {
+		t.Errorf("want not found error, have %v", err)
+	}
+	memStore.swaps[false][key] = &mongodb.MgoSwap{TxID: txid, PairID: pairID, Bind: "bind", Status: mongodb.TxVerifyFailed}
+	if _, err := RetrySwapout(&txid, &pairID); err != errSwapCannotRetry {
+		t.Errorf("want cannot retry error, have %v", err)
+	}
+	memStore.swaps[false][key].Status = mongodb.TxSenderNotRegistered
+	if _, err := RetrySwapout(&txid, &pairID); err != nil {
+		t.Fatalf("retry swapout failed, %v", err)
+	}
+	if status := memStore.swaps[false][key].Status; status != mongodb.TxNotStable {
+		t.Errorf("want status TxNotStable after retry, have %v", status)
+	}
+
+	// swapin is not retried by source bridge which can not set nonce
+	memStore.swaps[true][key] = &mongodb.MgoSwap{TxID: txid, PairID: pairID, Bind: "bind", Status: mongodb.TxSenderNotRegistered}
+	if _, err := RetrySwapin(&txid, &pairID); err != errSwapCannotRetry {
+		t.Errorf("want cannot retry error of non nonce setter source, have %v", err)
+	}
+	if status := memStore.swaps[true][key].Status; status != mongodb.TxSenderNotRegistered {
+		t.Errorf("swapin should not be changed, have %v", status)
+	}
+}
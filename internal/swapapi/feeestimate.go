@@ -0,0 +1,99 @@
+package swapapi
+
+import (
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+var (
+	errFeeEstimateDirection = newRPCError(-32048, "fee estimate direction must be swapin or swapout")
+	errFeeEstimateValue     = newRPCError(-32047, "wrong value, should be positive integer in smallest unit")
+)
+
+// SwapFeeEstimate fee quote of a prospective swap,
+// values are in smallest unit of deposit chain except output value.
+type SwapFeeEstimate struct {
+	PairID            string `json:"pairid"`
+	Direction         string `json:"direction"`
+	InputValue        string `json:"inputValue"`
+	Fee               string `json:"fee"`
+	OutputValue       string `json:"outputValue"` // in smallest unit of payout chain
+	MinSwap           string `json:"minSwap"`
+	MaxSwap           string `json:"maxSwap"`
+	BigValueThreshold string `json:"bigValueThreshold"`
+	BelowMinimum      bool   `json:"belowMinimum"`
+	AboveMaximum      bool   `json:"aboveMaximum"`
+	NotEnoughForFee   bool   `json:"notEnoughForFee"`
+	IsBigValue        bool   `json:"isBigValue"`
+	DustThreshold     string `json:"dustThreshold,omitempty"` // in smallest unit of payout chain, only for swapout to chain with dust rule
+	IsDust            bool   `json:"isDust"`
+}
+
+// GetSwapFeeEstimate api, quote fee and received value of a prospective swap
+// with the same math used by the workers. Out of range values are flagged
+// instead of rejected, their output value is what it would be if in range.
+// optional bind address of swapout is used to check the dust threshold.
+func GetSwapFeeEstimate(pairID, direction, value, bind string) (*SwapFeeEstimate, error) {
+	log.Debug("[api] receive GetSwapFeeEstimate", "pairID", pairID, "direction", direction, "value", value, "bind", bind)
+	var isSwapin bool
+	switch direction {
+	case DirectionSwapin:
+		isSwapin = true
+	case DirectionSwapout:
+	default:
+		return nil, errFeeEstimateDirection
+	}
+	token, cpToken := tokens.GetTokenConfigsByDirection(pairID, isSwapin)
+	if token == nil || cpToken == nil {
+		return nil, errTokenPairNotExist
+	}
+	inputValue, ok := new(big.Int).SetString(value, 10)
+	if !ok || inputValue.Sign() <= 0 {
+		return nil, errFeeEstimateValue
+	}
+
+	result := &SwapFeeEstimate{
+		PairID:     pairID,
+		Direction:  direction,
+		InputValue: inputValue.String(),
+	}
+	minSwap, maxSwap := tokens.GetSwapValueRange(pairID, isSwapin)
+	result.MinSwap = bigIntString(minSwap)
+	result.MaxSwap = bigIntString(maxSwap)
+	result.BelowMinimum = minSwap != nil && inputValue.Cmp(minSwap) < 0
+	result.AboveMaximum = maxSwap != nil && inputValue.Cmp(maxSwap) > 0
+	result.BigValueThreshold = bigIntString(tokens.GetBigValueThreshold(pairID, isSwapin))
+	result.IsBigValue = tokens.CheckBigValue(pairID, inputValue, isSwapin, "", "").IsBigValue
+
+	fee := tokens.CalcSwapFee(pairID, inputValue, isSwapin)
+	result.Fee = fee.String()
+
+	var outputValue *big.Int
+	switch {
+	case inputValue.Cmp(fee) <= 0 && fee.Sign() > 0:
+		result.NotEnoughForFee = true
+		outputValue = big.NewInt(0)
+	case result.BelowMinimum || result.AboveMaximum:
+		outputValue = tokens.ConvertTokenValue(new(big.Int).Sub(inputValue, fee), *token.Decimals, *cpToken.Decimals)
+	default:
+		outputValue = tokens.CalcSwappedValue(pairID, inputValue, isSwapin, "", "")
+	}
+	result.OutputValue = outputValue.String()
+
+	if !isSwapin && bind != "" {
+		if threshold := tokens.GetSwapoutDustThreshold(bind); threshold != nil {
+			result.DustThreshold = threshold.String()
+			result.IsDust = outputValue.Cmp(threshold) < 0
+		}
+	}
+	return result, nil
+}
+
+func bigIntString(value *big.Int) string {
+	if value == nil {
+		return ""
+	}
+	return value.String()
+}
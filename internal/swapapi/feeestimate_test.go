@@ -0,0 +1,114 @@
+package swapapi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func TestGetSwapFeeEstimate(t *testing.T) {
+	decimals8 := uint8(8)
+	feeRate, minFee, maxFee := 0.001, 0.01, 0.1
+	minSwap, maxSwap, bigValue := 1.0, 100.0, 50.0
+	newToken := func() *tokens.TokenConfig {
+		token := &tokens.TokenConfig{
+			Decimals:          &decimals8,
+			SwapFeeRate:       &feeRate,
+			MinimumSwapFee:    &minFee,
+			MaximumSwapFee:    &maxFee,
+			MinimumSwap:       &minSwap,
+			MaximumSwap:       &maxSwap,
+			BigValueThreshold: &bigValue,
+		}
+		token.CalcAndStoreValue()
+		return token
+	}
+	tokens.SetTokenPairsConfig(map[string]*tokens.TokenPairConfig{
+		"btc": {PairID: "btc", SrcToken: newToken(), DestToken: newToken()},
+	}, false)
+	defer tokens.SetTokenPairsConfig(map[string]*tokens.TokenPairConfig{}, false)
+
+	cases := []struct {
+		value        int64
+		fee          int64
+		output       int64
+		belowMinimum bool
+		aboveMaximum bool
+		isBigValue   bool
+	}{
+		{1e9, 1e6, 999e6, false, false, false},     // min fee
+		{6e9, 6e6, 6e9 - 6e6, false, false, true},  // fee by rate, big value
+		{5e7, 1e6, 49e6, true, false, false},       // below minimum
+		{2e10, 1e7, 2e10 - 1e7, false, true, true}, // max fee, above maximum
+		{1e6, 1e6, 0, true, false, false},          // not enough for fee
+	}
+	for _, c := range cases {
+		value := big.NewInt(c.value).String()
+		res, err := GetSwapFeeEstimate("btc", DirectionSwapin, value, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Fee != big.NewInt(c.fee).String() || res.OutputValue != big.NewInt(c.output).String() ||
+			res.BelowMinimum != c.belowMinimum || res.AboveMaximum != c.aboveMaximum || res.IsBigValue != c.isBigValue {
+			t.Errorf("value %v have %+v", value, res)
+		}
+		if !c.belowMinimum && !c.aboveMaximum {
+			if swapped := tokens.CalcSwappedValue("btc", big.NewInt(c.value), true, "", ""); res.OutputValue != swapped.String() {
+				t.Errorf("value %v output %v differs from swapped value %v", value, res.OutputValue, swapped)
+			}
+		}
+	}
+
+	if _, err := GetSwapFeeEstimate("btc", "both", "1", ""); err != errFeeEstimateDirection {
+		t.Errorf("want %v, have %v", errFeeEstimateDirection, err)
+	}
+	if _, err := GetSwapFeeEstimate("btc", DirectionSwapout, "0.5", ""); err != errFeeEstimateValue {
+		t.Errorf("want %v, have %v", errFeeEstimateValue, err)
+	}
+	if _, err := GetSwapFeeEstimate("eth", DirectionSwapout, "1", ""); err != errTokenPairNotExist {
+		t.Errorf("want %v, have %v", errTokenPairNotExist, err)
+	}
+
+	// swapout to chain with dust rule
+	oldSrcBridge := tokens.SrcBridge
+	tokens.SrcBridge = &dustBridge{threshold: 546 * 1e4}
+	defer func() { tokens.SrcBridge = oldSrcBridge }()
+	for _, c := range []struct {
+		value  int64
+		bind   string
+		isDust bool
+	}{
+		{5e7, "bind", false},      // output 49e6
+		{6e6, "bind", true},       // output 5e6
+		{6e6, "", false},          // no bind, no dust check
+		{6e6, "wrongaddr", false}, // threshold is unknown
+	} {
+		res, err := GetSwapFeeEstimate("btc", DirectionSwapout, big.NewInt(c.value).String(), c.bind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantThreshold := "5460000"
+		if c.bind != "bind" {
+			wantThreshold = ""
+		}
+		if res.IsDust != c.isDust || res.DustThreshold != wantThreshold {
+			t.Errorf("value %v bind %q have dust %v threshold %q", c.value, c.bind, res.IsDust, res.DustThreshold)
+		}
+	}
+	if res, _ := GetSwapFeeEstimate("btc", DirectionSwapin, "6000000", "bind"); res.IsDust || res.DustThreshold != "" {
+		t.Errorf("swapin should not check dust, have %+v", res)
+	}
+}
+
+type dustBridge struct {
+	tokens.CrossChainBridge
+	threshold uint64
+}
+
+func (b *dustBridge) GetDustThreshold(address string) (uint64, error) {
+	if address != "bind" {
+		return 0, tokens.ErrWrongMemoBindAddress
+	}
+	return b.threshold, nil
+}
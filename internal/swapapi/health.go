@@ -0,0 +1,199 @@
+package swapapi
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/dcrm"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+var (
+	errHealthCheckTimeout = errors.New("health check timeout")
+	errNoMongodbClient    = errors.New("mongodb is not connected")
+)
+
+const (
+	defaultScanStaleTime = 600             // seconds
+	chainStatusCacheTTL  = 5 * time.Second // protect nodes from dashboard polling
+)
+
+var (
+	chainStatusCache     [2]*ChainHealth // src and dst chain
+	chainStatusCacheTime time.Time
+	chainStatusCacheLock sync.Mutex
+)
+
+// health checks, replaced in tests
+var (
+	healthCheckTimeout = 5 * time.Second
+
+	getLiveLatestBlock = func(isSrc bool) (uint64, error) {
+		return tokens.GetCrossChainBridge(isSrc).GetLatestBlockNumber()
+	}
+	getLatestScanInfo = func(isSrc bool) (*mongodb.MgoLatestScanInfo, error) {
+		if !mongodb.HasClient() {
+			return nil, errNoMongodbClient
+		}
+		return mongodb.FindLatestScanInfo(isSrc)
+	}
+	pingMongodb   = mongodb.Ping
+	pingDcrm      = dcrm.PingDcrmNodes
+	isDcrmEnabled = params.IsDcrmEnabled
+)
+
+// HealthCheck result of one check
+type HealthCheck struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// ChainHealth health of chain, scan lag is valid if both heights are ok,
+// scan is active if scanned height is updated within 'ScanStaleTime' of chain config
+type ChainHealth struct {
+	LatestBlock   uint64       `json:"latestBlock"`
+	LatestScanned uint64       `json:"latestScanned"`
+	ScanLag       uint64       `json:"scanLag"`
+	LastScanTime  int64        `json:"lastScanTime"`
+	ScanActive    bool         `json:"scanActive"`
+	Node          *HealthCheck `json:"node"`
+	ScanInfo      *HealthCheck `json:"scanInfo"`
+}
+
+// DcrmHealth health of dcrm, reachable check is skipped if dcrm is disabled
+type DcrmHealth struct {
+	Enabled   bool         `json:"enabled"`
+	Reachable *HealthCheck `json:"reachable,omitempty"`
+}
+
+// HealthInfo health of bridge, OK is true if all checks are ok
+type HealthInfo struct {
+	OK        bool         `json:"ok"`
+	SrcChain  *ChainHealth `json:"srcChain"`
+	DestChain *ChainHealth `json:"destChain"`
+	Mongodb   *HealthCheck `json:"mongodb"`
+	Dcrm      *DcrmHealth  `json:"dcrm"`
+	CheckedAt int64        `json:"checkedAt"`
+}
+
+// runHealthCheck run check with timeout, the check is left running if timeout
+func runHealthCheck(check func() (uint64, error)) (uint64, *HealthCheck) {
+	type checkResult struct {
+		value uint64
+		err   error
+	}
+	resCh := make(chan checkResult, 1)
+	go func() {
+		value, err := check()
+		resCh <- checkResult{value: value, err: err}
+	}()
+	select {
+	case res := <-resCh:
+		if res.err != nil {
+			return 0, &HealthCheck{Error: res.err.Error()}
+		}
+		return res.value, &HealthCheck{OK: true}
+	case <-time.After(healthCheckTimeout):
+		return 0, &HealthCheck{Error: errHealthCheckTimeout.Error()}
+	}
+}
+
+func getScanStaleTime(isSrc bool) int64 {
+	if bridge := tokens.GetCrossChainBridge(isSrc); bridge != nil {
+		if chainCfg := bridge.GetChainConfig(); chainCfg != nil && chainCfg.ScanStaleTime > 0 {
+			return chainCfg.ScanStaleTime
+		}
+	}
+	return defaultScanStaleTime
+}
+
+func checkChainHealth(isSrc bool) *ChainHealth {
+	chain := &ChainHealth{}
+	var lastScanTime int64 // written by check left running if timeout
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		chain.LatestBlock, chain.Node = runHealthCheck(func() (uint64, error) { return getLiveLatestBlock(isSrc) })
+	}()
+	go func() {
+		defer wg.Done()
+		chain.LatestScanned, chain.ScanInfo = runHealthCheck(func() (uint64, error) {
+			info, err := getLatestScanInfo(isSrc)
+			if err != nil {
+				return 0, err
+			}
+			atomic.StoreInt64(&lastScanTime, info.Timestamp)
+			return info.BlockHeight, nil
+		})
+	}()
+	wg.Wait()
+	if chain.Node.OK && chain.ScanInfo.OK && chain.LatestBlock > chain.LatestScanned {
+		chain.ScanLag = chain.LatestBlock - chain.LatestScanned
+	}
+	if chain.ScanInfo.OK {
+		chain.LastScanTime = atomic.LoadInt64(&lastScanTime)
+		chain.ScanActive = chain.LastScanTime+getScanStaleTime(isSrc) > time.Now().Unix()
+	}
+	return chain
+}
+
+// getChainsStatus get health of src and dst chain concurrently, cached for a few seconds
+func getChainsStatus() (src, dst *ChainHealth) {
+	chainStatusCacheLock.Lock()
+	defer chainStatusCacheLock.Unlock()
+	if chainStatusCache[0] != nil && time.Since(chainStatusCacheTime) < chainStatusCacheTTL {
+		return chainStatusCache[0], chainStatusCache[1]
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		src = checkChainHealth(true)
+	}()
+	go func() {
+		defer wg.Done()
+		dst = checkChainHealth(false)
+	}()
+	wg.Wait()
+	chainStatusCache = [2]*ChainHealth{src, dst}
+	chainStatusCacheTime = time.Now()
+	return src, dst
+}
+
+// GetHealth api, checks are run concurrently and bounded by timeout respectively
+func GetHealth() (*HealthInfo, error) {
+	result := &HealthInfo{
+		Dcrm: &DcrmHealth{Enabled: isDcrmEnabled()},
+	}
+	var wg sync.WaitGroup
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		result.SrcChain = checkChainHealth(true)
+	}()
+	go func() {
+		defer wg.Done()
+		result.DestChain = checkChainHealth(false)
+	}()
+	go func() {
+		defer wg.Done()
+		_, result.Mongodb = runHealthCheck(func() (uint64, error) { return 0, pingMongodb(healthCheckTimeout) })
+	}()
+	go func() {
+		defer wg.Done()
+		if result.Dcrm.Enabled {
+			_, result.Dcrm.Reachable = runHealthCheck(func() (uint64, error) { return 0, pingDcrm() })
+		}
+	}()
+	wg.Wait()
+	result.OK = result.SrcChain.Node.OK && result.SrcChain.ScanInfo.OK &&
+		result.DestChain.Node.OK && result.DestChain.ScanInfo.OK &&
+		result.Mongodb.OK && (!result.Dcrm.Enabled || result.Dcrm.Reachable.OK)
+	result.CheckedAt = time.Now().Unix()
+	return result, nil
+}
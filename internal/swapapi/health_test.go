@@ -0,0 +1,93 @@
+package swapapi
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+)
+
+func TestGetHealth(t *testing.T) {
+	oldTimeout, oldLatest, oldScanned := healthCheckTimeout, getLiveLatestBlock, getLatestScanInfo
+	oldPingMongodb, oldPingDcrm, oldDcrmEnabled := pingMongodb, pingDcrm, isDcrmEnabled
+	defer func() {
+		healthCheckTimeout, getLiveLatestBlock, getLatestScanInfo = oldTimeout, oldLatest, oldScanned
+		pingMongodb, pingDcrm, isDcrmEnabled = oldPingMongodb, oldPingDcrm, oldDcrmEnabled
+	}()
+
+	healthCheckTimeout = 100 * time.Millisecond
+	getLiveLatestBlock = func(isSrc bool) (uint64, error) {
+		if isSrc {
+			return 1000, nil
+		}
+		time.Sleep(time.Second) // dst node hangs
+		return 2000, nil
+	}
+	getLatestScanInfo = func(isSrc bool) (*mongodb.MgoLatestScanInfo, error) {
+		if isSrc {
+			return &mongodb.MgoLatestScanInfo{BlockHeight: 990, Timestamp: time.Now().Unix()}, nil
+		}
+		return &mongodb.MgoLatestScanInfo{BlockHeight: 1990, Timestamp: time.Now().Unix() - 2*defaultScanStaleTime}, nil
+	}
+	pingMongodb = func(timeout time.Duration) error { return nil }
+	pingDcrm = func() error { return errors.New("connection refused") }
+	isDcrmEnabled = func() bool { return true }
+
+	start := time.Now()
+	health, err := GetHealth()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("health check is not bounded by timeout, took %v", elapsed)
+	}
+	if health.OK {
+		t.Error("want not ok if dst node hangs")
+	}
+	src := health.SrcChain
+	if !src.Node.OK || !src.ScanInfo.OK || src.LatestBlock != 1000 || src.LatestScanned != 990 || src.ScanLag != 10 || !src.ScanActive {
+		t.Errorf("wrong src chain health %+v", src)
+	}
+	dst := health.DestChain
+	if dst.Node.OK || dst.Node.Error != errHealthCheckTimeout.Error() || !dst.ScanInfo.OK || dst.LatestScanned != 1990 || dst.ScanLag != 0 || dst.ScanActive {
+		t.Errorf("failing dst node should not mask scan info, have %+v", dst)
+	}
+	if !health.Mongodb.OK {
+		t.Errorf("want mongodb ok, have %+v", health.Mongodb)
+	}
+	if !health.Dcrm.Enabled || health.Dcrm.Reachable == nil || health.Dcrm.Reachable.OK {
+		t.Errorf("want dcrm not reachable, have %+v", health.Dcrm.Reachable)
+	}
+}
+
+func TestGetChainsStatusCached(t *testing.T) {
+	oldLatest, oldScanned := getLiveLatestBlock, getLatestScanInfo
+	defer func() {
+		getLiveLatestBlock, getLatestScanInfo = oldLatest, oldScanned
+		chainStatusCache = [2]*ChainHealth{}
+	}()
+
+	var mu sync.Mutex
+	calls := 0
+	getLiveLatestBlock = func(isSrc bool) (uint64, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return 1000, nil
+	}
+	getLatestScanInfo = func(isSrc bool) (*mongodb.MgoLatestScanInfo, error) {
+		return &mongodb.MgoLatestScanInfo{BlockHeight: 900, Timestamp: time.Now().Unix()}, nil
+	}
+	chainStatusCache = [2]*ChainHealth{}
+
+	src, dst := getChainsStatus()
+	if src.ScanLag != 100 || dst.ScanLag != 100 || !src.ScanActive {
+		t.Errorf("wrong chains status %+v %+v", src, dst)
+	}
+	_, _ = getChainsStatus()
+	if calls != 2 {
+		t.Errorf("chains status should be cached, node queried %v times", calls)
+	}
+}
@@ -0,0 +1,156 @@
+package swapapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	rpcjson "github.com/gorilla/rpc/v2/json2"
+)
+
+func TestSplitHistoryAddresses(t *testing.T) {
+	for _, address := range []string{"", allAddresses} {
+		if addresses, err := splitHistoryAddresses(address); addresses != nil || err != nil {
+			t.Errorf("address %q should query all addresses, have %v %v", address, addresses, err)
+		}
+	}
+
+	for _, address := range []string{",", " , ,", ",,,"} {
+		if addresses, err := splitHistoryAddresses(address); !errors.Is(err, errNoHistoryAddress) {
+			t.Errorf("address %q without any address should be rejected, have %v %v", address, addresses, err)
+		}
+	}
+
+	hexAddr := "0x00000000000000000000000000000000000000Ab"
+	addresses, err := splitHistoryAddresses(" " + hexAddr + ",mfwanCuX9vvk3wHnJ3ysNBeCZ9ccQzBYCX, " + strings.ToLower(hexAddr) + ",,mfwanCuX9vvk3wHnJ3ysNBeCZ9ccQzBYCX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{strings.ToLower(hexAddr), "mfwanCuX9vvk3wHnJ3ysNBeCZ9ccQzBYCX"}
+	if !reflect.DeepEqual(addresses, want) {
+		t.Errorf("want deduplicated addresses %v, have %v", want, addresses)
+	}
+
+	many := make([]string, maxHistoryAddresses+1)
+	for i := range many {
+		many[i] = fmt.Sprintf("addr%v", i)
+	}
+	if addresses, err = splitHistoryAddresses(strings.Join(many[:maxHistoryAddresses], ",")); err != nil || len(addresses) != maxHistoryAddresses {
+		t.Errorf("want %v addresses, have %v %v", maxHistoryAddresses, len(addresses), err)
+	}
+	if _, err = splitHistoryAddresses(strings.Join(many, ",")); !errors.Is(err, errTooManyAddresses) {
+		t.Errorf("more than %v addresses should be rejected, have %v", maxHistoryAddresses, err)
+	}
+	// duplicates are not counted
+	if _, err = splitHistoryAddresses(strings.Join(append(many[:maxHistoryAddresses], many[0]), ",")); err != nil {
+		t.Errorf("duplicate addresses should not exceed the cap, have %v", err)
+	}
+}
+
+func TestTagMatchedAddresses(t *testing.T) {
+	swaps := []*SwapInfo{{From: "addr1"}, {From: "addr2"}, {From: "addr3"}}
+	tagMatchedAddresses(swaps, []string{"addr2", "addr1"})
+	for i, want := range []string{"addr1", "addr2", ""} {
+		if swaps[i].MatchedAddress != want {
+			t.Errorf("swap %v want matched address %q, have %q", i, want, swaps[i].MatchedAddress)
+		}
+	}
+
+	swaps = []*SwapInfo{{From: "addr1"}}
+	tagMatchedAddresses(swaps, nil)
+	if swaps[0].MatchedAddress != "" {
+		t.Errorf("query of all addresses should not tag, have %q", swaps[0].MatchedAddress)
+	}
+}
+
+func TestCheckHistoryStatus(t *testing.T) {
+	for _, status := range []string{"", " ", "MatchTxStable, MatchTxFailed", "9,10", "matchtxnotstable,14"} {
+		if err := checkHistoryStatus(status); err != nil {
+			t.Errorf("status %q should be valid, have %v", status, err)
+		}
+	}
+	for _, status := range []string{"Unknown", "MatchTxStable,Unknown", "9999"} {
+		err := checkHistoryStatus(status)
+		var rpcErr *rpcjson.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != -32071 {
+			t.Errorf("status %q should be rejected, have %v", status, err)
+		}
+	}
+}
+
+func TestCheckHistoryTimeRange(t *testing.T) {
+	valids := [][2]int64{{0, 0}, {100, 0}, {0, 200}, {100, 200}, {100, 100}}
+	for _, r := range valids {
+		if err := checkHistoryTimeRange(r[0], r[1]); err != nil {
+			t.Errorf("time range %v should be valid, have %v", r, err)
+		}
+	}
+	invalids := [][2]int64{{200, 100}, {-1, 0}, {0, -1}}
+	for _, r := range invalids {
+		if err := checkHistoryTimeRange(r[0], r[1]); !errors.Is(err, errHistoryTimeRange) {
+			t.Errorf("time range %v should be rejected, have %v", r, err)
+		}
+	}
+}
+
+func TestHistoryCursor(t *testing.T) {
+	cursor := &mongodb.HistoryCursor{InitTime: 1600000000123, Key: "0xtx:pair:bind"}
+	decoded, err := decodeHistoryCursor(encodeHistoryCursor(cursor))
+	if err != nil || !reflect.DeepEqual(decoded, cursor) {
+		t.Errorf("want cursor %+v, have %+v %v", cursor, decoded, err)
+	}
+	if decoded, err = decodeHistoryCursor(""); decoded != nil || err != nil {
+		t.Errorf("empty cursor should query from the first one, have %+v %v", decoded, err)
+	}
+	for _, cursorStr := range []string{"!!!", encodeHistoryCursor(&mongodb.HistoryCursor{InitTime: 1}), "MTIz"} {
+		if _, err = decodeHistoryCursor(cursorStr); !errors.Is(err, errWrongHistoryCursor) {
+			t.Errorf("cursor %q should be rejected, have %v", cursorStr, err)
+		}
+	}
+}
+
+func TestSwapHistoryPage(t *testing.T) {
+	params.SetConfig(&params.BridgeConfig{Server: &params.ServerConfig{}})
+	memStore, restore := useMemSwapStore()
+	defer restore()
+	// same init time of some swaps are ordered by key
+	for i, initTime := range []int64{100, 200, 200, 200, 300} {
+		txid := fmt.Sprintf("0xtx%v", i)
+		memStore.results[true][mongodb.GetSwapKey(txid, "pair", "bind")] = &mongodb.MgoSwapResult{
+			TxID: txid, PairID: "pair", Bind: "bind", InitTime: initTime,
+		}
+	}
+
+	var txids []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatalf("too many pages")
+		}
+		page, err := GetSwapinHistoryPage(allAddresses, "pair", cursor, 2, "", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != 5 || page.TotalEstimated {
+			t.Errorf("want total 5, have %v estimated %v", page.Total, page.TotalEstimated)
+		}
+		for _, item := range page.Items {
+			txids = append(txids, item.TxID)
+			if item.Direction != DirectionSwapin {
+				t.Errorf("want swapin direction, have %v", item.Direction)
+			}
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	want := []string{"0xtx0", "0xtx1", "0xtx2", "0xtx3", "0xtx4"}
+	if !reflect.DeepEqual(txids, want) {
+		t.Errorf("want all swaps in order %v, have %v", want, txids)
+	}
+}
@@ -0,0 +1,98 @@
+package swapapi
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+var errWrongHistoryCursor = newRPCError(-32064, "wrong history cursor")
+
+// SwapHistoryPage page of swap history, pass nextCursor to query the next page,
+// nextCursor is empty if there is no more pages.
+type SwapHistoryPage struct {
+	Items          []*SwapInfo `json:"items"`
+	NextCursor     string      `json:"nextCursor"`
+	Total          int64       `json:"total"`
+	TotalEstimated bool        `json:"totalEstimated,omitempty"`
+}
+
+// encodeHistoryCursor cursor is opaque to callers
+func encodeHistoryCursor(cursor *mongodb.HistoryCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(cursor.InitTime, 10) + ":" + cursor.Key))
+}
+
+func decodeHistoryCursor(cursorStr string) (*mongodb.HistoryCursor, error) {
+	if cursorStr == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursorStr)
+	if err != nil {
+		return nil, errWrongHistoryCursor
+	}
+	parts := strings.SplitN(string(data), ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, errWrongHistoryCursor
+	}
+	initTime, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, errWrongHistoryCursor
+	}
+	return &mongodb.HistoryCursor{InitTime: initTime, Key: parts[1]}, nil
+}
+
+// getSwapHistoryPage query history after cursor with a range scan,
+// other params are the same as getSwapHistory.
+func getSwapHistoryPage(dir SwapDirection, address, pairID, cursorStr string, limit int, status string, fromTime, toTime int64) (*SwapHistoryPage, error) {
+	log.Debug("[api] receive get swap history page", "direction", dir, "address", address, "pairID", pairID, "cursor", cursorStr, "limit", limit, "status", status, "fromTime", fromTime, "toTime", toTime)
+	addresses, err := splitHistoryAddresses(address)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkHistoryStatus(status); err != nil {
+		return nil, err
+	}
+	if err = checkHistoryTimeRange(fromTime, toTime); err != nil {
+		return nil, err
+	}
+	cursor, err := decodeHistoryCursor(cursorStr)
+	if err != nil {
+		return nil, err
+	}
+	limit = processHistoryLimit(limit)
+	result, err := swapDataStore.FindSwapResultsAfterCursor(dir.IsSwapin(), addresses, pairID, cursor, limit, status, fromTime, toTime)
+	if err != nil {
+		return nil, err
+	}
+	total, isEstimated, err := swapDataStore.CountSwapResults(dir.IsSwapin(), addresses, pairID, status, fromTime, toTime, params.GetServerConfig().EstimateHistoryTotal)
+	if err != nil {
+		return nil, err
+	}
+	page := &SwapHistoryPage{
+		Total:          total,
+		TotalEstimated: isEstimated,
+	}
+	if pageSize := len(result); pageSize > 0 && (pageSize == limit || pageSize == -limit) {
+		last := result[pageSize-1]
+		page.NextCursor = encodeHistoryCursor(&mongodb.HistoryCursor{InitTime: last.InitTime, Key: last.Key})
+	}
+	page.Items = tagMatchedAddresses(ConvertMgoSwapResultsToSwapInfos(result), addresses)
+	for _, swap := range page.Items {
+		swap.Direction = dir.String()
+	}
+	return page, nil
+}
+
+// GetSwapinHistoryPage api, cursor based pagination of GetSwapinHistory
+func GetSwapinHistoryPage(address, pairID, cursor string, limit int, status string, fromTime, toTime int64) (*SwapHistoryPage, error) {
+	return getSwapHistoryPage(SwapinDirection, address, pairID, cursor, limit, status, fromTime, toTime)
+}
+
+// GetSwapoutHistoryPage api, cursor based pagination of GetSwapoutHistory
+func GetSwapoutHistoryPage(address, pairID, cursor string, limit int, status string, fromTime, toTime int64) (*SwapHistoryPage, error) {
+	return getSwapHistoryPage(SwapoutDirection, address, pairID, cursor, limit, status, fromTime, toTime)
+}
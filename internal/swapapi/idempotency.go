@@ -0,0 +1,141 @@
+package swapapi
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+)
+
+// methods supporting idempotency key
+const (
+	IdempotentMethodSwapin     = "swapin"
+	IdempotentMethodSwapout    = "swapout"
+	IdempotentMethodP2shSwapin = "p2shswapin"
+)
+
+const maxIdempotencyKeyLength = 128
+
+var (
+	errIdempotencyKeyTooLong = newRPCError(-32088, "idempotency key is too long")
+	errIdempotencyKeyReused  = newRPCError(-32087, "idempotency key is reused with different request")
+	errIdempotentInProgress  = newRPCError(-32098, "request with the same idempotency key is in progress, retry later")
+
+	idempotencyLocks    = make(map[string]*idempotencyLock)
+	idempotencyLocksMtx sync.Mutex
+
+	idempotentStore idempotentResponseStore = mgoIdempotentStore{}
+)
+
+// idempotentResponseStore storage of idempotent response snapshots
+type idempotentResponseStore interface {
+	FindIdempotentResponse(method, idempotencyKey string) (*mongodb.MgoIdempotentResponse, error)
+	ReserveIdempotencyKey(method, idempotencyKey, request string) error
+	CompleteIdempotentResponse(method, idempotencyKey, response string) error
+	ReleaseIdempotencyKey(method, idempotencyKey string) error
+}
+
+type mgoIdempotentStore struct{}
+
+func (mgoIdempotentStore) FindIdempotentResponse(method, idempotencyKey string) (*mongodb.MgoIdempotentResponse, error) {
+	return mongodb.FindIdempotentResponse(method, idempotencyKey)
+}
+
+func (mgoIdempotentStore) ReserveIdempotencyKey(method, idempotencyKey, request string) error {
+	return mongodb.ReserveIdempotencyKey(method, idempotencyKey, request)
+}
+
+func (mgoIdempotentStore) CompleteIdempotentResponse(method, idempotencyKey, response string) error {
+	return mongodb.CompleteIdempotentResponse(method, idempotencyKey, response)
+}
+
+func (mgoIdempotentStore) ReleaseIdempotencyKey(method, idempotencyKey string) error {
+	return mongodb.ReleaseIdempotencyKey(method, idempotencyKey)
+}
+
+type idempotencyLock struct {
+	sync.Mutex
+	refs int
+}
+
+// lockIdempotencyKey serialize concurrent requests with the same key,
+// the lock is in memory and only works in the same server process.
+func lockIdempotencyKey(key string) (unlock func()) {
+	idempotencyLocksMtx.Lock()
+	lock, exist := idempotencyLocks[key]
+	if !exist {
+		lock = &idempotencyLock{}
+		idempotencyLocks[key] = lock
+	}
+	lock.refs++
+	idempotencyLocksMtx.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		idempotencyLocksMtx.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(idempotencyLocks, key)
+		}
+		idempotencyLocksMtx.Unlock()
+	}
+}
+
+// CallIdempotent call mutation api with optional client generated idempotency key.
+// the key is reserved in database before calling the api, so that servers sharing
+// a database call the api once for the same key (concurrent requests in this process
+// are serialized in memory beforehand). the successful response is stored and returned
+// for repeated requests with the same key, failed requests release the key to be retried.
+func CallIdempotent(method, idempotencyKey, request string, call func() (*PostResult, error)) (*PostResult, error) {
+	if idempotencyKey == "" {
+		return call()
+	}
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return nil, errIdempotencyKeyTooLong
+	}
+	unlock := lockIdempotencyKey(mongodb.GetIdempotentResponseKey(method, idempotencyKey))
+	defer unlock()
+
+	stored, err := reserveIdempotencyKey(method, idempotencyKey, request)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		if stored.Request != request {
+			return nil, errIdempotencyKeyReused
+		}
+		if stored.Pending {
+			return nil, errIdempotentInProgress
+		}
+		log.Info("[api] return stored idempotent response", "method", method, "idempotencyKey", idempotencyKey)
+		result := PostResult(stored.Response)
+		return &result, nil
+	}
+
+	result, err := call()
+	if err == nil && result != nil {
+		_ = idempotentStore.CompleteIdempotentResponse(method, idempotencyKey, string(*result))
+	} else {
+		_ = idempotentStore.ReleaseIdempotencyKey(method, idempotencyKey)
+	}
+	return result, err
+}
+
+// reserveIdempotencyKey return nil if the key is reserved by this call,
+// or the stored response or reservation if the key is already used.
+func reserveIdempotencyKey(method, idempotencyKey, request string) (*mongodb.MgoIdempotentResponse, error) {
+	for i := 0; i < 2; i++ {
+		err := idempotentStore.ReserveIdempotencyKey(method, idempotencyKey, request)
+		if !errors.Is(err, mongodb.ErrItemIsDup) {
+			return nil, err
+		}
+		stored, err := idempotentStore.FindIdempotentResponse(method, idempotencyKey)
+		if errors.Is(err, mongodb.ErrItemNotFound) {
+			continue // expired and removed, reserve again
+		}
+		return stored, err
+	}
+	return nil, errIdempotentInProgress
+}
@@ -0,0 +1,229 @@
+package swapapi
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+)
+
+// memIdempotentStore in memory idempotent response store
+type memIdempotentStore struct {
+	lock      sync.Mutex
+	responses map[string]*mongodb.MgoIdempotentResponse
+}
+
+func useMemIdempotentStore(t *testing.T) *memIdempotentStore {
+	store := &memIdempotentStore{responses: make(map[string]*mongodb.MgoIdempotentResponse)}
+	oldStore := idempotentStore
+	idempotentStore = store
+	t.Cleanup(func() { idempotentStore = oldStore })
+	return store
+}
+
+func (s *memIdempotentStore) FindIdempotentResponse(method, idempotencyKey string) (*mongodb.MgoIdempotentResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	stored, exist := s.responses[mongodb.GetIdempotentResponseKey(method, idempotencyKey)]
+	if !exist {
+		return nil, mongodb.ErrItemNotFound
+	}
+	return stored, nil
+}
+
+func (s *memIdempotentStore) ReserveIdempotencyKey(method, idempotencyKey, request string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := mongodb.GetIdempotentResponseKey(method, idempotencyKey)
+	if _, exist := s.responses[key]; exist {
+		return mongodb.ErrItemIsDup
+	}
+	s.responses[key] = &mongodb.MgoIdempotentResponse{Key: key, Method: method, IdempotencyKey: idempotencyKey, Request: request, Pending: true}
+	return nil
+}
+
+func (s *memIdempotentStore) CompleteIdempotentResponse(method, idempotencyKey, response string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	stored, exist := s.responses[mongodb.GetIdempotentResponseKey(method, idempotencyKey)]
+	if !exist {
+		return mongodb.ErrItemNotFound
+	}
+	copied := *stored
+	copied.Response = response
+	copied.Pending = false
+	s.responses[copied.Key] = &copied
+	return nil
+}
+
+func (s *memIdempotentStore) ReleaseIdempotencyKey(method, idempotencyKey string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := mongodb.GetIdempotentResponseKey(method, idempotencyKey)
+	if stored, exist := s.responses[key]; exist && stored.Pending {
+		delete(s.responses, key)
+	}
+	return nil
+}
+
+func newCountingCall(calls *int, result string, err error) func() (*PostResult, error) {
+	return func() (*PostResult, error) {
+		*calls++
+		if err != nil {
+			return nil, err
+		}
+		res := PostResult(result)
+		return &res, nil
+	}
+}
+
+func TestCallIdempotentReplayStoredResponse(t *testing.T) {
+	useMemIdempotentStore(t)
+	calls := 0
+	call := newCountingCall(&calls, "first", nil)
+
+	for i := 0; i < 3; i++ {
+		res, err := CallIdempotent(IdempotentMethodSwapin, "key", "request", call)
+		if err != nil || res == nil || *res != "first" {
+			t.Fatalf("call %v: want stored response, have %v %v", i, res, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("repeated requests should return stored response, have %v calls", calls)
+	}
+
+	// without key every request calls the api
+	for i := 0; i < 2; i++ {
+		_, _ = CallIdempotent(IdempotentMethodSwapin, "", "request", call)
+	}
+	if calls != 3 {
+		t.Errorf("requests without key should not be stored, have %v calls", calls)
+	}
+}
+
+func TestCallIdempotentFailureNotStored(t *testing.T) {
+	useMemIdempotentStore(t)
+	calls := 0
+	callErr := errors.New("verify failed")
+	if _, err := CallIdempotent(IdempotentMethodSwapin, "key", "request", newCountingCall(&calls, "", callErr)); !errors.Is(err, callErr) {
+		t.Fatalf("want call error, have %v", err)
+	}
+	res, err := CallIdempotent(IdempotentMethodSwapin, "key", "request", newCountingCall(&calls, "retried", nil))
+	if err != nil || *res != "retried" || calls != 2 {
+		t.Errorf("failed request should be retried, have %v %v after %v calls", res, err, calls)
+	}
+}
+
+func TestCallIdempotentKeyReuse(t *testing.T) {
+	useMemIdempotentStore(t)
+	calls := 0
+	if _, err := CallIdempotent(IdempotentMethodSwapin, "key", "request1", newCountingCall(&calls, "first", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CallIdempotent(IdempotentMethodSwapin, "key", "request2", newCountingCall(&calls, "second", nil)); !errors.Is(err, errIdempotencyKeyReused) {
+		t.Errorf("key reused with different request should fail, have %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("reused key should not call the api, have %v calls", calls)
+	}
+
+	if _, err := CallIdempotent(IdempotentMethodSwapin, string(make([]byte, maxIdempotencyKeyLength+1)), "request", newCountingCall(&calls, "", nil)); !errors.Is(err, errIdempotencyKeyTooLong) {
+		t.Errorf("too long key should fail, have %v", err)
+	}
+}
+
+func TestCallIdempotentKeyScopedByMethod(t *testing.T) {
+	useMemIdempotentStore(t)
+	calls := 0
+	for _, method := range []string{IdempotentMethodSwapin, IdempotentMethodSwapout, IdempotentMethodP2shSwapin} {
+		res, err := CallIdempotent(method, "key", "request", newCountingCall(&calls, method, nil))
+		if err != nil || *res != PostResult(method) {
+			t.Errorf("same key of %v should call the api, have %v %v", method, res, err)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("same key across methods should be independent, have %v calls", calls)
+	}
+}
+
+func TestCallIdempotentConcurrentDuplicates(t *testing.T) {
+	useMemIdempotentStore(t)
+	var (
+		lock    sync.Mutex
+		calls   int
+		running int
+		overlap bool
+	)
+	call := func() (*PostResult, error) {
+		lock.Lock()
+		calls++
+		running++
+		overlap = overlap || running > 1
+		lock.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		lock.Lock()
+		running--
+		lock.Unlock()
+		return &SuccessPostResult, nil
+	}
+
+	const concurrency = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrency)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := CallIdempotent(IdempotentMethodSwapout, "key", "request", call)
+			if err == nil && *res != SuccessPostResult {
+				err = errors.New("wrong response " + string(*res))
+			}
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if calls != 1 || overlap {
+		t.Errorf("concurrent duplicates should be serialized and call once, have %v calls, overlap %v", calls, overlap)
+	}
+	idempotencyLocksMtx.Lock()
+	defer idempotencyLocksMtx.Unlock()
+	if len(idempotencyLocks) != 0 {
+		t.Errorf("idempotency locks should be released, have %v", len(idempotencyLocks))
+	}
+}
+
+func TestCallIdempotentReservedByOtherServer(t *testing.T) {
+	store := useMemIdempotentStore(t)
+	calls := 0
+	call := newCountingCall(&calls, "mine", nil)
+
+	// another server sharing the database has reserved the key and is calling the api
+	if err := store.ReserveIdempotencyKey(IdempotentMethodSwapin, "key", "request"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CallIdempotent(IdempotentMethodSwapin, "key", "request", call); !errors.Is(err, errIdempotentInProgress) {
+		t.Errorf("want in progress error, have %v", err)
+	}
+
+	// and then stored its response
+	if err := store.CompleteIdempotentResponse(IdempotentMethodSwapin, "key", "other"); err != nil {
+		t.Fatal(err)
+	}
+	res, err := CallIdempotent(IdempotentMethodSwapin, "key", "request", call)
+	if err != nil || *res != "other" {
+		t.Errorf("want response stored by other server, have %v %v", res, err)
+	}
+	if calls != 0 {
+		t.Errorf("reserved key should not call the api, have %v calls", calls)
+	}
+}
@@ -0,0 +1,230 @@
+package swapapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+const (
+	maxP2shBatchSize    = 500
+	maxP2shBatchJobs    = 100
+	p2shBatchQueueSize  = 20
+	p2shListLimit       = 100
+	p2shListDefaultSize = 20
+)
+
+var (
+	errP2shBatchTooLarge = newRPCError(-32093, "too many addresses in p2sh batch")
+	errP2shBatchEmpty    = newRPCError(-32092, "empty p2sh batch")
+	errP2shBatchBusy     = newRPCError(-32091, "p2sh batch queue is full, retry later")
+	errP2shJobNotFound   = newRPCError(-32090, "p2sh batch job not found")
+
+	p2shBatchQueue     = make(chan *P2shBatchJob, p2shBatchQueueSize)
+	p2shBatchJobs      = make(map[string]*P2shBatchJob)
+	p2shBatchJobIDs    []string
+	p2shBatchJobsLock  sync.RWMutex
+	p2shBatchStartOnce sync.Once
+)
+
+// P2shBatchJob p2sh address batch registration job
+type P2shBatchJob struct {
+	JobID     string            `json:"jobid"`
+	Total     int               `json:"total"`
+	Processed int               `json:"processed"`
+	Failed    int               `json:"failed"`
+	Done      bool              `json:"done"`
+	Errors    map[string]string `json:"errors,omitempty"`
+	Timestamp int64             `json:"timestamp"`
+
+	addresses []string
+}
+
+// RegisterP2shAddressBatch api
+func RegisterP2shAddressBatch(bindAddresses []string) (string, error) {
+	if tokens.GetP2shAddressProvider() == nil {
+		return "", errNoP2shBridge
+	}
+	if len(bindAddresses) == 0 {
+		return "", errP2shBatchEmpty
+	}
+	if len(bindAddresses) > maxP2shBatchSize {
+		return "", errP2shBatchTooLarge
+	}
+	p2shBatchStartOnce.Do(func() {
+		go processP2shBatchJobs()
+	})
+	job := &P2shBatchJob{
+		JobID:     newP2shBatchJobID(),
+		Total:     len(bindAddresses),
+		Timestamp: time.Now().Unix(),
+		addresses: bindAddresses,
+	}
+	if err := enqueueP2shBatchJob(job); err != nil {
+		return "", err
+	}
+	log.Info("[api] add p2sh batch job", "jobid", job.JobID, "total", job.Total)
+	return job.JobID, nil
+}
+
+// GetP2shBatchJob api
+func GetP2shBatchJob(jobID string) (*P2shBatchJob, error) {
+	p2shBatchJobsLock.RLock()
+	defer p2shBatchJobsLock.RUnlock()
+	job, exist := p2shBatchJobs[jobID]
+	if !exist {
+		return nil, errP2shJobNotFound
+	}
+	result := *job
+	result.Errors = make(map[string]string, len(job.Errors))
+	for k, v := range job.Errors {
+		result.Errors[k] = v
+	}
+	return &result, nil
+}
+
+// ListP2shAddresses list registered p2sh addresses in registration time order.
+// it exposes all users' bind addresses, so it must be called with api token (or by admin).
+func ListP2shAddresses(offset, limit int) ([]*P2shAddress, error) {
+	switch {
+	case limit <= 0:
+		limit = p2shListDefaultSize
+	case limit > p2shListLimit:
+		limit = p2shListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return mongodb.FindP2shAddresses(offset, limit)
+}
+
+// GetP2shAddressByBind api, get the stored p2sh address of bind address
+// without recomputing the redeem script, so it works when the btc node is down.
+// bind address is stored as posted, so it's matched in lower and checksummed case too.
+func GetP2shAddressByBind(bindAddress string) (*P2shAddress, error) {
+	if bindAddress == "" {
+		return nil, mongodb.ErrItemNotFound
+	}
+	keys := []string{bindAddress}
+	if lower := strings.ToLower(bindAddress); lower != bindAddress {
+		keys = append(keys, lower)
+	}
+	if common.IsHexAddress(bindAddress) {
+		if checksummed := common.HexToAddress(bindAddress).String(); checksummed != bindAddress {
+			keys = append(keys, checksummed)
+		}
+	}
+	p2shAddresses, err := findP2shAddressesByKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+	if len(p2shAddresses) == 0 {
+		return nil, mongodb.ErrItemNotFound
+	}
+	for _, p2sh := range p2shAddresses {
+		if p2sh.Key == bindAddress {
+			return p2sh, nil
+		}
+	}
+	return p2shAddresses[0], nil
+}
+
+func newP2shBatchJobID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return hex.EncodeToString([]byte(time.Now().String()))
+	}
+	return hex.EncodeToString(buf)
+}
+
+// enqueueP2shBatchJob register job before enqueuing, so that it can be
+// queried and updated once it's taken by the worker
+func enqueueP2shBatchJob(job *P2shBatchJob) error {
+	addP2shBatchJob(job)
+	select {
+	case p2shBatchQueue <- job:
+		return nil
+	default:
+		removeP2shBatchJob(job.JobID)
+		return errP2shBatchBusy
+	}
+}
+
+// keep only the latest finished jobs in memory, unfinished jobs are never evicted
+// (they are limited by the queue size)
+func addP2shBatchJob(job *P2shBatchJob) {
+	p2shBatchJobsLock.Lock()
+	defer p2shBatchJobsLock.Unlock()
+	p2shBatchJobs[job.JobID] = job
+	p2shBatchJobIDs = append(p2shBatchJobIDs, job.JobID)
+	for i := 0; len(p2shBatchJobIDs) > maxP2shBatchJobs && i < len(p2shBatchJobIDs); {
+		jobID := p2shBatchJobIDs[i]
+		if !p2shBatchJobs[jobID].Done {
+			i++
+			continue
+		}
+		delete(p2shBatchJobs, jobID)
+		p2shBatchJobIDs = append(p2shBatchJobIDs[:i], p2shBatchJobIDs[i+1:]...)
+	}
+}
+
+func removeP2shBatchJob(jobID string) {
+	p2shBatchJobsLock.Lock()
+	defer p2shBatchJobsLock.Unlock()
+	delete(p2shBatchJobs, jobID)
+	for i, id := range p2shBatchJobIDs {
+		if id == jobID {
+			p2shBatchJobIDs = append(p2shBatchJobIDs[:i], p2shBatchJobIDs[i+1:]...)
+			break
+		}
+	}
+}
+
+func processP2shBatchJobs() {
+	for job := range p2shBatchQueue {
+		for _, bindAddress := range job.addresses {
+			_, err := calcP2shAddress(bindAddress, true)
+			p2shBatchJobsLock.Lock()
+			job.Processed++
+			if err != nil {
+				job.Failed++
+				if job.Errors == nil {
+					job.Errors = make(map[string]string)
+				}
+				job.Errors[bindAddress] = err.Error()
+			}
+			p2shBatchJobsLock.Unlock()
+		}
+		p2shBatchJobsLock.Lock()
+		job.Done = true
+		job.addresses = nil
+		p2shBatchJobsLock.Unlock()
+		log.Info("[api] p2sh batch job finished", "jobid", job.JobID, "total", job.Total, "failed", job.Failed)
+	}
+}
+
+const maxDisasmErrorLength = 256
+
+// disasmRedeemScript disassemble redeem script in best effort,
+// failure is returned as a (length limited) message but not an error,
+// as the p2sh address and redeem script are still usable.
+func disasmRedeemScript(redeemScript []byte) (disasm, disasmErr string) {
+	disasm, err := txscript.DisasmString(redeemScript)
+	if err == nil {
+		return disasm, ""
+	}
+	log.Debug("disassemble redeem script failed", "redeemScript", hex.EncodeToString(redeemScript), "err", err)
+	disasmErr = err.Error()
+	if len(disasmErr) > maxDisasmErrorLength {
+		disasmErr = disasmErr[:maxDisasmErrorLength]
+	}
+	return "", disasmErr
+}
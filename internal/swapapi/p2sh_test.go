@@ -0,0 +1,193 @@
+package swapapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// mockP2shProvider p2sh address provider, other bridge methods are not implemented
+type mockP2shProvider struct {
+	tokens.CrossChainBridge
+}
+
+func (p *mockP2shProvider) GetP2shPairID() string {
+	return "mockp2sh"
+}
+
+func (p *mockP2shProvider) GetTokenConfig(pairID string) *tokens.TokenConfig {
+	return nil
+}
+
+func (p *mockP2shProvider) GetP2shAddress(bindAddr string) (p2shAddress string, redeemScript []byte, err error) {
+	if bindAddr == "" {
+		return "", nil, errors.New("empty bind address")
+	}
+	return "3p2sh", []byte{txscript.OP_DROP}, nil
+}
+
+func (p *mockP2shProvider) VerifyP2shTransaction(pairID, txHash, bindAddress string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+	return nil, tokens.ErrTxNotFound
+}
+
+func TestP2shAPIWithoutProvider(t *testing.T) {
+	tokens.SetP2shAddressProvider(nil)
+	if _, err := calcP2shAddress("0x1111111111111111111111111111111111111111", false); err != errNoP2shBridge {
+		t.Errorf("want no p2sh bridge error, have %v", err)
+	}
+	if _, err := RegisterP2shAddressBatch([]string{"0x1111111111111111111111111111111111111111"}); err != errNoP2shBridge {
+		t.Errorf("want no p2sh bridge error, have %v", err)
+	}
+	result, err := PrevalidateDeposit("", "1", "0x1111111111111111111111111111111111111111", DepositTypeP2sh)
+	if err != nil || len(result.Violations) != 1 || result.Violations[0].Code != ViolationP2shNotSupported {
+		t.Errorf("want p2sh not supported violation, have %+v %v", result, err)
+	}
+}
+
+func TestP2shAPIWithMockProvider(t *testing.T) {
+	provider := &mockP2shProvider{}
+	oldSrcBridge := tokens.SrcBridge
+	tokens.SetP2shAddressProvider(provider)
+	tokens.SrcBridge = provider
+	defer func() {
+		tokens.SetP2shAddressProvider(nil)
+		tokens.SrcBridge = oldSrcBridge
+	}()
+
+	info, err := calcP2shAddress("0x1111111111111111111111111111111111111111", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.P2shAddress != "3p2sh" || info.RedeemScript != "75" || info.RedeemScriptDisasm != "OP_DROP" {
+		t.Errorf("wrong p2sh address info %+v", info)
+	}
+	if _, err := calcP2shAddress("", false); err == nil {
+		t.Error("want error of provider returned")
+	}
+
+	// pair ID is resolved by provider
+	result, err := PrevalidateDeposit("", "1", "0x1111111111111111111111111111111111111111", DepositTypeP2sh)
+	if err != nil || result.PairID != "mockp2sh" || len(result.Violations) != 1 || result.Violations[0].Code != ViolationPairNotExist {
+		t.Errorf("want pair of provider not exist, have %+v %v", result, err)
+	}
+}
+
+func TestDisasmRedeemScript(t *testing.T) {
+	script, err := txscript.NewScriptBuilder().
+		AddData([]byte("0x1111111111111111111111111111111111111111")).
+		AddOp(txscript.OP_DROP).
+		AddOp(txscript.OP_DUP).
+		AddOp(txscript.OP_HASH160).
+		AddData(make([]byte, 20)).
+		AddOp(txscript.OP_EQUALVERIFY).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	disasm, disasmErr := disasmRedeemScript(script)
+	if disasm == "" || disasmErr != "" {
+		t.Errorf("disasm valid script failed, disasm=%q err=%q", disasm, disasmErr)
+	}
+
+	// OP_PUSHDATA1 of 5 bytes with only 1 byte data
+	badScript := []byte{txscript.OP_DUP, txscript.OP_PUSHDATA1, 0x05, 0x01}
+	disasm, disasmErr = disasmRedeemScript(badScript)
+	if disasm != "" || disasmErr == "" {
+		t.Errorf("disasm bad script should fail, disasm=%q err=%q", disasm, disasmErr)
+	}
+}
+
+func resetP2shBatchJobs() {
+	p2shBatchJobsLock.Lock()
+	defer p2shBatchJobsLock.Unlock()
+	p2shBatchJobs = make(map[string]*P2shBatchJob)
+	p2shBatchJobIDs = nil
+}
+
+func TestAddP2shBatchJobKeepUnfinished(t *testing.T) {
+	resetP2shBatchJobs()
+	defer resetP2shBatchJobs()
+
+	// the oldest job is still running, others are finished
+	for i := 0; i < maxP2shBatchJobs; i++ {
+		addP2shBatchJob(&P2shBatchJob{JobID: fmt.Sprint(i), Done: i > 0})
+	}
+	addP2shBatchJob(&P2shBatchJob{JobID: "new"})
+	if len(p2shBatchJobs) != maxP2shBatchJobs || len(p2shBatchJobIDs) != maxP2shBatchJobs {
+		t.Fatalf("want %v jobs, have %v (ids %v)", maxP2shBatchJobs, len(p2shBatchJobs), len(p2shBatchJobIDs))
+	}
+	for _, jobID := range []string{"0", "2", "new"} {
+		if _, err := GetP2shBatchJob(jobID); err != nil {
+			t.Errorf("job %v should be kept, err %v", jobID, err)
+		}
+	}
+	if _, err := GetP2shBatchJob("1"); !errors.Is(err, errP2shJobNotFound) {
+		t.Errorf("oldest finished job should be evicted, err %v", err)
+	}
+}
+
+func TestEnqueueP2shBatchJob(t *testing.T) {
+	resetP2shBatchJobs()
+	defer resetP2shBatchJobs()
+	oldQueue := p2shBatchQueue
+	defer func() { p2shBatchQueue = oldQueue }()
+	p2shBatchQueue = make(chan *P2shBatchJob, 1)
+
+	job := &P2shBatchJob{JobID: "first"}
+	if err := enqueueP2shBatchJob(job); err != nil {
+		t.Fatalf("enqueue job failed: %v", err)
+	}
+	// the worker can find the job as soon as it takes it from the queue
+	if queued := <-p2shBatchQueue; queued != job {
+		t.Fatalf("wrong queued job %v", queued.JobID)
+	}
+	if _, err := GetP2shBatchJob("first"); err != nil {
+		t.Errorf("enqueued job is not registered: %v", err)
+	}
+
+	p2shBatchQueue <- &P2shBatchJob{JobID: "busy"}
+	if err := enqueueP2shBatchJob(&P2shBatchJob{JobID: "second"}); !errors.Is(err, errP2shBatchBusy) {
+		t.Fatalf("enqueue to full queue should fail, err %v", err)
+	}
+	if _, err := GetP2shBatchJob("second"); !errors.Is(err, errP2shJobNotFound) {
+		t.Errorf("job failed to enqueue should not be registered, err %v", err)
+	}
+	if len(p2shBatchJobIDs) != 1 {
+		t.Errorf("wrong job ids %v", p2shBatchJobIDs)
+	}
+}
+
+func TestGetP2shAddressByBind(t *testing.T) {
+	oldFindP2sh := findP2shAddressesByKeys
+	defer func() { findP2shAddressesByKeys = oldFindP2sh }()
+
+	const bind = "0xabcdefabcdefabcdefabcdefabcdefabcdefabcd"
+	var haveKeys []string
+	findP2shAddressesByKeys = func(keys []string) ([]*mongodb.MgoP2shAddress, error) {
+		haveKeys = keys
+		// posted with checksummed bind address
+		return []*mongodb.MgoP2shAddress{{Key: "0xABcdEFABcdEFabcdEfAbCdefabcdeFABcDEFabCD", P2shAddress: "3p2sh", Timestamp: 1600000000}}, nil
+	}
+	p2sh, err := GetP2shAddressByBind(bind)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(haveKeys) != 2 || haveKeys[0] != bind {
+		t.Errorf("want bind address and checksummed keys, have %v", haveKeys)
+	}
+	if p2sh.P2shAddress != "3p2sh" || p2sh.Timestamp != 1600000000 {
+		t.Errorf("wrong p2sh address %+v", p2sh)
+	}
+
+	findP2shAddressesByKeys = func(keys []string) ([]*mongodb.MgoP2shAddress, error) {
+		return nil, nil
+	}
+	if _, err = GetP2shAddressByBind(bind); !errors.Is(err, mongodb.ErrItemNotFound) {
+		t.Errorf("want %v, have %v", mongodb.ErrItemNotFound, err)
+	}
+}
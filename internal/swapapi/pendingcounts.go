@@ -0,0 +1,81 @@
+package swapapi
+
+import (
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+)
+
+var (
+	// pending swaps of storage, replaced in tests
+	findPendingSwapResults = mongodb.FindPendingSwapResults
+	countSwapsWithStatus   = mongodb.CountSwapsWithStatus
+)
+
+// PendingCounts pending swaps of one direction by stage,
+// stuck signings are told apart from stuck sends.
+type PendingCounts struct {
+	NotStable     int64 `json:"notStable"`     // deposit tx is waiting to be stable
+	Building      int64 `json:"building"`      // waiting for swap tx to be built
+	Signing       int64 `json:"signing"`       // swap tx is being signed by dcrm
+	MaxSigningAge int64 `json:"maxSigningAge"` // seconds, age of the oldest unfinished sign attempt
+	Sending       int64 `json:"sending"`       // swap tx is sent and waiting to be stable
+}
+
+// AllPendingCounts pending swaps of both directions
+type AllPendingCounts struct {
+	Swapin      *PendingCounts `json:"swapin"`
+	Swapout     *PendingCounts `json:"swapout"`
+	GeneratedAt int64          `json:"generatedAt"`
+}
+
+// CurrentSignAttemptAge age in seconds of the latest sign attempt if it is unfinished, or zero
+func CurrentSignAttemptAge(attempts []*mongodb.MgoSignAttempt, now int64) int64 {
+	if count := len(attempts); count > 0 {
+		if current := attempts[count-1]; current.FinishedAt == 0 {
+			return now - current.InitiatedAt
+		}
+	}
+	return 0
+}
+
+// GetPendingSwapCounts api
+func GetPendingSwapCounts() (*AllPendingCounts, error) {
+	now := time.Now().Unix()
+	swapinCounts, err := getPendingCounts(SwapinDirection, now)
+	if err != nil {
+		return nil, err
+	}
+	swapoutCounts, err := getPendingCounts(SwapoutDirection, now)
+	if err != nil {
+		return nil, err
+	}
+	return &AllPendingCounts{Swapin: swapinCounts, Swapout: swapoutCounts, GeneratedAt: now}, nil
+}
+
+func getPendingCounts(dir SwapDirection, now int64) (*PendingCounts, error) {
+	notStable, err := countSwapsWithStatus(dir.IsSwapin(), mongodb.TxNotStable)
+	if err != nil {
+		return nil, err
+	}
+	results, err := findPendingSwapResults(dir.IsSwapin())
+	if err != nil {
+		return nil, err
+	}
+	counts := &PendingCounts{NotStable: notStable}
+	for _, res := range results {
+		if res.Status == mongodb.MatchTxNotStable {
+			counts.Sending++
+			continue
+		}
+		if attempt := res.LastSignAttempt; attempt != nil && attempt.FinishedAt == 0 {
+			counts.Signing++
+			if age := now - attempt.InitiatedAt; age > counts.MaxSigningAge {
+				counts.MaxSigningAge = age
+			}
+		} else {
+			counts.Building++
+		}
+	}
+	return counts, nil
+}
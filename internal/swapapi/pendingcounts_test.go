@@ -0,0 +1,48 @@
+package swapapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+)
+
+func TestGetPendingSwapCounts(t *testing.T) {
+	oldFind, oldCount := findPendingSwapResults, countSwapsWithStatus
+	defer func() { findPendingSwapResults, countSwapsWithStatus = oldFind, oldCount }()
+
+	now := time.Now().Unix()
+	findPendingSwapResults = func(isSwapin bool) ([]*mongodb.PendingSwapResult, error) {
+		if !isSwapin {
+			return nil, nil
+		}
+		return []*mongodb.PendingSwapResult{
+			{Status: mongodb.MatchTxEmpty},
+			{Status: mongodb.MatchTxEmpty, LastSignAttempt: &mongodb.MgoSignAttempt{InitiatedAt: now - 300, FinishedAt: now - 200}},
+			{Status: mongodb.MatchTxEmpty, LastSignAttempt: &mongodb.MgoSignAttempt{InitiatedAt: now - 30}},
+			{Status: mongodb.MatchTxEmpty, LastSignAttempt: &mongodb.MgoSignAttempt{InitiatedAt: now - 600}},
+			{Status: mongodb.MatchTxNotStable, LastSignAttempt: &mongodb.MgoSignAttempt{InitiatedAt: now - 900, FinishedAt: now - 800, RsvReceived: true}},
+		}, nil
+	}
+	countSwapsWithStatus = func(isSwapin bool, status mongodb.SwapStatus) (int64, error) {
+		if isSwapin {
+			return 4, nil
+		}
+		return 1, nil
+	}
+
+	counts, err := GetPendingSwapCounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	swapin := counts.Swapin
+	if swapin.NotStable != 4 || swapin.Building != 2 || swapin.Signing != 2 || swapin.Sending != 1 {
+		t.Errorf("wrong swapin counts %+v", swapin)
+	}
+	if swapin.MaxSigningAge < 600 || swapin.MaxSigningAge > 600+5 {
+		t.Errorf("want max signing age of the oldest unfinished attempt, have %v", swapin.MaxSigningAge)
+	}
+	if want := (PendingCounts{NotStable: 1}); *counts.Swapout != want {
+		t.Errorf("wrong swapout counts %+v", counts.Swapout)
+	}
+}
@@ -0,0 +1,226 @@
+package swapapi
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	cmath "github.com/anyswap/CrossChain-Bridge/common/math"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// deposit types of prevalidation
+const (
+	DepositTypeSwapin  = "swapin"
+	DepositTypeSwapout = "swapout"
+	DepositTypeP2sh    = "p2sh"
+)
+
+// deposit violation codes
+const (
+	ViolationUnknownDepositType  = "UnknownDepositType"
+	ViolationPairNotExist        = "PairNotExist"
+	ViolationSwapIsClosed        = "SwapIsClosed"
+	ViolationWrongAmount         = "WrongAmount"
+	ViolationAmountTooSmall      = "AmountTooSmall"
+	ViolationAmountTooLarge      = "AmountTooLarge"
+	ViolationAmountNotEnoughFee  = "AmountNotEnoughForFee"
+	ViolationAmountIsDust        = "AmountIsDust"
+	ViolationSwapValueIsDust     = "SwapValueIsDust"
+	ViolationWrongBindAddress    = "WrongBindAddress"
+	ViolationAddressInBlacklist  = "AddressInBlacklist"
+	ViolationWrongDepositAddress = "WrongDepositAddress"
+	ViolationP2shNotSupported    = "P2shNotSupported"
+	ViolationP2shNotRegistered   = "P2shNotRegistered"
+)
+
+// DepositViolation deposit violation
+type DepositViolation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// PrevalidateResult prevalidate deposit result
+type PrevalidateResult struct {
+	Valid                    bool                `json:"valid"`
+	Violations               []*DepositViolation `json:"violations"`
+	PairID                   string              `json:"pairid"`
+	DepositType              string              `json:"depositType"`
+	DepositAddress           string              `json:"depositAddress"`
+	Bind                     string              `json:"bind"`
+	Value                    string              `json:"value"`
+	SwapValue                string              `json:"swapvalue"`
+	SwapFee                  string              `json:"swapfee"`
+	ValueDisplay             string              `json:"valueDisplay,omitempty"`     // value with decimals and symbol of deposit chain
+	SwapValueDisplay         string              `json:"swapvalueDisplay,omitempty"` // swap value with decimals and symbol of payout chain
+	DustThreshold            string              `json:"dustThreshold,omitempty"`    // in smallest unit of payout chain, only for chain with dust rule
+	IsBigValue               bool                `json:"isBigValue"`
+	BigValueRule             string              `json:"bigValueRule,omitempty"`
+	RequiredConfirmations    uint64              `json:"requiredConfirmations"`
+	MinRegisterConfirmations uint64              `json:"minRegisterConfirmations,omitempty"`
+}
+
+func (r *PrevalidateResult) addViolation(code, message string) {
+	r.Violations = append(r.Violations, &DepositViolation{Code: code, Message: message})
+}
+
+// PrevalidateDeposit api
+// check a planned deposit with the same rules of verification except the on chain tx,
+// amount is in token unit (eg. "0.5"), nothing is written into database.
+func PrevalidateDeposit(pairID, amount, bindAddress, depositType string) (*PrevalidateResult, error) {
+	log.Debug("[api] receive PrevalidateDeposit", "pairID", pairID, "amount", amount, "bind", bindAddress, "depositType", depositType)
+	depositType = strings.ToLower(depositType)
+	if depositType == "" {
+		depositType = DepositTypeSwapin
+	}
+	result := &PrevalidateResult{
+		PairID:      pairID,
+		DepositType: depositType,
+		Bind:        bindAddress,
+		Violations:  make([]*DepositViolation, 0),
+	}
+
+	var isSwapin bool
+	switch depositType {
+	case DepositTypeSwapin:
+		isSwapin = true
+	case DepositTypeP2sh:
+		isSwapin = true
+		provider := tokens.GetP2shAddressProvider()
+		if provider == nil {
+			result.addViolation(ViolationP2shNotSupported, errNoP2shBridge.Error())
+			return result, nil
+		}
+		pairID = provider.GetP2shPairID()
+		result.PairID = pairID
+	case DepositTypeSwapout:
+	default:
+		result.addViolation(ViolationUnknownDepositType, "unknown deposit type "+depositType)
+		return result, nil
+	}
+
+	bridge := tokens.GetCrossChainBridge(isSwapin)
+	bindBridge := tokens.GetCrossChainBridge(!isSwapin)
+	tokenCfg := bridge.GetTokenConfig(pairID)
+	if tokenCfg == nil {
+		result.addViolation(ViolationPairNotExist, tokens.ErrUnknownPairID.Error())
+		return result, nil
+	}
+	if tokenCfg.DisableSwap {
+		result.addViolation(ViolationSwapIsClosed, tokens.ErrSwapIsClosed.Error())
+	}
+
+	result.RequiredConfirmations = tokens.GetPairStableConfirmations(pairID, isSwapin)
+	if isSwapin {
+		result.MinRegisterConfirmations = tokens.GetPairMinRegisterConfirmations(pairID)
+	}
+
+	if depositType == DepositTypeP2sh {
+		p2shInfo, err := mongodb.FindP2shAddress(bindAddress)
+		switch {
+		case errors.Is(err, mongodb.ErrItemNotFound):
+			result.addViolation(ViolationP2shNotRegistered, "bind address has no registered p2sh address")
+		case err != nil:
+			return nil, newRPCInternalError(err)
+		default:
+			result.DepositAddress = p2shInfo.P2shAddress
+		}
+	} else {
+		if isSwapin {
+			result.DepositAddress = tokenCfg.DepositAddress
+		} else {
+			result.DepositAddress = tokenCfg.ContractAddress
+		}
+		if result.DepositAddress == "" || !bridge.IsValidAddress(result.DepositAddress) {
+			result.addViolation(ViolationWrongDepositAddress, "deposit address is not available")
+		}
+	}
+
+	if !bindBridge.IsValidAddress(bindAddress) {
+		result.addViolation(ViolationWrongBindAddress, tokens.ErrWrongMemoBindAddress.Error())
+	} else {
+		isBlacked, err := mongodb.QueryBlacklist(bindAddress, pairID)
+		if err != nil {
+			return nil, newRPCInternalError(err)
+		}
+		if isBlacked {
+			result.addViolation(ViolationAddressInBlacklist, tokens.ErrAddressIsInBlacklist.Error())
+		}
+	}
+
+	prevalidateAmount(result, tokenCfg, amount, isSwapin)
+
+	result.Valid = len(result.Violations) == 0
+	return result, nil
+}
+
+func prevalidateAmount(result *PrevalidateResult, tokenCfg *tokens.TokenConfig, amount string, isSwapin bool) {
+	value, ok := parseTokenAmount(amount, *tokenCfg.Decimals)
+	if !ok || value.Sign() <= 0 {
+		result.addViolation(ViolationWrongAmount, "wrong amount "+amount)
+		return
+	}
+	result.Value = value.String()
+	result.ValueDisplay = tokenCfg.FormatValue(value)
+
+	pairID := result.PairID
+	minSwap, maxSwap := tokens.GetSwapValueRange(pairID, isSwapin)
+	switch {
+	case minSwap != nil && value.Cmp(minSwap) < 0:
+		result.addViolation(ViolationAmountTooSmall, "amount is less than minimum swap value "+minSwap.String())
+		return
+	case maxSwap != nil && value.Cmp(maxSwap) > 0:
+		result.addViolation(ViolationAmountTooLarge, "amount is greater than maximum swap value "+maxSwap.String())
+		return
+	}
+
+	swappedValue := tokens.CalcSwappedValue(pairID, value, isSwapin, "", "")
+	if swappedValue.Sign() <= 0 {
+		result.addViolation(ViolationAmountNotEnoughFee, "amount is not enough to pay swap fee")
+		return
+	}
+	result.SwapValue = swappedValue.String()
+
+	_, cpTokenCfg := tokens.GetTokenConfigsByDirection(pairID, isSwapin)
+	result.SwapValueDisplay = cpTokenCfg.FormatValue(swappedValue)
+	convertedBack := tokens.ConvertTokenValue(swappedValue, *cpTokenCfg.Decimals, *tokenCfg.Decimals)
+	result.SwapFee = new(big.Int).Sub(value, convertedBack).String()
+
+	if !isSwapin {
+		prevalidateDust(result, cpTokenCfg, tokens.ConvertTokenValue(value, *tokenCfg.Decimals, *cpTokenCfg.Decimals), swappedValue)
+	}
+
+	bigValue := tokens.CheckBigValue(pairID, value, isSwapin, "", "")
+	result.IsBigValue = bigValue.IsBigValue
+	result.BigValueRule = bigValue.Rule()
+}
+
+// prevalidateDust flag swapout whose payout would be below dust threshold of the payout chain
+func prevalidateDust(result *PrevalidateResult, payoutTokenCfg *tokens.TokenConfig, value, swappedValue *big.Int) {
+	threshold := tokens.GetSwapoutDustThreshold(result.Bind)
+	if threshold == nil {
+		return
+	}
+	result.DustThreshold = threshold.String()
+	switch {
+	case value.Cmp(threshold) < 0:
+		result.addViolation(ViolationAmountIsDust, "amount is below dust threshold "+payoutTokenCfg.FormatValue(threshold))
+	case swappedValue.Cmp(threshold) < 0:
+		result.addViolation(ViolationSwapValueIsDust, "amount after swap fee is below dust threshold "+payoutTokenCfg.FormatValue(threshold))
+	}
+}
+
+// parseTokenAmount parse amount in token unit to value in smallest unit
+func parseTokenAmount(amount string, decimals uint8) (*big.Int, bool) {
+	rat, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return nil, false
+	}
+	rat.Mul(rat, new(big.Rat).SetInt(cmath.BigPow(10, int64(decimals))))
+	if !rat.IsInt() {
+		return nil, false
+	}
+	return rat.Num(), true
+}
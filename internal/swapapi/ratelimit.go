@@ -0,0 +1,96 @@
+package swapapi
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+const defaultRegisterBucketIdleTimeout = 600 // seconds
+
+var (
+	errRegisterRateExceeded = newRPCError(-32054, "register rate limit exceeded, retry later")
+
+	registerBuckets = newTokenBucketLimiter()
+)
+
+// tokenBucket token bucket, tokens are refilled lazily when taken
+type tokenBucket struct {
+	tokens   float64
+	lastTime time.Time
+}
+
+// tokenBucketLimiter token buckets by key, idle buckets are evicted
+type tokenBucketLimiter struct {
+	lock      sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastEvict time.Time
+}
+
+func newTokenBucketLimiter() *tokenBucketLimiter {
+	return &tokenBucketLimiter{buckets: make(map[string]*tokenBucket)}
+}
+
+// allow take a token from bucket of key
+func (l *tokenBucketLimiter) allow(key string, config *params.TokenBucketConfig, idleTimeout time.Duration, now time.Time) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if now.Sub(l.lastEvict) >= idleTimeout {
+		l.evictIdle(idleTimeout, now)
+	}
+	burst := float64(config.Burst)
+	bucket, exist := l.buckets[key]
+	if !exist {
+		bucket = &tokenBucket{tokens: burst, lastTime: now}
+		l.buckets[key] = bucket
+	} else if elapsed := now.Sub(bucket.lastTime).Seconds(); elapsed > 0 {
+		bucket.tokens += elapsed * config.Rate
+		if bucket.tokens > burst {
+			bucket.tokens = burst
+		}
+		bucket.lastTime = now
+	}
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// evictIdle buckets idle for longer than timeout are refilled to full,
+// so evicting them changes nothing as long as timeout covers refilling.
+func (l *tokenBucketLimiter) evictIdle(idleTimeout time.Duration, now time.Time) {
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.lastTime) >= idleTimeout {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastEvict = now
+}
+
+// allowRegisterTokens take tokens of client ip and txid (if configured) of register method,
+// it's always allowed if 'RegisterRateLimit' is not configured.
+func allowRegisterTokens(method, clientIP, txid string) bool {
+	config := params.GetServerConfig().RegisterRateLimit
+	if config == nil {
+		return true
+	}
+	idleTimeout := time.Duration(config.IdleTimeout) * time.Second
+	if idleTimeout <= 0 {
+		idleTimeout = defaultRegisterBucketIdleTimeout * time.Second
+	}
+	now := time.Now()
+	bucket := config.Methods[method]
+	if bucket == nil {
+		bucket = config.Default
+	}
+	if bucket != nil && !registerBuckets.allow("ip:"+method+":"+clientIP, bucket, idleTimeout, now) {
+		return false
+	}
+	if config.PerTxID != nil && !registerBuckets.allow("tx:"+method+":"+strings.ToLower(txid), config.PerTxID, idleTimeout, now) {
+		return false
+	}
+	return true
+}
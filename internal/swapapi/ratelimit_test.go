@@ -0,0 +1,72 @@
+package swapapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+func TestTokenBucketLimiter(t *testing.T) {
+	limiter := newTokenBucketLimiter()
+	config := &params.TokenBucketConfig{Rate: 2, Burst: 3}
+	idleTimeout := time.Minute
+	start := time.Unix(1000000, 0)
+
+	for i := 0; i < 3; i++ {
+		if !limiter.allow("a", config, idleTimeout, start) {
+			t.Fatalf("call %v within burst should be allowed", i)
+		}
+	}
+	if limiter.allow("a", config, idleTimeout, start) {
+		t.Error("call exceeding burst should be rejected")
+	}
+	if !limiter.allow("b", config, idleTimeout, start) {
+		t.Error("other key should be allowed")
+	}
+	// refilled 2 tokens per second
+	later := start.Add(time.Second)
+	if !limiter.allow("a", config, idleTimeout, later) || !limiter.allow("a", config, idleTimeout, later) || limiter.allow("a", config, idleTimeout, later) {
+		t.Error("want exactly 2 tokens refilled after 1 second")
+	}
+
+	// idle buckets are evicted
+	limiter.allow("c", config, idleTimeout, later.Add(idleTimeout))
+	if len(limiter.buckets) != 1 {
+		t.Errorf("want idle buckets evicted, have %v buckets", len(limiter.buckets))
+	}
+}
+
+func TestAllowRegisterTokens(t *testing.T) {
+	defer func() { registerBuckets = newTokenBucketLimiter() }()
+	const txid = "0x0000000000000000000000000000000000000000000000000000000000000001"
+
+	params.SetConfig(&params.BridgeConfig{Server: &params.ServerConfig{}})
+	for i := 0; i < 100; i++ {
+		if !allowRegisterTokens(RegisterMethodSwapin, "1.1.1.1", txid) {
+			t.Fatal("should not be limited if not configured")
+		}
+	}
+
+	params.SetConfig(&params.BridgeConfig{Server: &params.ServerConfig{
+		RegisterRateLimit: &params.RegisterRateLimitConfig{
+			Methods: map[string]*params.TokenBucketConfig{RegisterMethodSwapin: {Rate: 0.001, Burst: 2}},
+			PerTxID: &params.TokenBucketConfig{Rate: 0.001, Burst: 1},
+		},
+	}})
+	if !allowRegisterTokens(RegisterMethodSwapin, "1.1.1.1", txid) {
+		t.Error("first register should be allowed")
+	}
+	if allowRegisterTokens(RegisterMethodSwapin, "2.2.2.2", txid) {
+		t.Error("duplicate register of txid should be limited")
+	}
+	if !allowRegisterTokens(RegisterMethodSwapin, "1.1.1.1", txid[:len(txid)-1]+"2") {
+		t.Error("register of other txid should be allowed")
+	}
+	if allowRegisterTokens(RegisterMethodSwapin, "1.1.1.1", txid[:len(txid)-1]+"3") {
+		t.Error("register exceeding burst of client ip should be limited")
+	}
+	if !allowRegisterTokens(RegisterMethodP2shSwapin, "1.1.1.1", txid[:len(txid)-1]+"4") {
+		t.Error("method without limit should be allowed")
+	}
+}
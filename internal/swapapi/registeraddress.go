@@ -0,0 +1,113 @@
+package swapapi
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+const (
+	maxRegisterAddressLength = 128
+
+	// challenge is stateless, the signed timestamp must be in this lifetime
+	registerChallengeLifetime = 10 * time.Minute
+)
+
+var (
+	errMalformedRegisterAddress = newRPCError(-32059, "malformed register address")
+	errWrongRegisterAddress     = newRPCError(-32058, "register address is not valid on destination chain")
+	errRegisterProofRequired    = newRPCError(-32057, "register address requires ownership proof")
+	errWrongRegisterProof       = newRPCError(-32056, "wrong register address proof")
+	errRegisterChallengeExpired = newRPCError(-32055, "register address challenge expired")
+
+	// replaced in tests
+	isValidRegisterAddress = func(address string) bool { return tokens.DstBridge.IsValidAddress(address) }
+	registerChallengeNow   = time.Now
+)
+
+// RegisterAddressChallenge challenge to sign (personal_sign) by the registering address
+type RegisterAddressChallenge struct {
+	Address   string `json:"address"`
+	Message   string `json:"message"`
+	Timestamp int64  `json:"timestamp"`
+	ExpireAt  int64  `json:"expireAt"`
+}
+
+// checkRegisterAddress reject malformed input before validating it on destination chain
+func checkRegisterAddress(address string) error {
+	if address == "" || len(address) > maxRegisterAddressLength {
+		return errMalformedRegisterAddress
+	}
+	for _, c := range address {
+		if c <= ' ' || c > '~' {
+			return errMalformedRegisterAddress
+		}
+	}
+	if !isValidRegisterAddress(address) {
+		return errWrongRegisterAddress
+	}
+	return nil
+}
+
+func getRegisterChallengeMessage(address string, timestamp int64) string {
+	return fmt.Sprintf("Register address %v to %v bridge at %v", strings.ToLower(address), params.GetIdentifier(), timestamp)
+}
+
+// GetRegisterAddressChallenge api
+func GetRegisterAddressChallenge(address string) (*RegisterAddressChallenge, error) {
+	if err := checkRegisterAddress(address); err != nil {
+		return nil, err
+	}
+	timestamp := registerChallengeNow().Unix()
+	return &RegisterAddressChallenge{
+		Address:   address,
+		Message:   getRegisterChallengeMessage(address, timestamp),
+		Timestamp: timestamp,
+		ExpireAt:  timestamp + int64(registerChallengeLifetime/time.Second),
+	}, nil
+}
+
+// RegisterAddressWithProof api, signature is personal_sign of the challenge message
+// with timestamp, and is required if 'RegisterAddressProof' is configured.
+func RegisterAddressWithProof(address string, timestamp int64, signature string) (*PostResult, error) {
+	if !params.MustRegisterAccount() {
+		return &SuccessPostResult, nil
+	}
+	if err := checkRegisterAddress(address); err != nil {
+		return nil, err
+	}
+	if err := verifyRegisterAddressProof(address, timestamp, signature); err != nil {
+		return nil, err
+	}
+	return addRegisteredAddress(address)
+}
+
+func verifyRegisterAddressProof(address string, timestamp int64, signature string) error {
+	now := registerChallengeNow().Unix()
+	if timestamp > now || now-timestamp > int64(registerChallengeLifetime/time.Second) {
+		return errRegisterChallengeExpired
+	}
+	sig := common.FromHex(signature)
+	if len(sig) != crypto.SignatureLength {
+		return errWrongRegisterProof
+	}
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27 // wallets sign with v of 27 or 28
+	}
+	pubKey, err := crypto.SigToPub(personalSignHash(getRegisterChallengeMessage(address, timestamp)), sig)
+	if err != nil || !strings.EqualFold(crypto.PubkeyToAddress(*pubKey).String(), address) {
+		return errWrongRegisterProof
+	}
+	return nil
+}
+
+// personalSignHash hash of message signed by personal_sign (EIP-191)
+func personalSignHash(message string) []byte {
+	prefix := fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(message))
+	return crypto.Keccak256([]byte(prefix), []byte(message))
+}
@@ -0,0 +1,77 @@
+package swapapi
+
+import (
+	"crypto/ecdsa"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+func TestCheckRegisterAddress(t *testing.T) {
+	oldIsValid := isValidRegisterAddress
+	defer func() { isValidRegisterAddress = oldIsValid }()
+	isValidRegisterAddress = common.IsHexAddress
+
+	cases := []struct {
+		address string
+		want    error
+	}{
+		{"0x0123456789abcdef0123456789abcdef01234567", nil},
+		{"", errMalformedRegisterAddress},
+		{"0x0123456789abcdef0123456789abcdef0123456 ", errMalformedRegisterAddress},
+		{"0x0123456789abcdef\n0123456789abcdef012345", errMalformedRegisterAddress},
+		{"0x" + strings.Repeat("0", 200), errMalformedRegisterAddress},
+		{"0x0123456789abcdef0123456789abcdef0123456z", errWrongRegisterAddress},
+		{"0x0123456789abcdef0123456789abcdef012345678", errWrongRegisterAddress},
+	}
+	for _, c := range cases {
+		if have := checkRegisterAddress(c.address); have != c.want {
+			t.Errorf("address %q want %v, have %v", c.address, c.want, have)
+		}
+	}
+}
+
+func TestVerifyRegisterAddressProof(t *testing.T) {
+	params.SetConfig(&params.BridgeConfig{Identifier: "testbridge"})
+	oldNow := registerChallengeNow
+	defer func() { registerChallengeNow = oldNow }()
+	now := time.Unix(1600000000, 0)
+	registerChallengeNow = func() time.Time { return now }
+
+	key, _ := crypto.GenerateKey()
+	address := crypto.PubkeyToAddress(key.PublicKey).String()
+	other, _ := crypto.GenerateKey()
+
+	sign := func(key *ecdsa.PrivateKey, address string, timestamp int64) string {
+		hash := personalSignHash(getRegisterChallengeMessage(address, timestamp))
+		sig, _ := crypto.Sign(hash, key)
+		sig[crypto.RecoveryIDOffset] += 27 // as wallets do
+		return common.ToHex(sig)
+	}
+	timestamp := now.Unix() - 60
+
+	if err := verifyRegisterAddressProof(address, timestamp, sign(key, address, timestamp)); err != nil {
+		t.Errorf("verify proof failed, %v", err)
+	}
+	// address in any case
+	if err := verifyRegisterAddressProof(strings.ToLower(address), timestamp, sign(key, address, timestamp)); err != nil {
+		t.Errorf("verify proof of lower case address failed, %v", err)
+	}
+	if err := verifyRegisterAddressProof(address, timestamp, sign(other, address, timestamp)); err != errWrongRegisterProof {
+		t.Errorf("want wrong proof of other signer, have %v", err)
+	}
+	if err := verifyRegisterAddressProof(address, timestamp+1, sign(key, address, timestamp)); err != errWrongRegisterProof {
+		t.Errorf("want wrong proof of other timestamp, have %v", err)
+	}
+	if err := verifyRegisterAddressProof(address, timestamp, "0x1234"); err != errWrongRegisterProof {
+		t.Errorf("want wrong proof of short signature, have %v", err)
+	}
+	expired := now.Unix() - int64(registerChallengeLifetime/time.Second) - 1
+	if err := verifyRegisterAddressProof(address, expired, sign(key, address, expired)); err != errRegisterChallengeExpired {
+		t.Errorf("want expired challenge, have %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package swapapi
+
+import (
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+)
+
+const (
+	registeredListLimit       = 100
+	registeredListDefaultSize = 20
+)
+
+var (
+	errRegisteredTimeRange = newRPCError(-32052, "wrong time range in registered address query")
+
+	// replaced in tests
+	findRegisteredAddresses = mongodb.FindRegisteredAddresses
+	findP2shAddressesByKeys = mongodb.FindP2shAddressesByKeys
+)
+
+// RegisteredAddressEntry registered address with its linked p2sh address
+type RegisteredAddressEntry struct {
+	*RegisteredAddress
+	P2shAddress string `json:",omitempty"`
+}
+
+// RegisteredAddressList page of registered addresses
+type RegisteredAddressList struct {
+	Total     int64                     `json:"total"` // count of all addresses matching prefix and time range
+	Offset    int                       `json:"offset"`
+	Limit     int                       `json:"limit"`
+	Addresses []*RegisteredAddressEntry `json:"addresses"`
+}
+
+// ListRegisteredAddresses list registered addresses matching prefix and register time range,
+// in register time order if time range is specified, otherwise in address order.
+// it exposes all users' bindings, so it must be called with api token (or by admin).
+func ListRegisteredAddresses(offset, limit int, prefix string, fromTime, toTime int64) (*RegisteredAddressList, error) {
+	switch {
+	case limit <= 0:
+		limit = registeredListDefaultSize
+	case limit > registeredListLimit:
+		limit = registeredListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if len(prefix) > maxRegisterAddressLength {
+		return nil, errMalformedRegisterAddress
+	}
+	if checkHistoryTimeRange(fromTime, toTime) != nil {
+		return nil, errRegisteredTimeRange
+	}
+	// registered addresses are stored in lower case
+	registered, total, err := findRegisteredAddresses(strings.ToLower(prefix), fromTime, toTime, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	p2shAddresses, err := getLinkedP2shAddresses(registered)
+	if err != nil {
+		return nil, err
+	}
+	result := &RegisteredAddressList{
+		Total:     total,
+		Offset:    offset,
+		Limit:     limit,
+		Addresses: make([]*RegisteredAddressEntry, 0, len(registered)),
+	}
+	for _, address := range registered {
+		result.Addresses = append(result.Addresses, &RegisteredAddressEntry{
+			RegisteredAddress: address,
+			P2shAddress:       p2shAddresses[address.Key],
+		})
+	}
+	return result, nil
+}
+
+// getLinkedP2shAddresses get p2sh addresses keyed by registered address,
+// p2sh addresses are keyed by bind address as posted, which may be checksummed.
+func getLinkedP2shAddresses(registered []*RegisteredAddress) (map[string]string, error) {
+	result := make(map[string]string)
+	if len(registered) == 0 {
+		return result, nil
+	}
+	keys := make([]string, 0, 2*len(registered))
+	for _, address := range registered {
+		keys = append(keys, address.Key)
+		if common.IsHexAddress(address.Key) {
+			keys = append(keys, common.HexToAddress(address.Key).String())
+		}
+	}
+	p2shAddresses, err := findP2shAddressesByKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+	for _, p2sh := range p2shAddresses {
+		result[strings.ToLower(p2sh.Key)] = p2sh.P2shAddress
+	}
+	return result, nil
+}
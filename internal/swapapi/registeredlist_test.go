@@ -0,0 +1,56 @@
+package swapapi
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+)
+
+func TestListRegisteredAddresses(t *testing.T) {
+	oldFindRegistered, oldFindP2sh := findRegisteredAddresses, findP2shAddressesByKeys
+	defer func() { findRegisteredAddresses, findP2shAddressesByKeys = oldFindRegistered, oldFindP2sh }()
+
+	const (
+		address1 = "0x1111111111111111111111111111111111111111"
+		address2 = "0xabcdefabcdefabcdefabcdefabcdefabcdefabcd"
+	)
+	var havePrefix string
+	var haveFromTime, haveToTime int64
+	var haveOffset, haveLimit int
+	findRegisteredAddresses = func(prefix string, fromTime, toTime int64, offset, limit int) ([]*mongodb.MgoRegisteredAddress, int64, error) {
+		havePrefix, haveFromTime, haveToTime, haveOffset, haveLimit = prefix, fromTime, toTime, offset, limit
+		return []*mongodb.MgoRegisteredAddress{{Key: address1}, {Key: address2}}, 5, nil
+	}
+	findP2shAddressesByKeys = func(keys []string) ([]*mongodb.MgoP2shAddress, error) {
+		if len(keys) != 4 {
+			t.Errorf("want both lower case and checksummed keys, have %v", keys)
+		}
+		// posted with checksummed bind address
+		return []*mongodb.MgoP2shAddress{{Key: "0xABcdEFABcdEFabcdEfAbCdefabcdeFABcDEFabCD", P2shAddress: "3p2sh"}}, nil
+	}
+
+	list, err := ListRegisteredAddresses(-1, 1000, "0xABC", 1600000000, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if havePrefix != "0xabc" || haveOffset != 0 || haveLimit != registeredListLimit {
+		t.Errorf("wrong query prefix %v offset %v limit %v", havePrefix, haveOffset, haveLimit)
+	}
+	if haveFromTime != 1600000000 || haveToTime != 0 {
+		t.Errorf("wrong query time range %v to %v", haveFromTime, haveToTime)
+	}
+	if list.Total != 5 || len(list.Addresses) != 2 {
+		t.Fatalf("wrong list %+v", list)
+	}
+	if list.Addresses[0].P2shAddress != "" || list.Addresses[1].P2shAddress != "3p2sh" {
+		t.Errorf("wrong linked p2sh addresses %+v %+v", list.Addresses[0], list.Addresses[1])
+	}
+}
+
+func TestListRegisteredAddressesTimeRange(t *testing.T) {
+	for _, timeRange := range [][2]int64{{-1, 0}, {0, -1}, {200, 100}} {
+		if _, err := ListRegisteredAddresses(0, 0, "", timeRange[0], timeRange[1]); err != errRegisteredTimeRange {
+			t.Errorf("time range %v should fail with %v, have %v", timeRange, errRegisteredTimeRange, err)
+		}
+	}
+}
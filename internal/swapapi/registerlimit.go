@@ -0,0 +1,291 @@
+package swapapi
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// register methods limited by register limiter
+const (
+	RegisterMethodSwapin     = "swapin"
+	RegisterMethodSwapout    = "swapout"
+	RegisterMethodP2shSwapin = "p2shswapin"
+)
+
+const (
+	defaultRegisterMaxConcurrentPerBind = 2
+	defaultRegisterMaxConcurrentPerIP   = 4
+	defaultRegisterRatePerIP            = 60 // per minute
+	defaultTxNotFoundCacheTTL           = 30 // seconds
+
+	maxTxNotFoundCacheSize = 100000
+)
+
+var (
+	errWrongTxHashFormat        = newRPCError(-32075, "wrong tx hash format")
+	errRegisterRateLimited      = newRPCError(-32074, "register rate limited, retry later")
+	errRegisterTooManyInProcess = newRPCError(-32073, "too many registers in process, retry later")
+
+	registerLimitLock sync.Mutex
+	registerRateStart int64              // start of current rate window (minute)
+	registerRateCount map[string]int     // client ip -> calls in current window
+	inProcessPerIP    = map[string]int{} // client ip -> verifications in process
+	inProcessPerBind  = map[string]int{} // bind address -> verifications in process
+
+	txNotFoundCache     sync.Map // txid key -> expire time
+	txNotFoundCacheSize int64
+
+	registerLimitMetrics sync.Map // method -> *RegisterLimitMetrics
+)
+
+// RegisterLimitMetrics rejected public register calls of method
+type RegisterLimitMetrics struct {
+	RejectedByPrefilter uint64 `json:"rejectedByPrefilter"` // txid not matching chain's hash format
+	RejectedByRateLimit uint64 `json:"rejectedByRateLimit"`
+	RejectedByInProcess uint64 `json:"rejectedByInProcess"` // too many concurrent verifications
+	TxNotFoundCacheHits uint64 `json:"txNotFoundCacheHits"`
+}
+
+func getRegisterLimitMetrics(method string) *RegisterLimitMetrics {
+	if m, exist := registerLimitMetrics.Load(method); exist {
+		return m.(*RegisterLimitMetrics)
+	}
+	m, _ := registerLimitMetrics.LoadOrStore(method, &RegisterLimitMetrics{})
+	return m.(*RegisterLimitMetrics)
+}
+
+// GetRegisterLimitMetrics api, key is register method
+func GetRegisterLimitMetrics() map[string]*RegisterLimitMetrics {
+	result := make(map[string]*RegisterLimitMetrics)
+	registerLimitMetrics.Range(func(k, v interface{}) bool {
+		m := v.(*RegisterLimitMetrics)
+		result[k.(string)] = &RegisterLimitMetrics{
+			RejectedByPrefilter: atomic.LoadUint64(&m.RejectedByPrefilter),
+			RejectedByRateLimit: atomic.LoadUint64(&m.RejectedByRateLimit),
+			RejectedByInProcess: atomic.LoadUint64(&m.RejectedByInProcess),
+			TxNotFoundCacheHits: atomic.LoadUint64(&m.TxNotFoundCacheHits),
+		}
+		return true
+	})
+	return result
+}
+
+// ClientIPFromAddr get client ip from remote address of request
+func ClientIPFromAddr(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// ClientIPFromRequest get client ip of request. forwarded headers are set by
+// the client, they are trusted only if the remote address is a trusted proxy,
+// then the right most address which is not a trusted proxy is the client.
+func ClientIPFromRequest(remoteAddr string, forwardedFor []string) string {
+	clientIP := ClientIPFromAddr(remoteAddr)
+	trustedProxies := params.GetServerConfig().TrustedProxies
+	if len(forwardedFor) == 0 || !isTrustedProxy(trustedProxies, clientIP) {
+		return clientIP
+	}
+	addrs := strings.Split(strings.Join(forwardedFor, ","), ",")
+	for i := len(addrs) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(addrs[i])
+		if net.ParseIP(addr) == nil {
+			break
+		}
+		clientIP = addr
+		if !isTrustedProxy(trustedProxies, addr) {
+			break
+		}
+	}
+	return clientIP
+}
+
+func isTrustedProxy(trustedProxies []string, ip string) bool {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil {
+		return false
+	}
+	for _, proxy := range trustedProxies {
+		if _, ipNet, err := net.ParseCIDR(proxy); err == nil {
+			if ipNet.Contains(parsedIP) {
+				return true
+			}
+		} else if proxyIP := net.ParseIP(proxy); proxyIP != nil && proxyIP.Equal(parsedIP) {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidTxHashFormat cheap check of txid before querying the gateway,
+// all supported chains use 32 bytes hex tx hash (eth-like chains with '0x' prefix).
+func isValidTxHashFormat(txid string) bool {
+	hexStr := strings.TrimPrefix(strings.TrimPrefix(txid, "0x"), "0X")
+	if len(hexStr) != 2*common.HashLength {
+		return false
+	}
+	for _, c := range hexStr {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+func getRegisterLimitOrDefault(value, defaultValue int) int {
+	if value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// allowRegisterRate allow at most 'RegisterRatePerIP' calls per minute of client ip
+func allowRegisterRate(clientIP string) bool {
+	rate := getRegisterLimitOrDefault(params.GetServerConfig().RegisterRatePerIP, defaultRegisterRatePerIP)
+	window := time.Now().Unix() / 60
+	registerLimitLock.Lock()
+	defer registerLimitLock.Unlock()
+	if window != registerRateStart || registerRateCount == nil {
+		registerRateStart = window
+		registerRateCount = make(map[string]int)
+	}
+	if registerRateCount[clientIP] >= rate {
+		return false
+	}
+	registerRateCount[clientIP]++
+	return true
+}
+
+// acquireRegisterSlot acquire verification slot of client ip and bind address (may be empty)
+func acquireRegisterSlot(clientIP, bind string) bool {
+	serverCfg := params.GetServerConfig()
+	maxPerIP := getRegisterLimitOrDefault(serverCfg.RegisterMaxConcurrentPerIP, defaultRegisterMaxConcurrentPerIP)
+	maxPerBind := getRegisterLimitOrDefault(serverCfg.RegisterMaxConcurrentPerBind, defaultRegisterMaxConcurrentPerBind)
+	bind = strings.ToLower(bind)
+	registerLimitLock.Lock()
+	defer registerLimitLock.Unlock()
+	if inProcessPerIP[clientIP] >= maxPerIP {
+		return false
+	}
+	if bind != "" && inProcessPerBind[bind] >= maxPerBind {
+		return false
+	}
+	inProcessPerIP[clientIP]++
+	if bind != "" {
+		inProcessPerBind[bind]++
+	}
+	return true
+}
+
+func releaseRegisterSlot(clientIP, bind string) {
+	bind = strings.ToLower(bind)
+	registerLimitLock.Lock()
+	defer registerLimitLock.Unlock()
+	if inProcessPerIP[clientIP]--; inProcessPerIP[clientIP] <= 0 {
+		delete(inProcessPerIP, clientIP)
+	}
+	if bind != "" {
+		if inProcessPerBind[bind]--; inProcessPerBind[bind] <= 0 {
+			delete(inProcessPerBind, bind)
+		}
+	}
+}
+
+// limitRegister limit public register calls, bind is empty if it's unknown before verifying
+func limitRegister(method, clientIP, bind, txid string, register func() (*PostResult, error)) (*PostResult, error) {
+	metrics := getRegisterLimitMetrics(method)
+	if !isValidTxHashFormat(txid) {
+		atomic.AddUint64(&metrics.RejectedByPrefilter, 1)
+		return nil, errWrongTxHashFormat
+	}
+	if !allowRegisterTokens(method, clientIP, txid) {
+		atomic.AddUint64(&metrics.RejectedByRateLimit, 1)
+		log.Debug("[api] register rate limit exceeded", "method", method, "clientIP", clientIP, "txid", txid)
+		return nil, errRegisterRateExceeded
+	}
+	if !allowRegisterRate(clientIP) {
+		atomic.AddUint64(&metrics.RejectedByRateLimit, 1)
+		log.Debug("[api] register rate limited", "method", method, "clientIP", clientIP, "txid", txid)
+		return nil, errRegisterRateLimited
+	}
+	if !acquireRegisterSlot(clientIP, bind) {
+		atomic.AddUint64(&metrics.RejectedByInProcess, 1)
+		log.Debug("[api] too many registers in process", "method", method, "clientIP", clientIP, "bind", bind, "txid", txid)
+		return nil, errRegisterTooManyInProcess
+	}
+	defer releaseRegisterSlot(clientIP, bind)
+	return register()
+}
+
+func getTxNotFoundCacheTTL() int64 {
+	ttl := params.GetServerConfig().TxNotFoundCacheTTL
+	if ttl == 0 {
+		ttl = defaultTxNotFoundCacheTTL
+	}
+	return ttl
+}
+
+func getTxNotFoundCacheKey(txid string, isSwapin bool) string {
+	if isSwapin {
+		return "in:" + strings.ToLower(txid)
+	}
+	return "out:" + strings.ToLower(txid)
+}
+
+// isCachedTxNotFound check if txid is not found on chain recently
+func isCachedTxNotFound(method, txid string, isSwapin bool) bool {
+	key := getTxNotFoundCacheKey(txid, isSwapin)
+	expire, exist := txNotFoundCache.Load(key)
+	if !exist {
+		return false
+	}
+	if time.Now().Unix() >= expire.(int64) {
+		txNotFoundCache.Delete(key)
+		atomic.AddInt64(&txNotFoundCacheSize, -1)
+		return false
+	}
+	atomic.AddUint64(&getRegisterLimitMetrics(method).TxNotFoundCacheHits, 1)
+	return true
+}
+
+// cacheTxNotFound remember txid is not found on chain
+func cacheTxNotFound(txid string, isSwapin bool) {
+	ttl := getTxNotFoundCacheTTL()
+	if ttl < 0 {
+		return
+	}
+	now := time.Now().Unix()
+	if atomic.LoadInt64(&txNotFoundCacheSize) >= maxTxNotFoundCacheSize {
+		txNotFoundCache.Range(func(k, v interface{}) bool {
+			if now >= v.(int64) {
+				txNotFoundCache.Delete(k)
+				atomic.AddInt64(&txNotFoundCacheSize, -1)
+			}
+			return true
+		})
+		if atomic.LoadInt64(&txNotFoundCacheSize) >= maxTxNotFoundCacheSize {
+			return
+		}
+	}
+	if _, loaded := txNotFoundCache.LoadOrStore(getTxNotFoundCacheKey(txid, isSwapin), now+ttl); !loaded {
+		atomic.AddInt64(&txNotFoundCacheSize, 1)
+	}
+}
+
+// checkCachedTxNotFound returns the verify error if txid is not found on chain recently
+func checkCachedTxNotFound(method, txid string, isSwapin bool) error {
+	if isCachedTxNotFound(method, txid, isSwapin) {
+		return newRPCError(-32099, "verify swap failed! "+tokens.ErrTxNotFound.Error())
+	}
+	return nil
+}
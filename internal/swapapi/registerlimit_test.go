@@ -0,0 +1,79 @@
+package swapapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+func TestLimitRegister(t *testing.T) {
+	params.SetConfig(&params.BridgeConfig{Server: &params.ServerConfig{
+		RegisterMaxConcurrentPerBind: 1,
+		RegisterMaxConcurrentPerIP:   2,
+		RegisterRatePerIP:            100,
+	}})
+	const txid = "0x0000000000000000000000000000000000000000000000000000000000000001"
+	verifyCalls := 0
+	verify := func() (*PostResult, error) {
+		verifyCalls++
+		return &SuccessPostResult, nil
+	}
+
+	for _, badTxid := range []string{"", "0x01", txid + "00", "0x000000000000000000000000000000000000000000000000000000000000000g"} {
+		if _, err := limitRegister(RegisterMethodP2shSwapin, "1.1.1.1", "bind", badTxid, verify); !errors.Is(err, errWrongTxHashFormat) {
+			t.Errorf("txid %q should be rejected by prefilter, have %v", badTxid, err)
+		}
+	}
+	if verifyCalls != 0 || GetRegisterLimitMetrics()[RegisterMethodP2shSwapin].RejectedByPrefilter != 4 {
+		t.Errorf("prefilter rejected txids should not be verified and be counted, have %v verify calls, metrics %+v", verifyCalls, GetRegisterLimitMetrics()[RegisterMethodP2shSwapin])
+	}
+
+	// the same bind is being verified
+	if !acquireRegisterSlot("2.2.2.2", "bind") {
+		t.Fatal("acquire register slot failed")
+	}
+	if _, err := limitRegister(RegisterMethodP2shSwapin, "1.1.1.1", "BIND", txid, verify); !errors.Is(err, errRegisterTooManyInProcess) {
+		t.Errorf("concurrent verification of bind should be rejected, have %v", err)
+	}
+	releaseRegisterSlot("2.2.2.2", "bind")
+	if _, err := limitRegister(RegisterMethodP2shSwapin, "1.1.1.1", "bind", txid[2:], verify); err != nil || verifyCalls != 1 {
+		t.Errorf("register should be verified after slot released, have %v verify calls, err %v", verifyCalls, err)
+	}
+	if len(inProcessPerIP) != 0 || len(inProcessPerBind) != 0 {
+		t.Errorf("register slots leaked, ip %v, bind %v", inProcessPerIP, inProcessPerBind)
+	}
+
+	if isCachedTxNotFound(RegisterMethodSwapin, txid, true) {
+		t.Error("txid is not cached as not found")
+	}
+	cacheTxNotFound(txid, true)
+	if !isCachedTxNotFound(RegisterMethodSwapin, txid, true) || isCachedTxNotFound(RegisterMethodSwapout, txid, false) {
+		t.Error("txid should be cached as not found only on its chain")
+	}
+}
+
+func TestClientIPFromRequest(t *testing.T) {
+	params.SetConfig(&params.BridgeConfig{Server: &params.ServerConfig{
+		TrustedProxies: []string{"10.0.0.0/8", "192.168.1.1"},
+	}})
+
+	cases := []struct {
+		remoteAddr   string
+		forwardedFor []string
+		want         string
+	}{
+		{"1.1.1.1:1234", nil, "1.1.1.1"},
+		{"1.1.1.1:1234", []string{"2.2.2.2"}, "1.1.1.1"},                        // not from trusted proxy
+		{"10.1.2.3:1234", []string{"2.2.2.2"}, "2.2.2.2"},                       // from trusted proxy
+		{"10.1.2.3:1234", []string{"3.3.3.3, 2.2.2.2, 192.168.1.1"}, "2.2.2.2"}, // chained proxies
+		{"10.1.2.3:1234", []string{"3.3.3.3", "2.2.2.2"}, "2.2.2.2"},            // multiple headers
+		{"10.1.2.3:1234", []string{"3.3.3.3, bad"}, "10.1.2.3"},                 // malformed entry
+		{"10.1.2.3:1234", []string{"10.2.2.2"}, "10.2.2.2"},                     // all trusted
+	}
+	for _, c := range cases {
+		if have := ClientIPFromRequest(c.remoteAddr, c.forwardedFor); have != c.want {
+			t.Errorf("remote %v forwarded %v: want %v, have %v", c.remoteAddr, c.forwardedFor, c.want, have)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package swapapi
+
+import (
+	"crypto/ecdsa"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tools"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+	"github.com/anyswap/CrossChain-Bridge/tools/respsign"
+)
+
+var (
+	errResponseSigningDisabled = newRPCError(-32089, "response signing is not enabled")
+
+	responseSigningKey *ecdsa.PrivateKey
+)
+
+// SigningKeyInfo response signing key info
+type SigningKeyInfo struct {
+	Identifier string `json:"identifier"`
+	Address    string `json:"address"`
+	PublicKey  string `json:"publicKey"`
+}
+
+// InitResponseSigner load the operator key for signing responses
+func InitResponseSigner() error {
+	apiServer := params.GetServerConfig().APIServer
+	if apiServer == nil || apiServer.SigningKeyFile == "" {
+		return nil
+	}
+	key, err := tools.LoadKeyStore(apiServer.SigningKeyFile, apiServer.SigningPasswordFile)
+	if err != nil {
+		return err
+	}
+	responseSigningKey = key.PrivateKey
+	log.Info("init response signer success", "address", key.Address.String())
+	return nil
+}
+
+// GetSigningKey api
+func GetSigningKey() (*SigningKeyInfo, error) {
+	if responseSigningKey == nil {
+		return nil, errResponseSigningDisabled
+	}
+	return &SigningKeyInfo{
+		Identifier: params.GetIdentifier(),
+		Address:    crypto.PubkeyToAddress(responseSigningKey.PublicKey).String(),
+		PublicKey:  common.ToHex(crypto.FromECDSAPub(&responseSigningKey.PublicKey)),
+	}, nil
+}
+
+// SignSwapInfo sign swap info with the operator key
+func SignSwapInfo(info *SwapInfo) error {
+	if responseSigningKey == nil {
+		return errResponseSigningDisabled
+	}
+	info.Proof = nil
+	proof, err := respsign.Sign(info, params.GetIdentifier(), time.Now().Unix(), responseSigningKey)
+	if err != nil {
+		return newRPCInternalError(err)
+	}
+	info.Proof = proof
+	return nil
+}
@@ -0,0 +1,141 @@
+package swapapi
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// aggregate swap results by pair and status, replaced in tests
+var aggregateSwapResultsByPair = mongodb.AggregateSwapResultsByPair
+
+// DirectionStatistics swap statistics of pair in one direction,
+// volume and fee are sums of success swaps in smallest unit of deposit token.
+type DirectionStatistics struct {
+	Total   int64  `json:"total"`
+	Pending int64  `json:"pending"`
+	Success int64  `json:"success"`
+	Failed  int64  `json:"failed"`
+	Manual  int64  `json:"manual"`
+	Volume  string `json:"volume"`
+	Fee     string `json:"fee"`
+}
+
+// SwapStatistics swap statistics of pair
+type SwapStatistics struct {
+	Swapin  *DirectionStatistics `json:"swapin,omitempty"`
+	Swapout *DirectionStatistics `json:"swapout,omitempty"`
+}
+
+// AllSwapStatistics swap statistics of all pairs with records and grand totals,
+// fees of pairs are summed by deposit token symbol. fees in native coins of source
+// and dest chain are converted to reference currency if native price is configured.
+type AllSwapStatistics struct {
+	Pairs         map[string]*SwapStatistics `json:"pairs"`
+	TotalSwapins  int64                      `json:"totalSwapins"`
+	TotalSwapouts int64                      `json:"totalSwapouts"`
+	TotalFees     map[string]string          `json:"totalFees"`
+	ConvertedFees []*tokens.ConvertedValue   `json:"convertedFees,omitempty"`
+	GeneratedAt   int64                      `json:"generatedAt"`
+}
+
+// GetAllSwapStatistics api, one aggregation of each direction instead of a query of each pair
+func GetAllSwapStatistics() (*AllSwapStatistics, error) {
+	categories := make(map[mongodb.SwapStatus]mongodb.SwapStatusCategory)
+	for _, info := range mongodb.GetStatusCatalog() {
+		categories[info.Code] = info.Category
+	}
+	result := &AllSwapStatistics{
+		Pairs:     make(map[string]*SwapStatistics),
+		TotalFees: make(map[string]string),
+	}
+	totalFees := make(map[string]*big.Int)
+	nativeFees := make(map[bool]*big.Int) // key is isSrc
+	for _, isSwapin := range []bool{true, false} {
+		groups, err := aggregateSwapResultsByPair(isSwapin)
+		if err != nil {
+			return nil, err
+		}
+		stats := make(map[string]*DirectionStatistics)
+		volumes := make(map[string]*big.Int)
+		fees := make(map[string]*big.Int)
+		for _, group := range groups {
+			stat, exist := stats[group.PairID]
+			if !exist {
+				stat = &DirectionStatistics{}
+				stats[group.PairID] = stat
+				volumes[group.PairID] = big.NewInt(0)
+				fees[group.PairID] = big.NewInt(0)
+			}
+			stat.Total += group.Count
+			switch categories[group.Status] {
+			case mongodb.StatusCategorySuccess:
+				stat.Success += group.Count
+				volumes[group.PairID].Add(volumes[group.PairID], group.Value)
+				fees[group.PairID].Add(fees[group.PairID], calcStatisticsFee(group.PairID, group.Value, group.SwapValue, isSwapin))
+			case mongodb.StatusCategoryFailed:
+				stat.Failed += group.Count
+			case mongodb.StatusCategoryManual:
+				stat.Manual += group.Count
+			default:
+				stat.Pending += group.Count
+			}
+		}
+		for pairID, stat := range stats {
+			stat.Volume = volumes[pairID].String()
+			stat.Fee = fees[pairID].String()
+			pairStats, exist := result.Pairs[pairID]
+			if !exist {
+				pairStats = &SwapStatistics{}
+				result.Pairs[pairID] = pairStats
+			}
+			if isSwapin {
+				pairStats.Swapin = stat
+				result.TotalSwapins += stat.Total
+			} else {
+				pairStats.Swapout = stat
+				result.TotalSwapouts += stat.Total
+			}
+			if tokenCfg := tokens.GetTokenConfig(pairID, isSwapin); tokenCfg != nil && fees[pairID].Sign() > 0 {
+				if totalFees[tokenCfg.Symbol] == nil {
+					totalFees[tokenCfg.Symbol] = big.NewInt(0)
+				}
+				totalFees[tokenCfg.Symbol].Add(totalFees[tokenCfg.Symbol], fees[pairID])
+				// deposit token of swapin is on source chain
+				if tokenCfg.IsNativeCoin(isSwapin) {
+					if nativeFees[isSwapin] == nil {
+						nativeFees[isSwapin] = big.NewInt(0)
+					}
+					nativeFees[isSwapin].Add(nativeFees[isSwapin], fees[pairID])
+				}
+			}
+		}
+	}
+	for symbol, fee := range totalFees {
+		result.TotalFees[symbol] = fee.String()
+	}
+	for _, isSrc := range []bool{true, false} {
+		if fee := nativeFees[isSrc]; fee != nil {
+			result.ConvertedFees = append(result.ConvertedFees, tokens.ConvertNativeValue(isSrc, fee))
+		}
+	}
+	result.GeneratedAt = time.Now().Unix()
+	return result, nil
+}
+
+// calcStatisticsFee fee is deposit value minus swapped value, in deposit token unit
+func calcStatisticsFee(pairID string, value, swapValue *big.Int, isSwapin bool) *big.Int {
+	fromTokenCfg := tokens.GetTokenConfig(pairID, isSwapin)
+	toTokenCfg := tokens.GetTokenConfig(pairID, !isSwapin)
+	if fromTokenCfg == nil || toTokenCfg == nil || fromTokenCfg.Decimals == nil || toTokenCfg.Decimals == nil {
+		return big.NewInt(0)
+	}
+	swapValue = tokens.ConvertTokenValue(swapValue, *toTokenCfg.Decimals, *fromTokenCfg.Decimals)
+	fee := new(big.Int).Sub(value, swapValue)
+	if fee.Sign() < 0 {
+		return big.NewInt(0)
+	}
+	return fee
+}
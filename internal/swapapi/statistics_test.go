@@ -0,0 +1,78 @@
+package swapapi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func TestGetAllSwapStatistics(t *testing.T) {
+	decimals8, decimals18 := uint8(8), uint8(18)
+	tokens.SetTokenPairsConfig(map[string]*tokens.TokenPairConfig{
+		"btc": {
+			PairID:    "btc",
+			SrcToken:  &tokens.TokenConfig{Symbol: "BTC", Decimals: &decimals8},
+			DestToken: &tokens.TokenConfig{Symbol: "anyBTC", Decimals: &decimals18},
+		},
+	}, false)
+	defer tokens.SetTokenPairsConfig(map[string]*tokens.TokenPairConfig{}, false)
+
+	// static price of source native coin, dest price is missing
+	tokens.NativePriceCfg = &tokens.NativePriceConfig{
+		Currency: "USD",
+		Src:      &tokens.NativeCoinConfig{Symbol: "BTC", Decimals: 8, Price: 20000},
+		Dest:     &tokens.NativeCoinConfig{Symbol: "ETH", Decimals: 18},
+	}
+	defer func() { tokens.NativePriceCfg = nil }()
+	tokens.InitNativePrices()
+
+	oldAggregate := aggregateSwapResultsByPair
+	defer func() { aggregateSwapResultsByPair = oldAggregate }()
+	aggregateSwapResultsByPair = func(isSwapin bool) ([]*mongodb.PairStatusStat, error) {
+		if !isSwapin {
+			return nil, nil // no swapout records
+		}
+		return []*mongodb.PairStatusStat{
+			// swap value 99 BTC in 18 decimals, fee is 1 BTC in 8 decimals
+			{PairID: "btc", Status: mongodb.MatchTxStable, Count: 3, Value: big.NewInt(100e8), SwapValue: new(big.Int).Mul(big.NewInt(99e8), big.NewInt(1e10))},
+			{PairID: "btc", Status: mongodb.TxNotStable, Count: 2, Value: big.NewInt(5), SwapValue: big.NewInt(0)},
+			{PairID: "btc", Status: mongodb.TxVerifyFailed, Count: 1, Value: big.NewInt(0), SwapValue: big.NewInt(0)},
+			{PairID: "btc", Status: mongodb.TxWithBigValue, Count: 1, Value: big.NewInt(1e12), SwapValue: big.NewInt(0)},
+			// unconfigured pair is still counted
+			{PairID: "old", Status: mongodb.MatchTxStable, Count: 4, Value: big.NewInt(10), SwapValue: big.NewInt(8)},
+		}, nil
+	}
+
+	stats, err := GetAllSwapStatistics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	btc := stats.Pairs["btc"]
+	if btc == nil || btc.Swapout != nil || btc.Swapin == nil {
+		t.Fatalf("want only swapin statistics of btc, have %+v", btc)
+	}
+	want := DirectionStatistics{Total: 7, Pending: 2, Success: 3, Failed: 1, Manual: 1, Volume: "10000000000", Fee: "100000000"}
+	if *btc.Swapin != want {
+		t.Errorf("want btc swapin statistics %+v, have %+v", want, *btc.Swapin)
+	}
+	if old := stats.Pairs["old"]; old == nil || old.Swapin.Total != 4 || old.Swapin.Fee != "0" {
+		t.Errorf("want unconfigured pair counted without fee, have %+v", old)
+	}
+	if stats.TotalSwapins != 11 || stats.TotalSwapouts != 0 {
+		t.Errorf("want totals 11/0, have %v/%v", stats.TotalSwapins, stats.TotalSwapouts)
+	}
+	if len(stats.TotalFees) != 1 || stats.TotalFees["BTC"] != "100000000" {
+		t.Errorf("want total fees of BTC, have %v", stats.TotalFees)
+	}
+	if len(stats.ConvertedFees) != 1 {
+		t.Fatalf("want converted fees of source native coin, have %v", stats.ConvertedFees)
+	}
+	if converted := stats.ConvertedFees[0]; converted.Symbol != "BTC" || converted.Value == nil || *converted.Value != 20000 {
+		t.Errorf("want 1 BTC fee converted to 20000 USD, have %+v", converted)
+	}
+	if stats.GeneratedAt == 0 {
+		t.Errorf("generatedAt is not set")
+	}
+}
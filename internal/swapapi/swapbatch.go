@@ -0,0 +1,126 @@
+package swapapi
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const (
+	maxSwapBatchSize     = 50
+	swapBatchConcurrency = 5
+
+	// SwapBatchAlreadyRegistered result of txid which is already registered
+	SwapBatchAlreadyRegistered = AlreadyRegisteredPostResult
+)
+
+var (
+	errSwapBatchEmpty    = newRPCError(-32067, "empty batch txids")
+	errSwapBatchTooLarge = newRPCError(-32066, "too many txids in batch")
+)
+
+// SwapBatchResult register result of each txid, 'Success' or error message
+type SwapBatchResult map[string]string
+
+// SwapinBatch api
+func SwapinBatch(clientIP string, txids []string, pairID string) (SwapBatchResult, error) {
+	log.Debug("[api] receive SwapinBatch", "count", len(txids), "pairID", pairID, "clientIP", clientIP)
+	return swapBatch(clientIP, txids, pairID, true)
+}
+
+// SwapoutBatch api
+func SwapoutBatch(clientIP string, txids []string, pairID string) (SwapBatchResult, error) {
+	log.Debug("[api] receive SwapoutBatch", "count", len(txids), "pairID", pairID, "clientIP", clientIP)
+	return swapBatch(clientIP, txids, pairID, false)
+}
+
+func swapBatch(clientIP string, txids []string, pairID string, isSwapin bool) (SwapBatchResult, error) {
+	if len(txids) == 0 {
+		return nil, errSwapBatchEmpty
+	}
+	if len(txids) > maxSwapBatchSize {
+		return nil, errSwapBatchTooLarge
+	}
+	if err := basicCheckSwapRegister(tokens.GetCrossChainBridge(isSwapin), pairID); err != nil {
+		return nil, err
+	}
+	method := RegisterMethodSwapout
+	if isSwapin {
+		method = RegisterMethodSwapin
+	}
+	// a batch occupies one verification slot of client ip
+	if !acquireRegisterSlot(clientIP, "") {
+		atomic.AddUint64(&getRegisterLimitMetrics(method).RejectedByInProcess, 1)
+		return nil, errRegisterTooManyInProcess
+	}
+	defer releaseRegisterSlot(clientIP, "")
+	return processSwapBatch(method, clientIP, pairID, isSwapin, txids, swapBulkRegisterer{}), nil
+}
+
+// processSwapBatch verify and register txids with bounded concurrency,
+// failure of one txid does not abort the others.
+func processSwapBatch(method, clientIP, pairID string, isSwapin bool, txids []string, registerer bulkRegisterer) SwapBatchResult {
+	result := make(SwapBatchResult, len(txids))
+	var resultLock sync.Mutex
+	setResult := func(txid, res string) {
+		resultLock.Lock()
+		result[txid] = res
+		resultLock.Unlock()
+	}
+
+	metrics := getRegisterLimitMetrics(method)
+	seen := make(map[string]struct{}, len(txids))
+	sem := make(chan struct{}, swapBatchConcurrency)
+	var wg sync.WaitGroup
+	for _, txid := range txids {
+		txid = strings.TrimSpace(txid)
+		key := strings.ToLower(txid)
+		if _, isDup := seen[key]; isDup {
+			continue
+		}
+		seen[key] = struct{}{}
+		if !isValidTxHashFormat(txid) {
+			atomic.AddUint64(&metrics.RejectedByPrefilter, 1)
+			setResult(txid, errWrongTxHashFormat.Error())
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(txid string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if registerer.IsRegistered(isSwapin, txid, pairID) {
+				setResult(txid, SwapBatchAlreadyRegistered)
+				return
+			}
+			if !allowRegisterTokens(method, clientIP, txid) {
+				atomic.AddUint64(&metrics.RejectedByRateLimit, 1)
+				setResult(txid, errRegisterRateExceeded.Error())
+				return
+			}
+			if !allowRegisterRate(clientIP) {
+				atomic.AddUint64(&metrics.RejectedByRateLimit, 1)
+				setResult(txid, errRegisterRateLimited.Error())
+				return
+			}
+			err := registerer.Register(isSwapin, txid, pairID)
+			switch {
+			case errors.Is(err, mongodb.ErrItemIsDup):
+				setResult(txid, SwapBatchAlreadyRegistered)
+			case err != nil:
+				setResult(txid, err.Error())
+			default:
+				setResult(txid, string(SuccessPostResult))
+			}
+		}(txid)
+	}
+	wg.Wait()
+	return result
+}
@@ -0,0 +1,66 @@
+package swapapi
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+type syncBulkRegisterer struct {
+	lock sync.Mutex
+	memBulkRegisterer
+}
+
+func (r *syncBulkRegisterer) IsRegistered(isSwapin bool, txid, pairID string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.memBulkRegisterer.IsRegistered(isSwapin, txid, pairID)
+}
+
+func (r *syncBulkRegisterer) Register(isSwapin bool, txid, pairID string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.memBulkRegisterer.Register(isSwapin, txid, pairID)
+}
+
+func TestProcessSwapBatch(t *testing.T) {
+	params.SetConfig(&params.BridgeConfig{Server: &params.ServerConfig{RegisterRatePerIP: 1000}})
+	const (
+		txNew   = "0x0000000000000000000000000000000000000000000000000000000000000001"
+		txKnown = "0x0000000000000000000000000000000000000000000000000000000000000002"
+		txDup   = "0x0000000000000000000000000000000000000000000000000000000000000003"
+		txFail  = "0x0000000000000000000000000000000000000000000000000000000000000004"
+	)
+	registerer := &syncBulkRegisterer{memBulkRegisterer: memBulkRegisterer{
+		registered: map[string]bool{txKnown: true},
+		failures: map[string]error{
+			txDup:  mongodb.ErrItemIsDup,
+			txFail: errors.New("verify swap failed"),
+		},
+	}}
+
+	txids := []string{txNew, txKnown, txDup, txFail, "0x01", " " + txNew + " "}
+	result := processSwapBatch(RegisterMethodSwapin, "3.3.3.3", "pair", true, txids, registerer)
+
+	want := SwapBatchResult{
+		txNew:   string(SuccessPostResult),
+		txKnown: SwapBatchAlreadyRegistered,
+		txDup:   SwapBatchAlreadyRegistered,
+		txFail:  "verify swap failed",
+		"0x01":  errWrongTxHashFormat.Error(),
+	}
+	if len(result) != len(want) {
+		t.Fatalf("want %v results, have %v", want, result)
+	}
+	for txid, res := range want {
+		if result[txid] != res {
+			t.Errorf("txid %v: want result %q, have %q", txid, res, result[txid])
+		}
+	}
+	if len(registerer.calls) != 3 {
+		t.Errorf("want 3 register calls, have %v", registerer.calls)
+	}
+}
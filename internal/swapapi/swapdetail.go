@@ -0,0 +1,99 @@
+package swapapi
+
+import (
+	"sort"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+)
+
+// audit trail entry kinds
+const (
+	AuditTrailKindAudit = "audit"
+	AuditTrailKindNote  = "note"
+)
+
+var (
+	// operator records of swap, replaced in tests
+	findSwapNotes   = mongodb.GetSwapNotes
+	findAdminAudits = mongodb.GetAdminAudits
+)
+
+// SwapDetail admin level detail of swap, with dcrm sign attempts,
+// operator notes and admin audits
+type SwapDetail struct {
+	*SwapInfo
+	SignAttempts          []*mongodb.MgoSignAttempt `json:"signAttempts,omitempty"`
+	CurrentSignAttemptAge int64                     `json:"currentSignAttemptAge,omitempty"` // seconds, if still signing
+	Notes                 []*mongodb.MgoSwapNote    `json:"notes"`
+	Audits                []*mongodb.MgoAdminAudit  `json:"audits"`
+}
+
+// AuditTrailEntry admin audit or operator note of swap in audit trail
+type AuditTrailEntry struct {
+	Timestamp int64      `json:"timestamp"`
+	Kind      string     `json:"kind"`
+	Operator  string     `json:"operator"`
+	Operation string     `json:"operation,omitempty"`
+	OldStatus SwapStatus `json:"oldstatus,omitempty"`
+	NewStatus SwapStatus `json:"newstatus,omitempty"`
+	Text      string     `json:"text"` // reason of audit, or content of note
+}
+
+// GetSwapDetail get swap detail for admins
+func GetSwapDetail(isSwapin bool, txid, pairID, bind string) (*SwapDetail, error) {
+	dir := SwapDirection(isSwapin)
+	info, err := getSwap(dir, txid, pairID, bind)
+	if err != nil {
+		return nil, err
+	}
+	detail := &SwapDetail{SwapInfo: info}
+	if res, errf := getRawSwapResult(dir, txid, pairID, info.Bind); errf == nil {
+		detail.SignAttempts = res.SignAttempts
+		detail.CurrentSignAttemptAge = CurrentSignAttemptAge(res.SignAttempts, time.Now().Unix())
+	}
+	detail.Notes, err = findSwapNotes(isSwapin, txid, pairID, info.Bind)
+	if err != nil {
+		return nil, err
+	}
+	detail.Audits, err = findAdminAudits(isSwapin, txid, pairID, info.Bind)
+	if err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+// ExportAuditTrail export admin audits and operator notes of swap in time order
+func ExportAuditTrail(isSwapin bool, txid, pairID, bind string) ([]*AuditTrailEntry, error) {
+	audits, err := findAdminAudits(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := findSwapNotes(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return nil, err
+	}
+	trail := make([]*AuditTrailEntry, 0, len(audits)+len(notes))
+	for _, audit := range audits {
+		trail = append(trail, &AuditTrailEntry{
+			Timestamp: audit.Timestamp,
+			Kind:      AuditTrailKindAudit,
+			Operator:  audit.Operator,
+			Operation: audit.Operation,
+			OldStatus: audit.OldStatus,
+			NewStatus: audit.NewStatus,
+			Text:      audit.Reason,
+		})
+	}
+	for _, note := range notes {
+		trail = append(trail, &AuditTrailEntry{
+			Timestamp: note.Timestamp,
+			Kind:      AuditTrailKindNote,
+			Operator:  note.Operator,
+			Text:      note.Note,
+		})
+	}
+	// audits and notes are each in adding order, keep it for the same time
+	sort.SliceStable(trail, func(i, j int) bool { return trail[i].Timestamp < trail[j].Timestamp })
+	return trail, nil
+}
@@ -0,0 +1,104 @@
+package swapapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+)
+
+func useMemOperatorRecords(t *testing.T, notes []*mongodb.MgoSwapNote, audits []*mongodb.MgoAdminAudit) {
+	oldNotes, oldAudits := findSwapNotes, findAdminAudits
+	t.Cleanup(func() { findSwapNotes, findAdminAudits = oldNotes, oldAudits })
+	findSwapNotes = func(isSwapin bool, txid, pairID, bind string) ([]*mongodb.MgoSwapNote, error) {
+		var res []*mongodb.MgoSwapNote
+		for _, note := range notes {
+			if note.IsSwapin == isSwapin && note.SwapKey == mongodb.GetSwapKey(txid, pairID, bind) {
+				res = append(res, note)
+			}
+		}
+		return res, nil
+	}
+	findAdminAudits = func(isSwapin bool, txid, pairID, bind string) ([]*mongodb.MgoAdminAudit, error) {
+		var res []*mongodb.MgoAdminAudit
+		for _, audit := range audits {
+			if audit.IsSwapin == isSwapin && audit.SwapKey == mongodb.GetSwapKey(txid, pairID, bind) {
+				res = append(res, audit)
+			}
+		}
+		return res, nil
+	}
+}
+
+func TestGetSwapDetail(t *testing.T) {
+	memStore, restore := useMemSwapStore()
+	defer restore()
+	addMirroredSwaps(memStore)
+	key := mongodb.GetSwapKey("0xswapped", "pair", "bind")
+	memStore.results[true][key].SignAttempts = []*mongodb.MgoSignAttempt{
+		{KeyID: "key1", InitiatedAt: 1, FinishedAt: 2, DcrmStatus: "Failure"},
+		{KeyID: "key2", InitiatedAt: time.Now().Unix() - 60, DcrmStatus: "Pending"},
+	}
+	useMemOperatorRecords(t,
+		[]*mongodb.MgoSwapNote{
+			{SwapKey: key, IsSwapin: true, Operator: "op1", Note: "checking", Timestamp: 20},
+			{SwapKey: key, IsSwapin: false, Operator: "op1", Note: "other direction", Timestamp: 20},
+		},
+		[]*mongodb.MgoAdminAudit{
+			{SwapKey: key, IsSwapin: true, Operator: "op2", Operation: mongodb.AdminPassSwapOp, Reason: "verified", Timestamp: 10},
+		},
+	)
+
+	// empty bind matches the only swap of txid
+	detail, err := GetSwapDetail(true, "0xswapped", "pair", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Status != mongodb.MatchTxStable || detail.SwapTx != "0xswaptx" {
+		t.Errorf("want swap result in detail, have %+v", detail.SwapInfo)
+	}
+	if len(detail.SignAttempts) != 2 || detail.CurrentSignAttemptAge < 60 {
+		t.Errorf("want sign attempts and current attempt age, have %v %v", detail.SignAttempts, detail.CurrentSignAttemptAge)
+	}
+	if len(detail.Notes) != 1 || detail.Notes[0].Note != "checking" || len(detail.Audits) != 1 {
+		t.Errorf("want notes and audits of swapin, have %v %v", detail.Notes, detail.Audits)
+	}
+	if _, err = GetSwapDetail(true, "0xunknown", "pair", "bind"); err != mongodb.ErrSwapNotFound {
+		t.Errorf("want swap not found error, have %v", err)
+	}
+}
+
+func TestExportAuditTrail(t *testing.T) {
+	key := mongodb.GetSwapKey("0xtx", "pair", "bind")
+	useMemOperatorRecords(t,
+		[]*mongodb.MgoSwapNote{
+			{SwapKey: key, IsSwapin: true, Operator: "op1", Note: "first note", Timestamp: 5},
+			{SwapKey: key, IsSwapin: true, Operator: "op1", Note: "second note", Timestamp: 10},
+		},
+		[]*mongodb.MgoAdminAudit{
+			{SwapKey: key, IsSwapin: true, Operator: "op2", Operation: mongodb.AdminFailSwapOp,
+				OldStatus: mongodb.TxNotSwapped, NewStatus: mongodb.ManualMakeFail, Reason: "stuck", Timestamp: 10},
+		},
+	)
+
+	trail, err := ExportAuditTrail(true, "0xtx", "pair", "bind")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"first note", "stuck", "second note"} // audit first at the same time
+	if len(trail) != len(want) {
+		t.Fatalf("want %v entries, have %v", len(want), len(trail))
+	}
+	for i, entry := range trail {
+		if entry.Text != want[i] {
+			t.Errorf("entry %v: want %q, have %q", i, want[i], entry.Text)
+		}
+	}
+	if audit := trail[1]; audit.Kind != AuditTrailKindAudit || audit.Operator != "op2" ||
+		audit.Operation != mongodb.AdminFailSwapOp || audit.NewStatus != mongodb.ManualMakeFail {
+		t.Errorf("wrong audit entry %+v", audit)
+	}
+	if note := trail[0]; note.Kind != AuditTrailKindNote || note.Operator != "op1" {
+		t.Errorf("wrong note entry %+v", note)
+	}
+}
@@ -0,0 +1,31 @@
+package swapapi
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+const (
+	defaultSwapEventsLimit = 100
+	maxSwapEventsLimit     = 1000
+)
+
+var errSwapEventsDisabled = newRPCError(-32063, "swap events are not enabled")
+
+// SwapEvent type alias
+type SwapEvent = mongodb.MgoSwapEvent
+
+// GetSwapEvents api, events with sequence number greater than sinceSeq in order,
+// consumers tail events by passing the last seen sequence number.
+func GetSwapEvents(sinceSeq int64, limit int) ([]*SwapEvent, error) {
+	if !params.GetServerConfig().EnableSwapEvents {
+		return nil, errSwapEventsDisabled
+	}
+	switch {
+	case limit <= 0:
+		limit = defaultSwapEventsLimit
+	case limit > maxSwapEventsLimit:
+		limit = maxSwapEventsLimit
+	}
+	return mongodb.FindSwapEvents(sinceSeq, limit)
+}
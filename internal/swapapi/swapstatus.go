@@ -0,0 +1,167 @@
+package swapapi
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const (
+	// entries are invalidated on changes written by this process only,
+	// a short ttl bounds staleness when several servers share the database
+	swapStatusCacheTTL        = 10 * time.Second
+	maxSwapStatusCacheEntries = 100000
+)
+
+var (
+	swapStatusCache     = make(map[string]*swapStatusEntry)
+	swapStatusCacheLock sync.RWMutex
+
+	// findSwapStatus find swap status in database, nil entry if not found
+	findSwapStatus = findMgoSwapStatus
+)
+
+// SwapPollStatus simplified swap status for high frequency polling
+type SwapPollStatus struct {
+	SwapType      uint32     `json:"swaptype"`
+	Status        SwapStatus `json:"status"`
+	StatusMsg     string     `json:"statusmsg"`
+	SwapTx        string     `json:"swaptx"`
+	Confirmations uint64     `json:"confirmations"`
+}
+
+type swapStatusEntry struct {
+	status     SwapStatus
+	swapTx     string
+	swapHeight uint64
+	expireAt   time.Time
+}
+
+func init() {
+	mongodb.AddSwapChangeHandler(func(change *mongodb.SwapChange) {
+		invalidateSwapStatus(change.IsSwapin, change.Key)
+	})
+}
+
+func getSwapStatusCacheKey(isSwapin bool, key string) string {
+	if isSwapin {
+		return "swapin:" + key
+	}
+	return "swapout:" + key
+}
+
+// invalidateSwapStatus remove changed swap from cache,
+// including the entry queried without bind address
+func invalidateSwapStatus(isSwapin bool, key string) {
+	keys := []string{getSwapStatusCacheKey(isSwapin, key)}
+	if pos := strings.LastIndex(key, ":"); pos >= 0 {
+		keys = append(keys, getSwapStatusCacheKey(isSwapin, key[:pos+1]))
+	}
+	swapStatusCacheLock.Lock()
+	defer swapStatusCacheLock.Unlock()
+	for _, k := range keys {
+		delete(swapStatusCache, k)
+	}
+}
+
+func getCachedSwapStatus(cacheKey string) *swapStatusEntry {
+	swapStatusCacheLock.RLock()
+	defer swapStatusCacheLock.RUnlock()
+	entry, exist := swapStatusCache[cacheKey]
+	if !exist || time.Now().After(entry.expireAt) {
+		return nil
+	}
+	return entry
+}
+
+func cacheSwapStatus(cacheKey string, entry *swapStatusEntry) {
+	entry.expireAt = time.Now().Add(swapStatusCacheTTL)
+	swapStatusCacheLock.Lock()
+	defer swapStatusCacheLock.Unlock()
+	if len(swapStatusCache) >= maxSwapStatusCacheEntries {
+		now := time.Now()
+		for k, v := range swapStatusCache {
+			if now.After(v.expireAt) {
+				delete(swapStatusCache, k)
+			}
+		}
+		// drop arbitrary entries if all are alive
+		for k := range swapStatusCache {
+			if len(swapStatusCache) < maxSwapStatusCacheEntries {
+				break
+			}
+			delete(swapStatusCache, k)
+		}
+	}
+	swapStatusCache[cacheKey] = entry
+}
+
+func findMgoSwapStatus(isSwapin bool, txid, pairID, bind string) (*swapStatusEntry, error) {
+	if bind == "" {
+		register, err := mongodb.FindSwap(isSwapin, txid, pairID, "")
+		if errors.Is(err, mongodb.ErrSwapBindAmbiguous) {
+			return nil, err
+		}
+		if err == nil {
+			bind = register.Bind
+		}
+	}
+	result, err := mongodb.FindSwapResult(isSwapin, txid, pairID, bind)
+	if err == nil {
+		return &swapStatusEntry{status: result.Status, swapTx: result.SwapTx, swapHeight: result.SwapHeight}, nil
+	}
+	register, err := mongodb.FindSwap(isSwapin, txid, pairID, bind)
+	if err == nil {
+		return &swapStatusEntry{status: register.Status}, nil
+	}
+	if err == mongodb.ErrItemNotFound {
+		return nil, nil
+	}
+	return nil, err
+}
+
+// GetSwapStatus get simplified swap status of swapin or swapout,
+// recently queried swaps are served from cache until they are changed.
+func GetSwapStatus(txid, pairID, bind string) (*SwapPollStatus, error) {
+	key := mongodb.GetSwapKey(txid, pairID, bind)
+	for _, isSwapin := range []bool{true, false} {
+		if entry := getCachedSwapStatus(getSwapStatusCacheKey(isSwapin, key)); entry != nil {
+			return convertSwapStatusEntry(isSwapin, entry), nil
+		}
+	}
+	for _, isSwapin := range []bool{true, false} {
+		entry, err := findSwapStatus(isSwapin, txid, pairID, bind)
+		if err != nil {
+			return nil, err
+		}
+		if entry != nil {
+			cacheSwapStatus(getSwapStatusCacheKey(isSwapin, key), entry)
+			return convertSwapStatusEntry(isSwapin, entry), nil
+		}
+	}
+	return nil, mongodb.ErrSwapNotFound
+}
+
+func convertSwapStatusEntry(isSwapin bool, entry *swapStatusEntry) *SwapPollStatus {
+	swapType := tokens.SwapoutType
+	latest := tokens.SrcLatestBlockHeight
+	if isSwapin {
+		swapType = tokens.SwapinType
+		latest = tokens.DstLatestBlockHeight
+	}
+	var confirmations uint64
+	if entry.swapHeight != 0 && latest > entry.swapHeight {
+		confirmations = latest - entry.swapHeight
+	}
+	return &SwapPollStatus{
+		SwapType:      uint32(swapType),
+		Status:        entry.status,
+		StatusMsg:     entry.status.String(),
+		SwapTx:        entry.swapTx,
+		Confirmations: confirmations,
+	}
+}
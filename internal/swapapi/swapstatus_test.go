@@ -0,0 +1,105 @@
+package swapapi
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+)
+
+type countingSwapStatusFinder struct {
+	calls  int64
+	status map[string]SwapStatus // key is txid, swapin only
+}
+
+func (f *countingSwapStatusFinder) find(isSwapin bool, txid, pairID, bind string) (*swapStatusEntry, error) {
+	atomic.AddInt64(&f.calls, 1)
+	status, exist := f.status[txid]
+	if !isSwapin || !exist {
+		return nil, nil
+	}
+	return &swapStatusEntry{status: status, swapTx: "0xswap" + txid}, nil
+}
+
+func useSwapStatusFinder(finder *countingSwapStatusFinder) func() {
+	swapStatusCacheLock.Lock()
+	swapStatusCache = make(map[string]*swapStatusEntry)
+	swapStatusCacheLock.Unlock()
+	findSwapStatus = finder.find
+	return func() { findSwapStatus = findMgoSwapStatus }
+}
+
+func TestGetSwapStatusCache(t *testing.T) {
+	finder := &countingSwapStatusFinder{status: map[string]SwapStatus{"0xtx": mongodb.TxNotSwapped}}
+	defer useSwapStatusFinder(finder)()
+
+	for i := 0; i < 3; i++ {
+		res, err := GetSwapStatus("0xtx", "pair", "bind")
+		if err != nil || res.Status != mongodb.TxNotSwapped {
+			t.Fatalf("unexpected status %v, err %v", res, err)
+		}
+	}
+	if finder.calls != 1 {
+		t.Fatalf("want 1 database lookup, got %v", finder.calls)
+	}
+
+	finder.status["0xtx"] = mongodb.MatchTxNotStable
+	invalidateSwapStatus(true, mongodb.GetSwapKey("0xtx", "pair", "bind"))
+	res, err := GetSwapStatus("0xtx", "pair", "bind")
+	if err != nil || res.Status != mongodb.MatchTxNotStable || res.SwapTx != "0xswap0xtx" {
+		t.Fatalf("cache not invalidated, status %v, err %v", res, err)
+	}
+
+	if _, err = GetSwapStatus("0xunknown", "pair", "bind"); err != mongodb.ErrSwapNotFound {
+		t.Fatalf("want not found error, got %v", err)
+	}
+	calls := finder.calls
+	_, _ = GetSwapStatus("0xunknown", "pair", "bind")
+	if finder.calls == calls {
+		t.Fatal("not found results should not be cached")
+	}
+}
+
+func TestInvalidateSwapStatusWithoutBind(t *testing.T) {
+	finder := &countingSwapStatusFinder{status: map[string]SwapStatus{"0xtx": mongodb.TxNotSwapped}}
+	defer useSwapStatusFinder(finder)()
+
+	_, _ = GetSwapStatus("0xtx", "pair", "")
+	invalidateSwapStatus(true, mongodb.GetSwapKey("0xtx", "pair", "bind"))
+	_, _ = GetSwapStatus("0xtx", "pair", "")
+	if finder.calls != 2 {
+		t.Fatalf("entry queried without bind is not invalidated, lookups %v", finder.calls)
+	}
+}
+
+// BenchmarkGetSwapStatusPolling simulates wallets polling active swaps,
+// with a status transition every 50 polls of each swap.
+func BenchmarkGetSwapStatusPolling(b *testing.B) {
+	const activeSwaps = 1000
+	finder := &countingSwapStatusFinder{status: make(map[string]SwapStatus)}
+	txids := make([]string, activeSwaps)
+	for i := range txids {
+		txids[i] = fmt.Sprintf("0x%064x", i)
+		finder.status[txids[i]] = mongodb.TxNotSwapped
+	}
+	defer useSwapStatusFinder(finder)()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		txid := txids[i%activeSwaps]
+		if (i/activeSwaps)%50 == 49 {
+			invalidateSwapStatus(true, mongodb.GetSwapKey(txid, "pair", "bind"))
+		}
+		if _, err := GetSwapStatus(txid, "pair", "bind"); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+
+	hitRatio := 1 - float64(finder.calls)/float64(b.N)
+	b.ReportMetric(hitRatio*100, "%cached")
+	if b.N >= 100*activeSwaps && hitRatio < 0.95 {
+		b.Fatalf("cache hit ratio %.2f is below 95%%", hitRatio)
+	}
+}
@@ -1,13 +1,21 @@
 package swapapi
 
 import (
+	"github.com/anyswap/CrossChain-Bridge/internal/retry"
 	"github.com/anyswap/CrossChain-Bridge/mongodb"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tools/respsign"
 )
 
 // SwapStatus type alias
 type SwapStatus = mongodb.SwapStatus
 
+// RetryMetrics type alias
+type RetryMetrics = retry.Metrics
+
+// QuarantineMetrics type alias
+type QuarantineMetrics = mongodb.QuarantineMetrics
+
 // Swap type alias
 type Swap = mongodb.MgoSwap
 
@@ -17,9 +25,21 @@ type SwapResult = mongodb.MgoSwapResult
 // LatestScanInfo type alias
 type LatestScanInfo = mongodb.MgoLatestScanInfo
 
+// SwapStatusInfo type alias
+type SwapStatusInfo = mongodb.SwapStatusInfo
+
+// RegisterErrorEntry type alias
+type RegisterErrorEntry = tokens.RegisterErrorEntry
+
+// P2shAddress type alias
+type P2shAddress = mongodb.MgoP2shAddress
+
 // RegisteredAddress type alias
 type RegisteredAddress = mongodb.MgoRegisteredAddress
 
+// DailyReport type alias
+type DailyReport = mongodb.MgoDailyReport
+
 // ServerInfo server info
 type ServerInfo struct {
 	Identifier          string
@@ -28,6 +48,9 @@ type ServerInfo struct {
 	DestChain           *tokens.ChainConfig
 	PairIDs             []string
 	Version             string
+	APIVersions         []int // supported api versions, see 'X-API-Version' header
+	SrcChainStatus      *ChainHealth
+	DestChainStatus     *ChainHealth
 }
 
 // PostResult post result
@@ -36,28 +59,48 @@ type PostResult string
 // SuccessPostResult success post result
 var SuccessPostResult PostResult = "Success"
 
+// AlreadyRegisteredPostResult prefix of post result registering existing swap
+const AlreadyRegisteredPostResult = "already registered"
+
 // SwapInfo swap info
 type SwapInfo struct {
-	PairID        string     `json:"pairid"`
-	TxID          string     `json:"txid"`
-	TxTo          string     `json:"txto"`
-	TxHeight      uint64     `json:"txheight"`
-	From          string     `json:"from"`
-	To            string     `json:"to"`
-	Bind          string     `json:"bind"`
-	Value         string     `json:"value"`
-	SwapTx        string     `json:"swaptx"`
-	SwapHeight    uint64     `json:"swapheight"`
-	SwapValue     string     `json:"swapvalue"`
-	SwapType      uint32     `json:"swaptype"`
-	SwapNonce     uint64     `json:"swapnonce"`
-	Status        SwapStatus `json:"status"`
-	StatusMsg     string     `json:"statusmsg"`
-	InitTime      int64      `json:"inittime"`
-	Timestamp     int64      `json:"timestamp"`
-	Memo          string     `json:"memo"`
-	ReplaceCount  int        `json:"replaceCount"`
-	Confirmations uint64     `json:"confirmations"`
+	PairID        string          `json:"pairid"`
+	TxID          string          `json:"txid"`
+	TxTo          string          `json:"txto"`
+	TxHeight      uint64          `json:"txheight"`
+	From          string          `json:"from"`
+	To            string          `json:"to"`
+	Bind          string          `json:"bind"`
+	Value         string          `json:"value"`
+	SwapTx        string          `json:"swaptx"`
+	SwapHeight    uint64          `json:"swapheight"`
+	SwapValue     string          `json:"swapvalue"`
+	SwapType      uint32          `json:"swaptype"`
+	SwapNonce     uint64          `json:"swapnonce"`
+	Status        SwapStatus      `json:"status"`
+	StatusMsg     string          `json:"statusmsg"`
+	StatusInfo    *SwapStatusInfo `json:"statusinfo"`
+	InitTime      int64           `json:"inittime"`
+	Timestamp     int64           `json:"timestamp"`
+	Memo          string          `json:"memo"`
+	ReplaceCount  int             `json:"replaceCount"`
+	Confirmations uint64          `json:"confirmations"`
+
+	RefundTx       string `json:"refundtx,omitempty"`
+	MatchedAddress string `json:"matchedAddress,omitempty"` // requested address matched in history query
+	Direction      string `json:"direction,omitempty"`      // 'swapin' or 'swapout', set by GetSwap
+
+	// deposit tx confirmations, only in verbose replies, -1 if rpc is unreachable
+	TxConfirmations       *int64 `json:"txConfirmations,omitempty"`
+	RequiredConfirmations uint64 `json:"requiredConfirmations,omitempty"`
+
+	Proof *respsign.Proof `json:"proof,omitempty"`
+}
+
+// NativePricesInfo native coin prices of source and dest chain in reference currency
+type NativePricesInfo struct {
+	Src  *tokens.NativePrice `json:"src"`
+	Dest *tokens.NativePrice `json:"dest"`
 }
 
 // SwapNonceInfo swap nonce info
@@ -65,3 +108,21 @@ type SwapNonceInfo struct {
 	SwapinNonces  map[string]uint64 `json:"swapinNonces"`
 	SwapoutNonces map[string]uint64 `json:"swapoutNonces"`
 }
+
+// AcceptJobStatus oracle accept job status reported in heartbeat
+type AcceptJobStatus struct {
+	PollInterval  string `json:"pollInterval"` // current effective interval
+	WaitInterval  string `json:"waitInterval"`
+	RetryInterval string `json:"retryInterval"`
+	Failures      int    `json:"failures"` // consecutive errors or empty results
+	Timestamp     int64  `json:"timestamp"`
+
+	// sign infos of the last round
+	Dispatched       int `json:"dispatched"`
+	SkippedCached    int `json:"skippedCached"`    // in memory cache
+	SkippedProcessed int `json:"skippedProcessed"` // persisted processed records
+
+	TotalDispatched       uint64 `json:"totalDispatched"`
+	TotalSkippedCached    uint64 `json:"totalSkippedCached"`
+	TotalSkippedProcessed uint64 `json:"totalSkippedProcessed"`
+}
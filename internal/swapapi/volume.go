@@ -0,0 +1,111 @@
+package swapapi
+
+import (
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// volume history intervals
+const (
+	VolumeIntervalDay  = "day"
+	VolumeIntervalWeek = "week"
+
+	maxVolumeBuckets = 400
+
+	secondsPerDay = int64(24 * 60 * 60)
+	// weeks start on Monday, unix epoch is Thursday
+	weekStartOffset = 4 * secondsPerDay
+)
+
+var (
+	errWrongVolumeInterval = newRPCError(-32061, "wrong volume interval, should be 'day' or 'week'")
+	errVolumeHistoryRange  = newRPCError(-32060, "wrong time range in volume history query")
+
+	// aggregate success swap results by time bucket, replaced in tests
+	aggregateSwapVolumeByTime = mongodb.AggregateSwapVolumeByTime
+)
+
+// VolumeBucket success swaps in time bucket starting at 'start' (unix seconds, UTC),
+// volume and fee are sums in smallest unit of deposit token.
+type VolumeBucket struct {
+	Start  int64  `json:"start"`
+	Count  int64  `json:"count"`
+	Volume string `json:"volume"`
+	Fee    string `json:"fee"`
+}
+
+// SwapVolumeHistory volume of pair over time, every bucket in range is present.
+type SwapVolumeHistory struct {
+	PairID   string          `json:"pairid"`
+	Interval string          `json:"interval"`
+	Swapin   []*VolumeBucket `json:"swapin"`
+	Swapout  []*VolumeBucket `json:"swapout"`
+}
+
+func getVolumeBucketSize(interval string) (size, offset int64, err error) {
+	switch interval {
+	case VolumeIntervalDay:
+		return secondsPerDay, 0, nil
+	case VolumeIntervalWeek:
+		return 7 * secondsPerDay, weekStartOffset, nil
+	default:
+		return 0, 0, errWrongVolumeInterval
+	}
+}
+
+// GetSwapVolumeHistory api, time range is unix seconds of swap registering,
+// 'to' defaults to now and 'from' defaults to 30 buckets before 'to'.
+func GetSwapVolumeHistory(pairID, interval string, from, to int64) (*SwapVolumeHistory, error) {
+	if tokens.GetTokenPairConfig(pairID) == nil {
+		return nil, errTokenPairNotExist
+	}
+	size, offset, err := getVolumeBucketSize(interval)
+	if err != nil {
+		return nil, err
+	}
+	if to == 0 {
+		to = time.Now().Unix()
+	}
+	if from == 0 {
+		from = to - 29*size
+	}
+	if from < 0 || from > to {
+		return nil, errVolumeHistoryRange
+	}
+	firstStart := from - ((from-offset)%size+size)%size
+	if (to-firstStart)/size >= maxVolumeBuckets {
+		return nil, errVolumeHistoryRange
+	}
+	result := &SwapVolumeHistory{
+		PairID:   pairID,
+		Interval: interval,
+	}
+	for _, isSwapin := range []bool{true, false} {
+		stats, err := aggregateSwapVolumeByTime(isSwapin, pairID, size, offset, firstStart, to)
+		if err != nil {
+			return nil, err
+		}
+		statsByStart := make(map[int64]*mongodb.VolumeBucketStat, len(stats))
+		for _, stat := range stats {
+			statsByStart[stat.Start] = stat
+		}
+		var buckets []*VolumeBucket
+		for start := firstStart; start <= to; start += size {
+			bucket := &VolumeBucket{Start: start, Volume: "0", Fee: "0"}
+			if stat, exist := statsByStart[start]; exist {
+				bucket.Count = stat.Count
+				bucket.Volume = stat.Value.String()
+				bucket.Fee = calcStatisticsFee(pairID, stat.Value, stat.SwapValue, isSwapin).String()
+			}
+			buckets = append(buckets, bucket)
+		}
+		if isSwapin {
+			result.Swapin = buckets
+		} else {
+			result.Swapout = buckets
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package swapapi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func TestGetSwapVolumeHistory(t *testing.T) {
+	decimals18 := uint8(18)
+	tokens.SetTokenPairsConfig(map[string]*tokens.TokenPairConfig{
+		"eth": {
+			PairID:    "eth",
+			SrcToken:  &tokens.TokenConfig{Symbol: "ETH", Decimals: &decimals18},
+			DestToken: &tokens.TokenConfig{Symbol: "anyETH", Decimals: &decimals18},
+		},
+	}, false)
+	defer tokens.SetTokenPairsConfig(map[string]*tokens.TokenPairConfig{}, false)
+
+	// 2021-01-04 is Monday, 2021-01-11 is the next Monday
+	monday, nextMonday := int64(1609718400), int64(1610323200)
+	// exceeds float64 precision
+	value, _ := new(big.Int).SetString("123456789012345678901", 10)
+	swapValue, _ := new(big.Int).SetString("123456789012345678000", 10)
+
+	oldAggregate := aggregateSwapVolumeByTime
+	defer func() { aggregateSwapVolumeByTime = oldAggregate }()
+	aggregateSwapVolumeByTime = func(isSwapin bool, pairID string, bucketSize, offset, fromTime, toTime int64) ([]*mongodb.VolumeBucketStat, error) {
+		if fromTime != monday || toTime != nextMonday+secondsPerDay || bucketSize != 7*secondsPerDay {
+			t.Errorf("wrong aggregate range [%v, %v] with bucket size %v", fromTime, toTime, bucketSize)
+		}
+		if !isSwapin {
+			return nil, nil
+		}
+		return []*mongodb.VolumeBucketStat{
+			{Start: nextMonday, Count: 2, Value: value, SwapValue: swapValue},
+		}, nil
+	}
+
+	// from Wednesday to next Tuesday
+	history, err := GetSwapVolumeHistory("eth", VolumeIntervalWeek, monday+2*secondsPerDay, nextMonday+secondsPerDay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Swapin) != 2 || len(history.Swapout) != 2 {
+		t.Fatalf("want 2 buckets of each direction, have %v/%v", len(history.Swapin), len(history.Swapout))
+	}
+	empty := VolumeBucket{Start: monday, Volume: "0", Fee: "0"}
+	if *history.Swapin[0] != empty {
+		t.Errorf("want empty bucket %+v, have %+v", empty, *history.Swapin[0])
+	}
+	want := VolumeBucket{Start: nextMonday, Count: 2, Volume: "123456789012345678901", Fee: "901"}
+	if *history.Swapin[1] != want {
+		t.Errorf("want bucket %+v, have %+v", want, *history.Swapin[1])
+	}
+
+	if _, err = GetSwapVolumeHistory("eth", "month", 0, 0); err != errWrongVolumeInterval {
+		t.Errorf("want wrong interval error, have %v", err)
+	}
+	if _, err = GetSwapVolumeHistory("eth", VolumeIntervalDay, 1, 1+maxVolumeBuckets*secondsPerDay); err != errVolumeHistoryRange {
+		t.Errorf("want too many buckets error, have %v", err)
+	}
+	if _, err = GetSwapVolumeHistory("notexist", VolumeIntervalDay, 0, 0); err != errTokenPairNotExist {
+		t.Errorf("want pair not exist error, have %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package mongodb
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AddAcceptProcessed add processed accept sign info keyID
+func AddAcceptProcessed(keyID string) error {
+	item := &MgoAcceptProcessed{
+		Key:       keyID,
+		Timestamp: time.Now().Unix(),
+	}
+	opts := options.Replace().SetUpsert(true)
+	_, err := collAcceptProcessed.ReplaceOne(clientCtx, bson.M{"_id": keyID}, item, opts)
+	return mgoError(err)
+}
+
+// IsAcceptProcessed is accept sign info keyID processed
+func IsAcceptProcessed(keyID string) (bool, error) {
+	count, err := collAcceptProcessed.CountDocuments(clientCtx, bson.M{"_id": keyID})
+	if err != nil {
+		return false, mgoError(err)
+	}
+	return count > 0, nil
+}
+
+// PruneAcceptProcessed remove processed accept sign infos before timestamp
+func PruneAcceptProcessed(before int64) (int64, error) {
+	res, err := collAcceptProcessed.DeleteMany(clientCtx, bson.M{"timestamp": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, mgoError(err)
+	}
+	return res.DeletedCount, nil
+}
@@ -229,11 +229,11 @@ func ManualManageSwap(txid, pairID, bind, memo string, isSwapin, isPass bool) er
 
 func getSwapResultsTxStatus(bridge tokens.CrossChainBridge, res *MgoSwapResult) (status *tokens.TxStatus, txHash string) {
 	var err error
-	if status, err = bridge.GetTransactionStatus(res.SwapTx); err == nil {
+	if status, err = bridge.GetTransactionStatus(res.SwapTx); err == nil && status.BlockHeight > 0 {
 		return status, res.SwapTx
 	}
 	for _, tx := range res.OldSwapTxs {
-		if status, err = bridge.GetTransactionStatus(tx); err == nil {
+		if status, err = bridge.GetTransactionStatus(tx); err == nil && status.BlockHeight > 0 {
 			return status, tx
 		}
 	}
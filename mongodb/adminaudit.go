@@ -0,0 +1,161 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// admin operations on stuck swap
+const (
+	AdminPassSwapOp = "passswap"
+	AdminFailSwapOp = "failswap"
+)
+
+// getAdminPassStatus swap verified at stable depth and not swapped is passed to TxNotSwapped,
+// otherwise it is passed to TxNotStable to reverify as swap is never signed without stable verification.
+func getAdminPassStatus(swap *MgoSwap, res *MgoSwapResult) (SwapStatus, error) {
+	if res != nil && res.Status == MatchTxStable {
+		return 0, fmt.Errorf("swap result status is %v, can not operate", res.Status.String())
+	}
+	if !swap.Status.CanReverify() && swap.Status != ManualMakeFail && swap.Status != RiskRejected {
+		return 0, fmt.Errorf("swap status is %v, can not pass", swap.Status.String())
+	}
+	if swap.StableVerified && res != nil && res.SwapTx == "" && len(res.OldSwapTxs) == 0 {
+		return TxNotSwapped, nil
+	}
+	return TxNotStable, nil
+}
+
+func checkAdminFailSwap(swap *MgoSwap, res *MgoSwapResult) error {
+	if res != nil && res.Status == MatchTxStable {
+		return fmt.Errorf("swap result status is %v, can not operate", res.Status.String())
+	}
+	if !swap.Status.CanManualMakeFail() || swap.Status == ManualMakeFail {
+		return fmt.Errorf("swap status is %v, can not fail", swap.Status.String())
+	}
+	return nil
+}
+
+func findSwapAndResult(isSwapin bool, txid, pairID, bind string) (swap *MgoSwap, res *MgoSwapResult, err error) {
+	swap, err = FindSwap(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err = FindSwapResult(isSwapin, txid, pairID, bind)
+	if errors.Is(err, ErrItemNotFound) {
+		return swap, nil, nil // not verified yet
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return swap, res, nil
+}
+
+// AdminPassSwap pass stuck swap, returns the new status
+func AdminPassSwap(operator, txid, pairID, bind, reason string, isSwapin bool) (SwapStatus, error) {
+	if strings.TrimSpace(reason) == "" {
+		return 0, ErrEmptyAdminReason
+	}
+	swap, res, err := findSwapAndResult(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return 0, err
+	}
+	status, err := getAdminPassStatus(swap, res)
+	if err != nil {
+		return 0, err
+	}
+	err = AddSwapAudit(swap, isSwapin, operator, AdminPassSwapOp, status, reason)
+	if err != nil {
+		return 0, err
+	}
+	memo := getAdminMemo(operator, AdminPassSwapOp, reason)
+	now := time.Now().Unix()
+	if swap.Status == RiskRejected {
+		err = setSwapRiskOverridden(isSwapin, txid, pairID, bind)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if status == TxNotSwapped {
+		err = UpdateSwapResultStatus(isSwapin, txid, pairID, bind, MatchTxEmpty, now, memo)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return status, UpdateSwapStatus(isSwapin, txid, pairID, bind, status, now, memo)
+}
+
+// AdminFailSwap make stuck swap fail with terminal status ManualMakeFail
+func AdminFailSwap(operator, txid, pairID, bind, reason string, isSwapin bool) (SwapStatus, error) {
+	if strings.TrimSpace(reason) == "" {
+		return 0, ErrEmptyAdminReason
+	}
+	swap, res, err := findSwapAndResult(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return 0, err
+	}
+	if err = checkAdminFailSwap(swap, res); err != nil {
+		return 0, err
+	}
+	err = AddSwapAudit(swap, isSwapin, operator, AdminFailSwapOp, ManualMakeFail, reason)
+	if err != nil {
+		return 0, err
+	}
+	memo := getAdminMemo(operator, AdminFailSwapOp, reason)
+	now := time.Now().Unix()
+	if res != nil {
+		err = UpdateSwapResultStatus(isSwapin, txid, pairID, bind, ManualMakeFail, now, memo)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return ManualMakeFail, UpdateSwapStatus(isSwapin, txid, pairID, bind, ManualMakeFail, now, memo)
+}
+
+func getAdminMemo(operator, operation, reason string) string {
+	return fmt.Sprintf("%v by %v: %v", operation, operator, reason)
+}
+
+// AddSwapAudit add audit of operation changing swap status
+func AddSwapAudit(swap *MgoSwap, isSwapin bool, operator, operation string, newStatus SwapStatus, reason string) error {
+	item := &MgoAdminAudit{
+		Key:       newObjectID(),
+		SwapKey:   GetSwapKey(swap.TxID, swap.PairID, swap.Bind),
+		IsSwapin:  isSwapin,
+		TxID:      swap.TxID,
+		PairID:    swap.PairID,
+		Bind:      swap.Bind,
+		Operator:  operator,
+		Operation: operation,
+		OldStatus: swap.Status,
+		NewStatus: newStatus,
+		Reason:    reason,
+		Timestamp: time.Now().Unix(),
+	}
+	_, err := collAdminAudit.InsertOne(clientCtx, item)
+	if err == nil {
+		log.Info("mongodb add admin audit success", "operation", operation, "operator", operator, "txid", swap.TxID, "pairID", swap.PairID, "bind", swap.Bind, "isSwapin", isSwapin, "oldStatus", swap.Status, "newStatus", newStatus)
+	} else {
+		log.Error("mongodb add admin audit failed", "operation", operation, "operator", operator, "txid", swap.TxID, "pairID", swap.PairID, "bind", swap.Bind, "isSwapin", isSwapin, "err", err)
+	}
+	return mgoError(err)
+}
+
+// GetAdminAudits get admin audits of swap in adding order
+func GetAdminAudits(isSwapin bool, txid, pairID, bind string) ([]*MgoAdminAudit, error) {
+	swapKey := GetSwapKey(txid, pairID, bind)
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := collAdminAudit.Find(clientCtx, bson.M{"swapkey": swapKey, "isswapin": isSwapin}, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	var result []*MgoAdminAudit
+	err = cur.All(clientCtx, &result)
+	return result, mgoError(err)
+}
@@ -0,0 +1,48 @@
+package mongodb
+
+import "testing"
+
+func TestGetAdminPassStatus(t *testing.T) {
+	cases := []struct {
+		swap    *MgoSwap
+		res     *MgoSwapResult
+		want    SwapStatus
+		wantErr bool
+	}{
+		{&MgoSwap{Status: TxVerifyFailed}, nil, TxNotStable, false},
+		{&MgoSwap{Status: TxVerifyFailed, StableVerified: true}, nil, TxNotStable, false},
+		{&MgoSwap{Status: TxWithBigValue, StableVerified: true}, &MgoSwapResult{Status: TxWithBigValue}, TxNotSwapped, false},
+		{&MgoSwap{Status: ManualMakeFail, StableVerified: true}, &MgoSwapResult{Status: ManualMakeFail, SwapTx: "0x1"}, TxNotStable, false},
+		{&MgoSwap{Status: ManualMakeFail, StableVerified: true}, &MgoSwapResult{Status: MatchTxStable}, 0, true},
+		{&MgoSwap{Status: RiskRejected, StableVerified: true}, &MgoSwapResult{Status: MatchTxEmpty}, TxNotSwapped, false},
+		{&MgoSwap{Status: TxNotSwapped}, &MgoSwapResult{Status: MatchTxEmpty}, 0, true},
+		{&MgoSwap{Status: TxProcessed}, &MgoSwapResult{Status: MatchTxNotStable}, 0, true},
+	}
+	for i, c := range cases {
+		have, err := getAdminPassStatus(c.swap, c.res)
+		if (err != nil) != c.wantErr {
+			t.Errorf("case %v: want error %v, have %v", i, c.wantErr, err)
+		} else if have != c.want {
+			t.Errorf("case %v: want status %v, have %v", i, c.want, have)
+		}
+	}
+}
+
+func TestCheckAdminFailSwap(t *testing.T) {
+	cases := []struct {
+		swap    *MgoSwap
+		res     *MgoSwapResult
+		wantErr bool
+	}{
+		{&MgoSwap{Status: TxVerifyFailed}, nil, false},
+		{&MgoSwap{Status: TxNotSwapped}, &MgoSwapResult{Status: MatchTxNotStable}, false},
+		{&MgoSwap{Status: TxProcessed}, &MgoSwapResult{Status: MatchTxStable}, true},
+		{&MgoSwap{Status: ManualMakeFail}, nil, true},
+		{&MgoSwap{Status: Refunded}, nil, true},
+	}
+	for i, c := range cases {
+		if err := checkAdminFailSwap(c.swap, c.res); (err != nil) != c.wantErr {
+			t.Errorf("case %v: want error %v, have %v", i, c.wantErr, err)
+		}
+	}
+}
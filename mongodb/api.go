@@ -48,6 +48,37 @@ func UpdateSwapResultStatus(isSwapin bool, txid, pairID, bind string, status Swa
 	return updateSwapResultStatus(collSwapoutResult, txid, pairID, bind, status, timestamp, memo)
 }
 
+// UpdateSwapResultVerifiedInfo update verified tx info of swap result which is not swapped
+func UpdateSwapResultVerifiedInfo(isSwapin bool, result *MgoSwapResult) error {
+	collection := getSwapOrResultCollection(isSwapin, true)
+	pairID := strings.ToLower(result.PairID)
+	txid, bind := result.TxID, result.Bind
+	updates := bson.M{
+		"txto":        result.TxTo,
+		"txheight":    result.TxHeight,
+		"txtime":      result.TxTime,
+		"from":        result.From,
+		"to":          result.To,
+		"value":       result.Value,
+		"txblockhash": result.TxBlockHash,
+		"status":      result.Status,
+		"timestamp":   result.Timestamp,
+		"memo":        sanitizeMemo(result.Memo),
+	}
+	filter := bson.M{"_id": GetSwapKey(txid, pairID, bind), "swaptx": ""}
+	err := withRetry("updateSwapResultVerifiedInfo", func() error {
+		_, err := collection.UpdateOne(clientCtx, filter, bson.M{"$set": updates})
+		return err
+	})
+	notifySwapStatusWritten(err, isSwapin, txid, pairID, bind, result.Status, "", updates["memo"].(string))
+	if err == nil {
+		log.Info("mongodb update swap result verified info", "txid", txid, "pairID", pairID, "bind", bind, "status", result.Status, "txheight", result.TxHeight, "isSwapin", isSwapin)
+	} else {
+		log.Error("mongodb update swap result verified info", "txid", txid, "pairID", pairID, "bind", bind, "status", result.Status, "isSwapin", isSwapin, "err", err)
+	}
+	return mgoError(err)
+}
+
 // FindSwapResult find swap result
 func FindSwapResult(isSwapin bool, txid, pairID, bind string) (*MgoSwapResult, error) {
 	if isSwapin {
@@ -64,6 +95,14 @@ func FindSwap(isSwapin bool, txid, pairID, bind string) (*MgoSwap, error) {
 	return findSwap(collSwapout, txid, pairID, bind)
 }
 
+// FindSwapResults find swap history results of any of the addresses
+func FindSwapResults(isSwapin bool, addresses []string, pairID string, offset, limit int, status string, fromTime, toTime int64) ([]*MgoSwapResult, error) {
+	if isSwapin {
+		return findSwapResults(collSwapinResult, addresses, pairID, offset, limit, status, fromTime, toTime)
+	}
+	return findSwapResults(collSwapoutResult, addresses, pairID, offset, limit, status, fromTime, toTime)
+}
+
 // --------------- swapin --------------------------------
 
 // AddSwapin add swapin
@@ -128,7 +167,9 @@ func addSwap(collection *mongo.Collection, ms *MgoSwap) error {
 	ms.PairID = strings.ToLower(ms.PairID)
 	ms.Key = GetSwapKey(ms.TxID, ms.PairID, ms.Bind)
 	ms.InitTime = common.NowMilli()
+	ms.Memo = sanitizeMemo(ms.Memo)
 	_, err := collection.InsertOne(clientCtx, ms)
+	notifySwapStatusWritten(err, isSwapin(collection), ms.TxID, ms.PairID, ms.Bind, ms.Status, "", ms.Memo)
 	if err == nil {
 		log.Info("mongodb add swap success", "txid", ms.TxID, "pairID", ms.PairID, "bind", ms.Bind, "isSwapin", isSwapin(collection))
 	} else if !mongo.IsDuplicateKeyError(err) {
@@ -150,10 +191,13 @@ func updateSwapStatus(collection *mongo.Collection, txid, pairID, bind string, s
 	pairID = strings.ToLower(pairID)
 	updates := bson.M{"status": status, "timestamp": timestamp}
 	if memo != "" {
-		updates["memo"] = memo
+		updates["memo"] = sanitizeMemo(memo)
 	} else if status == TxNotSwapped || status == TxNotStable {
 		updates["memo"] = ""
 	}
+	if status == TxNotStable || status == RegisteredUnstable {
+		updates["stableverified"] = false
+	}
 	if status == TxNotStable {
 		retryLock.Lock()
 		defer retryLock.Unlock()
@@ -162,7 +206,12 @@ func updateSwapStatus(collection *mongo.Collection, txid, pairID, bind string, s
 			return nil
 		}
 	}
-	_, err := collection.UpdateByID(clientCtx, GetSwapKey(txid, pairID, bind), bson.M{"$set": updates})
+	err := withRetry("updateSwapStatus", func() error {
+		_, err := collection.UpdateByID(clientCtx, GetSwapKey(txid, pairID, bind), bson.M{"$set": updates})
+		return err
+	})
+	writtenMemo, _ := updates["memo"].(string)
+	notifySwapStatusWritten(err, isSwapin(collection), txid, pairID, bind, status, "", writtenMemo)
 	if err == nil {
 		printLog := log.Info
 		switch status {
@@ -177,6 +226,27 @@ func updateSwapStatus(collection *mongo.Collection, txid, pairID, bind string, s
 	return mgoError(err)
 }
 
+// UpdateSwapStatusStableVerified update swap status after verifying at stable depth
+func UpdateSwapStatusStableVerified(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
+	collection := collSwapout
+	if isSwapin {
+		collection = collSwapin
+	}
+	memo = sanitizeMemo(memo)
+	updates := bson.M{"status": status, "timestamp": timestamp, "memo": memo, "stableverified": true}
+	err := withRetry("UpdateSwapStatusStableVerified", func() error {
+		_, err := collection.UpdateByID(clientCtx, GetSwapKey(txid, pairID, bind), bson.M{"$set": updates})
+		return err
+	})
+	notifySwapStatusWritten(err, isSwapin, txid, pairID, bind, status, "", memo)
+	if err == nil {
+		log.Info("mongodb update swap status stable verified", "txid", txid, "pairID", pairID, "bind", bind, "status", status, "isSwapin", isSwapin)
+	} else {
+		log.Error("mongodb update swap status stable verified", "txid", txid, "pairID", pairID, "bind", bind, "status", status, "isSwapin", isSwapin, "err", err)
+	}
+	return mgoError(err)
+}
+
 // GetSwapKey txid + pairID + bind
 func GetSwapKey(txid, pairID, bind string) string {
 	return strings.ToLower(txid + ":" + pairID + ":" + bind)
@@ -191,16 +261,33 @@ func findSwap(collection *mongo.Collection, txid, pairID, bind string) (*MgoSwap
 	return result, nil
 }
 
+// findSwapOrSwapResult empty bind matches any bind, returns ErrSwapBindAmbiguous if not unique
 func findSwapOrSwapResult(result interface{}, collection *mongo.Collection, txid, pairID, bind string) (err error) {
 	if bind != "" {
 		err = collection.FindOne(clientCtx, bson.M{"_id": GetSwapKey(txid, pairID, bind)}).Decode(result)
-	} else {
-		qtxid := bson.M{"txid": strings.ToLower(txid)}
-		qpair := bson.M{"pairid": strings.ToLower(pairID)}
-		queries := []bson.M{qtxid, qpair}
-		err = collection.FindOne(clientCtx, bson.M{"$and": queries}).Decode(result)
+		return mgoError(err)
 	}
-	return mgoError(err)
+	qtxid := bson.M{"txid": strings.ToLower(txid)}
+	qpair := bson.M{"pairid": strings.ToLower(pairID)}
+	queries := []bson.M{qtxid, qpair}
+	cur, err := collection.Find(clientCtx, bson.M{"$and": queries}, options.Find().SetLimit(2))
+	if err != nil {
+		return mgoError(err)
+	}
+	defer cur.Close(clientCtx)
+	if !cur.Next(clientCtx) {
+		if err = cur.Err(); err != nil {
+			return mgoError(err)
+		}
+		return ErrItemNotFound
+	}
+	if err = cur.Decode(result); err != nil {
+		return mgoError(err)
+	}
+	if cur.Next(clientCtx) {
+		return ErrSwapBindAmbiguous
+	}
+	return mgoError(cur.Err())
 }
 
 func findSwapsWithStatus(collection *mongo.Collection, status SwapStatus, septime int64) (result []*MgoSwap, err error) {
@@ -272,9 +359,9 @@ func FindSwapinResultsWithStatus(status SwapStatus, septime int64) ([]*MgoSwapRe
 	return findSwapResultsWithStatus(collSwapinResult, status, septime)
 }
 
-// FindSwapinResults find swapin history results
-func FindSwapinResults(address, pairID string, offset, limit int, status string) ([]*MgoSwapResult, error) {
-	return findSwapResults(collSwapinResult, address, pairID, offset, limit, status)
+// FindSwapinResults find swapin history results of any of the addresses
+func FindSwapinResults(addresses []string, pairID string, offset, limit int, status string, fromTime, toTime int64) ([]*MgoSwapResult, error) {
+	return findSwapResults(collSwapinResult, addresses, pairID, offset, limit, status, fromTime, toTime)
 }
 
 // FindSwapResultsToReplace find swap results to replace
@@ -330,9 +417,9 @@ func FindSwapoutResultsWithStatus(status SwapStatus, septime int64) ([]*MgoSwapR
 	return findSwapResultsWithStatus(collSwapoutResult, status, septime)
 }
 
-// FindSwapoutResults find swapout history results
-func FindSwapoutResults(address, pairID string, offset, limit int, status string) ([]*MgoSwapResult, error) {
-	return findSwapResults(collSwapoutResult, address, pairID, offset, limit, status)
+// FindSwapoutResults find swapout history results of any of the addresses
+func FindSwapoutResults(addresses []string, pairID string, offset, limit int, status string, fromTime, toTime int64) ([]*MgoSwapResult, error) {
+	return findSwapResults(collSwapoutResult, addresses, pairID, offset, limit, status, fromTime, toTime)
 }
 
 // ------------------ swapin / swapout result common ------------------------
@@ -345,7 +432,9 @@ func addSwapResult(collection *mongo.Collection, ms *MgoSwapResult) error {
 	ms.PairID = strings.ToLower(ms.PairID)
 	ms.Key = GetSwapKey(ms.TxID, ms.PairID, ms.Bind)
 	ms.InitTime = common.NowMilli()
+	ms.Memo = sanitizeMemo(ms.Memo)
 	_, err := collection.InsertOne(clientCtx, ms)
+	notifySwapStatusWritten(err, isSwapin(collection), ms.TxID, ms.PairID, ms.Bind, ms.Status, ms.SwapTx, ms.Memo)
 	if err == nil {
 		log.Info("mongodb add swap result success", "txid", ms.TxID, "pairID", ms.PairID, "bind", ms.Bind, "swaptype", ms.SwapType, "value", ms.Value, "isSwapin", isSwapin(collection))
 	} else if !mongo.IsDuplicateKeyError(err) {
@@ -377,8 +466,11 @@ func updateSwapResult(collection *mongo.Collection, txid, pairID, bind string, i
 	if items.SwapType != 0 {
 		updates["swaptype"] = items.SwapType
 	}
+	if items.PayoutLegCount != 0 {
+		updates["payoutlegcount"] = items.PayoutLegCount
+	}
 	if items.Memo != "" {
-		updates["memo"] = items.Memo
+		updates["memo"] = sanitizeMemo(items.Memo)
 	} else if items.Status == MatchTxNotStable {
 		updates["memo"] = ""
 	}
@@ -401,7 +493,16 @@ func updateSwapResult(collection *mongo.Collection, txid, pairID, bind string, i
 			updates["swapnonce"] = items.SwapNonce
 		}
 	}
-	_, err := collection.UpdateByID(clientCtx, GetSwapKey(txid, pairID, bind), bson.M{"$set": updates})
+	err := withRetry("updateSwapResult", func() error {
+		_, err := collection.UpdateByID(clientCtx, GetSwapKey(txid, pairID, bind), bson.M{"$set": updates})
+		return err
+	})
+	if items.Status != KeepStatus {
+		writtenMemo, _ := updates["memo"].(string)
+		notifySwapStatusWritten(err, isSwapin(collection), txid, pairID, bind, items.Status, items.SwapTx, writtenMemo)
+	} else {
+		notifySwapChanged(isSwapin(collection), txid, pairID, bind)
+	}
 	if err == nil {
 		log.Info("mongodb update swap result", "txid", txid, "pairID", pairID, "bind", bind, "updates", updates, "isSwapin", isSwapin(collection))
 	} else {
@@ -414,7 +515,7 @@ func updateSwapResultStatus(collection *mongo.Collection, txid, pairID, bind str
 	pairID = strings.ToLower(pairID)
 	updates := bson.M{"status": status, "timestamp": timestamp}
 	if memo != "" {
-		updates["memo"] = memo
+		updates["memo"] = sanitizeMemo(memo)
 	}
 	if status == Reswapping {
 		updates["memo"] = ""
@@ -424,9 +525,16 @@ func updateSwapResultStatus(collection *mongo.Collection, txid, pairID, bind str
 		updates["swapheight"] = 0
 		updates["swaptime"] = 0
 		updates["swapnonce"] = 0
+		updates["payoutlegcount"] = 0
+		updates["payoutlegs"] = nil
 	}
-	_, err := collection.UpdateByID(clientCtx, GetSwapKey(txid, pairID, bind), bson.M{"$set": updates})
+	err := withRetry("updateSwapResultStatus", func() error {
+		_, err := collection.UpdateByID(clientCtx, GetSwapKey(txid, pairID, bind), bson.M{"$set": updates})
+		return err
+	})
 	isSwapin := isSwapin(collection)
+	writtenMemo, _ := updates["memo"].(string)
+	notifySwapStatusWritten(err, isSwapin, txid, pairID, bind, status, "", writtenMemo)
 	if err == nil {
 		log.Info("mongodb update swap result status", "txid", txid, "pairID", pairID, "bind", bind, "status", status, "isSwapin", isSwapin)
 	} else {
@@ -498,6 +606,7 @@ func updateSwapResultOldTxs(collection *mongo.Collection, txid, pairID, bind, sw
 	}
 
 	_, err = collection.UpdateByID(clientCtx, GetSwapKey(txid, pairID, bind), updates)
+	notifySwapChanged(isSwapin(collection), txid, pairID, bind)
 	if err == nil {
 		log.Info("UpdateRouterOldSwapTxs success", "txid", txid, "pairID", pairID, "bind", bind, "swaptx", swapTx, "nonce", swapRes.SwapNonce, "swapValue", swapValue)
 	} else {
@@ -527,15 +636,17 @@ func getStatusesFromStr(status string) []SwapStatus {
 		if part == "" {
 			continue
 		}
-		num, err := common.GetUint64FromStr(part)
+		status, err := ParseSwapStatus(strings.TrimSpace(part))
 		if err == nil {
-			result = append(result, SwapStatus(num))
+			result = append(result, status)
 		}
 	}
 	return result
 }
 
-func findSwapResults(collection *mongo.Collection, address, pairID string, offset, limit int, status string) ([]*MgoSwapResult, error) {
+// getSwapResultsFilter filter of swap history results, multiple addresses are queried with '$in',
+// fromTime and toTime (unix seconds, inclusive) bound timestamp, zero means unbounded.
+func getSwapResultsFilter(addresses []string, pairID, status string, fromTime, toTime int64) bson.M {
 	pairID = strings.ToLower(pairID)
 
 	var queries []bson.M
@@ -544,11 +655,22 @@ func findSwapResults(collection *mongo.Collection, address, pairID string, offse
 		queries = append(queries, bson.M{"pairid": pairID})
 	}
 
-	if address != "" && address != allAddresses {
+	if len(addresses) == 1 && addresses[0] == allAddresses {
+		addresses = nil
+	}
+	froms := make([]string, len(addresses))
+	for i, address := range addresses {
 		if common.IsHexAddress(address) {
 			address = strings.ToLower(address)
 		}
-		queries = append(queries, bson.M{"from": address})
+		froms[i] = address
+	}
+	switch len(froms) {
+	case 0:
+	case 1:
+		queries = append(queries, bson.M{"from": froms[0]})
+	default:
+		queries = append(queries, bson.M{"from": bson.M{"$in": froms}})
 	}
 
 	filterStatuses := getStatusesFromStr(status)
@@ -561,6 +683,30 @@ func findSwapResults(collection *mongo.Collection, address, pairID string, offse
 		}
 	}
 
+	if fromTime > 0 || toTime > 0 {
+		qtime := bson.M{}
+		if fromTime > 0 {
+			qtime["$gte"] = fromTime
+		}
+		if toTime > 0 {
+			qtime["$lte"] = toTime
+		}
+		queries = append(queries, bson.M{"timestamp": qtime})
+	}
+
+	switch len(queries) {
+	case 0:
+		return bson.M{}
+	case 1:
+		return queries[0]
+	default:
+		return bson.M{"$and": queries}
+	}
+}
+
+func findSwapResults(collection *mongo.Collection, addresses []string, pairID string, offset, limit int, status string, fromTime, toTime int64) ([]*MgoSwapResult, error) {
+	filter := getSwapResultsFilter(addresses, pairID, status, fromTime, toTime)
+
 	opts := &options.FindOptions{}
 	if limit >= 0 {
 		opts = opts.SetSort(bson.D{{Key: "inittime", Value: 1}}).
@@ -570,16 +716,7 @@ func findSwapResults(collection *mongo.Collection, address, pairID string, offse
 			SetSkip(int64(offset)).SetLimit(int64(-limit))
 	}
 
-	var cur *mongo.Cursor
-	var err error
-	switch len(queries) {
-	case 0:
-		cur, err = collection.Find(clientCtx, bson.M{}, opts)
-	case 1:
-		cur, err = collection.Find(clientCtx, queries[0], opts)
-	default:
-		cur, err = collection.Find(clientCtx, bson.M{"$and": queries}, opts)
-	}
+	cur, err := collection.Find(clientCtx, filter, opts)
 	if err != nil {
 		return nil, mgoError(err)
 	}
@@ -624,11 +761,25 @@ func FindP2shBindAddress(p2shAddress string) (string, error) {
 
 // FindP2shAddresses find p2sh address
 func FindP2shAddresses(offset, limit int) ([]*MgoP2shAddress, error) {
+	return findP2shAddresses(bson.M{}, offset, limit)
+}
+
+// FindActiveP2shAddresses find p2sh addresses which are not inactive
+func FindActiveP2shAddresses(offset, limit int) ([]*MgoP2shAddress, error) {
+	return findP2shAddresses(bson.M{"inactive": bson.M{"$ne": true}}, offset, limit)
+}
+
+// FindInactiveP2shAddresses find inactive p2sh addresses
+func FindInactiveP2shAddresses(offset, limit int) ([]*MgoP2shAddress, error) {
+	return findP2shAddresses(bson.M{"inactive": true}, offset, limit)
+}
+
+func findP2shAddresses(filter bson.M, offset, limit int) ([]*MgoP2shAddress, error) {
 	opts := options.Find().
 		SetSort(bson.D{{Key: "timestamp", Value: 1}}).
 		SetSkip(int64(offset)).
 		SetLimit(int64(limit))
-	cur, err := collP2shAddress.Find(clientCtx, bson.M{}, opts)
+	cur, err := collP2shAddress.Find(clientCtx, filter, opts)
 	if err != nil {
 		return nil, mgoError(err)
 	}
@@ -637,6 +788,82 @@ func FindP2shAddresses(offset, limit int) ([]*MgoP2shAddress, error) {
 	return result, mgoError(err)
 }
 
+// FindP2shAddressesByKeys find p2sh addresses of bind addresses
+func FindP2shAddressesByKeys(keys []string) ([]*MgoP2shAddress, error) {
+	cur, err := collP2shAddress.Find(clientCtx, bson.M{"_id": bson.M{"$in": keys}})
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoP2shAddress, 0, len(keys))
+	err = cur.All(clientCtx, &result)
+	return result, mgoError(err)
+}
+
+// CountP2shAddresses count active and inactive p2sh addresses
+func CountP2shAddresses() (active, inactive int64, err error) {
+	inactive, err = collP2shAddress.CountDocuments(clientCtx, bson.M{"inactive": true})
+	if err != nil {
+		return 0, 0, mgoError(err)
+	}
+	total, err := collP2shAddress.EstimatedDocumentCount(clientCtx)
+	if err != nil {
+		return 0, 0, mgoError(err)
+	}
+	return total - inactive, inactive, nil
+}
+
+// UpdateP2shDeposit record deposit to p2sh address and activate it
+func UpdateP2shDeposit(bindAddress string) error {
+	updates := bson.M{
+		"lastdeposit": time.Now().Unix(),
+		"inactive":    false,
+	}
+	_, err := collP2shAddress.UpdateOne(clientCtx, bson.M{"_id": bindAddress}, bson.M{"$set": updates})
+	return mgoError(err)
+}
+
+// ReactivateP2shAddress reactivate p2sh address and restart its aging
+func ReactivateP2shAddress(bindAddress string) error {
+	updates := bson.M{
+		"activetime": time.Now().Unix(),
+		"inactive":   false,
+	}
+	res, err := collP2shAddress.UpdateOne(clientCtx, bson.M{"_id": bindAddress}, bson.M{"$set": updates})
+	if err != nil {
+		return mgoError(err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrItemNotFound
+	}
+	log.Info("mongodb reactivate p2sh address", "bind", bindAddress)
+	return nil
+}
+
+// RecordP2shFailedDeposits record utxos of p2sh address which registered no swapin when reactivated,
+// the address is not reactivated by these utxos again.
+func RecordP2shFailedDeposits(bindAddress string, utxos []string) error {
+	_, err := collP2shAddress.UpdateOne(clientCtx, bson.M{"_id": bindAddress}, bson.M{"$set": bson.M{"faileddeposits": utxos}})
+	return mgoError(err)
+}
+
+// DeactivateStaleP2shAddresses mark p2sh addresses without any deposit
+// and registered (or reactivated) before the timestamp as inactive
+func DeactivateStaleP2shAddresses(before int64) (int64, error) {
+	filter := bson.M{
+		"inactive":    bson.M{"$ne": true},
+		"lastdeposit": nil,
+		"$or": bson.A{
+			bson.M{"activetime": bson.M{"$lt": before}},
+			bson.M{"activetime": nil, "timestamp": bson.M{"$lt": before}},
+		},
+	}
+	res, err := collP2shAddress.UpdateMany(clientCtx, filter, bson.M{"$set": bson.M{"inactive": true}})
+	if err != nil {
+		return 0, mgoError(err)
+	}
+	return res.ModifiedCount, nil
+}
+
 // ------------------ latest scan info ------------------------
 
 // UpdateLatestScanInfo update latest scan info
@@ -685,21 +912,6 @@ func FindLatestScanInfo(isSrc bool) (*MgoLatestScanInfo, error) {
 
 // ------------------------ register address ------------------------------
 
-// AddRegisteredAddress add register address
-func AddRegisteredAddress(address string) error {
-	ma := &MgoRegisteredAddress{
-		Key:       address,
-		Timestamp: time.Now().Unix(),
-	}
-	_, err := collRegisteredAddress.InsertOne(clientCtx, ma)
-	if err == nil {
-		log.Info("mongodb add register address", "key", ma.Key)
-	} else if !mongo.IsDuplicateKeyError(err) {
-		log.Error("mongodb add register address", "key", ma.Key, "err", err)
-	}
-	return mgoError(err)
-}
-
 // FindRegisteredAddress find register address
 func FindRegisteredAddress(key string) (*MgoRegisteredAddress, error) {
 	var result MgoRegisteredAddress
@@ -0,0 +1,71 @@
+package mongodb
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindSwapResultsUpdatedBetween find swap results with status in statuses
+// and last updated in time range [start, end)
+func FindSwapResultsUpdatedBetween(isSwapin bool, statuses []SwapStatus, start, end int64) ([]*MgoSwapResult, error) {
+	collection := getSwapOrResultCollection(isSwapin, true)
+	filter := bson.M{
+		"status":    bson.M{"$in": statuses},
+		"timestamp": bson.M{"$gte": start, "$lt": end},
+	}
+	cur, err := collection.Find(clientCtx, filter)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	var result []*MgoSwapResult
+	err = cur.All(clientCtx, &result)
+	return result, mgoError(err)
+}
+
+// CountSwapResultsByStatus count swap results of each status in statuses
+func CountSwapResultsByStatus(isSwapin bool, statuses []SwapStatus) (map[SwapStatus]int64, error) {
+	collection := getSwapOrResultCollection(isSwapin, true)
+	pipeline := []bson.M{
+		{"$match": bson.M{"status": bson.M{"$in": statuses}}},
+		{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}},
+	}
+	cur, err := collection.Aggregate(clientCtx, pipeline)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	var groups []struct {
+		Status SwapStatus `bson:"_id"`
+		Count  int64      `bson:"count"`
+	}
+	if err = cur.All(clientCtx, &groups); err != nil {
+		return nil, mgoError(err)
+	}
+	result := make(map[SwapStatus]int64, len(groups))
+	for _, group := range groups {
+		result[group.Status] = group.Count
+	}
+	return result, nil
+}
+
+// UpsertDailyReport add or replace daily report
+func UpsertDailyReport(report *MgoDailyReport) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := collDailyReport.ReplaceOne(clientCtx, bson.M{"_id": report.Key}, report, opts)
+	if err == nil {
+		log.Info("mongodb upsert daily report success", "date", report.Key)
+	} else {
+		log.Error("mongodb upsert daily report failed", "date", report.Key, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindDailyReport find daily report of date (2006-01-02)
+func FindDailyReport(date string) (*MgoDailyReport, error) {
+	result := &MgoDailyReport{}
+	err := collDailyReport.FindOne(clientCtx, bson.M{"_id": date}).Decode(result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
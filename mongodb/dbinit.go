@@ -4,12 +4,15 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/internal/metrics"
 	"github.com/anyswap/CrossChain-Bridge/log"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -43,6 +46,7 @@ func MongoServerInit(appName string, hosts []string, dbName, user, pass string)
 			Username:   user,
 			Password:   pass,
 		},
+		Monitor: commandMonitor,
 	}
 
 	if err := connect(clientOpts); err != nil {
@@ -55,6 +59,21 @@ func MongoServerInit(appName string, hosts []string, dbName, user, pass string)
 	go utils.WaitAndCleanup(doCleanup)
 }
 
+// commandMonitor record command latency if metrics is enabled
+var commandMonitor = &event.CommandMonitor{
+	Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+		observeCommand(&evt.CommandFinishedEvent, "ok")
+	},
+	Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+		observeCommand(&evt.CommandFinishedEvent, "error")
+	},
+}
+
+func observeCommand(evt *event.CommandFinishedEvent, result string) {
+	duration := time.Duration(evt.DurationNanos)
+	metrics.MongodbCommandDuration.Observe(duration.Seconds(), evt.CommandName, result)
+}
+
 func doCleanup() {
 	defer utils.TopWaitGroup.Done()
 	MgoWaitGroup.Wait()
@@ -67,6 +86,16 @@ func doCleanup() {
 	}
 }
 
+// Ping ping mongodb server with timeout
+func Ping(timeout time.Duration) error {
+	if client == nil {
+		return errors.New("mongodb is not connected")
+	}
+	ctx, cancel := context.WithTimeout(clientCtx, timeout)
+	defer cancel()
+	return client.Ping(ctx, nil)
+}
+
 func connect(opts *options.ClientOptions) (err error) {
 	ctx, cancel := context.WithTimeout(clientCtx, 10*time.Second)
 	defer cancel()
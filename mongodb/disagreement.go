@@ -0,0 +1,67 @@
+package mongodb
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DisagreeCheckUnknown check of disagreement which reason is not known locally
+const DisagreeCheckUnknown = "unknown"
+
+// AddSignDisagreement add or merge sign disagreement of the same keyID,
+// a known check overrides the unknown one recorded by the other side.
+func AddSignDisagreement(item *MgoSignDisagreement) error {
+	setOnInsert := bson.M{
+		"isswapin":  item.IsSwapin,
+		"txid":      item.TxID,
+		"pairid":    item.PairID,
+		"bind":      item.Bind,
+		"initiator": item.Initiator,
+		"timestamp": item.Timestamp,
+	}
+	update := bson.M{
+		"$setOnInsert": setOnInsert,
+		"$addToSet": bson.M{
+			"agreed":    bson.M{"$each": nonNilStrings(item.Agreed)},
+			"disagreed": bson.M{"$each": nonNilStrings(item.Disagreed)},
+			"reasons":   bson.M{"$each": nonNilReasons(item.Reasons)},
+		},
+	}
+	if item.Check == "" || item.Check == DisagreeCheckUnknown {
+		setOnInsert["check"] = DisagreeCheckUnknown
+	} else {
+		update["$set"] = bson.M{"check": item.Check}
+	}
+	opts := options.Update().SetUpsert(true)
+	_, err := collSignDisagreement.UpdateOne(clientCtx, bson.M{"_id": item.Key}, update, opts)
+	return mgoError(err)
+}
+
+// GetRecentSignDisagreements get latest sign disagreements
+func GetRecentSignDisagreements(limit int) ([]*MgoSignDisagreement, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}}).SetLimit(int64(limit))
+	cur, err := collSignDisagreement.Find(clientCtx, bson.M{}, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSignDisagreement, 0, limit)
+	err = cur.All(clientCtx, &result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+func nonNilStrings(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
+
+func nonNilReasons(items []*MgoDisagreeReason) []*MgoDisagreeReason {
+	if items == nil {
+		return []*MgoDisagreeReason{}
+	}
+	return items
+}
@@ -2,6 +2,7 @@ package mongodb
 
 import (
 	"errors"
+	"strings"
 
 	rpcjson "github.com/gorilla/rpc/v2/json2"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -27,6 +28,12 @@ func mgoError(err error) error {
 	return nil
 }
 
+// IsMgoError is error from mongodb operations
+func IsMgoError(err error) bool {
+	var rpcErr *rpcjson.Error
+	return errors.As(err, &rpcErr) && strings.HasPrefix(rpcErr.Message, "mgoError:")
+}
+
 // mongodb special errors
 var (
 	ErrItemNotFound       = newError(-32002, "mgoError: Item not found")
@@ -35,4 +42,9 @@ var (
 	ErrWrongKey           = newError(-32012, "mgoError: Wrong key")
 	ErrForbidUpdateNonce  = newError(-32013, "mgoError: Forbid update swap nonce")
 	ErrForbidUpdateSwapTx = newError(-32014, "mgoError: Forbid update swap tx")
+	ErrTooManySwapNotes   = newError(-32015, "mgoError: Too many notes of swap")
+	ErrSwapNoteTooLong    = newError(-32016, "mgoError: Swap note is too long")
+	ErrEmptySwapNote      = newError(-32017, "mgoError: Swap note is empty")
+	ErrEmptyAdminReason   = newError(-32018, "mgoError: Admin operation reason is empty")
+	ErrSwapBindAmbiguous  = newError(-32019, "mgoError: Multiple swaps match txid, specify bind")
 )
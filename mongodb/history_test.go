@@ -0,0 +1,52 @@
+package mongodb
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestGetSwapResultsFilter(t *testing.T) {
+	hexAddr := "0x00000000000000000000000000000000000000Ab"
+	lowerAddr := "0x00000000000000000000000000000000000000ab"
+	cases := []struct {
+		addresses []string
+		pairID    string
+		status    string
+		fromTime  int64
+		toTime    int64
+		want      bson.M
+	}{
+		{nil, "", "", 0, 0, bson.M{}},
+		{[]string{allAddresses}, allPairs, "", 0, 0, bson.M{}},
+		{[]string{hexAddr}, "", "", 0, 0, bson.M{"from": lowerAddr}},
+		{[]string{hexAddr, "mfwanCuX9vvk3wHnJ3ysNBeCZ9ccQzBYCX"}, "", "", 0, 0,
+			bson.M{"from": bson.M{"$in": []string{lowerAddr, "mfwanCuX9vvk3wHnJ3ysNBeCZ9ccQzBYCX"}}}},
+		{[]string{"addr1", "addr2"}, "ETH", "9,10", 0, 0,
+			bson.M{"$and": []bson.M{
+				{"pairid": "eth"},
+				{"from": bson.M{"$in": []string{"addr1", "addr2"}}},
+				{"status": bson.M{"$in": []SwapStatus{9, 10}}},
+			}}},
+		{nil, "ETH", "", 100, 200,
+			bson.M{"$and": []bson.M{
+				{"pairid": "eth"},
+				{"timestamp": bson.M{"$gte": int64(100), "$lte": int64(200)}},
+			}}},
+		// open ranges
+		{nil, "", "", 100, 0, bson.M{"timestamp": bson.M{"$gte": int64(100)}}},
+		{nil, "", "", 0, 200, bson.M{"timestamp": bson.M{"$lte": int64(200)}}},
+	}
+	for i, c := range cases {
+		if have := getSwapResultsFilter(c.addresses, c.pairID, c.status, c.fromTime, c.toTime); !reflect.DeepEqual(have, c.want) {
+			t.Errorf("case %v: want filter %v, have %v", i, c.want, have)
+		}
+	}
+
+	addresses := []string{hexAddr, "addr"}
+	getSwapResultsFilter(addresses, "", "", 0, 0)
+	if addresses[0] != hexAddr {
+		t.Errorf("addresses of caller should not be modified, have %v", addresses)
+	}
+}
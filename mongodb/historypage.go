@@ -0,0 +1,69 @@
+package mongodb
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MaxEstimatedHistoryCount estimated history count stops counting here
+const MaxEstimatedHistoryCount = 10000
+
+// HistoryCursor position of the last swap result of a history page,
+// history results are ordered by (inittime, _id).
+type HistoryCursor struct {
+	InitTime int64
+	Key      string
+}
+
+// FindSwapResultsAfterCursor find history results after cursor (nil means from the first one),
+// it's a range scan instead of skip. latest first if limit is negative like FindSwapResults.
+func FindSwapResultsAfterCursor(isSwapin bool, addresses []string, pairID string, cursor *HistoryCursor, limit int, status string, fromTime, toTime int64) ([]*MgoSwapResult, error) {
+	collection := getSwapOrResultCollection(isSwapin, true)
+	filter := getSwapResultsFilter(addresses, pairID, status, fromTime, toTime)
+	order := 1
+	if limit < 0 {
+		order, limit = -1, -limit
+	}
+	if cursor != nil {
+		filter = bson.M{"$and": []bson.M{filter, getHistoryCursorFilter(cursor, order)}}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "inittime", Value: order}, {Key: "_id", Value: order}}).
+		SetLimit(int64(limit))
+	cur, err := collection.Find(clientCtx, filter, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapResult, 0, limit)
+	err = cur.All(clientCtx, &result)
+	return result, mgoError(err)
+}
+
+func getHistoryCursorFilter(cursor *HistoryCursor, order int) bson.M {
+	cmp := "$gt"
+	if order < 0 {
+		cmp = "$lt"
+	}
+	return bson.M{"$or": []bson.M{
+		{"inittime": bson.M{cmp: cursor.InitTime}},
+		{"inittime": cursor.InitTime, "_id": bson.M{cmp: cursor.Key}},
+	}}
+}
+
+// CountSwapResults count history results in a separate query. if estimated,
+// it's the collection's estimated count when not filtered, otherwise counting
+// stops at MaxEstimatedHistoryCount, and isEstimated tells whether total is approximate.
+func CountSwapResults(isSwapin bool, addresses []string, pairID, status string, fromTime, toTime int64, estimated bool) (total int64, isEstimated bool, err error) {
+	collection := getSwapOrResultCollection(isSwapin, true)
+	filter := getSwapResultsFilter(addresses, pairID, status, fromTime, toTime)
+	if !estimated {
+		total, err = collection.CountDocuments(clientCtx, filter)
+		return total, false, mgoError(err)
+	}
+	if len(filter) == 0 {
+		total, err = collection.EstimatedDocumentCount(clientCtx)
+		return total, true, mgoError(err)
+	}
+	total, err = collection.CountDocuments(clientCtx, filter, options.Count().SetLimit(MaxEstimatedHistoryCount))
+	return total, total >= MaxEstimatedHistoryCount, mgoError(err)
+}
@@ -0,0 +1,77 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// IdempotencyKeyLifetime response snapshots are kept for this long
+	IdempotencyKeyLifetime = 24 * time.Hour
+	// IdempotencyReserveLifetime reservations not completed in this long are
+	// regarded as abandoned (eg. the server is down when calling the api)
+	IdempotencyReserveLifetime = 5 * time.Minute
+)
+
+// GetIdempotentResponseKey keys are scoped by method
+func GetIdempotentResponseKey(method, idempotencyKey string) string {
+	return method + ":" + idempotencyKey
+}
+
+// ReserveIdempotencyKey reserve idempotency key by inserting a pending item
+// on its unique id, return ErrItemIsDup if the key is already reserved or used.
+func ReserveIdempotencyKey(method, idempotencyKey, request string) error {
+	item := &MgoIdempotentResponse{
+		Key:            GetIdempotentResponseKey(method, idempotencyKey),
+		Method:         method,
+		IdempotencyKey: idempotencyKey,
+		Request:        request,
+		Pending:        true,
+		CreateTime:     time.Now(),
+	}
+	_, err := collIdempotencyKey.InsertOne(clientCtx, item)
+	return mgoError(err)
+}
+
+// CompleteIdempotentResponse store response snapshot of reserved idempotency key
+func CompleteIdempotentResponse(method, idempotencyKey, response string) error {
+	key := GetIdempotentResponseKey(method, idempotencyKey)
+	update := bson.M{"$set": bson.M{"response": response, "pending": false}}
+	_, err := collIdempotencyKey.UpdateByID(clientCtx, key, update)
+	if err != nil {
+		log.Error("mongodb complete idempotent response failed", "method", method, "idempotencyKey", idempotencyKey, "err", err)
+	}
+	return mgoError(err)
+}
+
+// ReleaseIdempotencyKey remove pending reservation, so that the failed request can be retried
+func ReleaseIdempotencyKey(method, idempotencyKey string) error {
+	key := GetIdempotentResponseKey(method, idempotencyKey)
+	_, err := collIdempotencyKey.DeleteOne(clientCtx, bson.M{"_id": key, "pending": true})
+	if err != nil {
+		log.Error("mongodb release idempotency key failed", "method", method, "idempotencyKey", idempotencyKey, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindIdempotentResponse find unexpired response snapshot or reservation of idempotent request
+func FindIdempotentResponse(method, idempotencyKey string) (*MgoIdempotentResponse, error) {
+	var result MgoIdempotentResponse
+	key := GetIdempotentResponseKey(method, idempotencyKey)
+	err := collIdempotencyKey.FindOne(clientCtx, bson.M{"_id": key}).Decode(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	lifetime := IdempotencyKeyLifetime
+	if result.Pending {
+		lifetime = IdempotencyReserveLifetime
+	}
+	// ttl monitor removes expired documents periodically, not immediately
+	if time.Since(result.CreateTime) > lifetime {
+		_, _ = collIdempotencyKey.DeleteOne(clientCtx, bson.M{"_id": key, "pending": result.Pending})
+		return nil, ErrItemNotFound
+	}
+	return &result, nil
+}
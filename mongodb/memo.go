@@ -0,0 +1,104 @@
+package mongodb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultMaxMemoLength = 1024 // bytes
+
+	// full memos collection is capped to this size
+	fullMemosCappedSize int64 = 256 * 1024 * 1024
+)
+
+var maxMemoLength = defaultMaxMemoLength
+
+// SetMaxMemoLength set max bytes length of memo stored in swaps, 0 means default
+func SetMaxMemoLength(length int) {
+	if length > 0 {
+		maxMemoLength = length
+	}
+}
+
+// sanitizeMemo strip control characters of memo and truncate it to max length.
+// if truncated the full memo is saved to full memos collection and referenced by id.
+func sanitizeMemo(memo string) string {
+	stripped := stripMemo(memo)
+	sanitized, truncated := truncateMemo(stripped, maxMemoLength, "")
+	if !truncated || collFullMemo == nil {
+		return sanitized
+	}
+	id := primitive.NewObjectID()
+	if err := addFullMemo(id, strings.ToValidUTF8(memo, string(utf8.RuneError))); err != nil {
+		log.Warn("mongodb add full memo failed", "err", err)
+		return sanitized
+	}
+	sanitized, _ = truncateMemo(stripped, maxMemoLength, id.Hex())
+	return sanitized
+}
+
+// stripMemo replace invalid utf8 bytes and control characters
+func stripMemo(memo string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == utf8.RuneError:
+			return -1
+		case r == '\n' || r == '\r' || r == '\t':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, memo)
+}
+
+// truncateMemo truncate memo to at most maxLength bytes (on rune boundary)
+// ending with ellipsis marker, which references the full memo id if not empty.
+func truncateMemo(memo string, maxLength int, fullMemoID string) (result string, truncated bool) {
+	if len(memo) <= maxLength {
+		return memo, false
+	}
+	marker := "...(truncated)"
+	if fullMemoID != "" {
+		marker = fmt.Sprintf("...(truncated, full memo %v)", fullMemoID)
+	}
+	end := maxLength - len(marker)
+	if end < 0 {
+		end = 0
+	}
+	for end > 0 && !utf8.RuneStart(memo[end]) {
+		end--
+	}
+	return memo[:end] + marker, true
+}
+
+func addFullMemo(id primitive.ObjectID, memo string) error {
+	_, err := collFullMemo.InsertOne(clientCtx, &MgoFullMemo{
+		Key:        id,
+		Memo:       memo,
+		CreateTime: time.Now().Unix(),
+	})
+	return mgoError(err)
+}
+
+// FindFullMemo find full memo by id referenced in truncated memo
+func FindFullMemo(id string) (*MgoFullMemo, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrItemNotFound
+	}
+	result := &MgoFullMemo{}
+	err = collFullMemo.FindOne(clientCtx, bson.M{"_id": objID}).Decode(result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,41 @@
+package mongodb
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitizeMemoStripsControlCharacters(t *testing.T) {
+	memo := "verify failed\x00: token\x07 name\nreverted\x1b[31m"
+	want := "verify failed: token name reverted[31m"
+	if have := sanitizeMemo(memo); have != want {
+		t.Fatalf("sanitize memo mismatch, have %q want %q", have, want)
+	}
+}
+
+func TestSanitizeMemoTruncatesLongError(t *testing.T) {
+	memo := "rpc error: " + strings.Repeat("é", 50*1024) // 100KB
+	have := sanitizeMemo(memo)
+	if len(have) > maxMemoLength {
+		t.Fatalf("memo is not truncated, length %v", len(have))
+	}
+	if !strings.HasPrefix(have, "rpc error: ") || !strings.HasSuffix(have, "...(truncated)") {
+		t.Fatalf("wrong truncated memo %q", have)
+	}
+	if !utf8.ValidString(have) {
+		t.Fatalf("truncated memo is not valid utf8")
+	}
+
+	have, _ = truncateMemo(memo, maxMemoLength, "0123456789abcdef01234567")
+	if len(have) > maxMemoLength || !strings.HasSuffix(have, "...(truncated, full memo 0123456789abcdef01234567)") {
+		t.Fatalf("wrong truncated memo with reference %q", have)
+	}
+}
+
+func TestSanitizeMemoKeepsShortMemo(t *testing.T) {
+	memo := "tx not stable"
+	if have := sanitizeMemo(memo); have != memo {
+		t.Fatalf("short memo changed, have %q", have)
+	}
+}
@@ -0,0 +1,41 @@
+package mongodb
+
+import (
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// UpgradeMempoolSwapin change status of swapin seen in mempool to 'TxNotStable' after it is mined,
+// then it is verified and signed as usual.
+func UpgradeMempoolSwapin(txid, pairID, bind string) error {
+	key := GetSwapKey(txid, strings.ToLower(pairID), bind)
+	filter := bson.M{"_id": key, "status": SeenInMempool}
+	updates := bson.M{"status": TxNotStable, "timestamp": time.Now().Unix(), "stableverified": false}
+	res, err := collSwapin.UpdateOne(clientCtx, filter, bson.M{"$set": updates})
+	if err == nil && res.ModifiedCount > 0 {
+		notifySwapStatusWritten(nil, true, txid, pairID, bind, TxNotStable, "", "")
+		log.Info("mongodb upgrade mempool swapin", "txid", txid, "pairID", pairID, "bind", bind)
+	} else if err != nil {
+		log.Error("mongodb upgrade mempool swapin", "txid", txid, "pairID", pairID, "bind", bind, "err", err)
+	}
+	return mgoError(err)
+}
+
+// RemoveMempoolSwapin remove swapin seen in mempool which is evicted or double spent,
+// swapins already upgraded after mined are kept.
+func RemoveMempoolSwapin(txid, pairID, bind string) (removed bool, err error) {
+	key := GetSwapKey(txid, strings.ToLower(pairID), bind)
+	res, err := collSwapin.DeleteOne(clientCtx, bson.M{"_id": key, "status": SeenInMempool})
+	if err != nil {
+		log.Error("mongodb remove mempool swapin", "txid", txid, "pairID", pairID, "bind", bind, "err", err)
+		return false, mgoError(err)
+	}
+	if res.DeletedCount > 0 {
+		notifySwapChanged(true, txid, pairID, bind)
+		log.Info("mongodb remove mempool swapin", "txid", txid, "pairID", pairID, "bind", bind)
+	}
+	return res.DeletedCount > 0, nil
+}
@@ -0,0 +1,33 @@
+package mongodb
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// FindOrphanedSwaps find registered swaps which have status in statuses and
+// last updated in time range [since, before), but have no swap result.
+func FindOrphanedSwaps(isSwapin bool, statuses []SwapStatus, since, before int64) ([]*MgoSwap, error) {
+	collection := getSwapOrResultCollection(isSwapin, false)
+	resultCollection := getSwapOrResultCollection(isSwapin, true)
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"status":    bson.M{"$in": statuses},
+			"timestamp": bson.M{"$gte": since, "$lt": before},
+		}},
+		{"$lookup": bson.M{
+			"from":         resultCollection.Name(),
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "result",
+		}},
+		{"$match": bson.M{"result": bson.M{"$size": 0}}},
+		{"$project": bson.M{"result": 0}},
+	}
+	cur, err := collection.Aggregate(clientCtx, pipeline)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	var result []*MgoSwap
+	err = cur.All(clientCtx, &result)
+	return result, mgoError(err)
+}
@@ -0,0 +1,59 @@
+package mongodb
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var payoutLegLock sync.Mutex
+
+type swapPayoutLegs struct {
+	PayoutLegs []*MgoPayoutLeg `bson:"payoutlegs"`
+}
+
+// mergePayoutLeg update leg with the same index, or insert it in order.
+// a resent leg keeps its replaced swap txs in 'OldSwapTxs'.
+func mergePayoutLeg(legs []*MgoPayoutLeg, leg *MgoPayoutLeg) []*MgoPayoutLeg {
+	for i, item := range legs {
+		if item.Index != leg.Index {
+			continue
+		}
+		oldSwapTxs := item.OldSwapTxs
+		if item.SwapTx != "" && !strings.EqualFold(item.SwapTx, leg.SwapTx) {
+			oldSwapTxs = append(oldSwapTxs, item.SwapTx)
+		}
+		leg.OldSwapTxs = oldSwapTxs
+		legs[i] = leg
+		return legs
+	}
+	legs = append(legs, leg)
+	sort.Slice(legs, func(i, j int) bool { return legs[i].Index < legs[j].Index })
+	return legs
+}
+
+// UpdateSwapResultPayoutLeg add or update payout leg of swap result
+func UpdateSwapResultPayoutLeg(isSwapin bool, txid, pairID, bind string, leg *MgoPayoutLeg) error {
+	payoutLegLock.Lock()
+	defer payoutLegLock.Unlock()
+
+	collection := getSwapOrResultCollection(isSwapin, true)
+	key := GetSwapKey(txid, pairID, bind)
+	var info swapPayoutLegs
+	err := collection.FindOne(clientCtx, bson.M{"_id": key}).Decode(&info)
+	if err != nil {
+		return mgoError(err)
+	}
+	legs := mergePayoutLeg(info.PayoutLegs, leg)
+	_, err = collection.UpdateByID(clientCtx, key, bson.M{"$set": bson.M{"payoutlegs": legs}})
+	notifySwapChanged(isSwapin, txid, pairID, bind)
+	if err == nil {
+		log.Info("mongodb update payout leg", "txid", txid, "pairID", pairID, "bind", bind, "index", leg.Index, "swaptx", leg.SwapTx, "stable", leg.Stable, "isSwapin", isSwapin)
+	} else {
+		log.Error("mongodb update payout leg", "txid", txid, "pairID", pairID, "bind", bind, "index", leg.Index, "swaptx", leg.SwapTx, "isSwapin", isSwapin, "err", err)
+	}
+	return mgoError(err)
+}
@@ -0,0 +1,26 @@
+package mongodb
+
+import (
+	"testing"
+)
+
+func TestMergePayoutLeg(t *testing.T) {
+	var legs []*MgoPayoutLeg
+	legs = mergePayoutLeg(legs, &MgoPayoutLeg{Index: 2, SwapTx: "0x2"})
+	legs = mergePayoutLeg(legs, &MgoPayoutLeg{Index: 1, SwapTx: "0x1"})
+	if len(legs) != 2 || legs[0].Index != 1 || legs[1].Index != 2 {
+		t.Fatalf("legs should be ordered by index, got %v", legs)
+	}
+
+	// resent leg keeps replaced tx
+	legs = mergePayoutLeg(legs, &MgoPayoutLeg{Index: 1, SwapTx: "0x1b"})
+	if len(legs) != 2 || legs[0].SwapTx != "0x1b" || len(legs[0].OldSwapTxs) != 1 || legs[0].OldSwapTxs[0] != "0x1" {
+		t.Fatalf("resent leg should replace swap tx and keep the old one, got %v", legs[0])
+	}
+
+	// marking stable does not add old tx
+	legs = mergePayoutLeg(legs, &MgoPayoutLeg{Index: 1, SwapTx: "0x1B", Stable: true})
+	if !legs[0].Stable || len(legs[0].OldSwapTxs) != 1 {
+		t.Errorf("update of same swap tx should not add old tx, got %v", legs[0])
+	}
+}
@@ -0,0 +1,182 @@
+package mongodb
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// swap processing stages
+const (
+	StageVerify = "verify"
+	StageSwap   = "swap"
+	StageStable = "stable"
+)
+
+const maxLastErrorLength = 512
+
+var (
+	quarantineLock sync.Mutex
+
+	quarantinedTotal uint64
+	requeuedTotal    uint64
+)
+
+// QuarantineMetrics quarantine metrics, alert when 'Quarantined' is not empty
+type QuarantineMetrics struct {
+	QuarantinedTotal uint64           `json:"quarantinedTotal"` // since start
+	RequeuedTotal    uint64           `json:"requeuedTotal"`    // since start
+	Quarantined      map[string]int64 `json:"quarantined"`      // table name -> quarantined count
+}
+
+type swapFailureInfo struct {
+	Status    SwapStatus `bson:"status"`
+	FailCount int        `bson:"failcount"`
+	FailStage string     `bson:"failstage"`
+}
+
+func getSwapOrResultCollection(isSwapin, isResult bool) *mongo.Collection {
+	switch {
+	case isSwapin && isResult:
+		return collSwapinResult
+	case isSwapin:
+		return collSwapin
+	case isResult:
+		return collSwapoutResult
+	default:
+		return collSwapout
+	}
+}
+
+// calcSwapFailCount count consecutive failures at the same stage
+func calcSwapFailCount(prevStage string, prevCount int, stage string) int {
+	if prevStage != stage || prevCount < 0 {
+		return 1
+	}
+	return prevCount + 1
+}
+
+// getSwapFailureUpdates get updates of recording a failure of swap with failure info,
+// quarantine the swap if it failed 'maxFailures' times at the same stage.
+func getSwapFailureUpdates(info *swapFailureInfo, stage string, procErr error, maxFailures int) (updates bson.M, quarantined bool) {
+	lastError := procErr.Error()
+	if len(lastError) > maxLastErrorLength {
+		lastError = lastError[:maxLastErrorLength]
+	}
+	failCount := calcSwapFailCount(info.FailStage, info.FailCount, stage)
+	updates = bson.M{
+		"failcount": failCount,
+		"failstage": stage,
+		"lasterror": lastError,
+	}
+	if maxFailures > 0 && failCount >= maxFailures {
+		quarantined = true
+		updates["status"] = Quarantined
+		updates["prevstatus"] = info.Status
+		updates["timestamp"] = time.Now().Unix()
+	}
+	return updates, quarantined
+}
+
+// RecordSwapFailure record swap processing failure,
+// and quarantine the swap if it failed too many times at the same stage.
+// the failure is ignored if swap status is changed by others after reading it.
+func RecordSwapFailure(isSwapin, isResult bool, txid, pairID, bind, stage string, procErr error, maxFailures int) (quarantined bool, err error) {
+	quarantineLock.Lock()
+	defer quarantineLock.Unlock()
+
+	collection := getSwapOrResultCollection(isSwapin, isResult)
+	key := GetSwapKey(txid, pairID, bind)
+	var info swapFailureInfo
+	err = collection.FindOne(clientCtx, bson.M{"_id": key}).Decode(&info)
+	if err != nil {
+		return false, mgoError(err)
+	}
+	if info.Status == Quarantined {
+		return true, nil
+	}
+
+	updates, quarantined := getSwapFailureUpdates(&info, stage, procErr, maxFailures)
+	res, err := collection.UpdateOne(clientCtx, bson.M{"_id": key, "status": info.Status}, bson.M{"$set": updates})
+	if err == nil && quarantined && res.MatchedCount > 0 {
+		notifySwapStatusWritten(nil, isSwapin, txid, pairID, bind, Quarantined, "", "")
+	} else {
+		notifySwapChanged(isSwapin, txid, pairID, bind)
+	}
+	if err != nil {
+		return false, mgoError(err)
+	}
+	if res.MatchedCount == 0 {
+		log.Info("mongodb record swap failure ignored as status changed", "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin, "isResult", isResult, "status", info.Status)
+		return false, nil
+	}
+	if quarantined {
+		atomic.AddUint64(&quarantinedTotal, 1)
+		log.Error("mongodb quarantine swap", "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin, "isResult", isResult, "stage", stage, "failCount", updates["failcount"], "lastError", updates["lasterror"])
+	}
+	return quarantined, nil
+}
+
+// ResetSwapFailure reset swap failure count after processed successfully
+func ResetSwapFailure(isSwapin, isResult bool, txid, pairID, bind string) error {
+	collection := getSwapOrResultCollection(isSwapin, isResult)
+	updates := bson.M{"failcount": 0, "failstage": "", "lasterror": ""}
+	_, err := collection.UpdateByID(clientCtx, GetSwapKey(txid, pairID, bind), bson.M{"$set": updates})
+	return mgoError(err)
+}
+
+// RequeueQuarantined requeue quarantined swap to its previous status
+func RequeueQuarantined(isSwapin bool, txid, pairID, bind string) error {
+	quarantineLock.Lock()
+	defer quarantineLock.Unlock()
+
+	key := GetSwapKey(txid, pairID, bind)
+	var requeued bool
+	for _, isResult := range []bool{false, true} {
+		collection := getSwapOrResultCollection(isSwapin, isResult)
+		var info MgoSwapResult
+		err := collection.FindOne(clientCtx, bson.M{"_id": key}).Decode(&info)
+		if err != nil || info.Status != Quarantined {
+			continue
+		}
+		updates := bson.M{
+			"status":    info.PrevStatus,
+			"failcount": 0,
+			"failstage": "",
+			"timestamp": time.Now().Unix(),
+		}
+		_, err = collection.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+		notifySwapStatusWritten(err, isSwapin, txid, pairID, bind, info.PrevStatus, "", "")
+		if err != nil {
+			return mgoError(err)
+		}
+		requeued = true
+		atomic.AddUint64(&requeuedTotal, 1)
+		log.Info("mongodb requeue quarantined swap", "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin, "isResult", isResult, "status", info.PrevStatus)
+	}
+	if !requeued {
+		return ErrItemNotFound
+	}
+	return nil
+}
+
+// GetQuarantineMetrics get quarantine metrics
+func GetQuarantineMetrics() (*QuarantineMetrics, error) {
+	metrics := &QuarantineMetrics{
+		QuarantinedTotal: atomic.LoadUint64(&quarantinedTotal),
+		RequeuedTotal:    atomic.LoadUint64(&requeuedTotal),
+		Quarantined:      make(map[string]int64),
+	}
+	for _, collection := range []*mongo.Collection{collSwapin, collSwapout, collSwapinResult, collSwapoutResult} {
+		count, err := collection.CountDocuments(clientCtx, bson.M{"status": Quarantined})
+		if err != nil {
+			return nil, mgoError(err)
+		}
+		metrics.Quarantined[collection.Name()] = count
+	}
+	return metrics, nil
+}
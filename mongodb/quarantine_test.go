@@ -0,0 +1,86 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCalcSwapFailCount(t *testing.T) {
+	cases := []struct {
+		prevStage string
+		prevCount int
+		stage     string
+		want      int
+	}{
+		{"", 0, StageVerify, 1},
+		{StageVerify, 1, StageVerify, 2},
+		{StageVerify, 5, StageSwap, 1},
+		{StageStable, -1, StageStable, 1},
+	}
+	for i, c := range cases {
+		got := calcSwapFailCount(c.prevStage, c.prevCount, c.stage)
+		if got != c.want {
+			t.Errorf("case %v: want %v, got %v", i, c.want, got)
+		}
+	}
+}
+
+func TestQuarantinedStatus(t *testing.T) {
+	status, err := ParseSwapStatus("Quarantined")
+	if err != nil {
+		t.Fatalf("parse quarantined status failed: %v", err)
+	}
+	if status != Quarantined {
+		t.Errorf("want %v, got %v", Quarantined, status)
+	}
+	if status.CanRetry() {
+		t.Errorf("quarantined swap should not be retried by users")
+	}
+	if info := status.Info(); info == nil || info.Category != StatusCategoryManual {
+		t.Errorf("quarantined status should need manual process")
+	}
+}
+
+func TestQuarantineUnparseableSwapResult(t *testing.T) {
+	// swap result document with corrupted value fails at swap stage on every iteration
+	doc := bson.M{"_id": "key", "txid": "txid", "value": "0xzz", "status": TxNotSwapped}
+	const maxFailures = 3
+	for i := 1; i <= maxFailures; i++ {
+		raw, err := bson.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var res MgoSwapResult
+		var info swapFailureInfo
+		if err = bson.Unmarshal(raw, &res); err != nil {
+			t.Fatal(err)
+		}
+		if err = bson.Unmarshal(raw, &info); err != nil {
+			t.Fatal(err)
+		}
+		_, procErr := common.GetBigIntFromStr(res.Value)
+		if procErr == nil {
+			t.Fatal("parse corrupted value should fail")
+		}
+		updates, quarantined := getSwapFailureUpdates(&info, StageSwap, procErr, maxFailures)
+		if quarantined != (i == maxFailures) {
+			t.Fatalf("failure %v: want quarantined %v, have %v", i, i == maxFailures, quarantined)
+		}
+		if updates["failcount"] != i {
+			t.Errorf("failure %v: wrong fail count %v", i, updates["failcount"])
+		}
+		for k, v := range updates { // apply '$set'
+			doc[k] = v
+		}
+	}
+	raw, _ := bson.Marshal(doc)
+	var res MgoSwapResult
+	if err := bson.Unmarshal(raw, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != Quarantined || res.PrevStatus != TxNotSwapped || res.LastError == "" {
+		t.Errorf("swap should be quarantined with previous status and last error, have %+v", res)
+	}
+}
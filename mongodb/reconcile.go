@@ -0,0 +1,32 @@
+package mongodb
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// IsSwapDatabaseEmpty is swap and swap result collections all empty (fresh database)
+func IsSwapDatabaseEmpty() (bool, error) {
+	for _, collection := range []*mongo.Collection{collSwapin, collSwapout, collSwapinResult, collSwapoutResult} {
+		count, err := collection.CountDocuments(clientCtx, bson.M{})
+		if err != nil {
+			return false, mgoError(err)
+		}
+		if count > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// FindAllSwapResultsWithStatus find all swap results with status (no time and count limit)
+func FindAllSwapResultsWithStatus(isSwapin bool, status SwapStatus) ([]*MgoSwapResult, error) {
+	collection := getSwapOrResultCollection(isSwapin, true)
+	cur, err := collection.Find(clientCtx, bson.M{"status": status})
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	var result []*MgoSwapResult
+	err = cur.All(clientCtx, &result)
+	return result, mgoError(err)
+}
@@ -0,0 +1,131 @@
+package mongodb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RefundStatus refund status
+type RefundStatus uint16
+
+// refund status constants
+const (
+	RefundTxNotStable RefundStatus = iota // 0
+	RefundTxStable                        // 1
+	RefundTxFailed                        // 2
+	RefundTxNotSent                       // 3 // sending refund tx failed, can initiate refund again
+)
+
+func (status RefundStatus) String() string {
+	switch status {
+	case RefundTxNotStable:
+		return "RefundTxNotStable"
+	case RefundTxStable:
+		return "RefundTxStable"
+	case RefundTxFailed:
+		return "RefundTxFailed"
+	case RefundTxNotSent:
+		return "RefundTxNotSent"
+	default:
+		return fmt.Sprintf("unknown refund status %d", status)
+	}
+}
+
+// AddRefund add refund (one refund per swap)
+func AddRefund(mr *MgoRefund) error {
+	mr.Key = GetSwapKey(mr.TxID, mr.PairID, mr.Bind)
+	mr.TxID = strings.ToLower(mr.TxID)
+	mr.PairID = strings.ToLower(mr.PairID)
+	_, err := collRefund.InsertOne(clientCtx, mr)
+	if err == nil {
+		log.Info("mongodb add refund success", "txid", mr.TxID, "pairID", mr.PairID, "bind", mr.Bind, "isSwapin", mr.IsSwapin, "refundtx", mr.RefundTx)
+	} else {
+		log.Error("mongodb add refund failed", "txid", mr.TxID, "pairID", mr.PairID, "bind", mr.Bind, "isSwapin", mr.IsSwapin, "err", err)
+	}
+	return mgoError(err)
+}
+
+// ReplaceNotSentRefund replace refund whose refund tx is not sent with a new one
+func ReplaceNotSentRefund(mr *MgoRefund) error {
+	mr.Key = GetSwapKey(mr.TxID, mr.PairID, mr.Bind)
+	mr.TxID = strings.ToLower(mr.TxID)
+	mr.PairID = strings.ToLower(mr.PairID)
+	res, err := collRefund.ReplaceOne(clientCtx, bson.M{"_id": mr.Key, "status": RefundTxNotSent}, mr)
+	if err == nil && res.MatchedCount == 0 {
+		err = mongo.ErrNoDocuments
+	}
+	if err == nil {
+		log.Info("mongodb replace refund success", "txid", mr.TxID, "pairID", mr.PairID, "bind", mr.Bind, "isSwapin", mr.IsSwapin, "refundtx", mr.RefundTx)
+	} else {
+		log.Error("mongodb replace refund failed", "txid", mr.TxID, "pairID", mr.PairID, "bind", mr.Bind, "isSwapin", mr.IsSwapin, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindRefund find refund of swap
+func FindRefund(txid, pairID, bind string) (*MgoRefund, error) {
+	result := &MgoRefund{}
+	err := collRefund.FindOne(clientCtx, bson.M{"_id": GetSwapKey(txid, pairID, bind)}).Decode(result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return result, nil
+}
+
+// FindRefundsWithStatus find refunds with status
+func FindRefundsWithStatus(status RefundStatus, limit int) ([]*MgoRefund, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "inittime", Value: 1}}).SetLimit(int64(limit))
+	cur, err := collRefund.Find(clientCtx, bson.M{"status": status}, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoRefund, 0, limit)
+	err = cur.All(clientCtx, &result)
+	return result, mgoError(err)
+}
+
+// UpdateRefundStatus update refund status and its tx block info
+func UpdateRefundStatus(txid, pairID, bind string, status RefundStatus, refundHeight, refundTime uint64) error {
+	updates := bson.M{
+		"status":    status,
+		"timestamp": time.Now().Unix(),
+	}
+	if refundHeight != 0 {
+		updates["refundheight"] = refundHeight
+		updates["refundtime"] = refundTime
+	}
+	_, err := collRefund.UpdateOne(clientCtx, bson.M{"_id": GetSwapKey(txid, pairID, bind)}, bson.M{"$set": updates})
+	if err == nil {
+		log.Info("mongodb update refund status success", "txid", txid, "pairID", pairID, "bind", bind, "status", status)
+	} else {
+		log.Error("mongodb update refund status failed", "txid", txid, "pairID", pairID, "bind", bind, "status", status, "err", err)
+	}
+	return mgoError(err)
+}
+
+// MarkSwapRefunded mark swap as refunded and link it to the refund tx
+func MarkSwapRefunded(isSwapin bool, txid, pairID, bind, refundTx string) error {
+	collection := collSwapout
+	if isSwapin {
+		collection = collSwapin
+	}
+	updates := bson.M{
+		"status":    Refunded,
+		"refundtx":  refundTx,
+		"timestamp": time.Now().Unix(),
+	}
+	_, err := collection.UpdateOne(clientCtx, bson.M{"_id": GetSwapKey(txid, pairID, bind)}, bson.M{"$set": updates})
+	notifySwapStatusWritten(err, isSwapin, txid, pairID, bind, Refunded, "", "")
+	if err == nil {
+		log.Info("mongodb mark swap refunded success", "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin, "refundtx", refundTx)
+	} else {
+		log.Error("mongodb mark swap refunded failed", "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin, "err", err)
+	}
+	return mgoError(err)
+}
@@ -0,0 +1,216 @@
+package mongodb
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// register sources of registered address
+const (
+	RegisterSourceAPI   = "api"
+	RegisterSourceChain = "chain"
+)
+
+const keyPrefixOfRegistryScanInfo = "registry:"
+
+var registerAddressLock sync.Mutex
+
+// GetSource get register source, old records without source are registered by api
+func (r *MgoRegisteredAddress) GetSource() string {
+	if r.Source == "" {
+		return RegisterSourceAPI
+	}
+	return r.Source
+}
+
+// IsValidRegisterPrecedence is valid register precedence
+func IsValidRegisterPrecedence(precedence string) bool {
+	return precedence == RegisterSourceAPI || precedence == RegisterSourceChain
+}
+
+// resolveRegisteredAddress decide whether incoming registration replaces the existing one.
+// registrations from different sources are conflicts and resolved by precedence,
+// chain registrations of the same address are replaced by later bind events.
+func resolveRegisteredAddress(existing, incoming *MgoRegisteredAddress, precedence string) (replace, conflict bool) {
+	if existing == nil {
+		return true, false
+	}
+	if existing.GetSource() != incoming.GetSource() {
+		return incoming.GetSource() == precedence, true
+	}
+	if incoming.GetSource() == RegisterSourceAPI {
+		return false, false
+	}
+	return incoming.BlockHeight >= existing.BlockHeight && incoming.BindTo != existing.BindTo, false
+}
+
+// AddRegisteredAddress add register address
+func AddRegisteredAddress(address, precedence string) error {
+	ma := &MgoRegisteredAddress{
+		Key:       address,
+		Timestamp: time.Now().Unix(),
+		Source:    RegisterSourceAPI,
+	}
+	registerAddressLock.Lock()
+	defer registerAddressLock.Unlock()
+
+	existing, _ := FindRegisteredAddress(address)
+	if existing != nil && existing.GetSource() != RegisterSourceAPI {
+		return replaceRegisteredAddress(existing, ma, precedence)
+	}
+	_, err := collRegisteredAddress.InsertOne(clientCtx, ma)
+	if err == nil {
+		log.Info("mongodb add register address", "key", ma.Key)
+	} else if !mongo.IsDuplicateKeyError(err) {
+		log.Error("mongodb add register address", "key", ma.Key, "err", err)
+	}
+	return mgoError(err)
+}
+
+// AddRegisteredAddresses add register addresses by unordered bulk insert,
+// so one duplicate does not abort the others. returns error of each address,
+// which is ErrItemIsDup if it is already registered (by any source).
+func AddRegisteredAddresses(addresses []string) ([]error, error) {
+	errs := make([]error, len(addresses))
+	if len(addresses) == 0 {
+		return errs, nil
+	}
+	now := time.Now().Unix()
+	docs := make([]interface{}, len(addresses))
+	for i, address := range addresses {
+		docs[i] = &MgoRegisteredAddress{
+			Key:       address,
+			Timestamp: now,
+			Source:    RegisterSourceAPI,
+		}
+	}
+	failed := 0
+	_, err := collRegisteredAddress.InsertMany(clientCtx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		var bwe mongo.BulkWriteException
+		if !errors.As(err, &bwe) || bwe.WriteConcernError != nil {
+			log.Error("mongodb add register addresses", "count", len(addresses), "err", err)
+			return nil, mgoError(err)
+		}
+		for _, we := range bwe.WriteErrors {
+			if we.Index < 0 || we.Index >= len(addresses) {
+				continue
+			}
+			failed++
+			if we.Code == 11000 { // DuplicateKey
+				errs[we.Index] = ErrItemIsDup
+			} else {
+				errs[we.Index] = newError(-32001, "mgoError: "+we.Message)
+			}
+		}
+	}
+	log.Info("mongodb add register addresses", "count", len(addresses), "failed", failed)
+	return errs, nil
+}
+
+// AddChainRegisteredAddress add register address mirrored from registry contract bind event
+func AddChainRegisteredAddress(ma *MgoRegisteredAddress, precedence string) error {
+	ma.Source = RegisterSourceChain
+	if ma.Timestamp == 0 {
+		ma.Timestamp = time.Now().Unix()
+	}
+	registerAddressLock.Lock()
+	defer registerAddressLock.Unlock()
+
+	existing, _ := FindRegisteredAddress(ma.Key)
+	return replaceRegisteredAddress(existing, ma, precedence)
+}
+
+func replaceRegisteredAddress(existing, ma *MgoRegisteredAddress, precedence string) error {
+	replace, conflict := resolveRegisteredAddress(existing, ma, precedence)
+	if conflict {
+		log.Warn("register address conflict", "key", ma.Key, "precedence", precedence, "replace", replace,
+			"existSource", existing.GetSource(), "existBindTo", existing.BindTo,
+			"newSource", ma.GetSource(), "newBindTo", ma.BindTo, "newTxHash", ma.TxHash)
+	}
+	if !replace {
+		return nil
+	}
+	_, err := collRegisteredAddress.ReplaceOne(clientCtx, bson.M{"_id": ma.Key}, ma, options.Replace().SetUpsert(true))
+	if err == nil {
+		log.Info("mongodb replace register address", "key", ma.Key, "source", ma.GetSource(), "bindTo", ma.BindTo, "txHash", ma.TxHash)
+	} else {
+		log.Error("mongodb replace register address", "key", ma.Key, "source", ma.GetSource(), "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindRegisteredAddresses find registered addresses with key prefix,
+// and the total count of matched addresses.
+// the prefix is matched by an anchored regex, which is served by the '_id' index.
+// fromTime and toTime (unix seconds, inclusive) bound register timestamp, zero means unbounded,
+// results are in register time order if time is bounded, otherwise in key order.
+func FindRegisteredAddresses(prefix string, fromTime, toTime int64, offset, limit int) ([]*MgoRegisteredAddress, int64, error) {
+	filter := bson.M{}
+	if prefix != "" {
+		filter["_id"] = bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}
+	}
+	sort := bson.D{{Key: "_id", Value: 1}}
+	if fromTime > 0 || toTime > 0 {
+		qtime := bson.M{}
+		if fromTime > 0 {
+			qtime["$gte"] = fromTime
+		}
+		if toTime > 0 {
+			qtime["$lte"] = toTime
+		}
+		filter["timestamp"] = qtime
+		sort = bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}
+	}
+	total, err := collRegisteredAddress.CountDocuments(clientCtx, filter)
+	if err != nil {
+		return nil, 0, mgoError(err)
+	}
+	opts := options.Find().
+		SetSort(sort).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cur, err := collRegisteredAddress.Find(clientCtx, filter, opts)
+	if err != nil {
+		return nil, 0, mgoError(err)
+	}
+	result := make([]*MgoRegisteredAddress, 0, limit)
+	err = cur.All(clientCtx, &result)
+	return result, total, mgoError(err)
+}
+
+func getRegistryScanInfoKey(registry string) string {
+	return keyPrefixOfRegistryScanInfo + strings.ToLower(registry)
+}
+
+// UpdateRegistryScanHeight update scanned block height of registry contract
+func UpdateRegistryScanHeight(registry string, blockHeight uint64) error {
+	key := getRegistryScanInfoKey(registry)
+	updates := bson.M{
+		"blockheight": blockHeight,
+		"timestamp":   time.Now().Unix(),
+	}
+	_, err := collLatestScanInfo.UpdateByID(clientCtx, key, bson.M{"$set": updates}, options.Update().SetUpsert(true))
+	if err != nil {
+		log.Error("mongodb update registry scan height", "registry", registry, "updates", updates, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindRegistryScanHeight find scanned block height of registry contract
+func FindRegistryScanHeight(registry string) (uint64, error) {
+	var result MgoLatestScanInfo
+	err := collLatestScanInfo.FindOne(clientCtx, bson.M{"_id": getRegistryScanInfoKey(registry)}).Decode(&result)
+	if err != nil {
+		return 0, mgoError(err)
+	}
+	return result.BlockHeight, nil
+}
@@ -0,0 +1,32 @@
+package mongodb
+
+import "testing"
+
+func TestResolveRegisteredAddress(t *testing.T) {
+	apiItem := &MgoRegisteredAddress{Key: "0x1111"}
+	chainItem := &MgoRegisteredAddress{Key: "0x1111", Source: RegisterSourceChain, BindTo: "0x2222", BlockHeight: 100}
+	rebindItem := &MgoRegisteredAddress{Key: "0x1111", Source: RegisterSourceChain, BindTo: "0x3333", BlockHeight: 200}
+
+	tests := []struct {
+		name               string
+		existing, incoming *MgoRegisteredAddress
+		precedence         string
+		replace, conflict  bool
+	}{
+		{"new api", nil, apiItem, RegisterSourceAPI, true, false},
+		{"new chain", nil, chainItem, RegisterSourceAPI, true, false},
+		{"dup api", apiItem, apiItem, RegisterSourceChain, false, false},
+		{"chain over api, api first", apiItem, chainItem, RegisterSourceAPI, false, true},
+		{"chain over api, chain first", apiItem, chainItem, RegisterSourceChain, true, true},
+		{"api over chain, api first", chainItem, apiItem, RegisterSourceAPI, true, true},
+		{"api over chain, chain first", chainItem, apiItem, RegisterSourceChain, false, true},
+		{"chain rebind", chainItem, rebindItem, RegisterSourceAPI, true, false},
+		{"chain stale rebind", rebindItem, chainItem, RegisterSourceAPI, false, false},
+	}
+	for _, test := range tests {
+		replace, conflict := resolveRegisteredAddress(test.existing, test.incoming, test.precedence)
+		if replace != test.replace || conflict != test.conflict {
+			t.Errorf("%v: want replace=%v conflict=%v, have replace=%v conflict=%v", test.name, test.replace, test.conflict, replace, conflict)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package mongodb
+
+import (
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// risk hook verdicts recorded in admin audits
+const (
+	RiskHookOperator   = "riskhook"
+	RiskVerdictAuditOp = "riskverdict"
+)
+
+// BindSwapStats swap history stats of bind address in pair
+type BindSwapStats struct {
+	Total     int64 `json:"total"`
+	Completed int64 `json:"completed"`
+	Failed    int64 `json:"failed"`
+}
+
+// GetBindSwapStats get swap history stats of bind address in pair
+func GetBindSwapStats(isSwapin bool, pairID, bind string) (*BindSwapStats, error) {
+	collection := getSwapOrResultCollection(isSwapin, true)
+	filter := bson.M{"pairid": strings.ToLower(pairID), "bind": bind}
+	total, err := collection.CountDocuments(clientCtx, filter)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	filter["status"] = MatchTxStable
+	completed, err := collection.CountDocuments(clientCtx, filter)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	filter["status"] = MatchTxFailed
+	failed, err := collection.CountDocuments(clientCtx, filter)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return &BindSwapStats{Total: total, Completed: completed, Failed: failed}, nil
+}
+
+func setSwapRiskOverridden(isSwapin bool, txid, pairID, bind string) error {
+	collection := getSwapOrResultCollection(isSwapin, false)
+	_, err := collection.UpdateByID(clientCtx, GetSwapKey(txid, pairID, bind), bson.M{"$set": bson.M{"riskoverridden": true}})
+	if err != nil {
+		log.Error("mongodb set swap risk overridden failed", "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin, "err", err)
+	}
+	return mgoError(err)
+}
@@ -0,0 +1,106 @@
+package mongodb
+
+import (
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MaxSignAttemptsPerSwap keep only the latest sign attempts
+const MaxSignAttemptsPerSwap = 20
+
+var signAttemptLock sync.Mutex
+
+type swapSignAttempts struct {
+	SignAttempts []*MgoSignAttempt `bson:"signattempts"`
+}
+
+// mergeSignAttempt update attempt with the same keyID, or append it
+func mergeSignAttempt(attempts []*MgoSignAttempt, attempt *MgoSignAttempt) []*MgoSignAttempt {
+	for i, item := range attempts {
+		if item.KeyID == attempt.KeyID {
+			attempts[i] = attempt
+			return attempts
+		}
+	}
+	attempts = append(attempts, attempt)
+	if len(attempts) > MaxSignAttemptsPerSwap {
+		attempts = attempts[len(attempts)-MaxSignAttemptsPerSwap:]
+	}
+	return attempts
+}
+
+// RecordSignAttempt record dcrm sign attempt of swap result
+func RecordSignAttempt(isSwapin bool, txid, pairID, bind string, attempt *MgoSignAttempt) error {
+	signAttemptLock.Lock()
+	defer signAttemptLock.Unlock()
+
+	collection := getSwapOrResultCollection(isSwapin, true)
+	key := GetSwapKey(txid, pairID, bind)
+	var info swapSignAttempts
+	err := collection.FindOne(clientCtx, bson.M{"_id": key}).Decode(&info)
+	if err != nil {
+		return mgoError(err)
+	}
+	attempts := mergeSignAttempt(info.SignAttempts, attempt)
+	_, err = collection.UpdateByID(clientCtx, key, bson.M{"$set": bson.M{"signattempts": attempts}})
+	return mgoError(err)
+}
+
+// FindSwapResultsBySignInitiator find latest swap results with sign attempts initiated by initiator
+func FindSwapResultsBySignInitiator(isSwapin bool, initiator string, offset, limit int) ([]*MgoSwapResult, error) {
+	collection := getSwapOrResultCollection(isSwapin, true)
+	filter := bson.M{"signattempts.initiator": strings.ToLower(initiator)}
+	opts := options.Find().SetSort(bson.D{{Key: "inittime", Value: -1}}).
+		SetSkip(int64(offset)).SetLimit(int64(limit))
+	cur, err := collection.Find(clientCtx, filter, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapResult, 0, limit)
+	err = cur.All(clientCtx, &result)
+	return result, mgoError(err)
+}
+
+// PendingSwapResult status and latest sign attempt of pending swap result
+type PendingSwapResult struct {
+	Status          SwapStatus
+	LastSignAttempt *MgoSignAttempt
+}
+
+// FindPendingSwapResults find swap results waiting for swap tx to be built or be stable,
+// only the latest sign attempt of each result is loaded.
+func FindPendingSwapResults(isSwapin bool) ([]*PendingSwapResult, error) {
+	collection := getSwapOrResultCollection(isSwapin, true)
+	filter := bson.M{"status": bson.M{"$in": []SwapStatus{MatchTxEmpty, MatchTxNotStable}}}
+	opts := options.Find().SetProjection(bson.M{"status": 1, "signattempts": bson.M{"$slice": -1}})
+	cur, err := collection.Find(clientCtx, filter, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	var items []struct {
+		Status       SwapStatus        `bson:"status"`
+		SignAttempts []*MgoSignAttempt `bson:"signattempts"`
+	}
+	if err = cur.All(clientCtx, &items); err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*PendingSwapResult, 0, len(items))
+	for _, item := range items {
+		pending := &PendingSwapResult{Status: item.Status}
+		if count := len(item.SignAttempts); count > 0 {
+			pending.LastSignAttempt = item.SignAttempts[count-1]
+		}
+		result = append(result, pending)
+	}
+	return result, nil
+}
+
+// CountSwapsWithStatus count registered swaps with status
+func CountSwapsWithStatus(isSwapin bool, status SwapStatus) (int64, error) {
+	collection := getSwapOrResultCollection(isSwapin, false)
+	count, err := collection.CountDocuments(clientCtx, bson.M{"status": status})
+	return count, mgoError(err)
+}
@@ -0,0 +1,24 @@
+package mongodb
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMergeSignAttempt(t *testing.T) {
+	var attempts []*MgoSignAttempt
+	attempts = mergeSignAttempt(attempts, &MgoSignAttempt{KeyID: "key1", DcrmStatus: "Pending"})
+	attempts = mergeSignAttempt(attempts, &MgoSignAttempt{KeyID: "key1", DcrmStatus: "Timeout"})
+	if len(attempts) != 1 || attempts[0].DcrmStatus != "Timeout" {
+		t.Fatalf("attempt with same keyID should be updated, got %v attempts", len(attempts))
+	}
+	for i := 0; i < MaxSignAttemptsPerSwap+5; i++ {
+		attempts = mergeSignAttempt(attempts, &MgoSignAttempt{KeyID: fmt.Sprintf("key-%d", i)})
+	}
+	if len(attempts) != MaxSignAttemptsPerSwap {
+		t.Fatalf("want %v attempts, got %v", MaxSignAttemptsPerSwap, len(attempts))
+	}
+	if last := attempts[len(attempts)-1].KeyID; last != fmt.Sprintf("key-%d", MaxSignAttemptsPerSwap+4) {
+		t.Errorf("latest attempt should be kept, got %v", last)
+	}
+}
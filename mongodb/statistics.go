@@ -0,0 +1,152 @@
+package mongodb
+
+import (
+	"math/big"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PairStatusStat swap results of pair with the same status,
+// values are sums in smallest unit.
+type PairStatusStat struct {
+	PairID    string
+	Status    SwapStatus
+	Count     int64
+	Value     *big.Int
+	SwapValue *big.Int
+}
+
+// AggregateSwapResultsByPair group swap results by pair and status in one aggregation,
+// pairs without swap results are not in result. sums are decimal128 in mongodb,
+// which keep 34 significant digits.
+func AggregateSwapResultsByPair(isSwapin bool) ([]*PairStatusStat, error) {
+	collection := getSwapOrResultCollection(isSwapin, true)
+	toDecimal := func(field string) bson.M {
+		return bson.M{"$convert": bson.M{"input": field, "to": "decimal", "onError": 0, "onNull": 0}}
+	}
+	pipeline := []bson.M{
+		{"$group": bson.M{
+			"_id":       bson.M{"pairid": "$pairid", "status": "$status"},
+			"count":     bson.M{"$sum": 1},
+			"value":     bson.M{"$sum": toDecimal("$value")},
+			"swapvalue": bson.M{"$sum": toDecimal("$swapvalue")},
+		}},
+		{"$project": bson.M{
+			"count":     1,
+			"value":     bson.M{"$toDecimal": "$value"},
+			"swapvalue": bson.M{"$toDecimal": "$swapvalue"},
+		}},
+	}
+	cur, err := collection.Aggregate(clientCtx, pipeline)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	var groups []struct {
+		ID struct {
+			PairID string     `bson:"pairid"`
+			Status SwapStatus `bson:"status"`
+		} `bson:"_id"`
+		Count     int64                `bson:"count"`
+		Value     primitive.Decimal128 `bson:"value"`
+		SwapValue primitive.Decimal128 `bson:"swapvalue"`
+	}
+	if err = cur.All(clientCtx, &groups); err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*PairStatusStat, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, &PairStatusStat{
+			PairID:    group.ID.PairID,
+			Status:    group.ID.Status,
+			Count:     group.Count,
+			Value:     decimal128ToBigInt(group.Value),
+			SwapValue: decimal128ToBigInt(group.SwapValue),
+		})
+	}
+	return result, nil
+}
+
+// VolumeBucketStat success swap results of pair in time bucket,
+// values are sums in smallest unit.
+type VolumeBucketStat struct {
+	Start     int64 // unix seconds
+	Count     int64
+	Value     *big.Int
+	SwapValue *big.Int
+}
+
+// AggregateSwapVolumeByTime group success swap results of pair with timestamp in [fromTime, toTime]
+// by time bucket, buckets start at 'offset' seconds of unix epoch and last 'bucketSize' seconds.
+// buckets without swap results are not in result.
+func AggregateSwapVolumeByTime(isSwapin bool, pairID string, bucketSize, offset, fromTime, toTime int64) ([]*VolumeBucketStat, error) {
+	var successStatuses []SwapStatus
+	for _, info := range statusRegistry {
+		if info.Category == StatusCategorySuccess {
+			successStatuses = append(successStatuses, info.Code)
+		}
+	}
+	collection := getSwapOrResultCollection(isSwapin, true)
+	toDecimal := func(field string) bson.M {
+		return bson.M{"$convert": bson.M{"input": field, "to": "decimal", "onError": 0, "onNull": 0}}
+	}
+	bucketStart := bson.M{"$subtract": bson.A{
+		"$timestamp",
+		bson.M{"$mod": bson.A{bson.M{"$subtract": bson.A{"$timestamp", offset}}, bucketSize}},
+	}}
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"pairid":    strings.ToLower(pairID),
+			"status":    bson.M{"$in": successStatuses},
+			"timestamp": bson.M{"$gte": fromTime, "$lte": toTime},
+		}},
+		{"$group": bson.M{
+			"_id":       bucketStart,
+			"count":     bson.M{"$sum": 1},
+			"value":     bson.M{"$sum": toDecimal("$value")},
+			"swapvalue": bson.M{"$sum": toDecimal("$swapvalue")},
+		}},
+		{"$project": bson.M{
+			"count":     1,
+			"value":     bson.M{"$toDecimal": "$value"},
+			"swapvalue": bson.M{"$toDecimal": "$swapvalue"},
+		}},
+		{"$sort": bson.M{"_id": 1}},
+	}
+	cur, err := collection.Aggregate(clientCtx, pipeline)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	var buckets []struct {
+		Start     int64                `bson:"_id"`
+		Count     int64                `bson:"count"`
+		Value     primitive.Decimal128 `bson:"value"`
+		SwapValue primitive.Decimal128 `bson:"swapvalue"`
+	}
+	if err = cur.All(clientCtx, &buckets); err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*VolumeBucketStat, 0, len(buckets))
+	for _, bucket := range buckets {
+		result = append(result, &VolumeBucketStat{
+			Start:     bucket.Start,
+			Count:     bucket.Count,
+			Value:     decimal128ToBigInt(bucket.Value),
+			SwapValue: decimal128ToBigInt(bucket.SwapValue),
+		})
+	}
+	return result, nil
+}
+
+// decimal128ToBigInt integer part of decimal, zero if it's NaN or Inf
+func decimal128ToBigInt(d primitive.Decimal128) *big.Int {
+	bi, exp, err := d.BigInt()
+	if err != nil {
+		return big.NewInt(0)
+	}
+	if exp >= 0 {
+		return bi.Mul(bi, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil))
+	}
+	return bi.Quo(bi, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil))
+}
@@ -0,0 +1,28 @@
+package mongodb
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDecimal128ToBigInt(t *testing.T) {
+	tests := map[string]string{
+		"0":                      "0",
+		"123456789012345678901":  "123456789012345678901",
+		"1.5E+30":                "1500000000000000000000000000000",
+		"12.75":                  "12",
+		"NaN":                    "0",
+		"Infinity":               "0",
+		"-1000000000000000000.0": "-1000000000000000000",
+	}
+	for str, want := range tests {
+		d, err := primitive.ParseDecimal128(str)
+		if err != nil {
+			t.Fatalf("parse decimal %v failed, %v", str, err)
+		}
+		if have := decimal128ToBigInt(d).String(); have != want {
+			t.Errorf("decimal %v want %v, have %v", str, want, have)
+		}
+	}
+}
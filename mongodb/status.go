@@ -2,6 +2,9 @@ package mongodb
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
 )
 
 // -----------------------------------------------
@@ -11,24 +14,30 @@ import (
 // -----------------------------------------------
 // 1. swap register status change graph
 //
+// RegisteredUnstable (verified with unstable tx) -> TxNotStable (reverify at stable depth)
 // TxNotStable -> |- TxVerifyFailed        -> admin reverify ---> TxNotStable
+//                |- TxIsNotDeposit        -> admin reverify ---> TxNotStable
 //                |- BindAddrIsContract    -> admin reverify ---> TxNotStable
 //                |- TxSenderNotRegistered -> retry reverify ---> TxNotStable
 //                |- TxWithBigValue        -> admin bigvalue ---> TxNotSwapped
+//                |- SwapValueIsDust       -> admin reverify ---> TxNotStable (eg. after lowering fee)
 //                |- TxWithWrongMemo   -> manual
 //                |- TxWithWrongSender -> manual
 //                |- TxWithWrongValue  -> manual
 //                |- SwapInBlacklist   -> manual
 //                |- ManualMakeFail    -> manual
-//                |- TxNotSwapped -> |- TxProcessed (->MatchTxNotStable or ->MatchTxFailed)
+//                |- TxNotSwapped (stable verified) -> |- TxProcessed (->MatchTxNotStable or ->MatchTxFailed)
+//                                                    |- RiskRejected -> admin passswap ---> TxNotSwapped
 // -----------------------------------------------
 // 2. swap result status change graph
 //
 // TxWithWrongMemo -> manual
 // TxWithBigValue  -> admin bigvalue ---> MatchTxEmpty
+// SwapValueIsDust -> manual
 // MatchTxEmpty    -> |- MatchTxNotStable [admin replace]
 // -> |- MatchTxStable
 //    |- MatchTxFailed -> admin reswap ---> MatchTxEmpty
+// non-terminal result -> startup reconcile ---> MatchTxStable or SwapExpired
 // -----------------------------------------------
 
 // SwapStatus swap status
@@ -54,19 +63,162 @@ const (
 	SwapInBlacklist                         // 15
 	ManualMakeFail                          // 16
 	BindAddrIsContract                      // 17
+	Quarantined                             // 18
+	SwapExpired                             // 19
+	RegisteredUnstable                      // 20
+	Refunded                                // 21
+	SwapValueIsDust                         // 22
+	SeenInMempool                           // 23
+	TxIsNotDeposit                          // 24
+	RiskRejected                            // 25
 
 	KeepStatus = 255
 	Reswapping = 256
 )
 
+// SwapStatusCategory swap status category
+type SwapStatusCategory string
+
+// swap status categories
+const (
+	StatusCategoryPending SwapStatusCategory = "pending"
+	StatusCategorySuccess SwapStatusCategory = "success"
+	StatusCategoryFailed  SwapStatusCategory = "failed"
+	StatusCategoryManual  SwapStatusCategory = "manual"
+)
+
+// SwapStatusInfo swap status info
+type SwapStatusInfo struct {
+	Code        SwapStatus         `json:"code"`
+	Name        string             `json:"name"`
+	Category    SwapStatusCategory `json:"category"`
+	IsTerminal  bool               `json:"isTerminal"`
+	Description string             `json:"description"`
+
+	// verify errors of tokens package resulting in this status, only in catalog
+	VerifyErrors []string `json:"verifyErrors,omitempty"`
+
+	canRetry bool
+}
+
+// statusRegistry is the single source of truth of swap statuses
+var statusRegistry = []*SwapStatusInfo{
+	{Code: TxNotStable, Name: "TxNotStable", Category: StatusCategoryPending, Description: "deposit tx is registered and waiting for verification"},
+	{Code: TxVerifyFailed, Name: "TxVerifyFailed", Category: StatusCategoryFailed, IsTerminal: true, Description: "deposit tx verification failed"},
+	{Code: TxWithWrongSender, Name: "TxWithWrongSender", Category: StatusCategoryManual, IsTerminal: true, Description: "deposit tx has wrong sender (deprecated)"},
+	{Code: TxWithWrongValue, Name: "TxWithWrongValue", Category: StatusCategoryFailed, IsTerminal: true, Description: "deposit value is out of the allowed range"},
+	{Code: TxIncompatible, Name: "TxIncompatible", Category: StatusCategoryFailed, IsTerminal: true, Description: "deposit tx is incompatible (deprecated)"},
+	{Code: TxNotSwapped, Name: "TxNotSwapped", Category: StatusCategoryPending, Description: "deposit tx is verified and waiting to be swapped"},
+	{Code: TxSwapFailed, Name: "TxSwapFailed", Category: StatusCategoryFailed, IsTerminal: true, Description: "swap failed (deprecated)"},
+	{Code: TxProcessed, Name: "TxProcessed", Category: StatusCategoryPending, Description: "swap tx is built and sent"},
+	{Code: MatchTxEmpty, Name: "MatchTxEmpty", Category: StatusCategoryPending, Description: "waiting for swap tx to be built"},
+	{Code: MatchTxNotStable, Name: "MatchTxNotStable", Category: StatusCategoryPending, Description: "swap tx is sent and waiting for stable"},
+	{Code: MatchTxStable, Name: "MatchTxStable", Category: StatusCategorySuccess, IsTerminal: true, Description: "swap tx is stable"},
+	{Code: TxWithWrongMemo, Name: "TxWithWrongMemo", Category: StatusCategoryManual, IsTerminal: true, Description: "deposit tx has wrong memo or bind address"},
+	{Code: TxWithBigValue, Name: "TxWithBigValue", Category: StatusCategoryManual, Description: "deposit value is big and needs admin approval"},
+	{Code: TxSenderNotRegistered, Name: "TxSenderNotRegistered", Category: StatusCategoryManual, Description: "deposit sender is not registered, retry after registering", canRetry: true},
+	{Code: MatchTxFailed, Name: "MatchTxFailed", Category: StatusCategoryFailed, IsTerminal: true, Description: "swap tx failed on chain"},
+	{Code: SwapInBlacklist, Name: "SwapInBlacklist", Category: StatusCategoryManual, IsTerminal: true, Description: "swap address is in blacklist"},
+	{Code: ManualMakeFail, Name: "ManualMakeFail", Category: StatusCategoryFailed, IsTerminal: true, Description: "swap is manually made failed"},
+	{Code: BindAddrIsContract, Name: "BindAddrIsContract", Category: StatusCategoryFailed, IsTerminal: true, Description: "bind address is a contract"},
+	{Code: Quarantined, Name: "Quarantined", Category: StatusCategoryManual, Description: "swap failed processing too many times and is quarantined, requeue after fixing"},
+	{Code: SwapExpired, Name: "SwapExpired", Category: StatusCategoryFailed, IsTerminal: true, Description: "swap is too old and expired by startup reconciliation"},
+	{Code: RegisteredUnstable, Name: "RegisteredUnstable", Category: StatusCategoryPending, Description: "deposit tx is registered after unstable verification and waiting for verification at stable depth"},
+	{Code: SwapValueIsDust, Name: "SwapValueIsDust", Category: StatusCategoryManual, Description: "swap value after fee is below dust threshold of the payout chain, held instead of building a tx the network rejects"},
+	{Code: SeenInMempool, Name: "SeenInMempool", Category: StatusCategoryPending, Description: "deposit tx is seen in mempool without confirmation, it is not verified or signed until mined"},
+	{Code: TxIsNotDeposit, Name: "TxIsNotDeposit", Category: StatusCategoryFailed, IsTerminal: true, Description: "deposit tx is not a deposit to the bridge (eg. wrong receiver, contract, input, log or status)"},
+	{Code: RiskRejected, Name: "RiskRejected", Category: StatusCategoryManual, Description: "swap is denied by risk hook before signing, admin passswap overrides it"},
+	{Code: Refunded, Name: "Refunded", Category: StatusCategoryFailed, IsTerminal: true, Description: "swap can never complete and deposit is refunded to sender"},
+	{Code: Reswapping, Name: "Reswapping", Category: StatusCategoryPending, Description: "swap is being reswapped"},
+}
+
+var (
+	statusByCode = make(map[SwapStatus]*SwapStatusInfo, len(statusRegistry))
+	statusByName = make(map[string]*SwapStatusInfo, len(statusRegistry))
+)
+
+func init() {
+	for _, info := range statusRegistry {
+		statusByCode[info.Code] = info
+		statusByName[strings.ToLower(info.Name)] = info
+	}
+}
+
+// GetStatusCatalog get all registered swap statuses
+func GetStatusCatalog() []*SwapStatusInfo {
+	verifyErrors := make(map[SwapStatus][]string)
+	for _, entry := range verifyErrorStatuses {
+		verifyErrors[entry.status] = append(verifyErrors[entry.status], entry.name)
+	}
+	result := make([]*SwapStatusInfo, len(statusRegistry))
+	for i, info := range statusRegistry {
+		item := *info
+		item.VerifyErrors = verifyErrors[info.Code]
+		result[i] = &item
+	}
+	return result
+}
+
+// ParseSwapStatus parse swap status from numeric code or name
+func ParseSwapStatus(str string) (SwapStatus, error) {
+	if num, err := common.GetUint64FromStr(str); err == nil {
+		if _, exist := statusByCode[SwapStatus(num)]; exist {
+			return SwapStatus(num), nil
+		}
+		return 0, fmt.Errorf("unknown swap status %v", str)
+	}
+	if info, exist := statusByName[strings.ToLower(str)]; exist {
+		return info.Code, nil
+	}
+	return 0, fmt.Errorf("unknown swap status %v", str)
+}
+
+// ParseSwapStatusList parse comma separated swap statuses, empty items are ignored
+func ParseSwapStatusList(str string) ([]SwapStatus, error) {
+	parts := strings.Split(str, ",")
+	result := make([]SwapStatus, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		status, err := ParseSwapStatus(part)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+// Info get status info from registry, return nil if unknown
+func (status SwapStatus) Info() *SwapStatusInfo {
+	return statusByCode[status]
+}
+
 // CanManualMakeFail can manual make fail
 func (status SwapStatus) CanManualMakeFail() bool {
-	return status != TxProcessed
+	return status != TxProcessed && status != Refunded
+}
+
+// CanRefund can refund deposit of swap which can never complete
+func (status SwapStatus) CanRefund() bool {
+	switch status {
+	case
+		TxWithWrongValue,
+		TxWithWrongMemo,
+		BindAddrIsContract,
+		SwapValueIsDust:
+		return true
+	default:
+		return false
+	}
 }
 
 // CanRetry can retry
 func (status SwapStatus) CanRetry() bool {
-	return status == TxSenderNotRegistered
+	info := status.Info()
+	return info != nil && info.canRetry
 }
 
 // CanReverify can reverify
@@ -74,11 +226,13 @@ func (status SwapStatus) CanReverify() bool {
 	switch status {
 	case
 		TxVerifyFailed,
+		TxIsNotDeposit,
 		TxWithWrongValue,
 		TxWithBigValue,
 		TxSenderNotRegistered,
 		SwapInBlacklist,
-		BindAddrIsContract:
+		BindAddrIsContract,
+		SwapValueIsDust:
 		return true
 	default:
 		return false
@@ -90,48 +244,9 @@ func (status SwapStatus) CanReswap() bool {
 	return status == TxProcessed
 }
 
-// nolint:gocyclo // allow big simple switch
 func (status SwapStatus) String() string {
-	switch status {
-	case TxNotStable:
-		return "TxNotStable"
-	case TxVerifyFailed:
-		return "TxVerifyFailed"
-	case TxWithWrongSender:
-		return "TxWithWrongSender"
-	case TxWithWrongValue:
-		return "TxWithWrongValue"
-	case TxIncompatible:
-		return "TxIncompatible"
-	case TxNotSwapped:
-		return "TxNotSwapped"
-	case TxSwapFailed:
-		return "TxSwapFailed"
-	case TxProcessed:
-		return "TxProcessed"
-	case MatchTxEmpty:
-		return "MatchTxEmpty"
-	case MatchTxNotStable:
-		return "MatchTxNotStable"
-	case MatchTxStable:
-		return "MatchTxStable"
-	case TxWithWrongMemo:
-		return "TxWithWrongMemo"
-	case TxWithBigValue:
-		return "TxWithBigValue"
-	case TxSenderNotRegistered:
-		return "TxSenderNotRegistered"
-	case MatchTxFailed:
-		return "MatchTxFailed"
-	case SwapInBlacklist:
-		return "SwapInBlacklist"
-	case ManualMakeFail:
-		return "ManualMakeFail"
-	case BindAddrIsContract:
-		return "BindAddrIsContract"
-	case Reswapping:
-		return "Reswapping"
-	default:
-		return fmt.Sprintf("unknown swap status %d", status)
+	if info := status.Info(); info != nil {
+		return info.Name
 	}
+	return fmt.Sprintf("unknown swap status %d", status)
 }
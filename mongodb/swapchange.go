@@ -0,0 +1,65 @@
+package mongodb
+
+import (
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+)
+
+// SwapChange change of swap or swap result written to database.
+// if 'HasStatus' is true, status, swaptx and memo are the written values,
+// swaptx and memo are empty if they are not written.
+type SwapChange struct {
+	IsSwapin  bool
+	Key       string
+	TxID      string
+	PairID    string
+	Bind      string
+	HasStatus bool
+	Status    SwapStatus
+	SwapTx    string
+	Memo      string
+	Timestamp int64 // unix milliseconds of the change
+}
+
+var swapChangeHandlers []func(change *SwapChange)
+
+// AddSwapChangeHandler add handler to observe changes of swaps and swap results,
+// handlers are called after each status changing write and must not block.
+// handlers should be added at initialization, before any write.
+func AddSwapChangeHandler(handler func(change *SwapChange)) {
+	swapChangeHandlers = append(swapChangeHandlers, handler)
+}
+
+// notifySwapChanged notify change which does not write status
+func notifySwapChanged(isSwapin bool, txid, pairID, bind string) {
+	notifySwapChange(newSwapChange(isSwapin, txid, pairID, bind))
+}
+
+// notifySwapStatusWritten notify change with the written status,
+// the status is unknown if writing failed.
+func notifySwapStatusWritten(err error, isSwapin bool, txid, pairID, bind string, status SwapStatus, swapTx, memo string) {
+	change := newSwapChange(isSwapin, txid, pairID, bind)
+	if err == nil {
+		change.HasStatus = true
+		change.Status, change.SwapTx, change.Memo = status, swapTx, memo
+	}
+	notifySwapChange(change)
+}
+
+func newSwapChange(isSwapin bool, txid, pairID, bind string) *SwapChange {
+	return &SwapChange{
+		IsSwapin:  isSwapin,
+		Key:       GetSwapKey(txid, pairID, bind),
+		TxID:      txid,
+		PairID:    strings.ToLower(pairID),
+		Bind:      bind,
+		Timestamp: common.NowMilli(),
+	}
+}
+
+func notifySwapChange(change *SwapChange) {
+	for _, handler := range swapChangeHandlers {
+		handler(change)
+	}
+}
@@ -0,0 +1,160 @@
+package mongodb
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/internal/metrics"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// swap events are persisted for external consumers to tail by sequence number,
+// they are written one by one in the order of changes, so a consumer querying
+// events after the last seen sequence number never skips a written event.
+// events are best effort, changes are dropped (and counted in metrics)
+// if the queue is full or writing keeps failing, so writes never block on it.
+const (
+	// SwapEventLifetime swap events are kept for this long
+	SwapEventLifetime = 7 * 24 * time.Hour
+
+	swapEventsCounter      = "swapevents"
+	swapEventQueueSize     = 1000
+	maxSwapEventWriteTries = 3
+)
+
+var (
+	swapEventQueue       = make(chan *SwapChange, swapEventQueueSize)
+	swapEventRetryPeriod = time.Second // replaced in tests
+
+	// swap event storage, replaced in tests
+	allocSwapEventSeq = func() (int64, error) { return nextCounterSeq(swapEventsCounter) }
+	insertSwapEvent   = func(event *MgoSwapEvent) error {
+		_, err := collSwapEvent.InsertOne(clientCtx, event)
+		return mgoError(err)
+	}
+	findSwapEventState = findSwapState
+)
+
+// EnableSwapEvents persist changes of swaps and swap results as swap events
+func EnableSwapEvents() {
+	AddSwapChangeHandler(enqueueSwapChange)
+	go writeSwapEvents()
+	log.Info("mongodb swap events enabled")
+}
+
+// enqueueSwapChange never blocks the writer, change is dropped if the queue is full
+func enqueueSwapChange(change *SwapChange) {
+	select {
+	case swapEventQueue <- change:
+	default:
+		metrics.SwapEventsDropped.Inc("queuefull")
+		log.Warn("mongodb swap event queue is full, drop event", "swapkey", change.Key, "isSwapin", change.IsSwapin)
+	}
+}
+
+func writeSwapEvents() {
+	for change := range swapEventQueue {
+		persistSwapEvent(change)
+	}
+}
+
+// persistSwapEvent retry a few times, then drop the event
+func persistSwapEvent(change *SwapChange) {
+	event := newSwapEvent(change)
+	var err error
+	for i := 0; i < maxSwapEventWriteTries; i++ {
+		if i > 0 {
+			time.Sleep(swapEventRetryPeriod)
+		}
+		if err = writeSwapEvent(event); err == nil {
+			return
+		}
+	}
+	metrics.SwapEventsDropped.Inc("writefailed")
+	log.Warn("mongodb write swap event failed, drop event", "swapkey", event.SwapKey, "isSwapin", event.IsSwapin, "seq", event.Seq, "err", err)
+}
+
+// writeSwapEvent allocate sequence number once, retrying keeps the same number
+func writeSwapEvent(event *MgoSwapEvent) (err error) {
+	if event.Seq == 0 {
+		event.Seq, err = allocSwapEventSeq()
+		if err != nil {
+			return err
+		}
+	}
+	err = insertSwapEvent(event)
+	if errors.Is(err, ErrItemIsDup) {
+		return nil // inserted by a failed attempt which reported error
+	}
+	return err
+}
+
+// newSwapEvent use the state captured at change,
+// and read it only for changes which do not write status.
+func newSwapEvent(change *SwapChange) *MgoSwapEvent {
+	event := &MgoSwapEvent{
+		IsSwapin:   change.IsSwapin,
+		SwapKey:    change.Key,
+		PairID:     change.PairID,
+		TxID:       change.TxID,
+		Bind:       change.Bind,
+		Status:     change.Status,
+		SwapTx:     change.SwapTx,
+		Memo:       change.Memo,
+		Timestamp:  change.Timestamp,
+		CreateTime: time.Now(),
+	}
+	if change.HasStatus {
+		return event
+	}
+	if res, swap := findSwapEventState(change.IsSwapin, change.Key); res != nil {
+		event.Status, event.SwapTx, event.Memo = res.Status, res.SwapTx, res.Memo
+	} else if swap != nil {
+		event.Status, event.Memo = swap.Status, swap.Memo
+	}
+	return event
+}
+
+// findSwapState find swap result, or swap if it has no result yet
+func findSwapState(isSwapin bool, key string) (*MgoSwapResult, *MgoSwap) {
+	var res MgoSwapResult
+	err := getSwapOrResultCollection(isSwapin, true).FindOne(clientCtx, bson.M{"_id": key}).Decode(&res)
+	if err == nil {
+		return &res, nil
+	}
+	var swap MgoSwap
+	err = getSwapOrResultCollection(isSwapin, false).FindOne(clientCtx, bson.M{"_id": key}).Decode(&swap)
+	if err == nil {
+		return nil, &swap
+	}
+	return nil, nil
+}
+
+// nextCounterSeq increase counter atomically with findAndModify, first sequence is 1
+func nextCounterSeq(name string) (int64, error) {
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+	var counter MgoCounter
+	err := collCounter.FindOneAndUpdate(clientCtx, bson.M{"_id": name}, bson.M{"$inc": bson.M{"seq": int64(1)}}, opts).Decode(&counter)
+	if err != nil {
+		return 0, mgoError(err)
+	}
+	return counter.Seq, nil
+}
+
+// FindSwapEvents find swap events with sequence number greater than sinceSeq
+func FindSwapEvents(sinceSeq int64, limit int) ([]*MgoSwapEvent, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+	cur, err := collSwapEvent.Find(clientCtx, bson.M{"_id": bson.M{"$gt": sinceSeq}}, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapEvent, 0, limit)
+	err = cur.All(clientCtx, &result)
+	return result, mgoError(err)
+}
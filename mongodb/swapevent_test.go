@@ -0,0 +1,182 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/internal/metrics"
+)
+
+// memSwapEvents in memory swap events, sequence is allocated like findAndModify
+type memSwapEvents struct {
+	lock      sync.Mutex
+	counter   int64
+	events    []*MgoSwapEvent
+	failFirst bool
+}
+
+func (m *memSwapEvents) allocSeq() (int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.counter++
+	return m.counter, nil
+}
+
+func (m *memSwapEvents) insert(event *MgoSwapEvent) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.failFirst {
+		m.failFirst = false
+		return errors.New("connection reset")
+	}
+	for _, exist := range m.events {
+		if exist.Seq == event.Seq {
+			return ErrItemIsDup
+		}
+	}
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *memSwapEvents) count() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.events)
+}
+
+func TestSwapEventsConcurrentWriters(t *testing.T) {
+	mem := &memSwapEvents{failFirst: true}
+	oldAlloc, oldInsert, oldFind := allocSwapEventSeq, insertSwapEvent, findSwapEventState
+	defer func() { allocSwapEventSeq, insertSwapEvent, findSwapEventState = oldAlloc, oldInsert, oldFind }()
+	allocSwapEventSeq, insertSwapEvent = mem.allocSeq, mem.insert
+	findSwapEventState = func(isSwapin bool, key string) (*MgoSwapResult, *MgoSwap) {
+		return &MgoSwapResult{Key: key, Status: MatchTxNotStable}, nil
+	}
+	oldHandlers := swapChangeHandlers
+	defer func() { swapChangeHandlers = oldHandlers }()
+	swapChangeHandlers = nil
+	EnableSwapEvents()
+
+	const updates = 100
+	var wg sync.WaitGroup
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			txid := fmt.Sprintf("0xtx%v", i)
+			if i%2 == 0 {
+				notifySwapChanged(true, txid, "pair", "bind")
+			} else {
+				notifySwapStatusWritten(nil, false, txid, "pair", "bind", MatchTxNotStable, "0xswaptx", "")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for mem.count() < updates {
+		if time.Now().After(deadline) {
+			t.Fatalf("want %v events, have %v", updates, mem.count())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	keys := make(map[string]bool)
+	for i, event := range mem.events {
+		// no gaps or duplicates, inserted in sequence order
+		if event.Seq != int64(i+1) {
+			t.Fatalf("event %v has sequence %v", i, event.Seq)
+		}
+		if keys[event.SwapKey] {
+			t.Errorf("duplicate event of %v", event.SwapKey)
+		}
+		keys[event.SwapKey] = true
+		if event.Status != MatchTxNotStable || event.Timestamp == 0 || event.TxID == "" {
+			t.Errorf("wrong event %+v", event)
+		}
+		if !event.IsSwapin && event.SwapTx != "0xswaptx" {
+			t.Errorf("event should use the written state, have %+v", event)
+		}
+	}
+}
+
+func TestSwapEventCapturedState(t *testing.T) {
+	oldFind := findSwapEventState
+	defer func() { findSwapEventState = oldFind }()
+	findSwapEventState = func(isSwapin bool, key string) (*MgoSwapResult, *MgoSwap) {
+		return &MgoSwapResult{Key: key, Status: MatchTxStable, SwapTx: "0xnewer", Memo: "newer"}, nil
+	}
+
+	// written state is kept even if the record is changed later
+	change := newSwapChange(true, "0xTX", "PAIR", "bind")
+	change.HasStatus, change.Status, change.Memo = true, TxSwapFailed, "swap failed"
+	event := newSwapEvent(change)
+	if event.Status != TxSwapFailed || event.Memo != "swap failed" || event.SwapTx != "" {
+		t.Errorf("event should use the written state, have %+v", event)
+	}
+	if event.TxID != "0xTX" || event.PairID != "pair" || event.SwapKey != GetSwapKey("0xTX", "pair", "bind") {
+		t.Errorf("wrong event key %+v", event)
+	}
+
+	// failed write has no known state
+	notifyErr := errors.New("write failed")
+	var notified *SwapChange
+	oldHandlers := swapChangeHandlers
+	defer func() { swapChangeHandlers = oldHandlers }()
+	swapChangeHandlers = []func(*SwapChange){func(change *SwapChange) { notified = change }}
+	notifySwapStatusWritten(notifyErr, true, "0xtx", "pair", "bind", TxSwapFailed, "", "")
+	if notified == nil || notified.HasStatus {
+		t.Fatalf("failed write should not carry status, have %+v", notified)
+	}
+	if event = newSwapEvent(notified); event.Status != MatchTxStable || event.SwapTx != "0xnewer" {
+		t.Errorf("event without status should read the record, have %+v", event)
+	}
+}
+
+func TestSwapEventDropped(t *testing.T) {
+	metrics.Enable()
+	oldQueue, oldAlloc, oldInsert, oldPeriod := swapEventQueue, allocSwapEventSeq, insertSwapEvent, swapEventRetryPeriod
+	defer func() {
+		swapEventQueue, allocSwapEventSeq, insertSwapEvent, swapEventRetryPeriod = oldQueue, oldAlloc, oldInsert, oldPeriod
+	}()
+
+	// full queue does not block
+	swapEventQueue = make(chan *SwapChange, 1)
+	dropped := metrics.SwapEventsDropped.Get("queuefull")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		enqueueSwapChange(newSwapChange(true, "0xtx1", "pair", "bind"))
+		enqueueSwapChange(newSwapChange(true, "0xtx2", "pair", "bind"))
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("enqueue blocked on full queue")
+	}
+	if have := metrics.SwapEventsDropped.Get("queuefull") - dropped; have != 1 {
+		t.Errorf("want 1 dropped event, have %v", have)
+	}
+
+	// writing gives up after limited tries
+	swapEventRetryPeriod = time.Millisecond
+	allocSwapEventSeq = (&memSwapEvents{}).allocSeq
+	var tries int
+	insertSwapEvent = func(*MgoSwapEvent) error {
+		tries++
+		return errors.New("connection reset")
+	}
+	dropped = metrics.SwapEventsDropped.Get("writefailed")
+	change := newSwapChange(true, "0xtx", "pair", "bind")
+	change.HasStatus = true
+	persistSwapEvent(change)
+	if tries != maxSwapEventWriteTries {
+		t.Errorf("want %v tries, have %v", maxSwapEventWriteTries, tries)
+	}
+	if have := metrics.SwapEventsDropped.Get("writefailed") - dropped; have != 1 {
+		t.Errorf("want 1 dropped event, have %v", have)
+	}
+}
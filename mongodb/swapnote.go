@@ -0,0 +1,84 @@
+package mongodb
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// swap note limits
+const (
+	MaxSwapNotesPerSwap = 50
+	MaxSwapNoteLength   = 1024
+)
+
+var swapNoteLock sync.Mutex
+
+// checkSwapNote check note content is acceptable
+func checkSwapNote(note string) error {
+	switch {
+	case strings.TrimSpace(note) == "":
+		return ErrEmptySwapNote
+	case len(note) > MaxSwapNoteLength:
+		return ErrSwapNoteTooLong
+	default:
+		return nil
+	}
+}
+
+// AddSwapNote append operator note to swap (notes are never updated or deleted)
+func AddSwapNote(isSwapin bool, txid, pairID, bind, operator, note string) error {
+	if err := checkSwapNote(note); err != nil {
+		return err
+	}
+	if _, err := FindSwap(isSwapin, txid, pairID, bind); err != nil {
+		return err
+	}
+
+	swapNoteLock.Lock()
+	defer swapNoteLock.Unlock()
+
+	swapKey := GetSwapKey(txid, pairID, bind)
+	count, err := collSwapNote.CountDocuments(clientCtx, bson.M{"swapkey": swapKey, "isswapin": isSwapin})
+	if err != nil {
+		return mgoError(err)
+	}
+	if count >= MaxSwapNotesPerSwap {
+		return ErrTooManySwapNotes
+	}
+	item := &MgoSwapNote{
+		Key:       newObjectID(),
+		SwapKey:   swapKey,
+		IsSwapin:  isSwapin,
+		TxID:      strings.ToLower(txid),
+		PairID:    strings.ToLower(pairID),
+		Bind:      bind,
+		Operator:  operator,
+		Note:      note,
+		Timestamp: time.Now().Unix(),
+	}
+	_, err = collSwapNote.InsertOne(clientCtx, item)
+	if err == nil {
+		log.Info("mongodb add swap note success", "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin, "operator", operator)
+	} else {
+		log.Error("mongodb add swap note failed", "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin, "operator", operator, "err", err)
+	}
+	return mgoError(err)
+}
+
+// GetSwapNotes get notes of swap in adding order
+func GetSwapNotes(isSwapin bool, txid, pairID, bind string) ([]*MgoSwapNote, error) {
+	swapKey := GetSwapKey(txid, pairID, bind)
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cur, err := collSwapNote.Find(clientCtx, bson.M{"swapkey": swapKey, "isswapin": isSwapin}, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoSwapNote, 0, MaxSwapNotesPerSwap)
+	err = cur.All(clientCtx, &result)
+	return result, mgoError(err)
+}
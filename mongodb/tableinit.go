@@ -1,24 +1,39 @@
 package mongodb
 
 import (
+	"errors"
+	"time"
+
 	"github.com/anyswap/CrossChain-Bridge/log"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
-	tbSwapins           string = "Swapins"
-	tbSwapouts          string = "Swapouts"
-	tbSwapinResults     string = "SwapinResults"
-	tbSwapoutResults    string = "SwapoutResults"
-	tbP2shAddresses     string = "P2shAddresses"
-	tbLatestScanInfo    string = "LatestScanInfo"
-	tbRegisteredAddress string = "RegisteredAddress"
-	tbBlacklist         string = "Blacklist"
-	tbLatestSwapNonces  string = "LatestSwapNonces"
-	tbSwapHistory       string = "SwapHistory"
-	tbUsedRValues       string = "UsedRValues"
+	tbSwapins             string = "Swapins"
+	tbSwapouts            string = "Swapouts"
+	tbSwapinResults       string = "SwapinResults"
+	tbSwapoutResults      string = "SwapoutResults"
+	tbP2shAddresses       string = "P2shAddresses"
+	tbLatestScanInfo      string = "LatestScanInfo"
+	tbRegisteredAddress   string = "RegisteredAddress"
+	tbBlacklist           string = "Blacklist"
+	tbLatestSwapNonces    string = "LatestSwapNonces"
+	tbSwapHistory         string = "SwapHistory"
+	tbUsedRValues         string = "UsedRValues"
+	tbSwapNotes           string = "SwapNotes"
+	tbIdempotencyKeys     string = "IdempotencyKeys"
+	tbFullMemos           string = "FullMemos"
+	tbRefunds             string = "Refunds"
+	tbDailyReports        string = "DailyReports"
+	tbAcceptProcessed     string = "AcceptProcessed"
+	tbSwapEvents          string = "SwapEvents"
+	tbCounters            string = "Counters"
+	tbAdminAudits         string = "AdminAudits"
+	tbSignDisagreements   string = "SignDisagreements"
+	tbUnsupportedDeposits string = "UnsupportedDeposits"
 
 	keyOfSrcLatestScanInfo string = "srclatest"
 	keyOfDstLatestScanInfo string = "dstlatest"
@@ -27,17 +42,28 @@ const (
 var (
 	database *mongo.Database
 
-	collSwapin            *mongo.Collection
-	collSwapout           *mongo.Collection
-	collSwapinResult      *mongo.Collection
-	collSwapoutResult     *mongo.Collection
-	collP2shAddress       *mongo.Collection
-	collLatestScanInfo    *mongo.Collection
-	collRegisteredAddress *mongo.Collection
-	collBlacklist         *mongo.Collection
-	collLatestSwapNonces  *mongo.Collection
-	collSwapHistory       *mongo.Collection
-	collUsedRValue        *mongo.Collection
+	collSwapin             *mongo.Collection
+	collSwapout            *mongo.Collection
+	collSwapinResult       *mongo.Collection
+	collSwapoutResult      *mongo.Collection
+	collP2shAddress        *mongo.Collection
+	collLatestScanInfo     *mongo.Collection
+	collRegisteredAddress  *mongo.Collection
+	collBlacklist          *mongo.Collection
+	collLatestSwapNonces   *mongo.Collection
+	collSwapHistory        *mongo.Collection
+	collUsedRValue         *mongo.Collection
+	collSwapNote           *mongo.Collection
+	collIdempotencyKey     *mongo.Collection
+	collFullMemo           *mongo.Collection
+	collRefund             *mongo.Collection
+	collDailyReport        *mongo.Collection
+	collAcceptProcessed    *mongo.Collection
+	collSwapEvent          *mongo.Collection
+	collCounter            *mongo.Collection
+	collAdminAudit         *mongo.Collection
+	collSignDisagreement   *mongo.Collection
+	collUnsupportedDeposit *mongo.Collection
 )
 
 func isSwapin(collection *mongo.Collection) bool {
@@ -49,15 +75,45 @@ func initCollections() {
 
 	initCollection(tbSwapins, &collSwapin, "inittime", "status")
 	initCollection(tbSwapouts, &collSwapout, "inittime", "status")
+	createOneIndex(collSwapin, "txid", "pairid")
+	createOneIndex(collSwapout, "txid", "pairid")
 	initCollection(tbSwapinResults, &collSwapinResult, "inittime", "status")
 	initCollection(tbSwapoutResults, &collSwapoutResult, "inittime", "status")
+	createOneIndex(collSwapinResult, "txid", "pairid")
+	createOneIndex(collSwapoutResult, "txid", "pairid")
+	createOneIndex(collSwapinResult, "signattempts.initiator")
+	createOneIndex(collSwapoutResult, "signattempts.initiator")
+	createOneIndex(collSwapinResult, "pairid", "timestamp")
+	createOneIndex(collSwapoutResult, "pairid", "timestamp")
+	createOneIndex(collSwapinResult, "pairid", "inittime", "_id")
+	createOneIndex(collSwapoutResult, "pairid", "inittime", "_id")
+	createOneIndex(collSwapinResult, "pairid", "bind")
+	createOneIndex(collSwapoutResult, "pairid", "bind")
+	createOneIndex(collSwapinResult, "from", "inittime")
+	createOneIndex(collSwapoutResult, "from", "inittime")
 	initCollection(tbP2shAddresses, &collP2shAddress, "p2shaddress")
+	createOneIndex(collP2shAddress, "inactive", "timestamp")
+	createOneIndex(collP2shAddress, "timestamp")
 	initCollection(tbLatestScanInfo, &collLatestScanInfo)
-	initCollection(tbRegisteredAddress, &collRegisteredAddress)
+	initCollection(tbRegisteredAddress, &collRegisteredAddress, "timestamp")
 	initCollection(tbBlacklist, &collBlacklist)
 	initCollection(tbLatestSwapNonces, &collLatestSwapNonces, "address")
 	initCollection(tbSwapHistory, &collSwapHistory, "txid")
 	initCollection(tbUsedRValues, &collUsedRValue)
+	initCollection(tbSwapNotes, &collSwapNote, "swapkey", "isswapin")
+	initCollection(tbIdempotencyKeys, &collIdempotencyKey)
+	createTTLIndex(collIdempotencyKey, "createtime", IdempotencyKeyLifetime)
+	createCappedCollection(tbFullMemos, fullMemosCappedSize)
+	initCollection(tbFullMemos, &collFullMemo)
+	initCollection(tbRefunds, &collRefund, "status")
+	initCollection(tbDailyReports, &collDailyReport)
+	initCollection(tbAcceptProcessed, &collAcceptProcessed, "timestamp")
+	initCollection(tbSwapEvents, &collSwapEvent)
+	createTTLIndex(collSwapEvent, "createtime", SwapEventLifetime)
+	initCollection(tbCounters, &collCounter)
+	initCollection(tbAdminAudits, &collAdminAudit, "swapkey", "isswapin")
+	initCollection(tbSignDisagreements, &collSignDisagreement, "timestamp")
+	initCollection(tbUnsupportedDeposits, &collUnsupportedDeposit, "blockheight")
 }
 
 func initCollection(table string, collection **mongo.Collection, indexKey ...string) {
@@ -78,3 +134,27 @@ func createOneIndex(coll *mongo.Collection, indexes ...string) {
 		log.Error("[mongodb] create indexes failed", "collection", coll.Name(), "indexes", indexes, "err", err)
 	}
 }
+
+func createCappedCollection(table string, size int64) {
+	opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(size)
+	err := database.CreateCollection(clientCtx, table, opts)
+	if err != nil && !isNamespaceExistsError(err) {
+		log.Error("[mongodb] create capped collection failed", "collection", table, "err", err)
+	}
+}
+
+func isNamespaceExistsError(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && cmdErr.Code == 48 // NamespaceExists
+}
+
+func createTTLIndex(coll *mongo.Collection, index string, lifetime time.Duration) {
+	model := mongo.IndexModel{
+		Keys:    bson.D{{Key: index, Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(lifetime.Seconds())),
+	}
+	_, err := coll.Indexes().CreateOne(clientCtx, model)
+	if err != nil {
+		log.Error("[mongodb] create ttl index failed", "collection", coll.Name(), "index", index, "err", err)
+	}
+}
@@ -1,6 +1,8 @@
 package mongodb
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -17,6 +19,18 @@ type MgoSwap struct {
 	InitTime  int64      `bson:"inittime"`
 	Timestamp int64      `bson:"timestamp"`
 	Memo      string     `bson:"memo"`
+
+	FailCount  int        `bson:"failcount,omitempty"`
+	FailStage  string     `bson:"failstage,omitempty"`
+	LastError  string     `bson:"lasterror,omitempty"`
+	PrevStatus SwapStatus `bson:"prevstatus,omitempty"`
+
+	// set only by verify job after verifying at stable depth, required before signing
+	StableVerified bool `bson:"stableverified,omitempty"`
+	// set by admin passing swap denied by risk hook, it's signed without asking again
+	RiskOverridden bool `bson:"riskoverridden,omitempty"`
+
+	RefundTx string `bson:"refundtx,omitempty"`
 }
 
 // MgoSwapResult swap result (verified swap)
@@ -43,6 +57,52 @@ type MgoSwapResult struct {
 	InitTime    int64      `bson:"inittime"`
 	Timestamp   int64      `bson:"timestamp"`
 	Memo        string     `bson:"memo"`
+
+	FailCount  int        `bson:"failcount,omitempty"`
+	FailStage  string     `bson:"failstage,omitempty"`
+	LastError  string     `bson:"lasterror,omitempty"`
+	PrevStatus SwapStatus `bson:"prevstatus,omitempty"`
+
+	SignAttempts []*MgoSignAttempt `bson:"signattempts,omitempty"`
+
+	// block hash of verified tx, used to check freshness before signing
+	TxBlockHash string `bson:"txblockhash,omitempty"`
+
+	// split payout, 'SwapTx' is the first leg, 'PayoutLegs' are the others
+	PayoutLegCount int             `bson:"payoutlegcount,omitempty"`
+	PayoutLegs     []*MgoPayoutLeg `bson:"payoutlegs,omitempty"`
+}
+
+// MgoPayoutLeg payout leg (except the first) of split payout
+type MgoPayoutLeg struct {
+	Index      int      `bson:"index" json:"index"`
+	SwapTx     string   `bson:"swaptx" json:"swaptx"`
+	OldSwapTxs []string `bson:"oldswaptxs,omitempty" json:"oldswaptxs,omitempty"`
+	SwapValue  string   `bson:"swapvalue" json:"swapvalue"`
+	SwapNonce  uint64   `bson:"swapnonce" json:"swapnonce"`
+	SwapHeight uint64   `bson:"swapheight" json:"swapheight"`
+	Stable     bool     `bson:"stable,omitempty" json:"stable,omitempty"`
+	Timestamp  int64    `bson:"timestamp" json:"timestamp"`
+}
+
+// GetPayoutLeg get payout leg of index, nil if not exist
+func (r *MgoSwapResult) GetPayoutLeg(index int) *MgoPayoutLeg {
+	for _, leg := range r.PayoutLegs {
+		if leg.Index == index {
+			return leg
+		}
+	}
+	return nil
+}
+
+// MgoSignAttempt dcrm sign attempt of swap result
+type MgoSignAttempt struct {
+	KeyID       string `bson:"keyid" json:"keyid"`
+	Initiator   string `bson:"initiator,omitempty" json:"initiator,omitempty"` // lower case dcrm account
+	InitiatedAt int64  `bson:"initiatedat" json:"initiatedat"`
+	FinishedAt  int64  `bson:"finishedat" json:"finishedat"`
+	DcrmStatus  string `bson:"dcrmstatus" json:"dcrmstatus"`
+	RsvReceived bool   `bson:"rsvreceived" json:"rsvreceived"`
 }
 
 // SwapResultUpdateItems swap update items
@@ -56,19 +116,31 @@ type SwapResultUpdateItems struct {
 	Status     SwapStatus
 	Timestamp  int64
 	Memo       string
+
+	PayoutLegCount int
 }
 
 // MgoP2shAddress key is the bind address
 type MgoP2shAddress struct {
 	Key         string `bson:"_id"`
 	P2shAddress string `bson:"p2shaddress"`
-	Timestamp   int64  `bson:"timestamp"`
+	Timestamp   int64  `bson:"timestamp"` // registration time
+	LastDeposit int64  `bson:"lastdeposit,omitempty"`
+	ActiveTime  int64  `bson:"activetime,omitempty"` // last reactivation time
+	Inactive    bool   `bson:"inactive,omitempty"`
+
+	FailedDeposits []string `bson:"faileddeposits,omitempty"` // utxos (txid:vout) which registered no swapin when reactivated
 }
 
 // MgoRegisteredAddress key is address (in whitelist)
 type MgoRegisteredAddress struct {
-	Key       string `bson:"_id"`
-	Timestamp int64  `bson:"timestamp"`
+	Key         string `bson:"_id"`
+	Timestamp   int64  `bson:"timestamp"`
+	Source      string `bson:"source,omitempty"` // api (default) or chain
+	BindTo      string `bson:"bindto,omitempty"`
+	Registry    string `bson:"registry,omitempty"`
+	TxHash      string `bson:"txhash,omitempty"`
+	BlockHeight uint64 `bson:"blockheight,omitempty"`
 }
 
 // MgoLatestScanInfo latest scan info
@@ -110,6 +182,164 @@ type MgoUsedRValue struct {
 	Timestamp int64  `bson:"timestamp"`
 }
 
+// MgoFullMemo full memo of swap whose memo is truncated
+type MgoFullMemo struct {
+	Key        primitive.ObjectID `bson:"_id"`
+	Memo       string             `bson:"memo"`
+	CreateTime int64              `bson:"createtime"`
+}
+
+// MgoSwapNote operator note attached to swap
+type MgoSwapNote struct {
+	Key       primitive.ObjectID `bson:"_id"`
+	SwapKey   string             `bson:"swapkey"`
+	IsSwapin  bool               `bson:"isswapin"`
+	TxID      string             `bson:"txid"`
+	PairID    string             `bson:"pairid"`
+	Bind      string             `bson:"bind"`
+	Operator  string             `bson:"operator"`
+	Note      string             `bson:"note"`
+	Timestamp int64              `bson:"timestamp"`
+}
+
+// MgoAdminAudit audit entry of admin operation on swap status,
+// it is added before the status is changed.
+type MgoAdminAudit struct {
+	Key       primitive.ObjectID `bson:"_id"`
+	SwapKey   string             `bson:"swapkey"`
+	IsSwapin  bool               `bson:"isswapin"`
+	TxID      string             `bson:"txid"`
+	PairID    string             `bson:"pairid"`
+	Bind      string             `bson:"bind"`
+	Operator  string             `bson:"operator"`
+	Operation string             `bson:"operation"`
+	OldStatus SwapStatus         `bson:"oldstatus"`
+	NewStatus SwapStatus         `bson:"newstatus"`
+	Reason    string             `bson:"reason"`
+	Timestamp int64              `bson:"timestamp"`
+}
+
+// MgoIdempotentResponse response snapshot of idempotent request,
+// key is method and client generated idempotency key
+type MgoIdempotentResponse struct {
+	Key            string    `bson:"_id"`
+	Method         string    `bson:"method"`
+	IdempotencyKey string    `bson:"idempotencykey"`
+	Request        string    `bson:"request"`
+	Response       string    `bson:"response"`
+	Pending        bool      `bson:"pending"` // reserved and the api is being called
+	CreateTime     time.Time `bson:"createtime"`
+}
+
+// MgoAcceptProcessed processed accept sign info of oracle, key is sign keyID
+type MgoAcceptProcessed struct {
+	Key       string `bson:"_id"`
+	Timestamp int64  `bson:"timestamp"`
+}
+
+// MgoSignDisagreement agree/disagree split of dcrm sign group, key is sign keyID.
+// enode IDs of agreed and disagreed nodes are merged from initiator and acceptors.
+type MgoSignDisagreement struct {
+	Key       string               `bson:"_id" json:"keyID"`
+	IsSwapin  bool                 `bson:"isswapin" json:"isSwapin"`
+	TxID      string               `bson:"txid" json:"txid"`
+	PairID    string               `bson:"pairid" json:"pairid"`
+	Bind      string               `bson:"bind" json:"bind"`
+	Initiator string               `bson:"initiator" json:"initiator"` // lower case dcrm account
+	Check     string               `bson:"check" json:"check"`         // differing check, eg. value, gas, confirmations
+	Agreed    []string             `bson:"agreed" json:"agreed"`
+	Disagreed []string             `bson:"disagreed" json:"disagreed"`
+	Reasons   []*MgoDisagreeReason `bson:"reasons" json:"reasons,omitempty"`
+	Timestamp int64                `bson:"timestamp" json:"timestamp"`
+}
+
+// MgoDisagreeReason disagree reason of node
+type MgoDisagreeReason struct {
+	Node   string `bson:"node" json:"node"` // enode ID
+	Reason string `bson:"reason" json:"reason"`
+}
+
+// MgoUnsupportedDeposit transfer of token which is not configured to deposit address,
+// key is txid and log index. value is in smallest unit of the token.
+type MgoUnsupportedDeposit struct {
+	Key         string `bson:"_id" json:"key"`
+	TxID        string `bson:"txid" json:"txid"`
+	LogIndex    uint   `bson:"logindex" json:"logIndex"`
+	Token       string `bson:"token" json:"token"` // token contract address
+	From        string `bson:"from" json:"from"`
+	To          string `bson:"to" json:"to"` // deposit address
+	Value       string `bson:"value" json:"value"`
+	BlockHeight uint64 `bson:"blockheight" json:"blockHeight"`
+	Timestamp   int64  `bson:"timestamp" json:"timestamp"` // found time
+}
+
+// MgoSwapEvent swap change event, key is sequence number allocated from counter.
+// status, swaptx and memo are the values written by the change.
+type MgoSwapEvent struct {
+	Seq        int64      `bson:"_id" json:"seq"`
+	IsSwapin   bool       `bson:"isswapin" json:"isSwapin"`
+	SwapKey    string     `bson:"swapkey" json:"swapKey"` // txid + pairid + bind
+	PairID     string     `bson:"pairid" json:"pairid"`
+	TxID       string     `bson:"txid" json:"txid"`
+	Bind       string     `bson:"bind" json:"bind"`
+	Status     SwapStatus `bson:"status" json:"status"`
+	SwapTx     string     `bson:"swaptx,omitempty" json:"swaptx,omitempty"`
+	Memo       string     `bson:"memo,omitempty" json:"memo,omitempty"`
+	Timestamp  int64      `bson:"timestamp" json:"timestamp"` // unix milliseconds of the change
+	CreateTime time.Time  `bson:"createtime" json:"-"`
+}
+
+// MgoCounter sequence counter, key is counter name
+type MgoCounter struct {
+	Key string `bson:"_id"`
+	Seq int64  `bson:"seq"`
+}
+
+// MgoRefund refund of swap which can never complete, key is same as the swap
+type MgoRefund struct {
+	Key          string       `bson:"_id"` // txid + pairid + bind
+	PairID       string       `bson:"pairid"`
+	TxID         string       `bson:"txid"`
+	Bind         string       `bson:"bind"`
+	IsSwapin     bool         `bson:"isswapin"`
+	SwapStatus   SwapStatus   `bson:"swapstatus"` // swap status before refund
+	To           string       `bson:"to"`
+	Value        string       `bson:"value"`
+	RefundTx     string       `bson:"refundtx"`
+	RefundNonce  uint64       `bson:"refundnonce"`
+	RefundHeight uint64       `bson:"refundheight"`
+	RefundTime   uint64       `bson:"refundtime"`
+	Status       RefundStatus `bson:"status"`
+	Initiator    string       `bson:"initiator"`
+	InitTime     int64        `bson:"inittime"`
+	Timestamp    int64        `bson:"timestamp"`
+}
+
+// MgoDailyReport daily summary report, key is date (2006-01-02)
+type MgoDailyReport struct {
+	Key             string              `bson:"_id"`
+	TimeZone        string              `bson:"timezone"`
+	StartTime       int64               `bson:"starttime"`
+	EndTime         int64               `bson:"endtime"`
+	GenerateTime    int64               `bson:"generatetime"`
+	Pairs           []*MgoDailyPairStat `bson:"pairs"`
+	SwapinBacklogs  map[string]int64    `bson:"swapinbacklogs"`  // pending status name -> count at generate time
+	SwapoutBacklogs map[string]int64    `bson:"swapoutbacklogs"` // pending status name -> count at generate time
+	Text            string              `bson:"text"`
+}
+
+// MgoDailyPairStat daily swap statistics of pair in one direction
+type MgoDailyPairStat struct {
+	PairID     string `bson:"pairid"`
+	IsSwapin   bool   `bson:"isswapin"`
+	Completed  int    `bson:"completed"`
+	Failed     int    `bson:"failed"`
+	Held       int    `bson:"held"`       // swaps held for manual handling, eg. big value, blacklist, quarantine
+	Volume     string `bson:"volume"`     // sum of completed deposit values
+	Fee        string `bson:"fee"`        // sum of completed swap fees, in deposit token unit
+	AvgLatency int64  `bson:"avglatency"` // average seconds from deposit tx to swap tx of completed swaps
+}
+
 func newObjectID() primitive.ObjectID {
 	return primitive.NewObjectID()
 }
@@ -0,0 +1,68 @@
+package mongodb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const keyOfUnsupportedDepositScanInfo = "unsupporteddeposit"
+
+// GetUnsupportedDepositKey get key of unsupported deposit
+func GetUnsupportedDepositKey(txid string, logIndex uint) string {
+	return fmt.Sprintf("%v:%v", txid, logIndex)
+}
+
+// AddUnsupportedDeposit add unsupported deposit, returns ErrItemIsDup if exist
+func AddUnsupportedDeposit(item *MgoUnsupportedDeposit) error {
+	item.Key = GetUnsupportedDepositKey(item.TxID, item.LogIndex)
+	if item.Timestamp == 0 {
+		item.Timestamp = time.Now().Unix()
+	}
+	_, err := collUnsupportedDeposit.InsertOne(clientCtx, item)
+	if err == nil {
+		log.Info("mongodb add unsupported deposit", "key", item.Key, "token", item.Token, "from", item.From, "value", item.Value)
+	}
+	return mgoError(err)
+}
+
+// ListUnsupportedDeposits list unsupported deposits in block height order
+func ListUnsupportedDeposits(offset, limit int) ([]*MgoUnsupportedDeposit, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "blockheight", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cur, err := collUnsupportedDeposit.Find(clientCtx, bson.M{}, opts)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoUnsupportedDeposit, 0, limit)
+	err = cur.All(clientCtx, &result)
+	return result, mgoError(err)
+}
+
+// UpdateUnsupportedDepositScanHeight update scanned block height of unsupported deposits
+func UpdateUnsupportedDepositScanHeight(blockHeight uint64) error {
+	updates := bson.M{
+		"blockheight": blockHeight,
+		"timestamp":   time.Now().Unix(),
+	}
+	_, err := collLatestScanInfo.UpdateByID(clientCtx, keyOfUnsupportedDepositScanInfo, bson.M{"$set": updates}, options.Update().SetUpsert(true))
+	if err != nil {
+		log.Error("mongodb update unsupported deposit scan height", "updates", updates, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindUnsupportedDepositScanHeight find scanned block height of unsupported deposits
+func FindUnsupportedDepositScanHeight() (uint64, error) {
+	var result MgoLatestScanInfo
+	err := collLatestScanInfo.FindOne(clientCtx, bson.M{"_id": keyOfUnsupportedDepositScanInfo}).Decode(&result)
+	if err != nil {
+		return 0, mgoError(err)
+	}
+	return result.BlockHeight, nil
+}
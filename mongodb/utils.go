@@ -1,28 +1,102 @@
 package mongodb
 
 import (
+	"context"
 	"errors"
 
+	"github.com/anyswap/CrossChain-Bridge/internal/retry"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// verifyErrorStatus status of swap failing verification with error
+type verifyErrorStatus struct {
+	name   string // error name in tokens package
+	err    error
+	status SwapStatus
+}
+
+// verifyErrorStatuses every verify result error of tokens package maps to a specific status,
+// errors not here are transient or not verify result (see utils_test.go).
+var verifyErrorStatuses = []*verifyErrorStatus{
+	{"ErrTxWithWrongMemo", tokens.ErrTxWithWrongMemo, TxWithWrongMemo},
+	{"ErrWrongMemoBindAddress", tokens.ErrWrongMemoBindAddress, TxWithWrongMemo},
+	{"ErrWrongP2shBindAddress", tokens.ErrWrongP2shBindAddress, TxWithWrongMemo},
+	{"ErrTxWithWrongValue", tokens.ErrTxWithWrongValue, TxWithWrongValue},
+	{"ErrWrongSwapValue", tokens.ErrWrongSwapValue, TxWithWrongValue},
+	{"ErrSwapoutValueIsDust", tokens.ErrSwapoutValueIsDust, TxWithWrongValue},
+	{"ErrSwappedValueIsDust", tokens.ErrSwappedValueIsDust, SwapValueIsDust},
+	{"ErrBindAddrIsContract", tokens.ErrBindAddrIsContract, BindAddrIsContract},
+	{"ErrTxSenderNotRegistered", tokens.ErrTxSenderNotRegistered, TxSenderNotRegistered},
+	{"ErrAddressIsInBlacklist", tokens.ErrAddressIsInBlacklist, SwapInBlacklist},
+	{"ErrTxWithWrongSender", tokens.ErrTxWithWrongSender, TxWithWrongSender},
+	{"ErrTxWithWrongReceiver", tokens.ErrTxWithWrongReceiver, TxIsNotDeposit},
+	{"ErrTxWithWrongContract", tokens.ErrTxWithWrongContract, TxIsNotDeposit},
+	{"ErrTxWithWrongInput", tokens.ErrTxWithWrongInput, TxIsNotDeposit},
+	{"ErrTxWithWrongLogData", tokens.ErrTxWithWrongLogData, TxIsNotDeposit},
+	{"ErrTxWithWrongStatus", tokens.ErrTxWithWrongStatus, TxIsNotDeposit},
+	{"ErrTxWithNoPayment", tokens.ErrTxWithNoPayment, TxIsNotDeposit},
+	{"ErrTxFuncHashMismatch", tokens.ErrTxFuncHashMismatch, TxIsNotDeposit},
+	{"ErrDepositLogNotFound", tokens.ErrDepositLogNotFound, TxIsNotDeposit},
+	{"ErrSwapoutLogNotFound", tokens.ErrSwapoutLogNotFound, TxIsNotDeposit},
+	{"ErrTxIsAggregateTx", tokens.ErrTxIsAggregateTx, TxIsNotDeposit},
+	{"ErrTxIsNotValidated", tokens.ErrTxIsNotValidated, TxIsNotDeposit},
+	{"ErrWrongSwapinTxType", tokens.ErrWrongSwapinTxType, TxIsNotDeposit},
+	{"ErrBindAddressMismatch", tokens.ErrBindAddressMismatch, TxVerifyFailed},
+	{"ErrTxBeforeInitialHeight", tokens.ErrTxBeforeInitialHeight, TxVerifyFailed},
+}
+
+// GetVerifyErrorStatus get specific status of swap failing verification with error,
+// returns TxVerifyFailed and false if error is not in the table.
+func GetVerifyErrorStatus(err error) (SwapStatus, bool) {
+	for _, entry := range verifyErrorStatuses {
+		if errors.Is(err, entry.err) {
+			return entry.status, true
+		}
+	}
+	return TxVerifyFailed, false
+}
+
+// GetVerifyErrorName get name of mapped verify error, or 'Other' if not mapped
+func GetVerifyErrorName(err error) string {
+	for _, entry := range verifyErrorStatuses {
+		if errors.Is(err, entry.err) {
+			return entry.name
+		}
+	}
+	return "Other"
+}
+
 // GetStatusByTokenVerifyError get status by token verify error
 func GetStatusByTokenVerifyError(err error) SwapStatus {
 	if !tokens.ShouldRegisterSwapForError(err) {
 		return TxVerifyFailed
 	}
-	// TxNotStable status will be reverify at work/verify, add store in result table
+	// registration is verified with unstable tx allowed,
+	// RegisteredUnstable status will be reverify at stable depth at work/verify, add store in result table
 	switch {
 	case err == nil,
 		errors.Is(err, tokens.ErrTxWithWrongMemo),
 		errors.Is(err, tokens.ErrTxWithWrongValue),
-		errors.Is(err, tokens.ErrBindAddrIsContract):
-		return TxNotStable
-	case errors.Is(err, tokens.ErrTxSenderNotRegistered):
-		return TxSenderNotRegistered
-	default:
-		log.Warn("[mongodb] maybe not considered tx verify error", "err", err)
-		return TxNotStable
+		errors.Is(err, tokens.ErrBindAddrIsContract),
+		errors.Is(err, tokens.ErrSwappedValueIsDust):
+		return RegisteredUnstable
 	}
+	// other verify errors configured to register swap (see tokens.SetRegisterSwapErrors)
+	status, exist := GetVerifyErrorStatus(err)
+	if !exist {
+		log.Warn("[mongodb] register swap with unknown verify error", "err", err)
+	}
+	return status
+}
+
+// isTransientError is network or timeout error which is retryable
+func isTransientError(err error) bool {
+	return mongo.IsNetworkError(err) || mongo.IsTimeout(err)
+}
+
+// withRetry retry idempotent operation on transient errors by mongodb retry policy
+func withRetry(site string, fn func() error) error {
+	return retry.Do(context.Background(), "mongodb."+site, retry.GetPolicy(retry.SubsystemMongoDB), isTransientError, fn)
 }
@@ -0,0 +1,142 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// errors of tokens package which are not verify result, swap keeps its status
+// and is retried, or registration is rejected, or they are not verify errors at all.
+var errorsWithoutVerifyStatus = map[string]bool{
+	// transient, retry later
+	"ErrTxNotFound": true, "ErrTxNotStable": true, "ErrNotFound": true, "ErrRPCQueryError": true,
+	"ErrTxTooRecent": true, "ErrSwapIsClosed": true, "ErrTxWithWrongReceipt": true,
+	"ErrTxIncompatible": true, "ErrEstimateGasFailed": true, "ErrMissTokenPrice": true,
+	// wrong request or config
+	"ErrUnknownPairID": true, "ErrSwapTypeNotSupported": true, "ErrBridgeSourceNotSupported": true,
+	"ErrBridgeDestinationNotSupported": true, "ErrUnknownSwapType": true, "ErrNoBtcBridge": true,
+	"ErrTodo": true, "ErrWrongExtraArgs": true, "ErrWrongPublicKey": true,
+	// build and sign swap tx
+	"ErrMsgHashMismatch": true, "ErrWrongCountOfMsgHashes": true, "ErrWrongRawTx": true,
+	"ErrBuildSwapTxInWrongEndpoint": true, "ErrWrongPayoutLeg": true, "ErrPayoutExceedsCap": true,
+	"ErrRefundNotSupported": true, "ErrRefundValueTooSmall": true,
+	// amount conversion
+	"ErrNilAmount": true, "ErrNegativeAmount": true, "ErrAmountOverflow": true,
+	"ErrAmountMismatch": true, "ErrWrongAmountSize": true,
+}
+
+// parseTokensErrors parse exported error variables of tokens package, value is error message
+func parseTokensErrors() (map[string]string, error) {
+	fset := token.NewFileSet()
+	notTest := func(fi os.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }
+	pkgs, err := parser.ParseDir(fset, "../tokens", notTest, 0)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.VAR {
+					continue
+				}
+				for _, spec := range genDecl.Specs {
+					valueSpec := spec.(*ast.ValueSpec)
+					for i, name := range valueSpec.Names {
+						if !name.IsExported() || !strings.HasPrefix(name.Name, "Err") || i >= len(valueSpec.Values) {
+							continue
+						}
+						call, ok := valueSpec.Values[i].(*ast.CallExpr)
+						if !ok || len(call.Args) != 1 {
+							return nil, fmt.Errorf("%v is not created by errors.New", name.Name)
+						}
+						lit, ok := call.Args[0].(*ast.BasicLit)
+						if !ok {
+							return nil, fmt.Errorf("%v is not created with literal message", name.Name)
+						}
+						result[name.Name], _ = strconv.Unquote(lit.Value)
+					}
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+func TestVerifyErrorStatusesAreExhaustive(t *testing.T) {
+	tokensErrors, err := parseTokensErrors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokensErrors) == 0 {
+		t.Fatal("no error is parsed from tokens package")
+	}
+	mapped := make(map[string]bool, len(verifyErrorStatuses))
+	for _, entry := range verifyErrorStatuses {
+		msg, exist := tokensErrors[entry.name]
+		if !exist {
+			t.Errorf("%v is not an error of tokens package", entry.name)
+		} else if msg != entry.err.Error() {
+			t.Errorf("%v is mapped with wrong error %q", entry.name, entry.err)
+		}
+		if entry.status.Info() == nil {
+			t.Errorf("%v is mapped to status %v not in status catalog", entry.name, uint16(entry.status))
+		}
+		mapped[entry.name] = true
+	}
+	for name := range tokensErrors {
+		if !mapped[name] && !errorsWithoutVerifyStatus[name] {
+			t.Errorf("%v is not mapped to a verify status, add it to verifyErrorStatuses or errorsWithoutVerifyStatus", name)
+		}
+		if mapped[name] && errorsWithoutVerifyStatus[name] {
+			t.Errorf("%v is both mapped and without verify status", name)
+		}
+	}
+}
+
+func TestGetStatusByTokenVerifyError(t *testing.T) {
+	defer func() { _ = tokens.SetRegisterSwapErrors(nil) }()
+	err := tokens.SetRegisterSwapErrors(map[string]bool{"ErrTxWithWrongReceiver": true, "ErrAddressIsInBlacklist": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		err  error
+		want SwapStatus
+	}{
+		{nil, RegisteredUnstable},
+		{tokens.ErrTxWithWrongMemo, RegisteredUnstable},
+		{fmt.Errorf("%w: value too small", tokens.ErrTxWithWrongValue), RegisteredUnstable},
+		{tokens.ErrTxSenderNotRegistered, TxSenderNotRegistered},
+		{tokens.ErrTxWithWrongReceiver, TxIsNotDeposit},
+		{tokens.ErrAddressIsInBlacklist, SwapInBlacklist},
+		{tokens.ErrTxWithWrongContract, TxVerifyFailed}, // not registered
+		{errors.New("unknown"), TxVerifyFailed},
+	}
+	for _, c := range cases {
+		if have := GetStatusByTokenVerifyError(c.err); have != c.want {
+			t.Errorf("verify error %v want status %v, have %v", c.err, c.want, have)
+		}
+	}
+}
+
+func TestStatusCatalogVerifyErrors(t *testing.T) {
+	for _, info := range GetStatusCatalog() {
+		if info.Code == TxIsNotDeposit && len(info.VerifyErrors) == 0 {
+			t.Error("want verify errors of TxIsNotDeposit in catalog")
+		}
+	}
+	if info := TxIsNotDeposit.Info(); info == nil || len(info.VerifyErrors) != 0 {
+		t.Error("verify errors should only be in catalog")
+	}
+}
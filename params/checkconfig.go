@@ -3,12 +3,15 @@ package params
 import (
 	"errors"
 	"fmt"
+	"net"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/anyswap/CrossChain-Bridge/internal/retry"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
 )
 
 var blankOrCommaSepRegexp = regexp.MustCompile(`[\s,]+`) // blank or comma separated
@@ -17,16 +20,43 @@ func splitStringByBlankOrComma(str string) []string {
 	return blankOrCommaSepRegexp.Split(strings.TrimSpace(str), -1)
 }
 
+func checkDeprecatedIdentifiers(identifier string, deprecatedIdentifiers []*DeprecatedIdentifier) error {
+	exist := make(map[string]struct{}, len(deprecatedIdentifiers))
+	for _, deprecated := range deprecatedIdentifiers {
+		if deprecated.Identifier == "" || deprecated.Identifier == identifier {
+			return fmt.Errorf("wrong deprecated identifier '%v'", deprecated.Identifier)
+		}
+		if _, dup := exist[deprecated.Identifier]; dup {
+			return fmt.Errorf("duplicate deprecated identifier '%v'", deprecated.Identifier)
+		}
+		exist[deprecated.Identifier] = struct{}{}
+		if _, err := time.Parse(deprecatedIdentifierExpireLayout, deprecated.Expire); err != nil {
+			return fmt.Errorf("wrong expire '%v' of deprecated identifier '%v'", deprecated.Expire, deprecated.Identifier)
+		}
+		log.Info("deprecated identifier is accepted", "identifier", deprecated.Identifier, "expire", deprecated.Expire,
+			"expired", deprecated.IsExpired(time.Now()))
+	}
+	return nil
+}
+
 // CheckConfig check config
 func CheckConfig(isServer bool) (err error) {
 	config := GetConfig()
 	if config.Identifier == "" {
 		return errors.New("server must config non empty 'Identifier'")
 	}
+	err = checkDeprecatedIdentifiers(config.Identifier, config.DeprecatedIdentifiers)
+	if err != nil {
+		return err
+	}
 	err = checkChainAndGatewayConfig(isServer)
 	if err != nil {
 		return err
 	}
+	err = checkProxyConfig()
+	if err != nil {
+		return err
+	}
 	if isServer {
 		if config.Server == nil {
 			return errors.New("server must config 'Server'")
@@ -48,12 +78,54 @@ func CheckConfig(isServer bool) (err error) {
 	if err != nil {
 		return err
 	}
+	if config.NativePrice != nil {
+		err = config.NativePrice.CheckConfig()
+		if err != nil {
+			return err
+		}
+	}
 	if config.Extra != nil {
 		err = config.Extra.CheckConfig()
 		if err != nil {
 			return err
 		}
 	}
+	if config.Metrics != nil {
+		err = config.Metrics.CheckConfig(isServer)
+		if err != nil {
+			return err
+		}
+	}
+	return checkRetryConfig(config.Retry)
+}
+
+// CheckConfig check metrics config
+func (c *MetricsConfig) CheckConfig(isServer bool) error {
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("wrong metrics 'Port' %v", c.Port)
+	}
+	if c.Enable && !isServer && c.Port == 0 {
+		return errors.New("oracle must config metrics 'Port' if metrics is enabled")
+	}
+	return nil
+}
+
+func checkRetryConfig(retryConfig map[string]*RetryConfig) error {
+	for subsystem, c := range retryConfig {
+		if c == nil {
+			continue
+		}
+		err := retry.SetPolicy(subsystem, &retry.Policy{
+			MaxAttempts:  c.MaxAttempts,
+			InitialDelay: time.Duration(c.InitialDelay) * time.Millisecond,
+			MaxDelay:     time.Duration(c.MaxDelay) * time.Millisecond,
+			Multiplier:   c.Multiplier,
+			Jitter:       c.Jitter,
+		})
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -82,11 +154,48 @@ func checkChainAndGatewayConfig(isServer bool) (err error) {
 	return nil
 }
 
+func checkProxyConfig() error {
+	config := GetConfig()
+	proxies := map[string]string{
+		"Proxy":             config.Proxy,
+		"SrcGateway.Proxy":  config.SrcGateway.Proxy,
+		"DestGateway.Proxy": config.DestGateway.Proxy,
+	}
+	if config.Dcrm != nil {
+		proxies["Dcrm.Proxy"] = config.Dcrm.Proxy
+	}
+	if config.Oracle != nil {
+		proxies["Oracle.Proxy"] = config.Oracle.Proxy
+	}
+	for name, proxy := range proxies {
+		if _, err := client.ParseProxy(proxy); err != nil {
+			return fmt.Errorf("wrong '%v' config, %w", name, err)
+		}
+	}
+	return nil
+}
+
+func isIPOrCIDR(str string) bool {
+	if strings.Contains(str, "/") {
+		_, _, err := net.ParseCIDR(str)
+		return err == nil
+	}
+	return net.ParseIP(str) != nil
+}
+
 // CheckConfig check swap server config
 func (c *ServerConfig) CheckConfig() error {
 	if c.APIServer == nil {
 		return errors.New("server must config 'Server.APIServer'")
 	}
+	if err := c.APIServer.CheckConfig(); err != nil {
+		return err
+	}
+	for _, proxy := range c.TrustedProxies {
+		if !isIPOrCIDR(proxy) {
+			return fmt.Errorf("wrong trusted proxy '%v', should be ip or cidr", proxy)
+		}
+	}
 	if IsTestMode() {
 		return nil
 	}
@@ -96,9 +205,156 @@ func (c *ServerConfig) CheckConfig() error {
 	if err := c.MongoDB.CheckConfig(); err != nil {
 		return err
 	}
+	if err := tokens.SetRegisterSwapErrors(c.RegisterSwapErrors); err != nil {
+		return err
+	}
+	if c.DailyReport != nil {
+		if err := c.DailyReport.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if c.BalanceMonitor != nil {
+		c.BalanceMonitor.CheckConfig()
+	}
+	if c.RegisterRateLimit != nil {
+		if err := c.RegisterRateLimit.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if c.RiskHook != nil {
+		if err := c.RiskHook.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if c.BigValueReview != nil {
+		if err := c.BigValueReview.CheckConfig(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// CheckConfig check api server config
+func (c *APIServerConfig) CheckConfig() error {
+	names := make(map[string]struct{})
+	secrets := make(map[string]struct{})
+	for _, apiToken := range c.APITokens {
+		if apiToken.Name == "" || apiToken.Token == "" {
+			return errors.New("api token must config 'Name' and 'Token'")
+		}
+		if len(apiToken.Methods) == 0 {
+			return fmt.Errorf("api token '%v' must config 'Methods'", apiToken.Name)
+		}
+		if apiToken.RatePerMinute < 0 {
+			return fmt.Errorf("api token '%v' has negative 'RatePerMinute'", apiToken.Name)
+		}
+		if _, exist := names[apiToken.Name]; exist {
+			return fmt.Errorf("duplicate api token name '%v'", apiToken.Name)
+		}
+		if _, exist := secrets[apiToken.Token]; exist {
+			return fmt.Errorf("api token '%v' reuses token of another name", apiToken.Name)
+		}
+		names[apiToken.Name] = struct{}{}
+		secrets[apiToken.Token] = struct{}{}
+	}
+	return nil
+}
+
+// CheckConfig check daily report config
+func (c *DailyReportConfig) CheckConfig() error {
+	location, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return fmt.Errorf("wrong daily report 'TimeZone' %v: %w", c.TimeZone, err)
+	}
+	if c.Delay < 0 {
+		return errors.New("daily report 'Delay' is negative")
+	}
+	c.location = location
+	return nil
+}
+
+// CheckConfig check balance monitor config
+func (c *BalanceMonitorConfig) CheckConfig() {
+	if c.Interval <= 0 {
+		c.Interval = 300
+	}
+	if c.HysteresisPercent == 0 {
+		c.HysteresisPercent = 10
+	}
+}
+
+// CheckConfig check register rate limit config
+func (c *RegisterRateLimitConfig) CheckConfig() error {
+	if err := c.Default.CheckConfig(); err != nil {
+		return fmt.Errorf("register rate limit 'Default': %w", err)
+	}
+	if err := c.PerTxID.CheckConfig(); err != nil {
+		return fmt.Errorf("register rate limit 'PerTxID': %w", err)
+	}
+	methods := make(map[string]*TokenBucketConfig, len(c.Methods))
+	for method, bucket := range c.Methods {
+		if err := bucket.CheckConfig(); err != nil {
+			return fmt.Errorf("register rate limit of method '%v': %w", method, err)
+		}
+		methods[strings.ToLower(method)] = bucket
+	}
+	c.Methods = methods
+	if c.IdleTimeout < 0 {
+		return errors.New("register rate limit 'IdleTimeout' is negative")
+	}
+	if c.IdleTimeout == 0 {
+		c.IdleTimeout = 600
+	}
+	return nil
+}
+
+// CheckConfig check token bucket config
+func (c *TokenBucketConfig) CheckConfig() error {
+	if c == nil {
+		return nil
+	}
+	if c.Rate <= 0 || c.Burst <= 0 {
+		return errors.New("token bucket must config positive 'Rate' and 'Burst'")
+	}
+	return nil
+}
+
+// CheckConfig check risk hook config
+func (c *RiskHookConfig) CheckConfig() error {
+	if c.URL == "" {
+		return errors.New("risk hook must config 'URL'")
+	}
+	if c.Timeout < 0 || c.HoldRetryInterval < 0 {
+		return errors.New("risk hook 'Timeout' or 'HoldRetryInterval' is negative")
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 5
+	}
+	if c.HoldRetryInterval == 0 {
+		c.HoldRetryInterval = 300
+	}
+	return nil
+}
+
+// CheckConfig check big value review config
+func (c *BigValueReviewConfig) CheckConfig() error {
+	if c.SenderInfoTTL < 0 {
+		return errors.New("big value review 'SenderInfoTTL' is negative")
+	}
+	if c.SenderInfoTTL == 0 {
+		c.SenderInfoTTL = 600
+	}
+	return nil
+}
+
+// GetLocation get time zone of daily report day boundaries
+func (c *DailyReportConfig) GetLocation() *time.Location {
+	if c == nil || c.location == nil {
+		return time.UTC
+	}
+	return c.location
+}
+
 // CheckConfig check mongodb config
 func (c *MongoDBConfig) CheckConfig() error {
 	if c.DBName == "" {
@@ -156,6 +412,38 @@ func (c *DcrmConfig) CheckConfig(isServer bool) (err error) {
 			return err
 		}
 	}
+	pubkeys := make(map[string]string, len(c.GroupProfiles))
+	for name, profile := range c.GroupProfiles {
+		err = profile.CheckConfig()
+		if err != nil {
+			return fmt.Errorf("dcrm group profile '%v': %w", name, err)
+		}
+		if other, exist := pubkeys[profile.Pubkey]; exist {
+			return fmt.Errorf("dcrm group profiles '%v' and '%v' have the same 'Pubkey'", other, name)
+		}
+		pubkeys[profile.Pubkey] = name
+	}
+	return nil
+}
+
+// CheckConfig check dcrm group profile config, and normalize its public key
+func (p *DcrmGroupProfile) CheckConfig() (err error) {
+	if p.GroupID == "" {
+		return errors.New("must config 'GroupID'")
+	}
+	if p.NeededOracles == 0 || p.NeededOracles > p.TotalOracles {
+		return fmt.Errorf("wrong threshold %v", p.GetThreshold())
+	}
+	if len(p.SignGroups) == 0 {
+		return errors.New("must config 'SignGroups'")
+	}
+	if p.Pubkey == "" {
+		return errors.New("must config 'Pubkey'")
+	}
+	p.Pubkey, err = tokens.NormalizeDcrmPublicKey(p.Pubkey)
+	if err != nil {
+		return fmt.Errorf("wrong 'Pubkey': %w", err)
+	}
 	return nil
 }
 
@@ -188,6 +476,17 @@ func (c *OracleConfig) CheckConfig() (err error) {
 	if ServerAPIAddress == "" {
 		return errors.New("oracle must config 'ServerAPIAddress'")
 	}
+	if c.MongoDB != nil {
+		if err = c.MongoDB.CheckConfig(); err != nil {
+			return err
+		}
+	}
+	if proxy := GetOracleProxy(); proxy != "" {
+		if err = client.SetProxy([]string{ServerAPIAddress}, proxy); err != nil {
+			return err
+		}
+		log.Info("set oracle proxy", "serverAPIAddress", ServerAPIAddress, "proxy", client.RedactProxy(proxy))
+	}
 	var version string
 	for i := 0; i < 3; i++ {
 		err = client.RPCPostWithTimeout(60, &version, ServerAPIAddress, "swap.GetVersionInfo")
@@ -208,5 +507,10 @@ func (c *ExtraConfig) CheckConfig() (err error) {
 	if c.UsePendingBalance {
 		GetBalanceBlockNumberOpt = "pending"
 	}
+	switch c.RegisterPrecedence {
+	case "", "api", "chain":
+	default:
+		return errors.New("wrong 'RegisterPrecedence', must be 'api' or 'chain'")
+	}
 	return nil
 }
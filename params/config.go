@@ -2,8 +2,11 @@ package params
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/anyswap/CrossChain-Bridge/common"
@@ -13,12 +16,15 @@ import (
 
 const (
 	defaultAPIPort = 11556
+
+	defaultRegisterPrecedence = "api"
 )
 
 var (
 	locDataDir        string
 	bridgeConfig      *BridgeConfig
 	loadConfigStarter sync.Once
+	configFilePath    string
 
 	// IsSwapServer if true then it's swap server, otherwise it's swap oracle
 	IsSwapServer bool
@@ -38,9 +44,12 @@ var (
 	ChanOut = make(chan string)
 )
 
+const replaceIdentifierSuffix = ":replaceswap"
+
 // BridgeConfig config items (decode from toml file)
 type BridgeConfig struct {
 	Identifier  string
+	Proxy       string `toml:",omitempty" json:"-"` // default proxy of chain rpc and dcrm connections
 	SrcChain    *tokens.ChainConfig
 	SrcGateway  *tokens.GatewayConfig
 	DestChain   *tokens.ChainConfig
@@ -51,6 +60,30 @@ type BridgeConfig struct {
 	BtcExtra    *tokens.BtcExtraConfig `toml:",omitempty" json:",omitempty"`
 	Extra       *ExtraConfig           `toml:",omitempty" json:",omitempty"`
 	Dcrm        *DcrmConfig            `toml:",omitempty" json:",omitempty"`
+
+	NativePrice *tokens.NativePriceConfig `toml:",omitempty" json:",omitempty"`
+
+	Retry map[string]*RetryConfig `toml:",omitempty" json:",omitempty"` // key is subsystem (dcrm, rpc, mongodb)
+
+	Metrics *MetricsConfig `toml:",omitempty" json:",omitempty"`
+
+	// previous identifiers of renamed bridge, accepted in dcrm accept until expired
+	DeprecatedIdentifiers []*DeprecatedIdentifier `toml:",omitempty" json:",omitempty"`
+}
+
+// MetricsConfig prometheus metrics config
+type MetricsConfig struct {
+	Enable bool
+	Port   int `toml:",omitempty" json:",omitempty"` // standalone '/metrics' listener, required by oracle
+}
+
+// RetryConfig retry policy config of subsystem
+type RetryConfig struct {
+	MaxAttempts  int
+	InitialDelay uint64  // milliseconds
+	MaxDelay     uint64  // milliseconds
+	Multiplier   float64 `toml:",omitempty" json:",omitempty"`
+	Jitter       float64 `toml:",omitempty" json:",omitempty"`
 }
 
 // ServerConfig swap server config
@@ -62,6 +95,94 @@ type ServerConfig struct {
 
 	SendTxLoopCount    int `toml:",omitempty" json:",omitempty"`
 	SendTxLoopInterval int `toml:",omitempty" json:",omitempty"`
+
+	MaxSwapFailures int `toml:",omitempty" json:",omitempty"`
+
+	StartupReconcile bool  `toml:",omitempty" json:",omitempty"`
+	MaxSwapLifetime  int64 `toml:",omitempty" json:",omitempty"`
+
+	P2shInactiveAge int64 `toml:",omitempty" json:",omitempty"`
+
+	BulkRegisterRate int `toml:",omitempty" json:",omitempty"` // txids per second
+
+	DebugVerifyPublic bool `toml:",omitempty" json:",omitempty"`
+	DebugVerifyRate   int  `toml:",omitempty" json:",omitempty"` // public calls per second
+
+	RegisterMaxConcurrentPerBind int   `toml:",omitempty" json:",omitempty"` // public register verifications in progress
+	RegisterMaxConcurrentPerIP   int   `toml:",omitempty" json:",omitempty"` // public register verifications in progress
+	RegisterRatePerIP            int   `toml:",omitempty" json:",omitempty"` // public register calls per minute
+	TxNotFoundCacheTTL           int64 `toml:",omitempty" json:",omitempty"` // seconds
+
+	TrustedProxies []string `toml:",omitempty" json:",omitempty"` // ips or cidrs of reverse proxies, trust their 'X-Forwarded-For'
+
+	EstimateHistoryTotal bool `toml:",omitempty" json:",omitempty"` // approximate total of paginated history
+	EnableSwapEvents     bool `toml:",omitempty" json:",omitempty"` // persist swap change events for 'GetSwapEvents'
+
+	RegisterSwapErrors map[string]bool `toml:",omitempty" json:",omitempty"` // override which verify errors still register swap
+
+	RegisterRateLimit *RegisterRateLimitConfig `toml:",omitempty" json:",omitempty"`
+
+	DailyReport    *DailyReportConfig    `toml:",omitempty" json:",omitempty"`
+	BalanceMonitor *BalanceMonitorConfig `toml:",omitempty" json:",omitempty"`
+	RiskHook       *RiskHookConfig       `toml:",omitempty" json:",omitempty"`
+	BigValueReview *BigValueReviewConfig `toml:",omitempty" json:",omitempty"`
+
+	UnsupportedDeposit *UnsupportedDepositConfig `toml:",omitempty" json:",omitempty"`
+}
+
+// DailyReportConfig daily summary report config
+type DailyReportConfig struct {
+	Enable   bool
+	TimeZone string `toml:",omitempty" json:",omitempty"` // day boundaries time zone, eg. Asia/Shanghai (default UTC)
+	Delay    int64  `toml:",omitempty" json:",omitempty"` // seconds after the day ends to generate its report
+	PushURL  string `toml:",omitempty" json:",omitempty"` // post generated report in json to this url if not empty
+
+	location *time.Location
+}
+
+// BalanceMonitorConfig payer accounts native balance monitor config,
+// thresholds are configured per chain by 'BalanceWarning' and 'BalanceCritical'
+type BalanceMonitorConfig struct {
+	Enable            bool
+	Interval          int64  `toml:",omitempty" json:",omitempty"` // seconds (default 300)
+	HysteresisPercent uint64 `toml:",omitempty" json:",omitempty"` // recover above threshold plus this percent (default 10)
+	PushURL           string `toml:",omitempty" json:",omitempty"` // post alert in json to this url if not empty
+}
+
+// RegisterRateLimitConfig token bucket rate limits of public register calls,
+// methods without limit (neither in 'Methods' nor 'Default') are not limited
+type RegisterRateLimitConfig struct {
+	Default     *TokenBucketConfig            `toml:",omitempty" json:",omitempty"` // per client ip of methods not in 'Methods'
+	Methods     map[string]*TokenBucketConfig `toml:",omitempty" json:",omitempty"` // per client ip, key is register method
+	PerTxID     *TokenBucketConfig            `toml:",omitempty" json:",omitempty"` // per txid to collapse duplicate registers
+	IdleTimeout int64                         `toml:",omitempty" json:",omitempty"` // seconds to evict idle buckets (default 600)
+}
+
+// TokenBucketConfig token bucket config
+type TokenBucketConfig struct {
+	Rate  float64 // tokens refilled per second
+	Burst int     // bucket capacity
+}
+
+// RiskHookConfig external risk engine consulted before signing swap
+type RiskHookConfig struct {
+	URL               string
+	Timeout           int   `toml:",omitempty" json:",omitempty"` // seconds (default 5)
+	FailOpen          bool  `toml:",omitempty" json:",omitempty"` // sign if risk engine is unavailable, overridden per pair
+	HoldRetryInterval int64 `toml:",omitempty" json:",omitempty"` // seconds to ask again for held swap (default 300)
+}
+
+// BigValueReviewConfig manual review of big value swaps config
+type BigValueReviewConfig struct {
+	PushURL       string `toml:",omitempty" json:",omitempty"` // post pending big value swap with sender info in json to this url if not empty
+	SenderInfoTTL int64  `toml:",omitempty" json:",omitempty"` // seconds to cache gathered sender info (default 600)
+}
+
+// UnsupportedDepositConfig scan transfers of unsupported tokens to deposit addresses config
+type UnsupportedDepositConfig struct {
+	Enable      bool
+	StartHeight uint64 `toml:",omitempty" json:",omitempty"` // height to start if never scanned (default latest stable height)
+	PushURL     string `toml:",omitempty" json:",omitempty"` // post found unsupported deposit in json to this url if not empty
 }
 
 // DcrmConfig dcrm related config
@@ -74,6 +195,8 @@ type DcrmConfig struct {
 
 	VerifySignatureInAccept bool `toml:",omitempty" json:",omitempty"`
 
+	DisagreementPushURL string `toml:",omitempty" json:",omitempty"` // alert sign disagreements
+
 	GroupID       *string
 	NeededOracles *uint32
 	TotalOracles  *uint32
@@ -81,6 +204,36 @@ type DcrmConfig struct {
 	Initiators    []string
 	DefaultNode   *DcrmNodeConfig
 	OtherNodes    []*DcrmNodeConfig `toml:",omitempty" json:",omitempty"`
+
+	// named dcrm groups referenced by token pair 'DcrmGroup', key is profile name
+	GroupProfiles map[string]*DcrmGroupProfile `toml:",omitempty" json:",omitempty"`
+
+	Proxy string `toml:",omitempty" json:"-"` // override default proxy
+}
+
+// DcrmGroupProfile dcrm group with its own threshold and public key,
+// pairs referencing it are signed by its sign groups instead of the default group
+type DcrmGroupProfile struct {
+	GroupID       string
+	NeededOracles uint32
+	TotalOracles  uint32
+	SignGroups    []string // sign subgroups initiators pick from
+	Pubkey        string   // dcrm public key generated by this group
+}
+
+// GetThreshold get sign threshold, eg. 3/5
+func (p *DcrmGroupProfile) GetThreshold() string {
+	return fmt.Sprintf("%d/%d", p.NeededOracles, p.TotalOracles)
+}
+
+// IsSignGroup is sign subgroup of this profile
+func (p *DcrmGroupProfile) IsSignGroup(groupID string) bool {
+	for _, signGroup := range p.SignGroups {
+		if strings.EqualFold(signGroup, groupID) {
+			return true
+		}
+	}
+	return false
 }
 
 // DcrmNodeConfig dcrm node config
@@ -94,8 +247,21 @@ type DcrmNodeConfig struct {
 // OracleConfig oracle config
 type OracleConfig struct {
 	ServerAPIAddress      string
+	Proxy                 string `toml:",omitempty" json:"-"` // override default proxy of server api connections
 	GetAcceptListInterval uint64
 	PendingInvalidAccept  bool `toml:",omitempty" json:",omitempty"`
+
+	RetryAcceptListInterval uint64 `toml:",omitempty" json:",omitempty"` // seconds, base interval when getting accept list failed
+	MaxAcceptListInterval   uint64 `toml:",omitempty" json:",omitempty"` // seconds, cap of backoff interval
+
+	ReplayBundleDir     string `toml:",omitempty" json:",omitempty"` // capture replay bundles of disagreed verifications if not empty
+	ReplayBundleMaxSize int    `toml:",omitempty" json:",omitempty"` // bytes
+
+	MaxMsgContextSize int `toml:",omitempty" json:",omitempty"` // bytes, larger msg context of sign is discarded before parsing
+
+	MaxAcceptRoutines int `toml:",omitempty" json:",omitempty"` // sign infos verified concurrently (default 10)
+
+	MongoDB *MongoDBConfig `toml:",omitempty" json:",omitempty"` // persist processed accept sign infos if configured
 }
 
 // APIServerConfig api service config
@@ -103,6 +269,19 @@ type APIServerConfig struct {
 	Port             int
 	AllowedOrigins   []string
 	MaxRequestsLimit int
+
+	SigningKeyFile      string `toml:",omitempty" json:"-"`
+	SigningPasswordFile string `toml:",omitempty" json:"-"`
+
+	APITokens []*APITokenConfig `toml:",omitempty" json:"-"`
+}
+
+// APITokenConfig api token of partner, restricted to call the scoped methods
+type APITokenConfig struct {
+	Name          string
+	Token         string
+	Methods       []string // allowed rpc methods, trailing '*' matches by prefix
+	RatePerMinute int      `toml:",omitempty"` // 0 means unlimited
 }
 
 // MongoDBConfig mongodb config
@@ -112,6 +291,8 @@ type MongoDBConfig struct {
 	DBName   string
 	UserName string `json:"-"`
 	Password string `json:"-"`
+
+	MaxMemoLength int `toml:",omitempty" json:",omitempty"` // max bytes of memo stored in swaps
 }
 
 // ExtraConfig extra config
@@ -124,6 +305,12 @@ type ExtraConfig struct {
 	IsNullSwapoutNativeMemo  bool `toml:",omitempty" json:",omitempty"`
 	UsePendingBalance        bool `toml:",omitempty" json:",omitempty"`
 	CheckBindAddrIsContract  bool `toml:",omitempty" json:",omitempty"`
+
+	// precedence of conflict registrations from api and registry contract, 'api' (default) or 'chain'
+	RegisterPrecedence string `toml:",omitempty" json:",omitempty"`
+
+	// registering address by api requires signature of the address over server challenge
+	RegisterAddressProof bool `toml:",omitempty" json:",omitempty"`
 }
 
 // GetAPIPort get api service port
@@ -142,7 +329,34 @@ func GetIdentifier() string {
 
 // GetReplaceIdentifier get identifier (to distiguish in dcrm accept)
 func GetReplaceIdentifier() string {
-	return GetConfig().Identifier + ":replaceswap"
+	return GetConfig().Identifier + replaceIdentifierSuffix
+}
+
+// DeprecatedIdentifier previous identifier of renamed bridge,
+// sign requests with it are accepted as own before the expire date.
+type DeprecatedIdentifier struct {
+	Identifier string
+	Expire     string // date (UTC) from which it is rejected, eg. 2006-01-02
+}
+
+const deprecatedIdentifierExpireLayout = "2006-01-02"
+
+// IsExpired is deprecated identifier expired at now, malformed expire is treated as expired
+func (d *DeprecatedIdentifier) IsExpired(now time.Time) bool {
+	expireTime, err := time.Parse(deprecatedIdentifierExpireLayout, d.Expire)
+	return err != nil || !now.Before(expireTime)
+}
+
+// GetDeprecatedIdentifier get configed deprecated identifier matching
+// identifier or its replace swap variant, return nil if not configed
+func GetDeprecatedIdentifier(identifier string) *DeprecatedIdentifier {
+	identifier = strings.TrimSuffix(identifier, replaceIdentifierSuffix)
+	for _, deprecated := range GetConfig().DeprecatedIdentifiers {
+		if deprecated.Identifier == identifier {
+			return deprecated
+		}
+	}
+	return nil
 }
 
 // MustRegisterAccount flag
@@ -150,6 +364,19 @@ func MustRegisterAccount() bool {
 	return GetExtraConfig() != nil && GetExtraConfig().MustRegisterAccount
 }
 
+// IsRegisterAddressProofRequired is ownership proof required to register address by api
+func IsRegisterAddressProofRequired() bool {
+	return GetExtraConfig() != nil && GetExtraConfig().RegisterAddressProof
+}
+
+// GetRegisterPrecedence get precedence of conflict address registrations
+func GetRegisterPrecedence() string {
+	if GetExtraConfig() == nil || GetExtraConfig().RegisterPrecedence == "" {
+		return defaultRegisterPrecedence
+	}
+	return GetExtraConfig().RegisterPrecedence
+}
+
 // IsSwapoutToStringAddress swapout to string address (eg. btc)
 func IsSwapoutToStringAddress() bool {
 	return GetExtraConfig() != nil && GetExtraConfig().IsSwapoutToStringAddress
@@ -204,6 +431,7 @@ func GetConfig() *BridgeConfig {
 func SetConfig(config *BridgeConfig) {
 	bridgeConfig = config
 	tokens.TokenPriceCfg = config.TokenPrice
+	tokens.NativePriceCfg = config.NativePrice
 }
 
 // GetServerConfig get server config
@@ -211,14 +439,31 @@ func GetServerConfig() *ServerConfig {
 	return GetConfig().Server
 }
 
+// IsMetricsEnabled is prometheus metrics enabled
+func IsMetricsEnabled() bool {
+	metricsConfig := GetConfig().Metrics
+	return metricsConfig != nil && metricsConfig.Enable
+}
+
+// GetMetricsPort get port of standalone metrics listener, 0 means not configured
+func GetMetricsPort() int {
+	if !IsMetricsEnabled() {
+		return 0
+	}
+	return GetConfig().Metrics.Port
+}
+
 // GetOracleConfig get oracle config
 func GetOracleConfig() *OracleConfig {
 	return GetConfig().Oracle
 }
 
-// GetExtraConfig get extra config
+// GetExtraConfig get extra config, nil if config is not loaded
 func GetExtraConfig() *ExtraConfig {
-	return GetConfig().Extra
+	if config := GetConfig(); config != nil {
+		return config.Extra
+	}
+	return nil
 }
 
 // GetTokenPriceConfig get token price config
@@ -240,6 +485,7 @@ func LoadConfig(configFile string, isServer bool) *BridgeConfig {
 		if _, err := toml.DecodeFile(configFile, &config); err != nil {
 			log.Fatalf("LoadConfig error (toml DecodeFile): %v", err)
 		}
+		configFilePath = configFile
 
 		if isServer {
 			config.Oracle = nil
@@ -263,6 +509,59 @@ func LoadConfig(configFile string, isServer bool) *BridgeConfig {
 	return bridgeConfig
 }
 
+// LoadGatewayConfigs load latest gateway configs from config file
+func LoadGatewayConfigs() (srcGateway, dstGateway *tokens.GatewayConfig, err error) {
+	if configFilePath == "" {
+		return nil, nil, errors.New("config file is not loaded")
+	}
+	config := &BridgeConfig{}
+	if _, err = toml.DecodeFile(configFilePath, &config); err != nil {
+		return nil, nil, err
+	}
+	if config.SrcGateway == nil || config.DestGateway == nil {
+		return nil, nil, errors.New("missing gateway config")
+	}
+	return config.SrcGateway, config.DestGateway, nil
+}
+
+// GetGatewayProxy get proxy of gateway, fallback to default proxy
+func GetGatewayProxy(gateway *tokens.GatewayConfig) string {
+	if gateway.Proxy != "" {
+		return gateway.Proxy
+	}
+	return GetConfig().Proxy
+}
+
+// GetDcrmProxy get proxy of dcrm nodes, fallback to default proxy
+func GetDcrmProxy() string {
+	if proxy := GetConfig().Dcrm.Proxy; proxy != "" {
+		return proxy
+	}
+	return GetConfig().Proxy
+}
+
+// GetDcrmGroupProfile get dcrm group profile by name, nil if not exist
+func GetDcrmGroupProfile(name string) *DcrmGroupProfile {
+	return GetConfig().Dcrm.GroupProfiles[name]
+}
+
+// GetDcrmGroupPubkeys get public keys of dcrm group profiles, key is profile name
+func (c *DcrmConfig) GetDcrmGroupPubkeys() map[string]string {
+	pubkeys := make(map[string]string, len(c.GroupProfiles))
+	for name, profile := range c.GroupProfiles {
+		pubkeys[name] = profile.Pubkey
+	}
+	return pubkeys
+}
+
+// GetOracleProxy get proxy of oracle connecting to swap server, fallback to default proxy
+func GetOracleProxy() string {
+	if proxy := GetConfig().Oracle.Proxy; proxy != "" {
+		return proxy
+	}
+	return GetConfig().Proxy
+}
+
 // HasAdmin has admin
 func HasAdmin() bool {
 	return len(GetServerConfig().Admins) != 0
@@ -16,6 +16,13 @@ import (
 var (
 	httpClient *http.Client
 	httpCtx    = context.Background()
+
+	// defaultTransport is used before InitHTTPClient
+	defaultTransport = func() *http.Transport {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = getProxy
+		return transport
+	}()
 )
 
 // InitHTTPClient init http client
@@ -34,7 +41,7 @@ const (
 func createHTTPClient() *http.Client {
 	return &http.Client{
 		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
+			Proxy: getProxy,
 			DialContext: (&net.Dialer{
 				Timeout:   30 * time.Second,
 				KeepAlive: 30 * time.Second,
@@ -157,10 +164,13 @@ func doRequest(req *http.Request, timeoutSeconds int) (*http.Response, error) {
 	timeout := time.Duration(timeoutSeconds) * time.Second
 	if httpClient == nil {
 		client := http.Client{
-			Timeout: timeout,
+			Transport: defaultTransport,
+			Timeout:   timeout,
 		}
-		return client.Do(req)
+		resp, err := client.Do(req)
+		return resp, wrapProxyError(req, err)
 	}
 	httpClient.Timeout = timeout
-	return httpClient.Do(req)
+	resp, err := httpClient.Do(req)
+	return resp, wrapProxyError(req, err)
 }
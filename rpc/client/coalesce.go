@@ -0,0 +1,70 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/internal/metrics"
+	"golang.org/x/sync/singleflight"
+)
+
+// methods containing these keywords mutate chain state (eg. eth_sendRawTransaction),
+// they are always requested by their own
+var nonCoalescableMethodKeywords = []string{"send", "submit", "broadcast"}
+
+var rpcCallGroup singleflight.Group
+
+func isCoalescable(method string) bool {
+	method = strings.ToLower(method)
+	for _, keyword := range nonCoalescableMethodKeywords {
+		if strings.Contains(method, keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+// getCoalesceKey key of identical calls is (gateway, method, params hash, max response size)
+func getCoalesceKey(url string, req *Request) (string, error) {
+	params, err := json.Marshal(req.Params)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(params)
+	key := url + " " + req.Method + " " + hex.EncodeToString(hash[:])
+	if req.MaxResponseSize > 0 {
+		key += " " + strconv.FormatInt(req.MaxResponseSize, 10)
+	}
+	return key, nil
+}
+
+// coalesceRPCRequest share one in-flight request among identical concurrent calls,
+// all of them receive the same result or error.
+func coalesceRPCRequest(url string, req *Request) (json.RawMessage, error) {
+	key, err := getCoalesceKey(url, req)
+	if err != nil {
+		return postRPCRequest(httpCtx, url, req)
+	}
+	var requested bool
+	res, err, shared := rpcCallGroup.Do(key, func() (interface{}, error) {
+		requested = true
+		return postRPCRequest(httpCtx, url, req)
+	})
+	if shared && !requested {
+		observeCoalescedCall(url)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res.(json.RawMessage), nil
+}
+
+func observeCoalescedCall(rawurl string) {
+	if !metrics.IsEnabled() {
+		return
+	}
+	metrics.RPCCoalescedCalls.Inc(getHostLabel(rawurl))
+}
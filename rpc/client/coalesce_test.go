@@ -0,0 +1,79 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newCountingRPCServer(delay time.Duration) (*httptest.Server, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(delay)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x64"}`))
+	}))
+	return server, &requests
+}
+
+func concurrentRPCPost(count int, url, method string, params ...interface{}) []string {
+	results := make([]string, count)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(count)
+	for i := 0; i < count; i++ {
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			if err := RPCPost(&results[i], url, method, params...); err != nil {
+				results[i] = err.Error()
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	return results
+}
+
+func TestCoalesceIdenticalCalls(t *testing.T) {
+	server, requests := newCountingRPCServer(500 * time.Millisecond)
+	defer server.Close()
+
+	results := concurrentRPCPost(100, server.URL, "eth_getTransactionReceipt", "0x1234")
+	if have := atomic.LoadInt32(requests); have != 1 {
+		t.Errorf("want 1 request for identical concurrent calls, have %v", have)
+	}
+	for i, result := range results {
+		if result != "0x64" {
+			t.Fatalf("call %v have result %v", i, result)
+		}
+	}
+}
+
+func TestCoalesceBypass(t *testing.T) {
+	server, requests := newCountingRPCServer(200 * time.Millisecond)
+	defer server.Close()
+
+	concurrentRPCPost(5, server.URL, "eth_sendRawTransaction", "0xf86c")
+	if have := atomic.LoadInt32(requests); have != 5 {
+		t.Errorf("mutating calls should not be coalesced, want 5 requests, have %v", have)
+	}
+
+	atomic.StoreInt32(requests, 0)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	for _, txid := range []string{"0x01", "0x02"} {
+		go func(txid string) {
+			defer wg.Done()
+			var result string
+			_ = RPCPost(&result, server.URL, "eth_getTransactionReceipt", txid)
+		}(txid)
+	}
+	wg.Wait()
+	if have := atomic.LoadInt32(requests); have != 2 {
+		t.Errorf("calls with different params should not be coalesced, want 2 requests, have %v", have)
+	}
+}
@@ -2,13 +2,19 @@ package client
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
+	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/log"
 )
 
+// ErrNotFoundStatus error of 404 response status
+var ErrNotFoundStatus = errors.New("error response status: 404")
+
 // RPCGet rpc get
 func RPCGet(result interface{}, url string) error {
 	return RPCGetRequest(result, url, nil, nil, defaultSlowTimeout)
@@ -20,7 +26,8 @@ func RPCGetWithTimeout(result interface{}, url string, timeout int) error {
 }
 
 // RPCGetRequest rpc get request
-func RPCGetRequest(result interface{}, url string, params, headers map[string]string, timeout int) error {
+func RPCGetRequest(result interface{}, url string, params, headers map[string]string, timeout int) (err error) {
+	defer observeRPCCall(url, time.Now(), &err)
 	resp, err := HTTPGet(url, params, headers, timeout)
 	if err != nil {
 		return fmt.Errorf("GET request error: %w (url: %v, params: %v)", err, url, params)
@@ -29,6 +36,10 @@ func RPCGetRequest(result interface{}, url string, params, headers map[string]st
 		_ = resp.Body.Close()
 	}()
 
+	if resp.StatusCode == http.StatusNotFound {
+		log.Trace("get rpc status error", "url", url, "status", resp.StatusCode)
+		return fmt.Errorf("%w (url: %v)", ErrNotFoundStatus, url)
+	}
 	if resp.StatusCode != 200 {
 		log.Trace("get rpc status error", "url", url, "status", resp.StatusCode)
 		return fmt.Errorf("error response status: %v (url: %v)", resp.StatusCode, url)
@@ -0,0 +1,28 @@
+package client
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/internal/metrics"
+)
+
+// observeRPCCall record rpc call latency by host, url path and query are not labeled
+func observeRPCCall(rawurl string, start time.Time, errp *error) {
+	if !metrics.IsEnabled() {
+		return
+	}
+	result := metrics.ResultLabel(*errp)
+	if errors.Is(*errp, ErrNotFoundStatus) {
+		result = "notfound"
+	}
+	metrics.RPCCallDuration.ObserveSince(start, getHostLabel(rawurl), result)
+}
+
+func getHostLabel(rawurl string) string {
+	if u, err := url.Parse(rawurl); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "unknown"
+}
@@ -3,10 +3,12 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/log"
 )
@@ -15,14 +17,21 @@ const (
 	defaultSlowTimeout = 60 // seconds
 	defaultTimeout     = 5  // seconds
 	defaultRequestID   = 1
+
+	defaultMaxResponseSize int64 = 1024 * 1024 * 10 // 10M
 )
 
+// ErrResponseTooLarge response body exceeds max response size of request
+var ErrResponseTooLarge = errors.New("response body is too large")
+
 // Request json rpc request
 type Request struct {
 	Method  string
 	Params  interface{}
 	Timeout int
 	ID      int
+
+	MaxResponseSize int64 // bytes, default to 10M
 }
 
 // NewRequest new request
@@ -88,6 +97,21 @@ func (err *jsonError) Error() string {
 	return fmt.Sprintf("json-rpc error %d, %s", err.Code, err.Message)
 }
 
+// IsJSONRPCError is error returned by json rpc server (not transport error)
+func IsJSONRPCError(err error) bool {
+	var jsonErr *jsonError
+	return errors.As(err, &jsonErr)
+}
+
+// GetJSONRPCErrorCode get code of error returned by json rpc server
+func GetJSONRPCErrorCode(err error) (int, bool) {
+	var jsonErr *jsonError
+	if !errors.As(err, &jsonErr) {
+		return 0, false
+	}
+	return jsonErr.Code, true
+}
+
 type jsonrpcResponse struct {
 	Version string          `json:"jsonrpc,omitempty"`
 	ID      json.RawMessage `json:"id,omitempty"`
@@ -95,13 +119,29 @@ type jsonrpcResponse struct {
 	Result  json.RawMessage `json:"result,omitempty"`
 }
 
-// RPCPostRequest rpc post request
+// RPCPostRequest rpc post request, identical concurrent calls are coalesced except mutating ones
 func RPCPostRequest(url string, req *Request, result interface{}) error {
-	return RPCPostRequestWithContext(httpCtx, url, req, result)
+	if !isCoalescable(req.Method) {
+		return RPCPostRequestWithContext(httpCtx, url, req, result)
+	}
+	rawResult, err := coalesceRPCRequest(url, req)
+	if err != nil {
+		return err
+	}
+	return unmarshalResult(rawResult, result)
 }
 
 // RPCPostRequestWithContext rpc post request with context
 func RPCPostRequestWithContext(ctx context.Context, url string, req *Request, result interface{}) error {
+	rawResult, err := postRPCRequest(ctx, url, req)
+	if err != nil {
+		return err
+	}
+	return unmarshalResult(rawResult, result)
+}
+
+func postRPCRequest(ctx context.Context, url string, req *Request) (rawResult json.RawMessage, err error) {
+	defer observeRPCCall(url, time.Now(), &err)
 	reqBody := &RequestBody{
 		Version: "2.0",
 		Method:  req.Method,
@@ -111,44 +151,54 @@ func RPCPostRequestWithContext(ctx context.Context, url string, req *Request, re
 	resp, err := HTTPPostWithContext(ctx, url, reqBody, nil, nil, req.Timeout)
 	if err != nil {
 		log.Trace("post rpc error", "url", url, "request", req, "err", err)
-		return err
+		return nil, err
 	}
-	err = getResultFromJSONResponse(result, resp)
+	maxSize := req.MaxResponseSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxResponseSize
+	}
+	rawResult, err = getResultFromJSONResponse(resp, maxSize)
 	if err != nil {
 		log.Trace("post rpc error", "url", url, "request", req, "err", err)
 	}
-	return err
+	return rawResult, err
+}
+
+func unmarshalResult(rawResult json.RawMessage, result interface{}) error {
+	err := json.Unmarshal(rawResult, &result)
+	if err != nil {
+		return fmt.Errorf("unmarshal result error: %w", err)
+	}
+	return nil
 }
 
-func getResultFromJSONResponse(result interface{}, resp *http.Response) error {
+func getResultFromJSONResponse(resp *http.Response, maxSize int64) (json.RawMessage, error) {
 	defer func() {
 		_ = resp.Body.Close()
 	}()
-	const maxReadContentLength int64 = 1024 * 1024 * 10 // 10M
-	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxReadContentLength))
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxSize+1))
 	if err != nil {
-		return fmt.Errorf("read body error: %w", err)
+		return nil, fmt.Errorf("read body error: %w", err)
+	}
+	if int64(len(body)) > maxSize {
+		return nil, fmt.Errorf("%w: exceeds %v bytes", ErrResponseTooLarge, maxSize)
 	}
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("wrong response status %v. message: %v", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("wrong response status %v. message: %v", resp.StatusCode, string(body))
 	}
 	if len(body) == 0 {
-		return fmt.Errorf("empty response body")
+		return nil, fmt.Errorf("empty response body")
 	}
 
 	var jsonResp jsonrpcResponse
 	err = json.Unmarshal(body, &jsonResp)
 	if err != nil {
-		return fmt.Errorf("unmarshal body error, body is \"%v\" err=\"%w\"", string(body), err)
+		return nil, fmt.Errorf("unmarshal body error, body is \"%v\" err=\"%w\"", string(body), err)
 	}
 	if jsonResp.Error != nil {
-		return fmt.Errorf("return error: %w", jsonResp.Error)
+		return nil, fmt.Errorf("return error: %w", jsonResp.Error)
 	}
-	err = json.Unmarshal(jsonResp.Result, &result)
-	if err != nil {
-		return fmt.Errorf("unmarshal result error: %w", err)
-	}
-	return nil
+	return jsonResp.Result, nil
 }
 
 // RPCRawPost rpc raw post
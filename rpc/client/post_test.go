@@ -0,0 +1,28 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxResponseSize(t *testing.T) {
+	result := `"` + strings.Repeat("a", 100) + `"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + result + `}`))
+	}))
+	defer server.Close()
+
+	var have string
+	req := NewRequest("test_getLarge")
+	if err := RPCPostRequest(server.URL, req, &have); err != nil || len(have) != 100 {
+		t.Fatalf("default max response size should be enough, have %v %v", len(have), err)
+	}
+
+	req.MaxResponseSize = 100
+	if err := RPCPostRequest(server.URL, req, &have); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("want %v, have %v", ErrResponseTooLarge, err)
+	}
+}
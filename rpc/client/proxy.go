@@ -0,0 +1,156 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ProxyDirect disable proxy of the targets
+const ProxyDirect = "direct"
+
+var (
+	// ErrProxyConnect is matched by errors.Is when connecting to proxy failed
+	ErrProxyConnect = errors.New("proxy connect failed")
+
+	// proxies of target hosts, nil value means direct connection.
+	// hosts not registered use proxy from environment as before.
+	proxies     = make(map[string]*url.URL)
+	proxiesLock sync.RWMutex
+)
+
+// ProxyError error of connecting to proxy, target is not reached
+type ProxyError struct {
+	Proxy  string // redacted proxy url
+	Target string
+	Err    error
+}
+
+func (e *ProxyError) Error() string {
+	return fmt.Sprintf("%v (proxy: %v, target: %v): %v", ErrProxyConnect, e.Proxy, e.Target, e.Err)
+}
+
+// Unwrap unwrap error
+func (e *ProxyError) Unwrap() error {
+	return e.Err
+}
+
+// Is is ErrProxyConnect
+func (e *ProxyError) Is(target error) bool {
+	return target == ErrProxyConnect
+}
+
+// ParseProxy parse proxy url, env references like `${PROXY_PASSWORD}` are expanded.
+// returns nil if proxy is empty or `direct`.
+// supported schemes are http, https and socks5.
+func ParseProxy(proxy string) (*url.URL, error) {
+	proxy = strings.TrimSpace(os.ExpandEnv(proxy))
+	if proxy == "" || strings.EqualFold(proxy, ProxyDirect) {
+		return nil, nil
+	}
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, errors.New("invalid proxy url")
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme '%v'", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy url %v", RedactProxy(proxy))
+	}
+	return proxyURL, nil
+}
+
+// SetProxy set proxy of target urls, empty or `direct` proxy means direct connection
+func SetProxy(targets []string, proxy string) error {
+	proxyURL, err := ParseProxy(proxy)
+	if err != nil {
+		return err
+	}
+	hosts := make([]string, 0, len(targets))
+	for _, target := range targets {
+		targetURL, err := url.Parse(target)
+		if err != nil || targetURL.Host == "" {
+			return fmt.Errorf("invalid proxy target url %v", target)
+		}
+		hosts = append(hosts, strings.ToLower(targetURL.Host))
+	}
+	proxiesLock.Lock()
+	defer proxiesLock.Unlock()
+	for _, host := range hosts {
+		proxies[host] = proxyURL
+	}
+	return nil
+}
+
+// ClearProxy clear proxy of target urls, they use proxy from environment again.
+// invalid target urls are ignored as they can not have been set.
+func ClearProxy(targets []string) {
+	proxiesLock.Lock()
+	defer proxiesLock.Unlock()
+	for _, target := range targets {
+		if targetURL, err := url.Parse(target); err == nil && targetURL.Host != "" {
+			delete(proxies, strings.ToLower(targetURL.Host))
+		}
+	}
+}
+
+// RedactProxy redact password of proxy url to be logged
+func RedactProxy(proxy string) string {
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return "xxxxx"
+	}
+	return redactProxyURL(proxyURL)
+}
+
+func redactProxyURL(proxyURL *url.URL) string {
+	if proxyURL == nil {
+		return ProxyDirect
+	}
+	if proxyURL.User == nil {
+		return proxyURL.String()
+	}
+	if _, hasPassword := proxyURL.User.Password(); !hasPassword {
+		return proxyURL.String()
+	}
+	redacted := *proxyURL
+	redacted.User = url.UserPassword(proxyURL.User.Username(), "xxxxx")
+	return redacted.String()
+}
+
+// GetProxy get proxy of request, used by non http clients (eg. websocket dialer)
+func GetProxy(req *http.Request) (*url.URL, error) {
+	return getProxy(req)
+}
+
+func getProxy(req *http.Request) (*url.URL, error) {
+	proxiesLock.RLock()
+	proxyURL, exist := proxies[strings.ToLower(req.URL.Host)]
+	proxiesLock.RUnlock()
+	if exist {
+		return proxyURL, nil
+	}
+	return http.ProxyFromEnvironment(req)
+}
+
+// wrapProxyError distinguish connecting to proxy failure from target failure
+func wrapProxyError(req *http.Request, err error) error {
+	var opErr *net.OpError
+	if err == nil || !errors.As(err, &opErr) || opErr.Op != "proxyconnect" {
+		return err
+	}
+	proxyURL, _ := getProxy(req)
+	return &ProxyError{
+		Proxy:  redactProxyURL(proxyURL),
+		Target: req.URL.Host,
+		Err:    opErr.Err,
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/internal/apiversion"
 	"github.com/anyswap/CrossChain-Bridge/internal/swapapi"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/params"
@@ -18,7 +20,7 @@ func writeResponse(w http.ResponseWriter, resp interface{}, err error) {
 		writeErrResponse(w, err)
 		return
 	}
-	jsonData, err := json.Marshal(resp)
+	jsonData, err := json.Marshal(apiversion.RenderFor(w, resp))
 	if err != nil {
 		writeErrResponse(w, err)
 		return
@@ -55,12 +57,109 @@ func ServerInfoHandler(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, res, err)
 }
 
+// HealthHandler handler
+func HealthHandler(w http.ResponseWriter, r *http.Request) {
+	res, err := swapapi.GetHealth()
+	writeResponse(w, res, err)
+}
+
 // OracleInfoHandler handler
 func OracleInfoHandler(w http.ResponseWriter, r *http.Request) {
 	res := swapapi.GetOraclesHeartbeat()
 	writeResponse(w, res, nil)
 }
 
+// OracleJobStatusHandler handler
+func OracleJobStatusHandler(w http.ResponseWriter, r *http.Request) {
+	res := swapapi.GetOraclesJobStatus()
+	writeResponse(w, res, nil)
+}
+
+// RetryMetricsHandler handler
+func RetryMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	res := swapapi.GetRetryMetrics()
+	writeResponse(w, res, nil)
+}
+
+// RegisterLimitMetricsHandler handler
+func RegisterLimitMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	res := swapapi.GetRegisterLimitMetrics()
+	writeResponse(w, res, nil)
+}
+
+// APITokenMetricsHandler handler
+func APITokenMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	res := swapapi.GetAPITokenMetrics()
+	writeResponse(w, res, nil)
+}
+
+// QuarantineMetricsHandler handler
+func QuarantineMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	res, err := swapapi.GetQuarantineMetrics()
+	writeResponse(w, res, err)
+}
+
+// DailyReportHandler handler
+func DailyReportHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	date := vars["date"]
+	res, err := swapapi.GetDailyReport(date)
+	writeResponse(w, res, err)
+}
+
+// AllSwapStatisticsHandler handler
+func AllSwapStatisticsHandler(w http.ResponseWriter, r *http.Request) {
+	res, err := swapapi.GetAllSwapStatistics()
+	writeResponse(w, res, err)
+}
+
+// PendingSwapCountsHandler handler
+func PendingSwapCountsHandler(w http.ResponseWriter, r *http.Request) {
+	res, err := swapapi.GetPendingSwapCounts()
+	writeResponse(w, res, err)
+}
+
+// SwapVolumeHistoryHandler handler
+func SwapVolumeHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	vals := r.URL.Query()
+	pairID := vars["pairid"]
+	interval := vals.Get("interval")
+	var from, to int
+	var err error
+	if fromStr := vals.Get("from"); fromStr != "" {
+		from, err = common.GetIntFromStr(fromStr)
+	}
+	if toStr := vals.Get("to"); toStr != "" && err == nil {
+		to, err = common.GetIntFromStr(toStr)
+	}
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	res, err := swapapi.GetSwapVolumeHistory(pairID, interval, int64(from), int64(to))
+	writeResponse(w, res, err)
+}
+
+// SwapEventsHandler handler
+func SwapEventsHandler(w http.ResponseWriter, r *http.Request) {
+	vals := r.URL.Query()
+	var sinceSeq, limit int
+	var err error
+	if sinceStr := vals.Get("since"); sinceStr != "" {
+		sinceSeq, err = common.GetIntFromStr(sinceStr)
+	}
+	if limitStr := vals.Get("limit"); limitStr != "" && err == nil {
+		limit, err = common.GetIntFromStr(limitStr)
+	}
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	res, err := swapapi.GetSwapEvents(int64(sinceSeq), limit)
+	writeResponse(w, res, err)
+}
+
 // StatusInfoHandler handler
 func StatusInfoHandler(w http.ResponseWriter, r *http.Request) {
 	var status string
@@ -73,6 +172,30 @@ func StatusInfoHandler(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, res, err)
 }
 
+// SigningKeyHandler handler
+func SigningKeyHandler(w http.ResponseWriter, r *http.Request) {
+	res, err := swapapi.GetSigningKey()
+	writeResponse(w, res, err)
+}
+
+// StatusCatalogHandler handler
+func StatusCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	res := swapapi.GetStatusCatalog()
+	writeResponse(w, res, nil)
+}
+
+// RegisterErrorTableHandler handler
+func RegisterErrorTableHandler(w http.ResponseWriter, r *http.Request) {
+	res := swapapi.GetRegisterErrorTable()
+	writeResponse(w, res, nil)
+}
+
+// NativePricesHandler handler
+func NativePricesHandler(w http.ResponseWriter, r *http.Request) {
+	res, err := swapapi.GetNativePrices()
+	writeResponse(w, res, err)
+}
+
 // TokenPairInfoHandler handler
 func TokenPairInfoHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -104,6 +227,19 @@ func getBindParam(r *http.Request) string {
 	return ""
 }
 
+// getIdempotencyKey idempotency key of mutation api is passed by header
+func getIdempotencyKey(r *http.Request) string {
+	return r.Header.Get("Idempotency-Key")
+}
+
+func isSignedParam(r *http.Request) bool {
+	return r.URL.Query().Get("signed") == "true"
+}
+
+func isVerboseParam(r *http.Request) bool {
+	return r.URL.Query().Get("verbose") == "true"
+}
+
 // GetRawSwapinHandler handler
 func GetRawSwapinHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -131,6 +267,37 @@ func GetSwapinHandler(w http.ResponseWriter, r *http.Request) {
 	pairID := vars["pairid"]
 	bind := getBindParam(r)
 	res, err := swapapi.GetSwapin(&txid, &pairID, &bind)
+	if err == nil && res != nil && isVerboseParam(r) {
+		swapapi.AddSwapConfirmations(res)
+	}
+	if err == nil && res != nil && isSignedParam(r) {
+		err = swapapi.SignSwapInfo(res)
+	}
+	writeResponse(w, res, err)
+}
+
+// GetSwapHandler handler
+func GetSwapHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	txid := vars["txid"]
+	pairID := vars["pairid"]
+	bind := getBindParam(r)
+	res, err := swapapi.GetSwap(&txid, &pairID, &bind)
+	if err == nil && isVerboseParam(r) {
+		for _, info := range res {
+			swapapi.AddSwapConfirmations(info)
+		}
+	}
+	writeResponse(w, res, err)
+}
+
+// GetSwapStatusHandler handler
+func GetSwapStatusHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	txid := vars["txid"]
+	pairID := vars["pairid"]
+	bind := getBindParam(r)
+	res, err := swapapi.GetSwapStatus(txid, pairID, bind)
 	writeResponse(w, res, err)
 }
 
@@ -161,15 +328,24 @@ func GetSwapoutHandler(w http.ResponseWriter, r *http.Request) {
 	pairID := vars["pairid"]
 	bind := getBindParam(r)
 	res, err := swapapi.GetSwapout(&txid, &pairID, &bind)
+	if err == nil && res != nil && isVerboseParam(r) {
+		swapapi.AddSwapConfirmations(res)
+	}
+	if err == nil && res != nil && isSignedParam(r) {
+		err = swapapi.SignSwapInfo(res)
+	}
 	writeResponse(w, res, err)
 }
 
 type historyParams struct {
-	address string
-	pairID  string
-	offset  int
-	limit   int
-	status  string
+	address  string
+	pairID   string
+	cursor   string
+	offset   int
+	limit    int
+	status   string
+	fromTime int64
+	toTime   int64
 }
 
 func getHistoryParams(r *http.Request) (p *historyParams, err error) {
@@ -197,11 +373,29 @@ func getHistoryParams(r *http.Request) (p *historyParams, err error) {
 		}
 	}
 
+	p.cursor = vals.Get("cursor")
+
 	statusStr, exist := vals["status"]
 	if exist {
 		p.status = statusStr[0]
 	}
 
+	if fromTimeStr, exist := vals["fromTime"]; exist {
+		fromTime, errt := common.GetIntFromStr(fromTimeStr[0])
+		if errt != nil {
+			return p, errt
+		}
+		p.fromTime = int64(fromTime)
+	}
+
+	if toTimeStr, exist := vals["toTime"]; exist {
+		toTime, errt := common.GetIntFromStr(toTimeStr[0])
+		if errt != nil {
+			return p, errt
+		}
+		p.toTime = int64(toTime)
+	}
+
 	return p, nil
 }
 
@@ -211,7 +405,7 @@ func SwapinHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		writeResponse(w, nil, err)
 	} else {
-		res, err := swapapi.GetSwapinHistory(p.address, p.pairID, p.offset, p.limit, p.status)
+		res, err := swapapi.GetSwapinHistory(p.address, p.pairID, p.offset, p.limit, p.status, p.fromTime, p.toTime)
 		writeResponse(w, res, err)
 	}
 }
@@ -222,7 +416,29 @@ func SwapoutHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		writeResponse(w, nil, err)
 	} else {
-		res, err := swapapi.GetSwapoutHistory(p.address, p.pairID, p.offset, p.limit, p.status)
+		res, err := swapapi.GetSwapoutHistory(p.address, p.pairID, p.offset, p.limit, p.status, p.fromTime, p.toTime)
+		writeResponse(w, res, err)
+	}
+}
+
+// SwapinHistoryPageHandler handler
+func SwapinHistoryPageHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := getHistoryParams(r)
+	if err != nil {
+		writeResponse(w, nil, err)
+	} else {
+		res, err := swapapi.GetSwapinHistoryPage(p.address, p.pairID, p.cursor, p.limit, p.status, p.fromTime, p.toTime)
+		writeResponse(w, res, err)
+	}
+}
+
+// SwapoutHistoryPageHandler handler
+func SwapoutHistoryPageHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := getHistoryParams(r)
+	if err != nil {
+		writeResponse(w, nil, err)
+	} else {
+		res, err := swapapi.GetSwapoutHistoryPage(p.address, p.pairID, p.cursor, p.limit, p.status, p.fromTime, p.toTime)
 		writeResponse(w, res, err)
 	}
 }
@@ -232,7 +448,9 @@ func PostSwapinHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	txid := vars["txid"]
 	pairID := vars["pairid"]
-	res, err := swapapi.Swapin(&txid, &pairID)
+	res, err := swapapi.CallIdempotent(swapapi.IdempotentMethodSwapin, getIdempotencyKey(r), pairID+":"+txid, func() (*swapapi.PostResult, error) {
+		return swapapi.Swapin(swapapi.ClientIPFromRequest(r.RemoteAddr, r.Header.Values("X-Forwarded-For")), &txid, &pairID)
+	})
 	writeResponse(w, res, err)
 }
 
@@ -245,12 +463,23 @@ func RetrySwapinHandler(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, res, err)
 }
 
+// RetrySwapoutHandler handler
+func RetrySwapoutHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	txid := vars["txid"]
+	pairID := vars["pairid"]
+	res, err := swapapi.RetrySwapout(&txid, &pairID)
+	writeResponse(w, res, err)
+}
+
 // PostP2shSwapinHandler handler
 func PostP2shSwapinHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	txid := vars["txid"]
 	bind := vars["bind"]
-	res, err := swapapi.P2shSwapin(&txid, &bind)
+	res, err := swapapi.CallIdempotent(swapapi.IdempotentMethodP2shSwapin, getIdempotencyKey(r), bind+":"+txid, func() (*swapapi.PostResult, error) {
+		return swapapi.P2shSwapin(swapapi.ClientIPFromRequest(r.RemoteAddr, r.Header.Values("X-Forwarded-For")), &txid, &bind)
+	})
 	writeResponse(w, res, err)
 }
 
@@ -259,7 +488,96 @@ func PostSwapoutHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	txid := vars["txid"]
 	pairID := vars["pairid"]
-	res, err := swapapi.Swapout(&txid, &pairID)
+	res, err := swapapi.CallIdempotent(swapapi.IdempotentMethodSwapout, getIdempotencyKey(r), pairID+":"+txid, func() (*swapapi.PostResult, error) {
+		return swapapi.Swapout(swapapi.ClientIPFromRequest(r.RemoteAddr, r.Header.Values("X-Forwarded-For")), &txid, &pairID)
+	})
+	writeResponse(w, res, err)
+}
+
+// getTxIDsParam get comma separated txids of url query
+func getTxIDsParam(r *http.Request) []string {
+	txids := r.URL.Query().Get("txids")
+	if txids == "" {
+		return nil
+	}
+	return strings.Split(txids, ",")
+}
+
+func getAddressesParam(r *http.Request) []string {
+	addresses := r.URL.Query().Get("addresses")
+	if addresses == "" {
+		return nil
+	}
+	return strings.Split(addresses, ",")
+}
+
+// SwapinBatchHandler handler
+func SwapinBatchHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	pairID := vars["pairid"]
+	res, err := swapapi.SwapinBatch(swapapi.ClientIPFromRequest(r.RemoteAddr, r.Header.Values("X-Forwarded-For")), getTxIDsParam(r), pairID)
+	writeResponse(w, res, err)
+}
+
+// SwapoutBatchHandler handler
+func SwapoutBatchHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	pairID := vars["pairid"]
+	res, err := swapapi.SwapoutBatch(swapapi.ClientIPFromRequest(r.RemoteAddr, r.Header.Values("X-Forwarded-For")), getTxIDsParam(r), pairID)
+	writeResponse(w, res, err)
+}
+
+// PrevalidateDepositHandler handler
+func PrevalidateDepositHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	pairID := vars["pairid"]
+	vals := r.URL.Query()
+	amount := vals.Get("amount")
+	bind := getBindParam(r)
+	depositType := vals.Get("type")
+	res, err := swapapi.PrevalidateDeposit(pairID, amount, bind, depositType)
+	writeResponse(w, res, err)
+}
+
+// GetSwapFeeEstimateHandler handler
+func GetSwapFeeEstimateHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	pairID := vars["pairid"]
+	direction := vars["direction"]
+	vals := r.URL.Query()
+	value := vals.Get("value")
+	bind := vals.Get("bind")
+	res, err := swapapi.GetSwapFeeEstimate(pairID, direction, value, bind)
+	writeResponse(w, res, err)
+}
+
+// ValidateBindAddressesHandler handler
+func ValidateBindAddressesHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	direction := vars["direction"]
+	res, err := swapapi.ValidateBindAddresses(direction, getAddressesParam(r))
+	writeResponse(w, res, err)
+}
+
+// ValidateBindAddressHandler handler
+func ValidateBindAddressHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	pairID := vars["pairid"]
+	address := vars["address"]
+	isSwapin := r.URL.Query().Get("direction") != "swapout"
+	res, err := swapapi.ValidateBindAddress(pairID, address, isSwapin)
+	writeResponse(w, res, err)
+}
+
+// DebugVerifyHandler handler
+func DebugVerifyHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	pairID := vars["pairid"]
+	txid := vars["txid"]
+	vals := r.URL.Query()
+	isSwapin := vals.Get("direction") != "swapout"
+	allowUnstable := vals.Get("unstable") == "true"
+	res, err := swapapi.PublicDebugVerifyTransaction(pairID, txid, isSwapin, allowUnstable)
 	writeResponse(w, res, err)
 }
 
@@ -279,6 +597,14 @@ func GetP2shAddressInfo(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, res, err)
 }
 
+// GetP2shBatchJob handler
+func GetP2shBatchJob(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	jobID := vars["jobid"]
+	res, err := swapapi.GetP2shBatchJob(jobID)
+	writeResponse(w, res, err)
+}
+
 // RegisterAddress handler
 func RegisterAddress(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -287,6 +613,42 @@ func RegisterAddress(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, res, err)
 }
 
+// GetP2shAddressByBindHandler handler
+func GetP2shAddressByBindHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	address := vars["address"]
+	res, err := swapapi.GetP2shAddressByBind(address)
+	writeResponse(w, res, err)
+}
+
+// RegisterAddressesHandler handler
+func RegisterAddressesHandler(w http.ResponseWriter, r *http.Request) {
+	res, err := swapapi.RegisterAddresses(getAddressesParam(r))
+	writeResponse(w, res, err)
+}
+
+// RegisterAddressChallengeHandler handler
+func RegisterAddressChallengeHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	address := vars["address"]
+	res, err := swapapi.GetRegisterAddressChallenge(address)
+	writeResponse(w, res, err)
+}
+
+// RegisterAddressWithProofHandler handler
+func RegisterAddressWithProofHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	vals := r.URL.Query()
+	address := vars["address"]
+	timestamp, err := common.GetIntFromStr(vals.Get("timestamp"))
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	res, err := swapapi.RegisterAddressWithProof(address, int64(timestamp), vals.Get("signature"))
+	writeResponse(w, res, err)
+}
+
 // GetRegisteredAddress handler
 func GetRegisteredAddress(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
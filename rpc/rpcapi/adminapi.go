@@ -1,16 +1,21 @@
 package rpcapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/admin"
 	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/internal/swapapi"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/mongodb"
 	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
 	"github.com/anyswap/CrossChain-Bridge/worker"
 )
 
@@ -22,6 +27,15 @@ const (
 	passSwapoutOp = "passswapout"
 	failSwapinOp  = "failswapin"
 	failSwapoutOp = "failswapout"
+
+	defaultDisagreementsLimit = 100
+	maxDisagreementsLimit     = 1000
+
+	defaultBigValuePendingLimit = 20
+	maxBigValuePendingLimit     = 100 // each pending checks sender on chain
+
+	defaultUnsupportedDepositsLimit = 100
+	maxUnsupportedDepositsLimit     = 1000
 )
 
 // AdminCall admin call
@@ -40,9 +54,9 @@ func (s *RPCAPI) AdminCall(r *http.Request, rawTx, result *string) (err error) {
 	senderAddress := sender.String()
 	if !params.IsAdmin(senderAddress) {
 		switch args.Method {
-		case "blacklist", "maintain", "reswap", "manual", "setnonce", "addpair":
+		case "blacklist", "maintain", "reswap", "manual", "setnonce", "addpair", "reconcile", "reloadgateway", "p2sh", "refund", "bulkregister", "dailyreport", "listregistered", mongodb.AdminPassSwapOp, mongodb.AdminFailSwapOp:
 			return fmt.Errorf("sender %v is not admin", senderAddress)
-		case "bigvalue", "reverify", "replaceswap":
+		case "bigvalue", "reverify", "replaceswap", "requeue", "addnote", "getnotes", "signattempts", "signsearch", "bulkjobstatus", "debugverify", "balancestatus", "adminaudits", "swapdetail", "audittrail", "disagreements", "bigvaluepending", "unsupporteddeposits":
 			if !params.IsAssistant(senderAddress) {
 				return fmt.Errorf("sender %v is not assistant", senderAddress)
 			}
@@ -51,10 +65,10 @@ func (s *RPCAPI) AdminCall(r *http.Request, rawTx, result *string) (err error) {
 		}
 	}
 	log.Info("admin call", "caller", senderAddress, "args", args, "result", result)
-	return doCall(args, result)
+	return doCall(senderAddress, args, result)
 }
 
-func doCall(args *admin.CallArgs, result *string) error {
+func doCall(caller string, args *admin.CallArgs, result *string) error {
 	switch args.Method {
 	case "blacklist":
 		return blacklist(args, result)
@@ -74,6 +88,50 @@ func doCall(args *admin.CallArgs, result *string) error {
 		return setnonce(args, result)
 	case "addpair":
 		return addpair(args, result)
+	case "requeue":
+		return requeue(args, result)
+	case "addnote":
+		return addnote(caller, args, result)
+	case "getnotes":
+		return getnotes(args, result)
+	case "reconcile":
+		return reconcile(caller, args, result)
+	case "signattempts":
+		return signattempts(args, result)
+	case "signsearch":
+		return signsearch(args, result)
+	case "reloadgateway":
+		return reloadgateway(args, result)
+	case "p2sh":
+		return p2sh(args, result)
+	case "refund":
+		return refund(caller, args, result)
+	case "bulkregister":
+		return bulkregister(args, result)
+	case "bulkjobstatus":
+		return bulkjobstatus(args, result)
+	case "debugverify":
+		return debugverify(args, result)
+	case "dailyreport":
+		return dailyreport(args, result)
+	case "balancestatus":
+		return balancestatus(args, result)
+	case "listregistered":
+		return listregistered(args, result)
+	case mongodb.AdminPassSwapOp, mongodb.AdminFailSwapOp:
+		return passOrFailSwap(caller, args, result)
+	case "adminaudits":
+		return adminaudits(args, result)
+	case "swapdetail":
+		return swapdetail(args, result)
+	case "audittrail":
+		return audittrail(args, result)
+	case "disagreements":
+		return disagreements(args, result)
+	case "bigvaluepending":
+		return bigvaluepending(args, result)
+	case "unsupporteddeposits":
+		return unsupporteddeposits(args, result)
 	default:
 		return fmt.Errorf("unknown admin method '%v'", args.Method)
 	}
@@ -169,12 +227,7 @@ func maintain(args *admin.CallArgs, result *string) (err error) {
 		return fmt.Errorf("unknown direction '%v'", direction)
 	}
 
-	var pairIDSlice []string
-	if strings.EqualFold(pairIDs, "all") {
-		pairIDSlice = tokens.GetAllPairIDs()
-	} else {
-		pairIDSlice = strings.Split(pairIDs, ",")
-	}
+	pairIDSlice := tokens.SplitPairIDs(pairIDs)
 
 	var successPairs, failedPairs string
 	for _, pairID := range pairIDSlice {
@@ -242,6 +295,606 @@ func reverify(args *admin.CallArgs, result *string) (err error) {
 	return nil
 }
 
+func requeue(args *admin.CallArgs, result *string) (err error) {
+	operation, txid, pairID, bind, err := getOpTxAndPairID(args)
+	if err != nil {
+		return err
+	}
+	switch operation {
+	case swapinOp:
+		err = mongodb.RequeueQuarantined(true, txid, pairID, bind)
+	case swapoutOp:
+		err = mongodb.RequeueQuarantined(false, txid, pairID, bind)
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	if err != nil {
+		return err
+	}
+	*result = successReuslt
+	return nil
+}
+
+func addnote(caller string, args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) != 5 {
+		return fmt.Errorf("wrong number of params, have %v want 5", len(args.Params))
+	}
+	operation := args.Params[0]
+	txid := args.Params[1]
+	pairID := args.Params[2]
+	bind := args.Params[3]
+	note := args.Params[4]
+	switch operation {
+	case swapinOp:
+		err = mongodb.AddSwapNote(true, txid, pairID, bind, caller, note)
+	case swapoutOp:
+		err = mongodb.AddSwapNote(false, txid, pairID, bind, caller, note)
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	if err != nil {
+		return err
+	}
+	*result = successReuslt
+	return nil
+}
+
+func getnotes(args *admin.CallArgs, result *string) (err error) {
+	operation, txid, pairID, bind, err := getOpTxAndPairID(args)
+	if err != nil {
+		return err
+	}
+	var notes []*mongodb.MgoSwapNote
+	switch operation {
+	case swapinOp:
+		notes, err = mongodb.GetSwapNotes(true, txid, pairID, bind)
+	case swapoutOp:
+		notes, err = mongodb.GetSwapNotes(false, txid, pairID, bind)
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(notes)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+func reloadgateway(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) != 0 {
+		return fmt.Errorf("wrong number of params, have %v want 0", len(args.Params))
+	}
+	srcGateway, dstGateway, err := params.LoadGatewayConfigs()
+	if err != nil {
+		return err
+	}
+	srcResult, err := tools.ReloadGateway(true, srcGateway)
+	if err != nil {
+		return fmt.Errorf("reload source gateway failed, %w", err)
+	}
+	dstResult, err := tools.ReloadGateway(false, dstGateway)
+	if err != nil {
+		return fmt.Errorf("reload dest gateway failed, %w", err)
+	}
+	data, err := json.Marshal([]*tools.GatewayReloadResult{srcResult, dstResult})
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+// SignAttemptsResult sign attempts of swap result
+type SignAttemptsResult struct {
+	Attempts          []*mongodb.MgoSignAttempt `json:"attempts"`
+	CurrentAttemptAge int64                     `json:"currentAttemptAge,omitempty"` // seconds, if still signing
+}
+
+func signattempts(args *admin.CallArgs, result *string) (err error) {
+	operation, txid, pairID, bind, err := getOpTxAndPairID(args)
+	if err != nil {
+		return err
+	}
+	var res *mongodb.MgoSwapResult
+	switch operation {
+	case swapinOp:
+		res, err = mongodb.FindSwapinResult(txid, pairID, bind)
+	case swapoutOp:
+		res, err = mongodb.FindSwapoutResult(txid, pairID, bind)
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	if err != nil {
+		return err
+	}
+	attempts := &SignAttemptsResult{
+		Attempts:          res.SignAttempts,
+		CurrentAttemptAge: swapapi.CurrentSignAttemptAge(res.SignAttempts, time.Now().Unix()),
+	}
+	data, err := json.Marshal(attempts)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+// SignSearchItem swap result with sign attempts of the searched initiator
+type SignSearchItem struct {
+	TxID     string                    `json:"txid"`
+	PairID   string                    `json:"pairid"`
+	Bind     string                    `json:"bind"`
+	Status   mongodb.SwapStatus        `json:"status"`
+	Attempts []*mongodb.MgoSignAttempt `json:"attempts"`
+}
+
+func signsearch(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) < 2 || len(args.Params) > 4 {
+		return fmt.Errorf("wrong number of params, have %v want 2 to 4", len(args.Params))
+	}
+	operation := args.Params[0]
+	initiator := args.Params[1]
+	offset, limit := 0, 20
+	if len(args.Params) > 2 {
+		if offset, err = strconv.Atoi(args.Params[2]); err != nil || offset < 0 {
+			return fmt.Errorf("wrong offset '%v'", args.Params[2])
+		}
+	}
+	if len(args.Params) > 3 {
+		if limit, err = strconv.Atoi(args.Params[3]); err != nil || limit <= 0 || limit > 100 {
+			return fmt.Errorf("wrong limit '%v', should be in range [1, 100]", args.Params[3])
+		}
+	}
+	var res []*mongodb.MgoSwapResult
+	switch operation {
+	case swapinOp:
+		res, err = mongodb.FindSwapResultsBySignInitiator(true, initiator, offset, limit)
+	case swapoutOp:
+		res, err = mongodb.FindSwapResultsBySignInitiator(false, initiator, offset, limit)
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	if err != nil {
+		return err
+	}
+	items := make([]*SignSearchItem, 0, len(res))
+	for _, swap := range res {
+		item := &SignSearchItem{TxID: swap.TxID, PairID: swap.PairID, Bind: swap.Bind, Status: swap.Status}
+		for _, attempt := range swap.SignAttempts {
+			if strings.EqualFold(attempt.Initiator, initiator) {
+				item.Attempts = append(item.Attempts, attempt)
+			}
+		}
+		items = append(items, item)
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+func reconcile(caller string, args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) != 1 {
+		return fmt.Errorf("wrong number of params, have %v want 1", len(args.Params))
+	}
+	operation := args.Params[0]
+	switch operation {
+	case "report":
+		report, err := worker.GetReconcileReport()
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		*result = string(data)
+	case "confirm":
+		err = worker.ConfirmReconcile(caller)
+		if err != nil {
+			return err
+		}
+		*result = successReuslt
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	return nil
+}
+
+func refund(caller string, args *admin.CallArgs, result *string) (err error) {
+	operation, txid, pairID, bind, err := getOpTxAndPairID(args)
+	if err != nil {
+		return err
+	}
+	var refundResult *worker.RefundResult
+	switch operation {
+	case swapinOp:
+		refundResult, err = worker.InitiateRefund(caller, txid, pairID, bind, true)
+	case swapoutOp:
+		refundResult, err = worker.InitiateRefund(caller, txid, pairID, bind, false)
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(refundResult)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+func p2sh(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) == 0 {
+		return fmt.Errorf("wrong number of params, have 0 want at least 1")
+	}
+	operation := args.Params[0]
+	switch operation {
+	case "stats":
+		stats, err := worker.GetP2shAddressStats()
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(stats)
+		if err != nil {
+			return err
+		}
+		*result = string(data)
+	case "reactivate":
+		if len(args.Params) != 2 {
+			return fmt.Errorf("wrong number of params, have %v want 2", len(args.Params))
+		}
+		backfilled, err := worker.ReactivateP2shAddress(args.Params[1])
+		if err != nil {
+			return err
+		}
+		*result = fmt.Sprintf("%v, backfilled %v txs", successReuslt, backfilled)
+	case "list":
+		offset, limit, err := getOffsetAndLimit(args.Params[1:])
+		if err != nil {
+			return err
+		}
+		list, err := swapapi.ListP2shAddresses(offset, limit)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(list)
+		if err != nil {
+			return err
+		}
+		*result = string(data)
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	return nil
+}
+
+// getOffsetAndLimit get optional offset and limit params
+func getOffsetAndLimit(params []string) (offset, limit int, err error) {
+	if len(params) > 2 {
+		return 0, 0, fmt.Errorf("wrong number of params, have %v want at most 2", len(params))
+	}
+	if len(params) > 0 {
+		if offset, err = strconv.Atoi(params[0]); err != nil {
+			return 0, 0, fmt.Errorf("wrong offset '%v'", params[0])
+		}
+	}
+	if len(params) > 1 {
+		if limit, err = strconv.Atoi(params[1]); err != nil {
+			return 0, 0, fmt.Errorf("wrong limit '%v'", params[1])
+		}
+	}
+	return offset, limit, nil
+}
+
+func bulkregister(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) < 3 {
+		return fmt.Errorf("wrong number of params, have %v want at least 3", len(args.Params))
+	}
+	direction := args.Params[0]
+	pairID := args.Params[1]
+	txids := args.Params[2:]
+	jobID, err := swapapi.BulkRegister(pairID, direction, txids)
+	if err != nil {
+		return err
+	}
+	*result = jobID
+	return nil
+}
+
+func bulkjobstatus(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) != 1 {
+		return fmt.Errorf("wrong number of params, have %v want 1", len(args.Params))
+	}
+	job, err := swapapi.GetBulkJobStatus(args.Params[0])
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+func debugverify(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) < 3 || len(args.Params) > 4 {
+		return fmt.Errorf("wrong number of params, have %v want 3 or 4", len(args.Params))
+	}
+	var isSwapin bool
+	switch args.Params[0] {
+	case "swapin":
+		isSwapin = true
+	case "swapout":
+		isSwapin = false
+	default:
+		return fmt.Errorf("unknown direction '%v'", args.Params[0])
+	}
+	pairID := args.Params[1]
+	txid := args.Params[2]
+	allowUnstable := len(args.Params) == 4 && args.Params[3] == "unstable"
+	res, err := swapapi.DebugVerifyTransaction(pairID, txid, isSwapin, allowUnstable)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+func dailyreport(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) != 1 {
+		return fmt.Errorf("wrong number of params, have %v want 1", len(args.Params))
+	}
+	report, err := worker.GenerateDailyReport(args.Params[0])
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+func balancestatus(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) != 0 {
+		return fmt.Errorf("wrong number of params, have %v want 0", len(args.Params))
+	}
+	data, err := json.Marshal(worker.GetBalanceStatuses())
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+func disagreements(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) > 1 {
+		return fmt.Errorf("wrong number of params, have %v want at most 1", len(args.Params))
+	}
+	limit := defaultDisagreementsLimit
+	if len(args.Params) > 0 {
+		if limit, err = strconv.Atoi(args.Params[0]); err != nil || limit <= 0 {
+			return fmt.Errorf("wrong limit '%v'", args.Params[0])
+		}
+		if limit > maxDisagreementsLimit {
+			limit = maxDisagreementsLimit
+		}
+	}
+	grouped, err := worker.GetRecentSignDisagreements(limit)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(grouped)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+func bigvaluepending(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) < 1 {
+		return fmt.Errorf("wrong number of params, have %v want at least 1", len(args.Params))
+	}
+	var isSwapin bool
+	switch args.Params[0] {
+	case swapinOp:
+		isSwapin = true
+	case swapoutOp:
+	default:
+		return fmt.Errorf("unknown operation '%v'", args.Params[0])
+	}
+	offset, limit, err := getOffsetAndLimit(args.Params[1:])
+	if err != nil {
+		return err
+	}
+	if limit <= 0 {
+		limit = defaultBigValuePendingLimit
+	} else if limit > maxBigValuePendingLimit {
+		limit = maxBigValuePendingLimit
+	}
+	pendings, err := worker.ListBigValuePendings(isSwapin, offset, limit)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(pendings)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+func unsupporteddeposits(args *admin.CallArgs, result *string) (err error) {
+	offset, limit, err := getOffsetAndLimit(args.Params)
+	if err != nil {
+		return err
+	}
+	if limit <= 0 {
+		limit = defaultUnsupportedDepositsLimit
+	} else if limit > maxUnsupportedDepositsLimit {
+		limit = maxUnsupportedDepositsLimit
+	}
+	deposits, err := mongodb.ListUnsupportedDeposits(offset, limit)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(deposits)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+func listregistered(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) > 3 {
+		return fmt.Errorf("wrong number of params, have %v want at most 3", len(args.Params))
+	}
+	var prefix string
+	var pageParams []string
+	if len(args.Params) > 0 {
+		prefix = args.Params[0]
+		pageParams = args.Params[1:]
+	}
+	offset, limit, err := getOffsetAndLimit(pageParams)
+	if err != nil {
+		return err
+	}
+	list, err := swapapi.ListRegisteredAddresses(offset, limit, prefix, 0, 0)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+func passOrFailSwap(caller string, args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) != 5 {
+		return fmt.Errorf("wrong number of params, have %v want 5", len(args.Params))
+	}
+	operation := args.Params[0]
+	txid := args.Params[1]
+	pairID := args.Params[2]
+	bind := args.Params[3]
+	reason := args.Params[4]
+	var isSwapin bool
+	switch operation {
+	case swapinOp:
+		isSwapin = true
+	case swapoutOp:
+		isSwapin = false
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	var status mongodb.SwapStatus
+	if args.Method == mongodb.AdminPassSwapOp {
+		status, err = mongodb.AdminPassSwap(caller, txid, pairID, bind, reason, isSwapin)
+	} else {
+		status, err = mongodb.AdminFailSwap(caller, txid, pairID, bind, reason, isSwapin)
+	}
+	if err != nil {
+		return err
+	}
+	worker.DeleteCachedSwap(isSwapin, txid, bind)
+	*result = successReuslt + ", new status is " + status.String()
+	return nil
+}
+
+func adminaudits(args *admin.CallArgs, result *string) (err error) {
+	operation, txid, pairID, bind, err := getOpTxAndPairID(args)
+	if err != nil {
+		return err
+	}
+	var audits []*mongodb.MgoAdminAudit
+	switch operation {
+	case swapinOp:
+		audits, err = mongodb.GetAdminAudits(true, txid, pairID, bind)
+	case swapoutOp:
+		audits, err = mongodb.GetAdminAudits(false, txid, pairID, bind)
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(audits)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+func swapdetail(args *admin.CallArgs, result *string) (err error) {
+	operation, txid, pairID, bind, err := getOpTxAndPairID(args)
+	if err != nil {
+		return err
+	}
+	var detail *swapapi.SwapDetail
+	switch operation {
+	case swapinOp:
+		detail, err = swapapi.GetSwapDetail(true, txid, pairID, bind)
+	case swapoutOp:
+		detail, err = swapapi.GetSwapDetail(false, txid, pairID, bind)
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(detail)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
+func audittrail(args *admin.CallArgs, result *string) (err error) {
+	operation, txid, pairID, bind, err := getOpTxAndPairID(args)
+	if err != nil {
+		return err
+	}
+	var trail []*swapapi.AuditTrailEntry
+	switch operation {
+	case swapinOp:
+		trail, err = swapapi.ExportAuditTrail(true, txid, pairID, bind)
+	case swapoutOp:
+		trail, err = swapapi.ExportAuditTrail(false, txid, pairID, bind)
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(trail)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
 func reswap(args *admin.CallArgs, result *string) (err error) {
 	operation, txid, pairID, bind, err := getOpTxAndPairID(args)
 	if err != nil {
@@ -33,15 +33,25 @@ func (s *RPCAPI) GetServerInfo(r *http.Request, args *RPCNullArgs, result *swapa
 	return err
 }
 
+// GetHealth api
+func (s *RPCAPI) GetHealth(r *http.Request, args *RPCNullArgs, result *swapapi.HealthInfo) error {
+	res, err := swapapi.GetHealth()
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
 // HeartbeatArgs heartbeat args
 type HeartbeatArgs struct {
-	Enode     string `json:"enode"`
-	Timestamp int64  `json:"timestamp"`
+	Enode     string                   `json:"enode"`
+	Timestamp int64                    `json:"timestamp"`
+	AcceptJob *swapapi.AcceptJobStatus `json:"acceptJob,omitempty"`
 }
 
 // UpdateOracleHeartbeat api
 func (s *RPCAPI) UpdateOracleHeartbeat(r *http.Request, args *HeartbeatArgs, result *string) error {
-	err := swapapi.UpdateOracleHeartbeat(args.Enode, args.Timestamp)
+	err := swapapi.UpdateOracleHeartbeat(args.Enode, args.Timestamp, args.AcceptJob)
 	if err != nil {
 		return err
 	}
@@ -55,6 +65,98 @@ func (s *RPCAPI) GetOraclesHeartbeat(r *http.Request, args *RPCNullArgs, result
 	return nil
 }
 
+// GetOraclesJobStatus api
+func (s *RPCAPI) GetOraclesJobStatus(r *http.Request, args *RPCNullArgs, result *map[string]*swapapi.AcceptJobStatus) error {
+	*result = swapapi.GetOraclesJobStatus()
+	return nil
+}
+
+// GetRetryMetrics api
+func (s *RPCAPI) GetRetryMetrics(r *http.Request, args *RPCNullArgs, result *map[string]*swapapi.RetryMetrics) error {
+	*result = swapapi.GetRetryMetrics()
+	return nil
+}
+
+// GetRegisterLimitMetrics api
+func (s *RPCAPI) GetRegisterLimitMetrics(r *http.Request, args *RPCNullArgs, result *map[string]*swapapi.RegisterLimitMetrics) error {
+	*result = swapapi.GetRegisterLimitMetrics()
+	return nil
+}
+
+// GetAPITokenMetrics api
+func (s *RPCAPI) GetAPITokenMetrics(r *http.Request, args *RPCNullArgs, result *map[string]*swapapi.APITokenMetrics) error {
+	*result = swapapi.GetAPITokenMetrics()
+	return nil
+}
+
+// GetQuarantineMetrics api
+func (s *RPCAPI) GetQuarantineMetrics(r *http.Request, args *RPCNullArgs, result *swapapi.QuarantineMetrics) error {
+	res, err := swapapi.GetQuarantineMetrics()
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// GetDailyReport api
+func (s *RPCAPI) GetDailyReport(r *http.Request, date *string, result *swapapi.DailyReport) error {
+	res, err := swapapi.GetDailyReport(*date)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// GetAllSwapStatistics api
+func (s *RPCAPI) GetAllSwapStatistics(r *http.Request, args *RPCNullArgs, result *swapapi.AllSwapStatistics) error {
+	res, err := swapapi.GetAllSwapStatistics()
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// GetPendingSwapCounts api
+func (s *RPCAPI) GetPendingSwapCounts(r *http.Request, args *RPCNullArgs, result *swapapi.AllPendingCounts) error {
+	res, err := swapapi.GetPendingSwapCounts()
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// RPCGetSwapVolumeHistoryArgs args
+type RPCGetSwapVolumeHistoryArgs struct {
+	PairID   string `json:"pairid"`
+	Interval string `json:"interval"`
+	From     int64  `json:"from"`
+	To       int64  `json:"to"`
+}
+
+// GetSwapVolumeHistory api
+func (s *RPCAPI) GetSwapVolumeHistory(r *http.Request, args *RPCGetSwapVolumeHistoryArgs, result *swapapi.SwapVolumeHistory) error {
+	res, err := swapapi.GetSwapVolumeHistory(args.PairID, args.Interval, args.From, args.To)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// RPCGetSwapEventsArgs args
+type RPCGetSwapEventsArgs struct {
+	SinceSeq int64 `json:"sinceSeq"`
+	Limit    int   `json:"limit"`
+}
+
+// GetSwapEvents api
+func (s *RPCAPI) GetSwapEvents(r *http.Request, args *RPCGetSwapEventsArgs, result *[]*swapapi.SwapEvent) error {
+	res, err := swapapi.GetSwapEvents(args.SinceSeq, args.Limit)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
 // GetStatusInfo api
 func (s *RPCAPI) GetStatusInfo(r *http.Request, statuses *string, result *map[string]map[string]interface{}) error {
 	res, err := swapapi.GetStatusInfo(*statuses)
@@ -64,6 +166,36 @@ func (s *RPCAPI) GetStatusInfo(r *http.Request, statuses *string, result *map[st
 	return err
 }
 
+// GetSigningKey api
+func (s *RPCAPI) GetSigningKey(r *http.Request, args *RPCNullArgs, result *swapapi.SigningKeyInfo) error {
+	res, err := swapapi.GetSigningKey()
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// GetStatusCatalog api
+func (s *RPCAPI) GetStatusCatalog(r *http.Request, args *RPCNullArgs, result *[]*swapapi.SwapStatusInfo) error {
+	*result = swapapi.GetStatusCatalog()
+	return nil
+}
+
+// GetRegisterErrorTable api
+func (s *RPCAPI) GetRegisterErrorTable(r *http.Request, args *RPCNullArgs, result *[]*swapapi.RegisterErrorEntry) error {
+	*result = swapapi.GetRegisterErrorTable()
+	return nil
+}
+
+// GetNativePrices api
+func (s *RPCAPI) GetNativePrices(r *http.Request, args *RPCNullArgs, result *swapapi.NativePricesInfo) error {
+	res, err := swapapi.GetNativePrices()
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
 // GetTokenPairInfo api
 func (s *RPCAPI) GetTokenPairInfo(r *http.Request, pairID *string, result *tokens.TokenPairConfig) error {
 	res, err := swapapi.GetTokenPairInfo(*pairID)
@@ -97,6 +229,13 @@ type RPCTxAndPairIDArgs struct {
 	TxID   string `json:"txid"`
 	PairID string `json:"pairid"`
 	Bind   string `json:"bind"`
+	Signed bool   `json:"signed"`
+
+	// add deposit tx confirmations to swap info
+	Verbose bool `json:"verbose,omitempty"`
+
+	// optional client generated key to make mutation api idempotent
+	IdempotencyKey string `json:"idempotencykey,omitempty"`
 }
 
 func (args *RPCTxAndPairIDArgs) getTxAndPairID() (txid, pairID, bind *string, err error) {
@@ -145,6 +284,43 @@ func (s *RPCAPI) GetSwapin(r *http.Request, args *RPCTxAndPairIDArgs, result *sw
 		return err
 	}
 	res, err := swapapi.GetSwapin(txid, pairID, bind)
+	if err == nil && res != nil {
+		if args.Verbose {
+			swapapi.AddSwapConfirmations(res)
+		}
+		if args.Signed {
+			err = swapapi.SignSwapInfo(res)
+		}
+		*result = *res
+	}
+	return err
+}
+
+// GetSwap api
+func (s *RPCAPI) GetSwap(r *http.Request, args *RPCTxAndPairIDArgs, result *[]*swapapi.SwapInfo) error {
+	txid, pairID, bind, err := args.getTxAndPairID()
+	if err != nil {
+		return err
+	}
+	res, err := swapapi.GetSwap(txid, pairID, bind)
+	if err == nil && res != nil {
+		if args.Verbose {
+			for _, info := range res {
+				swapapi.AddSwapConfirmations(info)
+			}
+		}
+		*result = res
+	}
+	return err
+}
+
+// GetSwapStatus api
+func (s *RPCAPI) GetSwapStatus(r *http.Request, args *RPCTxAndPairIDArgs, result *swapapi.SwapPollStatus) error {
+	txid, pairID, bind, err := args.getTxAndPairID()
+	if err != nil {
+		return err
+	}
+	res, err := swapapi.GetSwapStatus(*txid, *pairID, *bind)
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -185,6 +361,12 @@ func (s *RPCAPI) GetSwapout(r *http.Request, args *RPCTxAndPairIDArgs, result *s
 	}
 	res, err := swapapi.GetSwapout(txid, pairID, bind)
 	if err == nil && res != nil {
+		if args.Verbose {
+			swapapi.AddSwapConfirmations(res)
+		}
+		if args.Signed {
+			err = swapapi.SignSwapInfo(res)
+		}
 		*result = *res
 	}
 	return err
@@ -197,11 +379,15 @@ type RPCQueryHistoryArgs struct {
 	Offset  int    `json:"offset"`
 	Limit   int    `json:"limit"`
 	Status  string `json:"status"`
+
+	// optional time range (unix seconds) of swap timestamp
+	FromTime int64 `json:"fromTime,omitempty"`
+	ToTime   int64 `json:"toTime,omitempty"`
 }
 
 // GetSwapinHistory api
 func (s *RPCAPI) GetSwapinHistory(r *http.Request, args *RPCQueryHistoryArgs, result *[]*swapapi.SwapInfo) error {
-	res, err := swapapi.GetSwapinHistory(args.Address, args.PairID, args.Offset, args.Limit, args.Status)
+	res, err := swapapi.GetSwapinHistory(args.Address, args.PairID, args.Offset, args.Limit, args.Status, args.FromTime, args.ToTime)
 	if err == nil && res != nil {
 		*result = res
 	}
@@ -210,13 +396,44 @@ func (s *RPCAPI) GetSwapinHistory(r *http.Request, args *RPCQueryHistoryArgs, re
 
 // GetSwapoutHistory api
 func (s *RPCAPI) GetSwapoutHistory(r *http.Request, args *RPCQueryHistoryArgs, result *[]*swapapi.SwapInfo) error {
-	res, err := swapapi.GetSwapoutHistory(args.Address, args.PairID, args.Offset, args.Limit, args.Status)
+	res, err := swapapi.GetSwapoutHistory(args.Address, args.PairID, args.Offset, args.Limit, args.Status, args.FromTime, args.ToTime)
 	if err == nil && res != nil {
 		*result = res
 	}
 	return err
 }
 
+// RPCQueryHistoryPageArgs args
+type RPCQueryHistoryPageArgs struct {
+	Address string `json:"address"`
+	PairID  string `json:"pairid"`
+	Cursor  string `json:"cursor"`
+	Limit   int    `json:"limit"`
+	Status  string `json:"status"`
+
+	// optional time range (unix seconds) of swap timestamp
+	FromTime int64 `json:"fromTime,omitempty"`
+	ToTime   int64 `json:"toTime,omitempty"`
+}
+
+// GetSwapinHistoryPage api
+func (s *RPCAPI) GetSwapinHistoryPage(r *http.Request, args *RPCQueryHistoryPageArgs, result *swapapi.SwapHistoryPage) error {
+	res, err := swapapi.GetSwapinHistoryPage(args.Address, args.PairID, args.Cursor, args.Limit, args.Status, args.FromTime, args.ToTime)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// GetSwapoutHistoryPage api
+func (s *RPCAPI) GetSwapoutHistoryPage(r *http.Request, args *RPCQueryHistoryPageArgs, result *swapapi.SwapHistoryPage) error {
+	res, err := swapapi.GetSwapoutHistoryPage(args.Address, args.PairID, args.Cursor, args.Limit, args.Status, args.FromTime, args.ToTime)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
 // Swapin api
 func (s *RPCAPI) Swapin(r *http.Request, args *RPCTxAndPairIDArgs, result *swapapi.PostResult) error {
 	txid, pairID, _, err := args.getTxAndPairID()
@@ -224,7 +441,9 @@ func (s *RPCAPI) Swapin(r *http.Request, args *RPCTxAndPairIDArgs, result *swapa
 		return err
 	}
 	log.Infof("111111\nRPC Swapin\n111111")
-	res, err := swapapi.Swapin(txid, pairID)
+	res, err := swapapi.CallIdempotent(swapapi.IdempotentMethodSwapin, args.IdempotencyKey, *pairID+":"+*txid, func() (*swapapi.PostResult, error) {
+		return swapapi.Swapin(swapapi.ClientIPFromRequest(r.RemoteAddr, r.Header.Values("X-Forwarded-For")), txid, pairID)
+	})
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -244,15 +463,56 @@ func (s *RPCAPI) RetrySwapin(r *http.Request, args *RPCTxAndPairIDArgs, result *
 	return err
 }
 
+// RetrySwapout api
+func (s *RPCAPI) RetrySwapout(r *http.Request, args *RPCTxAndPairIDArgs, result *swapapi.PostResult) error {
+	txid, pairID, _, err := args.getTxAndPairID()
+	if err != nil {
+		return err
+	}
+	res, err := swapapi.RetrySwapout(txid, pairID)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// RPCSwapBatchArgs args
+type RPCSwapBatchArgs struct {
+	TxIDs  []string `json:"txids"`
+	PairID string   `json:"pairid"`
+}
+
+// SwapinBatch api
+func (s *RPCAPI) SwapinBatch(r *http.Request, args *RPCSwapBatchArgs, result *swapapi.SwapBatchResult) error {
+	res, err := swapapi.SwapinBatch(swapapi.ClientIPFromRequest(r.RemoteAddr, r.Header.Values("X-Forwarded-For")), args.TxIDs, args.PairID)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// SwapoutBatch api
+func (s *RPCAPI) SwapoutBatch(r *http.Request, args *RPCSwapBatchArgs, result *swapapi.SwapBatchResult) error {
+	res, err := swapapi.SwapoutBatch(swapapi.ClientIPFromRequest(r.RemoteAddr, r.Header.Values("X-Forwarded-For")), args.TxIDs, args.PairID)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
 // RPCP2shSwapinArgs args
 type RPCP2shSwapinArgs struct {
 	TxID string `json:"txid"`
 	Bind string `json:"bind"`
+
+	IdempotencyKey string `json:"idempotencykey,omitempty"`
 }
 
 // P2shSwapin api
 func (s *RPCAPI) P2shSwapin(r *http.Request, args *RPCP2shSwapinArgs, result *swapapi.PostResult) error {
-	res, err := swapapi.P2shSwapin(&args.TxID, &args.Bind)
+	res, err := swapapi.CallIdempotent(swapapi.IdempotentMethodP2shSwapin, args.IdempotencyKey, args.Bind+":"+args.TxID, func() (*swapapi.PostResult, error) {
+		return swapapi.P2shSwapin(swapapi.ClientIPFromRequest(r.RemoteAddr, r.Header.Values("X-Forwarded-For")), &args.TxID, &args.Bind)
+	})
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -265,7 +525,60 @@ func (s *RPCAPI) Swapout(r *http.Request, args *RPCTxAndPairIDArgs, result *swap
 	if err != nil {
 		return err
 	}
-	res, err := swapapi.Swapout(txid, pairID)
+	res, err := swapapi.CallIdempotent(swapapi.IdempotentMethodSwapout, args.IdempotencyKey, *pairID+":"+*txid, func() (*swapapi.PostResult, error) {
+		return swapapi.Swapout(swapapi.ClientIPFromRequest(r.RemoteAddr, r.Header.Values("X-Forwarded-For")), txid, pairID)
+	})
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// RPCPrevalidateDepositArgs args
+type RPCPrevalidateDepositArgs struct {
+	PairID      string `json:"pairid"`
+	Amount      string `json:"amount"`
+	Bind        string `json:"bind"`
+	DepositType string `json:"depositType"`
+}
+
+// PrevalidateDeposit api
+func (s *RPCAPI) PrevalidateDeposit(r *http.Request, args *RPCPrevalidateDepositArgs, result *swapapi.PrevalidateResult) error {
+	res, err := swapapi.PrevalidateDeposit(args.PairID, args.Amount, args.Bind, args.DepositType)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// RPCGetSwapFeeEstimateArgs args
+type RPCGetSwapFeeEstimateArgs struct {
+	PairID    string `json:"pairid"`
+	Direction string `json:"direction"`
+	Value     string `json:"value"`
+	Bind      string `json:"bind"`
+}
+
+// GetSwapFeeEstimate api
+func (s *RPCAPI) GetSwapFeeEstimate(r *http.Request, args *RPCGetSwapFeeEstimateArgs, result *swapapi.SwapFeeEstimate) error {
+	res, err := swapapi.GetSwapFeeEstimate(args.PairID, args.Direction, args.Value, args.Bind)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// RPCDebugVerifyArgs args
+type RPCDebugVerifyArgs struct {
+	PairID        string `json:"pairid"`
+	TxID          string `json:"txid"`
+	IsSwapin      bool   `json:"isSwapin"`
+	AllowUnstable bool   `json:"allowUnstable"`
+}
+
+// DebugVerifyTransaction api
+func (s *RPCAPI) DebugVerifyTransaction(r *http.Request, args *RPCDebugVerifyArgs, result *swapapi.DebugVerifyResult) error {
+	res, err := swapapi.PublicDebugVerifyTransaction(args.PairID, args.TxID, args.IsSwapin, args.AllowUnstable)
 	if err == nil && res != nil {
 		*result = *res
 	}
@@ -284,6 +597,37 @@ func (s *RPCAPI) IsValidSwapoutBindAddress(r *http.Request, address *string, res
 	return nil
 }
 
+// RPCValidateBindAddressArgs args
+type RPCValidateBindAddressArgs struct {
+	PairID   string `json:"pairid"`
+	Address  string `json:"address"`
+	IsSwapin bool   `json:"isSwapin"`
+}
+
+// ValidateBindAddress api
+func (s *RPCAPI) ValidateBindAddress(r *http.Request, args *RPCValidateBindAddressArgs, result *swapapi.BindAddressValidation) error {
+	res, err := swapapi.ValidateBindAddress(args.PairID, args.Address, args.IsSwapin)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// RPCValidateBindAddressesArgs args
+type RPCValidateBindAddressesArgs struct {
+	Direction string   `json:"direction"`
+	Addresses []string `json:"addresses"`
+}
+
+// ValidateBindAddresses api
+func (s *RPCAPI) ValidateBindAddresses(r *http.Request, args *RPCValidateBindAddressesArgs, result *swapapi.BindAddressBatchValidation) error {
+	res, err := swapapi.ValidateBindAddresses(args.Direction, args.Addresses)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
 // RegisterP2shAddress api
 func (s *RPCAPI) RegisterP2shAddress(r *http.Request, bindAddress *string, result *tokens.P2shAddressInfo) error {
 	res, err := swapapi.RegisterP2shAddress(*bindAddress)
@@ -302,6 +646,48 @@ func (s *RPCAPI) GetP2shAddressInfo(r *http.Request, p2shAddress *string, result
 	return err
 }
 
+// GetP2shAddressByBind api
+func (s *RPCAPI) GetP2shAddressByBind(r *http.Request, bindAddress *string, result *swapapi.P2shAddress) error {
+	res, err := swapapi.GetP2shAddressByBind(*bindAddress)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// RPCListP2shAddressesArgs args
+type RPCListP2shAddressesArgs struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// ListP2shAddresses api, requires api token
+func (s *RPCAPI) ListP2shAddresses(r *http.Request, args *RPCListP2shAddressesArgs, result *[]*swapapi.P2shAddress) error {
+	res, err := swapapi.ListP2shAddresses(args.Offset, args.Limit)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// RegisterP2shAddressBatch api
+func (s *RPCAPI) RegisterP2shAddressBatch(r *http.Request, bindAddresses *[]string, result *string) error {
+	res, err := swapapi.RegisterP2shAddressBatch(*bindAddresses)
+	if err == nil {
+		*result = res
+	}
+	return err
+}
+
+// GetP2shBatchJob api
+func (s *RPCAPI) GetP2shBatchJob(r *http.Request, jobID *string, result *swapapi.P2shBatchJob) error {
+	res, err := swapapi.GetP2shBatchJob(*jobID)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
 // GetLatestScanInfo api
 func (s *RPCAPI) GetLatestScanInfo(r *http.Request, isSrc *bool, result *swapapi.LatestScanInfo) error {
 	res, err := swapapi.GetLatestScanInfo(*isSrc)
@@ -320,6 +706,40 @@ func (s *RPCAPI) RegisterAddress(r *http.Request, address *string, result *swapa
 	return err
 }
 
+// RegisterAddresses api
+func (s *RPCAPI) RegisterAddresses(r *http.Request, addresses *[]string, result *swapapi.AddressBatchResult) error {
+	res, err := swapapi.RegisterAddresses(*addresses)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// RPCRegisterAddressWithProofArgs args
+type RPCRegisterAddressWithProofArgs struct {
+	Address   string `json:"address"`
+	Timestamp int64  `json:"timestamp"`
+	Signature string `json:"signature"`
+}
+
+// GetRegisterAddressChallenge api
+func (s *RPCAPI) GetRegisterAddressChallenge(r *http.Request, address *string, result *swapapi.RegisterAddressChallenge) error {
+	res, err := swapapi.GetRegisterAddressChallenge(*address)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// RegisterAddressWithProof api
+func (s *RPCAPI) RegisterAddressWithProof(r *http.Request, args *RPCRegisterAddressWithProofArgs, result *swapapi.PostResult) error {
+	res, err := swapapi.RegisterAddressWithProof(args.Address, args.Timestamp, args.Signature)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
 // GetRegisteredAddress api
 func (s *RPCAPI) GetRegisteredAddress(r *http.Request, address *string, result *swapapi.RegisteredAddress) error {
 	res, err := swapapi.GetRegisteredAddress(*address)
@@ -328,3 +748,21 @@ func (s *RPCAPI) GetRegisteredAddress(r *http.Request, address *string, result *
 	}
 	return err
 }
+
+// RPCListRegisteredAddressesArgs args
+type RPCListRegisteredAddressesArgs struct {
+	Offset   int    `json:"offset"`
+	Limit    int    `json:"limit"`
+	Prefix   string `json:"prefix"`
+	FromTime int64  `json:"fromTime,omitempty"`
+	ToTime   int64  `json:"toTime,omitempty"`
+}
+
+// ListRegisteredAddresses api, requires api token
+func (s *RPCAPI) ListRegisteredAddresses(r *http.Request, args *RPCListRegisteredAddressesArgs, result *swapapi.RegisteredAddressList) error {
+	res, err := swapapi.ListRegisteredAddresses(args.Offset, args.Limit, args.Prefix, args.FromTime, args.ToTime)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+package rpcapi
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/internal/swapapi"
+	"github.com/anyswap/CrossChain-Bridge/rpc/swapclient"
+)
+
+// rpcMethods binds method table of swapclient to handlers,
+// renaming or removing a handler without updating swapclient fails to compile.
+var rpcMethods = map[string]interface{}{
+	swapclient.MethodGetVersionInfo:              (*RPCAPI).GetVersionInfo,
+	swapclient.MethodGetServerInfo:               (*RPCAPI).GetServerInfo,
+	swapclient.MethodGetHealth:                   (*RPCAPI).GetHealth,
+	swapclient.MethodUpdateOracleHeartbeat:       (*RPCAPI).UpdateOracleHeartbeat,
+	swapclient.MethodGetOraclesHeartbeat:         (*RPCAPI).GetOraclesHeartbeat,
+	swapclient.MethodGetOraclesJobStatus:         (*RPCAPI).GetOraclesJobStatus,
+	swapclient.MethodGetRetryMetrics:             (*RPCAPI).GetRetryMetrics,
+	swapclient.MethodGetRegisterLimitMetrics:     (*RPCAPI).GetRegisterLimitMetrics,
+	swapclient.MethodGetAPITokenMetrics:          (*RPCAPI).GetAPITokenMetrics,
+	swapclient.MethodGetQuarantineMetrics:        (*RPCAPI).GetQuarantineMetrics,
+	swapclient.MethodGetDailyReport:              (*RPCAPI).GetDailyReport,
+	swapclient.MethodGetAllSwapStatistics:        (*RPCAPI).GetAllSwapStatistics,
+	swapclient.MethodGetPendingSwapCounts:        (*RPCAPI).GetPendingSwapCounts,
+	swapclient.MethodGetSwapVolumeHistory:        (*RPCAPI).GetSwapVolumeHistory,
+	swapclient.MethodGetSwapEvents:               (*RPCAPI).GetSwapEvents,
+	swapclient.MethodGetStatusInfo:               (*RPCAPI).GetStatusInfo,
+	swapclient.MethodGetSigningKey:               (*RPCAPI).GetSigningKey,
+	swapclient.MethodGetStatusCatalog:            (*RPCAPI).GetStatusCatalog,
+	swapclient.MethodGetRegisterErrorTable:       (*RPCAPI).GetRegisterErrorTable,
+	swapclient.MethodGetNativePrices:             (*RPCAPI).GetNativePrices,
+	swapclient.MethodGetTokenPairInfo:            (*RPCAPI).GetTokenPairInfo,
+	swapclient.MethodGetTokenPairsInfo:           (*RPCAPI).GetTokenPairsInfo,
+	swapclient.MethodGetNonceInfo:                (*RPCAPI).GetNonceInfo,
+	swapclient.MethodGetRawSwapin:                (*RPCAPI).GetRawSwapin,
+	swapclient.MethodGetRawSwapinResult:          (*RPCAPI).GetRawSwapinResult,
+	swapclient.MethodGetSwapin:                   (*RPCAPI).GetSwapin,
+	swapclient.MethodGetSwap:                     (*RPCAPI).GetSwap,
+	swapclient.MethodGetSwapStatus:               (*RPCAPI).GetSwapStatus,
+	swapclient.MethodGetRawSwapout:               (*RPCAPI).GetRawSwapout,
+	swapclient.MethodGetRawSwapoutResult:         (*RPCAPI).GetRawSwapoutResult,
+	swapclient.MethodGetSwapout:                  (*RPCAPI).GetSwapout,
+	swapclient.MethodGetSwapinHistory:            (*RPCAPI).GetSwapinHistory,
+	swapclient.MethodGetSwapoutHistory:           (*RPCAPI).GetSwapoutHistory,
+	swapclient.MethodGetSwapinHistoryPage:        (*RPCAPI).GetSwapinHistoryPage,
+	swapclient.MethodGetSwapoutHistoryPage:       (*RPCAPI).GetSwapoutHistoryPage,
+	swapclient.MethodSwapin:                      (*RPCAPI).Swapin,
+	swapclient.MethodRetrySwapin:                 (*RPCAPI).RetrySwapin,
+	swapclient.MethodRetrySwapout:                (*RPCAPI).RetrySwapout,
+	swapclient.MethodP2shSwapin:                  (*RPCAPI).P2shSwapin,
+	swapclient.MethodSwapout:                     (*RPCAPI).Swapout,
+	swapclient.MethodSwapinBatch:                 (*RPCAPI).SwapinBatch,
+	swapclient.MethodSwapoutBatch:                (*RPCAPI).SwapoutBatch,
+	swapclient.MethodPrevalidateDeposit:          (*RPCAPI).PrevalidateDeposit,
+	swapclient.MethodGetSwapFeeEstimate:          (*RPCAPI).GetSwapFeeEstimate,
+	swapclient.MethodDebugVerifyTransaction:      (*RPCAPI).DebugVerifyTransaction,
+	swapclient.MethodIsValidSwapinBindAddress:    (*RPCAPI).IsValidSwapinBindAddress,
+	swapclient.MethodIsValidSwapoutBindAddress:   (*RPCAPI).IsValidSwapoutBindAddress,
+	swapclient.MethodValidateBindAddress:         (*RPCAPI).ValidateBindAddress,
+	swapclient.MethodValidateBindAddresses:       (*RPCAPI).ValidateBindAddresses,
+	swapclient.MethodRegisterP2shAddress:         (*RPCAPI).RegisterP2shAddress,
+	swapclient.MethodGetP2shAddressInfo:          (*RPCAPI).GetP2shAddressInfo,
+	swapclient.MethodGetP2shAddressByBind:        (*RPCAPI).GetP2shAddressByBind,
+	swapclient.MethodListP2shAddresses:           (*RPCAPI).ListP2shAddresses,
+	swapclient.MethodRegisterP2shAddressBatch:    (*RPCAPI).RegisterP2shAddressBatch,
+	swapclient.MethodGetP2shBatchJob:             (*RPCAPI).GetP2shBatchJob,
+	swapclient.MethodGetLatestScanInfo:           (*RPCAPI).GetLatestScanInfo,
+	swapclient.MethodRegisterAddress:             (*RPCAPI).RegisterAddress,
+	swapclient.MethodRegisterAddresses:           (*RPCAPI).RegisterAddresses,
+	swapclient.MethodGetRegisterAddressChallenge: (*RPCAPI).GetRegisterAddressChallenge,
+	swapclient.MethodRegisterAddressWithProof:    (*RPCAPI).RegisterAddressWithProof,
+	swapclient.MethodGetRegisteredAddress:        (*RPCAPI).GetRegisteredAddress,
+	swapclient.MethodListRegisteredAddresses:     (*RPCAPI).ListRegisteredAddresses,
+	swapclient.MethodAdminCall:                   (*RPCAPI).AdminCall,
+}
+
+// args of swapclient must have the same fields as args of handlers
+var (
+	_ = RPCTxAndPairIDArgs(swapclient.TxAndPairIDArgs{})
+	_ = RPCRegisterAddressWithProofArgs(swapclient.RegisterAddressWithProofArgs{})
+	_ = RPCP2shSwapinArgs(swapclient.P2shSwapinArgs{})
+	_ = RPCSwapBatchArgs(swapclient.SwapBatchArgs{})
+	_ = RPCQueryHistoryArgs(swapclient.QueryHistoryArgs{})
+	_ = RPCQueryHistoryPageArgs(swapclient.QueryHistoryPageArgs{})
+	_ = RPCPrevalidateDepositArgs(swapclient.PrevalidateDepositArgs{})
+	_ = RPCGetSwapFeeEstimateArgs(swapclient.GetSwapFeeEstimateArgs{})
+	_ = RPCValidateBindAddressArgs(swapclient.ValidateBindAddressArgs{})
+	_ = RPCValidateBindAddressesArgs(swapclient.ValidateBindAddressesArgs{})
+	_ = RPCGetSwapVolumeHistoryArgs(swapclient.GetSwapVolumeHistoryArgs{})
+	_ = RPCGetSwapEventsArgs(swapclient.GetSwapEventsArgs{})
+	_ = RPCListRegisteredAddressesArgs(swapclient.ListRegisteredAddressesArgs{})
+	_ = RPCListP2shAddressesArgs(swapclient.ListP2shAddressesArgs{})
+)
+
+// methods exposing all users' data, which must be called with api token
+var apiTokenRequiredMethods = []string{
+	swapclient.MethodListRegisteredAddresses,
+	swapclient.MethodListP2shAddresses,
+}
+
+func init() {
+	for _, method := range apiTokenRequiredMethods {
+		swapapi.RequireAPIToken(method)
+	}
+}
@@ -0,0 +1,26 @@
+package rpcapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/rpc/swapclient"
+)
+
+func TestClientMethodTable(t *testing.T) {
+	if len(swapclient.Methods) != len(rpcMethods) {
+		t.Errorf("swapclient.Methods has %v methods, but %v are bound", len(swapclient.Methods), len(rpcMethods))
+	}
+	for _, method := range swapclient.Methods {
+		if _, exist := rpcMethods[method]; !exist {
+			t.Errorf("method %v is not bound to handler", method)
+		}
+	}
+	apiType := reflect.TypeOf(new(RPCAPI))
+	for i := 0; i < apiType.NumMethod(); i++ {
+		method := "swap." + apiType.Method(i).Name
+		if _, exist := rpcMethods[method]; !exist {
+			t.Errorf("handler %v is missing in swapclient method table", method)
+		}
+	}
+}
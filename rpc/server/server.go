@@ -16,6 +16,9 @@ import (
 	rpcjson "github.com/gorilla/rpc/v2/json2"
 
 	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/internal/apiversion"
+	"github.com/anyswap/CrossChain-Bridge/internal/metrics"
+	"github.com/anyswap/CrossChain-Bridge/internal/swapapi"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/rpc/restapi"
@@ -40,7 +43,7 @@ func StartAPIServer() {
 	}
 	if len(allowedOrigins) != 0 {
 		corsOptions = append(corsOptions,
-			handlers.AllowedHeaders([]string{"X-Requested-With", "Content-Type"}),
+			handlers.AllowedHeaders([]string{"X-Requested-With", "Content-Type", "Idempotency-Key", apiversion.HeaderName, swapapi.APITokenHeader}),
 			handlers.AllowedOrigins(allowedOrigins),
 		)
 	}
@@ -89,27 +92,58 @@ func initRouter(r *mux.Router) {
 	}
 
 	rpcserver := rpc.NewServer()
-	rpcserver.RegisterCodec(rpcjson.NewCodec(), "application/json")
+	rpcserver.RegisterCodec(apiversion.NewCodec(rpcjson.NewCodec()), "application/json")
 	err := rpcserver.RegisterService(new(rpcapi.RPCAPI), "swap")
 	if err != nil {
 		log.Fatal("start rpc service failed", "err", err)
 	}
 
+	rpcserver.RegisterValidateRequestFunc(checkAPIToken)
+
+	r.Use(apiversion.Middleware)
 	r.Handle("/rpc", rpcserver)
 
 	r.HandleFunc("/serverinfo", restapi.ServerInfoHandler).Methods("GET")
+	r.HandleFunc("/health", restapi.HealthHandler).Methods("GET")
+	if metrics.IsEnabled() {
+		r.Handle("/metrics", metrics.Handler()).Methods("GET")
+	}
 	r.HandleFunc("/versioninfo", restapi.VersionInfoHandler).Methods("GET")
 	r.HandleFunc("/oracleinfo", restapi.OracleInfoHandler).Methods("GET")
+	r.HandleFunc("/oraclejobs", restapi.OracleJobStatusHandler).Methods("GET")
+	r.HandleFunc("/retrymetrics", restapi.RetryMetricsHandler).Methods("GET")
+	r.HandleFunc("/registerlimitmetrics", restapi.RegisterLimitMetricsHandler).Methods("GET")
+	r.HandleFunc("/apitokenmetrics", restapi.APITokenMetricsHandler).Methods("GET")
+	r.HandleFunc("/quarantinemetrics", restapi.QuarantineMetricsHandler).Methods("GET")
+	r.HandleFunc("/dailyreport/{date}", restapi.DailyReportHandler).Methods("GET")
+	r.HandleFunc("/statistics", restapi.AllSwapStatisticsHandler).Methods("GET")
+	r.HandleFunc("/pendingcounts", restapi.PendingSwapCountsHandler).Methods("GET")
+	r.HandleFunc("/volume/{pairid}", restapi.SwapVolumeHistoryHandler).Methods("GET")
+	r.HandleFunc("/events", restapi.SwapEventsHandler).Methods("GET")
 	r.HandleFunc("/nonceinfo", restapi.NonceInfoHandler).Methods("GET")
 	r.HandleFunc("/statusinfo", restapi.StatusInfoHandler).Methods("GET")
+	r.HandleFunc("/statuscatalog", restapi.StatusCatalogHandler).Methods("GET")
+	r.HandleFunc("/registererrors", restapi.RegisterErrorTableHandler).Methods("GET")
+	r.HandleFunc("/nativeprices", restapi.NativePricesHandler).Methods("GET")
+	r.HandleFunc("/signingkey", restapi.SigningKeyHandler).Methods("GET")
 	r.HandleFunc("/pairinfo/{pairid}", restapi.TokenPairInfoHandler).Methods("GET")
 	r.HandleFunc("/pairsinfo/{pairids}", restapi.TokenPairsInfoHandler).Methods("GET")
 
 	r.HandleFunc("/swapin/post/{pairid}/{txid}", restapi.PostSwapinHandler).Methods("POST")
 	r.HandleFunc("/swapout/post/{pairid}/{txid}", restapi.PostSwapoutHandler).Methods("POST")
+	r.HandleFunc("/swapin/batch/{pairid}", restapi.SwapinBatchHandler).Methods("POST")
+	r.HandleFunc("/swapout/batch/{pairid}", restapi.SwapoutBatchHandler).Methods("POST")
 	r.HandleFunc("/swapin/p2sh/{txid}/{bind}", restapi.PostP2shSwapinHandler).Methods("POST")
 	r.HandleFunc("/swapin/retry/{pairid}/{txid}", restapi.RetrySwapinHandler).Methods("POST")
-
+	r.HandleFunc("/swapout/retry/{pairid}/{txid}", restapi.RetrySwapoutHandler).Methods("POST")
+
+	r.HandleFunc("/prevalidate/{pairid}", restapi.PrevalidateDepositHandler).Methods("GET")
+	r.HandleFunc("/feeestimate/{pairid}/{direction}", restapi.GetSwapFeeEstimateHandler).Methods("GET")
+	r.HandleFunc("/validatebind/batch/{direction}", restapi.ValidateBindAddressesHandler).Methods("GET")
+	r.HandleFunc("/validatebind/{pairid}/{address}", restapi.ValidateBindAddressHandler).Methods("GET")
+	r.HandleFunc("/debugverify/{pairid}/{txid}", restapi.DebugVerifyHandler).Methods("GET")
+	r.HandleFunc("/swap/{pairid}/{txid}", restapi.GetSwapHandler).Methods("GET")
+	r.HandleFunc("/swapstatus/{pairid}/{txid}", restapi.GetSwapStatusHandler).Methods("GET")
 	r.HandleFunc("/swapin/{pairid}/{txid}", restapi.GetSwapinHandler).Methods("GET")
 	r.HandleFunc("/swapout/{pairid}/{txid}", restapi.GetSwapoutHandler).Methods("GET")
 	r.HandleFunc("/swapin/{pairid}/{txid}/raw", restapi.GetRawSwapinHandler).Methods("GET")
@@ -118,10 +152,24 @@ func initRouter(r *mux.Router) {
 	r.HandleFunc("/swapout/{pairid}/{txid}/rawresult", restapi.GetRawSwapoutResultHandler).Methods("GET")
 	r.HandleFunc("/swapin/history/{pairid}/{address}", restapi.SwapinHistoryHandler).Methods("GET")
 	r.HandleFunc("/swapout/history/{pairid}/{address}", restapi.SwapoutHistoryHandler).Methods("GET")
+	r.HandleFunc("/swapin/historypage/{pairid}/{address}", restapi.SwapinHistoryPageHandler).Methods("GET")
+	r.HandleFunc("/swapout/historypage/{pairid}/{address}", restapi.SwapoutHistoryPageHandler).Methods("GET")
 
+	r.HandleFunc("/p2sh/batch/{jobid}", restapi.GetP2shBatchJob).Methods("GET")
 	r.HandleFunc("/p2sh/{address}", restapi.GetP2shAddressInfo).Methods("GET")
 	r.HandleFunc("/p2sh/bind/{address}", restapi.RegisterP2shAddress).Methods("POST")
+	r.HandleFunc("/p2sh/bind/{address}", restapi.GetP2shAddressByBindHandler).Methods("GET")
 
 	r.HandleFunc("/registered/{address}", restapi.GetRegisteredAddress).Methods("GET")
+	r.HandleFunc("/register/batch", restapi.RegisterAddressesHandler).Methods("POST")
 	r.HandleFunc("/register/{address}", restapi.RegisterAddress).Methods("POST")
+	r.HandleFunc("/register/{address}/challenge", restapi.RegisterAddressChallengeHandler).Methods("GET")
+	r.HandleFunc("/register/{address}/proof", restapi.RegisterAddressWithProofHandler).Methods("POST")
+}
+
+// checkAPIToken check api token of rpc call before dispatching,
+// token is only accepted in header to keep it out of logged urls
+func checkAPIToken(info *rpc.RequestInfo, args interface{}) error {
+	token := info.Request.Header.Get(swapapi.APITokenHeader)
+	return swapapi.CheckAPIToken(token, info.Method)
 }
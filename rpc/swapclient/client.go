@@ -0,0 +1,564 @@
+// Package swapclient provides a json rpc client of the swap server for go integrators.
+//
+// It only depends on the standard library. Requests are retried with backoff on
+// transport failures, and mutation calls carry an idempotency key so that retries
+// never register a swap twice.
+package swapclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultTimeout    = 30 * time.Second
+	defaultRetries    = 3
+	defaultBackoff    = 500 * time.Millisecond
+	defaultMaxBackoff = 10 * time.Second
+
+	maxResponseLength int64 = 10 * 1024 * 1024
+
+	// response types of this client are in the shape of api version 1
+	apiVersionHeader = "X-API-Version"
+	apiVersion       = "1"
+
+	apiTokenHeader = "X-Api-Token"
+)
+
+var (
+	errNoAdminSigner = errors.New("admin signer is not set")
+	errEmptyResponse = errors.New("empty response body")
+)
+
+// Error json rpc error returned by server
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("json-rpc error %d, %s", err.Code, err.Message)
+}
+
+// AdminSigner sign admin call and return raw tx, eg. `admin.Sign` after `admin.LoadKeyStore`
+type AdminSigner func(method string, params []string) (rawTx string, err error)
+
+// Client swap server json rpc client
+type Client struct {
+	url         string
+	httpClient  *http.Client
+	retries     int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	adminSigner AdminSigner
+	apiToken    string
+	requestID   uint64
+}
+
+// Option client option
+type Option func(*Client)
+
+// WithHTTPClient use custom http client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetry retry failed requests at most `retries` times,
+// the backoff between retries starts with `backoff` and is doubled each time up to `maxBackoff`
+func WithRetry(retries int, backoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// WithAdminSigner set signer of admin calls
+func WithAdminSigner(signer AdminSigner) Option {
+	return func(c *Client) {
+		c.adminSigner = signer
+	}
+}
+
+// WithAPIToken call with api token, which is required by some methods
+func WithAPIToken(token string) Option {
+	return func(c *Client) {
+		c.apiToken = token
+	}
+}
+
+// New new client of swap server rpc url (eg. http://127.0.0.1:11556/rpc)
+func New(url string, opts ...Option) *Client {
+	c := &Client{
+		url:        url,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		retries:    defaultRetries,
+		backoff:    defaultBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type requestBody struct {
+	Version string        `json:"jsonrpc"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+	ID      uint64        `json:"id"`
+}
+
+type responseBody struct {
+	Error  *Error          `json:"error,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// Call call json rpc method with retries,
+// json rpc errors returned by server are not retried.
+func (c *Client) Call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	return c.call(ctx, c.retries, result, method, params...)
+}
+
+func (c *Client) call(ctx context.Context, retries int, result interface{}, method string, params ...interface{}) (err error) {
+	if params == nil {
+		params = []interface{}{}
+	}
+	backoff := c.backoff
+	for i := 0; ; i++ {
+		err = c.post(ctx, result, method, params)
+		var rpcErr *Error
+		if err == nil || errors.As(err, &rpcErr) || i >= retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+		}
+	}
+}
+
+func (c *Client) post(ctx context.Context, result interface{}, method string, params []interface{}) error {
+	reqBody, err := json.Marshal(&requestBody{
+		Version: "2.0",
+		Method:  method,
+		Params:  params,
+		ID:      atomic.AddUint64(&c.requestID, 1),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(apiVersionHeader, apiVersion)
+	if c.apiToken != "" {
+		req.Header.Set(apiTokenHeader, c.apiToken)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseLength))
+	if err != nil {
+		return fmt.Errorf("read body error: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("wrong response status %v. message: %v", resp.StatusCode, string(body))
+	}
+	if len(body) == 0 {
+		return errEmptyResponse
+	}
+	var respBody responseBody
+	if err = json.Unmarshal(body, &respBody); err != nil {
+		return fmt.Errorf("unmarshal body error: %w", err)
+	}
+	if respBody.Error != nil {
+		return respBody.Error
+	}
+	if result == nil {
+		return nil
+	}
+	if err = json.Unmarshal(respBody.Result, result); err != nil {
+		return fmt.Errorf("unmarshal result error: %w", err)
+	}
+	return nil
+}
+
+// NewIdempotencyKey generate random idempotency key
+func NewIdempotencyKey() string {
+	var key [16]byte
+	_, _ = rand.Read(key[:])
+	return hex.EncodeToString(key[:])
+}
+
+// GetVersionInfo api
+func (c *Client) GetVersionInfo(ctx context.Context) (version string, err error) {
+	err = c.Call(ctx, &version, MethodGetVersionInfo)
+	return version, err
+}
+
+// GetStatusCatalog api
+func (c *Client) GetStatusCatalog(ctx context.Context) (result []*SwapStatusInfo, err error) {
+	err = c.Call(ctx, &result, MethodGetStatusCatalog)
+	return result, err
+}
+
+// GetOraclesJobStatus api, key is oracle enode ID
+func (c *Client) GetOraclesJobStatus(ctx context.Context) (result map[string]*AcceptJobStatus, err error) {
+	err = c.Call(ctx, &result, MethodGetOraclesJobStatus)
+	return result, err
+}
+
+// GetRetryMetrics api, key is call site
+func (c *Client) GetRetryMetrics(ctx context.Context) (result map[string]*RetryMetrics, err error) {
+	err = c.Call(ctx, &result, MethodGetRetryMetrics)
+	return result, err
+}
+
+// GetRegisterLimitMetrics api, key is register method
+func (c *Client) GetRegisterLimitMetrics(ctx context.Context) (result map[string]*RegisterLimitMetrics, err error) {
+	err = c.Call(ctx, &result, MethodGetRegisterLimitMetrics)
+	return result, err
+}
+
+// GetAPITokenMetrics api, key is api token name
+func (c *Client) GetAPITokenMetrics(ctx context.Context) (result map[string]*APITokenMetrics, err error) {
+	err = c.Call(ctx, &result, MethodGetAPITokenMetrics)
+	return result, err
+}
+
+// GetQuarantineMetrics api
+func (c *Client) GetQuarantineMetrics(ctx context.Context) (*QuarantineMetrics, error) {
+	var result QuarantineMetrics
+	err := c.Call(ctx, &result, MethodGetQuarantineMetrics)
+	return &result, err
+}
+
+// GetDailyReport api, date format is 2006-01-02
+func (c *Client) GetDailyReport(ctx context.Context, date string) (*DailyReport, error) {
+	var result DailyReport
+	err := c.Call(ctx, &result, MethodGetDailyReport, date)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetHealth api
+func (c *Client) GetHealth(ctx context.Context) (*HealthInfo, error) {
+	var result HealthInfo
+	err := c.Call(ctx, &result, MethodGetHealth)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetAllSwapStatistics api
+func (c *Client) GetAllSwapStatistics(ctx context.Context) (*AllSwapStatistics, error) {
+	var result AllSwapStatistics
+	err := c.Call(ctx, &result, MethodGetAllSwapStatistics)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetPendingSwapCounts api
+func (c *Client) GetPendingSwapCounts(ctx context.Context) (*AllPendingCounts, error) {
+	var result AllPendingCounts
+	err := c.Call(ctx, &result, MethodGetPendingSwapCounts)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetSwapVolumeHistory api
+func (c *Client) GetSwapVolumeHistory(ctx context.Context, args *GetSwapVolumeHistoryArgs) (*SwapVolumeHistory, error) {
+	var result SwapVolumeHistory
+	err := c.Call(ctx, &result, MethodGetSwapVolumeHistory, args)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetSwapEvents api, pass seq of the last seen event to tail events
+func (c *Client) GetSwapEvents(ctx context.Context, args *GetSwapEventsArgs) (result []*SwapEvent, err error) {
+	err = c.Call(ctx, &result, MethodGetSwapEvents, args)
+	return result, err
+}
+
+// GetRegisterErrorTable api
+func (c *Client) GetRegisterErrorTable(ctx context.Context) (result []*RegisterErrorEntry, err error) {
+	err = c.Call(ctx, &result, MethodGetRegisterErrorTable)
+	return result, err
+}
+
+// GetNativePrices api
+func (c *Client) GetNativePrices(ctx context.Context) (*NativePricesInfo, error) {
+	var result NativePricesInfo
+	err := c.Call(ctx, &result, MethodGetNativePrices)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetSwapin api
+func (c *Client) GetSwapin(ctx context.Context, txid, pairID, bind string) (*SwapInfo, error) {
+	var result SwapInfo
+	err := c.Call(ctx, &result, MethodGetSwapin, &TxAndPairIDArgs{TxID: txid, PairID: pairID, Bind: bind})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetSwap api, returns swapin and/or swapout of txid with direction
+func (c *Client) GetSwap(ctx context.Context, txid, pairID, bind string) (result []*SwapInfo, err error) {
+	err = c.Call(ctx, &result, MethodGetSwap, &TxAndPairIDArgs{TxID: txid, PairID: pairID, Bind: bind})
+	return result, err
+}
+
+// GetSwapStatus api
+func (c *Client) GetSwapStatus(ctx context.Context, txid, pairID, bind string) (*SwapPollStatus, error) {
+	var result SwapPollStatus
+	err := c.Call(ctx, &result, MethodGetSwapStatus, &TxAndPairIDArgs{TxID: txid, PairID: pairID, Bind: bind})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetSwapout api
+func (c *Client) GetSwapout(ctx context.Context, txid, pairID, bind string) (*SwapInfo, error) {
+	var result SwapInfo
+	err := c.Call(ctx, &result, MethodGetSwapout, &TxAndPairIDArgs{TxID: txid, PairID: pairID, Bind: bind})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetSwapinHistory api
+func (c *Client) GetSwapinHistory(ctx context.Context, args *QueryHistoryArgs) (result []*SwapInfo, err error) {
+	err = c.Call(ctx, &result, MethodGetSwapinHistory, args)
+	return result, err
+}
+
+// GetSwapoutHistory api
+func (c *Client) GetSwapoutHistory(ctx context.Context, args *QueryHistoryArgs) (result []*SwapInfo, err error) {
+	err = c.Call(ctx, &result, MethodGetSwapoutHistory, args)
+	return result, err
+}
+
+// GetSwapinHistoryPage api
+func (c *Client) GetSwapinHistoryPage(ctx context.Context, args *QueryHistoryPageArgs) (*SwapHistoryPage, error) {
+	var result SwapHistoryPage
+	err := c.Call(ctx, &result, MethodGetSwapinHistoryPage, args)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetSwapoutHistoryPage api
+func (c *Client) GetSwapoutHistoryPage(ctx context.Context, args *QueryHistoryPageArgs) (*SwapHistoryPage, error) {
+	var result SwapHistoryPage
+	err := c.Call(ctx, &result, MethodGetSwapoutHistoryPage, args)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Swapin api, an idempotency key is generated if args has none
+func (c *Client) Swapin(ctx context.Context, args *TxAndPairIDArgs) (result PostResult, err error) {
+	if args.IdempotencyKey == "" {
+		args.IdempotencyKey = NewIdempotencyKey()
+	}
+	err = c.Call(ctx, &result, MethodSwapin, args)
+	return result, err
+}
+
+// RetrySwapin api
+func (c *Client) RetrySwapin(ctx context.Context, txid, pairID string) (result PostResult, err error) {
+	err = c.Call(ctx, &result, MethodRetrySwapin, &TxAndPairIDArgs{TxID: txid, PairID: pairID})
+	return result, err
+}
+
+// RetrySwapout api
+func (c *Client) RetrySwapout(ctx context.Context, txid, pairID string) (result PostResult, err error) {
+	err = c.Call(ctx, &result, MethodRetrySwapout, &TxAndPairIDArgs{TxID: txid, PairID: pairID})
+	return result, err
+}
+
+// P2shSwapin api, an idempotency key is generated if args has none
+func (c *Client) P2shSwapin(ctx context.Context, args *P2shSwapinArgs) (result PostResult, err error) {
+	if args.IdempotencyKey == "" {
+		args.IdempotencyKey = NewIdempotencyKey()
+	}
+	err = c.Call(ctx, &result, MethodP2shSwapin, args)
+	return result, err
+}
+
+// Swapout api, an idempotency key is generated if args has none
+func (c *Client) Swapout(ctx context.Context, args *TxAndPairIDArgs) (result PostResult, err error) {
+	if args.IdempotencyKey == "" {
+		args.IdempotencyKey = NewIdempotencyKey()
+	}
+	err = c.Call(ctx, &result, MethodSwapout, args)
+	return result, err
+}
+
+// SwapinBatch api, result is register result of each txid
+func (c *Client) SwapinBatch(ctx context.Context, args *SwapBatchArgs) (result map[string]string, err error) {
+	err = c.Call(ctx, &result, MethodSwapinBatch, args)
+	return result, err
+}
+
+// SwapoutBatch api, result is register result of each txid
+func (c *Client) SwapoutBatch(ctx context.Context, args *SwapBatchArgs) (result map[string]string, err error) {
+	err = c.Call(ctx, &result, MethodSwapoutBatch, args)
+	return result, err
+}
+
+// PrevalidateDeposit api
+func (c *Client) PrevalidateDeposit(ctx context.Context, args *PrevalidateDepositArgs) (*PrevalidateResult, error) {
+	var result PrevalidateResult
+	err := c.Call(ctx, &result, MethodPrevalidateDeposit, args)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetSwapFeeEstimate api
+func (c *Client) GetSwapFeeEstimate(ctx context.Context, args *GetSwapFeeEstimateArgs) (*SwapFeeEstimate, error) {
+	var result SwapFeeEstimate
+	err := c.Call(ctx, &result, MethodGetSwapFeeEstimate, args)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ValidateBindAddress api
+func (c *Client) ValidateBindAddress(ctx context.Context, args *ValidateBindAddressArgs) (*BindAddressValidation, error) {
+	var result BindAddressValidation
+	err := c.Call(ctx, &result, MethodValidateBindAddress, args)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ValidateBindAddresses api, result is validation of each address
+func (c *Client) ValidateBindAddresses(ctx context.Context, args *ValidateBindAddressesArgs) (result map[string]*BindAddressValidation, err error) {
+	err = c.Call(ctx, &result, MethodValidateBindAddresses, args)
+	return result, err
+}
+
+// DebugVerifyTransaction api, dry run verification without registering
+func (c *Client) DebugVerifyTransaction(ctx context.Context, args *DebugVerifyArgs) (*DebugVerifyResult, error) {
+	var result DebugVerifyResult
+	err := c.Call(ctx, &result, MethodDebugVerifyTransaction, args)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RegisterAddress api
+func (c *Client) RegisterAddress(ctx context.Context, address string) (result PostResult, err error) {
+	err = c.Call(ctx, &result, MethodRegisterAddress, address)
+	return result, err
+}
+
+// RegisterAddresses api, result is register result of each address
+func (c *Client) RegisterAddresses(ctx context.Context, addresses []string) (result map[string]string, err error) {
+	err = c.Call(ctx, &result, MethodRegisterAddresses, addresses)
+	return result, err
+}
+
+// GetRegisterAddressChallenge api
+func (c *Client) GetRegisterAddressChallenge(ctx context.Context, address string) (*RegisterAddressChallenge, error) {
+	var result *RegisterAddressChallenge
+	err := c.Call(ctx, &result, MethodGetRegisterAddressChallenge, address)
+	return result, err
+}
+
+// RegisterAddressWithProof api, signature is personal_sign of the challenge message by the address
+func (c *Client) RegisterAddressWithProof(ctx context.Context, args *RegisterAddressWithProofArgs) (result PostResult, err error) {
+	err = c.Call(ctx, &result, MethodRegisterAddressWithProof, args)
+	return result, err
+}
+
+// GetRegisteredAddress api
+func (c *Client) GetRegisteredAddress(ctx context.Context, address string) (*RegisteredAddress, error) {
+	var result *RegisteredAddress
+	err := c.Call(ctx, &result, MethodGetRegisteredAddress, address)
+	return result, err
+}
+
+// ListRegisteredAddresses api, requires api token (see `WithAPIToken`)
+func (c *Client) ListRegisteredAddresses(ctx context.Context, args *ListRegisteredAddressesArgs) (*RegisteredAddressList, error) {
+	var result RegisteredAddressList
+	err := c.Call(ctx, &result, MethodListRegisteredAddresses, args)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetP2shAddressByBind api, get stored p2sh address of bind address
+func (c *Client) GetP2shAddressByBind(ctx context.Context, bindAddress string) (*P2shAddress, error) {
+	var result *P2shAddress
+	err := c.Call(ctx, &result, MethodGetP2shAddressByBind, bindAddress)
+	return result, err
+}
+
+// ListP2shAddresses api, requires api token (see `WithAPIToken`)
+func (c *Client) ListP2shAddresses(ctx context.Context, args *ListP2shAddressesArgs) (result []*P2shAddress, err error) {
+	err = c.Call(ctx, &result, MethodListP2shAddresses, args)
+	return result, err
+}
+
+// AdminCall sign and call admin method, admin calls are not retried
+func (c *Client) AdminCall(ctx context.Context, method string, params []string) (result json.RawMessage, err error) {
+	if c.adminSigner == nil {
+		return nil, errNoAdminSigner
+	}
+	rawTx, err := c.adminSigner(method, params)
+	if err != nil {
+		return nil, err
+	}
+	err = c.call(ctx, 0, &result, MethodAdminCall, rawTx)
+	return result, err
+}
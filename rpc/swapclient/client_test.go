@@ -0,0 +1,88 @@
+package swapclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryKeepsIdempotencyKey(t *testing.T) {
+	var calls int
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var req struct {
+			Method string             `json:"method"`
+			Params []*TxAndPairIDArgs `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		keys = append(keys, req.Params[0].IdempotencyKey)
+		if calls < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":"Success","id":1}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, WithRetry(3, time.Millisecond, time.Millisecond))
+	res, err := client.Swapin(context.Background(), &TxAndPairIDArgs{TxID: "0x1234", PairID: "fsn"})
+	if err != nil || res != "Success" {
+		t.Fatalf("swapin failed: res=%v err=%v", res, err)
+	}
+	if calls != 3 {
+		t.Fatalf("want 3 calls, have %v", calls)
+	}
+	for _, key := range keys {
+		if key == "" || key != keys[0] {
+			t.Fatalf("idempotency key should be generated once and reused, have %v", keys)
+		}
+	}
+}
+
+func TestRPCErrorNotRetried(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","error":{"code":-32095,"message":"token pair not exist"},"id":1}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, WithRetry(3, time.Millisecond, time.Millisecond))
+	_, err := client.GetSwapin(context.Background(), "0x1234", "unknown", "")
+	var rpcErr *Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32095 {
+		t.Fatalf("want json rpc error, have %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("json rpc error should not be retried, have %v calls", calls)
+	}
+}
+
+func TestListRegisteredAddressesWithAPIToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(apiTokenHeader) != "secret" {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","error":{"code":-32053,"message":"method requires api token"},"id":1}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":{"total":1,"offset":0,"limit":20,"addresses":[{"Key":"0x1234","Timestamp":1600000000,"P2shAddress":"3p2sh"}]},"id":1}`))
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL).ListRegisteredAddresses(context.Background(), &ListRegisteredAddressesArgs{})
+	var rpcErr *Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32053 {
+		t.Fatalf("want api token required error, have %v", err)
+	}
+	list, err := New(server.URL, WithAPIToken("secret")).ListRegisteredAddresses(context.Background(), &ListRegisteredAddressesArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Total != 1 || len(list.Addresses) != 1 || list.Addresses[0].Key != "0x1234" || list.Addresses[0].P2shAddress != "3p2sh" {
+		t.Errorf("wrong list %+v", list)
+	}
+}
@@ -0,0 +1,71 @@
+// Command example registers a swap to the swap server and polls it until finished.
+//
+//	go run ./rpc/swapclient/example -server http://127.0.0.1:11556/rpc -pairid BTC -txid 0x...
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/rpc/swapclient"
+)
+
+var (
+	server       = flag.String("server", "http://127.0.0.1:11556/rpc", "swap server rpc url")
+	pairID       = flag.String("pairid", "", "token pair id")
+	txid         = flag.String("txid", "", "deposit (swapin) or burn (swapout) tx hash")
+	bind         = flag.String("bind", "", "bind address (optional)")
+	isSwapout    = flag.Bool("swapout", false, "register swapout instead of swapin")
+	pollInterval = flag.Duration("interval", 10*time.Second, "poll interval")
+	pollTimeout  = flag.Duration("timeout", time.Hour, "give up polling after this duration")
+)
+
+func main() {
+	flag.Parse()
+	if *pairID == "" || *txid == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	client := swapclient.New(*server)
+	ctx, cancel := context.WithTimeout(context.Background(), *pollTimeout)
+	defer cancel()
+
+	args := &swapclient.TxAndPairIDArgs{TxID: *txid, PairID: *pairID}
+	register, get := client.Swapin, client.GetSwapin
+	if *isSwapout {
+		register, get = client.Swapout, client.GetSwapout
+	}
+
+	res, err := register(ctx, args)
+	if err != nil {
+		return err
+	}
+	fmt.Println("register swap:", res)
+
+	for {
+		swap, err := get(ctx, *txid, *pairID, *bind)
+		if err != nil {
+			fmt.Println("get swap failed:", err)
+		} else {
+			fmt.Printf("status: %v (%v) swaptx: %v confirmations: %v\n", swap.Status, swap.StatusMsg, swap.SwapTx, swap.Confirmations)
+			if swap.StatusInfo != nil && swap.StatusInfo.IsTerminal {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(*pollInterval):
+		}
+	}
+}
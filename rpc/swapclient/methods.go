@@ -0,0 +1,137 @@
+package swapclient
+
+// method names of swap json rpc service.
+// the server binds every name to its handler (see rpc/rpcapi/methods.go),
+// so that renaming or removing a handler without updating this table is a compile error.
+const (
+	MethodGetVersionInfo              = "swap.GetVersionInfo"
+	MethodGetServerInfo               = "swap.GetServerInfo"
+	MethodGetHealth                   = "swap.GetHealth"
+	MethodUpdateOracleHeartbeat       = "swap.UpdateOracleHeartbeat"
+	MethodGetOraclesHeartbeat         = "swap.GetOraclesHeartbeat"
+	MethodGetOraclesJobStatus         = "swap.GetOraclesJobStatus"
+	MethodGetRetryMetrics             = "swap.GetRetryMetrics"
+	MethodGetRegisterLimitMetrics     = "swap.GetRegisterLimitMetrics"
+	MethodGetAPITokenMetrics          = "swap.GetAPITokenMetrics"
+	MethodGetQuarantineMetrics        = "swap.GetQuarantineMetrics"
+	MethodGetDailyReport              = "swap.GetDailyReport"
+	MethodGetAllSwapStatistics        = "swap.GetAllSwapStatistics"
+	MethodGetPendingSwapCounts        = "swap.GetPendingSwapCounts"
+	MethodGetSwapVolumeHistory        = "swap.GetSwapVolumeHistory"
+	MethodGetSwapEvents               = "swap.GetSwapEvents"
+	MethodGetStatusInfo               = "swap.GetStatusInfo"
+	MethodGetSigningKey               = "swap.GetSigningKey"
+	MethodGetStatusCatalog            = "swap.GetStatusCatalog"
+	MethodGetRegisterErrorTable       = "swap.GetRegisterErrorTable"
+	MethodGetNativePrices             = "swap.GetNativePrices"
+	MethodGetTokenPairInfo            = "swap.GetTokenPairInfo"
+	MethodGetTokenPairsInfo           = "swap.GetTokenPairsInfo"
+	MethodGetNonceInfo                = "swap.GetNonceInfo"
+	MethodGetRawSwapin                = "swap.GetRawSwapin"
+	MethodGetRawSwapinResult          = "swap.GetRawSwapinResult"
+	MethodGetSwapin                   = "swap.GetSwapin"
+	MethodGetSwap                     = "swap.GetSwap"
+	MethodGetSwapStatus               = "swap.GetSwapStatus"
+	MethodGetRawSwapout               = "swap.GetRawSwapout"
+	MethodGetRawSwapoutResult         = "swap.GetRawSwapoutResult"
+	MethodGetSwapout                  = "swap.GetSwapout"
+	MethodGetSwapinHistory            = "swap.GetSwapinHistory"
+	MethodGetSwapoutHistory           = "swap.GetSwapoutHistory"
+	MethodGetSwapinHistoryPage        = "swap.GetSwapinHistoryPage"
+	MethodGetSwapoutHistoryPage       = "swap.GetSwapoutHistoryPage"
+	MethodSwapin                      = "swap.Swapin"
+	MethodRetrySwapin                 = "swap.RetrySwapin"
+	MethodRetrySwapout                = "swap.RetrySwapout"
+	MethodP2shSwapin                  = "swap.P2shSwapin"
+	MethodSwapout                     = "swap.Swapout"
+	MethodSwapinBatch                 = "swap.SwapinBatch"
+	MethodSwapoutBatch                = "swap.SwapoutBatch"
+	MethodPrevalidateDeposit          = "swap.PrevalidateDeposit"
+	MethodGetSwapFeeEstimate          = "swap.GetSwapFeeEstimate"
+	MethodDebugVerifyTransaction      = "swap.DebugVerifyTransaction"
+	MethodIsValidSwapinBindAddress    = "swap.IsValidSwapinBindAddress"
+	MethodIsValidSwapoutBindAddress   = "swap.IsValidSwapoutBindAddress"
+	MethodValidateBindAddress         = "swap.ValidateBindAddress"
+	MethodValidateBindAddresses       = "swap.ValidateBindAddresses"
+	MethodRegisterP2shAddress         = "swap.RegisterP2shAddress"
+	MethodGetP2shAddressInfo          = "swap.GetP2shAddressInfo"
+	MethodGetP2shAddressByBind        = "swap.GetP2shAddressByBind"
+	MethodListP2shAddresses           = "swap.ListP2shAddresses"
+	MethodRegisterP2shAddressBatch    = "swap.RegisterP2shAddressBatch"
+	MethodGetP2shBatchJob             = "swap.GetP2shBatchJob"
+	MethodGetLatestScanInfo           = "swap.GetLatestScanInfo"
+	MethodRegisterAddress             = "swap.RegisterAddress"
+	MethodRegisterAddresses           = "swap.RegisterAddresses"
+	MethodGetRegisterAddressChallenge = "swap.GetRegisterAddressChallenge"
+	MethodRegisterAddressWithProof    = "swap.RegisterAddressWithProof"
+	MethodGetRegisteredAddress        = "swap.GetRegisteredAddress"
+	MethodListRegisteredAddresses     = "swap.ListRegisteredAddresses"
+	MethodAdminCall                   = "swap.AdminCall"
+)
+
+// Methods all methods of swap json rpc service
+var Methods = []string{
+	MethodGetVersionInfo,
+	MethodGetServerInfo,
+	MethodGetHealth,
+	MethodUpdateOracleHeartbeat,
+	MethodGetOraclesHeartbeat,
+	MethodGetOraclesJobStatus,
+	MethodGetRetryMetrics,
+	MethodGetRegisterLimitMetrics,
+	MethodGetAPITokenMetrics,
+	MethodGetQuarantineMetrics,
+	MethodGetDailyReport,
+	MethodGetAllSwapStatistics,
+	MethodGetPendingSwapCounts,
+	MethodGetSwapVolumeHistory,
+	MethodGetSwapEvents,
+	MethodGetStatusInfo,
+	MethodGetSigningKey,
+	MethodGetStatusCatalog,
+	MethodGetRegisterErrorTable,
+	MethodGetNativePrices,
+	MethodGetTokenPairInfo,
+	MethodGetTokenPairsInfo,
+	MethodGetNonceInfo,
+	MethodGetRawSwapin,
+	MethodGetRawSwapinResult,
+	MethodGetSwapin,
+	MethodGetSwap,
+	MethodGetSwapStatus,
+	MethodGetRawSwapout,
+	MethodGetRawSwapoutResult,
+	MethodGetSwapout,
+	MethodGetSwapinHistory,
+	MethodGetSwapoutHistory,
+	MethodGetSwapinHistoryPage,
+	MethodGetSwapoutHistoryPage,
+	MethodSwapin,
+	MethodRetrySwapin,
+	MethodRetrySwapout,
+	MethodP2shSwapin,
+	MethodSwapout,
+	MethodSwapinBatch,
+	MethodSwapoutBatch,
+	MethodPrevalidateDeposit,
+	MethodGetSwapFeeEstimate,
+	MethodDebugVerifyTransaction,
+	MethodIsValidSwapinBindAddress,
+	MethodIsValidSwapoutBindAddress,
+	MethodValidateBindAddress,
+	MethodValidateBindAddresses,
+	MethodRegisterP2shAddress,
+	MethodGetP2shAddressInfo,
+	MethodGetP2shAddressByBind,
+	MethodListP2shAddresses,
+	MethodRegisterP2shAddressBatch,
+	MethodGetP2shBatchJob,
+	MethodGetLatestScanInfo,
+	MethodRegisterAddress,
+	MethodRegisterAddresses,
+	MethodGetRegisterAddressChallenge,
+	MethodRegisterAddressWithProof,
+	MethodGetRegisteredAddress,
+	MethodListRegisteredAddresses,
+	MethodAdminCall,
+}
@@ -0,0 +1,537 @@
+package swapclient
+
+import "math/big"
+
+// TxAndPairIDArgs args
+type TxAndPairIDArgs struct {
+	TxID   string `json:"txid"`
+	PairID string `json:"pairid"`
+	Bind   string `json:"bind"`
+	Signed bool   `json:"signed"`
+
+	Verbose bool `json:"verbose,omitempty"`
+
+	IdempotencyKey string `json:"idempotencykey,omitempty"`
+}
+
+// SwapBatchArgs args of batch swap registration
+type SwapBatchArgs struct {
+	TxIDs  []string `json:"txids"`
+	PairID string   `json:"pairid"`
+}
+
+// P2shSwapinArgs args
+type P2shSwapinArgs struct {
+	TxID string `json:"txid"`
+	Bind string `json:"bind"`
+
+	IdempotencyKey string `json:"idempotencykey,omitempty"`
+}
+
+// QueryHistoryArgs args
+type QueryHistoryArgs struct {
+	Address string `json:"address"` // up to 20 comma separated addresses
+	PairID  string `json:"pairid"`
+	Offset  int    `json:"offset"`
+	Limit   int    `json:"limit"`
+	Status  string `json:"status"`
+
+	FromTime int64 `json:"fromTime,omitempty"` // unix seconds, 0 means unbounded
+	ToTime   int64 `json:"toTime,omitempty"`
+}
+
+// QueryHistoryPageArgs args, cursor is nextCursor of the previous page (empty for the first page)
+type QueryHistoryPageArgs struct {
+	Address string `json:"address"` // up to 20 comma separated addresses
+	PairID  string `json:"pairid"`
+	Cursor  string `json:"cursor"`
+	Limit   int    `json:"limit"`
+	Status  string `json:"status"`
+
+	FromTime int64 `json:"fromTime,omitempty"` // unix seconds, 0 means unbounded
+	ToTime   int64 `json:"toTime,omitempty"`
+}
+
+// RegisterAddressWithProofArgs args
+type RegisterAddressWithProofArgs struct {
+	Address   string `json:"address"`
+	Timestamp int64  `json:"timestamp"` // timestamp of challenge
+	Signature string `json:"signature"`
+}
+
+// RegisterAddressChallenge challenge to sign by the registering address
+type RegisterAddressChallenge struct {
+	Address   string `json:"address"`
+	Message   string `json:"message"`
+	Timestamp int64  `json:"timestamp"`
+	ExpireAt  int64  `json:"expireAt"`
+}
+
+// GetSwapVolumeHistoryArgs args
+type GetSwapVolumeHistoryArgs struct {
+	PairID   string `json:"pairid"`
+	Interval string `json:"interval"` // day or week
+	From     int64  `json:"from"`     // unix seconds, default to 30 buckets before 'to'
+	To       int64  `json:"to"`       // unix seconds, default to now
+}
+
+// GetSwapEventsArgs args
+type GetSwapEventsArgs struct {
+	SinceSeq int64 `json:"sinceSeq"`
+	Limit    int   `json:"limit"` // default 100, max 1000
+}
+
+// PrevalidateDepositArgs args
+type PrevalidateDepositArgs struct {
+	PairID      string `json:"pairid"`
+	Amount      string `json:"amount"`
+	Bind        string `json:"bind"`
+	DepositType string `json:"depositType"`
+}
+
+// GetSwapFeeEstimateArgs args
+type GetSwapFeeEstimateArgs struct {
+	PairID    string `json:"pairid"`
+	Direction string `json:"direction"` // swapin or swapout
+	Value     string `json:"value"`     // in smallest unit of deposit chain
+	Bind      string `json:"bind"`      // optional, check dust threshold of swapout
+}
+
+// ValidateBindAddressArgs args
+type ValidateBindAddressArgs struct {
+	PairID   string `json:"pairid"`
+	Address  string `json:"address"`
+	IsSwapin bool   `json:"isSwapin"`
+}
+
+// ValidateBindAddressesArgs args, direction is 'swapin' or 'swapout'
+type ValidateBindAddressesArgs struct {
+	Direction string   `json:"direction"`
+	Addresses []string `json:"addresses"`
+}
+
+// DebugVerifyArgs args
+type DebugVerifyArgs struct {
+	PairID        string `json:"pairid"`
+	TxID          string `json:"txid"`
+	IsSwapin      bool   `json:"isSwapin"`
+	AllowUnstable bool   `json:"allowUnstable"`
+}
+
+// PostResult post result
+type PostResult string
+
+// SwapStatusInfo swap status info
+type SwapStatusInfo struct {
+	Code        uint16 `json:"code"`
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	IsTerminal  bool   `json:"isTerminal"`
+	Description string `json:"description"`
+
+	VerifyErrors []string `json:"verifyErrors,omitempty"` // only in catalog
+}
+
+// RegisterErrorEntry entry of register swap error table,
+// swaps failed verifying with error which register is true are still registered.
+type RegisterErrorEntry struct {
+	Name     string `json:"name"`
+	Error    string `json:"error"`
+	Register bool   `json:"register"`
+	Default  bool   `json:"default"`
+}
+
+// NativePrice native coin price in reference currency,
+// rate and timestamp are nil if the price is missing or stale.
+type NativePrice struct {
+	Symbol    string   `json:"symbol"`
+	Currency  string   `json:"currency"`
+	Rate      *float64 `json:"rate"`
+	Timestamp *int64   `json:"timestamp"`
+}
+
+// ConvertedValue native value converted to reference currency,
+// value is null if the price is missing or stale.
+type ConvertedValue struct {
+	NativePrice
+	Value *float64 `json:"value"`
+}
+
+// NativePricesInfo native coin prices of source and dest chain
+type NativePricesInfo struct {
+	Src  *NativePrice `json:"src"`
+	Dest *NativePrice `json:"dest"`
+}
+
+// Proof response signature proof
+type Proof struct {
+	Identifier string `json:"identifier"`
+	Timestamp  int64  `json:"timestamp"`
+	Signer     string `json:"signer"`
+	Signature  string `json:"signature"`
+}
+
+// SwapInfo swap info
+type SwapInfo struct {
+	PairID        string          `json:"pairid"`
+	TxID          string          `json:"txid"`
+	TxTo          string          `json:"txto"`
+	TxHeight      uint64          `json:"txheight"`
+	From          string          `json:"from"`
+	To            string          `json:"to"`
+	Bind          string          `json:"bind"`
+	Value         string          `json:"value"`
+	SwapTx        string          `json:"swaptx"`
+	SwapHeight    uint64          `json:"swapheight"`
+	SwapValue     string          `json:"swapvalue"`
+	SwapType      uint32          `json:"swaptype"`
+	SwapNonce     uint64          `json:"swapnonce"`
+	Status        uint16          `json:"status"`
+	StatusMsg     string          `json:"statusmsg"`
+	StatusInfo    *SwapStatusInfo `json:"statusinfo"`
+	InitTime      int64           `json:"inittime"`
+	Timestamp     int64           `json:"timestamp"`
+	Memo          string          `json:"memo"`
+	ReplaceCount  int             `json:"replaceCount"`
+	Confirmations uint64          `json:"confirmations"`
+
+	RefundTx       string `json:"refundtx,omitempty"`
+	MatchedAddress string `json:"matchedAddress,omitempty"`
+	Direction      string `json:"direction,omitempty"`
+
+	TxConfirmations       *int64 `json:"txConfirmations,omitempty"`
+	RequiredConfirmations uint64 `json:"requiredConfirmations,omitempty"`
+
+	Proof *Proof `json:"proof,omitempty"`
+}
+
+// SwapPollStatus simplified swap status for high frequency polling
+type SwapPollStatus struct {
+	SwapType      uint32 `json:"swaptype"`
+	Status        uint16 `json:"status"`
+	StatusMsg     string `json:"statusmsg"`
+	SwapTx        string `json:"swaptx"`
+	Confirmations uint64 `json:"confirmations"`
+}
+
+// DepositViolation deposit violation
+type DepositViolation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// PrevalidateResult prevalidate deposit result
+type PrevalidateResult struct {
+	Valid                    bool                `json:"valid"`
+	Violations               []*DepositViolation `json:"violations"`
+	PairID                   string              `json:"pairid"`
+	DepositType              string              `json:"depositType"`
+	DepositAddress           string              `json:"depositAddress"`
+	Bind                     string              `json:"bind"`
+	Value                    string              `json:"value"`
+	SwapValue                string              `json:"swapvalue"`
+	SwapFee                  string              `json:"swapfee"`
+	ValueDisplay             string              `json:"valueDisplay,omitempty"`
+	SwapValueDisplay         string              `json:"swapvalueDisplay,omitempty"`
+	DustThreshold            string              `json:"dustThreshold,omitempty"`
+	IsBigValue               bool                `json:"isBigValue"`
+	BigValueRule             string              `json:"bigValueRule,omitempty"`
+	RequiredConfirmations    uint64              `json:"requiredConfirmations"`
+	MinRegisterConfirmations uint64              `json:"minRegisterConfirmations,omitempty"`
+}
+
+// SwapFeeEstimate fee quote of a prospective swap
+type SwapFeeEstimate struct {
+	PairID            string `json:"pairid"`
+	Direction         string `json:"direction"`
+	InputValue        string `json:"inputValue"`
+	Fee               string `json:"fee"`
+	OutputValue       string `json:"outputValue"`
+	MinSwap           string `json:"minSwap"`
+	MaxSwap           string `json:"maxSwap"`
+	BigValueThreshold string `json:"bigValueThreshold"`
+	BelowMinimum      bool   `json:"belowMinimum"`
+	AboveMaximum      bool   `json:"aboveMaximum"`
+	NotEnoughForFee   bool   `json:"notEnoughForFee"`
+	IsBigValue        bool   `json:"isBigValue"`
+	DustThreshold     string `json:"dustThreshold,omitempty"`
+	IsDust            bool   `json:"isDust"`
+}
+
+// SwapHistoryPage page of swap history
+type SwapHistoryPage struct {
+	Items          []*SwapInfo `json:"items"`
+	NextCursor     string      `json:"nextCursor"`
+	Total          int64       `json:"total"`
+	TotalEstimated bool        `json:"totalEstimated,omitempty"`
+}
+
+// BindAddressValidation bind address validation result
+type BindAddressValidation struct {
+	Valid      bool     `json:"valid"`
+	Normalized string   `json:"normalized,omitempty"`
+	Reasons    []string `json:"reasons,omitempty"`
+}
+
+// TxSwapInfo decoded swap info of tx
+type TxSwapInfo struct {
+	PairID    string   `json:"pairid"`
+	Hash      string   `json:"hash"`
+	Height    uint64   `json:"height"`
+	Timestamp uint64   `json:"timestamp"`
+	From      string   `json:"from"`
+	TxTo      string   `json:"txto"`
+	To        string   `json:"to"`
+	Bind      string   `json:"bind"`
+	Value     *big.Int `json:"value"`
+}
+
+// VerifyCheck result of one verification check
+type VerifyCheck struct {
+	Name  string `json:"name"`
+	Pass  bool   `json:"pass"`
+	Error string `json:"error,omitempty"`
+}
+
+// DebugVerifyResult dry run verification result
+type DebugVerifyResult struct {
+	PairID        string         `json:"pairid"`
+	TxID          string         `json:"txid"`
+	IsSwapin      bool           `json:"isSwapin"`
+	AllowUnstable bool           `json:"allowUnstable"`
+	SwapInfo      *TxSwapInfo    `json:"swapInfo"`
+	Checks        []*VerifyCheck `json:"checks"`
+	Error         string         `json:"error,omitempty"`
+	ErrorChain    []string       `json:"errorChain,omitempty"`
+	WouldRegister bool           `json:"wouldRegister"`
+}
+
+// P2shAddress p2sh address of bind address, timestamp is registration time
+type P2shAddress struct {
+	Key         string // bind address
+	P2shAddress string
+	Timestamp   int64
+	LastDeposit int64
+	ActiveTime  int64
+	Inactive    bool
+}
+
+// ListP2shAddressesArgs args
+type ListP2shAddressesArgs struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// RegisteredAddress registered address
+type RegisteredAddress struct {
+	Key         string
+	Timestamp   int64
+	Source      string
+	BindTo      string
+	Registry    string
+	TxHash      string
+	BlockHeight uint64
+}
+
+// ListRegisteredAddressesArgs args, prefix filters addresses case insensitively,
+// fromTime and toTime (unix seconds, inclusive) filter register time, zero means unbounded.
+type ListRegisteredAddressesArgs struct {
+	Offset   int    `json:"offset"`
+	Limit    int    `json:"limit"`
+	Prefix   string `json:"prefix"`
+	FromTime int64  `json:"fromTime,omitempty"`
+	ToTime   int64  `json:"toTime,omitempty"`
+}
+
+// RegisteredAddressEntry registered address with its linked p2sh address
+type RegisteredAddressEntry struct {
+	RegisteredAddress
+	P2shAddress string `json:",omitempty"`
+}
+
+// RegisteredAddressList page of registered addresses
+type RegisteredAddressList struct {
+	Total     int64                     `json:"total"`
+	Offset    int                       `json:"offset"`
+	Limit     int                       `json:"limit"`
+	Addresses []*RegisteredAddressEntry `json:"addresses"`
+}
+
+// AcceptJobStatus oracle accept job status
+type AcceptJobStatus struct {
+	PollInterval  string `json:"pollInterval"` // current effective interval
+	WaitInterval  string `json:"waitInterval"`
+	RetryInterval string `json:"retryInterval"`
+	Failures      int    `json:"failures"` // consecutive errors or empty results
+	Timestamp     int64  `json:"timestamp"`
+
+	// sign infos of the last round
+	Dispatched       int `json:"dispatched"`
+	SkippedCached    int `json:"skippedCached"`    // in memory cache
+	SkippedProcessed int `json:"skippedProcessed"` // persisted processed records
+
+	TotalDispatched       uint64 `json:"totalDispatched"`
+	TotalSkippedCached    uint64 `json:"totalSkippedCached"`
+	TotalSkippedProcessed uint64 `json:"totalSkippedProcessed"`
+}
+
+// DailyReport daily summary report
+type DailyReport struct {
+	Key             string // date
+	TimeZone        string
+	StartTime       int64
+	EndTime         int64
+	GenerateTime    int64
+	Pairs           []*DailyPairStat
+	SwapinBacklogs  map[string]int64
+	SwapoutBacklogs map[string]int64
+	Text            string
+}
+
+// DailyPairStat daily swap statistics of pair in one direction
+type DailyPairStat struct {
+	PairID     string
+	IsSwapin   bool
+	Completed  int
+	Failed     int
+	Held       int
+	Volume     string
+	Fee        string
+	AvgLatency int64
+}
+
+// DirectionStatistics swap statistics of pair in one direction
+type DirectionStatistics struct {
+	Total   int64  `json:"total"`
+	Pending int64  `json:"pending"`
+	Success int64  `json:"success"`
+	Failed  int64  `json:"failed"`
+	Manual  int64  `json:"manual"`
+	Volume  string `json:"volume"`
+	Fee     string `json:"fee"`
+}
+
+// SwapStatistics swap statistics of pair
+type SwapStatistics struct {
+	Swapin  *DirectionStatistics `json:"swapin,omitempty"`
+	Swapout *DirectionStatistics `json:"swapout,omitempty"`
+}
+
+// HealthCheck result of one health check
+type HealthCheck struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// ChainHealth health of chain
+type ChainHealth struct {
+	LatestBlock   uint64       `json:"latestBlock"`
+	LatestScanned uint64       `json:"latestScanned"`
+	ScanLag       uint64       `json:"scanLag"`
+	LastScanTime  int64        `json:"lastScanTime"`
+	ScanActive    bool         `json:"scanActive"`
+	Node          *HealthCheck `json:"node"`
+	ScanInfo      *HealthCheck `json:"scanInfo"`
+}
+
+// DcrmHealth health of dcrm
+type DcrmHealth struct {
+	Enabled   bool         `json:"enabled"`
+	Reachable *HealthCheck `json:"reachable,omitempty"`
+}
+
+// HealthInfo health of bridge
+type HealthInfo struct {
+	OK        bool         `json:"ok"`
+	SrcChain  *ChainHealth `json:"srcChain"`
+	DestChain *ChainHealth `json:"destChain"`
+	Mongodb   *HealthCheck `json:"mongodb"`
+	Dcrm      *DcrmHealth  `json:"dcrm"`
+	CheckedAt int64        `json:"checkedAt"`
+}
+
+// AllSwapStatistics swap statistics of all pairs
+type AllSwapStatistics struct {
+	Pairs         map[string]*SwapStatistics `json:"pairs"`
+	TotalSwapins  int64                      `json:"totalSwapins"`
+	TotalSwapouts int64                      `json:"totalSwapouts"`
+	TotalFees     map[string]string          `json:"totalFees"`
+	ConvertedFees []*ConvertedValue          `json:"convertedFees,omitempty"`
+	GeneratedAt   int64                      `json:"generatedAt"`
+}
+
+// PendingCounts pending swaps of one direction by stage
+type PendingCounts struct {
+	NotStable     int64 `json:"notStable"`
+	Building      int64 `json:"building"`
+	Signing       int64 `json:"signing"`
+	MaxSigningAge int64 `json:"maxSigningAge"`
+	Sending       int64 `json:"sending"`
+}
+
+// AllPendingCounts pending swaps of both directions
+type AllPendingCounts struct {
+	Swapin      *PendingCounts `json:"swapin"`
+	Swapout     *PendingCounts `json:"swapout"`
+	GeneratedAt int64          `json:"generatedAt"`
+}
+
+// VolumeBucket success swaps in time bucket
+type VolumeBucket struct {
+	Start  int64  `json:"start"`
+	Count  int64  `json:"count"`
+	Volume string `json:"volume"`
+	Fee    string `json:"fee"`
+}
+
+// SwapVolumeHistory volume of pair over time
+type SwapVolumeHistory struct {
+	PairID   string          `json:"pairid"`
+	Interval string          `json:"interval"`
+	Swapin   []*VolumeBucket `json:"swapin"`
+	Swapout  []*VolumeBucket `json:"swapout"`
+}
+
+// SwapEvent swap change event, status, swaptx and memo are written by the change
+type SwapEvent struct {
+	Seq       int64  `json:"seq"`
+	IsSwapin  bool   `json:"isSwapin"`
+	SwapKey   string `json:"swapKey"`
+	PairID    string `json:"pairid"`
+	TxID      string `json:"txid"`
+	Bind      string `json:"bind"`
+	Status    uint16 `json:"status"`
+	SwapTx    string `json:"swaptx,omitempty"`
+	Memo      string `json:"memo,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// RetryMetrics retry metrics of call site
+type RetryMetrics struct {
+	Attempts uint64 `json:"attempts"`
+	Retries  uint64 `json:"retries"`
+	GaveUp   uint64 `json:"gaveUp"`
+}
+
+// RegisterLimitMetrics rejected public register calls of method
+type RegisterLimitMetrics struct {
+	RejectedByPrefilter uint64 `json:"rejectedByPrefilter"`
+	RejectedByRateLimit uint64 `json:"rejectedByRateLimit"`
+	RejectedByInProcess uint64 `json:"rejectedByInProcess"`
+	TxNotFoundCacheHits uint64 `json:"txNotFoundCacheHits"`
+}
+
+// APITokenMetrics usage counters of api token
+type APITokenMetrics struct {
+	Calls               uint64 `json:"calls"`
+	RejectedByScope     uint64 `json:"rejectedByScope"`
+	RejectedByRateLimit uint64 `json:"rejectedByRateLimit"`
+	LastCallTime        int64  `json:"lastCallTime"`
+}
+
+// QuarantineMetrics quarantine metrics, alert when 'Quarantined' is not empty
+type QuarantineMetrics struct {
+	QuarantinedTotal uint64           `json:"quarantinedTotal"`
+	RequeuedTotal    uint64           `json:"requeuedTotal"`
+	Quarantined      map[string]int64 `json:"quarantined"`
+}
@@ -0,0 +1,28 @@
+package tokens
+
+// machine readable reasons of invalid address
+const (
+	AddressReasonEmpty           = "empty"
+	AddressReasonWrongFormat     = "wrong_format"
+	AddressReasonWrongLength     = "wrong_length"
+	AddressReasonBadChecksum     = "bad_checksum"
+	AddressReasonWrongNetwork    = "wrong_network"
+	AddressReasonIsContract      = "is_contract"
+	AddressReasonCodeCheckFailed = "code_check_failed"
+)
+
+// ValidateAddress validate address by bridge, return normalized address
+// and reasons if it's invalid. bridges which are not AddressValidator
+// only report 'wrong_format' for invalid address.
+func ValidateAddress(bridge CrossChainBridge, address string) (normalized string, reasons []string) {
+	if address == "" {
+		return "", []string{AddressReasonEmpty}
+	}
+	if validator, ok := bridge.(AddressValidator); ok {
+		return validator.ValidateAddress(address)
+	}
+	if !bridge.IsValidAddress(address) {
+		return "", []string{AddressReasonWrongFormat}
+	}
+	return address, nil
+}
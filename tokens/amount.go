@@ -0,0 +1,82 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	cmath "github.com/anyswap/CrossChain-Bridge/common/math"
+)
+
+// amount validation errors
+var (
+	ErrNilAmount       = errors.New("amount is nil")
+	ErrNegativeAmount  = errors.New("amount is negative")
+	ErrAmountOverflow  = errors.New("amount overflow")
+	ErrAmountMismatch  = errors.New("amount mismatch with swap value")
+	ErrWrongAmountSize = errors.New("wrong amount bit size")
+)
+
+// AmountSpec native integer representation of amount in chain
+type AmountSpec struct {
+	BitSize  uint // eg. 64 for int64/uint64
+	IsSigned bool
+	Decimals uint8
+}
+
+// common amount specs
+var (
+	Int64AmountSpec  = &AmountSpec{BitSize: 64, IsSigned: true}
+	Uint64AmountSpec = &AmountSpec{BitSize: 64}
+)
+
+// WithDecimals copy amount spec with token decimals
+func (s *AmountSpec) WithDecimals(decimals uint8) *AmountSpec {
+	spec := *s
+	spec.Decimals = decimals
+	return &spec
+}
+
+// MaxAmount max amount can be represented
+func (s *AmountSpec) MaxAmount() *big.Int {
+	bitSize := s.BitSize
+	if s.IsSigned {
+		bitSize--
+	}
+	maxAmount := new(big.Int).Lsh(big.NewInt(1), bitSize)
+	return maxAmount.Sub(maxAmount, big.NewInt(1))
+}
+
+// CheckAmount check amount is valid to pack into chain's native integer,
+// and is consistent with the recorded swap value if it is not nil.
+func CheckAmount(amount *big.Int, spec *AmountSpec, swapValue *big.Int) error {
+	switch {
+	case amount == nil:
+		return ErrNilAmount
+	case spec.BitSize == 0:
+		return ErrWrongAmountSize
+	case amount.Sign() < 0:
+		return fmt.Errorf("%w: %v", ErrNegativeAmount, amount)
+	}
+	if maxAmount := spec.MaxAmount(); amount.Cmp(maxAmount) > 0 {
+		return fmt.Errorf("%w: %v (%v) exceeds max %v of %v bits integer",
+			ErrAmountOverflow, amount, formatAmount(amount, spec.Decimals), maxAmount, spec.BitSize)
+	}
+	if swapValue != nil && amount.Cmp(swapValue) != 0 {
+		return fmt.Errorf("%w: amount %v, swap value %v", ErrAmountMismatch, amount, swapValue)
+	}
+	return nil
+}
+
+func formatAmount(amount *big.Int, decimals uint8) string {
+	unit := cmath.BigPow(10, int64(decimals))
+	return new(big.Rat).SetFrac(amount, unit).FloatString(int(decimals))
+}
+
+// FormatValue format value in smallest unit for display with token decimals and symbol (eg. "0.00000546 BTC")
+func (c *TokenConfig) FormatValue(value *big.Int) string {
+	if value == nil {
+		return ""
+	}
+	return formatAmount(value, *c.Decimals) + " " + c.Symbol
+}
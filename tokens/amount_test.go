@@ -0,0 +1,53 @@
+package tokens
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestCheckAmount(t *testing.T) {
+	maxUint64 := new(big.Int).SetUint64(math.MaxUint64)
+	overUint64 := new(big.Int).Add(maxUint64, big.NewInt(1))
+	maxInt64 := big.NewInt(math.MaxInt64)
+	overInt64 := new(big.Int).Add(maxInt64, big.NewInt(1))
+
+	cases := []struct {
+		amount    *big.Int
+		spec      *AmountSpec
+		swapValue *big.Int
+		wantErr   error
+	}{
+		{nil, Uint64AmountSpec, nil, ErrNilAmount},
+		{big.NewInt(0), Uint64AmountSpec, nil, nil},
+		{big.NewInt(-1), Uint64AmountSpec, nil, ErrNegativeAmount},
+		{maxUint64, Uint64AmountSpec, nil, nil},
+		{overUint64, Uint64AmountSpec, nil, ErrAmountOverflow},
+		{maxUint64, Int64AmountSpec, nil, ErrAmountOverflow},
+		{maxInt64, Int64AmountSpec.WithDecimals(8), nil, nil},
+		{overInt64, Int64AmountSpec.WithDecimals(8), nil, ErrAmountOverflow},
+		{maxUint64, Uint64AmountSpec.WithDecimals(18), maxUint64, nil},
+		{maxUint64, Uint64AmountSpec.WithDecimals(18), big.NewInt(1), ErrAmountMismatch},
+		{big.NewInt(1), &AmountSpec{}, nil, ErrWrongAmountSize},
+	}
+	for i, c := range cases {
+		err := CheckAmount(c.amount, c.spec, c.swapValue)
+		if c.wantErr == nil {
+			if err != nil {
+				t.Errorf("case %v: unexpected error %v", i, err)
+			}
+		} else if !errors.Is(err, c.wantErr) {
+			t.Errorf("case %v: want error %v, got %v", i, c.wantErr, err)
+		}
+	}
+}
+
+func TestAmountSpecMaxAmount(t *testing.T) {
+	if have := Uint64AmountSpec.MaxAmount(); !have.IsUint64() || have.Uint64() != math.MaxUint64 {
+		t.Errorf("wrong max amount of uint64, have %v", have)
+	}
+	if have := Int64AmountSpec.MaxAmount(); !have.IsInt64() || have.Int64() != math.MaxInt64 {
+		t.Errorf("wrong max amount of int64, have %v", have)
+	}
+}
@@ -1,8 +1,10 @@
 package tokens
 
 import (
+	"fmt"
 	"math"
 	"math/big"
+	"strings"
 
 	cmath "github.com/anyswap/CrossChain-Bridge/common/math"
 	"github.com/anyswap/CrossChain-Bridge/log"
@@ -21,6 +23,7 @@ const (
 // common variables
 var (
 	AggregateIdentifier = "aggregate"
+	RefundIdentifier    = "refund"
 
 	SrcBridge CrossChainBridge
 	DstBridge CrossChainBridge
@@ -39,6 +42,8 @@ var (
 
 	IsDcrmDisabled bool
 
+	DcrmGroupPubkeys map[string]string // dcrm group profile name -> public key
+
 	IsSwapoutToStringAddress bool
 
 	TokenPriceCfg *TokenPriceConfig
@@ -66,11 +71,18 @@ func (b *CrossChainBridgeBase) SetChainAndGateway(chainCfg *ChainConfig, gateway
 	b.ChainConfig = chainCfg
 	b.GatewayConfig = gatewayCfg
 
-	if len(gatewayCfg.APIAddress) == 0 {
+	if len(gatewayCfg.GetAPIAddress()) == 0 {
 		log.Fatal("empty gateway 'APIAddress'")
 	}
 }
 
+// SetChainAndGatewayConfig only set chain and gateway config,
+// without verifying and initializing by rpc calls (eg. in offline replaying)
+func (b *CrossChainBridgeBase) SetChainAndGatewayConfig(chainCfg *ChainConfig, gatewayCfg *GatewayConfig) {
+	b.ChainConfig = chainCfg
+	b.GatewayConfig = gatewayCfg
+}
+
 // GetChainConfig get chain config
 func (b *CrossChainBridgeBase) GetChainConfig() *ChainConfig {
 	return b.ChainConfig
@@ -179,6 +191,20 @@ func GetBigValueThreshold(pairID string, isSrc bool) *big.Int {
 	return token.bigValThreshhold
 }
 
+// GetSwapValueRange get minimum and maximum swap value,
+// maximum is tightened by on-chain mint cap of the counterpart token
+func GetSwapValueRange(pairID string, isSrc bool) (minSwap, maxSwap *big.Int) {
+	token, cpToken := GetTokenConfigsByDirection(pairID, isSrc)
+	if token == nil {
+		return nil, nil
+	}
+	maxSwap = token.maxSwap
+	if mintCap := getMintCapOfDeposit(pairID, isSrc, token, cpToken); mintCap != nil && (maxSwap == nil || mintCap.Cmp(maxSwap) < 0) {
+		maxSwap = mintCap
+	}
+	return token.minSwap, maxSwap
+}
+
 // CheckSwapValue check swap value is in right range
 func CheckSwapValue(inf *TxSwapInfo, isSrc bool) bool {
 	return CalcSwappedValue(inf.PairID, inf.Value, isSrc, inf.From, inf.TxTo).Sign() > 0
@@ -202,38 +228,17 @@ func CalcSwappedValue(pairID string, value *big.Int, isSrc bool, from, txto stri
 		return big.NewInt(0)
 	}
 
+	// minting over cap reverts on-chain, even for big value whitelist
+	if mintCap := getMintCapOfDeposit(pairID, isSrc, token, cpToken); mintCap != nil && value.Cmp(mintCap) > 0 {
+		log.Warn("swap value exceeds mint cap", "pairID", pairID, "value", value, "isSrc", isSrc, "mintCap", mintCap)
+		return big.NewInt(0)
+	}
+
 	if *token.SwapFeeRate == 0.0 {
 		return ConvertTokenValue(value, *token.Decimals, *cpToken.Decimals)
 	}
 
-	var swapFee, adjustBaseFee *big.Int
-
-	if isInBigValueWhitelist {
-		swapFee = token.minSwapFee
-	} else {
-		feeRateMul1e18 := new(big.Int).SetUint64(uint64(*token.SwapFeeRate * 1e18))
-		swapFee = new(big.Int).Mul(value, feeRateMul1e18)
-		swapFee.Div(swapFee, big.NewInt(1e18))
-
-		if swapFee.Cmp(token.minSwapFee) < 0 {
-			swapFee = token.minSwapFee
-		} else if swapFee.Cmp(token.maxSwapFee) > 0 {
-			swapFee = token.maxSwapFee
-		}
-
-		if GetNonceSetter(!isSrc) != nil { // eth-like
-			chainCfg := GetCrossChainBridge(!isSrc).GetChainConfig()
-			if chainCfg.BaseFeePercent != 0 && token.minSwapFee.Sign() > 0 {
-				adjustBaseFee = new(big.Int).Set(token.minSwapFee)
-				adjustBaseFee.Mul(adjustBaseFee, big.NewInt(chainCfg.BaseFeePercent))
-				adjustBaseFee.Div(adjustBaseFee, big.NewInt(100))
-				swapFee = new(big.Int).Add(swapFee, adjustBaseFee)
-				if swapFee.Sign() < 0 {
-					swapFee = big.NewInt(0)
-				}
-			}
-		}
-	}
+	swapFee, adjustBaseFee := calcSwapFee(token, value, isSrc, isInBigValueWhitelist)
 
 	if value.Cmp(swapFee) <= 0 {
 		log.Warn("check swap value failed", "pairID", pairID, "value", value, "isSrc", isSrc,
@@ -249,6 +254,65 @@ func CalcSwappedValue(pairID string, value *big.Int, isSrc bool, from, txto stri
 	return ConvertTokenValue(swappedValue, *token.Decimals, *cpToken.Decimals)
 }
 
+// CalcSwapFee calc swap fee of value (not in big value whitelist), same as deducted by CalcSwappedValue
+func CalcSwapFee(pairID string, value *big.Int, isSrc bool) *big.Int {
+	token, _ := GetTokenConfigsByDirection(pairID, isSrc)
+	if token == nil || value == nil || value.Sign() <= 0 || *token.SwapFeeRate == 0.0 {
+		return big.NewInt(0)
+	}
+	swapFee, _ := calcSwapFee(token, value, isSrc, false)
+	return swapFee
+}
+
+func calcSwapFee(token *TokenConfig, value *big.Int, isSrc, isInBigValueWhitelist bool) (swapFee, adjustBaseFee *big.Int) {
+	if isInBigValueWhitelist {
+		return token.minSwapFee, nil
+	}
+
+	feeRateMul1e18 := new(big.Int).SetUint64(uint64(*token.SwapFeeRate * 1e18))
+	swapFee = new(big.Int).Mul(value, feeRateMul1e18)
+	swapFee.Div(swapFee, big.NewInt(1e18))
+
+	if swapFee.Cmp(token.minSwapFee) < 0 {
+		swapFee = token.minSwapFee
+	} else if swapFee.Cmp(token.maxSwapFee) > 0 {
+		swapFee = token.maxSwapFee
+	}
+
+	if GetNonceSetter(!isSrc) != nil { // eth-like
+		chainCfg := GetCrossChainBridge(!isSrc).GetChainConfig()
+		if chainCfg.BaseFeePercent != 0 && token.minSwapFee.Sign() > 0 {
+			adjustBaseFee = new(big.Int).Set(token.minSwapFee)
+			adjustBaseFee.Mul(adjustBaseFee, big.NewInt(chainCfg.BaseFeePercent))
+			adjustBaseFee.Div(adjustBaseFee, big.NewInt(100))
+			swapFee = new(big.Int).Add(swapFee, adjustBaseFee)
+			if swapFee.Sign() < 0 {
+				swapFee = big.NewInt(0)
+			}
+		}
+	}
+	return swapFee, adjustBaseFee
+}
+
+// CalcRefundValue calc value refunded to depositor (deposit value minus refund fee)
+// on the deposit chain, return zero if the deposit can not cover the fee
+func CalcRefundValue(pairID string, value *big.Int, isSrc bool) *big.Int {
+	if value == nil || value.Sign() <= 0 {
+		return big.NewInt(0)
+	}
+	token, _ := GetTokenConfigsByDirection(pairID, isSrc)
+	if token == nil {
+		return big.NewInt(0)
+	}
+	if token.refundFee == nil {
+		return new(big.Int).Set(value)
+	}
+	if value.Cmp(token.refundFee) <= 0 {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Sub(value, token.refundFee)
+}
+
 // SetLatestBlockHeight set latest block height
 func SetLatestBlockHeight(latest uint64, isSrc bool) {
 	if isSrc {
@@ -278,3 +342,53 @@ func GetStableConfirmations(isSrc bool) uint64 {
 	}
 	return DstStableConfirmations
 }
+
+// GetPairStableConfirmations get stable confirmations of pair on source or destination chain
+func GetPairStableConfirmations(pairID string, isSrc bool) uint64 {
+	pairCfg, exist := tokenPairsConfig[strings.ToLower(pairID)]
+	if exist {
+		if isSrc && pairCfg.SrcStableConfirmations != nil {
+			return *pairCfg.SrcStableConfirmations
+		}
+		if !isSrc && pairCfg.DstStableConfirmations != nil {
+			return *pairCfg.DstStableConfirmations
+		}
+	}
+	return GetStableConfirmations(isSrc)
+}
+
+// GetPairMinRegisterConfirmations get minimum confirmations of deposit tx before registering swapin
+func GetPairMinRegisterConfirmations(pairID string) uint64 {
+	pairCfg, exist := tokenPairsConfig[strings.ToLower(pairID)]
+	if exist {
+		return pairCfg.MinRegisterConfirmations
+	}
+	return 0
+}
+
+// IsRiskHookFailOpen whether to sign swap of pair if risk hook is unavailable
+func IsRiskHookFailOpen(pairID string, defaultFailOpen bool) bool {
+	pairCfg, exist := tokenPairsConfig[strings.ToLower(pairID)]
+	if exist && pairCfg.RiskHookFailOpen != nil {
+		return *pairCfg.RiskHookFailOpen
+	}
+	return defaultFailOpen
+}
+
+// CheckRegisterDepth check confirmations of deposit tx in block of height (0 if pending),
+// which are counted the same as stable confirmations (latest - height).
+// returned error wraps ErrTxTooRecent with blocks to wait and current depth.
+func CheckRegisterDepth(pairID string, height, latest uint64) error {
+	required := GetPairMinRegisterConfirmations(pairID)
+	if required == 0 {
+		return nil
+	}
+	var depth uint64
+	if height > 0 && latest > height {
+		depth = latest - height
+	}
+	if depth >= required {
+		return nil
+	}
+	return fmt.Errorf("%w, retry after %v blocks (current depth %v, required %v)", ErrTxTooRecent, required-depth, depth, required)
+}
@@ -0,0 +1,133 @@
+package tokens
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+// big value rules
+const (
+	BigValueRuleToken      = "token"
+	BigValueRuleUSD        = "usd"
+	BigValueRuleStalePrice = "usd-stale-price"
+)
+
+// policies of USD big value threshold when price is missing or stale
+const (
+	StalePriceFailOpen   = "failopen"
+	StalePriceFailClosed = "failclosed"
+)
+
+const defaultBigValuePriceMaxAge = 3600 // seconds
+
+// BigValueCheck big value check result
+type BigValueCheck struct {
+	IsBigValue bool
+	Rules      []string // triggered rules
+	Detail     string
+}
+
+// Rule get triggered rules joined by ','
+func (c *BigValueCheck) Rule() string {
+	return strings.Join(c.Rules, ",")
+}
+
+// Memo get memo of big value
+func (c *BigValueCheck) Memo() string {
+	if !c.IsBigValue {
+		return ""
+	}
+	return fmt.Sprintf("big value (rule: %v): %v", c.Rule(), c.Detail)
+}
+
+func (c *BigValueCheck) trigger(rule, detail string) {
+	c.IsBigValue = true
+	c.Rules = append(c.Rules, rule)
+	if c.Detail != "" {
+		c.Detail += "; "
+	}
+	c.Detail += detail
+}
+
+// CheckBigValue check deposit value by the token units and USD thresholds,
+// either exceeded is big value (the more restrictive wins).
+// all stages should call this to evaluate big value identically.
+func CheckBigValue(pairID string, value *big.Int, isSrc bool, from, txto string) *BigValueCheck {
+	result := &BigValueCheck{}
+	token := GetTokenConfig(pairID, isSrc)
+	if token == nil || value == nil {
+		return result
+	}
+	if token.IsInBigValueWhitelist(from) || token.IsInBigValueWhitelist(txto) {
+		return result
+	}
+	if token.bigValThreshhold != nil && value.Cmp(token.bigValThreshhold) > 0 {
+		result.trigger(BigValueRuleToken, fmt.Sprintf("value %v > threshold %v", value, token.bigValThreshhold))
+	}
+	if token.BigValueThresholdUSD == nil {
+		return result
+	}
+	price, ok := token.getUSDPrice(isSrc)
+	if !ok {
+		if token.BigValueOnStalePrice == StalePriceFailClosed {
+			result.trigger(BigValueRuleStalePrice, "token price in USD is missing or stale")
+		} else {
+			log.Warn("ignore USD big value threshold as token price is missing or stale", "pairID", pairID, "isSrc", isSrc)
+		}
+		return result
+	}
+	usdValue := new(big.Float).SetInt(value)
+	usdValue.Quo(usdValue, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(*token.Decimals)), nil)))
+	usdValue.Mul(usdValue, big.NewFloat(price))
+	if usdValue.Cmp(big.NewFloat(*token.BigValueThresholdUSD)) > 0 {
+		result.trigger(BigValueRuleUSD, fmt.Sprintf("USD value %v > threshold %v (price %v)",
+			usdValue.Text('f', 2), *token.BigValueThresholdUSD, price))
+	}
+	return result
+}
+
+// getUSDPrice get token price in USD, return false if it's missing or stale.
+// native coin uses the native price table, others use the loaded token price.
+func (c *TokenConfig) getUSDPrice(isSrc bool) (float64, bool) {
+	if c.isNativeCoin(isSrc) {
+		price := GetNativePrice(isSrc)
+		if price == nil || price.Rate == nil {
+			return 0, false
+		}
+		return *price.Rate, true
+	}
+	maxAge := c.BigValuePriceMaxAge
+	if maxAge == 0 {
+		maxAge = defaultBigValuePriceMaxAge
+	}
+	if c.TokenPrice <= 0 || c.tokenPriceTime+int64(maxAge) < time.Now().Unix() {
+		return 0, false
+	}
+	return c.TokenPrice, true
+}
+
+func (c *TokenConfig) isNativeCoin(isSrc bool) bool {
+	return NativePriceCfg != nil && strings.EqualFold(NativePriceCfg.Currency, "USD") && c.IsNativeCoin(isSrc)
+}
+
+func (c *TokenConfig) checkBigValueConfig() error {
+	if c.BigValueThreshold == nil && c.BigValueThresholdUSD == nil {
+		return fmt.Errorf("token must config 'BigValueThreshold' or 'BigValueThresholdUSD'")
+	}
+	if c.BigValueThresholdUSD == nil {
+		return nil
+	}
+	if *c.BigValueThresholdUSD < 0 {
+		return fmt.Errorf("wrong token config, BigValueThresholdUSD is negative")
+	}
+	switch c.BigValueOnStalePrice {
+	case StalePriceFailOpen, StalePriceFailClosed:
+	default:
+		return fmt.Errorf("token with 'BigValueThresholdUSD' must config 'BigValueOnStalePrice' (%v or %v)", StalePriceFailOpen, StalePriceFailClosed)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package tokens
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestCheckBigValue(t *testing.T) {
+	oldPairsConfig := tokenPairsConfig
+	defer func() { tokenPairsConfig = oldPairsConfig }()
+
+	decimals := uint8(2)
+	thresholdUSD := 1000.0
+	newToken := func(bigValThreshhold int64, price float64, priceTime int64, onStalePrice string) *TokenConfig {
+		return &TokenConfig{
+			ContractAddress:      "0x1111111111111111111111111111111111111111",
+			Decimals:             &decimals,
+			BigValueThresholdUSD: &thresholdUSD,
+			BigValueOnStalePrice: onStalePrice,
+			TokenPrice:           price,
+			tokenPriceTime:       priceTime,
+			bigValThreshhold:     big.NewInt(bigValThreshhold),
+		}
+	}
+	now := time.Now().Unix()
+	tokenPairsConfig = map[string]*TokenPairConfig{
+		"fresh": {
+			SrcToken:  newToken(50000, 10, now, StalePriceFailOpen),
+			DestToken: newToken(5000, 10, now, StalePriceFailOpen),
+		},
+		"stale": {
+			SrcToken:  newToken(50000, 10, now-2*defaultBigValuePriceMaxAge, StalePriceFailOpen),
+			DestToken: newToken(50000, 10, now-2*defaultBigValuePriceMaxAge, StalePriceFailClosed),
+		},
+	}
+
+	cases := []struct {
+		pairID string
+		value  int64
+		isSrc  bool
+		want   string
+	}{
+		{"fresh", 10000, true, ""},               // 100 tokens, 1000 USD
+		{"fresh", 10001, true, "usd"},            // USD threshold is more restrictive
+		{"fresh", 10001, false, "token,usd"},     // both exceeded
+		{"fresh", 6000, false, "token"},          // token threshold is more restrictive
+		{"stale", 60000, true, "token"},          // fail open ignores USD threshold
+		{"stale", 100, false, "usd-stale-price"}, // fail closed
+	}
+	for _, c := range cases {
+		have := CheckBigValue(c.pairID, big.NewInt(c.value), c.isSrc, "", "")
+		if have.Rule() != c.want || have.IsBigValue != (c.want != "") {
+			t.Errorf("CheckBigValue(%v, %v, %v) want rule %q, have %q", c.pairID, c.value, c.isSrc, c.want, have.Rule())
+		}
+	}
+}
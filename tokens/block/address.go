@@ -1,9 +1,13 @@
 package block
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/bech32"
 )
 
 // DecodeAddress decode address
@@ -30,12 +34,45 @@ func (b *Bridge) NewAddressScriptHash(redeemScript []byte) (*btcutil.AddressScri
 	return btcutil.NewAddressScriptHash(redeemScript, b.GetChainParams())
 }
 
+// PublicKeyToAddress convert public key hex to p2pkh address
+func (b *Bridge) PublicKeyToAddress(pubKeyHex string) (string, error) {
+	cPkData, err := b.ToCompressedPublicKey(common.FromHex(pubKeyHex))
+	if err != nil {
+		return "", err
+	}
+	address, err := b.NewAddressPubKeyHash(cPkData)
+	if err != nil {
+		return "", err
+	}
+	return address.EncodeAddress(), nil
+}
+
 // IsValidAddress check address
 func (b *Bridge) IsValidAddress(addr string) bool {
 	_, err := b.DecodeAddress(addr)
 	return err == nil
 }
 
+// ValidateAddress validate address and report reasons if invalid
+func (b *Bridge) ValidateAddress(addr string) (normalized string, reasons []string) {
+	chainConfig := b.GetChainParams()
+	address, err := btcutil.DecodeAddress(addr, chainConfig)
+	switch {
+	case errors.Is(err, btcutil.ErrChecksumMismatch) || errors.As(err, new(bech32.ErrInvalidChecksum)):
+		return "", []string{tokens.AddressReasonBadChecksum}
+	case errors.Is(err, btcutil.ErrUnknownAddressType): // version of other network
+		return "", []string{tokens.AddressReasonWrongNetwork}
+	case err != nil:
+		return "", []string{tokens.AddressReasonWrongFormat}
+	case !address.IsForNet(chainConfig):
+		return "", []string{tokens.AddressReasonWrongNetwork}
+	}
+	if _, isPubKey := address.(*btcutil.AddressPubKey); isPubKey {
+		return addr, nil
+	}
+	return address.EncodeAddress(), nil
+}
+
 // IsP2pkhAddress check p2pkh addrss
 func (b *Bridge) IsP2pkhAddress(addr string) bool {
 	address, err := b.DecodeAddress(addr)
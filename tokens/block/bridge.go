@@ -16,9 +16,20 @@ type Bridge struct {
 	*tokens.CrossChainBridgeBase
 }
 
+// ensure Bridge impl tokens.CrossChainBridge
+var _ tokens.CrossChainBridge = &Bridge{}
+
+// ensure Bridge impl tokens.AddressValidator
+var _ tokens.AddressValidator = &Bridge{}
+
 // PairID unique btc pair ID
 var PairID = "block"
 
+func init() {
+	newBridge := func(isSrc bool) tokens.CrossChainBridge { return NewCrossChainBridge(isSrc) }
+	tokens.RegisterBridgeConstructor("BLOCK", newBridge)
+}
+
 // NewCrossChainBridge new fsn bridge
 func NewCrossChainBridge(isSrc bool) *Bridge {
 	tokens.IsSwapoutToStringAddress = true
@@ -28,6 +39,7 @@ func NewCrossChainBridge(isSrc bool) *Bridge {
 	btc.PairID = PairID
 	instance := &Bridge{CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(isSrc)}
 	btc.BridgeInstance = instance
+	tokens.SetP2shAddressProvider(instance)
 	return instance
 }
 
@@ -79,7 +91,7 @@ func (b *Bridge) InitLatestBlockNumber() {
 			break
 		}
 		log.Error("get latst block number failed.", "BlockChain", chainCfg.BlockChain, "NetID", chainCfg.NetID, "err", err)
-		log.Println("retry query gateway", gatewayCfg.APIAddress)
+		log.Println("retry query gateway", gatewayCfg.GetAPIAddress())
 		time.Sleep(3 * time.Second)
 	}
 }
@@ -3,6 +3,7 @@ package block
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/big"
@@ -10,6 +11,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
 	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -164,10 +166,24 @@ func (b *Bridge) GetElectTransactionStatus(txHash string) (txstatus *electrs.Ele
 		}
 		errs = append(errs, err0)
 	}
+	if isTxNotFoundErrors(errs) {
+		return nil, fmt.Errorf("%w: %v", tokens.ErrTxNotFound, txHash)
+	}
 	err = fmt.Errorf("%+v", errs)
 	return
 }
 
+// isTxNotFoundErrors is all errors are 'No information available about transaction'
+func isTxNotFoundErrors(errs []error) bool {
+	for _, err := range errs {
+		var rpcErr *btcjson.RPCError
+		if !errors.As(err, &rpcErr) || rpcErr.Code != btcjson.ErrRPCNoTxInfo {
+			return false
+		}
+	}
+	return len(errs) > 0
+}
+
 // FindUtxos impl
 func (b *Bridge) FindUtxos(addr string) (utxos []*electrs.ElectUtxo, err error) {
 	// cloudchainsinc
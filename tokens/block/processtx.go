@@ -44,7 +44,7 @@ func (b *Bridge) processSwapin(txid string) {
 	if tools.IsSwapExist(txid, PairID, "", true) {
 		return
 	}
-	swapInfo, err := b.verifySwapinTx(PairID, txid, true)
+	swapInfo, err := b.verifySwapinTx(PairID, txid, true, nil)
 	tools.RegisterSwapin(txid, []*tokens.TxSwapInfo{swapInfo}, []error{err})
 }
 
@@ -56,6 +56,18 @@ func (b *Bridge) processP2shSwapin(txid, bindAddress string) {
 	tools.RegisterP2shSwapin(txid, swapInfo, err)
 }
 
+// BackfillP2shSwapins register swapins in the recent tx history of p2sh address
+func (b *Bridge) BackfillP2shSwapins(p2shAddress, bindAddress string) (int, error) {
+	txs, err := b.GetTransactionHistory(p2shAddress, "")
+	if err != nil {
+		return 0, err
+	}
+	for _, tx := range txs {
+		b.processP2shSwapin(*tx.Txid, bindAddress)
+	}
+	return len(txs), nil
+}
+
 func isP2pkhSwapinPrior(tx *electrs.ElectTx, depositAddress string) bool {
 	txFrom := getTxFrom(tx.Vin, depositAddress)
 	if txFrom == depositAddress {
@@ -12,44 +12,27 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/tokens/block"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
 	"github.com/anyswap/CrossChain-Bridge/tokens/colx"
-	"github.com/anyswap/CrossChain-Bridge/tokens/etc"
-	"github.com/anyswap/CrossChain-Bridge/tokens/eth"
-	"github.com/anyswap/CrossChain-Bridge/tokens/fsn"
-	"github.com/anyswap/CrossChain-Bridge/tokens/kusama"
 	"github.com/anyswap/CrossChain-Bridge/tokens/ltc"
-	"github.com/anyswap/CrossChain-Bridge/tokens/okex"
-	"github.com/anyswap/CrossChain-Bridge/tokens/ripple"
 	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+
+	// built-in bridges register their constructors in package init
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/etc"
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/eth"
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/fsn"
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/kusama"
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/okex"
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/ripple"
 )
 
-// NewCrossChainBridge new bridge according to chain name
+// NewCrossChainBridge new bridge according to chain name,
+// bridges are constructed by constructors registered in package tokens.
 func NewCrossChainBridge(id string, isSrc bool) tokens.CrossChainBridge {
-	blockChainIden := strings.ToUpper(id)
-	switch {
-	case strings.HasPrefix(blockChainIden, "BITCOIN"):
-		return btc.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "LITECOIN"):
-		return ltc.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "BLOCK"):
-		return block.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "ETHCLASSIC"):
-		return etc.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "ETHEREUM"):
-		return eth.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "OKEX"):
-		return okex.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "FUSION"):
-		return fsn.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "XRP"):
-		return ripple.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "COLOSSUS") || strings.HasPrefix(blockChainIden, "COLX"):
-		return colx.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "KUSAMA"):
-		return kusama.NewCrossChainBridge(isSrc)
-	default:
-		log.Fatalf("Unsupported block chain %v", id)
+	constructor := tokens.GetBridgeConstructor(id)
+	if constructor == nil {
+		log.Fatalf("Unsupported block chain %v, registered chains are %v", id, tokens.GetRegisteredChainNames())
 		return nil
 	}
+	return constructor(isSrc)
 }
 
 // InitCrossChainBridge init bridge
@@ -71,6 +54,13 @@ func InitCrossChainBridge(isServer bool) {
 	tokens.DstBridge = NewCrossChainBridge(dstID, false)
 	log.Info("New bridge finished", "source", srcID, "sourceNet", srcNet, "dest", dstID, "destNet", dstNet)
 
+	if err := tools.SetGatewayProxy(srcGateway); err != nil {
+		log.Fatal("set source gateway proxy failed", "err", err)
+	}
+	if err := tools.SetGatewayProxy(dstGateway); err != nil {
+		log.Fatal("set dest gateway proxy failed", "err", err)
+	}
+
 	tokens.SrcBridge.SetChainAndGateway(srcChain, srcGateway)
 	log.Info("Init bridge source", "source", srcID, "gateway", srcGateway)
 
@@ -90,7 +80,9 @@ func InitCrossChainBridge(isServer bool) {
 	tools.AdjustGatewayOrder(false)
 
 	tokens.IsDcrmDisabled = cfg.Dcrm.Disable
+	tokens.DcrmGroupPubkeys = cfg.Dcrm.GetDcrmGroupPubkeys()
 	tokens.LoadTokenPairsConfig(true)
+	tokens.InitNativePrices()
 
 	BlockChain := strings.ToUpper(srcChain.BlockChain)
 	switch BlockChain {
@@ -109,6 +101,8 @@ func InitCrossChainBridge(isServer bool) {
 	tokens.SrcBridge.InitAfterConfig()
 	tokens.DstBridge.InitAfterConfig()
 
+	tokens.InitMintCaps()
+
 	dcrm.Init(cfg.Dcrm, isServer)
 
 	log.Info("Init bridge success", "isServer", isServer, "dcrmEnabled", !cfg.Dcrm.Disable)
@@ -16,6 +16,12 @@ const (
 	netCustom   = "custom"
 )
 
+// ensure Bridge impl tokens.CrossChainBridge
+var _ tokens.CrossChainBridge = &Bridge{}
+
+// ensure Bridge impl tokens.AddressValidator
+var _ tokens.AddressValidator = &Bridge{}
+
 // PairID unique btc pair ID
 var PairID = "btc"
 
@@ -25,6 +31,11 @@ type Bridge struct {
 	Inherit Inheritable
 }
 
+func init() {
+	newBridge := func(isSrc bool) tokens.CrossChainBridge { return NewCrossChainBridge(isSrc) }
+	tokens.RegisterBridgeConstructor("BITCOIN", newBridge)
+}
+
 // NewCrossChainBridge new btc bridge
 func NewCrossChainBridge(isSrc bool) *Bridge {
 	tokens.IsSwapoutToStringAddress = true
@@ -33,6 +44,7 @@ func NewCrossChainBridge(isSrc bool) *Bridge {
 	}
 	instance := &Bridge{CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(isSrc)}
 	BridgeInstance = instance
+	tokens.SetP2shAddressProvider(instance)
 	instance.SetInherit(instance)
 	return instance
 }
@@ -90,7 +102,7 @@ func (b *Bridge) InitLatestBlockNumber() {
 			break
 		}
 		log.Error("get latst block number failed.", "BlockChain", chainCfg.BlockChain, "NetID", chainCfg.NetID, "err", err)
-		log.Println("retry query gateway", gatewayCfg.APIAddress)
+		log.Println("retry query gateway", gatewayCfg.GetAPIAddress())
 		time.Sleep(3 * time.Second)
 	}
 }
@@ -0,0 +1,26 @@
+package btc
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func TestGetDustThreshold(t *testing.T) {
+	b := NewCrossChainBridge(true)
+	b.ChainConfig = &tokens.ChainConfig{NetID: "mainnet"}
+
+	cases := map[string]uint64{
+		"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH": 546, // p2pkh
+		"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy": 540, // p2sh
+	}
+	for address, want := range cases {
+		threshold, err := b.GetDustThreshold(address)
+		if err != nil || threshold != want {
+			t.Errorf("%v: want dust threshold %v, have %v, %v", address, want, threshold, err)
+		}
+	}
+	if _, err := b.GetDustThreshold("wrong address"); err == nil {
+		t.Error("wrong address should fail")
+	}
+}
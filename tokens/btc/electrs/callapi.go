@@ -2,6 +2,7 @@
 package electrs
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 
@@ -23,7 +24,7 @@ func GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
 // GetLatestBlockNumber call /blocks/tip/height
 func GetLatestBlockNumber(b tokens.CrossChainBridge) (result uint64, err error) {
 	gateway := b.GetGatewayConfig()
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress + "/blocks/tip/height"
 		err = client.RPCGet(&result, url)
 		if err == nil {
@@ -38,7 +39,7 @@ func GetTransactionByHash(b tokens.CrossChainBridge, txHash string) (*ElectTx, e
 	gateway := b.GetGatewayConfig()
 	var result ElectTx
 	var err error
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress + "/tx/" + txHash
 		err = client.RPCGet(&result, url)
 		if err == nil {
@@ -53,20 +54,23 @@ func GetElectTransactionStatus(b tokens.CrossChainBridge, txHash string) (*Elect
 	gateway := b.GetGatewayConfig()
 	var result ElectTxStatus
 	var err error
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress + "/tx/" + txHash + "/status"
 		err = client.RPCGet(&result, url)
 		if err == nil {
 			return &result, nil
 		}
 	}
+	if errors.Is(err, client.ErrNotFoundStatus) {
+		return nil, fmt.Errorf("%w: %v", tokens.ErrTxNotFound, err)
+	}
 	return nil, err
 }
 
 // FindUtxos call /address/{add}/utxo (confirmed first, then big value first)
 func FindUtxos(b tokens.CrossChainBridge, addr string) (result []*ElectUtxo, err error) {
 	gateway := b.GetGatewayConfig()
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress + "/address/" + addr + "/utxo"
 		err = client.RPCGet(&result, url)
 		if err == nil {
@@ -80,7 +84,7 @@ func FindUtxos(b tokens.CrossChainBridge, addr string) (result []*ElectUtxo, err
 // GetPoolTxidList call /mempool/txids
 func GetPoolTxidList(b tokens.CrossChainBridge) (result []string, err error) {
 	gateway := b.GetGatewayConfig()
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress + "/mempool/txids"
 		err = client.RPCGet(&result, url)
 		if err == nil {
@@ -93,7 +97,7 @@ func GetPoolTxidList(b tokens.CrossChainBridge) (result []string, err error) {
 // GetPoolTransactions call /address/{addr}/txs/mempool
 func GetPoolTransactions(b tokens.CrossChainBridge, addr string) (result []*ElectTx, err error) {
 	gateway := b.GetGatewayConfig()
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress + "/address/" + addr + "/txs/mempool"
 		err = client.RPCGet(&result, url)
 		if err == nil {
@@ -106,7 +110,7 @@ func GetPoolTransactions(b tokens.CrossChainBridge, addr string) (result []*Elec
 // GetTransactionHistory call /address/{addr}/txs/chain
 func GetTransactionHistory(b tokens.CrossChainBridge, addr, lastSeenTxid string) (result []*ElectTx, err error) {
 	gateway := b.GetGatewayConfig()
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress + "/address/" + addr + "/txs/chain"
 		if lastSeenTxid != "" {
 			url += "/" + lastSeenTxid
@@ -124,7 +128,7 @@ func GetOutspend(b tokens.CrossChainBridge, txHash string, vout uint32) (*ElectO
 	gateway := b.GetGatewayConfig()
 	var result ElectOutspend
 	var err error
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress + "/tx/" + txHash + "/outspend/" + fmt.Sprintf("%d", vout)
 		err = client.RPCGet(&result, url)
 		if err == nil {
@@ -138,7 +142,7 @@ func GetOutspend(b tokens.CrossChainBridge, txHash string, vout uint32) (*ElectO
 func PostTransaction(b tokens.CrossChainBridge, txHex string) (txHash string, err error) {
 	gateway := b.GetGatewayConfig()
 	var success bool
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress + "/tx"
 		hash0, err0 := client.RPCRawPost(url, txHex)
 		if err0 == nil && !success {
@@ -154,7 +158,7 @@ func PostTransaction(b tokens.CrossChainBridge, txHex string) (txHash string, er
 // GetBlockHash call /block-height/{height}
 func GetBlockHash(b tokens.CrossChainBridge, height uint64) (blockHash string, err error) {
 	gateway := b.GetGatewayConfig()
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress + "/block-height/" + fmt.Sprintf("%d", height)
 		blockHash, err = client.RPCRawGet(url)
 		if err == nil {
@@ -167,7 +171,7 @@ func GetBlockHash(b tokens.CrossChainBridge, height uint64) (blockHash string, e
 // GetBlockTxids call /block/{blockHash}/txids
 func GetBlockTxids(b tokens.CrossChainBridge, blockHash string) (result []string, err error) {
 	gateway := b.GetGatewayConfig()
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress + "/block/" + blockHash + "/txids"
 		err = client.RPCGet(&result, url)
 		if err == nil {
@@ -182,7 +186,7 @@ func GetBlock(b tokens.CrossChainBridge, blockHash string) (*ElectBlock, error)
 	gateway := b.GetGatewayConfig()
 	var result ElectBlock
 	var err error
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress + "/block/" + blockHash
 		err = client.RPCGet(&result, url)
 		if err == nil {
@@ -195,7 +199,7 @@ func GetBlock(b tokens.CrossChainBridge, blockHash string) (*ElectBlock, error)
 // GetBlockTransactions call /block/{blockHash}/txs[/:start_index] (should start_index%25 == 0)
 func GetBlockTransactions(b tokens.CrossChainBridge, blockHash string, startIndex uint32) (result []*ElectTx, err error) {
 	gateway := b.GetGatewayConfig()
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress + "/block/" + blockHash + "/txs/" + fmt.Sprintf("%d", startIndex)
 		err = client.RPCGet(&result, url)
 		if err == nil {
@@ -209,7 +213,7 @@ func GetBlockTransactions(b tokens.CrossChainBridge, blockHash string, startInde
 func EstimateFeePerKb(b tokens.CrossChainBridge, blocks int) (fee int64, err error) {
 	var result map[int]float64
 	gateway := b.GetGatewayConfig()
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress + "/fee-estimates"
 		err = client.RPCGet(&result, url)
 		if err == nil {
@@ -32,6 +32,7 @@ func Init(btcExtra *tokens.BtcExtraConfig) {
 	initFromPublicKey()
 	initRelayFee(btcExtra)
 	initAggregate(btcExtra)
+	initMempoolRegister(btcExtra)
 }
 
 func initFromPublicKey() {
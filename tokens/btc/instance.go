@@ -10,15 +10,14 @@ var BridgeInstance BridgeInterface
 
 // BridgeInterface btc bridge interface
 type BridgeInterface interface {
-	tokens.CrossChainBridge
+	tokens.P2shAddressProvider
 
 	GetCompressedPublicKey(fromPublicKey string, needVerify bool) (cPkData []byte, err error)
-	GetP2shAddress(bindAddr string) (p2shAddress string, redeemScript []byte, err error)
-	VerifyP2shTransaction(pairID, txHash, bindAddress string, allowUnstable bool) (*tokens.TxSwapInfo, error)
 	VerifyAggregateMsgHash(msgHash []string, args *tokens.BuildTxArgs) error
 	AggregateUtxos(addrs []string, utxos []*electrs.ElectUtxo) (string, error)
 	FindUtxos(addr string) ([]*electrs.ElectUtxo, error)
 	GetOutspend(txHash string, vout uint32) (*electrs.ElectOutspend, error)
+	BackfillP2shSwapins(p2shAddress, bindAddress string) (int, error)
 
 	StartSwapHistoryScanJob()
 	StartChainTransactionScanJob()
@@ -0,0 +1,273 @@
+package btc
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+)
+
+const (
+	mempoolSwapLifetime      = 14 * 24 * 3600 // seconds
+	mempoolWatchInterval     = 60 * time.Second
+	defaultMaxMempoolSwaps   = 5000
+	mempoolDropReasonEvicted = "evicted"
+	mempoolDropReasonSpent   = "double-spent"
+	mempoolDropReasonExpired = "expired"
+)
+
+var (
+	cfgEnableMempoolRegister bool
+	cfgMaxMempoolSwaps       = defaultMaxMempoolSwaps
+	cfgMempoolDropPushURL    string
+
+	mempoolSwaps        = newMempoolSwapWatcher()
+	mempoolWatchStarter sync.Once
+
+	upgradeMempoolSwapin = tools.UpgradeMempoolSwapin // replaced in tests
+)
+
+// mempoolSwap registered swapins of an unconfirmed tx
+type mempoolSwap struct {
+	binds     []string
+	spendTxid string // first input of the tx, used to detect double spending
+	spendVout uint32
+	firstSeen int64
+}
+
+// mempoolSwapWatcher watches at most 'cfgMaxMempoolSwaps' unconfirmed txs
+type mempoolSwapWatcher struct {
+	lock  sync.Mutex
+	swaps map[string]*mempoolSwap // key is txid
+}
+
+func newMempoolSwapWatcher() *mempoolSwapWatcher {
+	return &mempoolSwapWatcher{swaps: make(map[string]*mempoolSwap)}
+}
+
+func (w *mempoolSwapWatcher) isFull() bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return len(w.swaps) >= cfgMaxMempoolSwaps
+}
+
+// watch add bind of txid, returns false if watcher is full
+func (w *mempoolSwapWatcher) watch(txid, bind string, firstInput *electrs.ElectTxin, firstSeen int64) bool {
+	return w.add(txid, bind, firstInput, firstSeen, true)
+}
+
+// restore add registered bind of txid even if watcher is full,
+// otherwise the registered swap is never upgraded or dropped.
+func (w *mempoolSwapWatcher) restore(txid, bind string, firstInput *electrs.ElectTxin, firstSeen int64) {
+	w.add(txid, bind, firstInput, firstSeen, false)
+}
+
+func (w *mempoolSwapWatcher) add(txid, bind string, firstInput *electrs.ElectTxin, firstSeen int64, bounded bool) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	swap, exist := w.swaps[txid]
+	if !exist {
+		if bounded && len(w.swaps) >= cfgMaxMempoolSwaps {
+			return false
+		}
+		swap = &mempoolSwap{firstSeen: firstSeen}
+		if firstInput != nil && firstInput.Txid != nil && firstInput.Vout != nil {
+			swap.spendTxid = *firstInput.Txid
+			swap.spendVout = *firstInput.Vout
+		}
+		w.swaps[txid] = swap
+	}
+	for _, item := range swap.binds {
+		if item == bind {
+			return true
+		}
+	}
+	swap.binds = append(swap.binds, bind)
+	return true
+}
+
+func (w *mempoolSwapWatcher) remove(txid string) *mempoolSwap {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	swap := w.swaps[txid]
+	delete(w.swaps, txid)
+	return swap
+}
+
+func (w *mempoolSwapWatcher) txids() []string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	txids := make([]string, 0, len(w.swaps))
+	for txid := range w.swaps {
+		txids = append(txids, txid)
+	}
+	return txids
+}
+
+func (w *mempoolSwapWatcher) get(txid string) *mempoolSwap {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.swaps[txid]
+}
+
+func initMempoolRegister(btcExtra *tokens.BtcExtraConfig) {
+	cfgEnableMempoolRegister = btcExtra.EnableMempoolRegister
+	if !cfgEnableMempoolRegister {
+		return
+	}
+	if btcExtra.MaxMempoolSwaps > 0 {
+		cfgMaxMempoolSwaps = btcExtra.MaxMempoolSwaps
+	}
+	cfgMempoolDropPushURL = btcExtra.MempoolDropPushURL
+	log.Info("Init Btc extra", "EnableMempoolRegister", cfgEnableMempoolRegister, "MaxMempoolSwaps", cfgMaxMempoolSwaps, "MempoolDropPushURL", cfgMempoolDropPushURL)
+	// watch swapins registered before restarting without waiting for new ones
+	if b, ok := BridgeInstance.(*Bridge); ok && isMempoolRegisterEnabled() {
+		mempoolWatchStarter.Do(func() {
+			go b.startMempoolSwapWatcher()
+		})
+	}
+}
+
+// mempool swapins are not registered if pair requires min register confirmations
+func isMempoolRegisterEnabled() bool {
+	return cfgEnableMempoolRegister && tools.CanRegisterMempoolSwapin() &&
+		tokens.GetPairMinRegisterConfirmations(PairID) == 0
+}
+
+func isTxConfirmed(tx *electrs.ElectTx) bool {
+	return tx.Status != nil && tx.Status.Confirmed != nil && *tx.Status.Confirmed
+}
+
+// processMempoolSwapin register valid swapins of unconfirmed tx,
+// returns false if it should be processed as usual.
+func (b *Bridge) processMempoolSwapin(tx *electrs.ElectTx, p2shBindAddrs []string) bool {
+	if mempoolSwaps.isFull() {
+		return false
+	}
+	txid := *tx.Txid
+	var firstInput *electrs.ElectTxin
+	if len(tx.Vin) > 0 {
+		firstInput = tx.Vin[0]
+	}
+	now := time.Now().Unix()
+	register := func(txType tokens.SwapTxType, swapInfo *tokens.TxSwapInfo, err error) {
+		if err != nil {
+			log.Debug("[mempool] ignore invalid mempool swapin", "tx", txid, "err", err)
+			return
+		}
+		if tools.RegisterMempoolSwapin(txid, txType, swapInfo) {
+			mempoolSwaps.watch(txid, swapInfo.Bind, firstInput, now)
+		}
+	}
+	if len(p2shBindAddrs) > 0 {
+		for _, p2shBindAddr := range p2shBindAddrs {
+			if tools.IsSwapExist(txid, PairID, p2shBindAddr, true) {
+				continue
+			}
+			swapInfo, err := b.verifyP2shSwapinTx(PairID, txid, p2shBindAddr, true)
+			register(tokens.P2shSwapinTx, swapInfo, err)
+		}
+	} else if !tools.IsSwapExist(txid, PairID, "", true) {
+		swapInfo, err := b.verifySwapinTx(PairID, txid, true, nil)
+		register(tokens.SwapinTx, swapInfo, err)
+	}
+	return true
+}
+
+func (b *Bridge) startMempoolSwapWatcher() {
+	log.Info("[mempool] start watch mempool swapins")
+	b.loadMempoolSwaps()
+	for {
+		for _, txid := range mempoolSwaps.txids() {
+			b.checkMempoolSwap(txid)
+		}
+		time.Sleep(mempoolWatchInterval)
+	}
+}
+
+// loadMempoolSwaps watch all mempool swapins registered before restarting
+func (b *Bridge) loadMempoolSwaps() {
+	swaps, err := tools.GetMempoolSwapins(mempoolSwapLifetime)
+	if err != nil {
+		log.Warn("[mempool] load mempool swapins failed", "err", err)
+		return
+	}
+	for _, swap := range swaps {
+		var firstInput *electrs.ElectTxin
+		if tx, errt := b.GetTransactionByHash(swap.TxID); errt == nil && len(tx.Vin) > 0 {
+			firstInput = tx.Vin[0]
+		}
+		mempoolSwaps.restore(swap.TxID, swap.Bind, firstInput, swap.Timestamp)
+	}
+	log.Info("[mempool] load mempool swapins success", "count", len(swaps))
+}
+
+func (b *Bridge) checkMempoolSwap(txid string) {
+	swap := mempoolSwaps.get(txid)
+	if swap == nil {
+		return
+	}
+	txStatus, err := b.GetElectTransactionStatus(txid)
+	switch {
+	case err == nil && txStatus.Confirmed != nil && *txStatus.Confirmed:
+		upgradeMempoolSwap(txid)
+	case err == nil:
+		if time.Now().Unix()-swap.firstSeen > mempoolSwapLifetime {
+			b.dropMempoolSwap(txid, mempoolDropReasonExpired, "")
+		}
+	case errors.Is(err, tokens.ErrTxNotFound):
+		reason, spentBy := mempoolDropReasonEvicted, ""
+		if swap.spendTxid != "" {
+			outspend, errs := b.GetOutspend(swap.spendTxid, swap.spendVout)
+			if errs == nil && outspend.Spent != nil && *outspend.Spent &&
+				outspend.Txid != nil && *outspend.Txid != txid {
+				reason, spentBy = mempoolDropReasonSpent, *outspend.Txid
+			}
+		}
+		b.dropMempoolSwap(txid, reason, spentBy)
+	default:
+		log.Debug("[mempool] get mempool swapin status failed", "tx", txid, "err", err)
+	}
+}
+
+// upgradeMempoolSwap upgrade watched swapins of mined tx to 'TxNotStable'
+func upgradeMempoolSwap(txid string) {
+	swap := mempoolSwaps.get(txid)
+	if swap == nil {
+		return
+	}
+	for _, bind := range swap.binds {
+		if err := upgradeMempoolSwapin(txid, PairID, bind); err != nil {
+			log.Warn("[mempool] upgrade mined mempool swapin failed", "tx", txid, "bind", bind, "err", err)
+			return
+		}
+		log.Info("[mempool] upgrade mined mempool swapin", "tx", txid, "bind", bind)
+	}
+	mempoolSwaps.remove(txid)
+}
+
+func (b *Bridge) dropMempoolSwap(txid, reason, spentBy string) {
+	swap := mempoolSwaps.get(txid)
+	if swap == nil {
+		return
+	}
+	for _, bind := range swap.binds {
+		notice := &tools.MempoolSwapDropNotice{
+			TxID:      txid,
+			PairID:    PairID,
+			Bind:      bind,
+			Reason:    reason,
+			SpentBy:   spentBy,
+			Timestamp: time.Now().Unix(),
+		}
+		if err := tools.DropMempoolSwapin(notice, cfgMempoolDropPushURL); err != nil {
+			log.Warn("[mempool] drop mempool swapin failed", "tx", txid, "bind", bind, "err", err)
+			return
+		}
+	}
+	mempoolSwaps.remove(txid)
+}
@@ -0,0 +1,65 @@
+package btc
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+)
+
+func TestMempoolSwapWatcherBounded(t *testing.T) {
+	defer func(max int) { cfgMaxMempoolSwaps = max }(cfgMaxMempoolSwaps)
+	cfgMaxMempoolSwaps = 2
+
+	spendTxid, spendVout := "0xprev", uint32(1)
+	input := &electrs.ElectTxin{Txid: &spendTxid, Vout: &spendVout}
+
+	w := newMempoolSwapWatcher()
+	if !w.watch("tx1", "bind1", input, 1) || !w.watch("tx1", "bind2", nil, 2) || !w.watch("tx1", "bind1", nil, 3) {
+		t.Fatal("watch binds of the same tx failed")
+	}
+	swap := w.get("tx1")
+	if len(swap.binds) != 2 || swap.spendTxid != spendTxid || swap.spendVout != spendVout || swap.firstSeen != 1 {
+		t.Fatalf("unexpected watched swap %+v", swap)
+	}
+	if !w.watch("tx2", "bind", nil, 1) {
+		t.Fatal("watch tx2 failed")
+	}
+	if !w.isFull() || w.watch("tx3", "bind", nil, 1) {
+		t.Fatal("watcher is not bounded")
+	}
+	w.remove("tx1")
+	if w.isFull() || !w.watch("tx3", "bind", nil, 1) {
+		t.Fatal("watch after remove failed")
+	}
+}
+
+func TestMempoolSwapRestoreAndUpgrade(t *testing.T) {
+	defer func(max int, swaps *mempoolSwapWatcher) {
+		cfgMaxMempoolSwaps, mempoolSwaps = max, swaps
+	}(cfgMaxMempoolSwaps, mempoolSwaps)
+	cfgMaxMempoolSwaps = 1
+	mempoolSwaps = newMempoolSwapWatcher()
+
+	// registered swapins are all restored even if watcher is full
+	mempoolSwaps.restore("tx1", "bind1", nil, 1)
+	mempoolSwaps.restore("tx2", "bind1", nil, 1)
+	mempoolSwaps.restore("tx2", "bind2", nil, 1)
+	if len(mempoolSwaps.txids()) != 2 || len(mempoolSwaps.get("tx2").binds) != 2 {
+		t.Fatalf("restore mempool swaps failed, have %v", mempoolSwaps.txids())
+	}
+
+	var upgraded []string
+	defer func(upgrade func(txid, pairID, bind string) error) { upgradeMempoolSwapin = upgrade }(upgradeMempoolSwapin)
+	upgradeMempoolSwapin = func(txid, pairID, bind string) error {
+		upgraded = append(upgraded, txid+":"+bind)
+		return nil
+	}
+	upgradeMempoolSwap("tx2")
+	upgradeMempoolSwap("tx3") // not watched
+	if len(upgraded) != 2 || upgraded[0] != "tx2:bind1" || upgraded[1] != "tx2:bind2" {
+		t.Errorf("want all binds of tx2 upgraded, have %v", upgraded)
+	}
+	if mempoolSwaps.get("tx2") != nil || mempoolSwaps.get("tx1") == nil {
+		t.Errorf("only upgraded tx should be removed, have %v", mempoolSwaps.txids())
+	}
+}
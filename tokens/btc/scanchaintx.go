@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
 )
 
@@ -57,13 +58,22 @@ func (b *Bridge) StartChainTransactionScanJob() {
 
 	chainCfg := b.GetChainConfig()
 	confirmations := *chainCfg.Confirmations
+	// delay scanning blocks until swapins in them can be registered
+	minRegisterConfirmations := tokens.GetPairMinRegisterConfirmations(PairID)
 
 	stable := start
 	errorSubject := fmt.Sprintf("[scanchain] get %v block failed", chainName)
 	scanSubject := fmt.Sprintf("[scanchain] scanned %v block", chainName)
 	for {
 		latest := tools.LoopGetLatestBlockNumber(b)
-		for h := stable + 1; h <= latest; {
+		scanLatest := latest
+		if minRegisterConfirmations > 0 {
+			scanLatest = 0
+			if latest > minRegisterConfirmations {
+				scanLatest = latest - minRegisterConfirmations
+			}
+		}
+		for h := stable + 1; h <= scanLatest; {
 			blockHash, err := b.GetBlockHash(h)
 			if err != nil {
 				log.Error(errorSubject, "height", h, "err", err)
@@ -89,6 +99,9 @@ func (b *Bridge) StartChainTransactionScanJob() {
 		}
 		if stable+confirmations < latest {
 			stable = latest - confirmations
+			if stable > scanLatest {
+				stable = scanLatest
+			}
 			_ = tools.UpdateLatestScanInfo(b.IsSrc, stable)
 		}
 		time.Sleep(restIntervalInScanJob)
@@ -0,0 +1,48 @@
+package btc
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func TestGetTransactionStatus(t *testing.T) {
+	statuses := map[string]string{
+		"pending":   `{"confirmed":false}`,
+		"confirmed": `{"confirmed":true,"block_height":700000,"block_hash":"0000000000000000000a","block_time":1630000000}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/blocks/tip/height" {
+			_, _ = w.Write([]byte("700005"))
+			return
+		}
+		for txHash, status := range statuses {
+			if r.URL.Path == "/tx/"+txHash+"/status" {
+				_, _ = w.Write([]byte(status))
+				return
+			}
+		}
+		http.Error(w, "Transaction not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	b := NewCrossChainBridge(true)
+	b.ChainConfig = &tokens.ChainConfig{BlockChain: "Bitcoin"}
+	b.GatewayConfig = &tokens.GatewayConfig{APIAddress: []string{server.URL}}
+
+	status, err := b.GetTransactionStatus("notfound")
+	if !errors.Is(err, tokens.ErrTxNotFound) || status != nil {
+		t.Errorf("not found: want ErrTxNotFound and nil status, have %v, %v", err, status)
+	}
+	status, err = b.GetTransactionStatus("pending")
+	if err != nil || status == nil || status.BlockHeight != 0 {
+		t.Errorf("pending: want status with height 0, have %v, %v", err, status)
+	}
+	status, err = b.GetTransactionStatus("confirmed")
+	if err != nil || status == nil || status.BlockHeight != 700000 || status.Confirmations != 5 {
+		t.Errorf("confirmed: want height 700000 confirmations 5, have %v, %+v", err, status)
+	}
+}
@@ -42,6 +42,14 @@ func (b *Bridge) verifyP2shSwapinTx(pairID, txHash, bindAddress string, allowUns
 		// tx with locktime should be on chain, prvent DDOS attack
 		return swapInfo, tokens.ErrTxNotStable
 	}
+	if allowUnstable {
+		if err = b.checkRegisterDepth(pairID, swapInfo.Height); err != nil {
+			return swapInfo, err
+		}
+	}
+	if txStatus.BlockHash != nil {
+		swapInfo.BlockHash = *txStatus.BlockHash // BlockHash
+	}
 	if txStatus.BlockTime != nil {
 		swapInfo.Timestamp = *txStatus.BlockTime // Timestamp
 	}
@@ -3,6 +3,7 @@ package colx
 import (
 	"fmt"
 
+	"github.com/anyswap/CrossChain-Bridge/common"
 	colxutil "github.com/giangnamnabka/btcutil"
 )
 
@@ -30,6 +31,19 @@ func (b *Bridge) NewAddressScriptHash(redeemScript []byte) (*colxutil.AddressScr
 	return colxutil.NewAddressScriptHash(redeemScript, b.GetChainParams())
 }
 
+// PublicKeyToAddress convert public key hex to p2pkh address
+func (b *Bridge) PublicKeyToAddress(pubKeyHex string) (string, error) {
+	cPkData, err := b.ToCompressedPublicKey(common.FromHex(pubKeyHex))
+	if err != nil {
+		return "", err
+	}
+	address, err := b.NewAddressPubKeyHash(cPkData)
+	if err != nil {
+		return "", err
+	}
+	return address.EncodeAddress(), nil
+}
+
 // IsValidAddress check address
 func (b *Bridge) IsValidAddress(addr string) bool {
 	_, err := b.DecodeAddress(addr)
@@ -17,6 +17,9 @@ const (
 	netCustom   = "custom"
 )
 
+// ensure Bridge impl tokens.CrossChainBridge
+var _ tokens.CrossChainBridge = &Bridge{}
+
 // PairID unique colx pair ID
 var PairID = "colx"
 
@@ -27,6 +30,12 @@ type Bridge struct {
 
 var instance *Bridge
 
+func init() {
+	newBridge := func(isSrc bool) tokens.CrossChainBridge { return NewCrossChainBridge(isSrc) }
+	tokens.RegisterBridgeConstructor("COLOSSUS", newBridge)
+	tokens.RegisterBridgeConstructor("COLX", newBridge)
+}
+
 // NewCrossChainBridge new colx bridge
 func NewCrossChainBridge(isSrc bool) *Bridge {
 	tokens.IsSwapoutToStringAddress = true
@@ -36,6 +45,7 @@ func NewCrossChainBridge(isSrc bool) *Bridge {
 	btc.PairID = PairID
 	instance = &Bridge{tokens.NewCrossChainBridgeBase(isSrc)}
 	btc.BridgeInstance = instance
+	tokens.SetP2shAddressProvider(instance)
 	return instance
 }
 
@@ -88,7 +98,7 @@ func (b *Bridge) InitLatestBlockNumber() {
 			break
 		}
 		log.Error("get latst block number failed.", "BlockChain", chainCfg.BlockChain, "NetID", chainCfg.NetID, "err", err)
-		log.Println("retry query gateway", gatewayCfg.APIAddress)
+		log.Println("retry query gateway", gatewayCfg.GetAPIAddress())
 		time.Sleep(3 * time.Second)
 	}
 }
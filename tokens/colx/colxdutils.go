@@ -12,6 +12,7 @@ import (
 	"github.com/giangnamnabka/btcd/txscript"
 	"github.com/giangnamnabka/btcd/wire"
 	colxutil "github.com/giangnamnabka/btcutil"
+	"github.com/giangnamnabka/btcwallet/wallet/txrules"
 )
 
 type colxAmountType = colxutil.Amount
@@ -57,6 +58,15 @@ func (b *Bridge) GetPayToAddrScript(address string) ([]byte, error) {
 	return txscript.PayToAddrScript(toAddr)
 }
 
+// GetDustThreshold get dust threshold of output paying to address
+func (b *Bridge) GetDustThreshold(address string) (uint64, error) {
+	pkScript, err := b.GetPayToAddrScript(address)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(txrules.GetDustThreshold(len(pkScript), txrules.DefaultRelayFeePerKb)), nil
+}
+
 // GetP2shRedeemScript get p2sh redeem script
 func (b *Bridge) GetP2shRedeemScript(memo, pubKeyHash []byte) (redeemScript []byte, err error) {
 	return txscript.NewScriptBuilder().
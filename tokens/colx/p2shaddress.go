@@ -21,6 +21,11 @@ func (b *Bridge) getP2shAddressWithMemo(memo, pubKeyHash []byte) (p2shAddress st
 	return
 }
 
+// GetP2shPairID get pair ID of p2sh swapins
+func (b *Bridge) GetP2shPairID() string {
+	return PairID
+}
+
 // GetP2shAddress get p2sh address from bind address
 func (b *Bridge) GetP2shAddress(bindAddr string) (p2shAddress string, redeemScript []byte, err error) {
 	if !tokens.GetCrossChainBridge(!b.IsSrc).IsValidAddress(bindAddr) {
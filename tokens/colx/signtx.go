@@ -205,17 +205,6 @@ func (b *Bridge) GetCompressedPublicKey(fromPublicKey string, needVerify bool) (
 	return cPkData, nil
 }
 
-// the rsv must have correct v (recovery id), otherwise will get wrong public key data.
-func (b *Bridge) getPkDataFromSig(rsv, msgHash string, compressed bool) (pkData []byte, err error) {
-	rsvData := common.FromHex(rsv)
-	hashData := common.FromHex(msgHash)
-	ecPub, err := crypto.SigToPub(hashData, rsvData)
-	if err != nil {
-		return nil, err
-	}
-	return b.SerializePublicKey(ecPub, compressed), nil
-}
-
 // DcrmSignMsgHash dcrm sign msg hash
 func (b *Bridge) DcrmSignMsgHash(msgHash []string, args *tokens.BuildTxArgs) (rsv []string, err error) {
 	extra := args.Extra.BtcExtra
@@ -245,32 +234,16 @@ func (b *Bridge) DcrmSignMsgHash(msgHash []string, args *tokens.BuildTxArgs) (rs
 	return rsv, nil
 }
 
+// adjustRsvOrders order rsvs by msgHashes, and verify each rsv is signed by the public key
 func (b *Bridge) adjustRsvOrders(rsvs, msgHashes []string, fromPublicKey string) (newRsvs []string, err error) {
-	if len(rsvs) <= 1 {
-		return rsvs, nil
+	newRsvs, reordered, err := tokens.MatchSignaturesToMsgHashes(rsvs, msgHashes, common.FromHex(fromPublicKey))
+	if err != nil {
+		return nil, err
 	}
-	fromPubkeyData, err := b.GetCompressedPublicKey(fromPublicKey, false)
-	matchedRsvMap := make(map[string]struct{})
-	var cPkData []byte
-	for _, msgHash := range msgHashes {
-		matched := false
-		for _, rsv := range rsvs {
-			if _, exist := matchedRsvMap[rsv]; exist {
-				continue
-			}
-			cPkData, err = b.getPkDataFromSig(rsv, msgHash, true)
-			if err == nil && bytes.Equal(cPkData, fromPubkeyData) {
-				matchedRsvMap[rsv] = struct{}{}
-				newRsvs = append(newRsvs, rsv)
-				matched = true
-				break
-			}
-		}
-		if !matched {
-			return nil, fmt.Errorf("msgHash %v hash no matched rsv", msgHash)
-		}
+	if reordered {
+		log.Warn(b.ChainConfig.BlockChain+" dcrm returned rsvs not in msg hash order", "msgHashes", msgHashes, "rsvs", rsvs)
 	}
-	return newRsvs, err
+	return newRsvs, nil
 }
 
 // SignTransaction sign tx with pairID
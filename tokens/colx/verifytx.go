@@ -23,14 +23,14 @@ func (b *Bridge) GetTransaction(txHash string) (interface{}, error) {
 
 // GetTransactionStatus impl
 func (b *Bridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
-	txStatus := &tokens.TxStatus{}
 	electStatus, err := b.GetElectTransactionStatus(txHash)
 	if err != nil {
 		log.Trace(b.ChainConfig.BlockChain+" Bridge::GetElectTransactionStatus fail", "tx", txHash, "err", err)
-		return txStatus, err
+		return nil, err
 	}
-	if !*electStatus.Confirmed {
-		return txStatus, tokens.ErrTxNotStable
+	txStatus := &tokens.TxStatus{}
+	if electStatus.Confirmed == nil || !*electStatus.Confirmed {
+		return txStatus, nil // pending
 	}
 	if electStatus.BlockHash != nil {
 		txStatus.BlockHash = *electStatus.BlockHash
@@ -78,34 +78,46 @@ func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHash []string) (err error)
 
 // VerifyTransaction impl
 func (b *Bridge) VerifyTransaction(pairID, txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+	return b.VerifyTransactionWithTrace(pairID, txHash, allowUnstable, nil)
+}
+
+// VerifyTransactionWithTrace impl
+func (b *Bridge) VerifyTransactionWithTrace(pairID, txHash string, allowUnstable bool, trace *tokens.VerifyTrace) (*tokens.TxSwapInfo, error) {
 	if !b.IsSrc {
 		return nil, tokens.ErrBridgeDestinationNotSupported
 	}
-	return b.verifySwapinTx(pairID, txHash, allowUnstable)
+	return b.verifySwapinTx(pairID, txHash, allowUnstable, trace)
 }
 
-func (b *Bridge) verifySwapinTx(pairID, txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+func (b *Bridge) verifySwapinTx(pairID, txHash string, allowUnstable bool, trace *tokens.VerifyTrace) (*tokens.TxSwapInfo, error) {
 	tokenCfg := b.GetTokenConfig(pairID)
 	if tokenCfg == nil {
-		return nil, tokens.ErrUnknownPairID
+		return nil, trace.Check("pairID", tokens.ErrUnknownPairID)
 	}
+	trace.Pass("pairID")
 	swapInfo := &tokens.TxSwapInfo{}
 	swapInfo.PairID = pairID // PairID
 	swapInfo.Hash = txHash   // Hash
 	if !allowUnstable && !b.checkStable(txHash) {
-		return swapInfo, tokens.ErrTxNotStable
+		return swapInfo, trace.Check("stable", tokens.ErrTxNotStable)
+	} else if !allowUnstable {
+		trace.Pass("stable")
 	}
 	tx, err := b.GetTransactionByHash(txHash)
 	if err != nil {
 		log.Debug("[verifySwapin] "+b.ChainConfig.BlockChain+" Bridge::GetTransaction fail", "tx", txHash, "err", err)
-		return swapInfo, tokens.ErrTxNotFound
+		return swapInfo, trace.Check("transaction", tokens.ErrTxNotFound)
 	}
+	trace.Pass("transaction")
 	txStatus := tx.Status
 	if txStatus.BlockHeight != nil {
 		swapInfo.Height = *txStatus.BlockHeight // Height
 	} else if *tx.Locktime != 0 {
 		// tx with locktime should be on chain, prvent DDOS attack
-		return swapInfo, tokens.ErrTxNotStable
+		return swapInfo, trace.Check("locktime", tokens.ErrTxNotStable)
+	}
+	if txStatus.BlockHash != nil {
+		swapInfo.BlockHash = *txStatus.BlockHash // BlockHash
 	}
 	if txStatus.BlockTime != nil {
 		swapInfo.Timestamp = *txStatus.BlockTime // Timestamp
@@ -113,8 +125,9 @@ func (b *Bridge) verifySwapinTx(pairID, txHash string, allowUnstable bool) (*tok
 	depositAddress := tokenCfg.DepositAddress
 	value, memoScript, rightReceiver := b.GetReceivedValue(tx.Vout, depositAddress, p2pkhType)
 	if !rightReceiver {
-		return swapInfo, tokens.ErrTxWithWrongReceiver
+		return swapInfo, trace.Check("receiver", tokens.ErrTxWithWrongReceiver)
 	}
+	trace.Pass("receiver")
 	bindAddress, bindOk := GetBindAddressFromMemoScipt(memoScript)
 
 	swapInfo.To = depositAddress                      // To
@@ -123,13 +136,14 @@ func (b *Bridge) verifySwapinTx(pairID, txHash string, allowUnstable bool) (*tok
 	swapInfo.From = getTxFrom(tx.Vin, depositAddress) // From
 
 	err = b.checkSwapinInfo(swapInfo)
-	if err != nil {
+	if err = trace.Check("swapinInfo", err); err != nil {
 		return swapInfo, err
 	}
 	if !bindOk {
 		log.Debug("wrong memo", "memo", memoScript)
-		return swapInfo, tokens.ErrTxWithWrongMemo
+		return swapInfo, trace.Check("memo", tokens.ErrTxWithWrongMemo)
 	}
+	trace.Pass("memo")
 
 	if !allowUnstable {
 		log.Debug("verify swapin pass", "pairID", swapInfo.PairID, "from", swapInfo.From, "to", swapInfo.To, "bind", swapInfo.Bind, "value", swapInfo.Value, "txid", swapInfo.Hash, "height", swapInfo.Height, "timestamp", swapInfo.Timestamp)
@@ -156,7 +170,7 @@ func (b *Bridge) checkStable(txHash string) bool {
 	if err != nil {
 		return false
 	}
-	confirmations := *b.GetChainConfig().Confirmations
+	confirmations := tokens.GetPairStableConfirmations(PairID, b.IsSrc)
 	return txStatus.BlockHeight > 0 && txStatus.Confirmations >= confirmations
 }
 
@@ -1,11 +1,11 @@
 package tokens
 
 import (
-	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
 
 	"github.com/anyswap/CrossChain-Bridge/common"
 	"github.com/anyswap/CrossChain-Bridge/log"
@@ -23,6 +23,10 @@ type BtcExtraConfig struct {
 	UtxoAggregateMinCount  int
 	UtxoAggregateMinValue  uint64
 	UtxoAggregateToAddress string
+
+	EnableMempoolRegister bool
+	MaxMempoolSwaps       int
+	MempoolDropPushURL    string
 }
 
 // GatewayConfig struct
@@ -30,6 +34,34 @@ type GatewayConfig struct {
 	APIAddress    []string
 	APIAddressExt []string
 	Extras        *GatewayExtras `json:",omitempty"`
+	Proxy         string         `toml:",omitempty" json:"-"` // override default proxy
+
+	// websocket gateway of eth-like chain, used to learn mined swap txs without polling
+	WebsocketAddress string `toml:",omitempty" json:",omitempty"`
+
+	lock sync.RWMutex // protect api addresses replaced at runtime
+}
+
+// GetAPIAddress get api addresses, callers must not modify the returned slice
+func (c *GatewayConfig) GetAPIAddress() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.APIAddress
+}
+
+// GetAPIAddressExt get ext api addresses, callers must not modify the returned slice
+func (c *GatewayConfig) GetAPIAddressExt() []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.APIAddressExt
+}
+
+// SetAPIAddress replace api addresses, in-flight calls keep iterating the old slices
+func (c *GatewayConfig) SetAPIAddress(apiAddress, apiAddressExt []string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.APIAddress = apiAddress
+	c.APIAddressExt = apiAddressExt
 }
 
 // GatewayExtras struct
@@ -58,12 +90,20 @@ type ChainConfig struct {
 	Confirmations *uint64
 	InitialHeight *uint64
 
+	// finality strategy: confirmations (default), safe, finalized, checkpoint
+	// confirmations are required by all strategies
+	Finality             string   `json:",omitempty"`
+	CheckpointContract   string   `json:",omitempty"`
+	CheckpointMethod     string   `json:",omitempty"`
+	CheckpointAPIAddress []string `json:"-"`
+
 	// judge by the 'from' chain (eg. src for swapin)
 	EnableScan              bool
 	EnableScanPool          bool
 	EnablePassBigValue      bool
 	EnableCheckTxBlockHash  bool
 	EnableCheckTxBlockIndex bool
+	ScanStaleTime           int64 `json:",omitempty"` // seconds, scan is inactive if scanned height is not updated within it (default 600)
 
 	// judge by the 'to' chain (eg. dst for swapin)
 	EnableReplaceSwap  bool
@@ -74,6 +114,8 @@ type ChainConfig struct {
 	CallByContractCodeHashWhitelist []string `json:",omitempty"`
 
 	MinReserveFee              string
+	BalanceWarning             string `json:",omitempty"` // native balance alert thresholds of payer account
+	BalanceCritical            string `json:",omitempty"`
 	BaseFeePercent             int64
 	BaseGasPrice               string `json:",omitempty"`
 	MaxGasPriceFluctPercent    uint64 `json:",omitempty"`
@@ -90,6 +132,12 @@ type ChainConfig struct {
 	MaxGasTipCap         string
 	MaxGasFeeCap         string
 
+	// bounds of extra args from initiator when accepting sign
+	MaxGasLimit              uint64 `json:",omitempty"`
+	GasLimitTolerancePercent uint64 `json:",omitempty"`
+	GasPriceTolerancePercent uint64 `json:",omitempty"`
+	NonceWindow              uint64 `json:",omitempty"`
+
 	// cached values
 	chainID       *big.Int
 	fixedGasPrice *big.Int
@@ -99,6 +147,9 @@ type ChainConfig struct {
 	maxGasTipCap  *big.Int
 	maxGasFeeCap  *big.Int
 
+	balanceWarning  *big.Int
+	balanceCritical *big.Int
+
 	callByContractWhitelist         map[string]struct{}
 	callByContractCodeHashWhitelist map[string]struct{}
 }
@@ -109,6 +160,24 @@ type TokenPriceConfig struct {
 	APIAddress []string
 }
 
+// NativePriceConfig native coin price config
+type NativePriceConfig struct {
+	Currency      string            // reference currency, eg. USD
+	FeedURL       string            `toml:",omitempty" json:",omitempty"`
+	FetchInterval uint64            `toml:",omitempty" json:",omitempty"` // seconds
+	MaxAge        uint64            `toml:",omitempty" json:",omitempty"` // seconds
+	Src           *NativeCoinConfig `toml:",omitempty" json:",omitempty"`
+	Dest          *NativeCoinConfig `toml:",omitempty" json:",omitempty"`
+}
+
+// NativeCoinConfig native coin of chain
+type NativeCoinConfig struct {
+	Symbol   string
+	Decimals uint8
+	CoinID   string  `toml:",omitempty" json:",omitempty"` // coin id in price feed
+	Price    float64 `toml:",omitempty" json:",omitempty"` // static price, not fetched from feed
+}
+
 // TokenConfig struct
 type TokenConfig struct {
 	ID                     string `json:",omitempty"`
@@ -127,6 +196,7 @@ type TokenConfig struct {
 	SwapFeeRate            *float64
 	MaximumSwapFee         *float64
 	MinimumSwapFee         *float64
+	RefundFee              *float64
 	TokenPrice             float64 `toml:"-"`
 	PlusGasPricePercentage uint64  `json:",omitempty"`
 	DisableSwap            bool
@@ -141,6 +211,24 @@ type TokenConfig struct {
 
 	BigValueWhitelist []string `json:",omitempty"`
 
+	// big value threshold in USD, either this or BigValueThreshold exceeded is big value
+	BigValueThresholdUSD *float64 `json:",omitempty"`
+	BigValuePriceMaxAge  uint64   `json:",omitempty"` // seconds
+	BigValueOnStalePrice string   `json:",omitempty"` // failopen or failclosed
+
+	// view methods of contract returning mint cap (eg. 'maxMintPerTx()'),
+	// swaps minting this token are limited by the tightest cap and 'MaximumSwap'
+	MintCapMethods []string `json:",omitempty"`
+
+	// split payout exceeding per tx cap (the tighter of 'MaximumPayoutPerTx'
+	// and mint cap) into multiple txs, mint cap does not reject swaps then
+	SplitPayout        bool     `json:",omitempty"`
+	MaximumPayoutPerTx *float64 `json:",omitempty"` // whole unit
+
+	// on-chain registry of bind addresses (destination chain only)
+	RegistryContract    string `json:",omitempty"`
+	RegistryStartHeight uint64 `json:",omitempty"`
+
 	// use private key address instead
 	DcrmAddressPriKey string `json:"-"`
 
@@ -150,6 +238,9 @@ type TokenConfig struct {
 	maxSwapFee       *big.Int
 	minSwapFee       *big.Int
 	bigValThreshhold *big.Int
+	refundFee        *big.Int
+	maxPayoutPerTx   *big.Int
+	tokenPriceTime   int64
 
 	bigValueWhitelist map[string]struct{}
 	RippleExtra       *RippleTokenExtra
@@ -180,6 +271,9 @@ func (c *ChainConfig) CheckConfig(isServer bool) error {
 	if c.InitialHeight == nil {
 		return errors.New("chain must config 'InitialHeight'")
 	}
+	if err := c.checkFinalityConfig(); err != nil {
+		return err
+	}
 	if c.BaseFeePercent < -90 || c.BaseFeePercent > 500 {
 		return errors.New("'BaseFeePercent' must be in range [-90, 500]")
 	}
@@ -222,6 +316,23 @@ func (c *ChainConfig) CheckConfig(isServer bool) error {
 		}
 		c.minReserveFee = bi
 	}
+	if c.BalanceWarning != "" {
+		bi, err := common.GetBigIntFromStr(c.BalanceWarning)
+		if err != nil {
+			return fmt.Errorf("wrong 'BalanceWarning': %w", err)
+		}
+		c.balanceWarning = bi
+	}
+	if c.BalanceCritical != "" {
+		bi, err := common.GetBigIntFromStr(c.BalanceCritical)
+		if err != nil {
+			return fmt.Errorf("wrong 'BalanceCritical': %w", err)
+		}
+		c.balanceCritical = bi
+	}
+	if c.balanceWarning != nil && c.balanceCritical != nil && c.balanceWarning.Cmp(c.balanceCritical) < 0 {
+		return errors.New("'BalanceWarning' is lower than 'BalanceCritical'")
+	}
 	if len(c.CallByContractWhitelist) > 0 {
 		c.callByContractWhitelist = make(map[string]struct{}, len(c.CallByContractWhitelist))
 		for _, addr := range c.CallByContractWhitelist {
@@ -334,11 +445,14 @@ func (c *TokenConfig) CheckConfig(isSrc bool) (err error) {
 	if *c.SwapFeeRate == 0.0 && *c.MinimumSwapFee > 0.0 {
 		return errors.New("wrong token config, MinimumSwapFee should be 0 if SwapFeeRate is 0")
 	}
+	if c.RefundFee != nil && *c.RefundFee < 0 {
+		return errors.New("wrong token config, RefundFee is negative")
+	}
 	if c.PlusGasPricePercentage > MaxPlusGasPricePercentage {
 		return errors.New("too large 'PlusGasPricePercentage' value")
 	}
-	if c.BigValueThreshold == nil {
-		return errors.New("token must config 'BigValueThreshold'")
+	if err = c.checkBigValueConfig(); err != nil {
+		return err
 	}
 	if c.DcrmAddress == "" {
 		return errors.New("token must config 'DcrmAddress'")
@@ -352,6 +466,20 @@ func (c *TokenConfig) CheckConfig(isSrc bool) (err error) {
 	if c.IsErc20() && c.ContractAddress == "" {
 		return errors.New("token must config 'ContractAddress' for ERC20 in source chain")
 	}
+	if c.RegistryContract != "" {
+		if isSrc {
+			return errors.New("token 'RegistryContract' is only support in destination chain")
+		}
+		if !common.IsHexAddress(c.RegistryContract) {
+			return errors.New("wrong 'RegistryContract' address")
+		}
+	}
+	if len(c.MintCapMethods) != 0 && c.ContractAddress == "" {
+		return errors.New("token must config 'ContractAddress' to query 'MintCapMethods'")
+	}
+	if c.SplitPayout && (c.MaximumPayoutPerTx == nil || *c.MaximumPayoutPerTx <= 0) {
+		return errors.New("token must config 'MaximumPayoutPerTx' (positive) to 'SplitPayout'")
+	}
 	if c.AllowSwapinFromContract {
 		if !isSrc || !c.IsErc20() {
 			return errors.New("only source ERC20 token allow swapin from contract")
@@ -386,6 +514,12 @@ func (c *TokenConfig) CheckConfig(isSrc bool) (err error) {
 	} else if c.DelegateToken != "" {
 		return errors.New("token forbid config 'DelegateToken' if 'IsDelegateContract' is false")
 	}
+	if c.DcrmPubkey != "" {
+		c.DcrmPubkey, err = NormalizeDcrmPublicKey(c.DcrmPubkey)
+		if err != nil {
+			return fmt.Errorf("wrong dcrm public key: %w", err)
+		}
+	}
 	err = c.VerifyDcrmPublicKey()
 	if err != nil {
 		return err
@@ -417,14 +551,12 @@ func (c *TokenConfig) CheckConfig(isSrc bool) (err error) {
 func (c *TokenConfig) CalcAndStoreValue() {
 	maxSwap := *c.MaximumSwap
 	minSwap := *c.MinimumSwap
-	bigSwap := *c.BigValueThreshold
 	maxFee := *c.MaximumSwapFee
 	minFee := *c.MinimumSwapFee
 	if c.TokenPrice > 0 {
 		// convert to token amount
 		maxSwap /= c.TokenPrice
 		minSwap /= c.TokenPrice
-		bigSwap /= c.TokenPrice
 		maxFee /= c.TokenPrice
 		minFee /= c.TokenPrice
 	}
@@ -434,7 +566,29 @@ func (c *TokenConfig) CalcAndStoreValue() {
 	c.minSwap = ToBits(minSwap-smallBiasValue, decimals)
 	c.maxSwapFee = ToBits(maxFee, decimals)
 	c.minSwapFee = ToBits(minFee, decimals)
-	c.bigValThreshhold = ToBits(bigSwap+smallBiasValue, decimals)
+	c.bigValThreshhold = nil
+	if c.BigValueThreshold != nil {
+		bigSwap := *c.BigValueThreshold
+		if c.TokenPrice > 0 {
+			bigSwap /= c.TokenPrice
+		}
+		c.bigValThreshhold = ToBits(bigSwap+smallBiasValue, decimals)
+	}
+	if c.RefundFee != nil {
+		refundFee := *c.RefundFee
+		if c.TokenPrice > 0 {
+			refundFee /= c.TokenPrice
+		}
+		c.refundFee = ToBits(refundFee, decimals)
+	}
+	c.maxPayoutPerTx = nil
+	if c.MaximumPayoutPerTx != nil {
+		maxPayout := *c.MaximumPayoutPerTx
+		if c.TokenPrice > 0 {
+			maxPayout /= c.TokenPrice
+		}
+		c.maxPayoutPerTx = ToBits(maxPayout, decimals)
+	}
 	if decimals > 8 {
 		mod := big.NewInt(10)
 		mod.Exp(mod, big.NewInt(int64(decimals-8)), nil)
@@ -442,7 +596,9 @@ func (c *TokenConfig) CalcAndStoreValue() {
 		c.minSwap = calcModValue(c.minSwap, mod)
 		c.maxSwapFee = calcModValue(c.maxSwapFee, mod)
 		c.minSwapFee = calcModValue(c.minSwapFee, mod)
-		c.bigValThreshhold = calcModValue(c.bigValThreshhold, mod)
+		if c.bigValThreshhold != nil {
+			c.bigValThreshhold = calcModValue(c.bigValThreshhold, mod)
+		}
 	}
 	log.Info("calc and store token swap and fee success",
 		"name", c.Name, "decimals", decimals, "contractAddress", c.ContractAddress,
@@ -525,6 +681,11 @@ func (c *ChainConfig) GetMinReserveFee() *big.Int {
 	return c.minReserveFee
 }
 
+// GetBalanceThresholds get native balance alert thresholds of payer account, nil if not configured
+func (c *ChainConfig) GetBalanceThresholds() (warning, critical *big.Int) {
+	return c.balanceWarning, c.balanceCritical
+}
+
 // GetMaxGasTipCap get max gas tip cap
 func (c *ChainConfig) GetMaxGasTipCap() *big.Int {
 	return c.maxGasTipCap
@@ -580,16 +741,11 @@ func (c *TokenConfig) VerifyDcrmPublicKey() error {
 	}
 
 	// ETH like address
-	pkBytes := common.FromHex(c.DcrmPubkey)
-	if len(pkBytes) != 65 || pkBytes[0] != 4 {
-		return fmt.Errorf("wrong uncompressed dcrm public key")
-	}
-	pubKey := ecdsa.PublicKey{
-		Curve: crypto.S256(),
-		X:     new(big.Int).SetBytes(pkBytes[1:33]),
-		Y:     new(big.Int).SetBytes(pkBytes[33:65]),
+	pubKey, err := ParsePublicKey(common.FromHex(c.DcrmPubkey))
+	if err != nil {
+		return fmt.Errorf("wrong dcrm public key: %w", err)
 	}
-	pubAddr := crypto.PubkeyToAddress(pubKey)
+	pubAddr := crypto.PubkeyToAddress(*pubKey)
 	if !strings.EqualFold(pubAddr.String(), c.DcrmAddress) {
 		return fmt.Errorf("dcrm address %v and public key address %v is not match", c.DcrmAddress, pubAddr.String())
 	}
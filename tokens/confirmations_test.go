@@ -0,0 +1,89 @@
+package tokens
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetPairStableConfirmations(t *testing.T) {
+	oldPairsConfig := tokenPairsConfig
+	oldSrc, oldDst := SrcStableConfirmations, DstStableConfirmations
+	defer func() {
+		tokenPairsConfig = oldPairsConfig
+		SrcStableConfirmations, DstStableConfirmations = oldSrc, oldDst
+	}()
+
+	SrcStableConfirmations = 6
+	DstStableConfirmations = 30
+
+	srcConfs, dstConfs := uint64(6), uint64(128)
+	reverseSrcConfs, reverseDstConfs := uint64(200), uint64(3)
+	tokenPairsConfig = map[string]*TokenPairConfig{
+		"btc2polygon": {PairID: "BTC2Polygon", SrcStableConfirmations: &srcConfs, DstStableConfirmations: &dstConfs},
+		"polygon2btc": {PairID: "Polygon2BTC", SrcStableConfirmations: &reverseSrcConfs, DstStableConfirmations: &reverseDstConfs},
+		"onlydst":     {PairID: "OnlyDst", DstStableConfirmations: &dstConfs},
+		"default":     {PairID: "Default"},
+	}
+
+	cases := []struct {
+		pairID string
+		isSrc  bool
+		want   uint64
+	}{
+		{"BTC2Polygon", true, 6},
+		{"BTC2Polygon", false, 128},
+		{"Polygon2BTC", true, 200},
+		{"Polygon2BTC", false, 3},
+		{"OnlyDst", true, 6},
+		{"OnlyDst", false, 128},
+		{"Default", true, 6},
+		{"Default", false, 30},
+		{"notexist", false, 30},
+	}
+	for _, c := range cases {
+		got := GetPairStableConfirmations(c.pairID, c.isSrc)
+		if got != c.want {
+			t.Errorf("pair %v isSrc %v: want %v, got %v", c.pairID, c.isSrc, c.want, got)
+		}
+	}
+}
+
+func TestCheckRegisterDepth(t *testing.T) {
+	oldPairsConfig := tokenPairsConfig
+	defer func() { tokenPairsConfig = oldPairsConfig }()
+
+	tokenPairsConfig = map[string]*TokenPairConfig{
+		"btc":     {PairID: "BTC", MinRegisterConfirmations: 3},
+		"default": {PairID: "Default"},
+	}
+
+	cases := []struct {
+		pairID         string
+		height, latest uint64
+		wantMsg        string // empty if not too recent
+	}{
+		{"BTC", 100, 103, ""},
+		{"BTC", 100, 110, ""},
+		{"BTC", 100, 102, "retry after 1 blocks (current depth 2, required 3)"},
+		{"BTC", 100, 100, "retry after 3 blocks (current depth 0, required 3)"},
+		{"BTC", 0, 100, "retry after 3 blocks (current depth 0, required 3)"}, // pending
+		{"Default", 0, 100, ""},
+		{"notexist", 0, 100, ""},
+	}
+	for _, c := range cases {
+		err := CheckRegisterDepth(c.pairID, c.height, c.latest)
+		if c.wantMsg == "" {
+			if err != nil {
+				t.Errorf("pair %v height %v latest %v: unexpected error %v", c.pairID, c.height, c.latest, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrTxTooRecent) || !strings.Contains(err.Error(), c.wantMsg) {
+			t.Errorf("pair %v height %v latest %v: want %q, got %v", c.pairID, c.height, c.latest, c.wantMsg, err)
+		}
+	}
+	if ShouldRegisterSwapForError(ErrTxTooRecent) {
+		t.Error("too recent tx should not be registered")
+	}
+}
@@ -0,0 +1,45 @@
+package tokens
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// GetSwapoutDustThreshold get dust threshold (in smallest unit of the payout chain)
+// of swapout paying to bind address, return nil if the payout chain has no dust rule.
+func GetSwapoutDustThreshold(bind string) *big.Int {
+	dustThresholder, ok := SrcBridge.(DustThresholder)
+	if !ok {
+		return nil
+	}
+	threshold, err := dustThresholder.GetDustThreshold(bind)
+	if err != nil {
+		return nil
+	}
+	return new(big.Int).SetUint64(threshold)
+}
+
+// CheckSwapoutDust check swapout value against dust threshold of the payout chain.
+// value below dust is rejected (ErrSwapoutValueIsDust),
+// value after swap fee below dust is held (ErrSwappedValueIsDust).
+func CheckSwapoutDust(swapInfo *TxSwapInfo) error {
+	threshold := GetSwapoutDustThreshold(swapInfo.Bind)
+	if threshold == nil {
+		return nil
+	}
+	token, cpToken := GetTokenConfigsByDirection(swapInfo.PairID, false)
+	if token == nil || cpToken == nil {
+		return ErrUnknownPairID
+	}
+	value := ConvertTokenValue(swapInfo.Value, *token.Decimals, *cpToken.Decimals)
+	if value == nil || value.Cmp(threshold) < 0 {
+		return fmt.Errorf("%w: value %v, dust threshold %v",
+			ErrSwapoutValueIsDust, cpToken.FormatValue(value), cpToken.FormatValue(threshold))
+	}
+	swappedValue := CalcSwappedValue(swapInfo.PairID, swapInfo.Value, false, swapInfo.From, swapInfo.TxTo)
+	if swappedValue.Cmp(threshold) < 0 {
+		return fmt.Errorf("%w: swapped value %v, dust threshold %v",
+			ErrSwappedValueIsDust, cpToken.FormatValue(swappedValue), cpToken.FormatValue(threshold))
+	}
+	return nil
+}
@@ -20,6 +20,9 @@ var (
 	ErrTxBeforeInitialHeight         = errors.New("transaction before initial block height")
 	ErrAddressIsInBlacklist          = errors.New("address is in black list")
 	ErrSwapIsClosed                  = errors.New("swap is closed")
+	ErrRefundNotSupported            = errors.New("refund not supported")
+	ErrRefundValueTooSmall           = errors.New("refund value is too small")
+	ErrWrongPublicKey                = errors.New("wrong public key")
 
 	ErrTodo = errors.New("developing: TODO")
 
@@ -48,29 +51,33 @@ var (
 	ErrTxWithWrongStatus    = errors.New("tx with wrong status")
 	ErrTxWithNoPayment      = errors.New("tx with no payment")
 	ErrTxIsNotValidated     = errors.New("tx is not validated")
+	ErrSwapoutValueIsDust   = errors.New("swapout value is below dust threshold")
+	ErrWrongPayoutLeg       = errors.New("wrong payout leg")
+	ErrPayoutExceedsCap     = errors.New("payout exceeds per tx cap")
+	ErrTxTooRecent          = errors.New("tx is too recent to register")
 
-	// errors should register
+	// errors should register (by default, see registererrors.go)
 	ErrTxWithWrongMemo       = errors.New("tx with wrong memo")
 	ErrTxWithWrongValue      = errors.New("tx with wrong value")
 	ErrTxSenderNotRegistered = errors.New("tx sender not registered")
 	ErrBindAddrIsContract    = errors.New("bind address is contract")
+	ErrSwappedValueIsDust    = errors.New("swapped value after fee is below dust threshold")
 )
 
-// ShouldRegisterSwapForError return true if this error should record in database
-func ShouldRegisterSwapForError(err error) bool {
+// IsRPCQueryOrNotFoundError is rpc or not found error
+func IsRPCQueryOrNotFoundError(err error) bool {
+	return errors.Is(err, ErrRPCQueryError) || errors.Is(err, ErrNotFound)
+}
+
+// IsRefundableVerifyError return true if deposit is valid but never swappable
+func IsRefundableVerifyError(err error) bool {
 	switch {
-	case err == nil:
 	case errors.Is(err, ErrTxWithWrongMemo):
 	case errors.Is(err, ErrTxWithWrongValue):
-	case errors.Is(err, ErrTxSenderNotRegistered):
 	case errors.Is(err, ErrBindAddrIsContract):
+	case errors.Is(err, ErrSwappedValueIsDust):
 	default:
 		return false
 	}
 	return true
 }
-
-// IsRPCQueryOrNotFoundError is rpc or not found error
-func IsRPCQueryOrNotFoundError(err error) bool {
-	return errors.Is(err, ErrRPCQueryError) || errors.Is(err, ErrNotFound)
-}
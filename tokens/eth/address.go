@@ -1,9 +1,14 @@
 package eth
 
 import (
+	"crypto/ecdsa"
 	"strings"
 
 	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
 	mapset "github.com/deckarep/golang-set"
 )
 
@@ -45,6 +50,54 @@ func (b *Bridge) IsValidAddress(address string) bool {
 	return ok
 }
 
+// ValidateAddress validate address and report reasons if invalid,
+// contract address is invalid if bind address is checked to be EOA.
+func (b *Bridge) ValidateAddress(address string) (normalized string, reasons []string) {
+	if !common.IsHexAddress(address) {
+		unprefixed := address
+		if common.HasHexPrefix(unprefixed) {
+			unprefixed = unprefixed[2:]
+		}
+		for _, c := range []byte(unprefixed) {
+			if !common.IsHexCharacter(c) {
+				return "", []string{tokens.AddressReasonWrongFormat}
+			}
+		}
+		return "", []string{tokens.AddressReasonWrongLength}
+	}
+	if !b.IsValidAddress(address) {
+		return "", []string{tokens.AddressReasonBadChecksum}
+	}
+	normalized = common.HexToAddress(address).String()
+	if params.CheckBindAddrIsContract() {
+		isContract, err := b.IsContractAddress(normalized)
+		switch {
+		case err != nil:
+			log.Warn("validate address check code failed", "address", address, "err", err)
+			reasons = append(reasons, tokens.AddressReasonCodeCheckFailed)
+		case isContract:
+			reasons = append(reasons, tokens.AddressReasonIsContract)
+		}
+	}
+	return normalized, reasons
+}
+
+// PublicKeyToAddress convert public key hex to address
+func (b *Bridge) PublicKeyToAddress(pubKeyHex string) (string, error) {
+	pkData := common.FromHex(pubKeyHex)
+	var pubKey *ecdsa.PublicKey
+	var err error
+	if len(pkData) == 33 {
+		pubKey, err = crypto.DecompressPubkey(pkData)
+	} else {
+		pubKey, err = crypto.UnmarshalPubkey(pkData)
+	}
+	if err != nil {
+		return "", err
+	}
+	return crypto.PubkeyToAddress(*pubKey).String(), nil
+}
+
 // IsContractAddress is contract address
 func (b *Bridge) IsContractAddress(address string) (bool, error) {
 	if cachedNoncontractAddrs.Contains(address) {
@@ -0,0 +1,36 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func TestValidateAddress(t *testing.T) {
+	b := &Bridge{}
+	checksummed := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	tests := []struct {
+		address    string
+		normalized string
+		reason     string
+	}{
+		{address: checksummed, normalized: checksummed},
+		{address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", normalized: checksummed},
+		{address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", reason: tokens.AddressReasonBadChecksum},
+		{address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea", reason: tokens.AddressReasonWrongLength},
+		{address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beazz", reason: tokens.AddressReasonWrongFormat},
+		{address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", reason: tokens.AddressReasonWrongFormat},
+	}
+	for _, test := range tests {
+		normalized, reasons := b.ValidateAddress(test.address)
+		if test.reason == "" {
+			if len(reasons) != 0 || normalized != test.normalized {
+				t.Errorf("address %v want valid %v, have %v %v", test.address, test.normalized, normalized, reasons)
+			}
+			continue
+		}
+		if len(reasons) != 1 || reasons[0] != test.reason {
+			t.Errorf("address %v want reason %v, have %v", test.address, test.reason, reasons)
+		}
+	}
+}
@@ -19,6 +19,10 @@ var (
 	_ tokens.CrossChainBridge = &Bridge{}
 	// ensure Bridge impl tokens.NonceSetter
 	_ tokens.NonceSetter = &Bridge{}
+	// ensure Bridge impl tokens.AddressValidator
+	_ tokens.AddressValidator = &Bridge{}
+	// ensure Bridge impl tokens.ContractChecker
+	_ tokens.ContractChecker = &Bridge{}
 	// ensure Bridge impl InheritInterface
 	_ InheritInterface = &Bridge{}
 )
@@ -37,6 +41,11 @@ type Bridge struct {
 	SignerChainID *big.Int
 }
 
+func init() {
+	newBridge := func(isSrc bool) tokens.CrossChainBridge { return NewCrossChainBridge(isSrc) }
+	tokens.RegisterBridgeConstructor("ETHEREUM", newBridge)
+}
+
 // NewCrossChainBridge new bridge
 func NewCrossChainBridge(isSrc bool) *Bridge {
 	bridge := &Bridge{
@@ -88,7 +97,7 @@ func (b *Bridge) VerifyChainID() {
 			break
 		}
 		log.Errorf("can not get gateway chainID. %v", err)
-		log.Println("retry query gateway", b.GatewayConfig.APIAddress)
+		log.Println("retry query gateway", b.GatewayConfig.GetAPIAddress())
 		time.Sleep(1 * time.Second)
 	}
 	if err != nil {
@@ -219,7 +228,7 @@ func (b *Bridge) InitLatestBlockNumber() {
 			break
 		}
 		log.Error("get latst block number failed.", "BlockChain", b.ChainConfig.BlockChain, "NetID", b.ChainConfig.NetID, "err", err)
-		log.Println("retry query gateway", b.GatewayConfig.APIAddress)
+		log.Println("retry query gateway", b.GatewayConfig.GetAPIAddress())
 		time.Sleep(3 * time.Second)
 	}
 }
@@ -0,0 +1,90 @@
+package eth
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/eth/abicoder"
+)
+
+// BuildRefundTransaction build tx refunding deposit (minus refund fee) to its sender.
+// swapin deposit is transferred back on source chain,
+// swapout deposit (burned) is minted back on destination chain.
+func (b *Bridge) BuildRefundTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	if args.Identifier != tokens.RefundIdentifier {
+		return nil, tokens.ErrRefundNotSupported
+	}
+	if args.Input != nil {
+		return nil, errNonEmptyInputData
+	}
+	if args.From == "" {
+		return nil, errNoSenderSpecified
+	}
+	if args.Value != nil && args.Value.Sign() != 0 {
+		return nil, errNonzeroValueSpecified
+	}
+	if (args.SwapType == tokens.SwapinType) != b.IsSrc {
+		return nil, tokens.ErrBuildSwapTxInWrongEndpoint
+	}
+
+	err = b.setDefaultGasPrice(args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.buildRefundTxInput(args)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.setDefaults(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.buildTx(args)
+}
+
+func (b *Bridge) buildRefundTxInput(args *tokens.BuildTxArgs) (err error) {
+	token := b.GetTokenConfig(args.PairID)
+	if token == nil {
+		return tokens.ErrUnknownPairID
+	}
+
+	receiver := common.HexToAddress(args.OriginFrom)
+	if receiver == (common.Address{}) || !common.IsHexAddress(args.OriginFrom) {
+		log.Warn("refund to wrong address", "receiver", args.OriginFrom)
+		return errInvalidReceiverAddress
+	}
+
+	refundValue := tokens.CalcRefundValue(args.PairID, args.OriginValue, b.IsSrc)
+	if refundValue.Sign() <= 0 {
+		return tokens.ErrRefundValueTooSmall
+	}
+	args.SwapValue = refundValue // refund value
+
+	if !b.IsSrc {
+		funcHash := getSwapinFuncHash()
+		txHash := common.HexToHash(args.SwapID)
+		input := abicoder.PackDataWithFuncHash(funcHash, txHash, receiver, refundValue)
+		args.Input = &input             // input
+		args.To = token.ContractAddress // to
+		if token.IsDelegateContract && !token.IsAnyswapAdapter {
+			return b.checkBalance(token.DelegateToken, token.ContractAddress, refundValue)
+		}
+		return nil
+	}
+
+	if token.ContractAddress == "" {
+		args.To = args.OriginFrom // to
+		args.Value = refundValue  // value
+		return nil
+	}
+
+	funcHash := erc20CodeParts["transfer"]
+	input := abicoder.PackDataWithFuncHash(funcHash, receiver, refundValue)
+	args.Input = &input             // input
+	args.To = token.ContractAddress // to
+
+	return b.checkBalance(token.ContractAddress, token.DcrmAddress, refundValue)
+}
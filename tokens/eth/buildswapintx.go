@@ -27,6 +27,10 @@ func (b *Bridge) buildSwapinTxInput(args *tokens.BuildTxArgs) (err error) {
 	if err != nil {
 		return err
 	}
+	swapValue, err = tokens.CalcPayoutLegValue(args.PairID, b.IsSrc, swapValue, args.PayoutLeg)
+	if err != nil {
+		return err
+	}
 	args.SwapValue = swapValue // swap value
 
 	funcHash := getSwapinFuncHash()
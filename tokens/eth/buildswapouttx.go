@@ -31,6 +31,10 @@ func (b *Bridge) buildSwapoutTxInput(args *tokens.BuildTxArgs) (err error) {
 	if err != nil {
 		return err
 	}
+	swapValue, err = tokens.CalcPayoutLegValue(args.PairID, b.IsSrc, swapValue, args.PayoutLeg)
+	if err != nil {
+		return err
+	}
 	args.SwapValue = swapValue // swap value
 
 	if token.ContractAddress == "" {
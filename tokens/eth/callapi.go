@@ -39,7 +39,7 @@ func (b *Bridge) GetLatestBlockNumberOf(url string) (latest uint64, err error) {
 // GetLatestBlockNumber call eth_blockNumber
 func (b *Bridge) GetLatestBlockNumber() (uint64, error) {
 	gateway := b.GatewayConfig
-	maxHeight, err := getMaxLatestBlockNumber(gateway.APIAddress)
+	maxHeight, err := getMaxLatestBlockNumber(gateway.GetAPIAddress())
 	if maxHeight > 0 {
 		tokens.CmpAndSetLatestBlockHeight(maxHeight, b.IsSrcEndpoint())
 		return maxHeight, nil
@@ -70,7 +70,7 @@ func getMaxLatestBlockNumber(urls []string) (maxHeight uint64, err error) {
 // GetBlockByHash call eth_getBlockByHash
 func (b *Bridge) GetBlockByHash(blockHash string) (*types.RPCBlock, error) {
 	gateway := b.GatewayConfig
-	return getBlockByHash(blockHash, gateway.APIAddress)
+	return getBlockByHash(blockHash, gateway.GetAPIAddress())
 }
 
 func getBlockByHash(blockHash string, urls []string) (result *types.RPCBlock, err error) {
@@ -78,7 +78,7 @@ func getBlockByHash(blockHash string, urls []string) (result *types.RPCBlock, er
 		return nil, errEmptyURLs
 	}
 	for _, url := range urls {
-		err = client.RPCPost(&result, url, "eth_getBlockByHash", blockHash, false)
+		err = strictRPCPost(&result, url, "eth_getBlockByHash", blockHash, false)
 		if err == nil && result != nil {
 			return result, nil
 		}
@@ -92,9 +92,9 @@ func (b *Bridge) GetBlockByNumber(number *big.Int) (*types.RPCBlock, error) {
 	var result *types.RPCBlock
 	var err error
 	blockNumber := types.ToBlockNumArg(number)
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress
-		err = client.RPCPost(&result, url, "eth_getBlockByNumber", blockNumber, false)
+		err = strictRPCPost(&result, url, "eth_getBlockByNumber", blockNumber, false)
 		if err == nil && result != nil {
 			return result, nil
 		}
@@ -102,10 +102,25 @@ func (b *Bridge) GetBlockByNumber(number *big.Int) (*types.RPCBlock, error) {
 	return nil, wrapRPCQueryError(err, "eth_getBlockByNumber", number)
 }
 
+// GetBlockNumberByTag call eth_getBlockByNumber with block tag (eg. safe, finalized)
+func (b *Bridge) GetBlockNumberByTag(tag string) (uint64, error) {
+	gateway := b.GatewayConfig
+	var result *types.RPCBlock
+	var err error
+	for _, apiAddress := range gateway.GetAPIAddress() {
+		url := apiAddress
+		err = strictRPCPost(&result, url, "eth_getBlockByNumber", tag, false)
+		if err == nil && result != nil && result.Number != nil {
+			return result.Number.ToInt().Uint64(), nil
+		}
+	}
+	return 0, wrapRPCQueryError(err, "eth_getBlockByNumber", tag)
+}
+
 // GetBlockHash impl
 func (b *Bridge) GetBlockHash(height uint64) (hash string, err error) {
 	gateway := b.GatewayConfig
-	return b.GetBlockHashOf(gateway.APIAddress, height)
+	return b.GetBlockHashOf(gateway.GetAPIAddress(), height)
 }
 
 // GetBlockHashOf impl
@@ -116,7 +131,7 @@ func (b *Bridge) GetBlockHashOf(urls []string, height uint64) (hash string, err
 	blockNumber := types.ToBlockNumArg(new(big.Int).SetUint64(height))
 	var block *types.RPCBaseBlock
 	for _, url := range urls {
-		err = client.RPCPost(&block, url, "eth_getBlockByNumber", blockNumber, false)
+		err = strictRPCPost(&block, url, "eth_getBlockByNumber", blockNumber, false)
 		if err == nil && block != nil {
 			return block.Hash.Hex(), nil
 		}
@@ -127,9 +142,9 @@ func (b *Bridge) GetBlockHashOf(urls []string, height uint64) (hash string, err
 // GetTransaction impl
 func (b *Bridge) GetTransaction(txHash string) (tx interface{}, err error) {
 	gateway := b.GatewayConfig
-	tx, err = b.getTransactionByHash(txHash, gateway.APIAddress)
-	if err != nil && tokens.IsRPCQueryOrNotFoundError(err) && len(gateway.APIAddressExt) > 0 {
-		tx, err = b.getTransactionByHash(txHash, gateway.APIAddressExt)
+	tx, err = b.getTransactionByHash(txHash, gateway.GetAPIAddress())
+	if err != nil && tokens.IsRPCQueryOrNotFoundError(err) && len(gateway.GetAPIAddressExt()) > 0 {
+		tx, err = b.getTransactionByHash(txHash, gateway.GetAPIAddressExt())
 	}
 	return tx, err
 }
@@ -137,7 +152,7 @@ func (b *Bridge) GetTransaction(txHash string) (tx interface{}, err error) {
 // GetTransactionByHash call eth_getTransactionByHash
 func (b *Bridge) GetTransactionByHash(txHash string) (*types.RPCTransaction, error) {
 	gateway := b.GatewayConfig
-	return b.getTransactionByHash(txHash, gateway.APIAddress)
+	return b.getTransactionByHash(txHash, gateway.GetAPIAddress())
 }
 
 func (b *Bridge) getTransactionByHash(txHash string, urls []string) (result *types.RPCTransaction, err error) {
@@ -145,7 +160,7 @@ func (b *Bridge) getTransactionByHash(txHash string, urls []string) (result *typ
 		return nil, errEmptyURLs
 	}
 	for _, url := range urls {
-		err = client.RPCPost(&result, url, "eth_getTransactionByHash", txHash)
+		err = strictRPCPost(&result, url, "eth_getTransactionByHash", txHash)
 		if err == nil && result != nil {
 			if !common.IsEqualIgnoreCase(result.Hash.Hex(), txHash) {
 				return nil, errTxHashMismatch
@@ -159,7 +174,7 @@ func (b *Bridge) getTransactionByHash(txHash string, urls []string) (result *typ
 // GetTransactionByBlockNumberAndIndex get tx by block number and tx index
 func (b *Bridge) GetTransactionByBlockNumberAndIndex(blockNumber *big.Int, txIndex uint) (result *types.RPCTransaction, err error) {
 	gateway := b.GatewayConfig
-	for _, url := range gateway.APIAddress {
+	for _, url := range gateway.GetAPIAddress() {
 		result, err = getTransactionByBlockNumberAndIndex(blockNumber, txIndex, url)
 		if err == nil && result != nil {
 			return result, nil
@@ -169,7 +184,7 @@ func (b *Bridge) GetTransactionByBlockNumberAndIndex(blockNumber *big.Int, txInd
 }
 
 func getTransactionByBlockNumberAndIndex(blockNumber *big.Int, txIndex uint, url string) (result *types.RPCTransaction, err error) {
-	err = client.RPCPost(&result, url, "eth_getTransactionByBlockNumberAndIndex", types.ToBlockNumArg(blockNumber), hexutil.Uint64(txIndex))
+	err = strictRPCPost(&result, url, "eth_getTransactionByBlockNumberAndIndex", types.ToBlockNumArg(blockNumber), hexutil.Uint64(txIndex))
 	if err == nil && result != nil {
 		return result, nil
 	}
@@ -179,7 +194,7 @@ func getTransactionByBlockNumberAndIndex(blockNumber *big.Int, txIndex uint, url
 // GetPendingTransactions call eth_pendingTransactions
 func (b *Bridge) GetPendingTransactions() (result []*types.RPCTransaction, err error) {
 	gateway := b.GatewayConfig
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress
 		err = client.RPCPost(&result, url, "eth_pendingTransactions")
 		if err == nil {
@@ -193,10 +208,10 @@ func (b *Bridge) GetPendingTransactions() (result []*types.RPCTransaction, err e
 func (b *Bridge) GetTxBlockInfo(txHash string) (blockHeight, blockTime uint64) {
 	var useExt bool
 	gateway := b.GatewayConfig
-	receipt, _, err := b.getTransactionReceipt(txHash, gateway.APIAddress)
-	if (err != nil || receipt == nil) && len(gateway.APIAddressExt) > 0 {
+	receipt, _, err := b.getTransactionReceipt(txHash, gateway.GetAPIAddress())
+	if (err != nil || receipt == nil) && len(gateway.GetAPIAddressExt()) > 0 {
 		useExt = true
-		receipt, _, err = b.getTransactionReceipt(txHash, gateway.APIAddressExt)
+		receipt, _, err = b.getTransactionReceipt(txHash, gateway.GetAPIAddressExt())
 	}
 	if err != nil || receipt == nil {
 		return 0, 0
@@ -214,9 +229,9 @@ func (b *Bridge) GetTxBlockInfo(txHash string) (blockHeight, blockTime uint64) {
 // GetTransactionReceipt call eth_getTransactionReceipt
 func (b *Bridge) GetTransactionReceipt(txHash string) (receipt *types.RPCTxReceipt, url string, err error) {
 	gateway := b.GatewayConfig
-	receipt, url, err = b.getTransactionReceipt(txHash, gateway.APIAddress)
-	if err != nil && tokens.IsRPCQueryOrNotFoundError(err) && len(gateway.APIAddressExt) > 0 {
-		return b.getTransactionReceipt(txHash, gateway.APIAddressExt)
+	receipt, url, err = b.getTransactionReceipt(txHash, gateway.GetAPIAddress())
+	if err != nil && tokens.IsRPCQueryOrNotFoundError(err) && len(gateway.GetAPIAddressExt()) > 0 {
+		return b.getTransactionReceipt(txHash, gateway.GetAPIAddressExt())
 	}
 	return receipt, url, err
 }
@@ -226,7 +241,7 @@ func (b *Bridge) getTransactionReceipt(txHash string, urls []string) (result *ty
 		return nil, "", errEmptyURLs
 	}
 	for _, url := range urls {
-		err = client.RPCPost(&result, url, "eth_getTransactionReceipt", txHash)
+		err = strictRPCPost(&result, url, "eth_getTransactionReceipt", txHash)
 		if err == nil && result != nil {
 			if result.BlockNumber == nil || result.BlockHash == nil || result.TxIndex == nil {
 				return nil, "", errTxReceiptMissBlockInfo
@@ -287,9 +302,9 @@ func (b *Bridge) GetLogs(filterQuery *types.FilterQuery) (result []*types.RPCLog
 		return nil, err
 	}
 	gateway := b.GatewayConfig
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress
-		err = client.RPCPost(&result, url, "eth_getLogs", args)
+		err = strictRPCPost(&result, url, "eth_getLogs", args)
 		if err == nil {
 			return result, nil
 		}
@@ -301,7 +316,7 @@ func (b *Bridge) GetLogs(filterQuery *types.FilterQuery) (result []*types.RPCLog
 func (b *Bridge) GetPoolNonce(address, height string) (uint64, error) {
 	account := common.HexToAddress(address)
 	gateway := b.GatewayConfig
-	return getMaxPoolNonce(account, height, gateway.APIAddress)
+	return getMaxPoolNonce(account, height, gateway.GetAPIAddress())
 }
 
 func getMaxPoolNonce(account common.Address, height string, urls []string) (maxNonce uint64, err error) {
@@ -328,7 +343,7 @@ func getMaxPoolNonce(account common.Address, height string, urls []string) (maxN
 // SuggestPrice call eth_gasPrice
 func (b *Bridge) SuggestPrice() (*big.Int, error) {
 	gateway := b.GatewayConfig
-	return getMedianGasPrice(gateway.APIAddress, gateway.APIAddressExt)
+	return getMedianGasPrice(gateway.GetAPIAddress(), gateway.GetAPIAddressExt())
 }
 
 // get median gas price as the rpc result fluctuates too widely
@@ -380,7 +395,7 @@ func (b *Bridge) SendSignedTransaction(tx *types.Transaction) (txHash string, er
 	log.Info("call eth_sendRawTransaction start", "txHash", tx.Hash().String())
 	hexData := common.ToHex(data)
 	gateway := b.GatewayConfig
-	urlCount := len(gateway.APIAddressExt) + len(gateway.APIAddress)
+	urlCount := len(gateway.GetAPIAddressExt()) + len(gateway.GetAPIAddress())
 	ch := make(chan *sendTxResult, urlCount)
 	wg := new(sync.WaitGroup)
 	wg.Add(urlCount)
@@ -389,10 +404,10 @@ func (b *Bridge) SendSignedTransaction(tx *types.Transaction) (txHash string, er
 		close(ch)
 		log.Info("call eth_sendRawTransaction finished", "txHash", txHash)
 	}()
-	for _, url := range gateway.APIAddress {
+	for _, url := range gateway.GetAPIAddress() {
 		go sendRawTransaction(wg, hexData, url, ch)
 	}
-	for _, url := range gateway.APIAddressExt {
+	for _, url := range gateway.GetAPIAddressExt() {
 		go sendRawTransaction(wg, hexData, url, ch)
 	}
 	for i := 0; i < urlCount; i++ {
@@ -428,7 +443,7 @@ func (b *Bridge) ChainID() (*big.Int, error) {
 	gateway := b.GatewayConfig
 	var result hexutil.Big
 	var err error
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress
 		err = client.RPCPost(&result, url, "eth_chainId")
 		if err == nil {
@@ -443,7 +458,7 @@ func (b *Bridge) NetworkID() (*big.Int, error) {
 	gateway := b.GatewayConfig
 	var result string
 	var err error
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress
 		err = client.RPCPost(&result, url, "net_version")
 		if err == nil {
@@ -475,9 +490,9 @@ func (b *Bridge) GetSignerChainID() (*big.Int, error) {
 // GetCode call eth_getCode
 func (b *Bridge) GetCode(contract string) (code []byte, err error) {
 	gateway := b.GatewayConfig
-	code, err = getCode(contract, gateway.APIAddress)
-	if err != nil && len(gateway.APIAddressExt) > 0 {
-		return getCode(contract, gateway.APIAddressExt)
+	code, err = getCode(contract, gateway.GetAPIAddress())
+	if err != nil && len(gateway.GetAPIAddressExt()) > 0 {
+		return getCode(contract, gateway.GetAPIAddressExt())
 	}
 	return code, err
 }
@@ -506,7 +521,7 @@ func (b *Bridge) CallContract(contract string, data hexutil.Bytes, blockNumber s
 	gateway := b.GatewayConfig
 	var result string
 	var err error
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress
 		err = client.RPCPost(&result, url, "eth_call", reqArgs, blockNumber)
 		if err == nil {
@@ -525,7 +540,7 @@ func (b *Bridge) GetBalance(account string) (*big.Int, error) {
 	gateway := b.GatewayConfig
 	var result hexutil.Big
 	var err error
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress
 		err = client.RPCPost(&result, url, "eth_getBalance", account, params.GetBalanceBlockNumberOpt)
 		if err == nil {
@@ -538,10 +553,10 @@ func (b *Bridge) GetBalance(account string) (*big.Int, error) {
 // SuggestGasTipCap call eth_maxPriorityFeePerGas
 func (b *Bridge) SuggestGasTipCap() (maxGasTipCap *big.Int, err error) {
 	gateway := b.GatewayConfig
-	if len(gateway.APIAddressExt) > 0 {
-		maxGasTipCap, err = getMaxGasTipCap(gateway.APIAddressExt)
+	if len(gateway.GetAPIAddressExt()) > 0 {
+		maxGasTipCap, err = getMaxGasTipCap(gateway.GetAPIAddressExt())
 	}
-	maxGasTipCap2, err2 := getMaxGasTipCap(gateway.APIAddress)
+	maxGasTipCap2, err2 := getMaxGasTipCap(gateway.GetAPIAddress())
 	if err2 == nil {
 		if maxGasTipCap == nil || maxGasTipCap2.Cmp(maxGasTipCap) > 0 {
 			maxGasTipCap = maxGasTipCap2
@@ -579,9 +594,9 @@ func getMaxGasTipCap(urls []string) (maxGasTipCap *big.Int, err error) {
 // FeeHistory call eth_feeHistory
 func (b *Bridge) FeeHistory(blockCount int, rewardPercentiles []float64) (*types.FeeHistoryResult, error) {
 	gateway := b.GatewayConfig
-	result, err := getFeeHistory(gateway.APIAddress, blockCount, rewardPercentiles)
-	if err != nil && len(gateway.APIAddressExt) > 0 {
-		result, err = getFeeHistory(gateway.APIAddressExt, blockCount, rewardPercentiles)
+	result, err := getFeeHistory(gateway.GetAPIAddress(), blockCount, rewardPercentiles)
+	if err != nil && len(gateway.GetAPIAddressExt()) > 0 {
+		result, err = getFeeHistory(gateway.GetAPIAddressExt(), blockCount, rewardPercentiles)
 	}
 	return result, err
 }
@@ -634,7 +649,7 @@ func (b *Bridge) EstimateGas(from, to string, value *big.Int, data []byte) (uint
 	gateway := b.GatewayConfig
 	var result hexutil.Uint64
 	var err error
-	for _, apiAddress := range gateway.APIAddress {
+	for _, apiAddress := range gateway.GetAPIAddress() {
 		url := apiAddress
 		err = client.RPCPost(&result, url, "eth_estimateGas", reqArgs)
 		if err == nil {
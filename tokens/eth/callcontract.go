@@ -50,6 +50,16 @@ func (b *Bridge) GetErc20Decimals(contract string) (uint8, error) {
 	return uint8(decimals), err
 }
 
+// GetMintCap get mint cap of token contract by calling view method without arguments
+func (b *Bridge) GetMintCap(contract, method string) (*big.Int, error) {
+	data := hexutil.Bytes(common.Keccak256Hash([]byte(method)).Bytes()[:4])
+	result, err := b.CallContract(contract, data, "latest")
+	if err != nil {
+		return nil, err
+	}
+	return common.GetBigIntFromStr(result)
+}
+
 // GetTokenBalance api
 func (b *Bridge) GetTokenBalance(tokenType, tokenAddress, accountAddress string) (*big.Int, error) {
 	switch strings.ToUpper(tokenType) {
@@ -0,0 +1,79 @@
+package eth
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// setTxFinalized set `Finalized` of tx status if finality strategy is not confirmations
+func (b *Bridge) setTxFinalized(txStatus *tokens.TxStatus) {
+	finality := b.ChainConfig.GetFinality()
+	if finality == tokens.FinalityConfirmations {
+		return
+	}
+	finalized := false
+	txStatus.Finalized = &finalized
+	if txStatus.BlockHeight == 0 {
+		return
+	}
+	finalizedHeight, err := b.GetFinalizedBlockNumber()
+	if err != nil {
+		log.Warn("get finalized block number failed", "finality", finality, "err", err)
+		return
+	}
+	finalized = txStatus.BlockHeight <= finalizedHeight
+}
+
+// GetFinalizedBlockNumber get latest final block number by finality strategy
+func (b *Bridge) GetFinalizedBlockNumber() (uint64, error) {
+	switch finality := b.ChainConfig.GetFinality(); finality {
+	case tokens.FinalitySafe, tokens.FinalityFinalized:
+		return b.GetBlockNumberByTag(finality)
+	case tokens.FinalityCheckpoint:
+		return b.getCheckpointBlockNumber()
+	default:
+		latest, err := b.GetLatestBlockNumber()
+		if err != nil {
+			return 0, err
+		}
+		confirmations := *b.ChainConfig.Confirmations
+		if latest < confirmations {
+			return 0, nil
+		}
+		return latest - confirmations, nil
+	}
+}
+
+// getCheckpointBlockNumber call checkpoint contract (eg. polygon root chain on ethereum)
+// for the last block number of this chain which is checkpointed
+func (b *Bridge) getCheckpointBlockNumber() (uint64, error) {
+	method := b.ChainConfig.GetCheckpointMethod()
+	reqArgs := map[string]interface{}{
+		"to":   b.ChainConfig.CheckpointContract,
+		"data": common.ToHex(common.Keccak256Hash([]byte(method)).Bytes()[:4]),
+	}
+	var result string
+	var err error
+	for _, apiAddress := range b.ChainConfig.CheckpointAPIAddress {
+		err = client.RPCPost(&result, apiAddress, "eth_call", reqArgs, "latest")
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return 0, wrapRPCQueryError(err, "eth_call", b.ChainConfig.CheckpointContract)
+	}
+	data := common.FromHex(result)
+	if len(data) < 32 {
+		return 0, fmt.Errorf("wrong checkpoint result '%v' of method '%v'", result, method)
+	}
+	number := common.GetBigInt(data, 0, 32)
+	if !number.IsUint64() {
+		return 0, fmt.Errorf("checkpoint block number %v overflow", number)
+	}
+	return number.Uint64(), nil
+}
@@ -0,0 +1,179 @@
+package eth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+var errSuspectRPCResult = errors.New("suspect rpc result")
+
+// rpcFieldError field of rpc result is missing or malformed
+type rpcFieldError struct {
+	Field  string
+	Reason string
+}
+
+func (e *rpcFieldError) Error() string {
+	return fmt.Sprintf("%v: field '%v' %v", errSuspectRPCResult, e.Field, e.Reason)
+}
+
+func (e *rpcFieldError) Unwrap() error {
+	return errSuspectRPCResult
+}
+
+func missingField(field string) error {
+	return &rpcFieldError{Field: field, Reason: "is missing"}
+}
+
+// strictRPCPost call rpc and decode result strictly,
+// suspect result is logged with gateway and field and returned as error,
+// so that the caller can fail over to another gateway.
+func strictRPCPost(result interface{}, url, method string, params ...interface{}) error {
+	var raw json.RawMessage
+	err := client.RPCPost(&raw, url, method, params...)
+	if err != nil {
+		return err
+	}
+	err = decodeRPCResult(raw, result)
+	if err != nil {
+		field := ""
+		var fieldErr *rpcFieldError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &fieldErr):
+			field = fieldErr.Field
+		case errors.As(err, &typeErr):
+			field = typeErr.Field
+		}
+		log.Warn("suspect rpc result", "gateway", url, "method", method, "field", field, "err", err)
+	}
+	return err
+}
+
+// decodeRPCResult decode and validate rpc result,
+// null result is decoded to nil and is not validated.
+func decodeRPCResult(raw json.RawMessage, result interface{}) error {
+	err := json.Unmarshal(raw, result)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errSuspectRPCResult, err)
+	}
+	switch res := result.(type) {
+	case **types.RPCBlock:
+		return validateRPCBlock(*res)
+	case **types.RPCBaseBlock:
+		return validateRPCBaseBlock(*res)
+	case **types.RPCTransaction:
+		return validateRPCTransaction(*res)
+	case **types.RPCTxReceipt:
+		return validateRPCTxReceipt(*res)
+	case *[]*types.RPCLog:
+		return validateRPCLogs(*res, "")
+	}
+	return nil
+}
+
+func validateRPCBaseBlock(block *types.RPCBaseBlock) error {
+	switch {
+	case block == nil:
+		return nil
+	case block.Hash == nil:
+		return missingField("hash")
+	case block.ParentHash == nil:
+		return missingField("parentHash")
+	case block.Number == nil:
+		return missingField("number")
+	case block.Time == nil:
+		return missingField("timestamp")
+	}
+	return nil
+}
+
+func validateRPCBlock(block *types.RPCBlock) error {
+	if block == nil {
+		return nil
+	}
+	err := validateRPCBaseBlock(&types.RPCBaseBlock{
+		Hash:       block.Hash,
+		ParentHash: block.ParentHash,
+		Number:     block.Number,
+		Time:       block.Time,
+	})
+	if err != nil {
+		return err
+	}
+	for i, txHash := range block.Transactions {
+		if txHash == nil {
+			return missingField(fmt.Sprintf("transactions[%d]", i))
+		}
+	}
+	return nil
+}
+
+func validateRPCTransaction(tx *types.RPCTransaction) error {
+	switch {
+	case tx == nil:
+		return nil
+	case tx.Hash == nil:
+		return missingField("hash")
+	case tx.From == nil:
+		return missingField("from")
+	case tx.AccountNonce == "":
+		return missingField("nonce")
+	case !strings.HasPrefix(tx.AccountNonce, "0x"):
+		return &rpcFieldError{Field: "nonce", Reason: "is not 0x prefixed hex"}
+	case tx.GasLimit == nil:
+		return missingField("gas")
+	case tx.Amount == nil:
+		return missingField("value")
+	case tx.Payload == nil:
+		return missingField("input")
+	case tx.BlockNumber != nil && tx.BlockHash == nil:
+		return missingField("blockHash")
+	case tx.BlockNumber != nil && tx.TxIndex == nil:
+		return missingField("transactionIndex")
+	}
+	return nil
+}
+
+func validateRPCTxReceipt(receipt *types.RPCTxReceipt) error {
+	switch {
+	case receipt == nil:
+		return nil
+	case receipt.TxHash == nil:
+		return missingField("transactionHash")
+	case receipt.Status == nil:
+		return missingField("status")
+	case *receipt.Status > 1:
+		return &rpcFieldError{Field: "status", Reason: fmt.Sprintf("has unknown value %d", *receipt.Status)}
+	case receipt.GasUsed == nil:
+		return missingField("gasUsed")
+	case receipt.Logs == nil:
+		return missingField("logs")
+	}
+	return validateRPCLogs(receipt.Logs, "logs")
+}
+
+func validateRPCLogs(logs []*types.RPCLog, prefix string) error {
+	for i, rlog := range logs {
+		field := fmt.Sprintf("%v[%d]", prefix, i)
+		switch {
+		case rlog == nil:
+			return missingField(field)
+		case rlog.Address == nil:
+			return missingField(field + ".address")
+		case rlog.Topics == nil:
+			return missingField(field + ".topics")
+		case rlog.Data == nil:
+			return missingField(field + ".data")
+		case rlog.Removed == nil:
+			return missingField(field + ".removed")
+		}
+	}
+	return nil
+}
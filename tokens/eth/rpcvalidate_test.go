@@ -0,0 +1,97 @@
+package eth
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+type rpcResultTest struct {
+	fixture   string
+	newResult func() interface{}
+	wantErr   bool
+	wantField string
+}
+
+var rpcResultTests = []*rpcResultTest{
+	{fixture: "block_valid.json", newResult: newRPCBlock},
+	{fixture: "block_number_without_0x.json", newResult: newRPCBlock, wantErr: true},
+	{fixture: "block_missing_timestamp.json", newResult: newRPCBlock, wantErr: true, wantField: "timestamp"},
+	{fixture: "block_valid.json", newResult: newRPCBaseBlock},
+	{fixture: "block_missing_timestamp.json", newResult: newRPCBaseBlock, wantErr: true, wantField: "timestamp"},
+	{fixture: "tx_valid.json", newResult: newRPCTransaction},
+	{fixture: "tx_nonce_decimal.json", newResult: newRPCTransaction, wantErr: true, wantField: "nonce"},
+	{fixture: "tx_missing_block_hash.json", newResult: newRPCTransaction, wantErr: true, wantField: "blockHash"},
+	{fixture: "receipt_valid.json", newResult: newRPCTxReceipt},
+	{fixture: "receipt_status_string.json", newResult: newRPCTxReceipt, wantErr: true},
+	{fixture: "receipt_status_number.json", newResult: newRPCTxReceipt, wantErr: true},
+	{fixture: "receipt_missing_status.json", newResult: newRPCTxReceipt, wantErr: true, wantField: "status"},
+	{fixture: "receipt_log_missing_removed.json", newResult: newRPCTxReceipt, wantErr: true, wantField: "logs[0].removed"},
+	{fixture: "logs_valid.json", newResult: newRPCLogs},
+	{fixture: "logs_missing_removed.json", newResult: newRPCLogs, wantErr: true, wantField: "[1].removed"},
+}
+
+func newRPCBlock() interface{} {
+	var result *types.RPCBlock
+	return &result
+}
+
+func newRPCBaseBlock() interface{} {
+	var result *types.RPCBaseBlock
+	return &result
+}
+
+func newRPCTransaction() interface{} {
+	var result *types.RPCTransaction
+	return &result
+}
+
+func newRPCTxReceipt() interface{} {
+	var result *types.RPCTxReceipt
+	return &result
+}
+
+func newRPCLogs() interface{} {
+	var result []*types.RPCLog
+	return &result
+}
+
+func TestDecodeRPCResult(t *testing.T) {
+	for _, test := range rpcResultTests {
+		raw, err := ioutil.ReadFile(filepath.Join("testdata", "rpcresult", test.fixture))
+		if err != nil {
+			t.Fatalf("read fixture %v failed: %v", test.fixture, err)
+		}
+		err = decodeRPCResult(raw, test.newResult())
+		if !test.wantErr {
+			if err != nil {
+				t.Errorf("fixture %v: unexpected error %v", test.fixture, err)
+			}
+			continue
+		}
+		if !errors.Is(err, errSuspectRPCResult) {
+			t.Errorf("fixture %v: want suspect rpc result error, have %v", test.fixture, err)
+			continue
+		}
+		if test.wantField == "" {
+			continue
+		}
+		var fieldErr *rpcFieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Field != test.wantField {
+			t.Errorf("fixture %v: want error of field '%v', have %v", test.fixture, test.wantField, err)
+		}
+	}
+}
+
+func TestDecodeNullRPCResult(t *testing.T) {
+	result := newRPCTxReceipt()
+	if err := decodeRPCResult([]byte("null"), result); err != nil {
+		t.Errorf("decode null result failed: %v", err)
+	}
+	if *(result.(**types.RPCTxReceipt)) != nil {
+		t.Errorf("decode null result should be nil")
+	}
+}
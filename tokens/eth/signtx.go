@@ -28,7 +28,12 @@ func (b *Bridge) verifyTransactionWithArgs(rawTx interface{}, args *tokens.Build
 		return nil, fmt.Errorf("[sign] verify tx with unknown pairID '%v'", args.PairID)
 	}
 	checkReceiver := tokenCfg.ContractAddress
-	if args.SwapType == tokens.SwapoutType && !tokenCfg.IsErc20() {
+	switch {
+	case args.Identifier == tokens.RefundIdentifier:
+		if b.IsSrc && !tokenCfg.IsErc20() {
+			checkReceiver = args.OriginFrom
+		}
+	case args.SwapType == tokens.SwapoutType && !tokenCfg.IsErc20():
 		checkReceiver = args.Bind
 	}
 	if !strings.EqualFold(tx.To().String(), checkReceiver) {
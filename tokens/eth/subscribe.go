@@ -0,0 +1,106 @@
+package eth
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsReconnectInterval = 10 * time.Second
+	wsReadTimeout       = 5 * time.Minute
+	getNewBlockRetry    = 3
+)
+
+// ensure Bridge impl tokens.BlockTxsSubscriber
+var _ tokens.BlockTxsSubscriber = &Bridge{}
+
+type newHeadsNotification struct {
+	Method string `json:"method"`
+	Params struct {
+		Result struct {
+			Hash *common.Hash `json:"hash"`
+		} `json:"result"`
+	} `json:"params"`
+}
+
+// SubscribeNewBlockTxs subscribe new blocks through websocket gateway and
+// call handler with height and tx hashes of each new block, reconnect if disconnected.
+func (b *Bridge) SubscribeNewBlockTxs(handler func(height uint64, txHashes []string)) bool {
+	wsAddress := b.GatewayConfig.WebsocketAddress
+	if wsAddress == "" {
+		return false
+	}
+	go func() {
+		for {
+			err := b.subscribeNewHeads(wsAddress, handler)
+			log.Warn("[subscribe] new heads subscription stopped", "isSrc", b.IsSrc, "err", err)
+			time.Sleep(wsReconnectInterval)
+		}
+	}()
+	return true
+}
+
+func (b *Bridge) subscribeNewHeads(wsAddress string, handler func(height uint64, txHashes []string)) error {
+	dialer := *websocket.DefaultDialer
+	dialer.Proxy = client.GetProxy
+	conn, _, err := dialer.Dial(wsAddress, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	req := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "eth_subscribe",
+		"params":  []string{"newHeads"},
+	}
+	if err = conn.WriteJSON(req); err != nil {
+		return err
+	}
+	log.Info("[subscribe] subscribe new heads", "isSrc", b.IsSrc, "url", wsAddress)
+
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		var notification newHeadsNotification
+		if err = json.Unmarshal(msg, &notification); err != nil {
+			log.Debug("[subscribe] unmarshal new heads message failed", "err", err)
+			continue
+		}
+		blockHash := notification.Params.Result.Hash
+		if notification.Method != "eth_subscription" || blockHash == nil {
+			continue // subscription id response
+		}
+		// blocks without txs are passed too, they add confirmations
+		if height, txHashes, ok := b.getNewBlockTxs(blockHash.Hex()); ok {
+			handler(height, txHashes)
+		}
+	}
+}
+
+// getNewBlockTxs get height and tx hashes of new block, http gateways may lag behind a little
+func (b *Bridge) getNewBlockTxs(blockHash string) (height uint64, txHashes []string, ok bool) {
+	for i := 0; i < getNewBlockRetry; i++ {
+		block, err := b.GetBlockByHash(blockHash)
+		if err == nil && block.Number != nil {
+			txHashes = make([]string, 0, len(block.Transactions))
+			for _, txHash := range block.Transactions {
+				txHashes = append(txHashes, txHash.Hex())
+			}
+			return block.Number.ToInt().Uint64(), txHashes, true
+		}
+		time.Sleep(time.Second)
+	}
+	log.Debug("[subscribe] get new block failed", "isSrc", b.IsSrc, "block", blockHash)
+	return 0, nil, false
+}
@@ -0,0 +1,76 @@
+package eth
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const testStatusTxHash = "0x9a3f1c2e4d5b6a79881726354453627180a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5"
+
+func newTxStatusTestServer(t *testing.T, receipt, tx string) *httptest.Server {
+	readFixture := func(fixture string) string {
+		if fixture == "" {
+			return "null"
+		}
+		data, err := ioutil.ReadFile(filepath.Join("testdata", "rpcresult", fixture))
+		if err != nil {
+			t.Fatalf("read fixture %v failed: %v", fixture, err)
+		}
+		return string(data)
+	}
+	results := map[string]string{
+		"eth_getTransactionReceipt": readFixture(receipt),
+		"eth_getTransactionByHash":  readFixture(tx),
+		"eth_blockNumber":           `"0xa1b2cd"`,
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + results[req.Method] + `}`))
+	}))
+}
+
+func TestGetTransactionStatus(t *testing.T) {
+	cases := []struct {
+		name          string
+		receipt, tx   string
+		wantErr       error
+		wantHeight    uint64
+		wantConfirmed uint64
+	}{
+		{name: "not found", wantErr: tokens.ErrTxNotFound},
+		{name: "pending", tx: "tx_valid.json"},
+		{name: "confirmed", receipt: "receipt_valid.json", tx: "tx_valid.json", wantHeight: 0xa1b2c3, wantConfirmed: 10},
+	}
+	for _, c := range cases {
+		server := newTxStatusTestServer(t, c.receipt, c.tx)
+		b := NewCrossChainBridge(true)
+		b.ChainConfig = &tokens.ChainConfig{}
+		b.GatewayConfig = &tokens.GatewayConfig{APIAddress: []string{server.URL}}
+
+		status, err := b.GetTransactionStatus(testStatusTxHash)
+		server.Close()
+		if c.wantErr != nil {
+			if !errors.Is(err, c.wantErr) || status != nil {
+				t.Errorf("%v: want error %v and nil status, have %v, %v", c.name, c.wantErr, err, status)
+			}
+			continue
+		}
+		if err != nil || status == nil {
+			t.Errorf("%v: want status, have error %v", c.name, err)
+			continue
+		}
+		if status.BlockHeight != c.wantHeight || status.Confirmations != c.wantConfirmed {
+			t.Errorf("%v: want height %v confirmations %v, have %v %v", c.name, c.wantHeight, c.wantConfirmed, status.BlockHeight, status.Confirmations)
+		}
+	}
+}
@@ -15,14 +15,14 @@ import (
 )
 
 // verifyErc20SwapinTx verify erc20 swapin with pairID
-func (b *Bridge) verifyErc20SwapinTx(swapInfo *tokens.TxSwapInfo, allowUnstable bool, token *tokens.TokenConfig, receipt *types.RPCTxReceipt) (*tokens.TxSwapInfo, error) {
+func (b *Bridge) verifyErc20SwapinTx(swapInfo *tokens.TxSwapInfo, allowUnstable bool, token *tokens.TokenConfig, receipt *types.RPCTxReceipt, trace *tokens.VerifyTrace) (*tokens.TxSwapInfo, error) {
 	err := b.verifyErc20SwapinTxReceipt(swapInfo, receipt, token)
-	if err != nil {
+	if err = trace.Check("depositLog", err); err != nil {
 		return swapInfo, err
 	}
 
 	err = b.checkSwapinInfo(swapInfo)
-	if err != nil {
+	if err = trace.Check("swapinInfo", err); err != nil {
 		return swapInfo, err
 	}
 
@@ -0,0 +1,167 @@
+package eth
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const (
+	defaultGasLimitTolerancePercent = 50
+	defaultGasPriceTolerancePercent = 50
+	defaultNonceWindow              = 100
+)
+
+// ethExtraBounds bounds of extra args an accepting node is willing to sign,
+// nil or zero max values are unbounded.
+type ethExtraBounds struct {
+	maxGas       uint64
+	maxGasPrice  *big.Int
+	maxGasTipCap *big.Int
+	maxGasFeeCap *big.Int
+	minNonce     uint64
+	maxNonce     uint64
+}
+
+func checkEthExtraBounds(extra *tokens.EthExtraArgs, bounds *ethExtraBounds) error {
+	if extra.Gas != nil && bounds.maxGas > 0 && *extra.Gas > bounds.maxGas {
+		return fmt.Errorf("%w: gas limit %v exceeds bound %v", tokens.ErrWrongExtraArgs, *extra.Gas, bounds.maxGas)
+	}
+	if extra.GasPrice != nil && bounds.maxGasPrice != nil && extra.GasPrice.Cmp(bounds.maxGasPrice) > 0 {
+		return fmt.Errorf("%w: gas price %v exceeds bound %v", tokens.ErrWrongExtraArgs, extra.GasPrice, bounds.maxGasPrice)
+	}
+	if extra.GasTipCap != nil && bounds.maxGasTipCap != nil && extra.GasTipCap.Cmp(bounds.maxGasTipCap) > 0 {
+		return fmt.Errorf("%w: gas tip cap %v exceeds bound %v", tokens.ErrWrongExtraArgs, extra.GasTipCap, bounds.maxGasTipCap)
+	}
+	if extra.GasFeeCap != nil && bounds.maxGasFeeCap != nil && extra.GasFeeCap.Cmp(bounds.maxGasFeeCap) > 0 {
+		return fmt.Errorf("%w: gas fee cap %v exceeds bound %v", tokens.ErrWrongExtraArgs, extra.GasFeeCap, bounds.maxGasFeeCap)
+	}
+	if extra.Nonce != nil && bounds.maxNonce > 0 && (*extra.Nonce < bounds.minNonce || *extra.Nonce > bounds.maxNonce) {
+		return fmt.Errorf("%w: nonce %v out of window [%v, %v]", tokens.ErrWrongExtraArgs, *extra.Nonce, bounds.minNonce, bounds.maxNonce)
+	}
+	return nil
+}
+
+// VerifyExtraArgs verify extra args (gas, gas price, nonce) from the initiator
+// are within bounds of this node. args should have been built (input is set).
+func (b *Bridge) VerifyExtraArgs(args *tokens.BuildTxArgs) error {
+	if args.Extra == nil || args.Extra.EthExtra == nil {
+		return nil
+	}
+	bounds, err := b.getEthExtraBounds(args)
+	if err != nil {
+		return err
+	}
+	return checkEthExtraBounds(args.Extra.EthExtra, bounds)
+}
+
+func (b *Bridge) getEthExtraBounds(args *tokens.BuildTxArgs) (bounds *ethExtraBounds, err error) {
+	extra := args.Extra.EthExtra
+	bounds = &ethExtraBounds{}
+	if extra.Gas != nil {
+		bounds.maxGas, err = b.getGasLimitBound(args)
+		if err != nil {
+			return nil, err
+		}
+	}
+	gasPriceTolerance := b.ChainConfig.GasPriceTolerancePercent
+	if gasPriceTolerance == 0 {
+		gasPriceTolerance = defaultGasPriceTolerancePercent
+	}
+	if extra.GasPrice != nil {
+		gasPrice, errf := b.getGasPriceBound(args)
+		if errf != nil {
+			return nil, errf
+		}
+		bounds.maxGasPrice = capBigInt(addPercent(gasPrice, gasPriceTolerance), b.ChainConfig.GetMaxGasPrice())
+	}
+	if extra.GasTipCap != nil || extra.GasFeeCap != nil {
+		gasTipCap, errf := b.getGasTipCap(args)
+		if errf != nil {
+			return nil, fmt.Errorf("%w: suggest gas tip cap failed, %v", tokens.ErrRPCQueryError, errf)
+		}
+		gasFeeCap, errf := b.getGasFeeCap(args, gasTipCap)
+		if errf != nil {
+			return nil, fmt.Errorf("%w: get base fee failed, %v", tokens.ErrRPCQueryError, errf)
+		}
+		bounds.maxGasTipCap = capBigInt(addPercent(gasTipCap, gasPriceTolerance), b.ChainConfig.GetMaxGasTipCap())
+		bounds.maxGasFeeCap = capBigInt(addPercent(gasFeeCap, gasPriceTolerance), b.ChainConfig.GetMaxGasFeeCap())
+	}
+	if extra.Nonce != nil {
+		pendingNonce, errf := b.GetPoolNonce(args.From, "pending")
+		if errf != nil {
+			return nil, fmt.Errorf("%w: get pool nonce failed, %v", tokens.ErrRPCQueryError, errf)
+		}
+		window := b.ChainConfig.NonceWindow
+		if window == 0 {
+			window = defaultNonceWindow
+		}
+		if pendingNonce > window {
+			bounds.minNonce = pendingNonce - window
+		}
+		bounds.maxNonce = pendingNonce + window
+	}
+	return bounds, nil
+}
+
+// getGasLimitBound mirror `setDefaults` with tolerance, capped by configed max gas limit
+func (b *Bridge) getGasLimitBound(args *tokens.BuildTxArgs) (uint64, error) {
+	var input []byte
+	if args.Input != nil {
+		input = *args.Input
+	}
+	esGasLimit, err := b.EstimateGas(args.From, args.To, args.Value, input)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", tokens.ErrEstimateGasFailed, err)
+	}
+	esGasLimit += esGasLimit * 30 / 100
+	defGasLimit := b.getDefaultGasLimit(args.PairID)
+	if esGasLimit < defGasLimit {
+		esGasLimit = defGasLimit
+	}
+	tolerance := b.ChainConfig.GasLimitTolerancePercent
+	if tolerance == 0 {
+		tolerance = defaultGasLimitTolerancePercent
+	}
+	maxGas := esGasLimit + esGasLimit*tolerance/100
+	if b.ChainConfig.MaxGasLimit > 0 && maxGas > b.ChainConfig.MaxGasLimit {
+		maxGas = b.ChainConfig.MaxGasLimit
+	}
+	return maxGas, nil
+}
+
+// getGasPriceBound mirror `getGasPrice` without changing the cached latest gas price
+func (b *Bridge) getGasPriceBound(args *tokens.BuildTxArgs) (price *big.Int, err error) {
+	price = b.ChainConfig.GetFixedGasPrice()
+	if price == nil {
+		price, err = b.SuggestPrice()
+		if err != nil {
+			return nil, fmt.Errorf("%w: suggest gas price failed, %v", tokens.ErrRPCQueryError, err)
+		}
+		minGasPrice := b.ChainConfig.GetMinGasPrice()
+		if minGasPrice != nil && price.Cmp(minGasPrice) < 0 {
+			price = minGasPrice
+		}
+	}
+	plusPercent := b.ChainConfig.ReplacePlusGasPricePercent * args.GetReplaceNum()
+	if tokenCfg := b.GetTokenConfig(args.PairID); tokenCfg != nil && !b.ChainConfig.IsFixedGasPrice() {
+		plusPercent += tokenCfg.PlusGasPricePercentage
+	}
+	if plusPercent > tokens.MaxPlusGasPricePercentage {
+		plusPercent = tokens.MaxPlusGasPricePercentage
+	}
+	return addPercent(price, plusPercent), nil
+}
+
+func addPercent(value *big.Int, percent uint64) *big.Int {
+	result := new(big.Int).Mul(value, new(big.Int).SetUint64(100+percent))
+	return result.Div(result, big.NewInt(100))
+}
+
+func capBigInt(value, maxValue *big.Int) *big.Int {
+	if maxValue != nil && value.Cmp(maxValue) > 0 {
+		return maxValue
+	}
+	return value
+}
@@ -0,0 +1,53 @@
+package eth
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func TestCheckEthExtraBounds(t *testing.T) {
+	bounds := &ethExtraBounds{
+		maxGas:       150000,
+		maxGasPrice:  big.NewInt(30e9),
+		maxGasTipCap: big.NewInt(3e9),
+		maxGasFeeCap: big.NewInt(60e9),
+		minNonce:     10,
+		maxNonce:     210,
+	}
+	newExtra := func() *tokens.EthExtraArgs {
+		gas, nonce := uint64(100000), uint64(110)
+		return &tokens.EthExtraArgs{
+			Gas:       &gas,
+			GasPrice:  big.NewInt(20e9),
+			GasTipCap: big.NewInt(2e9),
+			GasFeeCap: big.NewInt(50e9),
+			Nonce:     &nonce,
+		}
+	}
+	if err := checkEthExtraBounds(newExtra(), bounds); err != nil {
+		t.Fatalf("extra within bounds should pass, err=%v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(extra *tokens.EthExtraArgs)
+	}{
+		{"gas limit", func(extra *tokens.EthExtraArgs) { *extra.Gas = 150001 }},
+		{"gas price", func(extra *tokens.EthExtraArgs) { extra.GasPrice = big.NewInt(31e9) }},
+		{"gas tip cap", func(extra *tokens.EthExtraArgs) { extra.GasTipCap = big.NewInt(4e9) }},
+		{"gas fee cap", func(extra *tokens.EthExtraArgs) { extra.GasFeeCap = big.NewInt(61e9) }},
+		{"nonce too low", func(extra *tokens.EthExtraArgs) { *extra.Nonce = 9 }},
+		{"nonce too high", func(extra *tokens.EthExtraArgs) { *extra.Nonce = 211 }},
+	}
+	for _, test := range tests {
+		extra := newExtra()
+		test.tamper(extra)
+		err := checkEthExtraBounds(extra, bounds)
+		if !errors.Is(err, tokens.ErrWrongExtraArgs) {
+			t.Errorf("tampered %v should fail with wrong extra args, err=%v", test.name, err)
+		}
+	}
+}
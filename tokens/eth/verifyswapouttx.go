@@ -14,14 +14,14 @@ import (
 )
 
 // verifySwapoutTxWithPairID verify swapout with PairID
-func (b *Bridge) verifySwapoutTx(swapInfo *tokens.TxSwapInfo, allowUnstable bool, token *tokens.TokenConfig, receipt *types.RPCTxReceipt) (*tokens.TxSwapInfo, error) {
+func (b *Bridge) verifySwapoutTx(swapInfo *tokens.TxSwapInfo, allowUnstable bool, token *tokens.TokenConfig, receipt *types.RPCTxReceipt, trace *tokens.VerifyTrace) (*tokens.TxSwapInfo, error) {
 	err := b.verifySwapoutTxReceipt(swapInfo, receipt, token)
-	if err != nil {
+	if err = trace.Check("swapoutLog", err); err != nil {
 		return swapInfo, err
 	}
 
 	err = b.checkSwapoutInfo(swapInfo)
-	if err != nil {
+	if err = trace.Check("swapoutInfo", err); err != nil {
 		return swapInfo, err
 	}
 
@@ -79,7 +79,7 @@ func (b *Bridge) checkSwapoutInfo(swapInfo *tokens.TxSwapInfo) error {
 		log.Debug("wrong bind address in swapout", "bind", swapInfo.Bind)
 		return tokens.ErrTxWithWrongMemo
 	}
-	return nil
+	return tokens.CheckSwapoutDust(swapInfo)
 }
 
 func parseSwapoutTxLogs(logs []*types.RPCLog, targetContract string) (bind string, value *big.Int, err error) {
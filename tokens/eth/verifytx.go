@@ -1,6 +1,8 @@
 package eth
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -16,6 +18,9 @@ func (b *Bridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
 	txr, url, err := b.GetTransactionReceipt(txHash)
 	if err != nil {
 		log.Trace("GetTransactionReceipt fail", "hash", txHash, "err", err)
+		if errors.Is(err, tokens.ErrNotFound) {
+			return b.getPendingTxStatus(txHash)
+		}
 		return nil, err
 	}
 
@@ -36,9 +41,22 @@ func (b *Bridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
 			time.Sleep(1 * time.Second)
 		}
 	}
+	b.setTxFinalized(txStatus)
 	return txStatus, nil
 }
 
+// getPendingTxStatus get status of tx without receipt, it's pending if tx exists
+func (b *Bridge) getPendingTxStatus(txHash string) (*tokens.TxStatus, error) {
+	_, err := b.GetTransaction(txHash)
+	if err == nil {
+		return &tokens.TxStatus{}, nil
+	}
+	if errors.Is(err, tokens.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %v", tokens.ErrTxNotFound, txHash)
+	}
+	return nil, err
+}
+
 // VerifyMsgHash verify msg hash
 func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHashes []string) error {
 	tx, ok := rawTx.(*types.Transaction)
@@ -61,15 +79,20 @@ func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHashes []string) error {
 
 func getTxByHash(b *Bridge, txHash string, withExt bool) (*types.RPCTransaction, error) {
 	gateway := b.GatewayConfig
-	tx, err := b.getTransactionByHash(txHash, gateway.APIAddress)
-	if err != nil && withExt && len(gateway.APIAddressExt) > 0 {
-		tx, err = b.getTransactionByHash(txHash, gateway.APIAddressExt)
+	tx, err := b.getTransactionByHash(txHash, gateway.GetAPIAddress())
+	if err != nil && withExt && len(gateway.GetAPIAddressExt()) > 0 {
+		tx, err = b.getTransactionByHash(txHash, gateway.GetAPIAddressExt())
 	}
 	return tx, err
 }
 
 // VerifyTransaction impl
 func (b *Bridge) VerifyTransaction(pairID, txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+	return b.VerifyTransactionWithTrace(pairID, txHash, allowUnstable, nil)
+}
+
+// VerifyTransactionWithTrace impl
+func (b *Bridge) VerifyTransactionWithTrace(pairID, txHash string, allowUnstable bool, trace *tokens.VerifyTrace) (*tokens.TxSwapInfo, error) {
 	swapInfo := &tokens.TxSwapInfo{}
 	swapInfo.PairID = pairID                // PairID
 	swapInfo.Hash = strings.ToLower(txHash) // Hash
@@ -77,45 +100,49 @@ func (b *Bridge) VerifyTransaction(pairID, txHash string, allowUnstable bool) (*
 	token := b.GetTokenConfig(pairID)
 
 	if token == nil {
-		return swapInfo, tokens.ErrUnknownPairID
+		return swapInfo, trace.Check("pairID", tokens.ErrUnknownPairID)
 	}
+	trace.Pass("pairID")
 
 	if token.DisableSwap {
-		return swapInfo, tokens.ErrSwapIsClosed
+		return swapInfo, trace.Check("swapEnabled", tokens.ErrSwapIsClosed)
 	}
+	trace.Pass("swapEnabled")
 
 	receipt, err := b.getReceipt(swapInfo, allowUnstable)
-	if err != nil {
+	if err = trace.Check("receipt", err); err != nil {
 		return swapInfo, err
 	}
 
 	if !b.IsSrc {
-		return b.verifySwapoutTx(swapInfo, allowUnstable, token, receipt)
+		return b.verifySwapoutTx(swapInfo, allowUnstable, token, receipt, trace)
 	}
 
 	if token.IsErc20() {
-		return b.verifyErc20SwapinTx(swapInfo, allowUnstable, token, receipt)
+		return b.verifyErc20SwapinTx(swapInfo, allowUnstable, token, receipt, trace)
 	}
 
 	tx, err := getTxByHash(b, swapInfo.Hash, !allowUnstable)
 	if err != nil {
 		log.Debug("[verifyNativeSwapin] "+b.ChainConfig.BlockChain+" Bridge::GetTransaction fail", "tx", swapInfo.Hash, "err", err)
-		return swapInfo, tokens.ErrTxNotFound
+		return swapInfo, trace.Check("transaction", tokens.ErrTxNotFound)
 	}
-	return b.verifyNativeSwapinTx(swapInfo, allowUnstable, token, tx)
+	trace.Pass("transaction")
+	return b.verifyNativeSwapinTx(swapInfo, allowUnstable, token, tx, trace)
 }
 
-func (b *Bridge) verifyNativeSwapinTx(swapInfo *tokens.TxSwapInfo, allowUnstable bool, token *tokens.TokenConfig, tx *types.RPCTransaction) (*tokens.TxSwapInfo, error) {
+func (b *Bridge) verifyNativeSwapinTx(swapInfo *tokens.TxSwapInfo, allowUnstable bool, token *tokens.TokenConfig, tx *types.RPCTransaction, trace *tokens.VerifyTrace) (*tokens.TxSwapInfo, error) {
 	if tx.Recipient == nil { // ignore contract creation tx
-		return swapInfo, tokens.ErrTxWithWrongReceiver
+		return swapInfo, trace.Check("receiver", tokens.ErrTxWithWrongReceiver)
 	}
 
 	txRecipient := strings.ToLower(tx.Recipient.String())
 	if !common.IsEqualIgnoreCase(txRecipient, token.DepositAddress) {
-		return swapInfo, tokens.ErrTxWithWrongReceiver
+		return swapInfo, trace.Check("receiver", tokens.ErrTxWithWrongReceiver)
 	}
+	trace.Pass("receiver")
 	if *tx.From == (common.Address{}) {
-		return nil, tokens.ErrTxWithWrongSender
+		return nil, trace.Check("sender", tokens.ErrTxWithWrongSender)
 	}
 
 	swapInfo.TxTo = txRecipient                       // TxTo
@@ -125,7 +152,7 @@ func (b *Bridge) verifyNativeSwapinTx(swapInfo *tokens.TxSwapInfo, allowUnstable
 	swapInfo.Value = tx.Amount.ToInt()                // Value
 
 	err := b.checkSwapinInfo(swapInfo)
-	if err != nil {
+	if err = trace.Check("swapinInfo", err); err != nil {
 		return swapInfo, err
 	}
 
@@ -149,6 +176,9 @@ func (b *Bridge) getReceipt(swapInfo *tokens.TxSwapInfo, allowUnstable bool) (*t
 		return nil, err
 	}
 	swapInfo.Height = receipt.BlockNumber.ToInt().Uint64() // Height
+	if receipt.BlockHash != nil {
+		swapInfo.BlockHash = receipt.BlockHash.String() // BlockHash
+	}
 	if !receipt.IsStatusOk() {
 		return nil, tokens.ErrTxWithWrongReceipt
 	}
@@ -167,6 +197,7 @@ func (b *Bridge) getStableReceipt(swapInfo *tokens.TxSwapInfo) (*types.RPCTxRece
 		return nil, tokens.ErrTxNotFound
 	}
 	swapInfo.Height = txStatus.BlockHeight  // Height
+	swapInfo.BlockHash = txStatus.BlockHash // BlockHash
 	swapInfo.Timestamp = txStatus.BlockTime // Timestamp
 	if txStatus.BlockHeight < *b.ChainConfig.InitialHeight {
 		log.Warn("transaction before initial block height",
@@ -174,7 +205,7 @@ func (b *Bridge) getStableReceipt(swapInfo *tokens.TxSwapInfo) (*types.RPCTxRece
 			"blockHeight", txStatus.BlockHeight)
 		return nil, tokens.ErrTxBeforeInitialHeight
 	}
-	if txStatus.Confirmations < *b.GetChainConfig().Confirmations {
+	if !tokens.IsTxStatusStable(txStatus, swapInfo.PairID, b.IsSrc) {
 		return nil, tokens.ErrTxNotStable
 	}
 	receipt, ok := txStatus.Receipt.(*types.RPCTxReceipt)
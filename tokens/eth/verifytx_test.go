@@ -366,15 +366,15 @@ func verifyTestTx(test *verifyTxTest) (err error) {
 	case swapoutType:
 		ExtCodeParts = mETHExtCodeParts
 		params.GetExtraConfig().IsSwapoutToStringAddress = false
-		_, err = br.verifySwapoutTx(swapInfo, allowUnstable, test.token, test.receipt)
+		_, err = br.verifySwapoutTx(swapInfo, allowUnstable, test.token, test.receipt, nil)
 	case swapout2Type:
 		ExtCodeParts = mBTCExtCodeParts
 		params.GetExtraConfig().IsSwapoutToStringAddress = true
-		_, err = br.verifySwapoutTx(swapInfo, allowUnstable, test.token, test.receipt)
+		_, err = br.verifySwapoutTx(swapInfo, allowUnstable, test.token, test.receipt, nil)
 	case swapinType:
-		_, err = br.verifyErc20SwapinTx(swapInfo, allowUnstable, test.token, test.receipt)
+		_, err = br.verifyErc20SwapinTx(swapInfo, allowUnstable, test.token, test.receipt, nil)
 	case nativeType:
-		_, err = br.verifyNativeSwapinTx(swapInfo, allowUnstable, test.token, test.tx)
+		_, err = br.verifyNativeSwapinTx(swapInfo, allowUnstable, test.token, test.tx, nil)
 	default:
 		err = fmt.Errorf("verifyTestTx: unknown swap type '%v'", test.txtype)
 	}
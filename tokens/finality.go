@@ -0,0 +1,56 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+)
+
+// finality strategies of chain
+const (
+	FinalityConfirmations = "confirmations"
+	FinalitySafe          = "safe"
+	FinalityFinalized     = "finalized"
+	FinalityCheckpoint    = "checkpoint"
+
+	defaultCheckpointMethod = "getLastChildBlock()"
+)
+
+// GetFinality get finality strategy, default to confirmations
+func (c *ChainConfig) GetFinality() string {
+	if c.Finality == "" {
+		return FinalityConfirmations
+	}
+	return c.Finality
+}
+
+// GetCheckpointMethod get checkpoint contract method which returns last checkpointed block number
+func (c *ChainConfig) GetCheckpointMethod() string {
+	if c.CheckpointMethod == "" {
+		return defaultCheckpointMethod
+	}
+	return c.CheckpointMethod
+}
+
+func (c *ChainConfig) checkFinalityConfig() error {
+	switch c.GetFinality() {
+	case FinalityConfirmations, FinalitySafe, FinalityFinalized:
+	case FinalityCheckpoint:
+		if c.CheckpointContract == "" || len(c.CheckpointAPIAddress) == 0 {
+			return errors.New("checkpoint finality must config 'CheckpointContract' and 'CheckpointAPIAddress'")
+		}
+	default:
+		return fmt.Errorf("unknown finality '%v'", c.Finality)
+	}
+	return nil
+}
+
+// IsTxStatusStable is tx final by the finality strategy of its chain,
+// which is shared by verify, accept and stable stages to agree on.
+// confirmations of pair are always required, and if the strategy is
+// applied (Finalized is not nil) the tx must be finalized as well.
+func IsTxStatusStable(txStatus *TxStatus, pairID string, isSrc bool) bool {
+	if txStatus.Finalized != nil && !*txStatus.Finalized {
+		return false
+	}
+	return txStatus.Confirmations >= GetPairStableConfirmations(pairID, isSrc)
+}
@@ -0,0 +1,62 @@
+package tokens
+
+import (
+	"testing"
+)
+
+func TestIsTxStatusStable(t *testing.T) {
+	oldPairsConfig := tokenPairsConfig
+	oldSrc := SrcStableConfirmations
+	defer func() {
+		tokenPairsConfig = oldPairsConfig
+		SrcStableConfirmations = oldSrc
+	}()
+
+	SrcStableConfirmations = 10
+	pairConfirmations := uint64(20)
+	tokenPairsConfig = map[string]*TokenPairConfig{
+		"override": {PairID: "override", SrcStableConfirmations: &pairConfirmations},
+	}
+
+	finalized, notFinalized := true, false
+	cases := []struct {
+		pairID   string
+		txStatus *TxStatus
+		want     bool
+	}{
+		{"pair", &TxStatus{Confirmations: 9}, false},
+		{"pair", &TxStatus{Confirmations: 10}, true},
+		{"pair", &TxStatus{Confirmations: 10, Finalized: &finalized}, true},
+		{"pair", &TxStatus{Confirmations: 1, Finalized: &finalized}, false},
+		{"pair", &TxStatus{Confirmations: 100, Finalized: &notFinalized}, false},
+		// per pair confirmations are required even if finalized
+		{"override", &TxStatus{Confirmations: 10}, false},
+		{"override", &TxStatus{Confirmations: 10, Finalized: &finalized}, false},
+		{"override", &TxStatus{Confirmations: 20, Finalized: &finalized}, true},
+		{"override", &TxStatus{Confirmations: 20, Finalized: &notFinalized}, false},
+	}
+	for i, c := range cases {
+		if have := IsTxStatusStable(c.txStatus, c.pairID, true); have != c.want {
+			t.Errorf("case %v: have %v want %v", i, have, c.want)
+		}
+	}
+}
+
+func TestCheckFinalityConfig(t *testing.T) {
+	cases := []struct {
+		cfg   ChainConfig
+		valid bool
+	}{
+		{ChainConfig{}, true},
+		{ChainConfig{Finality: FinalityFinalized}, true},
+		{ChainConfig{Finality: FinalitySafe}, true},
+		{ChainConfig{Finality: FinalityCheckpoint}, false},
+		{ChainConfig{Finality: FinalityCheckpoint, CheckpointContract: "0x1", CheckpointAPIAddress: []string{"http://127.0.0.1:8545"}}, true},
+		{ChainConfig{Finality: "latest"}, false},
+	}
+	for i, c := range cases {
+		if err := c.cfg.checkFinalityConfig(); (err == nil) != c.valid {
+			t.Errorf("case %v: valid %v, err %v", i, c.valid, err)
+		}
+	}
+}
@@ -16,6 +16,11 @@ type Bridge struct {
 	*eth.Bridge
 }
 
+func init() {
+	newBridge := func(isSrc bool) tokens.CrossChainBridge { return NewCrossChainBridge(isSrc) }
+	tokens.RegisterBridgeConstructor("FUSION", newBridge)
+}
+
 // NewCrossChainBridge new fsn bridge
 func NewCrossChainBridge(isSrc bool) *Bridge {
 	bridge := &Bridge{Bridge: eth.NewCrossChainBridge(isSrc)}
@@ -50,7 +55,7 @@ func (b *Bridge) VerifyChainID() {
 			break
 		}
 		log.Errorf("can not get gateway chainID. %v", err)
-		log.Println("retry query gateway", b.GatewayConfig.APIAddress)
+		log.Println("retry query gateway", b.GatewayConfig.GetAPIAddress())
 		time.Sleep(3 * time.Second)
 	}
 
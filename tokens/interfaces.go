@@ -1,6 +1,8 @@
 // Package tokens defines the common interfaces and supported bridges in sub directories.
 package tokens
 
+import "math/big"
+
 // CrossChainBridge interface
 type CrossChainBridge interface {
 	IsSrcEndpoint() bool
@@ -17,6 +19,8 @@ type CrossChainBridge interface {
 	InitAfterConfig()
 
 	GetTransaction(txHash string) (interface{}, error)
+	// GetTransactionStatus returns non nil status if and only if error is nil,
+	// error wraps ErrTxNotFound if tx is unknown, block height is 0 if tx is pending.
 	GetTransactionStatus(txHash string) (*TxStatus, error)
 	VerifyTransaction(pairID, txHash string, allowUnstable bool) (*TxSwapInfo, error)
 	VerifyMsgHash(rawTx interface{}, msgHash []string) error
@@ -39,7 +43,72 @@ type NonceSetter interface {
 	InitNonces(nonces map[string]uint64)
 }
 
+// AddressDeriver derive address from public key interface
+type AddressDeriver interface {
+	PublicKeyToAddress(pubKeyHex string) (string, error)
+}
+
+// ExtraArgsVerifier verify extra args from initiator in accepting sign interface
+type ExtraArgsVerifier interface {
+	VerifyExtraArgs(args *BuildTxArgs) error
+}
+
+// RefundBuilder build tx refunding deposit on the deposit chain interface
+type RefundBuilder interface {
+	BuildRefundTransaction(args *BuildTxArgs) (rawTx interface{}, err error)
+}
+
+// TraceVerifier verify transaction and record check trace interface
+type TraceVerifier interface {
+	VerifyTransactionWithTrace(pairID, txHash string, allowUnstable bool, trace *VerifyTrace) (*TxSwapInfo, error)
+}
+
+// DustThresholder get dust threshold of output paying to address interface (eg. btc-like)
+type DustThresholder interface {
+	GetDustThreshold(address string) (uint64, error)
+}
+
+// BalanceGetter get native balance of account interface
+type BalanceGetter interface {
+	GetBalance(account string) (*big.Int, error)
+}
+
+// MintCapGetter get mint cap of token contract by view method interface
+type MintCapGetter interface {
+	GetMintCap(contract, method string) (*big.Int, error)
+}
+
+// BlockHashGetter get canonical block hash of height interface
+type BlockHashGetter interface {
+	GetBlockHash(height uint64) (string, error)
+}
+
+// BlockTxsSubscriber subscribe txs of new blocks interface,
+// returns false if subscribing is not supported (eg. no websocket gateway)
+type BlockTxsSubscriber interface {
+	SubscribeNewBlockTxs(handler func(height uint64, txHashes []string)) bool
+}
+
 // ForkChecker fork checker interface
 type ForkChecker interface {
 	GetBlockHashOf(urls []string, height uint64) (hash string, err error)
 }
+
+// P2shAddressProvider derive p2sh deposit address of bind address and verify deposits to it interface (eg. btc-like)
+type P2shAddressProvider interface {
+	CrossChainBridge
+
+	GetP2shPairID() string
+	GetP2shAddress(bindAddr string) (p2shAddress string, redeemScript []byte, err error)
+	VerifyP2shTransaction(pairID, txHash, bindAddress string, allowUnstable bool) (*TxSwapInfo, error)
+}
+
+// ContractChecker check whether address is contract interface (eg. eth-like)
+type ContractChecker interface {
+	IsContractAddress(address string) (bool, error)
+}
+
+// AddressValidator validate address and report reasons if invalid interface
+type AddressValidator interface {
+	ValidateAddress(address string) (normalized string, reasons []string)
+}
@@ -46,9 +46,9 @@ type KsmHeader struct {
 // KsmGetHeader call chain_getHeader
 func (b *Bridge) KsmGetHeader(blockHash string) (result *KsmHeader, err error) {
 	gateway := b.GatewayConfig
-	result, err = b.ksmGetHeader(blockHash, gateway.APIAddress)
-	if err != nil && len(gateway.APIAddressExt) > 0 {
-		result, err = b.ksmGetHeader(blockHash, gateway.APIAddressExt)
+	result, err = b.ksmGetHeader(blockHash, gateway.GetAPIAddress())
+	if err != nil && len(gateway.GetAPIAddressExt()) > 0 {
+		result, err = b.ksmGetHeader(blockHash, gateway.GetAPIAddressExt())
 	}
 	return result, err
 }
@@ -6,6 +6,7 @@ import (
 	"os/signal"
 	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/common"
 	"github.com/anyswap/CrossChain-Bridge/common/hexutil"
@@ -60,6 +61,9 @@ func (c *TokenPairConfig) loadTokenPrice() (err error) {
 
 	c.SrcToken.TokenPrice = srcTokenPrice
 	c.DestToken.TokenPrice = dstTokenPrice
+	now := time.Now().Unix()
+	c.SrcToken.tokenPriceTime = now
+	c.DestToken.tokenPriceTime = now
 
 	log.Info("load token pair price success", "pairID", c.PairID,
 		"srcTokenAddress", srcTokenAddress, "dstTokenAddress", dstTokenAddress,
@@ -101,7 +105,7 @@ func initAllTokenPrices() {
 	if TokenPriceCfg == nil {
 		return
 	}
-	for _, pairCfg := range GetTokenPairsConfig() {
+	for _, pairCfg := range GetSortedTokenPairsConfig() {
 		err := pairCfg.loadTokenPrice()
 		if err != nil {
 			log.Fatal("init token price failed", "pairID", pairCfg.PairID, "err", err)
@@ -120,11 +124,7 @@ func reloadTokenPrices(pairIDs []string) {
 	}
 	var pairCfgs []*TokenPairConfig
 	if len(pairIDs) == 0 {
-		allPairsCfg := GetTokenPairsConfig()
-		pairCfgs = make([]*TokenPairConfig, 0, len(allPairsCfg))
-		for _, pairCfg := range allPairsCfg {
-			pairCfgs = append(pairCfgs, pairCfg)
-		}
+		pairCfgs = GetSortedTokenPairsConfig()
 	} else {
 		pairCfgs = make([]*TokenPairConfig, 0, len(pairIDs))
 		for _, pairID := range pairIDs {
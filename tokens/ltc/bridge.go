@@ -17,6 +17,12 @@ const (
 	netCustom   = "custom"
 )
 
+// ensure Bridge impl tokens.CrossChainBridge
+var _ tokens.CrossChainBridge = &Bridge{}
+
+// ensure Bridge impl tokens.AddressValidator
+var _ tokens.AddressValidator = &Bridge{}
+
 // PairID unique ltc pair ID
 var PairID = "ltc"
 
@@ -27,6 +33,11 @@ type Bridge struct {
 
 var instance *Bridge
 
+func init() {
+	newBridge := func(isSrc bool) tokens.CrossChainBridge { return NewCrossChainBridge(isSrc) }
+	tokens.RegisterBridgeConstructor("LITECOIN", newBridge)
+}
+
 // NewCrossChainBridge new ltc bridge
 func NewCrossChainBridge(isSrc bool) *Bridge {
 	tokens.IsSwapoutToStringAddress = true
@@ -36,6 +47,7 @@ func NewCrossChainBridge(isSrc bool) *Bridge {
 	btc.PairID = PairID
 	instance = &Bridge{tokens.NewCrossChainBridgeBase(isSrc)}
 	btc.BridgeInstance = instance
+	tokens.SetP2shAddressProvider(instance)
 	return instance
 }
 
@@ -87,7 +99,7 @@ func (b *Bridge) InitLatestBlockNumber() {
 			break
 		}
 		log.Error("get latst block number failed.", "BlockChain", chainCfg.BlockChain, "NetID", chainCfg.NetID, "err", err)
-		log.Println("retry query gateway", gatewayCfg.APIAddress)
+		log.Println("retry query gateway", gatewayCfg.GetAPIAddress())
 		time.Sleep(3 * time.Second)
 	}
 }
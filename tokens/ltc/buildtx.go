@@ -47,6 +47,18 @@ func (b *Bridge) getRelayFeePerKb() (estimateFee int64, err error) {
 	return estimateFee, nil
 }
 
+// checkPayoutDust do not build tx with dust payout output which the network rejects
+func (b *Bridge) checkPayoutDust(to string, amount *big.Int) error {
+	threshold, err := b.GetDustThreshold(to)
+	if err != nil {
+		return err
+	}
+	if amount.Cmp(new(big.Int).SetUint64(threshold)) < 0 {
+		return fmt.Errorf("%w: amount %v, dust threshold %v", tokens.ErrSwappedValueIsDust, amount, threshold)
+	}
+	return nil
+}
+
 // BuildRawTransaction build raw tx
 func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
 	var (
@@ -104,6 +116,18 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		relayFeePerKb = ltcAmountType(relayFee)
 	}
 
+	// check against swap value recorded in args (eg. by the sign requester)
+	err = tokens.CheckAmount(amount, tokens.Int64AmountSpec.WithDecimals(*token.Decimals), args.SwapValue)
+	if err != nil {
+		return nil, err
+	}
+	args.SwapValue = amount // swap value
+
+	err = b.checkPayoutDust(to, amount)
+	if err != nil {
+		return nil, err
+	}
+
 	txOuts, err := b.getTxOutputs(to, amount, memo)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,117 @@
+package tokens
+
+import (
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+const mintCapRefreshInterval = 600 // seconds
+
+var (
+	mintCaps     = make(map[string]*big.Int) // key is lower case pairID and side
+	mintCapsLock sync.RWMutex
+
+	// failed cap queries are logged once until succeed again
+	failedMintCapQueries = make(map[string]struct{})
+
+	mintCapStarter sync.Once
+)
+
+func getMintCapKey(pairID string, isSrc bool) string {
+	if isSrc {
+		return strings.ToLower(pairID) + ":src"
+	}
+	return strings.ToLower(pairID) + ":dst"
+}
+
+// InitMintCaps query on-chain mint caps of token contracts with 'MintCapMethods'
+// and refresh them periodically
+func InitMintCaps() {
+	RefreshMintCaps()
+	mintCapStarter.Do(func() {
+		go func() {
+			for {
+				time.Sleep(mintCapRefreshInterval * time.Second)
+				RefreshMintCaps()
+			}
+		}()
+	})
+}
+
+// RefreshMintCaps query on-chain mint caps, the tightest cap of all methods is used.
+// caps are dropped if query failed, then only the static 'MaximumSwap' applies.
+func RefreshMintCaps() {
+	caps := make(map[string]*big.Int)
+	for _, pairCfg := range GetTokenPairsConfig() {
+		for _, isSrc := range []bool{true, false} {
+			token := pairCfg.SrcToken
+			if !isSrc {
+				token = pairCfg.DestToken
+			}
+			if token == nil || len(token.MintCapMethods) == 0 {
+				continue
+			}
+			if mintCap := queryMintCap(pairCfg.PairID, token, isSrc); mintCap != nil {
+				caps[getMintCapKey(pairCfg.PairID, isSrc)] = mintCap
+			}
+		}
+	}
+	mintCapsLock.Lock()
+	mintCaps = caps
+	mintCapsLock.Unlock()
+}
+
+func queryMintCap(pairID string, token *TokenConfig, isSrc bool) (mintCap *big.Int) {
+	getter, ok := GetCrossChainBridge(isSrc).(MintCapGetter)
+	if !ok {
+		return nil
+	}
+	for _, method := range token.MintCapMethods {
+		failedKey := getMintCapKey(pairID, isSrc) + ":" + method
+		value, err := getter.GetMintCap(token.ContractAddress, method)
+		if err != nil {
+			mintCapsLock.Lock()
+			if _, logged := failedMintCapQueries[failedKey]; !logged {
+				failedMintCapQueries[failedKey] = struct{}{}
+				log.Warn("query mint cap failed, use static config", "pairID", pairID, "isSrc", isSrc, "contract", token.ContractAddress, "method", method, "err", err)
+			}
+			mintCapsLock.Unlock()
+			return nil
+		}
+		mintCapsLock.Lock()
+		delete(failedMintCapQueries, failedKey)
+		mintCapsLock.Unlock()
+		if mintCap == nil || value.Cmp(mintCap) < 0 {
+			mintCap = value
+		}
+	}
+	log.Debug("query mint cap success", "pairID", pairID, "isSrc", isSrc, "mintCap", mintCap)
+	return mintCap
+}
+
+func getMintCap(pairID string, isSrc bool) *big.Int {
+	mintCapsLock.RLock()
+	defer mintCapsLock.RUnlock()
+	return mintCaps[getMintCapKey(pairID, isSrc)]
+}
+
+// getMintCapOfDeposit get mint cap of the counterpart token in deposit token unit,
+// nil if there is no mint cap. if the counterpart token splits payout, it's the
+// cap of paying in 'MaxPayoutLegs' txs.
+func getMintCapOfDeposit(pairID string, isSrc bool, token, cpToken *TokenConfig) *big.Int {
+	mintCap := getMintCap(pairID, !isSrc)
+	if cpToken.SplitPayout {
+		mintCap = GetPayoutCapPerTx(pairID, !isSrc)
+		if mintCap != nil {
+			mintCap = new(big.Int).Mul(mintCap, big.NewInt(MaxPayoutLegs))
+		}
+	}
+	if mintCap == nil || token.Decimals == nil || cpToken.Decimals == nil {
+		return nil
+	}
+	return ConvertTokenValue(mintCap, *cpToken.Decimals, *token.Decimals)
+}
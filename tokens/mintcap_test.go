@@ -0,0 +1,54 @@
+package tokens
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestMintCapLimitsSwapValue(t *testing.T) {
+	oldPairsConfig, oldMintCaps := tokenPairsConfig, mintCaps
+	defer func() { tokenPairsConfig, mintCaps = oldPairsConfig, oldMintCaps }()
+
+	srcDecimals, dstDecimals, zeroRate := uint8(6), uint8(18), 0.0
+	tokenPairsConfig = map[string]*TokenPairConfig{
+		"usdt": {
+			PairID: "USDT",
+			SrcToken: &TokenConfig{
+				Decimals:          &srcDecimals,
+				SwapFeeRate:       &zeroRate,
+				minSwap:           big.NewInt(100),
+				maxSwap:           big.NewInt(1000000),
+				bigValueWhitelist: map[string]struct{}{"0xwhite": {}},
+			},
+			DestToken: &TokenConfig{Decimals: &dstDecimals, SwapFeeRate: &zeroRate},
+		},
+	}
+
+	if _, maxSwap := GetSwapValueRange("usdt", true); maxSwap.Int64() != 1000000 {
+		t.Errorf("want static maximum swap without mint cap, have %v", maxSwap)
+	}
+
+	// mint cap of dest token in 18 decimals is 5000 in src token unit
+	mintCaps = map[string]*big.Int{getMintCapKey("USDT", false): new(big.Int).Mul(big.NewInt(5000), big.NewInt(1e12))}
+	if _, maxSwap := GetSwapValueRange("usdt", true); maxSwap.Int64() != 5000 {
+		t.Errorf("want maximum swap tightened by mint cap, have %v", maxSwap)
+	}
+	if _, maxSwap := GetSwapValueRange("usdt", false); maxSwap != nil {
+		t.Errorf("mint cap of dest token should not limit swapout, have %v", maxSwap)
+	}
+
+	if swapped := CalcSwappedValue("usdt", big.NewInt(5000), true, "0xfrom", ""); swapped.Sign() <= 0 {
+		t.Errorf("value within mint cap should be swapped")
+	}
+	for _, from := range []string{"0xfrom", "0xwhite"} {
+		if swapped := CalcSwappedValue("usdt", big.NewInt(5001), true, from, ""); swapped.Sign() != 0 {
+			t.Errorf("value over mint cap should not be swapped even from %v, have %v", from, swapped)
+		}
+	}
+
+	// larger mint cap than static config keeps static config
+	mintCaps = map[string]*big.Int{getMintCapKey("USDT", false): new(big.Int).Mul(big.NewInt(5000000), big.NewInt(1e12))}
+	if _, maxSwap := GetSwapValueRange("usdt", true); maxSwap.Int64() != 1000000 {
+		t.Errorf("want static maximum swap when it is tighter, have %v", maxSwap)
+	}
+}
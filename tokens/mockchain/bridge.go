@@ -0,0 +1,198 @@
+// Package mockchain implements a trivial in-memory bridge.
+//
+// It is the minimal example of an external bridge plugin. A plugin module
+// implements tokens.CrossChainBridge (optional interfaces in tokens/interfaces.go
+// enable more features) and registers its constructor in package init.
+// Then a custom main package imports the plugin for its side effects:
+//
+//	import _ "github.com/anyswap/CrossChain-Bridge/tokens/mockchain"
+//
+// and sets 'BlockChain' of the chain config to a name starting with 'MOCKCHAIN'.
+package mockchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// ChainName registered chain name of mock chain
+const ChainName = "MOCKCHAIN"
+
+// ensure Bridge impl tokens.CrossChainBridge
+var _ tokens.CrossChainBridge = &Bridge{}
+
+func init() {
+	tokens.RegisterBridgeConstructor(ChainName, func(isSrc bool) tokens.CrossChainBridge { return NewCrossChainBridge(isSrc) })
+}
+
+// Transaction mock chain transaction
+type Transaction struct {
+	PairID string
+	From   string
+	To     string
+	Bind   string
+	Value  *big.Int
+	Height uint64
+}
+
+// Hash transaction hash
+func (tx *Transaction) Hash() string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%v:%v:%v:%v:%v", tx.PairID, tx.From, tx.To, tx.Bind, tx.Value)))
+	return "0x" + hex.EncodeToString(hash[:])
+}
+
+// Bridge mock chain bridge
+type Bridge struct {
+	*tokens.CrossChainBridgeBase
+
+	lock   sync.RWMutex
+	height uint64
+	txs    map[string]*Transaction
+}
+
+// NewCrossChainBridge new mock chain bridge
+func NewCrossChainBridge(isSrc bool) *Bridge {
+	return &Bridge{
+		CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(isSrc),
+		txs:                  make(map[string]*Transaction),
+	}
+}
+
+// VerifyTokenConfig verify token config
+func (b *Bridge) VerifyTokenConfig(tokenCfg *tokens.TokenConfig) error {
+	if !b.IsValidAddress(tokenCfg.DcrmAddress) {
+		return fmt.Errorf("invalid dcrm address: %v", tokenCfg.DcrmAddress)
+	}
+	return nil
+}
+
+// IsValidAddress check address, any non empty address without space is valid
+func (b *Bridge) IsValidAddress(address string) bool {
+	return address != "" && !strings.ContainsAny(address, " \t\r\n")
+}
+
+// GetTransaction get tx
+func (b *Bridge) GetTransaction(txHash string) (interface{}, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	tx, exist := b.txs[txHash]
+	if !exist {
+		return nil, tokens.ErrTxNotFound
+	}
+	return tx, nil
+}
+
+// GetTransactionStatus get tx status
+func (b *Bridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	tx, exist := b.txs[txHash]
+	if !exist {
+		return nil, tokens.ErrTxNotFound
+	}
+	return &tokens.TxStatus{
+		Confirmations: b.height - tx.Height,
+		BlockHeight:   tx.Height,
+	}, nil
+}
+
+// VerifyTransaction verify deposit tx sent to dcrm address
+func (b *Bridge) VerifyTransaction(pairID, txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+	tokenCfg := b.GetTokenConfig(pairID)
+	if tokenCfg == nil {
+		return nil, tokens.ErrUnknownPairID
+	}
+	rawTx, err := b.GetTransaction(txHash)
+	if err != nil {
+		return nil, err
+	}
+	tx := rawTx.(*Transaction)
+	if !strings.EqualFold(tx.To, tokenCfg.DcrmAddress) {
+		return nil, tokens.ErrTxWithWrongReceiver
+	}
+	if tx.Value == nil || tx.Value.Sign() <= 0 {
+		return nil, tokens.ErrTxWithWrongValue
+	}
+	return &tokens.TxSwapInfo{
+		PairID: pairID,
+		Hash:   txHash,
+		Height: tx.Height,
+		From:   tx.From,
+		TxTo:   tx.To,
+		To:     tx.To,
+		Bind:   tx.Bind,
+		Value:  tx.Value,
+	}, nil
+}
+
+// VerifyMsgHash verify msg hash
+func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHash []string) error {
+	tx, ok := rawTx.(*Transaction)
+	if !ok {
+		return tokens.ErrWrongRawTx
+	}
+	if len(msgHash) != 1 || msgHash[0] != tx.Hash() {
+		return tokens.ErrMsgHashMismatch
+	}
+	return nil
+}
+
+// BuildRawTransaction build raw tx
+func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	if args.Value == nil {
+		return nil, tokens.ErrTxWithWrongValue
+	}
+	return &Transaction{
+		PairID: args.PairID,
+		From:   args.From,
+		To:     args.Bind,
+		Value:  args.Value,
+	}, nil
+}
+
+// SignTransaction sign tx with pairID, mock chain does not check signatures
+func (b *Bridge) SignTransaction(rawTx interface{}, pairID string) (signedTx interface{}, txHash string, err error) {
+	tx, ok := rawTx.(*Transaction)
+	if !ok {
+		return nil, "", tokens.ErrWrongRawTx
+	}
+	return tx, tx.Hash(), nil
+}
+
+// DcrmSignTransaction dcrm sign raw tx
+func (b *Bridge) DcrmSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signedTx interface{}, txHash string, err error) {
+	return b.SignTransaction(rawTx, args.PairID)
+}
+
+// SendTransaction send signed tx, it is included in a new block
+func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	tx, ok := signedTx.(*Transaction)
+	if !ok {
+		return "", tokens.ErrWrongRawTx
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.height++
+	tx.Height = b.height
+	txHash = tx.Hash()
+	b.txs[txHash] = tx
+	return txHash, nil
+}
+
+// GetLatestBlockNumber get latest block number
+func (b *Bridge) GetLatestBlockNumber() (uint64, error) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return b.height, nil
+}
+
+// GetLatestBlockNumberOf get latest block number of gateway, same as GetLatestBlockNumber
+func (b *Bridge) GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
+	return b.GetLatestBlockNumber()
+}
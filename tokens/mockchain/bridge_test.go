@@ -0,0 +1,52 @@
+package mockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func TestRegisteredBridge(t *testing.T) {
+	constructor := tokens.GetBridgeConstructor("mockchain-devnet")
+	if constructor == nil {
+		t.Fatal("mock chain constructor is not registered")
+	}
+	bridge, ok := constructor(true).(*Bridge)
+	if !ok || !bridge.IsSrcEndpoint() {
+		t.Fatal("constructor does not build source mock chain bridge")
+	}
+}
+
+func TestVerifyDeposit(t *testing.T) {
+	tokens.SetTokenPairsConfig(map[string]*tokens.TokenPairConfig{
+		"mock": {
+			PairID:    "mock",
+			SrcToken:  &tokens.TokenConfig{DcrmAddress: "dcrm"},
+			DestToken: &tokens.TokenConfig{DcrmAddress: "dcrm"},
+		},
+	}, false)
+	bridge := NewCrossChainBridge(true)
+
+	deposit := &Transaction{PairID: "mock", From: "user", To: "dcrm", Bind: "bind", Value: big.NewInt(100)}
+	txHash, err := bridge.SendTransaction(deposit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	swapInfo, err := bridge.VerifyTransaction("mock", txHash, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swapInfo.Bind != "bind" || swapInfo.Value.Cmp(big.NewInt(100)) != 0 || swapInfo.Height != 1 {
+		t.Fatalf("wrong swap info %+v", swapInfo)
+	}
+
+	wrongTo := &Transaction{PairID: "mock", From: "user", To: "other", Bind: "bind", Value: big.NewInt(100)}
+	txHash, _ = bridge.SendTransaction(wrongTo)
+	if _, err = bridge.VerifyTransaction("mock", txHash, false); err != tokens.ErrTxWithWrongReceiver {
+		t.Fatalf("want wrong receiver error, got %v", err)
+	}
+	if _, err = bridge.VerifyTransaction("mock", "0xunknown", false); err != tokens.ErrTxNotFound {
+		t.Fatalf("want tx not found error, got %v", err)
+	}
+}
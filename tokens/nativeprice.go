@@ -0,0 +1,208 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+)
+
+const (
+	defaultNativePriceFetchInterval = 300 // seconds
+	nativePriceFeedTimeout          = 30  // seconds
+)
+
+var (
+	// NativePriceCfg native coin price config
+	NativePriceCfg *NativePriceConfig
+
+	nativeRates     = make(map[bool]*nativeRate) // key is isSrc
+	nativeRatesLock sync.RWMutex
+
+	nativePriceStarter sync.Once
+
+	errNativePriceMissing = errors.New("native coin price is missing in feed")
+)
+
+type nativeRate struct {
+	rate      float64
+	timestamp int64
+	static    bool
+}
+
+// NativePrice native coin price in reference currency,
+// rate and timestamp are null if the price is missing or stale.
+type NativePrice struct {
+	Symbol    string   `json:"symbol"`
+	Currency  string   `json:"currency"`
+	Rate      *float64 `json:"rate"`
+	Timestamp *int64   `json:"timestamp"`
+}
+
+// ConvertedValue native value converted to reference currency,
+// value is null if the price is missing or stale.
+type ConvertedValue struct {
+	NativePrice
+	Value *float64 `json:"value"`
+}
+
+// CheckConfig check native price config
+func (c *NativePriceConfig) CheckConfig() error {
+	if c.Currency == "" {
+		return errors.New("native price must config 'Currency'")
+	}
+	if c.Src == nil || c.Dest == nil {
+		return errors.New("native price must config 'Src' and 'Dest'")
+	}
+	for _, coinCfg := range []*NativeCoinConfig{c.Src, c.Dest} {
+		switch {
+		case coinCfg.Price < 0:
+			return fmt.Errorf("native price of '%v' is negative", coinCfg.Symbol)
+		case coinCfg.Price == 0 && (c.FeedURL == "" || coinCfg.CoinID == ""):
+			return fmt.Errorf("native price of '%v' must config static 'Price' or 'FeedURL' and 'CoinID'", coinCfg.Symbol)
+		}
+	}
+	return nil
+}
+
+// GetNativePriceMaxAge get max age (seconds) of fetched price before it is stale
+func (c *NativePriceConfig) GetNativePriceMaxAge() int64 {
+	if c.MaxAge > 0 {
+		return int64(c.MaxAge)
+	}
+	return 3 * c.getFetchInterval()
+}
+
+func (c *NativePriceConfig) getFetchInterval() int64 {
+	if c.FetchInterval > 0 {
+		return int64(c.FetchInterval)
+	}
+	return defaultNativePriceFetchInterval
+}
+
+func (c *NativePriceConfig) getCoinConfig(isSrc bool) *NativeCoinConfig {
+	if isSrc {
+		return c.Src
+	}
+	return c.Dest
+}
+
+// InitNativePrices init static native prices and start fetching prices from feed
+func InitNativePrices() {
+	if NativePriceCfg == nil {
+		return
+	}
+	nativePriceStarter.Do(func() {
+		now := time.Now().Unix()
+		needFetch := false
+		for _, isSrc := range []bool{true, false} {
+			coinCfg := NativePriceCfg.getCoinConfig(isSrc)
+			if coinCfg.Price > 0 {
+				setNativeRate(isSrc, &nativeRate{rate: coinCfg.Price, timestamp: now, static: true})
+			} else {
+				needFetch = true
+			}
+		}
+		if needFetch && NativePriceCfg.FeedURL != "" {
+			go loopFetchNativePrices()
+		}
+		log.Info("init native prices success", "currency", NativePriceCfg.Currency, "fetch", needFetch && NativePriceCfg.FeedURL != "")
+	})
+}
+
+func loopFetchNativePrices() {
+	interval := time.Duration(NativePriceCfg.getFetchInterval()) * time.Second
+	for {
+		for _, isSrc := range []bool{true, false} {
+			coinCfg := NativePriceCfg.getCoinConfig(isSrc)
+			if coinCfg.Price > 0 {
+				continue
+			}
+			rate, err := fetchNativePrice(coinCfg.CoinID)
+			if err != nil {
+				log.Warn("fetch native price failed", "isSrc", isSrc, "coinID", coinCfg.CoinID, "err", err)
+				continue
+			}
+			setNativeRate(isSrc, &nativeRate{rate: rate, timestamp: time.Now().Unix()})
+			log.Debug("fetch native price success", "isSrc", isSrc, "coinID", coinCfg.CoinID, "rate", rate)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// fetchNativePrice fetch price from feed which responses like
+// `{"ethereum":{"usd":1234.5}}` (eg. coingecko simple price api)
+func fetchNativePrice(coinID string) (float64, error) {
+	var result map[string]map[string]float64
+	err := client.RPCGetWithTimeout(&result, NativePriceCfg.FeedURL, nativePriceFeedTimeout)
+	if err != nil {
+		return 0, err
+	}
+	rate, exist := result[coinID][strings.ToLower(NativePriceCfg.Currency)]
+	if !exist || rate <= 0 {
+		return 0, errNativePriceMissing
+	}
+	return rate, nil
+}
+
+func setNativeRate(isSrc bool, rate *nativeRate) {
+	nativeRatesLock.Lock()
+	nativeRates[isSrc] = rate
+	nativeRatesLock.Unlock()
+}
+
+// GetNativePrice get native coin price of source or dest chain
+func GetNativePrice(isSrc bool) *NativePrice {
+	if NativePriceCfg == nil {
+		return nil
+	}
+	price := &NativePrice{
+		Symbol:   NativePriceCfg.getCoinConfig(isSrc).Symbol,
+		Currency: NativePriceCfg.Currency,
+	}
+	nativeRatesLock.RLock()
+	rate := nativeRates[isSrc]
+	nativeRatesLock.RUnlock()
+	if rate == nil {
+		return price
+	}
+	if !rate.static && rate.timestamp+NativePriceCfg.GetNativePriceMaxAge() < time.Now().Unix() {
+		return price
+	}
+	price.Rate = &rate.rate
+	price.Timestamp = &rate.timestamp
+	return price
+}
+
+// IsNativeCoin is token the native coin of source or dest chain in native price config
+func (c *TokenConfig) IsNativeCoin(isSrc bool) bool {
+	if NativePriceCfg == nil {
+		return false
+	}
+	return c.ContractAddress == "" &&
+		strings.EqualFold(c.Symbol, NativePriceCfg.getCoinConfig(isSrc).Symbol)
+}
+
+// ConvertNativeValue convert native value (in smallest unit) to reference currency
+func ConvertNativeValue(isSrc bool, value *big.Int) *ConvertedValue {
+	price := GetNativePrice(isSrc)
+	if price == nil {
+		return nil
+	}
+	converted := &ConvertedValue{NativePrice: *price}
+	if price.Rate == nil || value == nil {
+		return converted
+	}
+	decimals := NativePriceCfg.getCoinConfig(isSrc).Decimals
+	amount := new(big.Float).SetInt(value)
+	amount.Quo(amount, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	amount.Mul(amount, big.NewFloat(*price.Rate))
+	result, _ := amount.Float64()
+	converted.Value = &result
+	return converted
+}
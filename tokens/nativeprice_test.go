@@ -0,0 +1,51 @@
+package tokens
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestConvertNativeValue(t *testing.T) {
+	oldCfg := NativePriceCfg
+	defer func() {
+		NativePriceCfg = oldCfg
+		nativeRates = make(map[bool]*nativeRate)
+	}()
+
+	NativePriceCfg = &NativePriceConfig{
+		Currency: "USD",
+		MaxAge:   600,
+		Src:      &NativeCoinConfig{Symbol: "BTC", Decimals: 8},
+		Dest:     &NativeCoinConfig{Symbol: "ETH", Decimals: 18},
+	}
+	nativeRates = make(map[bool]*nativeRate)
+
+	// missing rate
+	converted := ConvertNativeValue(true, big.NewInt(100000000))
+	if converted == nil || converted.Value != nil || converted.Rate != nil || converted.Timestamp != nil {
+		t.Fatalf("missing rate should convert to nulls, have %+v", converted)
+	}
+
+	now := time.Now().Unix()
+	setNativeRate(true, &nativeRate{rate: 20000, timestamp: now})
+	setNativeRate(false, &nativeRate{rate: 1000, timestamp: now - 601})
+
+	converted = ConvertNativeValue(true, big.NewInt(150000000))
+	if converted.Value == nil || *converted.Value != 30000 || *converted.Rate != 20000 || *converted.Timestamp != now {
+		t.Fatalf("wrong converted value %+v", converted)
+	}
+
+	// stale rate
+	converted = ConvertNativeValue(false, big.NewInt(1e18))
+	if converted.Value != nil || converted.Rate != nil {
+		t.Fatalf("stale rate should convert to nulls, have %+v", converted)
+	}
+
+	// static rate never stale
+	setNativeRate(false, &nativeRate{rate: 1000, timestamp: now - 6000, static: true})
+	converted = ConvertNativeValue(false, big.NewInt(2e18))
+	if converted.Value == nil || *converted.Value != 2000 {
+		t.Fatalf("wrong converted value of static rate %+v", converted)
+	}
+}
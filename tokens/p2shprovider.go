@@ -0,0 +1,14 @@
+package tokens
+
+// p2sh address provider of source bridge, nil if it's not btc-like
+var p2shAddressProvider P2shAddressProvider
+
+// SetP2shAddressProvider set p2sh address provider, call it in constructor of btc-like bridges
+func SetP2shAddressProvider(provider P2shAddressProvider) {
+	p2shAddressProvider = provider
+}
+
+// GetP2shAddressProvider get p2sh address provider, nil if no p2sh-capable bridge is configured
+func GetP2shAddressProvider() P2shAddressProvider {
+	return p2shAddressProvider
+}
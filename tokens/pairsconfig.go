@@ -1,11 +1,13 @@
 package tokens
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -25,6 +27,20 @@ type TokenPairConfig struct {
 	DiffDecimals bool
 	SrcToken     *TokenConfig
 	DestToken    *TokenConfig
+
+	// override chain 'Confirmations' of this pair, default to the chain config
+	SrcStableConfirmations *uint64 `toml:",omitempty" json:",omitempty"`
+	DstStableConfirmations *uint64 `toml:",omitempty" json:",omitempty"`
+
+	// minimum confirmations of deposit tx before registering swapin (btc only),
+	// it's not the stable confirmations, registered swaps still wait to be stable
+	MinRegisterConfirmations uint64 `toml:",omitempty" json:",omitempty"`
+
+	// override 'Server.RiskHook.FailOpen' of this pair
+	RiskHookFailOpen *bool `toml:",omitempty" json:",omitempty"`
+
+	// sign this pair by the named 'Dcrm.GroupProfiles' instead of the default dcrm group
+	DcrmGroup string `toml:",omitempty" json:",omitempty"`
 }
 
 // SetTokenPairsDir set token pairs directory
@@ -45,7 +61,7 @@ func GetTokenPairsDir() string {
 	return tokenPairsConfigDirectory
 }
 
-// SetTokenPairsConfig set token pairs config
+// SetTokenPairsConfig set token pairs config, keyed by lower case pairID
 func SetTokenPairsConfig(pairsConfig map[string]*TokenPairConfig, check bool) {
 	if check {
 		err := checkTokenPairsConfig(pairsConfig)
@@ -53,7 +69,25 @@ func SetTokenPairsConfig(pairsConfig map[string]*TokenPairConfig, check bool) {
 			log.Fatalf("check token pairs config error: %v", err)
 		}
 	}
-	tokenPairsConfig = pairsConfig
+	normalized, err := normalizeTokenPairsConfig(pairsConfig)
+	if err != nil {
+		log.Fatalf("set token pairs config error: %v", err)
+	}
+	tokenPairsConfig = normalized
+}
+
+// normalizeTokenPairsConfig key token pairs config by lower case pairID,
+// pairIDs which are only different in case are duplicate.
+func normalizeTokenPairsConfig(pairsConfig map[string]*TokenPairConfig) (map[string]*TokenPairConfig, error) {
+	normalized := make(map[string]*TokenPairConfig, len(pairsConfig))
+	for _, pairCfg := range pairsConfig {
+		pairID := strings.ToLower(pairCfg.PairID)
+		if exist, ok := normalized[pairID]; ok {
+			return nil, fmt.Errorf("duplicate pairID '%v' and '%v'", exist.PairID, pairCfg.PairID)
+		}
+		normalized[pairID] = pairCfg
+	}
+	return normalized, nil
 }
 
 // GetTokenPairsConfig get token pairs config
@@ -77,18 +111,57 @@ func IsTokenPairExist(pairID string) bool {
 	return exist
 }
 
-// GetAllPairIDs get all pairIDs
+// GetAllPairIDs get all pairIDs (lower case, deduplicated and sorted)
 func GetAllPairIDs() []string {
 	pairIDs := make([]string, 0, len(tokenPairsConfig))
 	for _, pairCfg := range tokenPairsConfig {
-		pairIDs = append(pairIDs, strings.ToLower(pairCfg.PairID))
+		pairIDs = append(pairIDs, pairCfg.PairID)
 	}
-	return pairIDs
+	return normalizePairIDs(pairIDs)
+}
+
+// GetSortedTokenPairsConfig get token pairs config sorted by pairID
+func GetSortedTokenPairsConfig() []*TokenPairConfig {
+	pairIDs := GetAllPairIDs()
+	pairCfgs := make([]*TokenPairConfig, 0, len(pairIDs))
+	for _, pairID := range pairIDs {
+		if pairCfg, exist := tokenPairsConfig[pairID]; exist {
+			pairCfgs = append(pairCfgs, pairCfg)
+		}
+	}
+	return pairCfgs
+}
+
+// SplitPairIDs split comma separated pairIDs, 'all' means all pairIDs.
+// the result is lower case, deduplicated and sorted.
+func SplitPairIDs(pairIDs string) []string {
+	if strings.EqualFold(strings.TrimSpace(pairIDs), "all") {
+		return GetAllPairIDs()
+	}
+	return normalizePairIDs(strings.Split(pairIDs, ","))
+}
+
+func normalizePairIDs(pairIDs []string) []string {
+	exist := make(map[string]struct{}, len(pairIDs))
+	result := make([]string, 0, len(pairIDs))
+	for _, pairID := range pairIDs {
+		pairID = strings.ToLower(strings.TrimSpace(pairID))
+		if pairID == "" {
+			continue
+		}
+		if _, ok := exist[pairID]; ok {
+			continue
+		}
+		exist[pairID] = struct{}{}
+		result = append(result, pairID)
+	}
+	sort.Strings(result)
+	return result
 }
 
 // FindTokenConfig find by (tx to) address
 func FindTokenConfig(address string, isSrc bool) (configs []*TokenConfig, pairIDs []string) {
-	for _, pairCfg := range tokenPairsConfig {
+	for _, pairCfg := range GetSortedTokenPairsConfig() {
 		var tokenCfg *TokenConfig
 		if isSrc {
 			tokenCfg = pairCfg.SrcToken
@@ -170,11 +243,11 @@ func checkTokenPairsConfig(pairsConfig map[string]*TokenPairConfig) (err error)
 		if err != nil {
 			return err
 		}
-		err = SrcBridge.VerifyTokenConfig(tokenPair.SrcToken)
+		err = verifyTokenConfig(SrcBridge, tokenPair.SrcToken)
 		if err != nil {
 			return err
 		}
-		err = DstBridge.VerifyTokenConfig(tokenPair.DestToken)
+		err = verifyTokenConfig(DstBridge, tokenPair.DestToken)
 		if err != nil {
 			return err
 		}
@@ -199,6 +272,10 @@ func (c *TokenPairConfig) CheckConfig() (err error) {
 	if c.DestToken == nil {
 		return errors.New("tokenPair must config 'DestToken'")
 	}
+	err = c.checkDcrmGroup()
+	if err != nil {
+		return err
+	}
 	err = c.SrcToken.CheckConfig(true)
 	if err != nil {
 		return err
@@ -210,6 +287,36 @@ func (c *TokenPairConfig) CheckConfig() (err error) {
 	return nil
 }
 
+// checkDcrmGroup check the referenced dcrm group profile exists, and fill
+// or check dcrm public keys of tokens with the profile's public key.
+// dcrm addresses are verified to be derived from it by 'verifyTokenConfig'.
+func (c *TokenPairConfig) checkDcrmGroup() error {
+	if c.DcrmGroup == "" {
+		return nil
+	}
+	pubkey, exist := DcrmGroupPubkeys[c.DcrmGroup]
+	if !exist {
+		return fmt.Errorf("tokenPair dcrm group '%v' is not configed in 'Dcrm.GroupProfiles'", c.DcrmGroup)
+	}
+	for _, tokenCfg := range []*TokenConfig{c.SrcToken, c.DestToken} {
+		if tokenCfg.DcrmAddressPriKey != "" {
+			return errors.New("tokenPair with dcrm group forbid config 'DcrmAddressPriKey'")
+		}
+		if tokenCfg.DcrmPubkey == "" {
+			tokenCfg.DcrmPubkey = pubkey
+			continue
+		}
+		normalized, err := NormalizeDcrmPublicKey(tokenCfg.DcrmPubkey)
+		if err != nil {
+			return fmt.Errorf("wrong dcrm public key: %w", err)
+		}
+		if !bytes.Equal(common.FromHex(normalized), common.FromHex(pubkey)) {
+			return fmt.Errorf("dcrm public key of token '%v' is not of dcrm group '%v'", tokenCfg.DcrmAddress, c.DcrmGroup)
+		}
+	}
+	return nil
+}
+
 // LoadTokenPairsConfig load token pairs config
 func LoadTokenPairsConfig(check bool) {
 	pairsConfig, err := LoadTokenPairsConfigInDir(tokenPairsConfigDirectory, check)
@@ -304,11 +411,11 @@ func checkAddTokenPairsConfig(pairConfig *TokenPairConfig) (err error) {
 	if err != nil {
 		return err
 	}
-	err = SrcBridge.VerifyTokenConfig(pairConfig.SrcToken)
+	err = verifyTokenConfig(SrcBridge, pairConfig.SrcToken)
 	if err != nil {
 		return err
 	}
-	err = DstBridge.VerifyTokenConfig(pairConfig.DestToken)
+	err = verifyTokenConfig(DstBridge, pairConfig.DestToken)
 	if err != nil {
 		return err
 	}
@@ -335,3 +442,39 @@ func checkAddTokenPairsConfig(pairConfig *TokenPairConfig) (err error) {
 	}
 	return nil
 }
+
+func verifyTokenConfig(bridge CrossChainBridge, tokenCfg *TokenConfig) error {
+	err := bridge.VerifyTokenConfig(tokenCfg)
+	if err != nil {
+		return err
+	}
+	return VerifyDcrmAddressOfPubkey(bridge, tokenCfg)
+}
+
+// VerifyDcrmAddressOfPubkey verify dcrm address is derived from dcrm public key,
+// it's skipped only if the token is signed by private key instead of dcrm.
+func VerifyDcrmAddressOfPubkey(bridge CrossChainBridge, tokenCfg *TokenConfig) error {
+	if tokenCfg.DcrmAddressPriKey != "" {
+		return nil
+	}
+	if tokenCfg.DcrmPubkey == "" {
+		return fmt.Errorf("dcrm address '%v' has no dcrm public key configed", tokenCfg.DcrmAddress)
+	}
+	deriver, ok := bridge.(AddressDeriver)
+	if !ok {
+		return fmt.Errorf("bridge can not derive address from dcrm public key to verify dcrm address '%v'", tokenCfg.DcrmAddress)
+	}
+	pubAddr, err := deriver.PublicKeyToAddress(tokenCfg.DcrmPubkey)
+	if err != nil {
+		return fmt.Errorf("derive address from dcrm public key '%v' failed: %w", tokenCfg.DcrmPubkey, err)
+	}
+	isMatch := pubAddr == tokenCfg.DcrmAddress
+	if !isMatch && common.IsHexAddress(pubAddr) {
+		isMatch = strings.EqualFold(pubAddr, tokenCfg.DcrmAddress)
+	}
+	if !isMatch {
+		return fmt.Errorf("dcrm address '%v' and public key address '%v' is not match", tokenCfg.DcrmAddress, pubAddr)
+	}
+	log.Info("verify dcrm address of public key success", "dcrmAddress", tokenCfg.DcrmAddress, "pubkeyAddress", pubAddr)
+	return nil
+}
@@ -0,0 +1,122 @@
+package tokens
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGetAllPairIDsSorted(t *testing.T) {
+	oldPairsConfig := tokenPairsConfig
+	defer func() { tokenPairsConfig = oldPairsConfig }()
+
+	SetTokenPairsConfig(map[string]*TokenPairConfig{
+		"x": {PairID: "USDT"},
+		"y": {PairID: "btc"},
+		"z": {PairID: "Eth"},
+	}, false)
+
+	want := []string{"btc", "eth", "usdt"}
+	for i := 0; i < 10; i++ {
+		if have := GetAllPairIDs(); !reflect.DeepEqual(have, want) {
+			t.Fatalf("GetAllPairIDs want %v, have %v", want, have)
+		}
+	}
+	if GetTokenPairConfig("ETH") == nil {
+		t.Errorf("pair config should be found case insensitively")
+	}
+
+	if have := SplitPairIDs("all"); !reflect.DeepEqual(have, want) {
+		t.Errorf("SplitPairIDs(all) want %v, have %v", want, have)
+	}
+	if have := SplitPairIDs(" USDT,eth,,usdt , BTC"); !reflect.DeepEqual(have, want) {
+		t.Errorf("SplitPairIDs want %v, have %v", want, have)
+	}
+}
+
+func TestNormalizeTokenPairsConfigDuplicate(t *testing.T) {
+	pairsConfig := map[string]*TokenPairConfig{
+		"a": {PairID: "USDT"},
+		"b": {PairID: "usdt"},
+	}
+	if _, err := normalizeTokenPairsConfig(pairsConfig); err == nil {
+		t.Errorf("pairIDs only different in case should be duplicate")
+	}
+	pairsConfig["b"].PairID = "btc"
+	normalized, err := normalizeTokenPairsConfig(pairsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if normalized["usdt"] != pairsConfig["a"] || normalized["btc"] != pairsConfig["b"] {
+		t.Errorf("pairs config should be keyed by lower case pairID, have %v", normalized)
+	}
+}
+
+func TestGetAllPairIDsStableAcrossReload(t *testing.T) {
+	oldPairsConfig := tokenPairsConfig
+	defer func() { tokenPairsConfig = oldPairsConfig }()
+
+	loadFrom := func(files map[string]string) []string {
+		dir, err := ioutil.TempDir("", "pairs")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		for name, pairID := range files {
+			content := "PairID = \"" + pairID + "\"\n[SrcToken]\n[DestToken]\n"
+			if err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		pairsConfig, err := LoadTokenPairsConfigInDir(dir, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		SetTokenPairsConfig(pairsConfig, false)
+		return GetAllPairIDs()
+	}
+
+	first := loadFrom(map[string]string{"a.toml": "ETH", "b.toml": "btc", "c.toml": "Usdt"})
+	second := loadFrom(map[string]string{"a.toml": "usdt", "b.toml": "Eth", "c.toml": "BTC"})
+	want := []string{"btc", "eth", "usdt"}
+	if !reflect.DeepEqual(first, want) || !reflect.DeepEqual(second, want) {
+		t.Errorf("reload should keep pair IDs order, want %v, have %v and %v", want, first, second)
+	}
+}
+
+func TestCheckDcrmGroup(t *testing.T) {
+	const (
+		compressedPubkey   = "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+		uncompressedPubkey = "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"
+		otherPubkey        = "02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5"
+	)
+	oldPubkeys := DcrmGroupPubkeys
+	defer func() { DcrmGroupPubkeys = oldPubkeys }()
+	DcrmGroupPubkeys = map[string]string{"highvalue": uncompressedPubkey}
+
+	pair := &TokenPairConfig{
+		PairID:    "USDT",
+		SrcToken:  &TokenConfig{},
+		DestToken: &TokenConfig{DcrmPubkey: compressedPubkey},
+		DcrmGroup: "highvalue",
+	}
+	if err := pair.checkDcrmGroup(); err != nil {
+		t.Fatalf("check dcrm group failed: %v", err)
+	}
+	if pair.SrcToken.DcrmPubkey != uncompressedPubkey {
+		t.Errorf("empty dcrm public key should be filled by dcrm group, have %v", pair.SrcToken.DcrmPubkey)
+	}
+
+	pair.DestToken.DcrmPubkey = otherPubkey
+	if err := pair.checkDcrmGroup(); err == nil {
+		t.Errorf("dcrm public key of other group should be rejected")
+	}
+
+	pair.DestToken.DcrmPubkey = ""
+	pair.DcrmGroup = "lowvalue"
+	if err := pair.checkDcrmGroup(); err == nil {
+		t.Errorf("unknown dcrm group should be rejected")
+	}
+}
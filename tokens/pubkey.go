@@ -0,0 +1,126 @@
+package tokens
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+const (
+	compressedPublicKeyLength   = 33
+	uncompressedPublicKeyLength = 65
+)
+
+// IsEd25519PublicKey is ed25519 public key (with 0xED prefix, eg. ripple)
+func IsEd25519PublicKey(pkData []byte) bool {
+	return len(pkData) == compressedPublicKeyLength && pkData[0] == 0xED
+}
+
+// ParsePublicKey parse secp256k1 public key of
+// compressed (33 bytes) or uncompressed (65 bytes) form
+func ParsePublicKey(pkData []byte) (*ecdsa.PublicKey, error) {
+	switch {
+	case len(pkData) == compressedPublicKeyLength && (pkData[0] == 2 || pkData[0] == 3):
+		return crypto.DecompressPubkey(pkData)
+	case len(pkData) == uncompressedPublicKeyLength && pkData[0] == 4:
+		return crypto.UnmarshalPubkey(pkData)
+	default:
+		return nil, fmt.Errorf("%w: length %v", ErrWrongPublicKey, len(pkData))
+	}
+}
+
+// ToCompressedPublicKey convert compressed or uncompressed public key to compressed form
+func ToCompressedPublicKey(pkData []byte) ([]byte, error) {
+	pubKey, err := ParsePublicKey(pkData)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.CompressPubkey(pubKey), nil
+}
+
+// ToUncompressedPublicKey convert compressed or uncompressed public key to uncompressed form
+func ToUncompressedPublicKey(pkData []byte) ([]byte, error) {
+	pubKey, err := ParsePublicKey(pkData)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.FromECDSAPub(pubKey), nil
+}
+
+// NormalizeDcrmPublicKey normalize compressed or uncompressed public key hex
+// to uncompressed hex (the form dcrm identifies keys by), and verify the
+// compressed and uncompressed forms round trip. ed25519 public key is kept as is.
+func NormalizeDcrmPublicKey(pubKeyHex string) (string, error) {
+	pkData := common.FromHex(pubKeyHex)
+	if IsEd25519PublicKey(pkData) {
+		return pubKeyHex, nil
+	}
+	uncompressed, err := ToUncompressedPublicKey(pkData)
+	if err != nil {
+		return "", err
+	}
+	compressed, err := ToCompressedPublicKey(uncompressed)
+	if err != nil {
+		return "", err
+	}
+	roundTrip, err := ToUncompressedPublicKey(compressed)
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(roundTrip, uncompressed) {
+		return "", fmt.Errorf("%w: compressed and uncompressed forms mismatch", ErrWrongPublicKey)
+	}
+	return common.Bytes2Hex(uncompressed), nil
+}
+
+// IsSignatureOfPublicKey check 65 bytes signature [R || S || V] is signed by public key
+// (compressed or uncompressed), try both recovery ids before declaring mismatch
+func IsSignatureOfPublicKey(msgHash, signature, pkData []byte) bool {
+	pubKey, err := ParsePublicKey(pkData)
+	if err != nil || len(signature) != crypto.SignatureLength {
+		return false
+	}
+	sig := common.CopyBytes(signature)
+	vPos := crypto.SignatureLength - 1
+	for i := 0; i < 2; i++ {
+		recovered, err := crypto.SigToPub(msgHash, sig)
+		if err == nil && recovered.X.Cmp(pubKey.X) == 0 && recovered.Y.Cmp(pubKey.Y) == 0 {
+			return true
+		}
+		sig[vPos] ^= 0x1 // v can only be 0 or 1
+	}
+	return false
+}
+
+// MatchSignaturesToMsgHashes pair each msg hash with a signature (hex rsv) signed by public key,
+// as dcrm may return signatures not in the order of msg hashes. reordered is true if the
+// returned order differs from the given order. error if some msg hash has no valid signature.
+func MatchSignaturesToMsgHashes(rsvs, msgHashes []string, pkData []byte) (ordered []string, reordered bool, err error) {
+	if len(rsvs) != len(msgHashes) {
+		return nil, false, fmt.Errorf("%w: have %v signatures for %v msg hashes", ErrWrongCountOfMsgHashes, len(rsvs), len(msgHashes))
+	}
+	used := make([]bool, len(rsvs))
+	ordered = make([]string, len(msgHashes))
+	for i, msgHash := range msgHashes {
+		hashData := common.FromHex(msgHash)
+		matched := -1
+		for j, rsv := range rsvs {
+			if !used[j] && IsSignatureOfPublicKey(hashData, common.FromHex(rsv), pkData) {
+				matched = j
+				break
+			}
+		}
+		if matched < 0 {
+			return nil, false, fmt.Errorf("msgHash %v has no matched rsv", msgHash)
+		}
+		used[matched] = true
+		ordered[i] = rsvs[matched]
+		if matched != i {
+			reordered = true
+		}
+	}
+	return ordered, reordered, nil
+}
@@ -0,0 +1,230 @@
+package tokens_test
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/block"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/anyswap/CrossChain-Bridge/tokens/colx"
+	"github.com/anyswap/CrossChain-Bridge/tokens/etc"
+	"github.com/anyswap/CrossChain-Bridge/tokens/eth"
+	"github.com/anyswap/CrossChain-Bridge/tokens/fsn"
+	"github.com/anyswap/CrossChain-Bridge/tokens/kusama"
+	"github.com/anyswap/CrossChain-Bridge/tokens/ltc"
+	"github.com/anyswap/CrossChain-Bridge/tokens/okex"
+	"github.com/anyswap/CrossChain-Bridge/tokens/ripple"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+// public key of private key 1
+const (
+	testCompressedPubkey   = "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+	testUncompressedPubkey = "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"
+)
+
+func TestNormalizeDcrmPublicKey(t *testing.T) {
+	for _, pubkey := range []string{testCompressedPubkey, testUncompressedPubkey, "0x" + testCompressedPubkey} {
+		normalized, err := tokens.NormalizeDcrmPublicKey(pubkey)
+		if err != nil || normalized != testUncompressedPubkey {
+			t.Errorf("normalize %v: want %v, have %v, %v", pubkey, testUncompressedPubkey, normalized, err)
+		}
+	}
+	edPubkey := "ed" + testCompressedPubkey[2:]
+	if normalized, err := tokens.NormalizeDcrmPublicKey(edPubkey); err != nil || normalized != edPubkey {
+		t.Errorf("ed25519 public key should be kept, have %v, %v", normalized, err)
+	}
+	if _, err := tokens.NormalizeDcrmPublicKey(testUncompressedPubkey[:128]); !errors.Is(err, tokens.ErrWrongPublicKey) {
+		t.Errorf("want ErrWrongPublicKey, have %v", err)
+	}
+}
+
+func TestIsSignatureOfPublicKey(t *testing.T) {
+	privKey, _ := crypto.HexToECDSA("0000000000000000000000000000000000000000000000000000000000000001")
+	otherKey, _ := crypto.HexToECDSA("0000000000000000000000000000000000000000000000000000000000000002")
+	msgHash := crypto.Keccak256([]byte("message"))
+	signature, err := crypto.Sign(msgHash, privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrongV := common.CopyBytes(signature)
+	wrongV[crypto.SignatureLength-1] ^= 0x1
+
+	for _, pubkey := range []string{testCompressedPubkey, testUncompressedPubkey} {
+		pkData := common.FromHex(pubkey)
+		if !tokens.IsSignatureOfPublicKey(msgHash, signature, pkData) {
+			t.Errorf("signature should match public key %v", pubkey)
+		}
+		if !tokens.IsSignatureOfPublicKey(msgHash, wrongV, pkData) {
+			t.Errorf("signature with flipped recovery id should match public key %v", pubkey)
+		}
+	}
+	if tokens.IsSignatureOfPublicKey(msgHash, signature, crypto.FromECDSAPub(&otherKey.PublicKey)) {
+		t.Error("signature should not match other public key")
+	}
+}
+
+func TestMatchSignaturesToMsgHashes(t *testing.T) {
+	privKey, _ := crypto.HexToECDSA("0000000000000000000000000000000000000000000000000000000000000001")
+	otherKey, _ := crypto.HexToECDSA("0000000000000000000000000000000000000000000000000000000000000002")
+	pkData := common.FromHex(testCompressedPubkey)
+
+	sign := func(key *ecdsa.PrivateKey, msgHash string) string {
+		signature, err := crypto.Sign(common.FromHex(msgHash), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return common.ToHex(signature)
+	}
+
+	for _, count := range []int{2, 5} {
+		msgHashes := make([]string, count)
+		rsvs := make([]string, count)
+		for i := range msgHashes {
+			msgHashes[i] = common.ToHex(crypto.Keccak256([]byte(fmt.Sprintf("input %v", i))))
+			rsvs[i] = sign(privKey, msgHashes[i])
+		}
+		for round := 0; round < 10; round++ {
+			shuffled := append([]string(nil), rsvs...)
+			rand.Shuffle(count, func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+			ordered, reordered, err := tokens.MatchSignaturesToMsgHashes(shuffled, msgHashes, pkData)
+			if err != nil {
+				t.Fatalf("%v inputs: match shuffled signatures failed: %v", count, err)
+			}
+			if !reflect.DeepEqual(ordered, rsvs) {
+				t.Errorf("%v inputs: signatures are not paired with msg hashes", count)
+			}
+			if reordered != !reflect.DeepEqual(shuffled, rsvs) {
+				t.Errorf("%v inputs: wrong reordered flag %v", count, reordered)
+			}
+		}
+
+		wrong := append([]string(nil), rsvs...)
+		wrong[count-1] = sign(otherKey, msgHashes[count-1])
+		if _, _, err := tokens.MatchSignaturesToMsgHashes(wrong, msgHashes, pkData); err == nil {
+			t.Errorf("%v inputs: want error if signature of other key", count)
+		}
+		if _, _, err := tokens.MatchSignaturesToMsgHashes(rsvs[1:], msgHashes, pkData); !errors.Is(err, tokens.ErrWrongCountOfMsgHashes) {
+			t.Errorf("%v inputs: want wrong count error, have %v", count, err)
+		}
+	}
+
+	// same msg hash of multiple inputs
+	msgHash := common.ToHex(crypto.Keccak256([]byte("same")))
+	rsv := sign(privKey, msgHash)
+	ordered, reordered, err := tokens.MatchSignaturesToMsgHashes([]string{rsv, rsv}, []string{msgHash, msgHash}, pkData)
+	if err != nil || reordered || len(ordered) != 2 {
+		t.Errorf("match duplicate msg hashes failed: %v %v %v", ordered, reordered, err)
+	}
+}
+
+func TestPublicKeyToAddressOfBridges(t *testing.T) {
+	mainnet := &tokens.ChainConfig{NetID: "mainnet"}
+
+	btcBridge := btc.NewCrossChainBridge(true)
+	btcBridge.ChainConfig = mainnet
+	ltcBridge := ltc.NewCrossChainBridge(true)
+	ltcBridge.ChainConfig = mainnet
+	blockBridge := block.NewCrossChainBridge(true)
+	blockBridge.ChainConfig = mainnet
+	colxBridge := colx.NewCrossChainBridge(true)
+	colxBridge.ChainConfig = mainnet
+	ethBridge := eth.NewCrossChainBridge(true)
+	rippleBridge := ripple.NewCrossChainBridge(true)
+
+	cases := []struct {
+		name    string
+		deriver tokens.AddressDeriver
+		address string // empty means only check both forms derive the same address
+	}{
+		{"btc", btcBridge, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"},
+		{"ltc", ltcBridge, "LVuDpNCSSj6pQ7t9Pv6d6sUkLKoqDEVUnJ"},
+		{"block", blockBridge, "BeTx9ye6pkuYQapErgmcS7VSVE95Wwkeiz"},
+		{"colx", colxBridge, ""},
+		{"eth", ethBridge, "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf"},
+		{"ripple", rippleBridge, "rBgGZ9tc4him9KBzD8fKFiQz3fSZpaSwMH"},
+	}
+	for _, c := range cases {
+		fromCompressed, err := c.deriver.PublicKeyToAddress(testCompressedPubkey)
+		if err != nil {
+			t.Errorf("%v: derive from compressed public key failed: %v", c.name, err)
+			continue
+		}
+		fromUncompressed, err := c.deriver.PublicKeyToAddress(testUncompressedPubkey)
+		if err != nil {
+			t.Errorf("%v: derive from uncompressed public key failed: %v", c.name, err)
+			continue
+		}
+		if fromCompressed != fromUncompressed {
+			t.Errorf("%v: compressed address %v and uncompressed address %v mismatch", c.name, fromCompressed, fromUncompressed)
+		}
+		if c.address != "" && fromCompressed != c.address {
+			t.Errorf("%v: want address %v, have %v", c.name, c.address, fromCompressed)
+		}
+	}
+}
+
+type nonDeriverBridge struct {
+	tokens.CrossChainBridge
+}
+
+func TestVerifyDcrmAddressOfPubkey(t *testing.T) {
+	mainnet := &tokens.ChainConfig{NetID: "mainnet"}
+
+	btcBridge := btc.NewCrossChainBridge(true)
+	btcBridge.ChainConfig = mainnet
+	ltcBridge := ltc.NewCrossChainBridge(true)
+	ltcBridge.ChainConfig = mainnet
+	blockBridge := block.NewCrossChainBridge(true)
+	blockBridge.ChainConfig = mainnet
+	colxBridge := colx.NewCrossChainBridge(true)
+	colxBridge.ChainConfig = mainnet
+
+	bridges := map[string]tokens.CrossChainBridge{
+		"btc":    btcBridge,
+		"ltc":    ltcBridge,
+		"block":  blockBridge,
+		"colx":   colxBridge,
+		"eth":    eth.NewCrossChainBridge(true),
+		"etc":    etc.NewCrossChainBridge(true),
+		"fsn":    fsn.NewCrossChainBridge(true),
+		"okex":   okex.NewCrossChainBridge(true),
+		"kusama": kusama.NewCrossChainBridge(true),
+		"ripple": ripple.NewCrossChainBridge(true),
+	}
+	otherPubkey := "02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5" // of private key 2
+	for name, bridge := range bridges {
+		address, err := bridge.(tokens.AddressDeriver).PublicKeyToAddress(testUncompressedPubkey)
+		if err != nil {
+			t.Errorf("%v: derive address failed: %v", name, err)
+			continue
+		}
+		tokenCfg := &tokens.TokenConfig{DcrmAddress: address, DcrmPubkey: testUncompressedPubkey}
+		if err = tokens.VerifyDcrmAddressOfPubkey(bridge, tokenCfg); err != nil {
+			t.Errorf("%v: verify matched dcrm address failed: %v", name, err)
+		}
+		tokenCfg.DcrmPubkey = otherPubkey
+		if err = tokens.VerifyDcrmAddressOfPubkey(bridge, tokenCfg); err == nil {
+			t.Errorf("%v: verify mismatched dcrm address should fail", name)
+		}
+		tokenCfg.DcrmPubkey = ""
+		if err = tokens.VerifyDcrmAddressOfPubkey(bridge, tokenCfg); err == nil {
+			t.Errorf("%v: verify dcrm address without public key should fail", name)
+		}
+	}
+
+	tokenCfg := &tokens.TokenConfig{DcrmAddress: "address", DcrmPubkey: testUncompressedPubkey}
+	if err := tokens.VerifyDcrmAddressOfPubkey(&nonDeriverBridge{}, tokenCfg); err == nil {
+		t.Errorf("verify with bridge not deriving address should fail")
+	}
+	tokenCfg.DcrmAddressPriKey = "private key"
+	if err := tokens.VerifyDcrmAddressOfPubkey(&nonDeriverBridge{}, tokenCfg); err != nil {
+		t.Errorf("verify should be skipped if signed by private key, have %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package tokens
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestCalcRefundValue(t *testing.T) {
+	oldPairsConfig := tokenPairsConfig
+	defer func() { tokenPairsConfig = oldPairsConfig }()
+
+	tokenPairsConfig = map[string]*TokenPairConfig{
+		"eth2fsn": {
+			PairID:    "ETH2FSN",
+			SrcToken:  &TokenConfig{refundFee: big.NewInt(1000)},
+			DestToken: &TokenConfig{},
+		},
+	}
+
+	cases := []struct {
+		pairID string
+		value  int64
+		isSrc  bool
+		want   int64
+	}{
+		{"ETH2FSN", 5000, true, 4000},
+		{"ETH2FSN", 1000, true, 0},
+		{"ETH2FSN", 500, true, 0},
+		{"ETH2FSN", 5000, false, 5000},
+		{"ETH2FSN", 0, false, 0},
+		{"notexist", 5000, true, 0},
+	}
+	for _, c := range cases {
+		have := CalcRefundValue(c.pairID, big.NewInt(c.value), c.isSrc)
+		if have.Int64() != c.want {
+			t.Errorf("CalcRefundValue(%v, %v, %v) want %v, have %v", c.pairID, c.value, c.isSrc, c.want, have)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// registrableVerifyErrors verify errors which can be configured to register swap,
+// transient errors (eg. ErrTxNotFound, ErrRPCQueryError) are never registrable.
+var registrableVerifyErrors = map[string]error{
+	"ErrTxWithWrongMemo":       ErrTxWithWrongMemo,
+	"ErrTxWithWrongValue":      ErrTxWithWrongValue,
+	"ErrTxSenderNotRegistered": ErrTxSenderNotRegistered,
+	"ErrBindAddrIsContract":    ErrBindAddrIsContract,
+	"ErrTxWithWrongReceiver":   ErrTxWithWrongReceiver,
+	"ErrTxWithWrongContract":   ErrTxWithWrongContract,
+	"ErrTxWithWrongInput":      ErrTxWithWrongInput,
+	"ErrTxWithWrongLogData":    ErrTxWithWrongLogData,
+	"ErrTxWithWrongSender":     ErrTxWithWrongSender,
+	"ErrTxWithWrongStatus":     ErrTxWithWrongStatus,
+	"ErrTxWithNoPayment":       ErrTxWithNoPayment,
+	"ErrTxFuncHashMismatch":    ErrTxFuncHashMismatch,
+	"ErrWrongP2shBindAddress":  ErrWrongP2shBindAddress,
+	"ErrWrongMemoBindAddress":  ErrWrongMemoBindAddress,
+	"ErrBindAddressMismatch":   ErrBindAddressMismatch,
+	"ErrTxBeforeInitialHeight": ErrTxBeforeInitialHeight,
+	"ErrAddressIsInBlacklist":  ErrAddressIsInBlacklist,
+	"ErrSwappedValueIsDust":    ErrSwappedValueIsDust,
+}
+
+// defaultRegisterSwapErrors verify errors which register swap by default
+var defaultRegisterSwapErrors = map[string]bool{
+	"ErrTxWithWrongMemo":       true,
+	"ErrTxWithWrongValue":      true,
+	"ErrTxSenderNotRegistered": true,
+	"ErrBindAddrIsContract":    true,
+	"ErrSwappedValueIsDust":    true,
+}
+
+// registerSwapErrors effective table, key is error name
+var registerSwapErrors = defaultRegisterSwapErrors
+
+// RegisterErrorEntry entry of register swap error table
+type RegisterErrorEntry struct {
+	Name     string `json:"name"`
+	Error    string `json:"error"`
+	Register bool   `json:"register"`
+	Default  bool   `json:"default"`
+}
+
+// SetRegisterSwapErrors override the default register swap error table,
+// key is error name (eg. ErrTxWithWrongValue), value is whether to register.
+func SetRegisterSwapErrors(overrides map[string]bool) error {
+	table := make(map[string]bool, len(defaultRegisterSwapErrors)+len(overrides))
+	for name, register := range defaultRegisterSwapErrors {
+		table[name] = register
+	}
+	for name, register := range overrides {
+		if _, exist := registrableVerifyErrors[name]; !exist {
+			return fmt.Errorf("unknown or not registrable verify error '%v' in 'RegisterSwapErrors'", name)
+		}
+		table[name] = register
+	}
+	registerSwapErrors = table
+	return nil
+}
+
+// GetRegisterErrorTable get the effective register swap error table
+func GetRegisterErrorTable() []*RegisterErrorEntry {
+	entries := make([]*RegisterErrorEntry, 0, len(registrableVerifyErrors))
+	for name, err := range registrableVerifyErrors {
+		entries = append(entries, &RegisterErrorEntry{
+			Name:     name,
+			Error:    err.Error(),
+			Register: registerSwapErrors[name],
+			Default:  defaultRegisterSwapErrors[name],
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// ShouldRegisterSwapForError return true if this error should record in database
+func ShouldRegisterSwapForError(err error) bool {
+	if err == nil {
+		return true
+	}
+	for name, register := range registerSwapErrors {
+		if register && errors.Is(err, registrableVerifyErrors[name]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package tokens
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSetRegisterSwapErrors(t *testing.T) {
+	defer func() { registerSwapErrors = defaultRegisterSwapErrors }()
+
+	wrappedValueErr := fmt.Errorf("%w: value too small", ErrTxWithWrongValue)
+	if !ShouldRegisterSwapForError(nil) || !ShouldRegisterSwapForError(wrappedValueErr) ||
+		ShouldRegisterSwapForError(ErrAddressIsInBlacklist) || ShouldRegisterSwapForError(ErrTxNotFound) {
+		t.Fatal("default register swap error table mismatch")
+	}
+
+	err := SetRegisterSwapErrors(map[string]bool{
+		"ErrTxWithWrongValue":     false,
+		"ErrAddressIsInBlacklist": true,
+	})
+	if err != nil {
+		t.Fatalf("set register swap errors failed: %v", err)
+	}
+	if ShouldRegisterSwapForError(wrappedValueErr) || !ShouldRegisterSwapForError(ErrAddressIsInBlacklist) ||
+		!ShouldRegisterSwapForError(ErrTxWithWrongMemo) {
+		t.Fatal("overridden register swap error table mismatch")
+	}
+	if defaultRegisterSwapErrors["ErrTxWithWrongValue"] != true {
+		t.Fatal("default register swap error table is modified")
+	}
+
+	for _, name := range []string{"ErrTxNotFound", "ErrRPCQueryError", "ErrNoSuchError"} {
+		if err := SetRegisterSwapErrors(map[string]bool{name: true}); err == nil {
+			t.Errorf("set not registrable error %v should fail", name)
+		}
+	}
+}
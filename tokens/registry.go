@@ -0,0 +1,62 @@
+package tokens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BridgeConstructor construct bridge of source or destination endpoint
+type BridgeConstructor func(isSrc bool) CrossChainBridge
+
+var (
+	bridgeConstructors     = make(map[string]BridgeConstructor) // key is upper case chain name
+	bridgeConstructorsLock sync.RWMutex
+)
+
+// RegisterBridgeConstructor register bridge constructor of chain name,
+// config 'BlockChain' starts with the chain name (ignore case) use this constructor.
+// call it in package init of external bridges, before loading config.
+func RegisterBridgeConstructor(chainName string, constructor BridgeConstructor) {
+	name := strings.ToUpper(chainName)
+	if name == "" || constructor == nil {
+		panic("register bridge constructor with empty chain name or constructor")
+	}
+	bridgeConstructorsLock.Lock()
+	defer bridgeConstructorsLock.Unlock()
+	if _, exist := bridgeConstructors[name]; exist {
+		panic(fmt.Sprintf("bridge constructor of %v is already registered", name))
+	}
+	bridgeConstructors[name] = constructor
+}
+
+// GetBridgeConstructor get registered bridge constructor of block chain,
+// the longest registered chain name prefix matches.
+func GetBridgeConstructor(blockChain string) BridgeConstructor {
+	iden := strings.ToUpper(blockChain)
+	bridgeConstructorsLock.RLock()
+	defer bridgeConstructorsLock.RUnlock()
+	var matched string
+	for name := range bridgeConstructors {
+		if strings.HasPrefix(iden, name) && len(name) > len(matched) {
+			matched = name
+		}
+	}
+	if matched == "" {
+		return nil
+	}
+	return bridgeConstructors[matched]
+}
+
+// GetRegisteredChainNames get registered chain names
+func GetRegisteredChainNames() []string {
+	bridgeConstructorsLock.RLock()
+	defer bridgeConstructorsLock.RUnlock()
+	names := make([]string, 0, len(bridgeConstructors))
+	for name := range bridgeConstructors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,38 @@
+package tokens
+
+import "testing"
+
+func TestGetBridgeConstructor(t *testing.T) {
+	var built string
+	constructorOf := func(name string) BridgeConstructor {
+		return func(isSrc bool) CrossChainBridge {
+			built = name
+			return nil
+		}
+	}
+	RegisterBridgeConstructor("testchain", constructorOf("TESTCHAIN"))
+	RegisterBridgeConstructor("TestChainClassic", constructorOf("TESTCHAINCLASSIC"))
+
+	tests := map[string]string{
+		"TestChain":         "TESTCHAIN",
+		"testchain-testnet": "TESTCHAIN",
+		"TESTCHAINCLASSIC":  "TESTCHAINCLASSIC", // longest prefix matches
+		"unknown":           "",
+	}
+	for blockChain, want := range tests {
+		built = ""
+		if constructor := GetBridgeConstructor(blockChain); constructor != nil {
+			constructor(true)
+		}
+		if built != want {
+			t.Errorf("block chain %v use constructor of %v, want %v", blockChain, built, want)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("duplicate registration should panic")
+		}
+	}()
+	RegisterBridgeConstructor("TESTCHAIN", constructorOf("dup"))
+}
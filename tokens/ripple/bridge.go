@@ -1,6 +1,7 @@
 package ripple
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
@@ -29,6 +30,11 @@ type Bridge struct {
 	Remotes map[string]*websockets.Remote
 }
 
+func init() {
+	newBridge := func(isSrc bool) tokens.CrossChainBridge { return NewCrossChainBridge(isSrc) }
+	tokens.RegisterBridgeConstructor("XRP", newBridge)
+}
+
 // NewCrossChainBridge new bridge
 func NewCrossChainBridge(isSrc bool) *Bridge {
 	tokens.IsSwapoutToStringAddress = true
@@ -58,7 +64,7 @@ func (b *Bridge) InitRemotes() {
 		}
 	}
 	b.Remotes = make(map[string]*websockets.Remote)
-	for _, apiAddress := range b.GetGatewayConfig().APIAddress {
+	for _, apiAddress := range b.GetGatewayConfig().GetAPIAddress() {
 		remote, err := websockets.NewRemote(apiAddress)
 		if err != nil || remote == nil {
 			log.Warn("Cannot connect to ripple", "address", apiAddress, "error", err)
@@ -148,7 +154,7 @@ func (b *Bridge) InitLatestBlockNumber() {
 			break
 		}
 		log.Error("get latst block number failed.", "BlockChain", chainCfg.BlockChain, "NetID", chainCfg.NetID, "err", err)
-		log.Println("retry query gateway", gatewayCfg.APIAddress)
+		log.Println("retry query gateway", gatewayCfg.GetAPIAddress())
 		time.Sleep(3 * time.Second)
 	}
 }
@@ -225,10 +231,13 @@ func (b *Bridge) GetTransaction(txHash string) (tx interface{}, err error) {
 }
 
 // GetTransactionStatus impl
-func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, err error) {
-	status = new(tokens.TxStatus)
+func (b *Bridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
 	tx, err := b.GetTransaction(txHash)
 	if err != nil {
+		var cmdErr *websockets.CommandError
+		if errors.As(err, &cmdErr) && cmdErr.Name == "txnNotFound" {
+			return nil, fmt.Errorf("%w: %v", tokens.ErrTxNotFound, txHash)
+		}
 		return nil, err
 	}
 
@@ -239,20 +248,22 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 		return nil, errTxResultType
 	}
 
+	if !txres.Validated {
+		return &tokens.TxStatus{}, nil // pending
+	}
+
 	// Check tx status
 	if !txres.TransactionWithMetaData.MetaData.TransactionResult.Success() {
 		log.Warn("Ripple tx status is not success", "result", txres.TransactionWithMetaData.MetaData.TransactionResult)
 		return nil, tokens.ErrTxWithWrongStatus
 	}
 
-	status.Receipt = nil
-	inledger := txres.LedgerSequence
-	status.BlockHeight = uint64(inledger)
-
-	if latest, err := b.GetLatestBlockNumber(); err == nil && latest > uint64(inledger) {
-		status.Confirmations = latest - uint64(inledger)
+	inledger := uint64(txres.LedgerSequence)
+	status := &tokens.TxStatus{BlockHeight: inledger}
+	if latest, err := b.GetLatestBlockNumber(); err == nil && latest > inledger {
+		status.Confirmations = latest - inledger
 	}
-	return
+	return status, nil
 }
 
 // GetBlockHash gets block hash
@@ -110,8 +110,8 @@ func getPaymentAmount(amount *big.Int, token *tokens.TokenConfig) (*data.Amount,
 		return nil, fmt.Errorf("non exist currency %v", token.RippleExtra.Currency)
 	}
 
-	if !amount.IsInt64() {
-		return nil, fmt.Errorf("amount value %v is overflow of type int64", amount)
+	if err := tokens.CheckAmount(amount, tokens.Int64AmountSpec.WithDecimals(*token.Decimals), nil); err != nil {
+		return nil, err
 	}
 
 	if currency.IsNative() { // native XRP
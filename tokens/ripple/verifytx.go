@@ -84,7 +84,7 @@ func (b *Bridge) verifySwapinTxWithPairID(pairID, txHash string, allowUnstable b
 			return swapInfo, errf
 		}
 
-		if h < uint64(txres.TransactionWithMetaData.LedgerSequence)+*b.GetChainConfig().Confirmations {
+		if h < uint64(txres.TransactionWithMetaData.LedgerSequence)+tokens.GetPairStableConfirmations(pairID, b.IsSrc) {
 			return swapInfo, tokens.ErrTxNotStable
 		}
 		if h < *b.ChainConfig.InitialHeight {
@@ -1,8 +1,10 @@
 package tokens
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/anyswap/CrossChain-Bridge/internal/retry"
 	"github.com/anyswap/CrossChain-Bridge/rpc/client"
 )
 
@@ -14,24 +16,47 @@ func WrapRPCQueryError(err error, method string, params ...interface{}) error {
 	return fmt.Errorf("%w: call '%s %v' failed, err='%v'", ErrRPCQueryError, method, params, err)
 }
 
+// IsRetryableRPCError transport errors are retryable, json rpc errors are not
+func IsRetryableRPCError(err error) bool {
+	return !client.IsJSONRPCError(err)
+}
+
 // RPCCall common RPC calling
 func RPCCall(result interface{}, urls []string, method string, params ...interface{}) (err error) {
-	for _, url := range urls {
-		err = client.RPCPost(&result, url, method, params...)
-		if err == nil {
-			return nil
+	if len(urls) == 0 {
+		return WrapRPCQueryError(nil, method, params...)
+	}
+	err = retry.Do(context.Background(), "rpc."+method, retry.GetPolicy(retry.SubsystemRPC), IsRetryableRPCError, func() (err error) {
+		for _, url := range urls {
+			err = client.RPCPost(&result, url, method, params...)
+			if err == nil {
+				return nil
+			}
 		}
+		return err
+	})
+	if err != nil {
+		return WrapRPCQueryError(err, method, params...)
 	}
-	return WrapRPCQueryError(err, method, params...)
+	return nil
 }
 
 // RPCCallWithTimeout common RPC calling with specified timeout
 func RPCCallWithTimeout(timeout int, result interface{}, urls []string, method string, params ...interface{}) (err error) {
-	for _, url := range urls {
-		err = client.RPCPostWithTimeout(timeout, &result, url, method, params...)
-		if err == nil {
-			return nil
+	if len(urls) == 0 {
+		return WrapRPCQueryError(nil, method, params...)
+	}
+	err = retry.Do(context.Background(), "rpc."+method, retry.GetPolicy(retry.SubsystemRPC), IsRetryableRPCError, func() (err error) {
+		for _, url := range urls {
+			err = client.RPCPostWithTimeout(timeout, &result, url, method, params...)
+			if err == nil {
+				return nil
+			}
 		}
+		return err
+	})
+	if err != nil {
+		return WrapRPCQueryError(err, method, params...)
 	}
-	return WrapRPCQueryError(err, method, params...)
+	return nil
 }
@@ -0,0 +1,74 @@
+package tokens
+
+import (
+	"math/big"
+)
+
+// MaxPayoutLegs max count of txs a payout can be split into
+const MaxPayoutLegs = 20
+
+// GetPayoutCapPerTx get per tx cap of split payout token, it's the tighter of
+// 'MaximumPayoutPerTx' and mint cap. nil if token does not split payout.
+func GetPayoutCapPerTx(pairID string, isSrc bool) *big.Int {
+	token := GetTokenConfig(pairID, isSrc)
+	if token == nil || !token.SplitPayout {
+		return nil
+	}
+	payoutCap := token.maxPayoutPerTx
+	if mintCap := getMintCap(pairID, isSrc); mintCap != nil && (payoutCap == nil || mintCap.Cmp(payoutCap) < 0) {
+		payoutCap = mintCap
+	}
+	return payoutCap
+}
+
+// GetPayoutLegCount get count of txs to pay out value, 1 if no need to split
+func GetPayoutLegCount(pairID string, isSrc bool, value *big.Int) (int, error) {
+	return calcPayoutLegCount(value, GetPayoutCapPerTx(pairID, isSrc))
+}
+
+func calcPayoutLegCount(value, payoutCap *big.Int) (int, error) {
+	if payoutCap == nil || value == nil || value.Cmp(payoutCap) <= 0 {
+		return 1, nil
+	}
+	if payoutCap.Sign() <= 0 {
+		return 0, ErrPayoutExceedsCap
+	}
+	count := new(big.Int).Add(value, payoutCap)
+	count.Sub(count, big.NewInt(1))
+	count.Div(count, payoutCap)
+	if !count.IsInt64() || count.Int64() > MaxPayoutLegs {
+		return 0, ErrPayoutExceedsCap
+	}
+	return int(count.Int64()), nil
+}
+
+// splitPayoutValue split total value equally, the remainder is added to the
+// leading legs one by one, so the legs always sum to the total.
+func splitPayoutValue(total *big.Int, leg *PayoutLeg) *big.Int {
+	count := big.NewInt(int64(leg.Count))
+	value, remainder := new(big.Int).DivMod(total, count, new(big.Int))
+	if big.NewInt(int64(leg.Index)).Cmp(remainder) < 0 {
+		value.Add(value, big.NewInt(1))
+	}
+	return value
+}
+
+// CalcPayoutLegValue get the value paid by payout leg of total swap value,
+// paying over the per tx cap in one tx is refused as it would revert on chain.
+func CalcPayoutLegValue(pairID string, isSrc bool, total *big.Int, leg *PayoutLeg) (*big.Int, error) {
+	payoutCap := GetPayoutCapPerTx(pairID, isSrc)
+	if leg == nil {
+		if payoutCap != nil && total.Cmp(payoutCap) > 0 {
+			return nil, ErrPayoutExceedsCap
+		}
+		return total, nil
+	}
+	if payoutCap == nil || leg.Count < 2 || leg.Count > MaxPayoutLegs || leg.Index < 0 || leg.Index >= leg.Count {
+		return nil, ErrWrongPayoutLeg
+	}
+	value := splitPayoutValue(total, leg)
+	if value.Cmp(payoutCap) > 0 {
+		return nil, ErrPayoutExceedsCap
+	}
+	return value, nil
+}
@@ -0,0 +1,91 @@
+package tokens
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestCalcPayoutLegCount(t *testing.T) {
+	payoutCap := big.NewInt(100)
+	cases := []struct {
+		value int64
+		count int
+	}{
+		{50, 1}, {100, 1}, {101, 2}, {200, 2}, {201, 3}, {2000, MaxPayoutLegs},
+	}
+	for _, c := range cases {
+		count, err := calcPayoutLegCount(big.NewInt(c.value), payoutCap)
+		if err != nil || count != c.count {
+			t.Errorf("value %v want %v legs, have %v (err %v)", c.value, c.count, count, err)
+		}
+	}
+	if _, err := calcPayoutLegCount(big.NewInt(2001), payoutCap); err == nil {
+		t.Errorf("too many legs should fail")
+	}
+	if count, _ := calcPayoutLegCount(big.NewInt(1000), nil); count != 1 {
+		t.Errorf("no cap should not split, have %v", count)
+	}
+}
+
+func TestCalcPayoutLegValue(t *testing.T) {
+	oldPairsConfig, oldMintCaps := tokenPairsConfig, mintCaps
+	defer func() { tokenPairsConfig, mintCaps = oldPairsConfig, oldMintCaps }()
+
+	decimals, zeroRate := uint8(0), 0.0
+	tokenPairsConfig = map[string]*TokenPairConfig{
+		"usdt": {
+			PairID: "USDT",
+			SrcToken: &TokenConfig{
+				Decimals:    &decimals,
+				SwapFeeRate: &zeroRate,
+				minSwap:     big.NewInt(1),
+				maxSwap:     big.NewInt(10000),
+			},
+			DestToken: &TokenConfig{
+				Decimals:       &decimals,
+				SwapFeeRate:    &zeroRate,
+				SplitPayout:    true,
+				maxPayoutPerTx: big.NewInt(100),
+			},
+		},
+	}
+	mintCaps = map[string]*big.Int{getMintCapKey("USDT", false): big.NewInt(40)}
+
+	if payoutCap := GetPayoutCapPerTx("usdt", false); payoutCap.Int64() != 40 {
+		t.Fatalf("want payout cap tightened by mint cap, have %v", payoutCap)
+	}
+	if swapped := CalcSwappedValue("usdt", big.NewInt(101), true, "0xfrom", ""); swapped.Int64() != 101 {
+		t.Errorf("value over mint cap should be swapped in split payout, have %v", swapped)
+	}
+	if swapped := CalcSwappedValue("usdt", big.NewInt(40*MaxPayoutLegs+1), true, "0xfrom", ""); swapped.Sign() != 0 {
+		t.Errorf("value over cap of all legs should not be swapped, have %v", swapped)
+	}
+
+	total := big.NewInt(101)
+	if _, err := CalcPayoutLegValue("usdt", false, total, nil); err == nil {
+		t.Errorf("paying over cap without leg should fail")
+	}
+	count, _ := GetPayoutLegCount("usdt", false, total)
+	if count != 3 {
+		t.Fatalf("want 3 legs, have %v", count)
+	}
+	sum := new(big.Int)
+	for i, want := range []int64{34, 34, 33} {
+		value, err := CalcPayoutLegValue("usdt", false, total, &PayoutLeg{Index: i, Count: count})
+		if err != nil || value.Int64() != want {
+			t.Errorf("leg %v want %v, have %v (err %v)", i, want, value, err)
+		}
+		sum.Add(sum, value)
+	}
+	if sum.Cmp(total) != 0 {
+		t.Errorf("legs should sum to total, have %v", sum)
+	}
+	for _, leg := range []*PayoutLeg{{Index: 3, Count: 3}, {Index: 0, Count: 1}, {Index: 0, Count: 2}} {
+		if _, err := CalcPayoutLegValue("usdt", false, total, leg); err == nil {
+			t.Errorf("wrong leg %v should fail", leg)
+		}
+	}
+	if _, err := CalcPayoutLegValue("usdt", true, total, &PayoutLeg{Index: 0, Count: 3}); err == nil {
+		t.Errorf("leg of token not splitting payout should fail")
+	}
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+var (
+	errEmptyGateway = errors.New("reload gateway with no available api address")
+
+	reloadGatewayLock sync.Mutex
+)
+
+// GatewayReloadResult gateway reload result
+type GatewayReloadResult struct {
+	IsSrc   bool              `json:"isSrc"`
+	Kept    []string          `json:"kept"`
+	Added   []string          `json:"added"`
+	Removed []string          `json:"removed"`
+	Failed  map[string]string `json:"failed,omitempty"` // unhealthy new endpoints
+}
+
+// ReloadGateway reload api addresses of gateway.
+// new endpoints are health checked before use, remained endpoints keep their order,
+// removed endpoints are dropped from the list but in-flight calls can still finish.
+func ReloadGateway(isSrc bool, latest *tokens.GatewayConfig) (*GatewayReloadResult, error) {
+	reloadGatewayLock.Lock()
+	defer reloadGatewayLock.Unlock()
+
+	if err := SetGatewayProxy(latest); err != nil {
+		return nil, err
+	}
+	bridge := tokens.GetCrossChainBridge(isSrc)
+	result, err := reloadGatewayAddresses(bridge.GetGatewayConfig(), latest, bridge.GetLatestBlockNumberOf)
+	if err != nil {
+		return nil, err
+	}
+	result.IsSrc = isSrc
+	log.Info("reload gateway success", "isSrc", isSrc, "kept", result.Kept, "added", result.Added, "removed", result.Removed, "failed", result.Failed)
+	return result, nil
+}
+
+// SetGatewayProxy set proxy of gateway api addresses,
+// clear previous proxy of them if proxy is not configured (eg. removed by reload)
+func SetGatewayProxy(gateway *tokens.GatewayConfig) error {
+	addresses := append(append([]string{}, gateway.GetAPIAddress()...), gateway.GetAPIAddressExt()...)
+	if gateway.WebsocketAddress != "" {
+		addresses = append(addresses, gateway.WebsocketAddress)
+	}
+	proxy := params.GetGatewayProxy(gateway)
+	if proxy == "" {
+		client.ClearProxy(addresses)
+		return nil
+	}
+	if err := client.SetProxy(addresses, proxy); err != nil {
+		return err
+	}
+	log.Info("set gateway proxy", "proxy", client.RedactProxy(proxy), "addresses", addresses)
+	return nil
+}
+
+func reloadGatewayAddresses(gateway, latest *tokens.GatewayConfig, healthCheck func(url string) (uint64, error)) (*GatewayReloadResult, error) {
+	result := &GatewayReloadResult{}
+	apiAddress := mergeGatewayAddresses(gateway.GetAPIAddress(), latest.APIAddress, healthCheck, result)
+	if len(apiAddress) == 0 {
+		return nil, errEmptyGateway
+	}
+	apiAddressExt := mergeGatewayAddresses(gateway.GetAPIAddressExt(), latest.APIAddressExt, healthCheck, result)
+
+	// replace the slices, so that in-flight calls iterating the old slices are not affected
+	gateway.SetAPIAddress(apiAddress, apiAddressExt)
+	return result, nil
+}
+
+func mergeGatewayAddresses(current, latest []string, healthCheck func(url string) (uint64, error), result *GatewayReloadResult) []string {
+	latestSet := make(map[string]struct{}, len(latest))
+	for _, url := range latest {
+		latestSet[url] = struct{}{}
+	}
+	currentSet := make(map[string]struct{}, len(current))
+	merged := make([]string, 0, len(latest))
+	for _, url := range current {
+		currentSet[url] = struct{}{}
+		if _, exist := latestSet[url]; exist {
+			merged = append(merged, url)
+			result.Kept = append(result.Kept, url)
+		} else {
+			result.Removed = append(result.Removed, url)
+		}
+	}
+	var added WeightedStringSlice
+	for _, url := range latest {
+		if _, exist := currentSet[url]; exist {
+			continue
+		}
+		currentSet[url] = struct{}{} // ignore duplicates
+		height, err := healthCheck(url)
+		if err == nil && height == 0 {
+			err = errors.New("zero block height")
+		}
+		if err != nil {
+			if result.Failed == nil {
+				result.Failed = make(map[string]string)
+			}
+			result.Failed[url] = err.Error()
+			continue
+		}
+		added = added.Add(url, height)
+		result.Added = append(result.Added, url)
+	}
+	return append(merged, added.Sort().GetStrings()...)
+}
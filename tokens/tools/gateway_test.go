@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func TestReloadGatewayDuringScan(t *testing.T) {
+	errUnreachable := errors.New("unreachable")
+	// endpoint "c" is removed by reload but still serves in-flight calls,
+	// endpoint "e" is newly added but unhealthy.
+	heights := map[string]uint64{"a": 100, "b": 100, "c": 100, "d": 120}
+	healthCheck := func(url string) (uint64, error) {
+		height, exist := heights[url]
+		if !exist {
+			return 0, errUnreachable
+		}
+		return height, nil
+	}
+
+	gateway := &tokens.GatewayConfig{
+		APIAddress:    []string{"a", "b", "c"},
+		APIAddressExt: []string{"c"},
+	}
+	latest := &tokens.GatewayConfig{
+		APIAddress:    []string{"b", "d", "e", "a"},
+		APIAddressExt: []string{"d"},
+	}
+
+	// scanners iterate the lists like the bridges do while reload happens,
+	// run with '-race' to detect unsynchronized access.
+	const scanners = 4
+	const scanBlocks = 1000
+	var wg sync.WaitGroup
+	scanErrs := make(chan error, scanners)
+	for i := 0; i < scanners; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for height := 0; height < scanBlocks; height++ {
+				urls := append(append([]string{}, gateway.GetAPIAddress()...), gateway.GetAPIAddressExt()...)
+				if len(urls) == 0 {
+					scanErrs <- errors.New("empty gateway during scan")
+					return
+				}
+				for _, url := range urls {
+					if _, err := healthCheck(url); err != nil {
+						scanErrs <- fmt.Errorf("scan call to %v failed at height %v: %w", url, height, err)
+						return
+					}
+				}
+			}
+		}()
+	}
+	reloadResult, err := reloadGatewayAddresses(gateway, latest, healthCheck)
+	wg.Wait()
+	close(scanErrs)
+	if err != nil {
+		t.Fatalf("reload gateway failed: %v", err)
+	}
+	for err := range scanErrs {
+		t.Error(err)
+	}
+
+	if want := []string{"a", "b", "d"}; !reflect.DeepEqual(gateway.GetAPIAddress(), want) {
+		t.Errorf("wrong api address after reload, want %v, have %v", want, gateway.GetAPIAddress())
+	}
+	if want := []string{"d"}; !reflect.DeepEqual(gateway.GetAPIAddressExt(), want) {
+		t.Errorf("wrong ext api address after reload, want %v, have %v", want, gateway.GetAPIAddressExt())
+	}
+	if _, exist := reloadResult.Failed["e"]; !exist || len(reloadResult.Failed) != 1 {
+		t.Errorf("unhealthy endpoint should be reported, have %v", reloadResult.Failed)
+	}
+	if want := []string{"c", "c"}; !reflect.DeepEqual(reloadResult.Removed, want) {
+		t.Errorf("wrong removed endpoints, want %v, have %v", want, reloadResult.Removed)
+	}
+}
+
+func TestReloadGatewayToEmpty(t *testing.T) {
+	gateway := &tokens.GatewayConfig{APIAddress: []string{"a"}}
+	latest := &tokens.GatewayConfig{APIAddress: []string{"x"}}
+	healthCheck := func(url string) (uint64, error) { return 0, errors.New("unreachable") }
+	if _, err := reloadGatewayAddresses(gateway, latest, healthCheck); !errors.Is(err, errEmptyGateway) {
+		t.Errorf("want error %v, have %v", errEmptyGateway, err)
+	}
+	if want := []string{"a"}; !reflect.DeepEqual(gateway.GetAPIAddress(), want) {
+		t.Errorf("gateway should not change on failed reload, have %v", gateway.GetAPIAddress())
+	}
+}
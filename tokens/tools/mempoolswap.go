@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/dcrm"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const mempoolDropPushTimeout = 60 // seconds
+
+// MempoolSwapDropNotice notice of dropped swapin which is registered in mempool
+type MempoolSwapDropNotice struct {
+	TxID      string `json:"txid"`
+	PairID    string `json:"pairid"`
+	Bind      string `json:"bind"`
+	Reason    string `json:"reason"` // evicted, double-spent or expired
+	SpentBy   string `json:"spentby,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// CanRegisterMempoolSwapin only swap server with mongodb registers mempool swapins
+func CanRegisterMempoolSwapin() bool {
+	return dcrm.IsSwapServer() && mongodb.HasClient()
+}
+
+// RegisterMempoolSwapin register swapin of unconfirmed tx with 'SeenInMempool' status,
+// returns true if it is newly registered.
+func RegisterMempoolSwapin(txid string, txType tokens.SwapTxType, swapInfo *tokens.TxSwapInfo) bool {
+	if !CanRegisterMempoolSwapin() || swapInfo.Bind == "" {
+		return false
+	}
+	swap := &mongodb.MgoSwap{
+		TxID:      txid,
+		PairID:    swapInfo.PairID,
+		TxTo:      swapInfo.TxTo,
+		TxType:    uint32(txType),
+		From:      swapInfo.From,
+		Bind:      swapInfo.Bind,
+		Status:    mongodb.SeenInMempool,
+		Timestamp: time.Now().Unix(),
+	}
+	if mongodb.AddSwapin(swap) != nil {
+		return false
+	}
+	log.Info("[mempool] register mempool swapin", "tx", txid, "pairID", swapInfo.PairID, "bind", swapInfo.Bind, "txType", txType)
+	return true
+}
+
+// UpgradeMempoolSwapin upgrade mined mempool swapin to be verified as usual
+func UpgradeMempoolSwapin(txid, pairID, bind string) error {
+	return mongodb.UpgradeMempoolSwapin(txid, pairID, bind)
+}
+
+// DropMempoolSwapin remove evicted or double spent mempool swapin,
+// and push the notice to 'pushURL' if it is removed.
+func DropMempoolSwapin(notice *MempoolSwapDropNotice, pushURL string) error {
+	removed, err := mongodb.RemoveMempoolSwapin(notice.TxID, notice.PairID, notice.Bind)
+	if err != nil || !removed {
+		return err
+	}
+	log.Warn("[mempool] drop mempool swapin", "tx", notice.TxID, "pairID", notice.PairID, "bind", notice.Bind, "reason", notice.Reason, "spentBy", notice.SpentBy)
+	if pushURL == "" {
+		return nil
+	}
+	resp, err := client.HTTPPost(pushURL, notice, nil, nil, mempoolDropPushTimeout)
+	if err == nil {
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("response status %v", resp.Status)
+		}
+	}
+	if err != nil {
+		log.Warn("[mempool] push mempool swapin drop notice failed", "tx", notice.TxID, "url", pushURL, "err", err)
+	}
+	return nil
+}
+
+// GetMempoolSwapins get registered mempool swapins in the past lifetime (seconds)
+func GetMempoolSwapins(lifetime int64) ([]*mongodb.MgoSwap, error) {
+	return mongodb.FindSwapinsWithStatus(mongodb.SeenInMempool, time.Now().Unix()-lifetime)
+}
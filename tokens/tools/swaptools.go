@@ -11,6 +11,7 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/rpc/client"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
+	rpcjson "github.com/gorilla/rpc/v2/json2"
 )
 
 var (
@@ -19,11 +20,11 @@ var (
 	swapRPCTimeout   = 60 // seconds
 )
 
-// IsSwapExist is swapin exist
+// IsSwapExist is swapin exist, multiple swaps matched by empty bind also exist
 func IsSwapExist(txid, pairID, bind string, isSwapin bool) bool {
 	if mongodb.HasClient() {
-		swap, _ := mongodb.FindSwap(isSwapin, txid, pairID, bind)
-		return swap != nil
+		swap, err := mongodb.FindSwap(isSwapin, txid, pairID, bind)
+		return swap != nil || errors.Is(err, mongodb.ErrSwapBindAmbiguous)
 	}
 	var result interface{}
 	var method string
@@ -42,11 +43,20 @@ func IsSwapExist(txid, pairID, bind string, isSwapin bool) bool {
 		if err == nil {
 			return result != nil
 		}
+		if isSwapBindAmbiguous(err) {
+			return true
+		}
 		time.Sleep(retryRPCInterval)
 	}
 	return false
 }
 
+// isSwapBindAmbiguous is ErrSwapBindAmbiguous returned by swap server
+func isSwapBindAmbiguous(err error) bool {
+	code, ok := client.GetJSONRPCErrorCode(err)
+	return ok && rpcjson.ErrorCode(code) == mongodb.ErrSwapBindAmbiguous.(*rpcjson.Error).Code
+}
+
 // RegisterSwapin register swapin
 func RegisterSwapin(txid string, swapInfos []*tokens.TxSwapInfo, verifyErrors []error) {
 	registerSwap(true, txid, swapInfos, verifyErrors)
@@ -152,7 +162,9 @@ func RegisterP2shSwapin(txid string, swapInfo *tokens.TxSwapInfo, verifyError er
 			Timestamp: time.Now().Unix(),
 			Memo:      memo,
 		}
-		_ = mongodb.AddSwapin(swap)
+		if mongodb.AddSwapin(swap) == nil {
+			_ = mongodb.UpdateP2shDeposit(bind)
+		}
 	} else {
 		args := map[string]interface{}{
 			"txid": txid,
@@ -258,10 +270,13 @@ func AdjustGatewayOrder(isSrc bool) {
 	var weightedAPIs WeightedStringSlice
 	bridge := tokens.GetCrossChainBridge(isSrc)
 	gateway := bridge.GetGatewayConfig()
-	length := len(gateway.APIAddress)
+
+	// do not overwrite the addresses replaced by a concurrent reload
+	reloadGatewayLock.Lock()
+	apiAddresses := gateway.GetAPIAddress()
 	maxHeight := uint64(0)
-	for i := length; i > 0; i-- { // query in reverse order
-		apiAddress := gateway.APIAddress[i-1]
+	for i := len(apiAddresses); i > 0; i-- { // query in reverse order
+		apiAddress := apiAddresses[i-1]
 		height, _ := bridge.GetLatestBlockNumberOf(apiAddress)
 		weightedAPIs = weightedAPIs.Add(apiAddress, height)
 		if height > maxHeight {
@@ -271,7 +286,8 @@ func AdjustGatewayOrder(isSrc bool) {
 	tokens.CmpAndSetLatestBlockHeight(maxHeight, isSrc)
 	weightedAPIs.Reverse() // reverse as iter in reverse order in the above
 	weightedAPIs = weightedAPIs.Sort()
-	gateway.APIAddress = weightedAPIs.GetStrings()
+	gateway.SetAPIAddress(weightedAPIs.GetStrings(), gateway.GetAPIAddressExt())
+	reloadGatewayLock.Unlock()
 	if isSrc {
 		log.Info("adjust source gateways", "result", weightedAPIs)
 	} else {
@@ -282,7 +298,7 @@ func AdjustGatewayOrder(isSrc bool) {
 		return
 	}
 
-	if len(gateway.APIAddressExt) == 0 {
+	if len(gateway.GetAPIAddressExt()) == 0 {
 		return
 	}
 
@@ -302,8 +318,8 @@ func AdjustGatewayOrder(isSrc bool) {
 	retrySleepInterval := 3 * time.Second
 	time.Sleep(retrySleepInterval)
 	for i := 1; i <= retryCount; i++ {
-		hash1, err1 := forkChecker.GetBlockHashOf(gateway.APIAddress, checkPointHeight)
-		hash2, err2 := forkChecker.GetBlockHashOf(gateway.APIAddressExt, checkPointHeight)
+		hash1, err1 := forkChecker.GetBlockHashOf(gateway.GetAPIAddress(), checkPointHeight)
+		hash2, err2 := forkChecker.GetBlockHashOf(gateway.GetAPIAddressExt(), checkPointHeight)
 		if err1 != nil || err2 != nil {
 			if i == retryCount {
 				log.Warn("[detect] get block hash failed", "height", checkPointHeight, "isSrc", isSrc, "count", i, "err1", err1, "err2", err2)
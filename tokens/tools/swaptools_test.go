@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+func TestIsSwapExistThroughServer(t *testing.T) {
+	responses := map[string]string{
+		"exist":     `{"jsonrpc":"2.0","id":1,"result":{"txid":"exist"}}`,
+		"ambiguous": `{"jsonrpc":"2.0","id":1,"error":{"code":-32019,"message":"mgoError: Multiple swaps match txid, specify bind"}}`,
+		"notfound":  `{"jsonrpc":"2.0","id":1,"error":{"code":-32011,"message":"mgoError: Swap is not found"}}`,
+	}
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(responses[r.URL.Query().Get("case")]))
+	}))
+	defer server.Close()
+
+	oldAddress, oldInterval := params.ServerAPIAddress, retryRPCInterval
+	defer func() { params.ServerAPIAddress, retryRPCInterval = oldAddress, oldInterval }()
+	retryRPCInterval = 0
+
+	cases := map[string]bool{"exist": true, "ambiguous": true, "notfound": false}
+	for name, want := range cases {
+		calls = 0
+		params.ServerAPIAddress = server.URL + "/rpc?case=" + name
+		if have := IsSwapExist(name, "pair", "", true); have != want {
+			t.Errorf("%v swap exist want %v, have %v", name, want, have)
+		}
+		if name == "ambiguous" && calls != 1 {
+			t.Errorf("ambiguous swap should not be retried, have %v calls", calls)
+		}
+	}
+}
@@ -56,6 +56,7 @@ type TxSwapInfo struct {
 	PairID    string   `json:"pairid"`
 	Hash      string   `json:"hash"`
 	Height    uint64   `json:"height"`
+	BlockHash string   `json:"blockhash,omitempty"`
 	Timestamp uint64   `json:"timestamp"`
 	From      string   `json:"from"`
 	TxTo      string   `json:"txto"`
@@ -71,6 +72,7 @@ type TxStatus struct {
 	BlockHeight   uint64      `json:"block_height"`
 	BlockHash     string      `json:"block_hash"`
 	BlockTime     uint64      `json:"block_time"`
+	Finalized     *bool       `json:"finalized,omitempty"`
 }
 
 // SwapInfo struct
@@ -82,6 +84,13 @@ type SwapInfo struct {
 	Bind       string     `json:"bind,omitempty"`
 	Identifier string     `json:"identifier,omitempty"`
 	Reswapping bool       `json:"reswapping,omitempty"`
+	PayoutLeg  *PayoutLeg `json:"payoutLeg,omitempty"`
+}
+
+// PayoutLeg one of the txs splitting a payout exceeding per tx cap
+type PayoutLeg struct {
+	Index int `json:"index"`
+	Count int `json:"count"`
 }
 
 // IsSwapin is swapin type
@@ -112,11 +121,12 @@ func (args *BuildTxArgs) GetReplaceNum() uint64 {
 	return 0
 }
 
-// GetExtraArgs get extra args
+// GetExtraArgs get extra args (sent to oracles when signing)
 func (args *BuildTxArgs) GetExtraArgs() *BuildTxArgs {
 	return &BuildTxArgs{
-		SwapInfo: args.SwapInfo,
-		Extra:    args.Extra,
+		SwapInfo:  args.SwapInfo,
+		SwapValue: args.SwapValue,
+		Extra:     args.Extra,
 	}
 }
 
@@ -181,4 +191,5 @@ type P2shAddressInfo struct {
 	P2shAddress        string
 	RedeemScript       string
 	RedeemScriptDisasm string
+	DisasmError        string `json:",omitempty"`
 }
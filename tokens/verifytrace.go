@@ -0,0 +1,31 @@
+package tokens
+
+// VerifyCheck result of one verification check
+type VerifyCheck struct {
+	Name  string `json:"name"`
+	Pass  bool   `json:"pass"`
+	Error string `json:"error,omitempty"`
+}
+
+// VerifyTrace records the checks run by verifier in order.
+// methods of nil trace are no-ops, so verifiers can always call them.
+type VerifyTrace struct {
+	Checks []*VerifyCheck `json:"checks"`
+}
+
+// Check record check passed if err is nil or failed otherwise, and return err
+func (t *VerifyTrace) Check(name string, err error) error {
+	if t != nil {
+		check := &VerifyCheck{Name: name, Pass: err == nil}
+		if err != nil {
+			check.Error = err.Error()
+		}
+		t.Checks = append(t.Checks, check)
+	}
+	return err
+}
+
+// Pass record check passed
+func (t *VerifyTrace) Pass(name string) {
+	_ = t.Check(name, nil)
+}
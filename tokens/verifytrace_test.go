@@ -0,0 +1,29 @@
+package tokens
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyTrace(t *testing.T) {
+	var nilTrace *VerifyTrace
+	if err := nilTrace.Check("receiver", ErrTxWithWrongReceiver); !errors.Is(err, ErrTxWithWrongReceiver) {
+		t.Errorf("nil trace should return the checked error, have %v", err)
+	}
+	nilTrace.Pass("pairID")
+
+	trace := &VerifyTrace{}
+	trace.Pass("pairID")
+	if err := trace.Check("receiver", ErrTxWithWrongReceiver); !errors.Is(err, ErrTxWithWrongReceiver) {
+		t.Errorf("trace should return the checked error, have %v", err)
+	}
+	if len(trace.Checks) != 2 {
+		t.Fatalf("want 2 checks, have %v", len(trace.Checks))
+	}
+	if c := trace.Checks[0]; c.Name != "pairID" || !c.Pass || c.Error != "" {
+		t.Errorf("wrong pass check %+v", c)
+	}
+	if c := trace.Checks[1]; c.Name != "receiver" || c.Pass || c.Error != ErrTxWithWrongReceiver.Error() {
+		t.Errorf("wrong fail check %+v", c)
+	}
+}
@@ -0,0 +1,143 @@
+// Package respsign provides signing and verifying of bridge api responses.
+package respsign
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+// ProofField json field name of proof in signed response
+const ProofField = "proof"
+
+var (
+	errNoProof          = errors.New("response has no proof")
+	errNoExpectedSigner = errors.New("expected signer is required")
+	errSignerMismatch   = errors.New("response signer mismatch")
+	errWrongSignature   = errors.New("wrong response signature")
+	errSignatureInvalid = errors.New("response signature is invalid")
+)
+
+// Proof response proof
+type Proof struct {
+	Identifier string `json:"identifier"`
+	Timestamp  int64  `json:"timestamp"`
+	Signer     string `json:"signer"`
+	Signature  string `json:"signature"`
+}
+
+type signMessage struct {
+	Payload    interface{} `json:"payload"`
+	Identifier string      `json:"identifier"`
+	Timestamp  int64       `json:"timestamp"`
+}
+
+// CanonicalJSON marshal payload to json with sorted object keys
+func CanonicalJSON(payload interface{}) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var obj interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&obj); err != nil {
+		return nil, err
+	}
+	return json.Marshal(obj)
+}
+
+// MessageHash calc message hash of payload, identifier and timestamp
+func MessageHash(payload interface{}, identifier string, timestamp int64) ([]byte, error) {
+	canonicalPayload, err := CanonicalJSON(payload)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := json.Marshal(&signMessage{
+		Payload:    json.RawMessage(canonicalPayload),
+		Identifier: identifier,
+		Timestamp:  timestamp,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(msg), nil
+}
+
+// Sign sign payload
+func Sign(payload interface{}, identifier string, timestamp int64, key *ecdsa.PrivateKey) (*Proof, error) {
+	hash, err := MessageHash(payload, identifier, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := crypto.Sign(hash, key)
+	if err != nil {
+		return nil, err
+	}
+	return &Proof{
+		Identifier: identifier,
+		Timestamp:  timestamp,
+		Signer:     crypto.PubkeyToAddress(key.PublicKey).String(),
+		Signature:  common.ToHex(sig),
+	}, nil
+}
+
+// Verify verify payload with proof and expected signer address,
+// the expected signer is required as anyone can sign a forged payload.
+func Verify(payload interface{}, proof *Proof, expectedSigner string) error {
+	if expectedSigner == "" {
+		return errNoExpectedSigner
+	}
+	if proof == nil {
+		return errNoProof
+	}
+	if !strings.EqualFold(proof.Signer, expectedSigner) {
+		return errSignerMismatch
+	}
+	sig := common.FromHex(proof.Signature)
+	if len(sig) != crypto.SignatureLength {
+		return errWrongSignature
+	}
+	hash, err := MessageHash(payload, proof.Identifier, proof.Timestamp)
+	if err != nil {
+		return err
+	}
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errSignatureInvalid, err)
+	}
+	if !strings.EqualFold(crypto.PubkeyToAddress(*pubKey).String(), proof.Signer) {
+		return errSignatureInvalid
+	}
+	return nil
+}
+
+// VerifyJSON verify a json object response which contains the 'proof' field
+func VerifyJSON(response []byte, expectedSigner string) (*Proof, error) {
+	if expectedSigner == "" {
+		return nil, errNoExpectedSigner
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(response, &obj); err != nil {
+		return nil, err
+	}
+	proofData, exist := obj[ProofField]
+	if !exist || string(proofData) == "null" {
+		return nil, errNoProof
+	}
+	var proof Proof
+	if err := json.Unmarshal(proofData, &proof); err != nil {
+		return nil, err
+	}
+	delete(obj, ProofField)
+	if err := Verify(obj, &proof, expectedSigner); err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
@@ -0,0 +1,60 @@
+package respsign
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+type testSwapInfo struct {
+	TxID   string `json:"txid"`
+	Value  string `json:"value"`
+	Status uint16 `json:"status"`
+	Proof  *Proof `json:"proof,omitempty"`
+}
+
+func TestSignAndVerifyJSON(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.PubkeyToAddress(key.PublicKey).String()
+
+	info := &testSwapInfo{TxID: "0x1234", Value: "1000000", Status: 10}
+	info.Proof, err = Sign(info, "test-bridge", 1600000000, key)
+	if err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	if err = Verify(&testSwapInfo{TxID: info.TxID, Value: info.Value, Status: info.Status}, info.Proof, signer); err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+
+	data, _ := json.Marshal(info)
+	if _, err = VerifyJSON(data, signer); err != nil {
+		t.Fatalf("verify json failed: %v", err)
+	}
+
+	info.Value = "2000000"
+	data, _ = json.Marshal(info)
+	if _, err = VerifyJSON(data, signer); err == nil {
+		t.Fatal("verify tampered response should fail")
+	}
+
+	info.Value = "1000000"
+	data, _ = json.Marshal(info)
+	if _, err = VerifyJSON(data, "0x0000000000000000000000000000000000000001"); err == nil {
+		t.Fatal("verify with wrong signer should fail")
+	}
+
+	// self signed forgery carries its own signer
+	forger, _ := crypto.GenerateKey()
+	info.Proof, _ = Sign(&testSwapInfo{TxID: info.TxID, Value: info.Value, Status: info.Status}, "test-bridge", 1600000000, forger)
+	data, _ = json.Marshal(info)
+	if _, err = VerifyJSON(data, ""); err == nil {
+		t.Fatal("verify without expected signer should fail")
+	}
+	if _, err = VerifyJSON(data, signer); err == nil {
+		t.Fatal("verify self signed forgery should fail")
+	}
+}
@@ -79,10 +79,13 @@ func (tx *RPCTransaction) GetAccountNonce() uint64 {
 
 // RPCLog struct
 type RPCLog struct {
-	Address *common.Address `json:"address"`
-	Topics  []common.Hash   `json:"topics"`
-	Data    *hexutil.Bytes  `json:"data"`
-	Removed *bool           `json:"removed"`
+	Address     *common.Address `json:"address"`
+	Topics      []common.Hash   `json:"topics"`
+	Data        *hexutil.Bytes  `json:"data"`
+	Removed     *bool           `json:"removed"`
+	BlockNumber *hexutil.Uint64 `json:"blockNumber,omitempty"`
+	TxHash      *common.Hash    `json:"transactionHash,omitempty"`
+	LogIndex    *hexutil.Uint   `json:"logIndex,omitempty"`
 }
 
 // RPCTxReceipt struct
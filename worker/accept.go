@@ -1,15 +1,17 @@
 package worker
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/common"
 	"github.com/anyswap/CrossChain-Bridge/dcrm"
+	"github.com/anyswap/CrossChain-Bridge/internal/metrics"
 	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
@@ -27,11 +29,22 @@ var (
 	cachedAcceptInfos    = mapset.NewSet()
 	maxCachedAcceptInfos = 500
 
+	// sign infos being verified, not evicted like the cache,
+	// so the same keyID is never verified concurrently
+	processingAcceptInfos     = make(map[string]struct{})
+	processingAcceptInfosLock sync.Mutex
+
 	isPendingInvalidAccept    bool
 	maxAcceptSignTimeInterval = int64(600) // seconds
+	acceptProcessedLookback   = 3 * maxAcceptSignTimeInterval
+
+	retryInterval    = 3 * time.Second
+	waitInterval     = 20 * time.Second
+	maxRetryInterval = retryInterval // cap of backoff interval when failed
+	maxWaitInterval  = waitInterval  // cap of backoff interval when empty
 
-	retryInterval = 3 * time.Second
-	waitInterval  = 20 * time.Second
+	acceptJobStatus     AcceptJobStatus
+	acceptJobStatusLock sync.RWMutex
 
 	acceptInfoCh      = make(chan *dcrm.SignInfoData, 10)
 	maxAcceptRoutines = int64(10)
@@ -41,6 +54,9 @@ var (
 	errIdentifierMismatch = errors.New("cross chain bridge identifier mismatch")
 	errInitiatorMismatch  = errors.New("initiator mismatch")
 	errWrongMsgContext    = errors.New("wrong msg context")
+
+	errDeprecatedIdentifierExpired = errors.New("deprecated identifier is expired")
+	errSignGroupMismatch           = errors.New("sign group mismatch")
 )
 
 // StartAcceptSignJob accept job
@@ -50,16 +66,18 @@ func StartAcceptSignJob() {
 		return
 	}
 	isPendingInvalidAccept = params.GetOracleConfig().PendingInvalidAccept
-	getAcceptListInterval := params.GetOracleConfig().GetAcceptListInterval
-	if getAcceptListInterval > 0 {
-		waitInterval = time.Duration(getAcceptListInterval) * time.Second
-		if retryInterval > waitInterval {
-			retryInterval = waitInterval
-		}
+	oracleCfg := params.GetOracleConfig()
+	initAcceptIntervals(oracleCfg)
+	if oracleCfg.MaxMsgContextSize > 0 {
+		maxMsgContextSize = oracleCfg.MaxMsgContextSize
+	}
+	if oracleCfg.MaxAcceptRoutines > 0 {
+		maxAcceptRoutines = int64(oracleCfg.MaxAcceptRoutines)
 	}
 	acceptSignStarter.Do(func() {
-		logWorker("accept", "start accept sign job")
+		logWorker("accept", "start accept sign job", "maxRoutines", maxAcceptRoutines)
 		openLeveldb()
+		initAcceptProcessedStore()
 		go startAcceptProducer()
 
 		utils.TopWaitGroup.Add(1)
@@ -67,38 +85,180 @@ func StartAcceptSignJob() {
 	})
 }
 
+// AcceptJobStatus accept job status
+type AcceptJobStatus struct {
+	PollInterval  string `json:"pollInterval"` // current effective interval
+	WaitInterval  string `json:"waitInterval"`
+	RetryInterval string `json:"retryInterval"`
+	Failures      int    `json:"failures"` // consecutive errors or empty results
+	Timestamp     int64  `json:"timestamp"`
+
+	// sign infos of the last round
+	Dispatched       int `json:"dispatched"`
+	SkippedCached    int `json:"skippedCached"`    // in memory cache
+	SkippedProcessed int `json:"skippedProcessed"` // persisted processed records
+
+	TotalDispatched       uint64 `json:"totalDispatched"`
+	TotalSkippedCached    uint64 `json:"totalSkippedCached"`
+	TotalSkippedProcessed uint64 `json:"totalSkippedProcessed"`
+}
+
+// GetAcceptJobStatus get accept job status, return nil if job is not started
+func GetAcceptJobStatus() *AcceptJobStatus {
+	acceptJobStatusLock.RLock()
+	defer acceptJobStatusLock.RUnlock()
+	if acceptJobStatus.Timestamp == 0 {
+		return nil
+	}
+	status := acceptJobStatus
+	return &status
+}
+
+func updateAcceptJobStatus(pollInterval time.Duration, failures int) {
+	acceptJobStatusLock.Lock()
+	defer acceptJobStatusLock.Unlock()
+	acceptJobStatus.PollInterval = pollInterval.String()
+	acceptJobStatus.WaitInterval = waitInterval.String()
+	acceptJobStatus.RetryInterval = retryInterval.String()
+	acceptJobStatus.Failures = failures
+	acceptJobStatus.Timestamp = time.Now().Unix()
+}
+
+func recordAcceptRound(dispatched, skippedCached, skippedProcessed int) {
+	acceptJobStatusLock.Lock()
+	defer acceptJobStatusLock.Unlock()
+	acceptJobStatus.Dispatched = dispatched
+	acceptJobStatus.SkippedCached = skippedCached
+	acceptJobStatus.SkippedProcessed = skippedProcessed
+	acceptJobStatus.TotalDispatched += uint64(dispatched)
+	acceptJobStatus.TotalSkippedCached += uint64(skippedCached)
+	acceptJobStatus.TotalSkippedProcessed += uint64(skippedProcessed)
+}
+
+// backoffInterval double base interval for each repeated failure, capped at max
+// initAcceptIntervals init poll intervals of accept job,
+// failures keep retrying at retry interval unless the backoff cap is configured
+func initAcceptIntervals(oracleCfg *params.OracleConfig) {
+	if oracleCfg.GetAcceptListInterval > 0 {
+		waitInterval = time.Duration(oracleCfg.GetAcceptListInterval) * time.Second
+	}
+	if oracleCfg.RetryAcceptListInterval > 0 {
+		retryInterval = time.Duration(oracleCfg.RetryAcceptListInterval) * time.Second
+	}
+	if retryInterval > waitInterval {
+		retryInterval = waitInterval
+	}
+	maxWaitInterval = waitInterval
+	maxRetryInterval = retryInterval
+	if oracleCfg.MaxAcceptListInterval > 0 {
+		maxWaitInterval = time.Duration(oracleCfg.MaxAcceptListInterval) * time.Second
+		maxRetryInterval = maxWaitInterval
+	}
+	if maxWaitInterval < waitInterval {
+		maxWaitInterval = waitInterval
+	}
+}
+
+func backoffInterval(base, max time.Duration, failures int) time.Duration {
+	if max < base {
+		max = base
+	}
+	interval := base
+	for i := 1; i < failures && interval < max; i++ {
+		interval *= 2
+	}
+	if interval > max {
+		interval = max
+	}
+	return interval
+}
+
 func startAcceptProducer() {
+	var errFailures, emptyFailures int
+	var pollInterval time.Duration
 	i := 0
 	for {
+		if pollInterval > 0 {
+			time.Sleep(pollInterval)
+		}
+		if utils.IsCleanuping() {
+			return
+		}
 		signInfo, err := dcrm.GetCurNodeSignInfo(maxAcceptSignTimeInterval)
 		if err != nil {
 			logWorkerError("accept", "getCurNodeSignInfo failed", err)
-			time.Sleep(retryInterval)
+			errFailures++
+			emptyFailures = 0
+			pollInterval = backoffInterval(retryInterval, maxRetryInterval, errFailures)
+			updateAcceptJobStatus(pollInterval, errFailures)
 			continue
 		}
+		errFailures = 0
 		i++
-		if i%7 == 0 {
-			logWorker("accept", "getCurNodeSignInfo", "count", len(signInfo))
+		if i%100 == 1 {
+			pruneAcceptProcessed()
 		}
-		for _, info := range signInfo {
-			if utils.IsCleanuping() {
-				return
-			}
-			if info == nil { // maybe a dcrm RPC problem
-				continue
-			}
-			keyID := info.Key
-			if cachedAcceptInfos.Contains(keyID) {
-				logWorkerTrace("accept", "ignore cached accept sign info before dispatch", "keyID", keyID)
-				continue
-			}
-			logWorker("accept", "dispatch accept sign info", "keyID", keyID)
+		dispatched, skippedCached, skippedProcessed := dispatchAcceptSignInfos(signInfo, func(info *dcrm.SignInfoData) {
 			acceptInfoCh <- info // produce
+		})
+		if utils.IsCleanuping() {
+			return
+		}
+		recordAcceptRound(dispatched, skippedCached, skippedProcessed)
+		if i%7 == 0 || skippedProcessed > 0 {
+			logWorker("accept", "getCurNodeSignInfo", "count", len(signInfo), "dispatched", dispatched, "skippedCached", skippedCached, "skippedProcessed", skippedProcessed)
 		}
+		switch {
+		case dispatched > 0: // poll immediately as there may be more pending requests
+			emptyFailures = 0
+			pollInterval = 0
+		case len(signInfo) == 0:
+			emptyFailures++
+			pollInterval = backoffInterval(waitInterval, maxWaitInterval, emptyFailures)
+		default:
+			emptyFailures = 0
+			pollInterval = waitInterval
+		}
+		updateAcceptJobStatus(pollInterval, emptyFailures)
+	}
+}
+
+// dispatchAcceptSignInfos dispatch sign infos which are neither cached nor processed
+func dispatchAcceptSignInfos(signInfo []*dcrm.SignInfoData, dispatch func(*dcrm.SignInfoData)) (dispatched, skippedCached, skippedProcessed int) {
+	for _, info := range signInfo {
 		if utils.IsCleanuping() {
 			return
 		}
-		time.Sleep(waitInterval)
+		if info == nil { // maybe a dcrm RPC problem
+			continue
+		}
+		keyID := info.Key
+		if cachedAcceptInfos.Contains(keyID) {
+			logWorkerTrace("accept", "ignore cached accept sign info before dispatch", "keyID", keyID)
+			skippedCached++
+			continue
+		}
+		if IsAcceptProcessed(keyID) {
+			logWorkerTrace("accept", "ignore processed accept sign info before dispatch", "keyID", keyID)
+			addCachedAcceptInfo(keyID)
+			skippedProcessed++
+			continue
+		}
+		logWorker("accept", "dispatch accept sign info", "keyID", keyID)
+		dispatch(info)
+		dispatched++
+	}
+	return dispatched, skippedCached, skippedProcessed
+}
+
+// sign infos older than `maxAcceptSignTimeInterval` are filtered out by dcrm api,
+// keep processed records for a longer lookback to tolerate clock skew.
+func pruneAcceptProcessed() {
+	pruned, err := PruneAcceptProcessed(acceptProcessedLookback)
+	if err != nil {
+		logWorkerError("accept", "prune processed accept sign info failed", err)
+	} else if pruned > 0 {
+		logWorker("accept", "prune processed accept sign info", "count", pruned)
 	}
 }
 
@@ -128,15 +288,31 @@ func startAcceptConsumer() {
 }
 
 func checkAndUpdateCachedAcceptInfoMap(keyID string) (ok bool) {
-	if cachedAcceptInfos.Contains(keyID) {
+	processingAcceptInfosLock.Lock()
+	defer processingAcceptInfosLock.Unlock()
+	if _, exist := processingAcceptInfos[keyID]; exist || cachedAcceptInfos.Contains(keyID) {
 		logWorkerTrace("accept", "ignore cached accept sign info in process", "keyID", keyID)
 		return false
 	}
+	processingAcceptInfos[keyID] = struct{}{}
+	addCachedAcceptInfo(keyID)
+	return true
+}
+
+func finishProcessingAcceptInfo(keyID string, isProcessed bool) {
+	processingAcceptInfosLock.Lock()
+	defer processingAcceptInfosLock.Unlock()
+	delete(processingAcceptInfos, keyID)
+	if !isProcessed {
+		cachedAcceptInfos.Remove(keyID)
+	}
+}
+
+func addCachedAcceptInfo(keyID string) {
 	if cachedAcceptInfos.Cardinality() >= maxCachedAcceptInfos {
 		cachedAcceptInfos.Pop()
 	}
 	cachedAcceptInfos.Add(keyID)
-	return true
 }
 
 func processAcceptInfo(info *dcrm.SignInfoData) {
@@ -148,15 +324,20 @@ func processAcceptInfo(info *dcrm.SignInfoData) {
 	}
 	isProcessed := false
 	defer func() {
-		if !isProcessed {
-			cachedAcceptInfos.Remove(keyID)
+		finishProcessingAcceptInfo(keyID, isProcessed)
+		if isProcessed {
+			if err := AddAcceptProcessed(keyID); err != nil {
+				logWorkerError("accept", "save processed accept sign info failed", err, "keyID", keyID)
+			}
 		}
 	}()
 
 	args, err := verifySignInfo(info)
+	verifyErr := err
 
 	ctx := []interface{}{
 		"keyID", keyID,
+		"initiator", getSignInfoInitiator(info),
 	}
 	if args != nil {
 		ctx = append(ctx,
@@ -218,18 +399,26 @@ func processAcceptInfo(info *dcrm.SignInfoData) {
 		logWorkerError("accept", "accept sign job failed", err, ctx...)
 	} else {
 		logWorker("accept", "accept sign job finish", ctx...)
+		metrics.DcrmAccepts.Inc(strings.ToLower(agreeResult))
 		isProcessed = true
+		if agreeResult == acceptDisagree {
+			go recordAcceptorDisagreement(info, args, verifyErr, aggreeMsgContext[0])
+			captureReplayBundle(info, args, aggreeMsgContext[0])
+		}
 	}
 }
 
 func getBuildTxArgsFromMsgContext(signInfo *dcrm.SignInfoData) (*tokens.BuildTxArgs, error) {
-	msgContext := signInfo.MsgContext
-	var args tokens.BuildTxArgs
-	err := json.Unmarshal([]byte(msgContext[0]), &args)
+	return parseMsgContext(signInfo.MsgContext)
+}
+
+// getSignInfoInitiator get initiator account of sign info, empty if not recovered
+func getSignInfoInitiator(signInfo *dcrm.SignInfoData) string {
+	initiator, err := signInfo.GetInitiator()
 	if err != nil {
-		return nil, errWrongMsgContext
+		return ""
 	}
-	return &args, nil
+	return initiator
 }
 
 func verifySignInfo(signInfo *dcrm.SignInfoData) (args *tokens.BuildTxArgs, err error) {
@@ -239,16 +428,15 @@ func verifySignInfo(signInfo *dcrm.SignInfoData) (args *tokens.BuildTxArgs, err
 	}
 	msgHash := signInfo.MsgHash
 	msgContext := signInfo.MsgContext
-	switch args.Identifier {
-	case params.GetIdentifier():
-	case params.GetReplaceIdentifier():
-	case tokens.AggregateIdentifier:
-	default:
-		return args, errIdentifierMismatch
+	if err = checkSelfIdentifier(signInfo.Key, args.Identifier); err != nil {
+		return args, err
 	}
-	if !params.IsDcrmInitiator(signInfo.Account) {
+	if !params.IsDcrmInitiator(getSignInfoInitiator(signInfo)) {
 		return nil, errInitiatorMismatch
 	}
+	if err = checkSignGroup(signInfo, args.PairID); err != nil {
+		return args, err
+	}
 
 	if args.Identifier == tokens.AggregateIdentifier {
 		if btc.BridgeInstance == nil {
@@ -269,13 +457,61 @@ func verifySignInfo(signInfo *dcrm.SignInfoData) (args *tokens.BuildTxArgs, err
 			return args, err
 		}
 	}
-	err = rebuildAndVerifyMsgHash(signInfo.Key, msgHash, args)
+	if args.Identifier == tokens.RefundIdentifier {
+		err = rebuildAndVerifyRefundMsgHash(signInfo.Key, msgHash, args)
+	} else {
+		err = rebuildAndVerifyMsgHash(signInfo.Key, msgHash, args)
+	}
 	if err != nil {
 		return args, err
 	}
 	return args, nil
 }
 
+// checkSignGroup check sign group, threshold and public key of sign info
+// are of the dcrm group profile of pair if the pair references one
+func checkSignGroup(signInfo *dcrm.SignInfoData, pairID string) error {
+	pairCfg := tokens.GetTokenPairConfig(pairID)
+	if pairCfg == nil || pairCfg.DcrmGroup == "" {
+		return nil
+	}
+	profile := params.GetDcrmGroupProfile(pairCfg.DcrmGroup)
+	if profile == nil {
+		return fmt.Errorf("%w: dcrm group '%v' is not configed", errSignGroupMismatch, pairCfg.DcrmGroup)
+	}
+	if !profile.IsSignGroup(signInfo.GroupID) {
+		return fmt.Errorf("%w: group %v is not sign group of '%v'", errSignGroupMismatch, signInfo.GroupID, pairCfg.DcrmGroup)
+	}
+	if signInfo.ThresHold != profile.GetThreshold() {
+		return fmt.Errorf("%w: threshold %v, want %v", errSignGroupMismatch, signInfo.ThresHold, profile.GetThreshold())
+	}
+	if common.Bytes2Hex(common.FromHex(signInfo.PubKey)) != common.Bytes2Hex(common.FromHex(profile.Pubkey)) {
+		return fmt.Errorf("%w: public key is not of '%v'", errSignGroupMismatch, pairCfg.DcrmGroup)
+	}
+	return nil
+}
+
+// checkSelfIdentifier check identifier is this bridge's own,
+// deprecated identifiers of renamed bridge are accepted before expired.
+func checkSelfIdentifier(keyID, identifier string) error {
+	switch identifier {
+	case params.GetIdentifier():
+	case params.GetReplaceIdentifier():
+	case tokens.AggregateIdentifier:
+	case tokens.RefundIdentifier:
+	default:
+		deprecated := params.GetDeprecatedIdentifier(identifier)
+		if deprecated == nil {
+			return errIdentifierMismatch
+		}
+		if deprecated.IsExpired(time.Now()) {
+			return fmt.Errorf("%w: '%v' expired on %v", errDeprecatedIdentifierExpired, deprecated.Identifier, deprecated.Expire)
+		}
+		logWorker("accept", "match deprecated identifier", "keyID", keyID, "identifier", identifier, "expire", deprecated.Expire)
+	}
+	return nil
+}
+
 func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildTxArgs) error {
 	var srcBridge, dstBridge tokens.CrossChainBridge
 	switch args.SwapType {
@@ -293,6 +529,9 @@ func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildT
 	if tokenCfg == nil {
 		return tokens.ErrUnknownPairID
 	}
+	if _, ok := dstBridge.(tokens.NonceSetter); !ok && args.PayoutLeg != nil {
+		return tokens.ErrWrongPayoutLeg // split payout is only for nonce supported chains
+	}
 
 	ctx := []interface{}{
 		"keyID", keyID,
@@ -301,6 +540,7 @@ func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildT
 		"pairID", args.PairID,
 		"swapID", args.SwapID,
 		"bind", args.Bind,
+		"payoutLeg", args.PayoutLeg,
 	}
 
 	swapInfo, err := verifySwapTransaction(srcBridge, args.PairID, args.SwapID, args.Bind, args.TxType)
@@ -308,6 +548,10 @@ func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildT
 		logWorkerError("accept", "verifySignInfo failed", err, ctx...)
 		return err
 	}
+	if bigValue := tokens.CheckBigValue(args.PairID, swapInfo.Value, args.SwapType == tokens.SwapinType, swapInfo.From, swapInfo.TxTo); bigValue.IsBigValue {
+		// big value swap is passed by admin on the server side, oracles only report it
+		logWorkerWarn("accept", "sign big value swap", append(ctx, "rule", bigValue.Rule(), "detail", bigValue.Detail)...)
+	}
 
 	buildTxArgs := &tokens.BuildTxArgs{
 		SwapInfo:    args.SwapInfo,
@@ -315,6 +559,7 @@ func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildT
 		OriginFrom:  swapInfo.From,
 		OriginTxTo:  swapInfo.TxTo,
 		OriginValue: swapInfo.Value,
+		SwapValue:   args.SwapValue, // recorded swap value, checked by builders of fixed-width amount
 		Extra:       args.Extra,
 	}
 	rawTx, err := dstBridge.BuildRawTransaction(buildTxArgs)
@@ -322,6 +567,13 @@ func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildT
 		logWorkerError("accept", "build raw tx failed", err, ctx...)
 		return err
 	}
+	if verifier, ok := dstBridge.(tokens.ExtraArgsVerifier); ok {
+		err = verifier.VerifyExtraArgs(buildTxArgs)
+		if err != nil {
+			logWorkerError("accept", "verify extra args failed", err, ctx...)
+			return err
+		}
+	}
 	err = dstBridge.VerifyMsgHash(rawTx, msgHash)
 	if err != nil {
 		logWorkerError("accept", "verify message hash failed", err, ctx...)
@@ -334,6 +586,61 @@ func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildT
 	return nil
 }
 
+// rebuildAndVerifyRefundMsgHash verify refund sign request by verifying the deposit
+// is valid but never swappable, and this node never accepted swapping it
+func rebuildAndVerifyRefundMsgHash(keyID string, msgHash []string, args *tokens.BuildTxArgs) error {
+	if args.SwapType != tokens.SwapinType && args.SwapType != tokens.SwapoutType {
+		return fmt.Errorf("unknown swap type %v", args.SwapType)
+	}
+	isSwapin := args.IsSwapin()
+	bridge := tokens.GetCrossChainBridge(isSwapin)
+
+	ctx := []interface{}{
+		"keyID", keyID,
+		"identifier", args.Identifier,
+		"swaptype", args.SwapType.String(),
+		"pairID", args.PairID,
+		"swapID", args.SwapID,
+		"bind", args.Bind,
+	}
+
+	swapArgs := &tokens.BuildTxArgs{SwapInfo: args.SwapInfo}
+	swapArgs.Identifier = params.GetIdentifier()
+	if len(FindAcceptRecords(swapArgs)) > 0 {
+		logWorkerError("accept", "refund swap which has been accepted", errAlreadySwapped, ctx...)
+		return errAlreadySwapped
+	}
+
+	buildTxArgs, err := buildRefundTxArgs(args.SwapID, args.PairID, args.Bind, isSwapin, args.TxType)
+	if err != nil {
+		logWorkerError("accept", "verify refund failed", err, ctx...)
+		return err
+	}
+	buildTxArgs.Extra = args.Extra
+	rawTx, err := bridge.(tokens.RefundBuilder).BuildRefundTransaction(buildTxArgs)
+	if err != nil {
+		logWorkerError("accept", "build refund tx failed", err, ctx...)
+		return err
+	}
+	if verifier, ok := bridge.(tokens.ExtraArgsVerifier); ok {
+		err = verifier.VerifyExtraArgs(buildTxArgs)
+		if err != nil {
+			logWorkerError("accept", "verify extra args failed", err, ctx...)
+			return err
+		}
+	}
+	err = bridge.VerifyMsgHash(rawTx, msgHash)
+	if err != nil {
+		logWorkerError("accept", "verify message hash failed", err, ctx...)
+		return err
+	}
+	if lvldbHandle != nil && args.GetTxNonce() > 0 { // only for eth like chain
+		go saveAcceptRecord(bridge, keyID, buildTxArgs, rawTx)
+	}
+	logWorker("accept", "verify refund message hash success", ctx...)
+	return nil
+}
+
 func saveAcceptRecord(bridge tokens.CrossChainBridge, keyID string, args *tokens.BuildTxArgs, rawTx interface{}) {
 	impl, ok := bridge.(interface {
 		GetSignedTxHashOfKeyID(keyID, pairID string, rawTx interface{}) (txHash string, err error)
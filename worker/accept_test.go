@@ -0,0 +1,263 @@
+package worker
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/dcrm"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	mapset "github.com/deckarep/golang-set"
+)
+
+func TestBackoffInterval(t *testing.T) {
+	base, max := 3*time.Second, 20*time.Second
+	want := []time.Duration{3 * time.Second, 3 * time.Second, 6 * time.Second, 12 * time.Second, 20 * time.Second, 20 * time.Second}
+	for failures, interval := range want {
+		if have := backoffInterval(base, max, failures); have != interval {
+			t.Errorf("failures %v: want interval %v, have %v", failures, interval, have)
+		}
+	}
+	// max below base keeps base interval
+	if have := backoffInterval(20*time.Second, 3*time.Second, 5); have != 20*time.Second {
+		t.Errorf("want base interval when max is lower, have %v", have)
+	}
+}
+
+func TestInitAcceptIntervals(t *testing.T) {
+	oldRetry, oldWait, oldMaxRetry, oldMaxWait := retryInterval, waitInterval, maxRetryInterval, maxWaitInterval
+	defer func() {
+		retryInterval, waitInterval, maxRetryInterval, maxWaitInterval = oldRetry, oldWait, oldMaxRetry, oldMaxWait
+	}()
+
+	// failures are not backed off without configured cap
+	initAcceptIntervals(&params.OracleConfig{GetAcceptListInterval: 20, RetryAcceptListInterval: 3})
+	if maxRetryInterval != 3*time.Second || maxWaitInterval != 20*time.Second {
+		t.Errorf("want caps 3s and 20s, have %v and %v", maxRetryInterval, maxWaitInterval)
+	}
+
+	initAcceptIntervals(&params.OracleConfig{GetAcceptListInterval: 20, RetryAcceptListInterval: 3, MaxAcceptListInterval: 60})
+	if maxRetryInterval != 60*time.Second || maxWaitInterval != 60*time.Second {
+		t.Errorf("want caps 60s and 60s, have %v and %v", maxRetryInterval, maxWaitInterval)
+	}
+}
+
+// memAcceptProcessedStore in memory processed accept sign info store
+type memAcceptProcessedStore struct {
+	processed map[string]int64 // keyID -> timestamp
+	findErr   error
+}
+
+func useMemAcceptProcessedStore(t *testing.T) *memAcceptProcessedStore {
+	store := &memAcceptProcessedStore{processed: make(map[string]int64)}
+	oldStore := acceptProcessed
+	acceptProcessed = store
+	t.Cleanup(func() { acceptProcessed = oldStore })
+	return store
+}
+
+func (s *memAcceptProcessedStore) AddAcceptProcessed(keyID string) error {
+	s.processed[keyID] = now()
+	return nil
+}
+
+func (s *memAcceptProcessedStore) IsAcceptProcessed(keyID string) (bool, error) {
+	if s.findErr != nil {
+		return false, s.findErr
+	}
+	_, exist := s.processed[keyID]
+	return exist, nil
+}
+
+func (s *memAcceptProcessedStore) PruneAcceptProcessed(before int64) (pruned int64, err error) {
+	for keyID, timestamp := range s.processed {
+		if timestamp < before {
+			delete(s.processed, keyID)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+func TestAcceptProcessedWithoutStore(t *testing.T) {
+	oldStore := acceptProcessed
+	acceptProcessed = nil
+	defer func() { acceptProcessed = oldStore }()
+
+	if err := AddAcceptProcessed("key"); err != nil {
+		t.Errorf("add without store should be ignored, have %v", err)
+	}
+	if IsAcceptProcessed("key") {
+		t.Errorf("nothing is processed without store")
+	}
+	if pruned, err := PruneAcceptProcessed(0); pruned != 0 || err != nil {
+		t.Errorf("prune without store should be ignored, have %v %v", pruned, err)
+	}
+}
+
+func TestAddAndPruneAcceptProcessed(t *testing.T) {
+	store := useMemAcceptProcessedStore(t)
+	if err := AddAcceptProcessed("new"); err != nil {
+		t.Fatal(err)
+	}
+	store.processed["old"] = now() - 2*acceptProcessedLookback
+	if !IsAcceptProcessed("new") || !IsAcceptProcessed("old") || IsAcceptProcessed("unknown") {
+		t.Errorf("want new and old processed, have %v", store.processed)
+	}
+
+	pruned, err := PruneAcceptProcessed(acceptProcessedLookback)
+	if err != nil || pruned != 1 {
+		t.Errorf("want 1 pruned, have %v %v", pruned, err)
+	}
+	if !IsAcceptProcessed("new") || IsAcceptProcessed("old") {
+		t.Errorf("only records older than lookback should be pruned, have %v", store.processed)
+	}
+
+	// verify again if unknown
+	store.findErr = errors.New("db error")
+	if IsAcceptProcessed("new") {
+		t.Errorf("database error should not be taken as processed")
+	}
+}
+
+func TestDispatchAcceptSignInfos(t *testing.T) {
+	store := useMemAcceptProcessedStore(t)
+	oldCached := cachedAcceptInfos
+	cachedAcceptInfos = mapset.NewSet()
+	defer func() { cachedAcceptInfos = oldCached }()
+
+	cachedAcceptInfos.Add("cached")
+	store.processed["processed"] = now()
+	signInfo := []*dcrm.SignInfoData{{Key: "cached"}, nil, {Key: "processed"}, {Key: "new"}}
+
+	var dispatchedKeys []string
+	dispatch := func(info *dcrm.SignInfoData) { dispatchedKeys = append(dispatchedKeys, info.Key) }
+	dispatched, skippedCached, skippedProcessed := dispatchAcceptSignInfos(signInfo, dispatch)
+	if dispatched != 1 || skippedCached != 1 || skippedProcessed != 1 || len(dispatchedKeys) != 1 || dispatchedKeys[0] != "new" {
+		t.Errorf("want only new dispatched, have %v (skipped cached %v processed %v)", dispatchedKeys, skippedCached, skippedProcessed)
+	}
+	// processed records are cached, not queried again
+	if !cachedAcceptInfos.Contains("processed") {
+		t.Errorf("processed sign info should be cached")
+	}
+}
+
+func TestCheckAcceptInfoInProcess(t *testing.T) {
+	oldCached := cachedAcceptInfos
+	cachedAcceptInfos = mapset.NewSet()
+	defer func() { cachedAcceptInfos = oldCached }()
+
+	var wg sync.WaitGroup
+	var started int64
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if checkAndUpdateCachedAcceptInfoMap("key") {
+				atomic.AddInt64(&started, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if started != 1 {
+		t.Fatalf("same keyID should be verified once concurrently, started %v", started)
+	}
+
+	// evicted from cache while still in process
+	cachedAcceptInfos.Remove("key")
+	if checkAndUpdateCachedAcceptInfoMap("key") {
+		t.Errorf("keyID in process should not be verified again")
+	}
+
+	finishProcessingAcceptInfo("key", false)
+	if !checkAndUpdateCachedAcceptInfoMap("key") {
+		t.Errorf("unprocessed keyID should be verified again")
+	}
+	finishProcessingAcceptInfo("key", true)
+	if checkAndUpdateCachedAcceptInfoMap("key") {
+		t.Errorf("processed keyID should be cached")
+	}
+	finishProcessingAcceptInfo("key", true)
+}
+
+func TestRecordAcceptRound(t *testing.T) {
+	acceptJobStatusLock.Lock()
+	oldStatus := acceptJobStatus
+	acceptJobStatus = AcceptJobStatus{}
+	acceptJobStatusLock.Unlock()
+	defer func() { acceptJobStatus = oldStatus }()
+
+	recordAcceptRound(3, 1, 2)
+	recordAcceptRound(0, 4, 0)
+	updateAcceptJobStatus(waitInterval, 0)
+
+	status := GetAcceptJobStatus()
+	if status == nil {
+		t.Fatal("want accept job status")
+	}
+	if status.Dispatched != 0 || status.SkippedCached != 4 || status.SkippedProcessed != 0 {
+		t.Errorf("want counts of last round, have %+v", status)
+	}
+	if status.TotalDispatched != 3 || status.TotalSkippedCached != 5 || status.TotalSkippedProcessed != 2 {
+		t.Errorf("want total counts, have %+v", status)
+	}
+}
+
+func TestCheckSelfIdentifier(t *testing.T) {
+	params.SetConfig(&params.BridgeConfig{
+		Identifier: "BRIDGEv3",
+		DeprecatedIdentifiers: []*params.DeprecatedIdentifier{
+			{Identifier: "BRIDGEv2", Expire: "2999-01-01"},
+			{Identifier: "BRIDGEv1", Expire: "2001-01-01"},
+		},
+	})
+	for _, identifier := range []string{"BRIDGEv3", "BRIDGEv3:replaceswap", "BRIDGEv2", "BRIDGEv2:replaceswap"} {
+		if err := checkSelfIdentifier("keyID", identifier); err != nil {
+			t.Errorf("identifier %v should be accepted, have %v", identifier, err)
+		}
+	}
+	if err := checkSelfIdentifier("keyID", "BRIDGEv1"); !errors.Is(err, errDeprecatedIdentifierExpired) {
+		t.Errorf("expired identifier want %v, have %v", errDeprecatedIdentifierExpired, err)
+	}
+	if err := checkSelfIdentifier("keyID", "OTHER"); !errors.Is(err, errIdentifierMismatch) {
+		t.Errorf("other identifier want %v, have %v", errIdentifierMismatch, err)
+	}
+}
+
+func TestCheckSignGroup(t *testing.T) {
+	const pubkey = "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"
+	params.SetConfig(&params.BridgeConfig{
+		Dcrm: &params.DcrmConfig{
+			GroupProfiles: map[string]*params.DcrmGroupProfile{
+				"highvalue": {GroupID: "g", NeededOracles: 4, TotalOracles: 6, SignGroups: []string{"sg1", "sg2"}, Pubkey: pubkey},
+			},
+		},
+	})
+	oldPairsConfig := tokens.GetTokenPairsConfig()
+	tokens.SetTokenPairsConfig(map[string]*tokens.TokenPairConfig{
+		"usdt": {PairID: "usdt", DcrmGroup: "highvalue"},
+		"eth":  {PairID: "eth"},
+	}, false)
+	t.Cleanup(func() { tokens.SetTokenPairsConfig(oldPairsConfig, false) })
+
+	signInfo := &dcrm.SignInfoData{GroupID: "sg2", ThresHold: "4/6", PubKey: "0x" + pubkey}
+	if err := checkSignGroup(signInfo, "usdt"); err != nil {
+		t.Errorf("sign of dcrm group should pass, have %v", err)
+	}
+	if err := checkSignGroup(&dcrm.SignInfoData{GroupID: "default", ThresHold: "2/3"}, "eth"); err != nil {
+		t.Errorf("sign of pair without dcrm group should pass, have %v", err)
+	}
+
+	for _, wrong := range []*dcrm.SignInfoData{
+		{GroupID: "default", ThresHold: "4/6", PubKey: pubkey},
+		{GroupID: "sg1", ThresHold: "2/3", PubKey: pubkey},
+		{GroupID: "sg1", ThresHold: "4/6", PubKey: "02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5"},
+	} {
+		if err := checkSignGroup(wrong, "usdt"); !errors.Is(err, errSignGroupMismatch) {
+			t.Errorf("sign %+v want %v, have %v", wrong, errSignGroupMismatch, err)
+		}
+	}
+}
@@ -23,7 +23,43 @@ var (
 )
 
 func getSwapKeyPrefix(args *tokens.BuildTxArgs) string {
-	return strings.ToLower(fmt.Sprintf("%s:%d:%s:%s:", args.SwapID, args.SwapType, args.PairID, args.Bind))
+	prefix := fmt.Sprintf("%s:%d:%s:%s:", args.SwapID, args.SwapType, args.PairID, args.Bind)
+	if args.Identifier == tokens.RefundIdentifier {
+		prefix = tokens.RefundIdentifier + ":" + prefix
+	}
+	return strings.ToLower(prefix)
+}
+
+// getPayoutLegTag payout leg tag of accept record key, eg. 'leg1/3:'
+func getPayoutLegTag(leg *tokens.PayoutLeg) string {
+	if leg == nil {
+		return ""
+	}
+	return fmt.Sprintf("leg%d/%d:", leg.Index, leg.Count)
+}
+
+// parseAcceptRecordSuffix parse payout leg and swap tx of accept record key after swap key prefix
+func parseAcceptRecordSuffix(suffix string) (leg *tokens.PayoutLeg, swapTx string) {
+	pos := strings.Index(suffix, ":")
+	if !strings.HasPrefix(suffix, "leg") || pos < 0 {
+		return nil, suffix
+	}
+	leg = &tokens.PayoutLeg{}
+	if _, err := fmt.Sscanf(suffix[:pos], "leg%d/%d", &leg.Index, &leg.Count); err != nil {
+		return nil, suffix
+	}
+	return leg, suffix[pos+1:]
+}
+
+func isSamePayoutSplit(leg, other *tokens.PayoutLeg) bool {
+	if leg == nil || other == nil {
+		return leg == nil && other == nil
+	}
+	return leg.Count == other.Count
+}
+
+func isSamePayoutLeg(leg, other *tokens.PayoutLeg) bool {
+	return isSamePayoutSplit(leg, other) && (leg == nil || leg.Index == other.Index)
 }
 
 func int64ToBytes(i int64) []byte {
@@ -41,7 +77,7 @@ func AddAcceptRecord(args *tokens.BuildTxArgs, swapTx string) (err error) {
 	if lvldbHandle == nil {
 		return nil
 	}
-	key := []byte(getSwapKeyPrefix(args) + swapTx)
+	key := []byte(getSwapKeyPrefix(args) + getPayoutLegTag(args.PayoutLeg) + swapTx)
 	return lvldbHandle.Put(key, int64ToBytes(now()))
 }
 
@@ -60,13 +96,17 @@ func FindAcceptRecords(args *tokens.BuildTxArgs) map[string]int64 {
 	return result
 }
 
-// CheckAcceptRecord check accept record
+// CheckAcceptRecord check accept record, records of other legs of the
+// same split payout are ignored, but records of different split are not.
 func CheckAcceptRecord(args *tokens.BuildTxArgs) (err error) {
 	if lvldbHandle == nil {
 		return nil
 	}
 	isSwapin := args.SwapType == tokens.SwapinType
 	resBridge := tokens.GetCrossChainBridge(!isSwapin)
+	if args.Identifier == tokens.RefundIdentifier { // refund on deposit chain
+		resBridge = tokens.GetCrossChainBridge(isSwapin)
+	}
 	alreadySwapped := false
 	nowTime := now()
 
@@ -76,10 +116,13 @@ func CheckAcceptRecord(args *tokens.BuildTxArgs) (err error) {
 	for iter.Next() {
 		key := string(iter.Key())
 		value := bytesToInt64(iter.Value())
-		oldSwapTx := key[prefixLen:]
+		recordLeg, oldSwapTx := parseAcceptRecordSuffix(key[prefixLen:])
+		if isSamePayoutSplit(recordLeg, args.PayoutLeg) && !isSamePayoutLeg(recordLeg, args.PayoutLeg) {
+			continue
+		}
 		log.Info("[accept] check saved record", "key", key, "value", value)
 		txStatus, errt := resBridge.GetTransactionStatus(oldSwapTx)
-		if errt == nil && txStatus != nil && txStatus.BlockHeight > 0 { // on chain
+		if errt == nil && txStatus.BlockHeight > 0 { // on chain
 			if txStatus.Receipt != nil { // for eth like chain
 				receipt, ok := txStatus.Receipt.(*types.RPCTxReceipt)
 				if ok && receipt.IsStatusOk() {
@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+// acceptProcessedStore persists processed accept sign infos of oracle
+type acceptProcessedStore interface {
+	AddAcceptProcessed(keyID string) error
+	IsAcceptProcessed(keyID string) (bool, error)
+	PruneAcceptProcessed(before int64) (int64, error)
+}
+
+type mgoAcceptProcessedStore struct{}
+
+func (mgoAcceptProcessedStore) AddAcceptProcessed(keyID string) error {
+	return mongodb.AddAcceptProcessed(keyID)
+}
+
+func (mgoAcceptProcessedStore) IsAcceptProcessed(keyID string) (bool, error) {
+	return mongodb.IsAcceptProcessed(keyID)
+}
+
+func (mgoAcceptProcessedStore) PruneAcceptProcessed(before int64) (int64, error) {
+	return mongodb.PruneAcceptProcessed(before)
+}
+
+// nil if oracle has no mongodb config, only the in memory cache is used then
+var acceptProcessed acceptProcessedStore
+
+func initAcceptProcessedStore() {
+	oracleCfg := params.GetOracleConfig()
+	if oracleCfg == nil || oracleCfg.MongoDB == nil || params.IsTestMode() {
+		logWorkerWarn("accept", "processed accept sign infos are not persisted without 'Oracle.MongoDB' config")
+		return
+	}
+	acceptProcessed = mgoAcceptProcessedStore{}
+}
+
+// AddAcceptProcessed record processed sign info keyID,
+// so that it is not verified again after restart
+func AddAcceptProcessed(keyID string) error {
+	if acceptProcessed == nil {
+		return nil
+	}
+	return acceptProcessed.AddAcceptProcessed(keyID)
+}
+
+// IsAcceptProcessed is sign info keyID processed,
+// return false if unknown because of database error to verify it again
+func IsAcceptProcessed(keyID string) bool {
+	if acceptProcessed == nil {
+		return false
+	}
+	processed, err := acceptProcessed.IsAcceptProcessed(keyID)
+	if err != nil {
+		logWorkerError("accept", "find processed accept sign info failed", err, "keyID", keyID)
+		return false
+	}
+	return processed
+}
+
+// PruneAcceptProcessed remove processed records older than `lookback` seconds
+func PruneAcceptProcessed(lookback int64) (int64, error) {
+	if acceptProcessed == nil {
+		return 0, nil
+	}
+	return acceptProcessed.PruneAcceptProcessed(now() - lookback)
+}
@@ -48,9 +48,9 @@ func doAggregateJob() {
 		if utils.IsCleanuping() {
 			return
 		}
-		p2shAddrs, err := mongodb.FindP2shAddresses(aggOffset, utxoPageLimit)
+		p2shAddrs, err := mongodb.FindActiveP2shAddresses(aggOffset, utxoPageLimit)
 		if err != nil {
-			logWorkerError("aggregate", "FindP2shAddresses failed", err, "offset", aggOffset, "limit", utxoPageLimit)
+			logWorkerError("aggregate", "FindActiveP2shAddresses failed", err, "offset", aggOffset, "limit", utxoPageLimit)
 			time.Sleep(3 * time.Second)
 			continue
 		}
@@ -0,0 +1,240 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// balance levels of payer account
+const (
+	BalanceLevelOK       = "ok"
+	BalanceLevelWarning  = "warning"
+	BalanceLevelCritical = "critical"
+
+	balanceAlertPushTimeout = 60 // seconds
+)
+
+var (
+	balanceMonitorStarter sync.Once
+
+	balanceStatuses     = make(map[string]*BalanceStatus) // key is chain side and lower case account
+	balanceStatusesLock sync.RWMutex
+
+	errBalanceCritical = errors.New("payer balance is below critical threshold")
+)
+
+// BalanceStatus native balance status of dcrm payer account
+type BalanceStatus struct {
+	Side        string `json:"side"` // src or dst
+	BlockChain  string `json:"blockChain"`
+	Account     string `json:"account"`
+	Balance     string `json:"balance"`
+	Level       string `json:"level"`
+	Warning     string `json:"warning,omitempty"`
+	Critical    string `json:"critical,omitempty"`
+	BurnPerHour string `json:"burnPerHour"` // estimated from balance decreases since monitor started
+	TimeToEmpty string `json:"timeToEmpty,omitempty"`
+	Timestamp   int64  `json:"timestamp"`
+
+	startTime int64
+	spent     *big.Int // sum of balance decreases, top ups are not counted
+	balance   *big.Int
+}
+
+// BalanceAlert balance level change pushed to 'PushURL'
+type BalanceAlert struct {
+	PrevLevel string `json:"prevLevel"`
+	*BalanceStatus
+}
+
+// StartBalanceMonitorJob check native balance of payer accounts periodically
+func StartBalanceMonitorJob() {
+	config := params.GetServerConfig().BalanceMonitor
+	if config == nil || !config.Enable {
+		return
+	}
+	balanceMonitorStarter.Do(func() {
+		logWorker("balancemonitor", "start balance monitor job", "interval", config.Interval, "hysteresis", config.HysteresisPercent)
+		go func() {
+			for {
+				checkPayerBalances(config)
+				time.Sleep(time.Duration(config.Interval) * time.Second)
+			}
+		}()
+	})
+}
+
+// GetBalanceStatuses get balance statuses of payer accounts
+func GetBalanceStatuses() []*BalanceStatus {
+	balanceStatusesLock.RLock()
+	defer balanceStatusesLock.RUnlock()
+	result := make([]*BalanceStatus, 0, len(balanceStatuses))
+	for _, status := range balanceStatuses {
+		copied := *status
+		result = append(result, &copied)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Side != result[j].Side {
+			return result[i].Side > result[j].Side // src first
+		}
+		return result[i].Account < result[j].Account
+	})
+	return result
+}
+
+// getPayerAccounts dcrm accounts paying native gas or value on each side
+func getPayerAccounts() map[string][]string {
+	accounts := make(map[string][]string)
+	exist := make(map[string]bool)
+	add := func(side, account string) {
+		key := side + ":" + strings.ToLower(account)
+		if account != "" && !exist[key] {
+			exist[key] = true
+			accounts[side] = append(accounts[side], account)
+		}
+	}
+	for _, pairCfg := range tokens.GetTokenPairsConfig() {
+		if pairCfg.SrcToken != nil {
+			add("src", pairCfg.SrcToken.DcrmAddress)
+		}
+		if pairCfg.DestToken != nil {
+			add("dst", pairCfg.DestToken.DcrmAddress)
+		}
+	}
+	return accounts
+}
+
+func checkPayerBalances(config *params.BalanceMonitorConfig) {
+	for side, accounts := range getPayerAccounts() {
+		bridge := tokens.SrcBridge
+		if side == "dst" {
+			bridge = tokens.DstBridge
+		}
+		getter, ok := bridge.(tokens.BalanceGetter)
+		if !ok {
+			continue
+		}
+		chainCfg := bridge.GetChainConfig()
+		warning, critical := chainCfg.GetBalanceThresholds()
+		for _, account := range accounts {
+			balance, err := getter.GetBalance(account)
+			if err != nil {
+				logWorkerError("balancemonitor", "get balance failed", err, "side", side, "account", account)
+				continue
+			}
+			status, prevLevel := updateBalanceStatus(side, chainCfg.BlockChain, account, balance, warning, critical, config.HysteresisPercent, now())
+			if status.Level != prevLevel {
+				alertBalanceLevel(config, &BalanceAlert{PrevLevel: prevLevel, BalanceStatus: status})
+			}
+		}
+	}
+}
+
+func updateBalanceStatus(side, blockChain, account string, balance, warning, critical *big.Int, hysteresisPercent uint64, timestamp int64) (status *BalanceStatus, prevLevel string) {
+	key := side + ":" + strings.ToLower(account)
+	balanceStatusesLock.Lock()
+	defer balanceStatusesLock.Unlock()
+
+	status, exist := balanceStatuses[key]
+	if !exist {
+		status = &BalanceStatus{
+			Side:       side,
+			BlockChain: blockChain,
+			Account:    account,
+			Level:      BalanceLevelOK,
+			startTime:  timestamp,
+			spent:      big.NewInt(0),
+		}
+		balanceStatuses[key] = status
+	} else if status.balance != nil && balance.Cmp(status.balance) < 0 {
+		status.spent.Add(status.spent, new(big.Int).Sub(status.balance, balance))
+	}
+	prevLevel = status.Level
+
+	status.balance = balance
+	status.Balance = balance.String()
+	status.Level = nextBalanceLevel(prevLevel, balance, warning, critical, hysteresisPercent)
+	if warning != nil {
+		status.Warning = warning.String()
+	}
+	if critical != nil {
+		status.Critical = critical.String()
+	}
+	status.Timestamp = timestamp
+
+	burnPerHour := big.NewInt(0)
+	if elapsed := timestamp - status.startTime; elapsed > 0 {
+		burnPerHour.Mul(status.spent, big.NewInt(3600))
+		burnPerHour.Div(burnPerHour, big.NewInt(elapsed))
+	}
+	status.BurnPerHour = burnPerHour.String()
+	status.TimeToEmpty = ""
+	if burnPerHour.Sign() > 0 {
+		seconds := new(big.Int).Mul(balance, big.NewInt(3600))
+		seconds.Div(seconds, burnPerHour)
+		if seconds.IsInt64() {
+			status.TimeToEmpty = (time.Duration(seconds.Int64()) * time.Second).String()
+		}
+	}
+
+	copied := *status
+	return &copied, prevLevel
+}
+
+// nextBalanceLevel lower balance changes level immediately, while recovering
+// from a level requires balance above its threshold plus hysteresis percent.
+func nextBalanceLevel(curLevel string, balance, warning, critical *big.Int, hysteresisPercent uint64) string {
+	isBelow := func(threshold *big.Int, withHysteresis bool) bool {
+		if threshold == nil {
+			return false
+		}
+		if withHysteresis {
+			threshold = new(big.Int).Mul(threshold, new(big.Int).SetUint64(100+hysteresisPercent))
+			threshold.Div(threshold, big.NewInt(100))
+		}
+		return balance.Cmp(threshold) < 0
+	}
+	switch {
+	case isBelow(critical, curLevel == BalanceLevelCritical):
+		return BalanceLevelCritical
+	case isBelow(warning, curLevel != BalanceLevelOK):
+		return BalanceLevelWarning
+	default:
+		return BalanceLevelOK
+	}
+}
+
+func alertBalanceLevel(config *params.BalanceMonitorConfig, alert *BalanceAlert) {
+	logCtx := []interface{}{"side", alert.Side, "account", alert.Account, "balance", alert.Balance, "level", alert.Level, "prevLevel", alert.PrevLevel, "burnPerHour", alert.BurnPerHour, "timeToEmpty", alert.TimeToEmpty}
+	switch alert.Level {
+	case BalanceLevelCritical:
+		logWorkerError("balancemonitor", "payer balance is critical", errBalanceCritical, logCtx...)
+	case BalanceLevelWarning:
+		logWorkerWarn("balancemonitor", "payer balance is low", logCtx...)
+	default:
+		logWorker("balancemonitor", "payer balance recovered", logCtx...)
+	}
+	if config.PushURL == "" {
+		return
+	}
+	resp, err := client.HTTPPost(config.PushURL, alert, nil, nil, balanceAlertPushTimeout)
+	if err == nil {
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("response status %v", resp.Status)
+		}
+	}
+	if err != nil {
+		logWorkerError("balancemonitor", "push balance alert failed", err, "account", alert.Account, "url", config.PushURL)
+	}
+}
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestNextBalanceLevel(t *testing.T) {
+	warning, critical := big.NewInt(1000), big.NewInt(100)
+	cases := []struct {
+		cur     string
+		balance int64
+		want    string
+	}{
+		{BalanceLevelOK, 2000, BalanceLevelOK},
+		{BalanceLevelOK, 999, BalanceLevelWarning},
+		{BalanceLevelOK, 99, BalanceLevelCritical}, // skip warning when dropping fast
+		{BalanceLevelWarning, 1050, BalanceLevelWarning},
+		{BalanceLevelWarning, 1100, BalanceLevelOK},
+		{BalanceLevelWarning, 99, BalanceLevelCritical},
+		{BalanceLevelCritical, 105, BalanceLevelCritical},
+		{BalanceLevelCritical, 110, BalanceLevelWarning},
+		{BalanceLevelCritical, 1100, BalanceLevelOK},
+	}
+	for _, c := range cases {
+		if have := nextBalanceLevel(c.cur, big.NewInt(c.balance), warning, critical, 10); have != c.want {
+			t.Errorf("level %v balance %v: want %v, have %v", c.cur, c.balance, c.want, have)
+		}
+	}
+	if have := nextBalanceLevel(BalanceLevelOK, big.NewInt(0), nil, nil, 10); have != BalanceLevelOK {
+		t.Errorf("no thresholds should be ok, have %v", have)
+	}
+}
+
+func TestUpdateBalanceStatus(t *testing.T) {
+	balanceStatusesLock.Lock()
+	oldStatuses := balanceStatuses
+	balanceStatuses = make(map[string]*BalanceStatus)
+	balanceStatusesLock.Unlock()
+	defer func() { balanceStatuses = oldStatuses }()
+
+	warning, critical := big.NewInt(5000), big.NewInt(1000)
+	update := func(balance, timestamp int64) (*BalanceStatus, string) {
+		return updateBalanceStatus("dst", "ETHEREUM", "0xPayer", big.NewInt(balance), warning, critical, 10, timestamp)
+	}
+
+	status, prevLevel := update(10000, 0)
+	if status.Level != BalanceLevelOK || prevLevel != BalanceLevelOK || status.BurnPerHour != "0" || status.TimeToEmpty != "" {
+		t.Errorf("unexpected initial status %+v", status)
+	}
+	update(9000, 1800)
+	// top up is not counted as burn
+	update(12000, 3600)
+	status, prevLevel = update(4000, 7200)
+	if status.Level != BalanceLevelWarning || prevLevel != BalanceLevelOK {
+		t.Errorf("want warning crossing from ok, have %v from %v", status.Level, prevLevel)
+	}
+	// spent 1000 + 8000 in 2 hours
+	if status.BurnPerHour != "4500" || status.TimeToEmpty == "" {
+		t.Errorf("want burn 4500 per hour, have %v time to empty %v", status.BurnPerHour, status.TimeToEmpty)
+	}
+
+	statuses := GetBalanceStatuses()
+	if len(statuses) != 1 || statuses[0].Account != "0xPayer" || statuses[0].Balance != "4000" {
+		t.Errorf("unexpected statuses %+v", statuses)
+	}
+	// account is case insensitive
+	if status, _ = updateBalanceStatus("dst", "ETHEREUM", "0xpayer", big.NewInt(4000), warning, critical, 10, 7300); status.Level != BalanceLevelWarning || len(GetBalanceStatuses()) != 1 {
+		t.Errorf("same account should be updated, have %+v", GetBalanceStatuses())
+	}
+}
@@ -0,0 +1,221 @@
+package worker
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const (
+	defaultSenderInfoTTL  = 600  // seconds
+	maxSenderHistorySwaps = 1000 // swap results of sender scanned to gather sender info
+	bigValuePushTimeout   = 60   // seconds
+)
+
+var (
+	senderInfoCache     = make(map[string]*SenderInfo)
+	senderInfoCacheLock sync.Mutex
+
+	// replaced in tests
+	bigValueSwaps     bigValueStore = mgoBigValueStore{}
+	isContractSender                = checkContractSender
+	pushBigValueAlert               = doPushBigValueAlert
+)
+
+// SenderInfo depositor context gathered for big value review,
+// bridge history includes the pending swap itself.
+type SenderInfo struct {
+	Sender        string            `json:"sender"`
+	FirstSeenTime int64             `json:"firstSeenTime"` // unix seconds of first swap through the bridge
+	SwapCount     int               `json:"swapCount"`
+	Volumes       map[string]string `json:"volumes"` // pairID -> total value in smallest unit
+	BindCount     int               `json:"bindCount"`
+	IsContract    *bool             `json:"isContract,omitempty"` // nil if unknown
+	Truncated     bool              `json:"truncated,omitempty"`  // only the first swaps of history are counted
+	UpdateTime    int64             `json:"updateTime"`
+}
+
+// BigValuePending swap waiting for big value review with sender info,
+// it is listed by admin 'bigvaluepending' and pushed to 'PushURL' of big value review.
+type BigValuePending struct {
+	IsSwapin   bool                   `json:"isSwapin"`
+	Swap       *mongodb.MgoSwapResult `json:"swap"`
+	SenderInfo *SenderInfo            `json:"senderInfo"`
+}
+
+type bigValueStore interface {
+	FindSenderSwapResults(isSwapin bool, sender string, limit int) ([]*mongodb.MgoSwapResult, error)
+	FindBigValueSwapResults(isSwapin bool, offset, limit int) ([]*mongodb.MgoSwapResult, error)
+}
+
+type mgoBigValueStore struct{}
+
+func (mgoBigValueStore) FindSenderSwapResults(isSwapin bool, sender string, limit int) ([]*mongodb.MgoSwapResult, error) {
+	return mongodb.FindSwapResults(isSwapin, []string{sender}, "", 0, limit, "", 0, 0)
+}
+
+func (mgoBigValueStore) FindBigValueSwapResults(isSwapin bool, offset, limit int) ([]*mongodb.MgoSwapResult, error) {
+	return mongodb.FindSwapResults(isSwapin, nil, "", offset, limit, mongodb.TxWithBigValue.String(), 0, 0)
+}
+
+func getBigValueReviewConfig() *params.BigValueReviewConfig {
+	serverCfg := params.GetServerConfig()
+	if serverCfg == nil {
+		return nil
+	}
+	return serverCfg.BigValueReview
+}
+
+func getSenderInfoTTL() int64 {
+	if config := getBigValueReviewConfig(); config != nil && config.SenderInfoTTL > 0 {
+		return config.SenderInfoTTL
+	}
+	return defaultSenderInfoTTL
+}
+
+func getSenderInfoCacheKey(isSwapin bool, sender string) string {
+	if isSwapin {
+		return "swapin:" + strings.ToLower(sender)
+	}
+	return "swapout:" + strings.ToLower(sender)
+}
+
+// checkContractSender check sender is contract, nil if chain has no contract
+func checkContractSender(isSwapin bool, sender string) (*bool, error) {
+	checker, ok := tokens.GetCrossChainBridge(isSwapin).(tokens.ContractChecker)
+	if !ok {
+		return nil, nil
+	}
+	isContract, err := checker.IsContractAddress(sender)
+	if err != nil {
+		return nil, err
+	}
+	return &isContract, nil
+}
+
+// GetSenderInfo get sender info of deposit (swapin) or burn (swapout) sender,
+// gathered from swap history and chain, and cached for 'SenderInfoTTL'
+func GetSenderInfo(isSwapin bool, sender string) (*SenderInfo, error) {
+	cacheKey := getSenderInfoCacheKey(isSwapin, sender)
+	now := time.Now().Unix()
+	senderInfoCacheLock.Lock()
+	info, exist := senderInfoCache[cacheKey]
+	senderInfoCacheLock.Unlock()
+	if exist && info.UpdateTime+getSenderInfoTTL() > now {
+		return info, nil
+	}
+
+	results, err := bigValueSwaps.FindSenderSwapResults(isSwapin, sender, maxSenderHistorySwaps)
+	if err != nil {
+		return nil, err
+	}
+	info = calcSenderInfo(sender, results)
+	info.UpdateTime = now
+
+	info.IsContract, err = isContractSender(isSwapin, sender)
+	if err != nil {
+		// do not cache incomplete info, it is gathered again next time
+		logWorkerWarn("bigvalue", "check sender is contract failed", "sender", sender, "isSwapin", isSwapin, "err", err)
+		return info, nil
+	}
+
+	senderInfoCacheLock.Lock()
+	for key, item := range senderInfoCache {
+		if item.UpdateTime+getSenderInfoTTL() <= now {
+			delete(senderInfoCache, key)
+		}
+	}
+	senderInfoCache[cacheKey] = info
+	senderInfoCacheLock.Unlock()
+	return info, nil
+}
+
+// calcSenderInfo calc sender info from swap results sorted by init time
+func calcSenderInfo(sender string, results []*mongodb.MgoSwapResult) *SenderInfo {
+	info := &SenderInfo{
+		Sender:    sender,
+		SwapCount: len(results),
+		Volumes:   make(map[string]string),
+		Truncated: len(results) >= maxSenderHistorySwaps,
+	}
+	volumes := make(map[string]*big.Int)
+	binds := make(map[string]struct{})
+	for _, res := range results {
+		if initTime := res.InitTime / 1000; info.FirstSeenTime == 0 || initTime < info.FirstSeenTime { // init time is milli seconds
+			info.FirstSeenTime = initTime
+		}
+		binds[strings.ToLower(res.Bind)] = struct{}{}
+		value, ok := new(big.Int).SetString(res.Value, 10)
+		if !ok {
+			continue
+		}
+		if volume, exist := volumes[res.PairID]; exist {
+			volume.Add(volume, value)
+		} else {
+			volumes[res.PairID] = value
+		}
+	}
+	for pairID, volume := range volumes {
+		info.Volumes[pairID] = volume.String()
+	}
+	info.BindCount = len(binds)
+	return info
+}
+
+func newBigValuePending(isSwapin bool, res *mongodb.MgoSwapResult) *BigValuePending {
+	pending := &BigValuePending{IsSwapin: isSwapin, Swap: res}
+	senderInfo, err := GetSenderInfo(isSwapin, res.From)
+	if err != nil {
+		logWorkerError("bigvalue", "get sender info failed", err, "txid", res.TxID, "sender", res.From, "isSwapin", isSwapin)
+	}
+	pending.SenderInfo = senderInfo
+	return pending
+}
+
+// ListBigValuePendings list swaps waiting for big value review with sender info, oldest first
+func ListBigValuePendings(isSwapin bool, offset, limit int) ([]*BigValuePending, error) {
+	results, err := bigValueSwaps.FindBigValueSwapResults(isSwapin, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	pendings := make([]*BigValuePending, 0, len(results))
+	for _, res := range results {
+		pendings = append(pendings, newBigValuePending(isSwapin, res))
+	}
+	return pendings, nil
+}
+
+// notifyBigValuePending push swap which is pending for big value review with sender info
+func notifyBigValuePending(isSwapin bool, txid, pairID, bind string) {
+	res, err := swapResults.FindSwapResult(isSwapin, txid, pairID, bind)
+	if err != nil {
+		logWorkerError("bigvalue", "find big value swap result failed", err, "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin)
+		return
+	}
+	pushBigValueAlert(newBigValuePending(isSwapin, res))
+}
+
+func doPushBigValueAlert(pending *BigValuePending) {
+	config := getBigValueReviewConfig()
+	if config == nil || config.PushURL == "" {
+		return
+	}
+	resp, err := client.HTTPPost(config.PushURL, pending, nil, nil, bigValuePushTimeout)
+	if err == nil {
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("response status %v", resp.Status)
+		}
+	}
+	if err != nil {
+		logWorkerError("bigvalue", "push big value pending failed", err, "txid", pending.Swap.TxID, "url", config.PushURL)
+	}
+}
@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+// memBigValueStore in memory big value review storage
+type memBigValueStore struct {
+	senderResults  []*mongodb.MgoSwapResult
+	bigValues      []*mongodb.MgoSwapResult
+	senderQueryCnt int
+}
+
+func (s *memBigValueStore) FindSenderSwapResults(isSwapin bool, sender string, limit int) ([]*mongodb.MgoSwapResult, error) {
+	s.senderQueryCnt++
+	return s.senderResults, nil
+}
+
+func (s *memBigValueStore) FindBigValueSwapResults(isSwapin bool, offset, limit int) ([]*mongodb.MgoSwapResult, error) {
+	return s.bigValues, nil
+}
+
+func useBigValueStore(t *testing.T, contractErr error) *memBigValueStore {
+	params.SetConfig(&params.BridgeConfig{Server: &params.ServerConfig{}})
+	store := &memBigValueStore{}
+	oldStore, oldIsContract := bigValueSwaps, isContractSender
+	bigValueSwaps = store
+	isContractSender = func(bool, string) (*bool, error) {
+		if contractErr != nil {
+			return nil, contractErr
+		}
+		isContract := true
+		return &isContract, nil
+	}
+	senderInfoCache = make(map[string]*SenderInfo)
+	t.Cleanup(func() {
+		bigValueSwaps, isContractSender = oldStore, oldIsContract
+		senderInfoCache = make(map[string]*SenderInfo)
+	})
+	return store
+}
+
+func TestGetSenderInfo(t *testing.T) {
+	store := useBigValueStore(t, nil)
+	store.senderResults = []*mongodb.MgoSwapResult{
+		{PairID: "eth", Bind: "0xAAAA", Value: "1000", InitTime: 2000000},
+		{PairID: "eth", Bind: "0xaaaa", Value: "500", InitTime: 1000000},
+		{PairID: "usdt", Bind: "0xbbbb", Value: "7", InitTime: 3000000},
+	}
+
+	info, err := GetSenderInfo(true, "0xSender")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.FirstSeenTime != 1000 || info.SwapCount != 3 || info.BindCount != 2 || info.Truncated {
+		t.Errorf("wrong sender info %+v", info)
+	}
+	if info.Volumes["eth"] != "1500" || info.Volumes["usdt"] != "7" {
+		t.Errorf("wrong volumes %v", info.Volumes)
+	}
+	if info.IsContract == nil || !*info.IsContract {
+		t.Errorf("sender should be contract")
+	}
+
+	if _, err = GetSenderInfo(true, "0xsender"); err != nil {
+		t.Fatal(err)
+	}
+	if store.senderQueryCnt != 1 {
+		t.Errorf("sender info should be cached, queried %v times", store.senderQueryCnt)
+	}
+	if _, err = GetSenderInfo(false, "0xsender"); err != nil {
+		t.Fatal(err)
+	}
+	if store.senderQueryCnt != 2 {
+		t.Errorf("sender info of other direction should not be cached, queried %v times", store.senderQueryCnt)
+	}
+}
+
+func TestGetSenderInfoContractCheckFailed(t *testing.T) {
+	store := useBigValueStore(t, errors.New("rpc error"))
+	for i := 0; i < 2; i++ {
+		info, err := GetSenderInfo(true, "0xsender")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.IsContract != nil {
+			t.Errorf("contract check failed, is contract should be unknown")
+		}
+	}
+	if store.senderQueryCnt != 2 {
+		t.Errorf("incomplete sender info should not be cached, queried %v times", store.senderQueryCnt)
+	}
+}
+
+func TestNotifyBigValuePending(t *testing.T) {
+	useBigValueStore(t, nil)
+	results := useMemSwapResultStore(t)
+	res := &mongodb.MgoSwapResult{TxID: "txid", PairID: "eth", Bind: "bind", From: "0xsender", Value: "1000", Status: mongodb.TxWithBigValue}
+	if err := results.AddSwapResult(true, res); err != nil {
+		t.Fatal(err)
+	}
+	var alerts []*BigValuePending
+	oldPush := pushBigValueAlert
+	pushBigValueAlert = func(pending *BigValuePending) { alerts = append(alerts, pending) }
+	t.Cleanup(func() { pushBigValueAlert = oldPush })
+
+	notifyBigValuePending(true, "txid", "eth", "bind")
+	if len(alerts) != 1 {
+		t.Fatalf("want 1 alert, have %v", len(alerts))
+	}
+	alert := alerts[0]
+	if !alert.IsSwapin || alert.Swap.TxID != "txid" || alert.SenderInfo == nil || alert.SenderInfo.Sender != "0xsender" {
+		t.Errorf("wrong alert %+v", alert)
+	}
+}
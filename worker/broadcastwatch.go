@@ -0,0 +1,220 @@
+package worker
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// swap txs broadcast to chains with websocket gateway are watched,
+// the stable check is triggered at once when the tx is found in a new block
+// (to record its height) and when it reaches the stable confirmations,
+// otherwise they are left to the polling of stable job.
+const maxBroadcastWatchTxs = 10000
+
+var (
+	swapinTxWatcher  = newBroadcastTxWatcher(true)
+	swapoutTxWatcher = newBroadcastTxWatcher(false)
+
+	// trigger stable check of watched swap, replaced in tests
+	triggerWatchedSwapStable = processWatchedSwapStable
+)
+
+type watchedSwap struct {
+	isSwapin    bool
+	txid        string
+	pairID      string
+	bind        string
+	watchTime   int64
+	minedHeight uint64 // height of block including the watched tx, 0 if not mined
+}
+
+func (s *watchedSwap) isSameSwap(other *watchedSwap) bool {
+	return s.txid == other.txid && s.pairID == other.pairID && strings.EqualFold(s.bind, other.bind)
+}
+
+// broadcastTxWatcher watch broadcast swap txs of one direction
+type broadcastTxWatcher struct {
+	isSwapin bool
+	lock     sync.Mutex
+	enabled  bool
+	txs      map[string]*watchedSwap // key is lower case swap tx hash
+}
+
+func newBroadcastTxWatcher(isSwapin bool) *broadcastTxWatcher {
+	return &broadcastTxWatcher{
+		isSwapin: isSwapin,
+		txs:      make(map[string]*watchedSwap),
+	}
+}
+
+func getBroadcastTxWatcher(isSwapin bool) *broadcastTxWatcher {
+	if isSwapin {
+		return swapinTxWatcher
+	}
+	return swapoutTxWatcher
+}
+
+func (w *broadcastTxWatcher) setEnabled(enabled bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.enabled = enabled
+	if !enabled {
+		w.txs = make(map[string]*watchedSwap)
+	}
+}
+
+// watch swap tx, do nothing if not enabled (no subscription)
+func (w *broadcastTxWatcher) watch(swapTx string, swap *watchedSwap) {
+	if swapTx == "" {
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if !w.enabled {
+		return
+	}
+	if len(w.txs) >= maxBroadcastWatchTxs {
+		w.pruneExpired()
+		if len(w.txs) >= maxBroadcastWatchTxs {
+			return // left to polling
+		}
+	}
+	w.txs[strings.ToLower(swapTx)] = swap
+}
+
+// pruneExpired remove swaps which are beyond lifetime of stable job
+func (w *broadcastTxWatcher) pruneExpired() {
+	septime := getSepTimeInFind(maxStableLifetime)
+	for txHash, swap := range w.txs {
+		if swap.watchTime < septime {
+			delete(w.txs, txHash)
+		}
+	}
+}
+
+// match return swaps whose tx is in the new block at height, and swaps whose
+// mined tx reaches the stable confirmations at height. mined txs are kept
+// with their height and other txs (eg. replaced txs) of them are removed,
+// stable txs are removed.
+func (w *broadcastTxWatcher) match(height uint64, txHashes []string) (mined, stable []*watchedSwap) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for _, txHash := range txHashes {
+		txKey := strings.ToLower(txHash)
+		swap, exist := w.txs[txKey]
+		if !exist {
+			continue
+		}
+		for key, item := range w.txs {
+			if key != txKey && item.isSameSwap(swap) {
+				delete(w.txs, key)
+			}
+		}
+		swap.minedHeight = height // mined again at other height if reorg
+		mined = append(mined, swap)
+	}
+	for key, swap := range w.txs {
+		if swap.minedHeight == 0 {
+			continue
+		}
+		if height >= swap.minedHeight+tokens.GetPairStableConfirmations(swap.pairID, !w.isSwapin) {
+			delete(w.txs, key)
+			stable = append(stable, swap)
+		}
+	}
+	return mined, stable
+}
+
+func (w *broadcastTxWatcher) onNewBlockTxs(height uint64, txHashes []string) {
+	mined, stable := w.match(height, txHashes)
+	for _, swap := range mined {
+		triggerWatchedSwapStable(swap)
+	}
+	for _, swap := range stable {
+		triggerWatchedSwapStable(swap)
+	}
+}
+
+// StartBroadcastWatchJob subscribe new blocks of chains with websocket gateway
+func StartBroadcastWatchJob() {
+	for _, isSwapin := range []bool{true, false} {
+		startBroadcastWatch(getBroadcastTxWatcher(isSwapin))
+	}
+}
+
+func startBroadcastWatch(w *broadcastTxWatcher) {
+	resBridge := tokens.GetCrossChainBridge(!w.isSwapin)
+	subscriber, ok := resBridge.(tokens.BlockTxsSubscriber)
+	if !ok {
+		return
+	}
+	w.setEnabled(true)
+	if !subscriber.SubscribeNewBlockTxs(w.onNewBlockTxs) {
+		w.setEnabled(false)
+		return
+	}
+	logWorker("broadcastwatch", "start watch broadcast swap txs", "isSwapin", w.isSwapin)
+	w.loadFromDB()
+}
+
+// loadFromDB rebuild watched swaps after restarting
+func (w *broadcastTxWatcher) loadFromDB() {
+	var res []*mongodb.MgoSwapResult
+	var err error
+	if w.isSwapin {
+		res, err = findSwapinResultsToStable()
+	} else {
+		res, err = findSwapoutResultsToStable()
+	}
+	if err != nil {
+		logWorkerError("broadcastwatch", "load swaps to stable failed", err, "isSwapin", w.isSwapin)
+		return
+	}
+	for _, swapResult := range res {
+		w.watchSwapResult(swapResult)
+	}
+	logWorker("broadcastwatch", "load swaps to stable success", "isSwapin", w.isSwapin, "count", len(res))
+}
+
+func (w *broadcastTxWatcher) watchSwapResult(swapResult *mongodb.MgoSwapResult) {
+	swap := &watchedSwap{
+		isSwapin:  w.isSwapin,
+		txid:      swapResult.TxID,
+		pairID:    swapResult.PairID,
+		bind:      swapResult.Bind,
+		watchTime: now(),
+	}
+	w.watch(swapResult.SwapTx, swap)
+	for _, oldSwapTx := range swapResult.OldSwapTxs {
+		w.watch(oldSwapTx, swap)
+	}
+}
+
+// watchBroadcastTx watch swap tx after it is sent successfully
+func watchBroadcastTx(isSwapin bool, swapTx, txid, pairID, bind string) {
+	getBroadcastTxWatcher(isSwapin).watch(swapTx, &watchedSwap{
+		isSwapin:  isSwapin,
+		txid:      txid,
+		pairID:    pairID,
+		bind:      bind,
+		watchTime: now(),
+	})
+}
+
+func processWatchedSwapStable(swap *watchedSwap) {
+	swapResult, err := mongodb.FindSwapResult(swap.isSwapin, swap.txid, swap.pairID, swap.bind)
+	if err != nil {
+		logWorkerError("broadcastwatch", "find watched swap result failed", err, "txid", swap.txid, "pairID", swap.pairID, "bind", swap.bind, "isSwapin", swap.isSwapin)
+		return
+	}
+	if swapResult.Status != mongodb.MatchTxNotStable {
+		return
+	}
+	logWorker("broadcastwatch", "watched swap tx is mined", "swaptxid", swapResult.SwapTx, "txid", swap.txid, "pairID", swap.pairID, "bind", swap.bind, "isSwapin", swap.isSwapin, "minedHeight", swap.minedHeight)
+	if err = processSwapStable(swapResult, swap.isSwapin); err != nil {
+		logWorkerError("broadcastwatch", "process watched swap stable error", err, "txid", swap.txid, "pairID", swap.pairID, "bind", swap.bind, "isSwapin", swap.isSwapin)
+	}
+}
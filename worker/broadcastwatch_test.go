@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func TestBroadcastTxWatcher(t *testing.T) {
+	w := newBroadcastTxWatcher(true)
+	var triggered []*watchedSwap
+	oldTrigger := triggerWatchedSwapStable
+	triggerWatchedSwapStable = func(swap *watchedSwap) {
+		copied := *swap
+		triggered = append(triggered, &copied)
+	}
+	defer func() { triggerWatchedSwapStable = oldTrigger }()
+	oldStable := tokens.DstStableConfirmations
+	tokens.DstStableConfirmations = 3
+	defer func() { tokens.DstStableConfirmations = oldStable }()
+
+	// not watched without subscription, fallback to polling
+	w.watch("0xAAA", &watchedSwap{txid: "tx1"})
+	if len(w.txs) != 0 {
+		t.Fatalf("should not watch when disabled, have %v", len(w.txs))
+	}
+
+	w.setEnabled(true)
+	// rebuilt from db, replaced txs are watched too
+	w.watchSwapResult(&mongodb.MgoSwapResult{
+		TxID:       "tx1",
+		PairID:     "pair",
+		Bind:       "0xBind",
+		SwapTx:     "0xBBB",
+		OldSwapTxs: []string{"0xAAA", "0xBBB"},
+	})
+	w.watch("0xCCC", &watchedSwap{isSwapin: true, txid: "tx2", pairID: "pair", bind: "0xbind2"})
+	if len(w.txs) != 3 {
+		t.Fatalf("want 3 watched txs, have %v", len(w.txs))
+	}
+
+	// mined, height is recorded at once and replaced txs are removed
+	w.onNewBlockTxs(100, []string{"0xddd", "0xaaa"})
+	if len(triggered) != 1 || triggered[0].txid != "tx1" || triggered[0].minedHeight != 100 {
+		t.Fatalf("want tx1 triggered at mined height, have %v", triggered)
+	}
+	if len(w.txs) != 2 || w.txs["0xaaa"] == nil || w.txs["0xccc"] == nil {
+		t.Errorf("mined tx should be kept and replaced txs removed, have %v", w.txs)
+	}
+
+	// not stable yet
+	w.onNewBlockTxs(102, nil)
+	if len(triggered) != 1 {
+		t.Errorf("should not trigger before stable, have %v", triggered)
+	}
+
+	// reorg, mined again at other height
+	w.onNewBlockTxs(101, []string{"0xAAA"})
+	if len(triggered) != 2 || triggered[1].minedHeight != 101 {
+		t.Fatalf("want tx1 triggered at new mined height, have %v", triggered)
+	}
+
+	// stable at mined height + confirmations
+	w.onNewBlockTxs(103, nil)
+	if len(triggered) != 2 {
+		t.Errorf("should not trigger before stable, have %v", triggered)
+	}
+	w.onNewBlockTxs(104, nil)
+	if len(triggered) != 3 || triggered[2].txid != "tx1" {
+		t.Fatalf("want tx1 triggered at stable height, have %v", triggered)
+	}
+	if len(w.txs) != 1 || w.txs["0xccc"] == nil {
+		t.Errorf("stable swap should be removed, have %v", w.txs)
+	}
+	w.onNewBlockTxs(105, []string{"0xAAA"})
+	if len(triggered) != 3 {
+		t.Errorf("removed swap should not be triggered again")
+	}
+
+	w.setEnabled(false)
+	if len(w.txs) != 0 {
+		t.Errorf("disable should clear watched txs")
+	}
+}
+
+func TestTryLockSwapStable(t *testing.T) {
+	swap := &mongodb.MgoSwapResult{TxID: "txid", PairID: "pair", Bind: "bind"}
+	unlock, ok := tryLockSwapStable(swap, true)
+	if !ok {
+		t.Fatal("lock swap stable failed")
+	}
+	// being processed by stable job, skipped by broadcast watch
+	if err := processSwapStable(swap, true); err != nil {
+		t.Errorf("locked swap should be skipped, have %v", err)
+	}
+	if unlockOut, ok := tryLockSwapStable(swap, false); !ok {
+		t.Error("swapout should not be locked by swapin")
+	} else {
+		unlockOut()
+	}
+	unlock()
+	if unlock, ok = tryLockSwapStable(swap, true); !ok {
+		t.Fatal("swap should be unlocked")
+	}
+	unlock()
+}
@@ -79,6 +79,9 @@ func checkFailedSwap(swap *mongodb.MgoSwapResult, isSwapin bool) error {
 	if (swap.SwapNonce == 0 && swap.SwapHeight == 0) || swap.SwapTx == "" {
 		return nil
 	}
+	if swap.PayoutLegCount > 1 {
+		return nil // split payout is checked by legs in stable job
+	}
 
 	txid, pairID, bind := swap.TxID, swap.PairID, swap.Bind
 
@@ -103,7 +106,7 @@ func checkFailedSwap(swap *mongodb.MgoSwapResult, isSwapin bool) error {
 
 	if txStatus != nil && txStatus.BlockHeight > 0 {
 		logWorker("checkfailedswap", "do checking with height", "swap", swap, "swapheight", txStatus.BlockHeight, "confirmations", txStatus.Confirmations)
-		if txStatus.Confirmations < *resBridge.GetChainConfig().Confirmations {
+		if !tokens.IsTxStatusStable(txStatus, swap.PairID, !isSwapin) {
 			return markSwapResultUnstable(txid, pairID, bind, isSwapin)
 		}
 		return markSwapResultStable(txid, pairID, bind, isSwapin)
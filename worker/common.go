@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -19,6 +20,8 @@ type MatchTx struct {
 	SwapValue  string
 	SwapType   tokens.SwapType
 	SwapNonce  uint64
+
+	PayoutLegCount int
 }
 
 func getSwapType(isSwapin bool) tokens.SwapType {
@@ -28,17 +31,88 @@ func getSwapType(isSwapin bool) tokens.SwapType {
 	return tokens.SwapoutType
 }
 
-func addInitialSwapResult(swapInfo *tokens.TxSwapInfo, status mongodb.SwapStatus, isSwapin bool) (err error) {
+func addInitialSwapResult(swapInfo *tokens.TxSwapInfo, status mongodb.SwapStatus, isSwapin bool, memo string) (err error) {
 	txid := swapInfo.Hash
-	var swapType tokens.SwapType
-	if isSwapin {
-		swapType = tokens.SwapinType
+	swapResult := newInitialSwapResult(swapInfo, status, isSwapin, memo)
+	err = swapResults.AddSwapResult(isSwapin, swapResult)
+	if errors.Is(err, mongodb.ErrItemIsDup) {
+		// result is already created (eg. crashed after adding it), keep idempotent
+		err = syncInitialSwapResult(swapResult, isSwapin)
+	}
+	if err != nil {
+		logWorkerError("add", "addInitialSwapResult", err, "txid", txid)
 	} else {
-		swapType = tokens.SwapoutType
+		logWorker("add", "addInitialSwapResult", "txid", txid)
 	}
+	return err
+}
+
+// statuses of swap result added by verify job
+var initialSwapResultStatuses = []mongodb.SwapStatus{
+	mongodb.MatchTxEmpty,
+	mongodb.TxWithBigValue,
+	mongodb.TxWithWrongMemo,
+	mongodb.BindAddrIsContract,
+	mongodb.SwapValueIsDust,
+	mongodb.TxWithWrongValue,
+}
+
+func isInitialSwapResultStatus(status mongodb.SwapStatus) bool {
+	for _, s := range initialSwapResultStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// syncInitialSwapResult sync status and verified tx info (eg. reverified after reorg)
+// of existing swap result which has not been processed
+func syncInitialSwapResult(swapResult *mongodb.MgoSwapResult, isSwapin bool) error {
+	txid, pairID, bind := swapResult.TxID, swapResult.PairID, swapResult.Bind
+	res, err := swapResults.FindSwapResult(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return err
+	}
+	if res.SwapTx != "" || !isInitialSwapResultStatus(res.Status) {
+		logWorker("add", "swap result already exist", "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin, "status", res.Status)
+		return nil
+	}
+	return swapResults.UpdateSwapResultVerifiedInfo(isSwapin, swapResult)
+}
+
+// swapResultStore swap result storage used by verify job
+type swapResultStore interface {
+	AddSwapResult(isSwapin bool, result *mongodb.MgoSwapResult) error
+	FindSwapResult(isSwapin bool, txid, pairID, bind string) (*mongodb.MgoSwapResult, error)
+	UpdateSwapResultVerifiedInfo(isSwapin bool, result *mongodb.MgoSwapResult) error
+}
+
+var swapResults swapResultStore = mgoSwapResultStore{}
+
+type mgoSwapResultStore struct{}
+
+func (mgoSwapResultStore) AddSwapResult(isSwapin bool, result *mongodb.MgoSwapResult) error {
+	if isSwapin {
+		return mongodb.AddSwapinResult(result)
+	}
+	return mongodb.AddSwapoutResult(result)
+}
+
+func (mgoSwapResultStore) FindSwapResult(isSwapin bool, txid, pairID, bind string) (*mongodb.MgoSwapResult, error) {
+	return mongodb.FindSwapResult(isSwapin, txid, pairID, bind)
+}
+
+func (mgoSwapResultStore) UpdateSwapResultVerifiedInfo(isSwapin bool, result *mongodb.MgoSwapResult) error {
+	return mongodb.UpdateSwapResultVerifiedInfo(isSwapin, result)
+}
+
+// newInitialSwapResult new swap result of verified swap info
+func newInitialSwapResult(swapInfo *tokens.TxSwapInfo, status mongodb.SwapStatus, isSwapin bool, memo string) *mongodb.MgoSwapResult {
+	swapType := getSwapType(isSwapin)
 	swapResult := &mongodb.MgoSwapResult{
 		PairID:     swapInfo.PairID,
-		TxID:       txid,
+		TxID:       swapInfo.Hash,
 		TxTo:       swapInfo.TxTo,
 		TxHeight:   swapInfo.Height,
 		TxTime:     swapInfo.Timestamp,
@@ -54,19 +128,13 @@ func addInitialSwapResult(swapInfo *tokens.TxSwapInfo, status mongodb.SwapStatus
 		SwapNonce:  0,
 		Status:     status,
 		Timestamp:  now(),
-		Memo:       "",
+		Memo:       memo,
 	}
-	if isSwapin {
-		err = mongodb.AddSwapinResult(swapResult)
-	} else {
-		err = mongodb.AddSwapoutResult(swapResult)
-	}
-	if err != nil {
-		logWorkerError("add", "addInitialSwapResult", err, "txid", txid)
-	} else {
-		logWorker("add", "addInitialSwapResult", "txid", txid)
+	if status == mongodb.MatchTxEmpty || status == mongodb.TxWithBigValue {
+		// block hash of the verified tx, the swap is reverified fully before signing if it's empty
+		swapResult.TxBlockHash = swapInfo.BlockHash
 	}
-	return err
+	return swapResult
 }
 
 func updateSwapResult(txid, pairID, bind string, mtx *MatchTx) (err error) {
@@ -77,6 +145,7 @@ func updateSwapResult(txid, pairID, bind string, mtx *MatchTx) (err error) {
 	if mtx.SwapHeight == 0 {
 		updates.SwapValue = mtx.SwapValue
 		updates.SwapNonce = mtx.SwapNonce
+		updates.PayoutLegCount = mtx.PayoutLegCount
 		updates.SwapHeight = 0
 		updates.SwapTime = 0
 		if mtx.SwapTx != "" {
@@ -271,6 +340,7 @@ func sendSignedTransaction(bridge tokens.CrossChainBridge, signedTx interface{},
 		nonceSetter.SetNonce(pairID, swapNonce+1) // increase for next usage
 	}
 
+	watchBroadcastTx(isSwapin, txHash, txid, pairID, bind)
 	go sendTxLoopUntilSuccess(bridge, txHash, signedTx, args)
 
 	return txHash, nil
@@ -290,6 +360,9 @@ func sendTxLoopUntilSuccess(bridge tokens.CrossChainBridge, txHash string, signe
 	for loop := 1; loop <= sendTxLoopCount; loop++ {
 		txStatus, err := bridge.GetTransactionStatus(txHash)
 		if err == nil && txStatus.BlockHeight > 0 {
+			if args.PayoutLeg != nil {
+				break // payout legs are updated by stable job
+			}
 			matchTx := &MatchTx{
 				SwapTx:     txHash,
 				SwapHeight: txStatus.BlockHeight,
@@ -0,0 +1,280 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const (
+	dailyReportDateFormat    = "2006-01-02"
+	dailyReportRetryInterval = 10 * time.Minute
+	dailyReportPushTimeout   = 60 // seconds
+)
+
+var (
+	dailyReportStarter sync.Once
+
+	errDailyReportDayNotEnded = errors.New("daily report day is not ended")
+)
+
+// StartDailyReportJob generate report of the previous day after each day ends
+func StartDailyReportJob() {
+	config := params.GetServerConfig().DailyReport
+	if config == nil || !config.Enable {
+		return
+	}
+	dailyReportStarter.Do(func() {
+		logWorker("dailyreport", "start daily report job", "timezone", config.GetLocation(), "delay", config.Delay)
+		go runDailyReportJob(config)
+	})
+}
+
+func runDailyReportJob(config *params.DailyReportConfig) {
+	location := config.GetLocation()
+	delay := time.Duration(config.Delay) * time.Second
+	for {
+		today := startOfDay(time.Now().Add(-delay), location)
+		yesterday := today.AddDate(0, 0, -1).Format(dailyReportDateFormat)
+		var err error
+		if _, errf := mongodb.FindDailyReport(yesterday); errf != nil {
+			_, err = GenerateDailyReport(yesterday)
+			if err != nil {
+				logWorkerError("dailyreport", "generate daily report failed", err, "date", yesterday)
+			}
+		}
+		wait := time.Until(today.AddDate(0, 0, 1).Add(delay))
+		if err != nil && wait > dailyReportRetryInterval {
+			wait = dailyReportRetryInterval
+		}
+		time.Sleep(wait)
+	}
+}
+
+func startOfDay(t time.Time, location *time.Location) time.Time {
+	t = t.In(location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+}
+
+// GenerateDailyReport generate, store and push report of ended date (2006-01-02),
+// existing report of the date is replaced.
+func GenerateDailyReport(date string) (*mongodb.MgoDailyReport, error) {
+	location := params.GetServerConfig().DailyReport.GetLocation()
+	day, err := time.ParseInLocation(dailyReportDateFormat, date, location)
+	if err != nil {
+		return nil, fmt.Errorf("wrong date '%v', want format %v", date, dailyReportDateFormat)
+	}
+	start, end := day.Unix(), day.AddDate(0, 0, 1).Unix()
+	if end > now() {
+		return nil, errDailyReportDayNotEnded
+	}
+	report := &mongodb.MgoDailyReport{
+		Key:       day.Format(dailyReportDateFormat),
+		TimeZone:  location.String(),
+		StartTime: start,
+		EndTime:   end,
+	}
+	for _, isSwapin := range []bool{true, false} {
+		stats, errf := collectDailyPairStats(isSwapin, start, end)
+		if errf != nil {
+			return nil, errf
+		}
+		report.Pairs = append(report.Pairs, stats...)
+		backlogs, errf := collectBacklogs(isSwapin)
+		if errf != nil {
+			return nil, errf
+		}
+		if isSwapin {
+			report.SwapinBacklogs = backlogs
+		} else {
+			report.SwapoutBacklogs = backlogs
+		}
+	}
+	report.GenerateTime = now()
+	report.Text = renderDailyReport(report, location)
+	err = mongodb.UpsertDailyReport(report)
+	if err != nil {
+		return nil, err
+	}
+	logWorker("dailyreport", "generate daily report success", "date", report.Key, "pairs", len(report.Pairs))
+	pushDailyReport(report)
+	return report, nil
+}
+
+func getStatusesOfCategory(category mongodb.SwapStatusCategory) (statuses []mongodb.SwapStatus) {
+	for _, info := range mongodb.GetStatusCatalog() {
+		if info.Category == category {
+			statuses = append(statuses, info.Code)
+		}
+	}
+	return statuses
+}
+
+func collectDailyPairStats(isSwapin bool, start, end int64) ([]*mongodb.MgoDailyPairStat, error) {
+	categories := []mongodb.SwapStatusCategory{
+		mongodb.StatusCategorySuccess,
+		mongodb.StatusCategoryFailed,
+		mongodb.StatusCategoryManual,
+	}
+	statusCategory := make(map[mongodb.SwapStatus]mongodb.SwapStatusCategory)
+	var statuses []mongodb.SwapStatus
+	for _, category := range categories {
+		for _, status := range getStatusesOfCategory(category) {
+			statusCategory[status] = category
+			statuses = append(statuses, status)
+		}
+	}
+	results, err := mongodb.FindSwapResultsUpdatedBetween(isSwapin, statuses, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	type pairAcc struct {
+		stat         *mongodb.MgoDailyPairStat
+		volume, fee  *big.Int
+		totalLatency int64
+		latencyCount int64
+	}
+	accs := make(map[string]*pairAcc)
+	for _, res := range results {
+		acc, exist := accs[res.PairID]
+		if !exist {
+			acc = &pairAcc{
+				stat:   &mongodb.MgoDailyPairStat{PairID: res.PairID, IsSwapin: isSwapin},
+				volume: big.NewInt(0),
+				fee:    big.NewInt(0),
+			}
+			accs[res.PairID] = acc
+		}
+		switch statusCategory[res.Status] {
+		case mongodb.StatusCategorySuccess:
+			acc.stat.Completed++
+			value, _ := new(big.Int).SetString(res.Value, 10)
+			if value == nil {
+				break
+			}
+			acc.volume.Add(acc.volume, value)
+			acc.fee.Add(acc.fee, calcSwapFee(res, value, isSwapin))
+			if res.TxTime != 0 && res.SwapTime >= res.TxTime {
+				acc.totalLatency += int64(res.SwapTime - res.TxTime)
+				acc.latencyCount++
+			}
+		case mongodb.StatusCategoryFailed:
+			acc.stat.Failed++
+		default:
+			acc.stat.Held++
+		}
+	}
+
+	stats := make([]*mongodb.MgoDailyPairStat, 0, len(accs))
+	for _, acc := range accs {
+		acc.stat.Volume = acc.volume.String()
+		acc.stat.Fee = acc.fee.String()
+		if acc.latencyCount > 0 {
+			acc.stat.AvgLatency = acc.totalLatency / acc.latencyCount
+		}
+		stats = append(stats, acc.stat)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].PairID < stats[j].PairID })
+	return stats, nil
+}
+
+// calcSwapFee fee is deposit value minus swapped value, in deposit token unit
+func calcSwapFee(res *mongodb.MgoSwapResult, value *big.Int, isSwapin bool) *big.Int {
+	fromTokenCfg := tokens.GetTokenConfig(res.PairID, isSwapin)
+	toTokenCfg := tokens.GetTokenConfig(res.PairID, !isSwapin)
+	swapValue, _ := new(big.Int).SetString(res.SwapValue, 10)
+	if fromTokenCfg == nil || toTokenCfg == nil || swapValue == nil {
+		return big.NewInt(0)
+	}
+	swapValue = tokens.ConvertTokenValue(swapValue, *toTokenCfg.Decimals, *fromTokenCfg.Decimals)
+	fee := new(big.Int).Sub(value, swapValue)
+	if fee.Sign() < 0 {
+		return big.NewInt(0)
+	}
+	return fee
+}
+
+func collectBacklogs(isSwapin bool) (map[string]int64, error) {
+	counts, err := mongodb.CountSwapResultsByStatus(isSwapin, getStatusesOfCategory(mongodb.StatusCategoryPending))
+	if err != nil {
+		return nil, err
+	}
+	backlogs := make(map[string]int64, len(counts))
+	for status, count := range counts {
+		backlogs[status.String()] = count
+	}
+	return backlogs, nil
+}
+
+func formatReportValue(pairID string, isSwapin bool, value string) string {
+	tokenCfg := tokens.GetTokenConfig(pairID, isSwapin)
+	bigValue, _ := new(big.Int).SetString(value, 10)
+	if tokenCfg == nil || tokenCfg.Decimals == nil || bigValue == nil {
+		return value
+	}
+	return tokenCfg.FormatValue(bigValue)
+}
+
+func renderDailyReport(report *mongodb.MgoDailyReport, location *time.Location) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "daily report of %v (%v)\n", report.Key, report.TimeZone)
+	fmt.Fprintf(&sb, "generated at %v\n", time.Unix(report.GenerateTime, 0).In(location).Format("2006-01-02 15:04:05"))
+	if len(report.Pairs) == 0 {
+		sb.WriteString("\nno completed, failed or held swaps\n")
+	}
+	for _, stat := range report.Pairs {
+		direction := "swapout"
+		if stat.IsSwapin {
+			direction = "swapin"
+		}
+		fmt.Fprintf(&sb, "\n%v %v\n", stat.PairID, direction)
+		fmt.Fprintf(&sb, "  completed %v, failed %v, held %v\n", stat.Completed, stat.Failed, stat.Held)
+		fmt.Fprintf(&sb, "  volume %v, fee %v\n", formatReportValue(stat.PairID, stat.IsSwapin, stat.Volume), formatReportValue(stat.PairID, stat.IsSwapin, stat.Fee))
+		fmt.Fprintf(&sb, "  average latency %vs\n", stat.AvgLatency)
+	}
+	writeBacklogs := func(direction string, backlogs map[string]int64) {
+		names := make([]string, 0, len(backlogs))
+		for name := range backlogs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&sb, "  %v %v %v\n", direction, name, backlogs[name])
+		}
+	}
+	sb.WriteString("\nbacklogs\n")
+	writeBacklogs("swapin", report.SwapinBacklogs)
+	writeBacklogs("swapout", report.SwapoutBacklogs)
+	return sb.String()
+}
+
+func pushDailyReport(report *mongodb.MgoDailyReport) {
+	config := params.GetServerConfig().DailyReport
+	if config == nil || config.PushURL == "" {
+		return
+	}
+	url := config.PushURL
+	resp, err := client.HTTPPost(url, report, nil, nil, dailyReportPushTimeout)
+	if err == nil {
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("response status %v", resp.Status)
+		}
+	}
+	if err != nil {
+		logWorkerError("dailyreport", "push daily report failed", err, "date", report.Key, "url", url)
+		return
+	}
+	logWorker("dailyreport", "push daily report success", "date", report.Key, "url", url)
+}
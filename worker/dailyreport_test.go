@@ -0,0 +1,22 @@
+package worker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStartOfDay(t *testing.T) {
+	shanghai := time.FixedZone("UTC+8", 8*3600)
+	at := time.Date(2021, 6, 1, 20, 30, 0, 0, time.UTC) // 2021-06-02 04:30 in UTC+8
+
+	if have := startOfDay(at, time.UTC).Format(dailyReportDateFormat); have != "2021-06-01" {
+		t.Errorf("want day 2021-06-01 in UTC, have %v", have)
+	}
+	day := startOfDay(at, shanghai)
+	if have := day.Format(dailyReportDateFormat); have != "2021-06-02" {
+		t.Errorf("want day 2021-06-02 in UTC+8, have %v", have)
+	}
+	if want := time.Date(2021, 6, 1, 16, 0, 0, 0, time.UTC); !day.Equal(want) {
+		t.Errorf("want day start %v, have %v", want, day.UTC())
+	}
+}
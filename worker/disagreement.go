@@ -0,0 +1,191 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/dcrm"
+	"github.com/anyswap/CrossChain-Bridge/internal/metrics"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// sides of sign disagreement observed by this node
+const (
+	DisagreeSideInitiator = "initiator"
+	DisagreeSideAcceptor  = "acceptor"
+
+	disagreementPushTimeout = 60 // seconds
+)
+
+// checks which differ between nodes of sign disagreement
+const (
+	DisagreeCheckValue         = "value"
+	DisagreeCheckGas           = "gas"
+	DisagreeCheckConfirmations = "confirmations"
+	DisagreeCheckMsgHash       = "msghash"
+	DisagreeCheckConfig        = "config"
+	DisagreeCheckOther         = "other"
+)
+
+var (
+	// replaced in tests
+	disagreements         disagreementStore = mgoDisagreementStore{}
+	getSignStatusDetail                     = dcrm.GetSignStatusDetail
+	pushDisagreementAlert                   = doPushDisagreementAlert
+)
+
+type disagreementStore interface {
+	Add(item *mongodb.MgoSignDisagreement) error
+}
+
+type mgoDisagreementStore struct{}
+
+func (mgoDisagreementStore) Add(item *mongodb.MgoSignDisagreement) error {
+	if !mongodb.HasClient() {
+		return nil
+	}
+	return mongodb.AddSignDisagreement(item)
+}
+
+// SignDisagreementAlert sign disagreement pushed to 'DisagreementPushURL'
+type SignDisagreementAlert struct {
+	Side string `json:"side"`
+	*mongodb.MgoSignDisagreement
+}
+
+// getDisagreeCheck classify verify error to the differing check
+func getDisagreeCheck(err error) string {
+	switch {
+	case err == nil:
+		return mongodb.DisagreeCheckUnknown
+	case errors.Is(err, tokens.ErrWrongSwapValue),
+		errors.Is(err, tokens.ErrTxWithWrongValue),
+		errors.Is(err, tokens.ErrSwapoutValueIsDust),
+		errors.Is(err, tokens.ErrSwappedValueIsDust),
+		errors.Is(err, tokens.ErrPayoutExceedsCap):
+		return DisagreeCheckValue
+	case errors.Is(err, tokens.ErrWrongExtraArgs),
+		errors.Is(err, tokens.ErrEstimateGasFailed):
+		return DisagreeCheckGas
+	case errors.Is(err, tokens.ErrTxNotStable),
+		errors.Is(err, tokens.ErrTxNotFound):
+		return DisagreeCheckConfirmations
+	case errors.Is(err, tokens.ErrMsgHashMismatch),
+		errors.Is(err, tokens.ErrWrongCountOfMsgHashes):
+		return DisagreeCheckMsgHash
+	case errors.Is(err, tokens.ErrUnknownPairID),
+		errors.Is(err, tokens.ErrNoBtcBridge),
+		errors.Is(err, tokens.ErrSwapIsClosed),
+		errors.Is(err, errInitiatorMismatch),
+		errors.Is(err, errDeprecatedIdentifierExpired):
+		return DisagreeCheckConfig
+	default:
+		return DisagreeCheckOther
+	}
+}
+
+// getEnodeID get node ID from enode url 'enode://<id>@<ip>:<port>'
+func getEnodeID(enode string) string {
+	enode = strings.TrimPrefix(enode, "enode://")
+	if pos := strings.Index(enode, "@"); pos >= 0 {
+		enode = enode[:pos]
+	}
+	return strings.ToLower(enode)
+}
+
+func newSignDisagreement(keyID, initiator string, args *tokens.BuildTxArgs) *mongodb.MgoSignDisagreement {
+	item := &mongodb.MgoSignDisagreement{
+		Key:       keyID,
+		Initiator: strings.ToLower(initiator),
+		Check:     mongodb.DisagreeCheckUnknown,
+		Timestamp: time.Now().Unix(),
+	}
+	if args != nil {
+		item.IsSwapin = args.IsSwapin()
+		item.TxID = args.SwapID
+		item.PairID = args.PairID
+		item.Bind = args.Bind
+	}
+	return item
+}
+
+// recordInitiatorDisagreement record the group's replies of sign initiated by this node,
+// the initiator agrees by building the tx, so any disagree reply is a split.
+func recordInitiatorDisagreement(args *tokens.BuildTxArgs, keyID, initiator string) {
+	status, err := getSignStatusDetail(keyID)
+	if err != nil || status == nil {
+		logWorkerWarn("disagreement", "get sign status detail failed", "keyID", keyID, "err", err)
+		return
+	}
+	item := newSignDisagreement(keyID, initiator, args)
+	for _, reply := range status.AllReply {
+		switch {
+		case strings.EqualFold(reply.Status, "Agree"):
+			item.Agreed = append(item.Agreed, getEnodeID(reply.Enode))
+		case strings.EqualFold(reply.Status, dcrm.SignStatusDisagree):
+			item.Disagreed = append(item.Disagreed, getEnodeID(reply.Enode))
+		}
+	}
+	if len(item.Disagreed) == 0 {
+		return
+	}
+	reportSignDisagreement(DisagreeSideInitiator, item)
+}
+
+// recordAcceptorDisagreement record disagreement of this node to sign built by initiator,
+// args may be nil if msg context can not be parsed.
+func recordAcceptorDisagreement(info *dcrm.SignInfoData, args *tokens.BuildTxArgs, verifyErr error, reason string) {
+	item := newSignDisagreement(info.Key, getSignInfoInitiator(info), args)
+	item.Check = getDisagreeCheck(verifyErr)
+	selfID := getEnodeID(dcrm.GetSelfEnode())
+	item.Disagreed = []string{selfID}
+	item.Reasons = []*mongodb.MgoDisagreeReason{{Node: selfID, Reason: reason}}
+	reportSignDisagreement(DisagreeSideAcceptor, item)
+}
+
+func reportSignDisagreement(side string, item *mongodb.MgoSignDisagreement) {
+	metrics.SignDisagreements.Inc(side, item.Check)
+	logWorkerWarn("disagreement", "sign disagreement detected", "side", side, "keyID", item.Key, "check", item.Check,
+		"txid", item.TxID, "pairID", item.PairID, "bind", item.Bind, "initiator", item.Initiator,
+		"agreed", len(item.Agreed), "disagreed", len(item.Disagreed))
+	if err := disagreements.Add(item); err != nil {
+		logWorkerError("disagreement", "record sign disagreement failed", err, "keyID", item.Key)
+	}
+	pushDisagreementAlert(&SignDisagreementAlert{Side: side, MgoSignDisagreement: item})
+}
+
+func doPushDisagreementAlert(alert *SignDisagreementAlert) {
+	pushURL := params.GetConfig().Dcrm.DisagreementPushURL
+	if pushURL == "" {
+		return
+	}
+	resp, err := client.HTTPPost(pushURL, alert, nil, nil, disagreementPushTimeout)
+	if err == nil {
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("response status %v", resp.Status)
+		}
+	}
+	if err != nil {
+		logWorkerError("disagreement", "push sign disagreement alert failed", err, "keyID", alert.Key, "url", pushURL)
+	}
+}
+
+// GetRecentSignDisagreements get recent sign disagreements grouped by differing check
+func GetRecentSignDisagreements(limit int) (map[string][]*mongodb.MgoSignDisagreement, error) {
+	items, err := mongodb.GetRecentSignDisagreements(limit)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]*mongodb.MgoSignDisagreement)
+	for _, item := range items {
+		result[item.Check] = append(result[item.Check], item)
+	}
+	return result, nil
+}
@@ -0,0 +1,112 @@
+package worker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/dcrm"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// memDisagreementStore in memory sign disagreement storage
+type memDisagreementStore struct {
+	items []*mongodb.MgoSignDisagreement
+}
+
+func (s *memDisagreementStore) Add(item *mongodb.MgoSignDisagreement) error {
+	s.items = append(s.items, item)
+	return nil
+}
+
+func useDisagreementStore(t *testing.T, status *dcrm.SignStatus) (*memDisagreementStore, *[]*SignDisagreementAlert) {
+	store := &memDisagreementStore{}
+	var alerts []*SignDisagreementAlert
+	oldStore, oldGetStatus, oldPush := disagreements, getSignStatusDetail, pushDisagreementAlert
+	disagreements = store
+	getSignStatusDetail = func(string) (*dcrm.SignStatus, error) { return status, nil }
+	pushDisagreementAlert = func(alert *SignDisagreementAlert) { alerts = append(alerts, alert) }
+	t.Cleanup(func() {
+		disagreements, getSignStatusDetail, pushDisagreementAlert = oldStore, oldGetStatus, oldPush
+	})
+	return store, &alerts
+}
+
+func TestGetDisagreeCheck(t *testing.T) {
+	tests := []struct {
+		err   error
+		check string
+	}{
+		{nil, mongodb.DisagreeCheckUnknown},
+		{tokens.ErrWrongSwapValue, DisagreeCheckValue},
+		{fmt.Errorf("wrapped: %w", tokens.ErrTxWithWrongValue), DisagreeCheckValue},
+		{tokens.ErrWrongExtraArgs, DisagreeCheckGas},
+		{tokens.ErrTxNotStable, DisagreeCheckConfirmations},
+		{tokens.ErrMsgHashMismatch, DisagreeCheckMsgHash},
+		{errInitiatorMismatch, DisagreeCheckConfig},
+		{fmt.Errorf("some error"), DisagreeCheckOther},
+	}
+	for _, test := range tests {
+		if have := getDisagreeCheck(test.err); have != test.check {
+			t.Errorf("getDisagreeCheck(%v) have %v want %v", test.err, have, test.check)
+		}
+	}
+}
+
+func TestRecordInitiatorDisagreement(t *testing.T) {
+	store, alerts := useDisagreementStore(t, &dcrm.SignStatus{
+		Status: "Failure",
+		AllReply: []*dcrm.SignReply{
+			{Enode: "enode://AAAA@127.0.0.1:4441", Status: "Agree"},
+			{Enode: "enode://bbbb@127.0.0.1:4442", Status: "DisAgree"},
+			{Enode: "enode://cccc@127.0.0.1:4443", Status: "Pending"},
+		},
+	})
+	args := &tokens.BuildTxArgs{SwapInfo: tokens.SwapInfo{SwapID: "txid", PairID: "pair", Bind: "bind", SwapType: tokens.SwapinType}}
+	recordInitiatorDisagreement(args, "keyID", "0xInitiator")
+
+	if len(store.items) != 1 || len(*alerts) != 1 {
+		t.Fatalf("want 1 disagreement and alert, have %v and %v", len(store.items), len(*alerts))
+	}
+	item := store.items[0]
+	if item.Key != "keyID" || !item.IsSwapin || item.TxID != "txid" || item.Initiator != "0xinitiator" {
+		t.Errorf("wrong disagreement %+v", item)
+	}
+	if item.Check != mongodb.DisagreeCheckUnknown {
+		t.Errorf("initiator does not know the reason, have check %v", item.Check)
+	}
+	if len(item.Agreed) != 1 || item.Agreed[0] != "aaaa" || len(item.Disagreed) != 1 || item.Disagreed[0] != "bbbb" {
+		t.Errorf("wrong agreed %v disagreed %v", item.Agreed, item.Disagreed)
+	}
+	if (*alerts)[0].Side != DisagreeSideInitiator {
+		t.Errorf("wrong alert side %v", (*alerts)[0].Side)
+	}
+}
+
+func TestRecordInitiatorWithoutDisagree(t *testing.T) {
+	store, alerts := useDisagreementStore(t, &dcrm.SignStatus{
+		Status:   "Timeout",
+		AllReply: []*dcrm.SignReply{{Enode: "enode://aaaa@127.0.0.1:4441", Status: "Agree"}},
+	})
+	recordInitiatorDisagreement(&tokens.BuildTxArgs{}, "keyID", "0xinitiator")
+	if len(store.items) != 0 || len(*alerts) != 0 {
+		t.Errorf("should not record without disagree replies")
+	}
+}
+
+func TestRecordAcceptorDisagreement(t *testing.T) {
+	store, alerts := useDisagreementStore(t, nil)
+	info := &dcrm.SignInfoData{Key: "keyID", Account: "0xinitiator"}
+	recordAcceptorDisagreement(info, nil, tokens.ErrTxNotStable, "tx not stable")
+
+	if len(store.items) != 1 || len(*alerts) != 1 {
+		t.Fatalf("want 1 disagreement and alert, have %v and %v", len(store.items), len(*alerts))
+	}
+	item := store.items[0]
+	if item.Check != DisagreeCheckConfirmations || len(item.Reasons) != 1 || item.Reasons[0].Reason != "tx not stable" {
+		t.Errorf("wrong disagreement %+v", item)
+	}
+	if (*alerts)[0].Side != DisagreeSideAcceptor {
+		t.Errorf("wrong alert side %v", (*alerts)[0].Side)
+	}
+}
@@ -0,0 +1,92 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const (
+	defaultMaxMsgContextSize = 256 * 1024 // bytes
+
+	maxMsgContextValueBits = 256
+	maxMsgContextOutPoints = 10000
+)
+
+// replaced by oracle config 'MaxMsgContextSize'
+var maxMsgContextSize = defaultMaxMsgContextSize
+
+// parseMsgContext parse build tx args from msg context constructed by initiator,
+// oversized context is rejected before parsing and unknown fields are not allowed.
+func parseMsgContext(msgContext []string) (*tokens.BuildTxArgs, error) {
+	if len(msgContext) == 0 {
+		return nil, fmt.Errorf("%w: empty", errWrongMsgContext)
+	}
+	size := 0
+	for _, ctx := range msgContext {
+		size += len(ctx)
+	}
+	if size > maxMsgContextSize {
+		return nil, fmt.Errorf("%w: size %v exceeds %v", errWrongMsgContext, size, maxMsgContextSize)
+	}
+	var args tokens.BuildTxArgs
+	decoder := json.NewDecoder(strings.NewReader(msgContext[0]))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&args); err != nil {
+		return nil, fmt.Errorf("%w: %v", errWrongMsgContext, err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("%w: trailing data", errWrongMsgContext)
+	}
+	if err := checkMsgContextArgs(&args); err != nil {
+		return nil, fmt.Errorf("%w: %v", errWrongMsgContext, err)
+	}
+	return &args, nil
+}
+
+// checkMsgContextArgs check numeric fields are in sane ranges before any chain rpc
+func checkMsgContextArgs(args *tokens.BuildTxArgs) error {
+	values := map[string]*big.Int{
+		"value":       args.Value,
+		"originValue": args.OriginValue,
+		"swapvalue":   args.SwapValue,
+	}
+	if leg := args.PayoutLeg; leg != nil {
+		if leg.Count < 2 || leg.Count > tokens.MaxPayoutLegs || leg.Index < 0 || leg.Index >= leg.Count {
+			return fmt.Errorf("wrong payout leg %v/%v", leg.Index, leg.Count)
+		}
+	}
+	if extra := args.Extra; extra != nil {
+		if ethExtra := extra.EthExtra; ethExtra != nil {
+			values["gasPrice"] = ethExtra.GasPrice
+			values["gasTipCap"] = ethExtra.GasTipCap
+			values["gasFeeCap"] = ethExtra.GasFeeCap
+		}
+		if btcExtra := extra.BtcExtra; btcExtra != nil {
+			if btcExtra.RelayFeePerKb != nil && *btcExtra.RelayFeePerKb < 0 {
+				return fmt.Errorf("negative relayFeePerKb %v", *btcExtra.RelayFeePerKb)
+			}
+			if len(btcExtra.PreviousOutPoints) > maxMsgContextOutPoints {
+				return fmt.Errorf("too many previousOutPoints %v", len(btcExtra.PreviousOutPoints))
+			}
+			for _, outPoint := range btcExtra.PreviousOutPoints {
+				if outPoint == nil {
+					return errors.New("nil previousOutPoint")
+				}
+			}
+		}
+		if rippleExtra := extra.RippleExtra; rippleExtra != nil && rippleExtra.Fee != nil && *rippleExtra.Fee < 0 {
+			return fmt.Errorf("negative ripple fee %v", *rippleExtra.Fee)
+		}
+	}
+	for name, value := range values {
+		if value != nil && (value.Sign() < 0 || value.BitLen() > maxMsgContextValueBits) {
+			return fmt.Errorf("%v %v out of range", name, value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/dcrm"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func newTestMsgContext(t *testing.T, identifier string) string {
+	gas, nonce := uint64(90000), uint64(1)
+	args := &tokens.BuildTxArgs{
+		SwapInfo: tokens.SwapInfo{
+			Identifier: identifier,
+			PairID:     "pair",
+			SwapID:     "0x0000000000000000000000000000000000000000000000000000000000000001",
+			SwapType:   tokens.SwapinType,
+			Bind:       "0x1111111111111111111111111111111111111111",
+		},
+		SwapValue: big.NewInt(1000),
+		Extra: &tokens.AllExtras{EthExtra: &tokens.EthExtraArgs{
+			Gas:      &gas,
+			GasPrice: big.NewInt(1e9),
+			Nonce:    &nonce,
+		}},
+	}
+	data, err := json.Marshal(args.GetExtraArgs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseMsgContext(t *testing.T) {
+	valid := newTestMsgContext(t, "test-bridge")
+	if _, err := parseMsgContext([]string{valid}); err != nil {
+		t.Fatalf("valid msg context is rejected, %v", err)
+	}
+	for _, msgContext := range [][]string{
+		nil,
+		{""},
+		{"null x"},
+		{valid + "{}"},
+		{strings.Replace(valid, `"pairid"`, `"unknown"`, 1)},
+		{strings.Replace(valid, `"swapvalue":1000`, `"swapvalue":-1000`, 1)},
+		{strings.Replace(valid, `"swapvalue":1000`, `"swapvalue":1`+strings.Repeat("0", 100), 1)},
+		{strings.Replace(valid, `"swapvalue":1000`, `"swapvalue":"1000"`, 1)},
+		{strings.Replace(valid, `"gasPrice":1000000000`, `"gasPrice":-1`, 1)},
+		{strings.Replace(valid, `"bind"`, `"payoutLeg":{"index":2,"count":2},"bind"`, 1)},
+		{valid, strings.Repeat("a", maxMsgContextSize)},
+	} {
+		if _, err := parseMsgContext(msgContext); !errors.Is(err, errWrongMsgContext) {
+			t.Errorf("want wrong msg context error, have %v for %.100q", err, msgContext)
+		}
+	}
+}
+
+func TestParseOversizedMsgContext(t *testing.T) {
+	oversized := `{"swapInfo":{"pairid":"` + strings.Repeat("a", 16*maxMsgContextSize) + `"}}`
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err := parseMsgContext([]string{oversized})
+	runtime.ReadMemStats(&after)
+	if !errors.Is(err, errWrongMsgContext) {
+		t.Errorf("want wrong msg context error, have %v", err)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > uint64(maxMsgContextSize) {
+		t.Errorf("oversized msg context should be rejected before parsing, allocated %v bytes", allocated)
+	}
+}
+
+func TestVerifySignInfoMalformedMsgContext(t *testing.T) {
+	params.SetConfig(&params.BridgeConfig{Identifier: "test-bridge"})
+	base := newTestMsgContext(t, "other-bridge")
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		data := []byte(base)
+		switch i % 3 {
+		case 0: // flip bytes
+			for j := 0; j < 1+rnd.Intn(4); j++ {
+				data[rnd.Intn(len(data))] = byte(rnd.Intn(256))
+			}
+		case 1: // truncate
+			data = data[:rnd.Intn(len(data))]
+		default: // duplicate a chunk
+			start := rnd.Intn(len(data))
+			end := start + rnd.Intn(len(data)-start)
+			data = append(data[:end:end], data[start:]...)
+		}
+		signInfo := &dcrm.SignInfoData{Key: "key", MsgContext: []string{string(data)}}
+		_, err := verifySignInfo(signInfo)
+		if !errors.Is(err, errWrongMsgContext) && !errors.Is(err, errIdentifierMismatch) {
+			t.Fatalf("malformed msg context should be discarded, have %v for %q", err, data)
+		}
+	}
+}
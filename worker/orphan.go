@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+)
+
+// registrations are orphaned if they have no result a while after verified
+const orphanedSwapMinAge = int64(300)
+
+// statuses of registered swap which has swap result added by verify job
+var statusesWithSwapResult = []mongodb.SwapStatus{
+	mongodb.TxNotSwapped,
+	mongodb.TxWithBigValue,
+	mongodb.TxWithWrongMemo,
+	mongodb.BindAddrIsContract,
+	mongodb.SwapValueIsDust,
+	mongodb.TxWithWrongValue,
+}
+
+// repairOrphanedSwaps reverify registered swaps which are verified but have
+// no swap result (eg. crashed between updating status and adding result),
+// verify will add the missing swap result.
+func repairOrphanedSwaps() {
+	since := getSepTimeInFind(getMaxSwapLifetime())
+	before := getSepTimeInFind(orphanedSwapMinAge)
+	findOrphanedSwaps := func(isSwapin bool) ([]*mongodb.MgoSwap, error) {
+		return mongodb.FindOrphanedSwaps(isSwapin, statusesWithSwapResult, since, before)
+	}
+	repairOrphanedSwapsWith(findOrphanedSwaps, processSwapVerify)
+}
+
+// repairOrphanedSwapsWith reverify orphaned swaps, swaps being verified by verify job are skipped
+func repairOrphanedSwapsWith(findOrphanedSwaps func(isSwapin bool) ([]*mongodb.MgoSwap, error), verify func(swap *mongodb.MgoSwap, isSwapin bool) error) (repaired int) {
+	for _, isSwapin := range []bool{true, false} {
+		swaps, err := findOrphanedSwaps(isSwapin)
+		if err != nil {
+			logWorkerError("orphan", "find orphaned swaps failed", err, "isSwapin", isSwapin)
+			continue
+		}
+		repairedCount := 0
+		for _, swap := range swaps {
+			logWorkerWarn("orphan", "found orphaned swap", "txid", swap.TxID, "pairID", swap.PairID, "bind", swap.Bind, "isSwapin", isSwapin, "status", swap.Status)
+			err = verify(swap, isSwapin)
+			if err != nil {
+				logWorkerError("orphan", "repair orphaned swap failed", err, "txid", swap.TxID, "pairID", swap.PairID, "bind", swap.Bind, "isSwapin", isSwapin)
+				continue
+			}
+			repairedCount++
+		}
+		logWorker("orphan", "repair orphaned swaps finished", "isSwapin", isSwapin, "found", len(swaps), "repaired", repairedCount)
+		repaired += repairedCount
+	}
+	return repaired
+}
@@ -0,0 +1,179 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// memSwapResultStore in memory swap result store with the same semantics as mongodb
+type memSwapResultStore struct {
+	results map[string]*mongodb.MgoSwapResult
+}
+
+func useMemSwapResultStore(t *testing.T) *memSwapResultStore {
+	store := &memSwapResultStore{results: make(map[string]*mongodb.MgoSwapResult)}
+	oldStore := swapResults
+	swapResults = store
+	t.Cleanup(func() { swapResults = oldStore })
+	return store
+}
+
+func (s *memSwapResultStore) AddSwapResult(isSwapin bool, result *mongodb.MgoSwapResult) error {
+	key := mongodb.GetSwapKey(result.TxID, result.PairID, result.Bind)
+	if _, exist := s.results[key]; exist {
+		return mongodb.ErrItemIsDup
+	}
+	copied := *result
+	s.results[key] = &copied
+	return nil
+}
+
+func (s *memSwapResultStore) FindSwapResult(isSwapin bool, txid, pairID, bind string) (*mongodb.MgoSwapResult, error) {
+	res, exist := s.results[mongodb.GetSwapKey(txid, pairID, bind)]
+	if !exist {
+		return nil, mongodb.ErrItemNotFound
+	}
+	copied := *res
+	return &copied, nil
+}
+
+func (s *memSwapResultStore) UpdateSwapResultVerifiedInfo(isSwapin bool, result *mongodb.MgoSwapResult) error {
+	res, exist := s.results[mongodb.GetSwapKey(result.TxID, result.PairID, result.Bind)]
+	if !exist || res.SwapTx != "" {
+		return nil
+	}
+	res.TxHeight = result.TxHeight
+	res.TxBlockHash = result.TxBlockHash
+	res.Value = result.Value
+	res.Status = result.Status
+	return nil
+}
+
+func TestAddInitialSwapResultIdempotent(t *testing.T) {
+	store := useMemSwapResultStore(t)
+	swapInfo := &tokens.TxSwapInfo{PairID: "pair", Hash: "txid", Bind: "bind", Height: 100, BlockHash: "0x01", Value: big.NewInt(1000)}
+	key := mongodb.GetSwapKey("txid", "pair", "bind")
+
+	if err := addInitialSwapResult(swapInfo, mongodb.TxWithBigValue, true, ""); err != nil {
+		t.Fatal(err)
+	}
+	// reverified after reorg, existing unprocessed result is synced
+	swapInfo.Height, swapInfo.BlockHash = 101, "0x02"
+	if err := addInitialSwapResult(swapInfo, mongodb.MatchTxEmpty, true, ""); err != nil {
+		t.Fatalf("adding existing result should be idempotent, have %v", err)
+	}
+	if res := store.results[key]; res.Status != mongodb.MatchTxEmpty || res.TxHeight != 101 || res.TxBlockHash != "0x02" {
+		t.Errorf("unprocessed result should be synced, have status %v height %v block %v", res.Status, res.TxHeight, res.TxBlockHash)
+	}
+
+	// processed result is kept
+	store.results[key].SwapTx = "swaptx"
+	store.results[key].Status = mongodb.MatchTxNotStable
+	swapInfo.Height = 102
+	if err := addInitialSwapResult(swapInfo, mongodb.MatchTxEmpty, true, ""); err != nil {
+		t.Fatalf("adding processed result should be idempotent, have %v", err)
+	}
+	if res := store.results[key]; res.Status != mongodb.MatchTxNotStable || res.TxHeight != 101 {
+		t.Errorf("processed result should not be changed, have status %v height %v", res.Status, res.TxHeight)
+	}
+}
+
+func TestRepairOrphanedSwaps(t *testing.T) {
+	orphans := map[bool][]*mongodb.MgoSwap{
+		true:  {{TxID: "in1", PairID: "pair", Bind: "bind"}, {TxID: "in2", PairID: "pair", Bind: "bind"}},
+		false: {{TxID: "out1", PairID: "pair", Bind: "bind"}},
+	}
+	findOrphanedSwaps := func(isSwapin bool) ([]*mongodb.MgoSwap, error) {
+		if !isSwapin {
+			return nil, errors.New("db error")
+		}
+		return orphans[isSwapin], nil
+	}
+	var verified []string
+	verify := func(swap *mongodb.MgoSwap, isSwapin bool) error {
+		verified = append(verified, swap.TxID)
+		if swap.TxID == "in1" {
+			return errors.New("verify failed")
+		}
+		return nil
+	}
+	if repaired := repairOrphanedSwapsWith(findOrphanedSwaps, verify); repaired != 1 {
+		t.Errorf("want 1 repaired swap, have %v", repaired)
+	}
+	if len(verified) != 2 {
+		t.Errorf("repair should continue after failure, verified %v", verified)
+	}
+}
+
+func TestRepairSkipSwapInVerify(t *testing.T) {
+	swap := &mongodb.MgoSwap{TxID: "txid", PairID: "pair", Bind: "bind"}
+
+	// verify job is verifying the swap
+	unlock, ok := tryLockSwapVerify(swap, true)
+	if !ok {
+		t.Fatal("lock swap verify failed")
+	}
+	// only the locked swapin is orphaned, swapout verify needs a bridge
+	findOrphanedSwaps := func(isSwapin bool) ([]*mongodb.MgoSwap, error) {
+		if !isSwapin {
+			return nil, nil
+		}
+		return []*mongodb.MgoSwap{swap}, nil
+	}
+	if repaired := repairOrphanedSwapsWith(findOrphanedSwaps, processSwapVerify); repaired != 0 {
+		t.Errorf("swap being verified should be skipped, have %v repaired", repaired)
+	}
+	if err := processSwapVerify(swap, true); !errors.Is(err, errSwapIsVerifying) {
+		t.Errorf("want %v, have %v", errSwapIsVerifying, err)
+	}
+
+	// same swap of other direction is not locked
+	if unlockOut, ok := tryLockSwapVerify(swap, false); !ok {
+		t.Errorf("swapout should not be locked by swapin")
+	} else {
+		unlockOut()
+	}
+
+	unlock()
+	if unlock, ok = tryLockSwapVerify(swap, true); !ok {
+		t.Fatal("swap should be unlocked")
+	}
+	unlock()
+}
+
+func TestTryLockSwapVerifyConcurrent(t *testing.T) {
+	swap := &mongodb.MgoSwap{TxID: "txid", PairID: "pair", Bind: "bind"}
+	const concurrency = 10
+	var (
+		wg     sync.WaitGroup
+		tried  sync.WaitGroup
+		lock   sync.Mutex
+		locked int
+	)
+	tried.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, ok := tryLockSwapVerify(swap, true)
+			tried.Done()
+			if !ok {
+				return
+			}
+			lock.Lock()
+			locked++
+			lock.Unlock()
+			tried.Wait() // hold the lock until all have tried
+			unlock()
+		}()
+	}
+	wg.Wait()
+	if locked != 1 {
+		t.Errorf("only one verification should hold the lock, have %v", locked)
+	}
+}
@@ -0,0 +1,205 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+)
+
+var (
+	p2shExpireInterval = 6 * time.Hour
+
+	errNotBtcBridge = errors.New("bridge is not btc")
+)
+
+// p2shExpireStore p2sh address storage used by p2sh expire job
+type p2shExpireStore interface {
+	DeactivateStaleP2shAddresses(before int64) (int64, error)
+	FindInactiveP2shAddresses(offset, limit int) ([]*mongodb.MgoP2shAddress, error)
+	FindP2shAddress(bindAddress string) (*mongodb.MgoP2shAddress, error)
+	ReactivateP2shAddress(bindAddress string) error
+	RecordP2shFailedDeposits(bindAddress string, utxos []string) error
+}
+
+// p2shExpireChain chain queries used by p2sh expire job
+type p2shExpireChain interface {
+	FindUtxos(addr string) ([]*electrs.ElectUtxo, error)
+	BackfillP2shSwapins(p2shAddress, bindAddress string) (int, error)
+}
+
+type mgoP2shExpireStore struct{}
+
+func (mgoP2shExpireStore) DeactivateStaleP2shAddresses(before int64) (int64, error) {
+	return mongodb.DeactivateStaleP2shAddresses(before)
+}
+
+func (mgoP2shExpireStore) FindInactiveP2shAddresses(offset, limit int) ([]*mongodb.MgoP2shAddress, error) {
+	return mongodb.FindInactiveP2shAddresses(offset, limit)
+}
+
+func (mgoP2shExpireStore) FindP2shAddress(bindAddress string) (*mongodb.MgoP2shAddress, error) {
+	return mongodb.FindP2shAddress(bindAddress)
+}
+
+func (mgoP2shExpireStore) ReactivateP2shAddress(bindAddress string) error {
+	return mongodb.ReactivateP2shAddress(bindAddress)
+}
+
+func (mgoP2shExpireStore) RecordP2shFailedDeposits(bindAddress string, utxos []string) error {
+	return mongodb.RecordP2shFailedDeposits(bindAddress, utxos)
+}
+
+// P2shAddressStats p2sh address counts
+type P2shAddressStats struct {
+	Active   int64 `json:"active"`
+	Inactive int64 `json:"inactive"`
+}
+
+// StartP2shExpireJob deactivate p2sh addresses never received deposit,
+// and sweep inactive ones to reactivate if deposit is observed later.
+func StartP2shExpireJob() {
+	if btc.BridgeInstance == nil || params.GetServerConfig().P2shInactiveAge <= 0 {
+		return
+	}
+
+	mongodb.MgoWaitGroup.Add(1)
+	go loopDoP2shExpireJob()
+}
+
+func loopDoP2shExpireJob() {
+	defer mongodb.MgoWaitGroup.Done()
+	store := mgoP2shExpireStore{}
+	for loop := 1; ; loop++ {
+		if utils.IsCleanuping() {
+			return
+		}
+		logWorker("p2shexpire", "start p2sh expire job", "loop", loop)
+		deactivateStaleP2shAddresses(store)
+		sweepInactiveP2shAddresses(store, btc.BridgeInstance)
+		logWorker("p2shexpire", "finish p2sh expire job", "loop", loop)
+		time.Sleep(p2shExpireInterval)
+	}
+}
+
+func deactivateStaleP2shAddresses(store p2shExpireStore) {
+	before := now() - params.GetServerConfig().P2shInactiveAge
+	count, err := store.DeactivateStaleP2shAddresses(before)
+	if err != nil {
+		logWorkerError("p2shexpire", "deactivate stale p2sh addresses failed", err)
+		return
+	}
+	logWorker("p2shexpire", "deactivate stale p2sh addresses", "count", count)
+}
+
+// sweepInactiveP2shAddresses reactivate inactive p2sh addresses which received deposits,
+// stop at database error and retry in the next pass.
+func sweepInactiveP2shAddresses(store p2shExpireStore, chain p2shExpireChain) {
+	offset := 0
+	for {
+		if utils.IsCleanuping() {
+			return
+		}
+		p2shAddrs, err := store.FindInactiveP2shAddresses(offset, utxoPageLimit)
+		if err != nil {
+			logWorkerError("p2shexpire", "FindInactiveP2shAddresses failed", err, "offset", offset, "limit", utxoPageLimit)
+			return
+		}
+		reactivated := 0
+		for _, p2shAddr := range p2shAddrs {
+			utxos, errf := chain.FindUtxos(p2shAddr.P2shAddress)
+			if errf != nil || len(utxos) == 0 {
+				continue
+			}
+			deposits := getUtxoKeys(utxos)
+			if !hasNewDeposit(deposits, p2shAddr.FailedDeposits) {
+				continue
+			}
+			logWorker("p2shexpire", "observe deposit to inactive p2sh address", "bind", p2shAddr.Key, "p2shAddress", p2shAddr.P2shAddress)
+			ok, backfilled, _ := reactivateP2shAddress(store, chain, p2shAddr.Key)
+			if !ok {
+				continue
+			}
+			reactivated++
+			if backfilled == 0 {
+				// otherwise the address is reactivated by the same utxos after every deactivation
+				logWorker("p2shexpire", "deposits to p2sh address registered no swapin", "bind", p2shAddr.Key, "deposits", deposits)
+				if errr := store.RecordP2shFailedDeposits(p2shAddr.Key, deposits); errr != nil {
+					logWorkerError("p2shexpire", "record p2sh failed deposits failed", errr, "bind", p2shAddr.Key)
+				}
+			}
+		}
+		if len(p2shAddrs) < utxoPageLimit {
+			break
+		}
+		// reactivated items are removed from the inactive set
+		offset += utxoPageLimit - reactivated
+	}
+}
+
+func getUtxoKeys(utxos []*electrs.ElectUtxo) []string {
+	keys := make([]string, 0, len(utxos))
+	for _, utxo := range utxos {
+		if utxo.Txid != nil && utxo.Vout != nil {
+			keys = append(keys, fmt.Sprintf("%v:%v", *utxo.Txid, *utxo.Vout))
+		}
+	}
+	return keys
+}
+
+// hasNewDeposit is any deposit not in the failed deposits of the last reactivation
+func hasNewDeposit(deposits, failedDeposits []string) bool {
+	failed := make(map[string]bool, len(failedDeposits))
+	for _, key := range failedDeposits {
+		failed[key] = true
+	}
+	for _, key := range deposits {
+		if !failed[key] {
+			return true
+		}
+	}
+	return len(deposits) == 0 // utxos without id are regarded as new
+}
+
+// ReactivateP2shAddress reactivate p2sh address and backfill its recent swapins
+func ReactivateP2shAddress(bindAddress string) (backfilled int, err error) {
+	if btc.BridgeInstance == nil {
+		return 0, errNotBtcBridge
+	}
+	_, backfilled, err = reactivateP2shAddress(mgoP2shExpireStore{}, btc.BridgeInstance, bindAddress)
+	return backfilled, err
+}
+
+// reactivateP2shAddress returns whether the address is reactivated in store,
+// even if backfilling its swapins failed afterwards.
+func reactivateP2shAddress(store p2shExpireStore, chain p2shExpireChain, bindAddress string) (reactivated bool, backfilled int, err error) {
+	p2shAddr, err := store.FindP2shAddress(bindAddress)
+	if err != nil {
+		return false, 0, err
+	}
+	err = store.ReactivateP2shAddress(bindAddress)
+	if err != nil {
+		return false, 0, err
+	}
+	backfilled, err = chain.BackfillP2shSwapins(p2shAddr.P2shAddress, bindAddress)
+	if err != nil {
+		logWorkerError("p2shexpire", "backfill p2sh swapins failed", err, "bind", bindAddress)
+		return true, 0, err
+	}
+	logWorker("p2shexpire", "reactivate p2sh address", "bind", bindAddress, "backfilled", backfilled)
+	return true, backfilled, nil
+}
+
+// GetP2shAddressStats get active and inactive p2sh address counts
+func GetP2shAddressStats() (*P2shAddressStats, error) {
+	active, inactive, err := mongodb.CountP2shAddresses()
+	if err != nil {
+		return nil, err
+	}
+	return &P2shAddressStats{Active: active, Inactive: inactive}, nil
+}
@@ -0,0 +1,236 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+)
+
+// memP2shStore in memory p2sh address store with the same semantics as mongodb
+type memP2shStore struct {
+	addrs   map[string]*mongodb.MgoP2shAddress
+	findErr error
+	finds   int
+}
+
+func newMemP2shStore() *memP2shStore {
+	return &memP2shStore{addrs: make(map[string]*mongodb.MgoP2shAddress)}
+}
+
+func (s *memP2shStore) add(bind string, timestamp int64, inactive bool) {
+	s.addrs[bind] = &mongodb.MgoP2shAddress{Key: bind, P2shAddress: "p2sh-" + bind, Timestamp: timestamp, Inactive: inactive}
+}
+
+func (s *memP2shStore) DeactivateStaleP2shAddresses(before int64) (count int64, err error) {
+	for _, addr := range s.addrs {
+		activeTime := addr.ActiveTime
+		if activeTime == 0 {
+			activeTime = addr.Timestamp
+		}
+		if !addr.Inactive && addr.LastDeposit == 0 && activeTime < before {
+			addr.Inactive = true
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *memP2shStore) FindInactiveP2shAddresses(offset, limit int) ([]*mongodb.MgoP2shAddress, error) {
+	s.finds++
+	if s.findErr != nil {
+		return nil, s.findErr
+	}
+	var inactive []*mongodb.MgoP2shAddress
+	for _, addr := range s.addrs {
+		if addr.Inactive {
+			inactive = append(inactive, addr)
+		}
+	}
+	sort.Slice(inactive, func(i, j int) bool { return inactive[i].Timestamp < inactive[j].Timestamp })
+	if offset >= len(inactive) {
+		return nil, nil
+	}
+	inactive = inactive[offset:]
+	if len(inactive) > limit {
+		inactive = inactive[:limit]
+	}
+	return inactive, nil
+}
+
+func (s *memP2shStore) FindP2shAddress(bindAddress string) (*mongodb.MgoP2shAddress, error) {
+	addr, exist := s.addrs[bindAddress]
+	if !exist {
+		return nil, mongodb.ErrItemNotFound
+	}
+	return addr, nil
+}
+
+func (s *memP2shStore) ReactivateP2shAddress(bindAddress string) error {
+	addr, exist := s.addrs[bindAddress]
+	if !exist {
+		return mongodb.ErrItemNotFound
+	}
+	addr.Inactive = false
+	addr.ActiveTime = now()
+	return nil
+}
+
+func (s *memP2shStore) RecordP2shFailedDeposits(bindAddress string, utxos []string) error {
+	addr, exist := s.addrs[bindAddress]
+	if !exist {
+		return mongodb.ErrItemNotFound
+	}
+	addr.FailedDeposits = utxos
+	return nil
+}
+
+type depositChain struct {
+	deposits    map[string]bool // p2sh address -> has utxo
+	newDeposits map[string]bool // p2sh address -> has another utxo
+	backfillErr error
+	backfilled  []string
+}
+
+func (c *depositChain) FindUtxos(addr string) ([]*electrs.ElectUtxo, error) {
+	var utxos []*electrs.ElectUtxo
+	newUtxo := func(vout uint32) *electrs.ElectUtxo {
+		txid := "tx-" + addr
+		return &electrs.ElectUtxo{Txid: &txid, Vout: &vout}
+	}
+	if c.deposits[addr] {
+		utxos = append(utxos, newUtxo(0))
+	}
+	if c.newDeposits[addr] {
+		utxos = append(utxos, newUtxo(1))
+	}
+	return utxos, nil
+}
+
+func (c *depositChain) BackfillP2shSwapins(p2shAddress, bindAddress string) (int, error) {
+	if c.backfillErr != nil {
+		return 0, c.backfillErr
+	}
+	c.backfilled = append(c.backfilled, bindAddress)
+	return 1, nil
+}
+
+func TestDeactivateStaleP2shAddresses(t *testing.T) {
+	const inactiveAge = 1000
+	params.SetConfig(&params.BridgeConfig{Server: &params.ServerConfig{P2shInactiveAge: inactiveAge}})
+	store := newMemP2shStore()
+	store.add("stale", now()-2*inactiveAge, false)
+	store.add("recent", now(), false)
+	store.add("deposited", now()-2*inactiveAge, false)
+	store.addrs["deposited"].LastDeposit = now()
+	store.add("reactivated", now()-2*inactiveAge, false)
+	store.addrs["reactivated"].ActiveTime = now()
+
+	deactivateStaleP2shAddresses(store)
+	for bind, addr := range store.addrs {
+		if want := bind == "stale"; addr.Inactive != want {
+			t.Errorf("%v: want inactive %v, have %v", bind, want, addr.Inactive)
+		}
+	}
+}
+
+func TestSweepInactiveP2shAddresses(t *testing.T) {
+	store := newMemP2shStore()
+	chain := &depositChain{deposits: make(map[string]bool)}
+	// more than one page, deposits observed on first and second pages
+	total := utxoPageLimit + utxoPageLimit/2
+	for i := 0; i < total; i++ {
+		bind := fmt.Sprintf("bind%03d", i)
+		store.add(bind, int64(i), true)
+		if i%10 == 0 {
+			chain.deposits["p2sh-"+bind] = true
+		}
+	}
+	sweepInactiveP2shAddresses(store, chain)
+	for bind, addr := range store.addrs {
+		deposited := chain.deposits[addr.P2shAddress]
+		if addr.Inactive == deposited {
+			t.Errorf("%v: deposited %v but inactive %v", bind, deposited, addr.Inactive)
+		}
+	}
+	if want := (total + 9) / 10; len(chain.backfilled) != want {
+		t.Errorf("want %v backfilled addresses, have %v", want, len(chain.backfilled))
+	}
+}
+
+func TestSweepInactiveP2shAddressesStopAtDBError(t *testing.T) {
+	store := newMemP2shStore()
+	store.findErr = errors.New("db error")
+	done := make(chan struct{})
+	go func() {
+		sweepInactiveP2shAddresses(store, &depositChain{})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sweep should stop at database error")
+	}
+	if store.finds != 1 {
+		t.Errorf("want 1 query, have %v", store.finds)
+	}
+}
+
+func TestReactivateP2shAddress(t *testing.T) {
+	store := newMemP2shStore()
+	store.add("bind", now(), true)
+	chain := &depositChain{}
+
+	if ok, backfilled, err := reactivateP2shAddress(store, chain, "bind"); !ok || backfilled != 1 || err != nil {
+		t.Errorf("reactivate failed, reactivated %v backfilled %v err %v", ok, backfilled, err)
+	}
+	if store.addrs["bind"].Inactive {
+		t.Errorf("address should be active")
+	}
+
+	if ok, _, err := reactivateP2shAddress(store, chain, "unknown"); ok || !errors.Is(err, mongodb.ErrItemNotFound) {
+		t.Errorf("reactivate unknown address should fail, reactivated %v err %v", ok, err)
+	}
+
+	// address is kept reactivated even if backfill failed, admin can reactivate again to backfill
+	store.addrs["bind"].Inactive = true
+	chain.backfillErr = errors.New("gateway error")
+	if ok, _, err := reactivateP2shAddress(store, chain, "bind"); !ok || err == nil {
+		t.Errorf("want reactivated with backfill error, have reactivated %v err %v", ok, err)
+	}
+	if store.addrs["bind"].Inactive {
+		t.Errorf("address should be active after backfill failure")
+	}
+}
+
+func TestSweepSkipsFailedDeposits(t *testing.T) {
+	store := newMemP2shStore()
+	store.add("bind", now(), true)
+	chain := &depositChain{deposits: map[string]bool{"p2sh-bind": true}, backfillErr: errors.New("not a valid swapin")}
+
+	sweepInactiveP2shAddresses(store, chain)
+	addr := store.addrs["bind"]
+	if addr.Inactive || len(addr.FailedDeposits) != 1 || addr.FailedDeposits[0] != "tx-p2sh-bind:0" {
+		t.Fatalf("want reactivated with failed deposits recorded, have %+v", addr)
+	}
+
+	// deactivated again, the same deposit does not reactivate it
+	addr.Inactive = true
+	chain.backfillErr = nil
+	sweepInactiveP2shAddresses(store, chain)
+	if !addr.Inactive || len(chain.backfilled) != 0 {
+		t.Errorf("failed deposit should not reactivate address, inactive %v backfilled %v", addr.Inactive, chain.backfilled)
+	}
+
+	// a new deposit does
+	chain.newDeposits = map[string]bool{"p2sh-bind": true}
+	sweepInactiveP2shAddresses(store, chain)
+	if addr.Inactive || len(chain.backfilled) != 1 {
+		t.Errorf("new deposit should reactivate address, inactive %v backfilled %v", addr.Inactive, chain.backfilled)
+	}
+}
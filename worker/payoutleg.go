@@ -0,0 +1,303 @@
+package worker
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// split payout: a swap paying over the per tx cap is paid by sequential legs.
+// the first leg is recorded as the swap tx of swap result, the others are
+// recorded in 'PayoutLegs'. the swap is stable only when all legs are stable,
+// failed legs are resent alone (a resent first leg is recorded in 'PayoutLegs').
+
+type payoutLegState int
+
+const (
+	payoutLegPending payoutLegState = iota // sent and not stable yet
+	payoutLegToSend                        // not sent, or failed and should be resent
+	payoutLegStable
+)
+
+// getFirstPayoutLeg get the first payout leg if swap value exceeds per tx cap,
+// only nonce supported (eth like) chains support split payout.
+func getFirstPayoutLeg(pairID string, value *big.Int, isSwapin bool, from, txto string) (*tokens.PayoutLeg, error) {
+	if tokens.GetNonceSetter(!isSwapin) == nil {
+		return nil, nil
+	}
+	swapValue := tokens.CalcSwappedValue(pairID, value, isSwapin, from, txto)
+	count, err := tokens.GetPayoutLegCount(pairID, !isSwapin, swapValue)
+	if err != nil {
+		return nil, err
+	}
+	if count < 2 {
+		return nil, nil
+	}
+	return &tokens.PayoutLeg{Index: 0, Count: count}, nil
+}
+
+func newPayoutLegArgs(args *tokens.BuildTxArgs, index int) *tokens.BuildTxArgs {
+	swapInfo := args.SwapInfo
+	swapInfo.PayoutLeg = &tokens.PayoutLeg{Index: index, Count: args.PayoutLeg.Count}
+	return &tokens.BuildTxArgs{
+		SwapInfo:    swapInfo,
+		From:        args.From,
+		OriginFrom:  args.OriginFrom,
+		OriginTxTo:  args.OriginTxTo,
+		OriginValue: args.OriginValue,
+	}
+}
+
+// sendPayoutLegs send the other legs after the first leg is sent,
+// unsent legs are retried by stable job.
+func sendPayoutLegs(args *tokens.BuildTxArgs) {
+	for i := 1; i < args.PayoutLeg.Count; i++ {
+		legArgs := newPayoutLegArgs(args, i)
+		if err := doSwapPayoutLeg(legArgs); err != nil {
+			logWorkerError("doSwap", "send payout leg failed", err, "pairID", args.PairID, "txid", args.SwapID, "bind", args.Bind, "isSwapin", args.IsSwapin(), "leg", i, "count", args.PayoutLeg.Count)
+			return
+		}
+	}
+}
+
+// isPayoutLegResend legs except the first one, and the first leg which
+// is already recorded as swap tx, are sent by doSwapPayoutLeg
+func isPayoutLegResend(args *tokens.BuildTxArgs) bool {
+	if args.PayoutLeg == nil {
+		return false
+	}
+	if args.PayoutLeg.Index > 0 {
+		return true
+	}
+	res, err := mongodb.FindSwapResult(args.IsSwapin(), args.SwapID, args.PairID, args.Bind)
+	return err == nil && res.SwapTx != ""
+}
+
+func doSwapPayoutLeg(args *tokens.BuildTxArgs) error {
+	pairID, txid, bind := args.PairID, args.SwapID, args.Bind
+	isSwapin := args.IsSwapin()
+	index := args.PayoutLeg.Index
+	resBridge := tokens.GetCrossChainBridge(!isSwapin)
+
+	if _, err := checkPayoutLegToSend(resBridge, args); err != nil {
+		return err
+	}
+
+	logWorker("doSwap", "start to process payout leg", "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "leg", index, "count", args.PayoutLeg.Count)
+
+	rawTx, err := resBridge.BuildRawTransaction(args)
+	if err != nil {
+		logWorkerError("doSwap", "build payout leg tx failed", err, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "leg", index)
+		return err
+	}
+	signedTx, signTxHash, err := signSwapTx(resBridge, rawTx, args)
+	if err != nil {
+		return err
+	}
+
+	// recheck before update db
+	res, err := checkPayoutLegToSend(resBridge, args)
+	if err != nil {
+		return err
+	}
+	leg := &mongodb.MgoPayoutLeg{
+		Index:     index,
+		SwapTx:    signTxHash,
+		SwapValue: args.SwapValue.String(),
+		SwapNonce: args.GetTxNonce(),
+		Timestamp: now(),
+	}
+	if index == 0 && res.GetPayoutLeg(0) == nil {
+		// resent first leg keeps the swap txs of swap result
+		leg.OldSwapTxs = append(append([]string{}, res.OldSwapTxs...), res.SwapTx)
+	}
+	err = mongodb.UpdateSwapResultPayoutLeg(isSwapin, txid, pairID, bind, leg)
+	if err != nil {
+		logWorkerError("doSwap", "update payout leg failed", err, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "leg", index)
+		return err
+	}
+
+	txHash, err := sendSignedTransaction(resBridge, signedTx, args)
+	if err == nil && txHash != signTxHash {
+		logWorkerError("doSwap", "send payout leg success but with different hash", errSendTxWithDiffHash, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "leg", index, "txHash", txHash, "signTxHash", signTxHash)
+		leg.SwapTx = txHash
+		_ = mongodb.UpdateSwapResultPayoutLeg(isSwapin, txid, pairID, bind, leg)
+	}
+	return err
+}
+
+// checkPayoutLegToSend a leg is sent only if it's not sent or should be resent
+func checkPayoutLegToSend(resBridge tokens.CrossChainBridge, args *tokens.BuildTxArgs) (*mongodb.MgoSwapResult, error) {
+	res, err := mongodb.FindSwapResult(args.IsSwapin(), args.SwapID, args.PairID, args.Bind)
+	if err != nil {
+		return nil, err
+	}
+	if res.Status != mongodb.MatchTxNotStable {
+		return nil, errAlreadySwapped
+	}
+	if res.PayoutLegCount != args.PayoutLeg.Count {
+		return nil, fmt.Errorf("%w, count %v mismatch with %v in db", tokens.ErrWrongPayoutLeg, args.PayoutLeg.Count, res.PayoutLegCount)
+	}
+	legs := getPayoutLegs(res)
+	index := args.PayoutLeg.Index
+	if index < 0 || index >= len(legs) {
+		return nil, tokens.ErrWrongPayoutLeg
+	}
+	if state, _ := getPayoutLegState(resBridge, res, legs[index]); state != payoutLegToSend {
+		return nil, errAlreadySwapped
+	}
+	return res, nil
+}
+
+// getPayoutLegs get all legs in order, nil item if the leg is not sent
+func getPayoutLegs(res *mongodb.MgoSwapResult) []*mongodb.MgoPayoutLeg {
+	legs := make([]*mongodb.MgoPayoutLeg, res.PayoutLegCount)
+	for i := range legs {
+		legs[i] = res.GetPayoutLeg(i)
+	}
+	if len(legs) > 0 && legs[0] == nil && res.SwapTx != "" {
+		legs[0] = &mongodb.MgoPayoutLeg{
+			Index:      0,
+			SwapTx:     res.SwapTx,
+			OldSwapTxs: res.OldSwapTxs,
+			SwapValue:  res.SwapValue,
+			SwapNonce:  res.SwapNonce,
+			Timestamp:  res.Timestamp,
+		}
+	}
+	return legs
+}
+
+// getPayoutLegState get state of leg, and the stable leg if it becomes stable
+func getPayoutLegState(resBridge tokens.CrossChainBridge, res *mongodb.MgoSwapResult, leg *mongodb.MgoPayoutLeg) (payoutLegState, *mongodb.MgoPayoutLeg) {
+	if leg == nil {
+		return payoutLegToSend, nil
+	}
+	if leg.Stable {
+		return payoutLegStable, nil
+	}
+	txStatus, swapTx := getPayoutLegTxStatus(resBridge, leg)
+	if txStatus == nil {
+		if isPayoutLegNoncePassed(resBridge, res, leg) {
+			return payoutLegToSend, nil
+		}
+		return payoutLegPending, nil
+	}
+	isSwapin := tokens.SwapType(res.SwapType) == tokens.SwapinType
+	if !tokens.IsTxStatusStable(txStatus, res.PairID, !isSwapin) {
+		return payoutLegPending, nil
+	}
+	if txStatus.IsSwapTxOnChainAndFailed(resBridge.GetTokenConfig(res.PairID)) {
+		return payoutLegToSend, nil
+	}
+	stableLeg := *leg
+	stableLeg.SwapTx = swapTx
+	stableLeg.SwapHeight = txStatus.BlockHeight
+	stableLeg.Stable = true
+	return payoutLegStable, &stableLeg
+}
+
+func getPayoutLegTxStatus(resBridge tokens.CrossChainBridge, leg *mongodb.MgoPayoutLeg) (*tokens.TxStatus, string) {
+	for _, swapTx := range append([]string{leg.SwapTx}, leg.OldSwapTxs...) {
+		if swapTx == "" {
+			continue
+		}
+		txStatus, err := resBridge.GetTransactionStatus(swapTx)
+		if err == nil && txStatus.BlockHeight > 0 {
+			return txStatus, swapTx
+		}
+	}
+	return nil, ""
+}
+
+// isPayoutLegNoncePassed none of the leg txs can be mined if its nonce is passed
+func isPayoutLegNoncePassed(resBridge tokens.CrossChainBridge, res *mongodb.MgoSwapResult, leg *mongodb.MgoPayoutLeg) bool {
+	nonceSetter, ok := resBridge.(tokens.NonceSetter)
+	if !ok || leg.SwapNonce == 0 || leg.Timestamp >= getSepTimeInFind(treatAsNoncePassedInterval) {
+		return false
+	}
+	tokenCfg := resBridge.GetTokenConfig(res.PairID)
+	if tokenCfg == nil {
+		return false
+	}
+	nonce, err := nonceSetter.GetPoolNonce(tokenCfg.DcrmAddress, "latest")
+	if err != nil || nonce <= leg.SwapNonce {
+		return false
+	}
+	// recheck
+	if isTransactionOnChain(nonceSetter, leg.SwapTx) {
+		return false
+	}
+	for _, swapTx := range leg.OldSwapTxs {
+		if isTransactionOnChain(nonceSetter, swapTx) {
+			return false
+		}
+	}
+	return true
+}
+
+// processPayoutLegsStable mark stable legs, resend unsent or failed legs,
+// and mark swap result stable when all legs are stable.
+func processPayoutLegsStable(swap *mongodb.MgoSwapResult, isSwapin bool) error {
+	resBridge := tokens.GetCrossChainBridge(!isSwapin)
+	allStable := true
+	for i, leg := range getPayoutLegs(swap) {
+		state, stableLeg := getPayoutLegState(resBridge, swap, leg)
+		switch state {
+		case payoutLegStable:
+			if stableLeg == nil {
+				continue
+			}
+			err := mongodb.UpdateSwapResultPayoutLeg(isSwapin, swap.TxID, swap.PairID, swap.Bind, stableLeg)
+			if err != nil {
+				return err
+			}
+		case payoutLegToSend:
+			allStable = false
+			logWorkerWarn("stable", "resend payout leg", "pairID", swap.PairID, "txid", swap.TxID, "bind", swap.Bind, "isSwapin", isSwapin, "leg", i, "count", swap.PayoutLegCount)
+			if err := dispatchPayoutLeg(swap, isSwapin, i); err != nil {
+				return err
+			}
+		default:
+			allStable = false
+		}
+	}
+	if !allStable {
+		return nil
+	}
+	return markSwapResultStable(swap.TxID, swap.PairID, swap.Bind, isSwapin)
+}
+
+func dispatchPayoutLeg(res *mongodb.MgoSwapResult, isSwapin bool, index int) error {
+	swap, err := mongodb.FindSwap(isSwapin, res.TxID, res.PairID, res.Bind)
+	if err != nil {
+		return err
+	}
+	value, ok := new(big.Int).SetString(res.Value, 10)
+	if !ok {
+		return fmt.Errorf("wrong swap value %v in db", res.Value)
+	}
+	tokenCfg := tokens.GetCrossChainBridge(!isSwapin).GetTokenConfig(res.PairID)
+	if tokenCfg == nil {
+		return tokens.ErrUnknownPairID
+	}
+	args := &tokens.BuildTxArgs{
+		SwapInfo: tokens.SwapInfo{
+			Identifier: params.GetIdentifier(),
+			PairID:     res.PairID,
+			SwapID:     res.TxID,
+			SwapType:   getSwapType(isSwapin),
+			TxType:     tokens.SwapTxType(swap.TxType),
+			Bind:       res.Bind,
+			PayoutLeg:  &tokens.PayoutLeg{Index: index, Count: res.PayoutLegCount},
+		},
+		From:        tokenCfg.DcrmAddress,
+		OriginFrom:  res.From,
+		OriginTxTo:  res.TxTo,
+		OriginValue: value,
+	}
+	return dispatchSwapTask(args)
+}
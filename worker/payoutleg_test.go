@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func TestGetPayoutLegs(t *testing.T) {
+	res := &mongodb.MgoSwapResult{
+		SwapTx:         "0x0",
+		SwapNonce:      5,
+		PayoutLegCount: 3,
+		PayoutLegs:     []*mongodb.MgoPayoutLeg{{Index: 2, SwapTx: "0x2"}},
+	}
+	legs := getPayoutLegs(res)
+	if len(legs) != 3 || legs[0].SwapTx != "0x0" || legs[0].SwapNonce != 5 || legs[1] != nil || legs[2].SwapTx != "0x2" {
+		t.Fatalf("wrong legs %v", legs)
+	}
+
+	// resent first leg overrides swap tx of swap result
+	res.PayoutLegs = append(res.PayoutLegs, &mongodb.MgoPayoutLeg{Index: 0, SwapTx: "0x0b"})
+	if legs = getPayoutLegs(res); legs[0].SwapTx != "0x0b" {
+		t.Errorf("want resent first leg, have %v", legs[0].SwapTx)
+	}
+}
+
+func TestAcceptRecordPayoutLeg(t *testing.T) {
+	leg := &tokens.PayoutLeg{Index: 1, Count: 3}
+	recordLeg, swapTx := parseAcceptRecordSuffix(getPayoutLegTag(leg) + "0xabc")
+	if !isSamePayoutLeg(recordLeg, leg) || swapTx != "0xabc" {
+		t.Fatalf("wrong parsed record, leg %v swaptx %v", recordLeg, swapTx)
+	}
+	if recordLeg, swapTx = parseAcceptRecordSuffix("0xabc"); recordLeg != nil || swapTx != "0xabc" {
+		t.Fatalf("wrong parsed record without leg, leg %v swaptx %v", recordLeg, swapTx)
+	}
+
+	other := &tokens.PayoutLeg{Index: 2, Count: 3}
+	if !isSamePayoutSplit(leg, other) || isSamePayoutLeg(leg, other) {
+		t.Errorf("legs of same split should be distinguished")
+	}
+	if isSamePayoutSplit(leg, &tokens.PayoutLeg{Index: 1, Count: 2}) || isSamePayoutSplit(leg, nil) {
+		t.Errorf("different split should not be same split")
+	}
+	if !isSamePayoutLeg(nil, nil) {
+		t.Errorf("swaps without leg should be same")
+	}
+}
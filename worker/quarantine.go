@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"errors"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const defaultMaxSwapFailures = 20
+
+func getMaxSwapFailures() int {
+	maxFailures := params.GetServerConfig().MaxSwapFailures
+	if maxFailures == 0 {
+		maxFailures = defaultMaxSwapFailures
+	}
+	return maxFailures
+}
+
+// failures of rpc or database are not the swap's fault
+func isTransientSwapError(err error) bool {
+	return tokens.IsRPCQueryOrNotFoundError(err) ||
+		errors.Is(err, errDBError) ||
+		mongodb.IsMgoError(err)
+}
+
+func recordSwapFailure(job string, isSwapin, isResult bool, txid, pairID, bind, stage string, err error) {
+	if err == nil || isTransientSwapError(err) {
+		return
+	}
+	maxFailures := getMaxSwapFailures()
+	if maxFailures < 0 {
+		return
+	}
+	quarantined, errf := mongodb.RecordSwapFailure(isSwapin, isResult, txid, pairID, bind, stage, err, maxFailures)
+	if errf != nil {
+		logWorkerWarn(job, "record swap failure failed", "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin, "err", errf)
+		return
+	}
+	if quarantined {
+		logWorkerError(job, "swap is quarantined", err, "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin, "stage", stage)
+	}
+}
+
+func resetSwapFailure(isSwapin, isResult bool, txid, pairID, bind string, failCount int) {
+	if failCount > 0 {
+		_ = mongodb.ResetSwapFailure(isSwapin, isResult, txid, pairID, bind)
+	}
+}
@@ -0,0 +1,32 @@
+package worker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func TestIsTransientSwapError(t *testing.T) {
+	// a swap document with unparseable value fails on every iteration
+	_, parseErr := common.GetBigIntFromStr("0xzz")
+	if parseErr == nil {
+		t.Fatal("parse wrong value should fail")
+	}
+	if isTransientSwapError(fmt.Errorf("wrong swap value: %w", parseErr)) {
+		t.Errorf("unparseable value error should be counted as swap failure")
+	}
+	transientErrs := []error{
+		tokens.ErrRPCQueryError,
+		tokens.ErrNotFound,
+		errDBError,
+		mongodb.ErrItemNotFound,
+	}
+	for _, err := range transientErrs {
+		if !isTransientSwapError(err) {
+			t.Errorf("error '%v' should be transient", err)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package worker
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const (
+	defaultMaxSwapLifetime = int64(7 * 24 * 3600)
+
+	reconcileMemo = "expired by startup reconcile"
+)
+
+// reconcile actions
+const (
+	ReconcileActionCompleted = "completed"
+	ReconcileActionFailed    = "failed"
+	ReconcileActionExpired   = "expired"
+	ReconcileActionKeep      = "keep"
+	ReconcileActionError     = "error"
+)
+
+var (
+	errNoReconcileReport     = errors.New("no startup reconcile report")
+	errReconcileNotFinished  = errors.New("startup reconcile is not finished")
+	errReconcileConfirmed    = errors.New("startup reconcile is already confirmed")
+	reconcileReport          *ReconcileReport
+	reconcileLock            sync.RWMutex
+	reconcileConfirmedSignal = make(chan struct{})
+)
+
+// ReconcileItem reconcile result of a swap result
+type ReconcileItem struct {
+	IsSwapin bool   `json:"isswapin"`
+	TxID     string `json:"txid"`
+	PairID   string `json:"pairid"`
+	Bind     string `json:"bind"`
+	SwapTx   string `json:"swaptx"`
+	Status   string `json:"status"`
+	Action   string `json:"action"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// ReconcileReport startup reconcile report
+type ReconcileReport struct {
+	StartTime   int64            `json:"starttime"`
+	FinishTime  int64            `json:"finishtime"`
+	Finished    bool             `json:"finished"`
+	Checked     int              `json:"checked"`
+	Completed   int              `json:"completed"`
+	Failed      int              `json:"failed"`
+	Expired     int              `json:"expired"`
+	Kept        int              `json:"kept"`
+	Errors      int              `json:"errors"`
+	Items       []*ReconcileItem `json:"items"`
+	Confirmed   bool             `json:"confirmed"`
+	ConfirmedBy string           `json:"confirmedby,omitempty"`
+	ConfirmTime int64            `json:"confirmtime,omitempty"`
+}
+
+func getMaxSwapLifetime() int64 {
+	lifetime := params.GetServerConfig().MaxSwapLifetime
+	if lifetime <= 0 {
+		lifetime = defaultMaxSwapLifetime
+	}
+	return lifetime
+}
+
+// GetReconcileReport get startup reconcile report
+func GetReconcileReport() (*ReconcileReport, error) {
+	reconcileLock.RLock()
+	defer reconcileLock.RUnlock()
+	if reconcileReport == nil {
+		return nil, errNoReconcileReport
+	}
+	report := *reconcileReport
+	report.Items = make([]*ReconcileItem, len(reconcileReport.Items))
+	copy(report.Items, reconcileReport.Items)
+	return &report, nil
+}
+
+// ConfirmReconcile confirm startup reconcile report and resume processing
+func ConfirmReconcile(operator string) error {
+	reconcileLock.Lock()
+	defer reconcileLock.Unlock()
+	switch {
+	case reconcileReport == nil:
+		return errNoReconcileReport
+	case !reconcileReport.Finished:
+		return errReconcileNotFinished
+	case reconcileReport.Confirmed:
+		return errReconcileConfirmed
+	}
+	reconcileReport.Confirmed = true
+	reconcileReport.ConfirmedBy = operator
+	reconcileReport.ConfirmTime = now()
+	close(reconcileConfirmedSignal)
+	logWorker("reconcile", "startup reconcile is confirmed", "operator", operator)
+	return nil
+}
+
+// needStartupReconcile fresh database needs no reconcile
+func needStartupReconcile() bool {
+	if !params.GetServerConfig().StartupReconcile {
+		return false
+	}
+	isEmpty, err := mongodb.IsSwapDatabaseEmpty()
+	if err != nil {
+		logWorkerError("reconcile", "check database is empty failed", err)
+		return true
+	}
+	if isEmpty {
+		logWorker("reconcile", "skip startup reconcile of empty database")
+		return false
+	}
+	return true
+}
+
+// runStartupReconcile walk through non-terminal swap results and cross check them with chain state
+func runStartupReconcile() {
+	report := &ReconcileReport{StartTime: now()}
+	reconcileLock.Lock()
+	reconcileReport = report
+	reconcileLock.Unlock()
+
+	logWorker("reconcile", "start startup reconcile")
+	lifetime := getMaxSwapLifetime()
+	var items []*ReconcileItem
+	for _, isSwapin := range []bool{true, false} {
+		for _, statusInfo := range mongodb.GetStatusCatalog() {
+			if statusInfo.IsTerminal || statusInfo.Category != mongodb.StatusCategoryPending {
+				continue
+			}
+			results, err := mongodb.FindAllSwapResultsWithStatus(isSwapin, statusInfo.Code)
+			if err != nil {
+				logWorkerError("reconcile", "find swap results failed", err, "isSwapin", isSwapin, "status", statusInfo.Name)
+				continue
+			}
+			for _, res := range results {
+				items = append(items, reconcileSwapResult(res, isSwapin, lifetime))
+			}
+		}
+	}
+
+	reconcileLock.Lock()
+	defer reconcileLock.Unlock()
+	for _, item := range items {
+		switch item.Action {
+		case ReconcileActionCompleted:
+			report.Completed++
+		case ReconcileActionFailed:
+			report.Failed++
+		case ReconcileActionExpired:
+			report.Expired++
+		case ReconcileActionKeep:
+			report.Kept++
+		default:
+			report.Errors++
+		}
+	}
+	report.Items = items
+	report.Checked = len(items)
+	report.Finished = true
+	report.FinishTime = now()
+	logWorker("reconcile", "startup reconcile finished, waiting for admin confirmation", "checked", report.Checked, "completed", report.Completed, "failed", report.Failed, "expired", report.Expired, "kept", report.Kept, "errors", report.Errors)
+}
+
+func waitReconcileConfirmed() {
+	<-reconcileConfirmedSignal
+}
+
+func reconcileSwapResult(res *mongodb.MgoSwapResult, isSwapin bool, lifetime int64) *ReconcileItem {
+	item := &ReconcileItem{
+		IsSwapin: isSwapin,
+		TxID:     res.TxID,
+		PairID:   res.PairID,
+		Bind:     res.Bind,
+		SwapTx:   res.SwapTx,
+		Status:   res.Status.String(),
+		Action:   ReconcileActionKeep,
+	}
+
+	var txStatus *tokens.TxStatus
+	resBridge := tokens.GetCrossChainBridge(!isSwapin)
+	if res.SwapTx != "" {
+		oldSwapTx := res.SwapTx
+		txStatus = getSwapTxStatus(resBridge, res)
+		if txStatus != nil && res.SwapTx != oldSwapTx {
+			item.SwapTx = res.SwapTx
+			_ = updateSwapResultTx(res.TxID, res.PairID, res.Bind, res.SwapTx, res.SwapValue, isSwapin, mongodb.KeepStatus)
+		}
+	}
+
+	var err error
+	switch {
+	case txStatus != nil:
+		if !tokens.IsTxStatusStable(txStatus, res.PairID, !isSwapin) {
+			item.Detail = "swap tx is on chain but not stable"
+			break
+		}
+		if txStatus.IsSwapTxOnChainAndFailed(resBridge.GetTokenConfig(res.PairID)) {
+			item.Action = ReconcileActionFailed
+			err = markSwapResultFailed(res.TxID, res.PairID, res.Bind, isSwapin)
+		} else {
+			item.Action = ReconcileActionCompleted
+			err = markSwapResultStable(res.TxID, res.PairID, res.Bind, isSwapin)
+		}
+	case isSwapExpired(res, lifetime) && isSwapTxsNotFound(resBridge, res):
+		item.Action = ReconcileActionExpired
+		err = mongodb.UpdateSwapResultStatus(isSwapin, res.TxID, res.PairID, res.Bind, mongodb.SwapExpired, now(), reconcileMemo)
+	case isSwapExpired(res, lifetime):
+		// swap tx may be pending or its status is unknown (eg. rpc error)
+		item.Detail = "swap is expired but its swap tx is not confirmed to be not found"
+		logWorkerWarn("reconcile", "keep expired swap result with swap tx", "txid", res.TxID, "pairID", res.PairID, "bind", res.Bind, "isSwapin", isSwapin, "swaptx", res.SwapTx)
+	}
+	if err != nil {
+		item.Action = ReconcileActionError
+		item.Detail = err.Error()
+		logWorkerError("reconcile", "reconcile swap result failed", err, "txid", res.TxID, "pairID", res.PairID, "bind", res.Bind, "isSwapin", isSwapin)
+	} else if item.Action != ReconcileActionKeep {
+		logWorker("reconcile", "reconcile swap result", "txid", res.TxID, "pairID", res.PairID, "bind", res.Bind, "isSwapin", isSwapin, "swaptx", item.SwapTx, "action", item.Action)
+	}
+	return item
+}
+
+// isSwapTxsNotFound return true if swap result has no swap tx,
+// or all of its swap txs are definitely not found on chain
+func isSwapTxsNotFound(resBridge tokens.CrossChainBridge, res *mongodb.MgoSwapResult) bool {
+	if res.SwapTx == "" && len(res.OldSwapTxs) == 0 {
+		return true
+	}
+	swapTxs := append([]string{res.SwapTx}, res.OldSwapTxs...)
+	for _, swapTx := range swapTxs {
+		if swapTx == "" {
+			continue
+		}
+		if _, err := resBridge.GetTransactionStatus(swapTx); !errors.Is(err, tokens.ErrTxNotFound) {
+			return false
+		}
+	}
+	return true
+}
+
+// deposit time is preferred, init time is milli seconds
+func isSwapExpired(res *mongodb.MgoSwapResult, lifetime int64) bool {
+	depositTime := int64(res.TxTime)
+	if depositTime == 0 {
+		depositTime = res.InitTime / 1000
+	}
+	return depositTime > 0 && depositTime < getSepTimeInFind(lifetime)
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+type txStatusBridge struct {
+	tokens.CrossChainBridge
+	statuses map[string]error // tx hash -> error of GetTransactionStatus
+}
+
+func (b *txStatusBridge) GetTransactionStatus(txHash string) (*tokens.TxStatus, error) {
+	if err := b.statuses[txHash]; err != nil {
+		return nil, err
+	}
+	return &tokens.TxStatus{}, nil // pending
+}
+
+func TestIsSwapTxsNotFound(t *testing.T) {
+	bridge := &txStatusBridge{statuses: map[string]error{
+		"notfound1": fmt.Errorf("%w: notfound1", tokens.ErrTxNotFound),
+		"notfound2": tokens.ErrTxNotFound,
+		"rpcerror":  tokens.ErrRPCQueryError,
+	}}
+	cases := []struct {
+		res  *mongodb.MgoSwapResult
+		want bool
+	}{
+		{&mongodb.MgoSwapResult{}, true},
+		{&mongodb.MgoSwapResult{SwapTx: "notfound1", OldSwapTxs: []string{"notfound1", "notfound2"}}, true},
+		{&mongodb.MgoSwapResult{SwapTx: "pending"}, false},
+		{&mongodb.MgoSwapResult{SwapTx: "rpcerror"}, false},
+		{&mongodb.MgoSwapResult{SwapTx: "notfound1", OldSwapTxs: []string{"notfound1", "pending"}}, false},
+	}
+	for i, c := range cases {
+		if have := isSwapTxsNotFound(bridge, c.res); have != c.want {
+			t.Errorf("case %v: want %v, have %v", i, c.want, have)
+		}
+	}
+}
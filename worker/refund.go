@@ -0,0 +1,256 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+var (
+	refundLock sync.Mutex
+
+	refundStableStarter      sync.Once
+	restIntervalRefundStable = 60 * time.Second
+	maxRefundsToStable       = 100
+
+	errSwapCanNotRefund = errors.New("swap status can not refund")
+	errRefundTxIsSent   = errors.New("refund tx is found on chain, wait for it to be stable")
+)
+
+// RefundResult refund result
+type RefundResult struct {
+	RefundTx string `json:"refundtx"`
+	To       string `json:"to"`
+	Value    string `json:"value"`
+}
+
+// InitiateRefund refund deposit (minus refund fee) of swap which can never complete
+// to the original sender on the deposit chain
+func InitiateRefund(caller, txid, pairID, bind string, isSwapin bool) (*RefundResult, error) {
+	refundLock.Lock()
+	defer refundLock.Unlock()
+
+	swap, err := mongodb.FindSwap(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return nil, err
+	}
+	if !swap.Status.CanRefund() {
+		return nil, fmt.Errorf("%w: %v", errSwapCanNotRefund, swap.Status)
+	}
+	bridge := tokens.GetCrossChainBridge(isSwapin)
+	oldRefund, _ := mongodb.FindRefund(txid, pairID, bind)
+	if oldRefund != nil {
+		// only refund whose tx is not sent can be initiated again
+		if oldRefund.Status != mongodb.RefundTxNotSent {
+			return nil, mongodb.ErrItemIsDup
+		}
+		if isRefundTxSent(bridge, oldRefund) {
+			return nil, errRefundTxIsSent
+		}
+	}
+
+	args, err := buildRefundTxArgs(txid, pairID, bind, isSwapin, tokens.SwapTxType(swap.TxType))
+	if err != nil {
+		return nil, err
+	}
+	rawTx, err := bridge.(tokens.RefundBuilder).BuildRefundTransaction(args)
+	if err != nil {
+		logWorkerError("refund", "build refund tx failed", err, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin)
+		return nil, err
+	}
+
+	var signedTx interface{}
+	var signTxHash string
+	tokenCfg := bridge.GetTokenConfig(pairID)
+	if tokenCfg.GetDcrmAddressPrivateKey() != nil {
+		signedTx, signTxHash, err = bridge.SignTransaction(rawTx, pairID)
+	} else {
+		signedTx, signTxHash, err = bridge.DcrmSignTransaction(rawTx, args)
+	}
+	if err != nil {
+		logWorkerError("refund", "sign refund tx failed", err, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin)
+		return nil, err
+	}
+
+	// update database before sending transaction
+	refund := &mongodb.MgoRefund{
+		PairID:      pairID,
+		TxID:        txid,
+		Bind:        bind,
+		IsSwapin:    isSwapin,
+		SwapStatus:  swap.Status,
+		To:          args.OriginFrom,
+		Value:       args.SwapValue.String(),
+		RefundTx:    signTxHash,
+		RefundNonce: args.GetTxNonce(),
+		Status:      mongodb.RefundTxNotStable,
+		Initiator:   caller,
+		InitTime:    now(),
+		Timestamp:   now(),
+	}
+	if oldRefund == nil {
+		err = mongodb.AddRefund(refund)
+	} else {
+		err = mongodb.ReplaceNotSentRefund(refund)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = sendRefundTransaction(bridge, signedTx, args)
+	if err != nil {
+		// swap is not marked refunded, the refund can be initiated again
+		_ = mongodb.UpdateRefundStatus(txid, pairID, bind, mongodb.RefundTxNotSent, 0, 0)
+		return nil, err
+	}
+	err = mongodb.MarkSwapRefunded(isSwapin, txid, pairID, bind, signTxHash)
+	if err != nil {
+		return nil, err
+	}
+	return &RefundResult{RefundTx: signTxHash, To: refund.To, Value: refund.Value}, nil
+}
+
+// isRefundTxSent is refund tx known on chain (sending may fail after it's broadcasted)
+func isRefundTxSent(bridge tokens.CrossChainBridge, refund *mongodb.MgoRefund) bool {
+	_, err := bridge.GetTransactionStatus(refund.RefundTx)
+	return err == nil
+}
+
+// buildRefundTxArgs verify deposit is valid but never swappable,
+// and build refund args (deposit chain is source chain of swapin, dest chain of swapout)
+func buildRefundTxArgs(txid, pairID, bind string, isSwapin bool, txType tokens.SwapTxType) (*tokens.BuildTxArgs, error) {
+	bridge := tokens.GetCrossChainBridge(isSwapin)
+	if _, ok := bridge.(tokens.RefundBuilder); !ok {
+		return nil, tokens.ErrRefundNotSupported
+	}
+	tokenCfg := bridge.GetTokenConfig(pairID)
+	if tokenCfg == nil {
+		return nil, tokens.ErrUnknownPairID
+	}
+	swapInfo, err := verifySwapTransaction(bridge, pairID, txid, bind, txType)
+	if !tokens.IsRefundableVerifyError(err) {
+		if err == nil {
+			err = errSwapCanNotRefund
+		}
+		return nil, err
+	}
+	swapType := tokens.SwapoutType
+	if isSwapin {
+		swapType = tokens.SwapinType
+	}
+	return &tokens.BuildTxArgs{
+		SwapInfo: tokens.SwapInfo{
+			PairID:     pairID,
+			SwapID:     txid,
+			SwapType:   swapType,
+			TxType:     txType,
+			Bind:       bind,
+			Identifier: tokens.RefundIdentifier,
+		},
+		From:        tokenCfg.DcrmAddress,
+		OriginFrom:  swapInfo.From,
+		OriginTxTo:  swapInfo.TxTo,
+		OriginValue: swapInfo.Value,
+	}, nil
+}
+
+func sendRefundTransaction(bridge tokens.CrossChainBridge, signedTx interface{}, args *tokens.BuildTxArgs) (err error) {
+	var txHash string
+	for i := 0; i < 3; i++ {
+		txHash, err = bridge.SendTransaction(signedTx)
+		if err == nil {
+			break
+		}
+		time.Sleep(1 * time.Second)
+	}
+	if err != nil {
+		logWorkerError("refund", "send refund tx failed", err, "pairID", args.PairID, "txid", args.SwapID, "bind", args.Bind, "isSwapin", args.IsSwapin())
+		return err
+	}
+	if nonceSetter, ok := bridge.(tokens.NonceSetter); ok && nonceSetter != nil {
+		nonceSetter.SetNonce(args.PairID, args.GetTxNonce()+1) // increase for next usage
+	}
+	logWorker("refund", "send refund tx success", "pairID", args.PairID, "txid", args.SwapID, "bind", args.Bind, "isSwapin", args.IsSwapin(), "refundtx", txHash)
+	return nil
+}
+
+// StartRefundStableJob refund stable job
+func StartRefundStableJob() {
+	mongodb.MgoWaitGroup.Add(1)
+	go startRefundStableJob()
+}
+
+func startRefundStableJob() {
+	refundStableStarter.Do(func() {
+		logWorker("refund", "start update refund stable job")
+		defer mongodb.MgoWaitGroup.Done()
+		for {
+			recoverNotSentRefunds()
+			refunds, err := mongodb.FindRefundsWithStatus(mongodb.RefundTxNotStable, maxRefundsToStable)
+			if err != nil {
+				logWorkerError("refund", "find refunds error", err)
+			}
+			for _, refund := range refunds {
+				if utils.IsCleanuping() {
+					logWorker("refund", "stop update refund stable job")
+					return
+				}
+				err = processRefundStable(refund)
+				if err != nil {
+					logWorkerError("refund", "process refund stable error", err, "txid", refund.TxID, "refundtx", refund.RefundTx)
+				}
+				time.Sleep(3 * time.Second) // in case of too frequently rpc calling
+			}
+			if utils.IsCleanuping() {
+				logWorker("refund", "stop update refund stable job")
+				return
+			}
+			restInJob(restIntervalRefundStable)
+		}
+	})
+}
+
+// recoverNotSentRefunds mark swap refunded if its refund tx is found on chain
+// though sending it returned error
+func recoverNotSentRefunds() {
+	refunds, err := mongodb.FindRefundsWithStatus(mongodb.RefundTxNotSent, maxRefundsToStable)
+	if err != nil {
+		logWorkerError("refund", "find not sent refunds error", err)
+		return
+	}
+	for _, refund := range refunds {
+		if !isRefundTxSent(tokens.GetCrossChainBridge(refund.IsSwapin), refund) {
+			continue
+		}
+		logWorker("refund", "found not sent refund tx on chain", "pairID", refund.PairID, "txid", refund.TxID, "bind", refund.Bind, "refundtx", refund.RefundTx)
+		err = mongodb.UpdateRefundStatus(refund.TxID, refund.PairID, refund.Bind, mongodb.RefundTxNotStable, 0, 0)
+		if err == nil {
+			err = mongodb.MarkSwapRefunded(refund.IsSwapin, refund.TxID, refund.PairID, refund.Bind, refund.RefundTx)
+		}
+		if err != nil {
+			logWorkerError("refund", "recover not sent refund failed", err, "txid", refund.TxID, "refundtx", refund.RefundTx)
+		}
+	}
+}
+
+func processRefundStable(refund *mongodb.MgoRefund) error {
+	bridge := tokens.GetCrossChainBridge(refund.IsSwapin)
+	txStatus, err := bridge.GetTransactionStatus(refund.RefundTx)
+	if err != nil || txStatus.BlockHeight == 0 {
+		return nil
+	}
+	if !tokens.IsTxStatusStable(txStatus, refund.PairID, refund.IsSwapin) {
+		return nil
+	}
+	status := mongodb.RefundTxStable
+	if txStatus.IsSwapTxOnChainAndFailed(bridge.GetTokenConfig(refund.PairID)) {
+		status = mongodb.RefundTxFailed
+		logWorkerWarn("refund", "refund tx failed on chain", "pairID", refund.PairID, "txid", refund.TxID, "bind", refund.Bind, "refundtx", refund.RefundTx)
+	}
+	return mongodb.UpdateRefundStatus(refund.TxID, refund.PairID, refund.Bind, status, txStatus.BlockHeight, txStatus.BlockTime)
+}
@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/leveldb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+type refundBridge struct {
+	tokens.CrossChainBridge
+	verifyErr error
+}
+
+func (b *refundBridge) GetTokenConfig(pairID string) *tokens.TokenConfig {
+	return &tokens.TokenConfig{DcrmAddress: "dcrm"}
+}
+
+func (b *refundBridge) VerifyTransaction(pairID, txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+	return &tokens.TxSwapInfo{PairID: pairID, Hash: txHash, From: "sender", Value: big.NewInt(1000)}, b.verifyErr
+}
+
+func (b *refundBridge) BuildRefundTransaction(args *tokens.BuildTxArgs) (interface{}, error) {
+	return args.OriginValue, nil
+}
+
+func (b *refundBridge) VerifyMsgHash(rawTx interface{}, msgHash []string) error {
+	return nil
+}
+
+func TestRebuildAndVerifyRefundMsgHash(t *testing.T) {
+	params.SetConfig(&params.BridgeConfig{Identifier: "test-bridge"})
+	oldSrcBridge := tokens.SrcBridge
+	defer func() { tokens.SrcBridge = oldSrcBridge }()
+	bridge := &refundBridge{}
+	tokens.SrcBridge = bridge
+
+	args := &tokens.BuildTxArgs{SwapInfo: tokens.SwapInfo{
+		Identifier: tokens.RefundIdentifier,
+		PairID:     "pair",
+		SwapID:     "txid",
+		SwapType:   tokens.SwapinType,
+		TxType:     tokens.SwapinTx,
+		Bind:       "bind",
+	}}
+
+	bridge.verifyErr = tokens.ErrTxWithWrongMemo
+	if err := rebuildAndVerifyRefundMsgHash("key", nil, args); err != nil {
+		t.Errorf("refund of never swappable deposit should be accepted, have %v", err)
+	}
+
+	bridge.verifyErr = nil
+	if err := rebuildAndVerifyRefundMsgHash("key", nil, args); !errors.Is(err, errSwapCanNotRefund) {
+		t.Errorf("refund of swappable deposit should be rejected, have %v", err)
+	}
+
+	bridge.verifyErr = tokens.ErrTxNotStable
+	if err := rebuildAndVerifyRefundMsgHash("key", nil, args); !errors.Is(err, tokens.ErrTxNotStable) {
+		t.Errorf("refund of non refundable status should be rejected, have %v", err)
+	}
+
+	// this node has accepted swapping the deposit
+	dir, err := ioutil.TempDir("", "refundtest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if lvldbHandle, err = leveldb.New(dir, 16, 16, false); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { closeLeveldb(); lvldbHandle = nil }()
+	swapArgs := &tokens.BuildTxArgs{SwapInfo: args.SwapInfo}
+	swapArgs.Identifier = params.GetIdentifier()
+	if err = AddAcceptRecord(swapArgs, "swaptx"); err != nil {
+		t.Fatal(err)
+	}
+	bridge.verifyErr = tokens.ErrTxWithWrongMemo
+	if err = rebuildAndVerifyRefundMsgHash("key", nil, args); !errors.Is(err, errAlreadySwapped) {
+		t.Errorf("refund of accepted swap should be rejected, have %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+var (
+	registryBindTopic = common.Keccak256Hash([]byte("Bind(address,address)"))
+
+	registryScanInterval  = 30 * time.Second
+	registryScanBatchSize = uint64(1000)
+
+	errWrongBindLog = errors.New("wrong registry bind log")
+
+	// registry contract -> next block height to scan
+	registryScanHeights = make(map[string]uint64)
+)
+
+type chainLogGetter interface {
+	GetLogs(filterQuery *types.FilterQuery) ([]*types.RPCLog, error)
+}
+
+// StartRegistryScanJob mirror bind events of registry contracts into registered addresses
+func StartRegistryScanJob() {
+	if !params.MustRegisterAccount() {
+		return
+	}
+	if _, ok := tokens.DstBridge.(chainLogGetter); !ok {
+		logWorker("registry", "destination chain does not support registry contract")
+		return
+	}
+	logWorker("registry", "start registry scan job")
+	go doRegistryScanJob()
+}
+
+func doRegistryScanJob() {
+	for {
+		if utils.IsCleanuping() {
+			return
+		}
+		registries := getRegistryContracts()
+		if len(registries) != 0 {
+			latest, err := tokens.DstBridge.GetLatestBlockNumber()
+			if err != nil {
+				logWorkerError("registry", "get latest block number failed", err)
+			} else {
+				for registry, startHeight := range registries {
+					scanRegistryContract(registry, startHeight, latest)
+				}
+			}
+		}
+		time.Sleep(registryScanInterval)
+	}
+}
+
+// getRegistryContracts registry contracts of destination tokens and their start heights
+func getRegistryContracts() map[string]uint64 {
+	registries := make(map[string]uint64)
+	for _, pairCfg := range tokens.GetTokenPairsConfig() {
+		registry := strings.ToLower(pairCfg.DestToken.RegistryContract)
+		if registry == "" {
+			continue
+		}
+		startHeight, exist := registries[registry]
+		if !exist || pairCfg.DestToken.RegistryStartHeight < startHeight {
+			registries[registry] = pairCfg.DestToken.RegistryStartHeight
+		}
+	}
+	return registries
+}
+
+func scanRegistryContract(registry string, startHeight, latest uint64) {
+	from, exist := registryScanHeights[registry]
+	if !exist {
+		scanned, err := mongodb.FindRegistryScanHeight(registry)
+		switch {
+		case err == nil:
+			from = scanned + 1
+		case errors.Is(err, mongodb.ErrItemNotFound):
+			from = startHeight
+		default:
+			logWorkerError("registry", "find registry scan height failed", err, "registry", registry)
+			return
+		}
+	}
+	confirmations := *tokens.DstBridge.GetChainConfig().Confirmations
+	if latest < confirmations {
+		return
+	}
+	stable := latest - confirmations
+	getter := tokens.DstBridge.(chainLogGetter)
+	precedence := params.GetRegisterPrecedence()
+	for from <= stable {
+		to := from + registryScanBatchSize - 1
+		if to > stable {
+			to = stable
+		}
+		logs, err := getter.GetLogs(&types.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{common.HexToAddress(registry)},
+			Topics:    [][]common.Hash{{registryBindTopic}},
+		})
+		if err != nil {
+			logWorkerError("registry", "get registry logs failed", err, "registry", registry, "from", from, "to", to)
+			return
+		}
+		for _, rlog := range logs {
+			if err = processRegistryBindLog(registry, rlog, precedence); err != nil {
+				logWorkerError("registry", "process registry bind log failed", err, "registry", registry, "from", from, "to", to)
+				return
+			}
+		}
+		if err = mongodb.UpdateRegistryScanHeight(registry, to); err != nil {
+			return
+		}
+		logWorker("registry", "scanned registry contract", "registry", registry, "from", from, "to", to, "binds", len(logs))
+		from = to + 1
+		registryScanHeights[registry] = from
+	}
+}
+
+func processRegistryBindLog(registry string, rlog *types.RPCLog, precedence string) error {
+	if rlog.Removed != nil && *rlog.Removed {
+		return nil
+	}
+	src, dst, err := parseRegistryBindLog(rlog)
+	if err != nil {
+		logWorkerWarn("registry", "ignore wrong registry bind log", "registry", registry, "txHash", rlog.TxHash, "err", err)
+		return nil
+	}
+	item := &mongodb.MgoRegisteredAddress{
+		Key:      strings.ToLower(src),
+		BindTo:   strings.ToLower(dst),
+		Registry: registry,
+	}
+	if rlog.TxHash != nil {
+		item.TxHash = rlog.TxHash.String()
+	}
+	if rlog.BlockNumber != nil {
+		item.BlockHeight = uint64(*rlog.BlockNumber)
+	}
+	return mongodb.AddChainRegisteredAddress(item, precedence)
+}
+
+// parseRegistryBindLog parse `Bind(address src, address dst)` log, addresses may be indexed or not
+func parseRegistryBindLog(rlog *types.RPCLog) (src, dst string, err error) {
+	if len(rlog.Topics) == 0 || rlog.Topics[0] != registryBindTopic {
+		return "", "", errWrongBindLog
+	}
+	var data []byte
+	if rlog.Data != nil {
+		data = *rlog.Data
+	}
+	switch len(rlog.Topics) {
+	case 3:
+		src = common.BytesToAddress(rlog.Topics[1][:]).String()
+		dst = common.BytesToAddress(rlog.Topics[2][:]).String()
+	case 2:
+		if len(data) != 32 {
+			return "", "", errWrongBindLog
+		}
+		src = common.BytesToAddress(rlog.Topics[1][:]).String()
+		dst = common.BytesToAddress(data).String()
+	case 1:
+		if len(data) != 64 {
+			return "", "", errWrongBindLog
+		}
+		src = common.BytesToAddress(data[:32]).String()
+		dst = common.BytesToAddress(data[32:]).String()
+	default:
+		return "", "", errWrongBindLog
+	}
+	return src, dst, nil
+}
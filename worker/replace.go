@@ -114,6 +114,9 @@ func processReplaceSwap(swap *mongodb.MgoSwapResult, isSwapin bool) {
 	if swap.SwapNonce == 0 || swap.SwapHeight != 0 {
 		return
 	}
+	if swap.PayoutLegCount > 1 {
+		return // payout legs are resent by stable job
+	}
 	if swap.Status != mongodb.MatchTxNotStable {
 		return
 	}
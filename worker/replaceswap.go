@@ -23,6 +23,7 @@ var (
 	errSignTxFailed       = errors.New("sign tx failed")
 	errUpdateOldTxsFailed = errors.New("update old swaptxs failed")
 	errNotNonceSupport    = errors.New("not nonce support bridge")
+	errReplaceSplitPayout = errors.New("forbid replace split payout swap, its legs are resent by stable job")
 
 	maxDistanceOfSwapNonce = uint64(5)
 )
@@ -52,6 +53,9 @@ func verifyReplaceSwap(txid, pairID, bind string, isSwapin, isManual bool) (*mon
 	if res.Status != mongodb.MatchTxNotStable {
 		return nil, nil, errSwapWithErrStatus
 	}
+	if res.PayoutLegCount > 1 {
+		return nil, nil, errReplaceSplitPayout
+	}
 
 	bridge := tokens.GetCrossChainBridge(!isSwapin)
 	err = checkIfSwapNonceHasPassed(bridge, res, true)
@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/dcrm"
+	"github.com/anyswap/CrossChain-Bridge/internal/replay"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+var (
+	maxReplayCaptures = int64(3)
+	curReplayCaptures = int64(0)
+
+	replayCaptureWaits        = 20
+	replayCaptureWaitInterval = 30 * time.Second
+)
+
+// captureReplayBundle capture replay bundle of a sign this node disagreed,
+// if others agreed it (which means verification differs across nodes).
+func captureReplayBundle(info *dcrm.SignInfoData, args *tokens.BuildTxArgs, disagreeReason string) {
+	oracleCfg := params.GetOracleConfig()
+	if oracleCfg == nil || oracleCfg.ReplayBundleDir == "" {
+		return
+	}
+	if args == nil || args.Identifier == tokens.AggregateIdentifier {
+		return
+	}
+	if atomic.AddInt64(&curReplayCaptures, 1) > maxReplayCaptures {
+		atomic.AddInt64(&curReplayCaptures, -1)
+		logWorkerWarn("replay", "too many replay captures, ignore", "keyID", info.Key)
+		return
+	}
+	go func() {
+		defer atomic.AddInt64(&curReplayCaptures, -1)
+		doCaptureReplayBundle(info, args, disagreeReason, oracleCfg.ReplayBundleDir, oracleCfg.ReplayBundleMaxSize)
+	}()
+}
+
+func doCaptureReplayBundle(info *dcrm.SignInfoData, args *tokens.BuildTxArgs, disagreeReason, dir string, maxSize int) {
+	keyID := info.Key
+	var signStatus *dcrm.SignStatus
+	for i := 0; i < replayCaptureWaits; i++ {
+		time.Sleep(replayCaptureWaitInterval)
+		status, err := dcrm.GetSignStatusDetail(keyID)
+		if err == nil && status.IsFinished() {
+			signStatus = status
+			break
+		}
+	}
+	if signStatus == nil {
+		logWorkerWarn("replay", "sign status is not finished, skip capture", "keyID", keyID)
+		return
+	}
+	agreeCount := signStatus.CountAgree()
+	if agreeCount == 0 {
+		logWorkerTrace("replay", "no others agreed, skip capture", "keyID", keyID)
+		return
+	}
+	bundle, err := replay.Capture(keyID, args, disagreeReason, maxSize)
+	if err != nil {
+		logWorkerError("replay", "capture replay bundle failed", err, "keyID", keyID)
+		return
+	}
+	initiator := getSignInfoInitiator(info)
+	bundle.Initiator = initiator
+	file, err := bundle.Save(dir, maxSize)
+	if err != nil {
+		logWorkerError("replay", "save replay bundle failed", err, "keyID", keyID, "file", file)
+		return
+	}
+	logWorker("replay", "capture replay bundle success", "keyID", keyID, "initiator", initiator, "agreeCount", agreeCount,
+		"pairID", args.PairID, "swapID", args.SwapID, "calls", len(bundle.Calls), "truncated", bundle.Truncated, "file", file)
+}
@@ -41,6 +41,9 @@ func updateHeartbeat() {
 		"enode":     dcrm.GetSelfEnode(),
 		"timestamp": timestamp,
 	}
+	if acceptJob := GetAcceptJobStatus(); acceptJob != nil {
+		args["acceptJob"] = acceptJob
+	}
 	var result string
 	var err error
 	for i := 0; i < 3; i++ {
@@ -0,0 +1,175 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// verdicts of risk hook
+const (
+	RiskVerdictAllow = "allow"
+	RiskVerdictDeny  = "deny"
+	RiskVerdictHold  = "hold"
+
+	maxRiskHookResponseLength = 4096
+)
+
+var (
+	riskHoldUntil     = make(map[string]int64) // key is swap cache key
+	riskHoldUntilLock sync.Mutex
+
+	// replaced in tests
+	postRiskHook           = doPostRiskHook
+	riskHooks    riskStore = mgoRiskStore{}
+
+	errRiskHookHold = errors.New("swap is held by risk hook")
+	errRiskRejected = errors.New("swap is denied by risk hook")
+)
+
+// RiskHookRequest swap details posted to risk hook before signing
+type RiskHookRequest struct {
+	Identifier  string                 `json:"identifier"`
+	PairID      string                 `json:"pairid"`
+	SwapType    string                 `json:"swaptype"`
+	TxID        string                 `json:"txid"`
+	TxHeight    uint64                 `json:"txheight"`
+	TxTime      uint64                 `json:"txtime"`
+	From        string                 `json:"from"`
+	Bind        string                 `json:"bind"`
+	Value       string                 `json:"value"`
+	BindHistory *mongodb.BindSwapStats `json:"bindHistory"`
+}
+
+// RiskHookResponse verdict replied by risk hook
+type RiskHookResponse struct {
+	Verdict string `json:"verdict"` // allow, deny or hold
+	Reason  string `json:"reason"`
+}
+
+// riskStore storage used by risk hook
+type riskStore interface {
+	GetBindSwapStats(isSwapin bool, pairID, bind string) (*mongodb.BindSwapStats, error)
+	AddSwapAudit(swap *mongodb.MgoSwap, isSwapin bool, operator, operation string, newStatus mongodb.SwapStatus, reason string) error
+	UpdateSwapStatus(isSwapin bool, txid, pairID, bind string, status mongodb.SwapStatus, timestamp int64, memo string) error
+}
+
+type mgoRiskStore struct{}
+
+func (mgoRiskStore) GetBindSwapStats(isSwapin bool, pairID, bind string) (*mongodb.BindSwapStats, error) {
+	return mongodb.GetBindSwapStats(isSwapin, pairID, bind)
+}
+
+func (mgoRiskStore) AddSwapAudit(swap *mongodb.MgoSwap, isSwapin bool, operator, operation string, newStatus mongodb.SwapStatus, reason string) error {
+	return mongodb.AddSwapAudit(swap, isSwapin, operator, operation, newStatus, reason)
+}
+
+func (mgoRiskStore) UpdateSwapStatus(isSwapin bool, txid, pairID, bind string, status mongodb.SwapStatus, timestamp int64, memo string) error {
+	return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, status, timestamp, memo)
+}
+
+func doPostRiskHook(config *params.RiskHookConfig, req *RiskHookRequest) (*RiskHookResponse, error) {
+	resp, err := client.HTTPPost(config.URL, req, nil, nil, config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("response status %v", resp.Status)
+	}
+	var result RiskHookResponse
+	if err = json.NewDecoder(io.LimitReader(resp.Body, maxRiskHookResponseLength)).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// checkRiskHook ask risk hook for verdict before signing swap,
+// it's skipped if risk hook is not configured or admin overrides denied swap.
+func checkRiskHook(swap *mongodb.MgoSwap, res *mongodb.MgoSwapResult, isSwapin bool) error {
+	config := params.GetServerConfig().RiskHook
+	if config == nil || swap.RiskOverridden {
+		return nil
+	}
+	pairID, txid, bind := swap.PairID, swap.TxID, swap.Bind
+	cacheKey := getSwapCacheKey(isSwapin, txid, bind)
+
+	riskHoldUntilLock.Lock()
+	holdUntil := riskHoldUntil[cacheKey]
+	riskHoldUntilLock.Unlock()
+	if now() < holdUntil {
+		return errRiskHookHold
+	}
+
+	verdict, err := queryRiskHook(config, swap, res, isSwapin)
+	if err != nil {
+		failOpen := tokens.IsRiskHookFailOpen(pairID, config.FailOpen)
+		logWorkerError("riskhook", "query risk hook failed", err, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "failOpen", failOpen)
+		if failOpen {
+			verdict = &RiskHookResponse{Verdict: RiskVerdictAllow, Reason: "fail open: " + err.Error()}
+		} else {
+			verdict = &RiskHookResponse{Verdict: RiskVerdictHold, Reason: "fail closed: " + err.Error()}
+		}
+	}
+
+	newStatus := swap.Status
+	switch verdict.Verdict {
+	case RiskVerdictAllow:
+	case RiskVerdictDeny:
+		newStatus = mongodb.RiskRejected
+	default:
+		verdict.Verdict = RiskVerdictHold // unknown verdict is held
+	}
+	reason := fmt.Sprintf("%v: %v", verdict.Verdict, verdict.Reason)
+	logWorker("riskhook", "risk hook verdict", "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "verdict", verdict.Verdict, "reason", verdict.Reason)
+	_ = riskHooks.AddSwapAudit(swap, isSwapin, mongodb.RiskHookOperator, mongodb.RiskVerdictAuditOp, newStatus, reason)
+
+	riskHoldUntilLock.Lock()
+	if verdict.Verdict == RiskVerdictHold {
+		riskHoldUntil[cacheKey] = now() + config.HoldRetryInterval
+	} else {
+		delete(riskHoldUntil, cacheKey)
+	}
+	riskHoldUntilLock.Unlock()
+
+	switch verdict.Verdict {
+	case RiskVerdictAllow:
+		return nil
+	case RiskVerdictDeny:
+		err = riskHooks.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.RiskRejected, now(), reason)
+		if err != nil {
+			return err
+		}
+		return errRiskRejected
+	default:
+		return errRiskHookHold
+	}
+}
+
+func queryRiskHook(config *params.RiskHookConfig, swap *mongodb.MgoSwap, res *mongodb.MgoSwapResult, isSwapin bool) (*RiskHookResponse, error) {
+	stats, err := riskHooks.GetBindSwapStats(isSwapin, swap.PairID, swap.Bind)
+	if err != nil {
+		return nil, fmt.Errorf("get bind swap stats failed, %w", err)
+	}
+	req := &RiskHookRequest{
+		Identifier:  params.GetIdentifier(),
+		PairID:      swap.PairID,
+		SwapType:    getSwapType(isSwapin).String(),
+		TxID:        swap.TxID,
+		TxHeight:    res.TxHeight,
+		TxTime:      res.TxTime,
+		From:        res.From,
+		Bind:        swap.Bind,
+		Value:       res.Value,
+		BindHistory: stats,
+	}
+	return postRiskHook(config, req)
+}
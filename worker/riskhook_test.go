@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+// memRiskStore in memory risk hook storage
+type memRiskStore struct {
+	audits   []string
+	statuses map[string]mongodb.SwapStatus
+}
+
+func (s *memRiskStore) GetBindSwapStats(isSwapin bool, pairID, bind string) (*mongodb.BindSwapStats, error) {
+	return &mongodb.BindSwapStats{Total: 3, Completed: 2}, nil
+}
+
+func (s *memRiskStore) AddSwapAudit(swap *mongodb.MgoSwap, isSwapin bool, operator, operation string, newStatus mongodb.SwapStatus, reason string) error {
+	s.audits = append(s.audits, reason)
+	return nil
+}
+
+func (s *memRiskStore) UpdateSwapStatus(isSwapin bool, txid, pairID, bind string, status mongodb.SwapStatus, timestamp int64, memo string) error {
+	s.statuses[txid] = status
+	return nil
+}
+
+func useRiskHook(t *testing.T, config *params.RiskHookConfig, hook func(*params.RiskHookConfig, *RiskHookRequest) (*RiskHookResponse, error)) *memRiskStore {
+	store := &memRiskStore{statuses: make(map[string]mongodb.SwapStatus)}
+	oldStore, oldHook := riskHooks, postRiskHook
+	riskHooks, postRiskHook = store, hook
+	params.SetConfig(&params.BridgeConfig{Server: &params.ServerConfig{RiskHook: config}})
+	t.Cleanup(func() {
+		riskHooks, postRiskHook = oldStore, oldHook
+		riskHoldUntilLock.Lock()
+		riskHoldUntil = make(map[string]int64)
+		riskHoldUntilLock.Unlock()
+	})
+	return store
+}
+
+func TestRiskHookNotConfigured(t *testing.T) {
+	useRiskHook(t, nil, func(*params.RiskHookConfig, *RiskHookRequest) (*RiskHookResponse, error) {
+		t.Fatal("risk hook should not be called if not configured")
+		return nil, nil
+	})
+	swap := &mongodb.MgoSwap{PairID: "pair", TxID: "tx", Bind: "bind", Status: mongodb.TxNotSwapped}
+	if err := checkRiskHook(swap, &mongodb.MgoSwapResult{}, true); err != nil {
+		t.Errorf("want signed, have %v", err)
+	}
+}
+
+func TestRiskHookVerdicts(t *testing.T) {
+	config := &params.RiskHookConfig{URL: "http://localhost", HoldRetryInterval: 300}
+	verdict := RiskVerdictHold
+	calls := 0
+	store := useRiskHook(t, config, func(_ *params.RiskHookConfig, req *RiskHookRequest) (*RiskHookResponse, error) {
+		calls++
+		if req.BindHistory == nil || req.BindHistory.Completed != 2 || req.Value != "1000" {
+			t.Errorf("wrong risk hook request %+v", req)
+		}
+		return &RiskHookResponse{Verdict: verdict, Reason: "test"}, nil
+	})
+	res := &mongodb.MgoSwapResult{Value: "1000"}
+
+	held := &mongodb.MgoSwap{PairID: "pair", TxID: "held", Bind: "bind", Status: mongodb.TxNotSwapped}
+	if err := checkRiskHook(held, res, true); !errors.Is(err, errRiskHookHold) {
+		t.Errorf("want held, have %v", err)
+	}
+	// not asked again before retry interval
+	if err := checkRiskHook(held, res, true); !errors.Is(err, errRiskHookHold) || calls != 1 {
+		t.Errorf("want held without asking, have %v and %v calls", err, calls)
+	}
+
+	verdict = RiskVerdictDeny
+	denied := &mongodb.MgoSwap{PairID: "pair", TxID: "denied", Bind: "bind", Status: mongodb.TxNotSwapped}
+	if err := checkRiskHook(denied, res, true); !errors.Is(err, errRiskRejected) {
+		t.Errorf("want denied, have %v", err)
+	}
+	if status := store.statuses["denied"]; status != mongodb.RiskRejected {
+		t.Errorf("want status %v, have %v", mongodb.RiskRejected, status)
+	}
+
+	// overridden by admin
+	denied.RiskOverridden = true
+	if err := checkRiskHook(denied, res, true); err != nil || calls != 2 {
+		t.Errorf("want signed without asking, have %v and %v calls", err, calls)
+	}
+
+	verdict = RiskVerdictAllow
+	allowed := &mongodb.MgoSwap{PairID: "pair", TxID: "allowed", Bind: "bind", Status: mongodb.TxNotSwapped}
+	if err := checkRiskHook(allowed, res, true); err != nil {
+		t.Errorf("want signed, have %v", err)
+	}
+
+	verdict = "unknown"
+	if err := checkRiskHook(allowed, res, false); !errors.Is(err, errRiskHookHold) {
+		t.Errorf("want unknown verdict held, have %v", err)
+	}
+
+	if len(store.audits) != 4 {
+		t.Errorf("want 4 verdicts audited, have %v", store.audits)
+	}
+}
+
+func TestRiskHookUnavailable(t *testing.T) {
+	config := &params.RiskHookConfig{URL: "http://localhost", HoldRetryInterval: 300}
+	useRiskHook(t, config, func(*params.RiskHookConfig, *RiskHookRequest) (*RiskHookResponse, error) {
+		return nil, errors.New("timeout")
+	})
+	res := &mongodb.MgoSwapResult{Value: "1000"}
+
+	swap := &mongodb.MgoSwap{PairID: "pair", TxID: "closed", Bind: "bind", Status: mongodb.TxNotSwapped}
+	if err := checkRiskHook(swap, res, true); !errors.Is(err, errRiskHookHold) {
+		t.Errorf("want held if fail closed, have %v", err)
+	}
+
+	config.FailOpen = true
+	swap.TxID = "open"
+	if err := checkRiskHook(swap, res, true); err != nil {
+		t.Errorf("want signed if fail open, have %v", err)
+	}
+}
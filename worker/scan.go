@@ -15,4 +15,8 @@ func StartScanJob(isServer bool) {
 		}
 		go btc.BridgeInstance.StartSwapHistoryScanJob()
 	}
+	if isServer {
+		StartRegistryScanJob()
+		StartUnsupportedDepositScanJob()
+	}
 }
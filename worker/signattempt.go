@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/dcrm"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// recordSignAttempt record dcrm sign attempt to swap result,
+// the swap is identified by the build tx args in sign message context.
+func recordSignAttempt(msgContext []string, attempt *dcrm.SignAttempt) {
+	if len(msgContext) == 0 {
+		return
+	}
+	var args tokens.BuildTxArgs
+	if err := json.Unmarshal([]byte(msgContext[0]), &args); err != nil || args.SwapID == "" {
+		return
+	}
+	if attempt.DcrmStatus == dcrm.SignStatusDisagree {
+		go recordInitiatorDisagreement(&args, attempt.KeyID, attempt.Initiator)
+	}
+	if !mongodb.HasClient() {
+		return
+	}
+	item := &mongodb.MgoSignAttempt{
+		KeyID:       attempt.KeyID,
+		Initiator:   strings.ToLower(attempt.Initiator),
+		InitiatedAt: attempt.InitiatedAt,
+		FinishedAt:  attempt.FinishedAt,
+		DcrmStatus:  attempt.DcrmStatus,
+		RsvReceived: attempt.RsvReceived,
+	}
+	err := mongodb.RecordSignAttempt(args.IsSwapin(), args.SwapID, args.PairID, args.Bind, item)
+	if err != nil {
+		logWorkerWarn("sign", "record sign attempt failed", "keyID", attempt.KeyID, "txid", args.SwapID, "pairID", args.PairID, "bind", args.Bind, "err", err)
+	}
+}
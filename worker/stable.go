@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -13,9 +14,20 @@ var (
 	swapinStableStarter  sync.Once
 	swapoutStableStarter sync.Once
 
+	swapsInStable sync.Map // swap key -> struct{}, swaps being processed
+
 	treatAsNoncePassedInterval = int64(600) // seconds
 )
 
+// tryLockSwapStable prevent stable job and broadcast watch handling the same swap concurrently
+func tryLockSwapStable(swap *mongodb.MgoSwapResult, isSwapin bool) (unlock func(), ok bool) {
+	key := fmt.Sprintf("%v:%v", isSwapin, mongodb.GetSwapKey(swap.TxID, swap.PairID, swap.Bind))
+	if _, loaded := swapsInStable.LoadOrStore(key, struct{}{}); loaded {
+		return nil, false
+	}
+	return func() { swapsInStable.Delete(key) }, true
+}
+
 // StartStableJob stable job
 func StartStableJob() {
 	mongodb.MgoWaitGroup.Add(2)
@@ -43,6 +55,9 @@ func startSwapinStableJob() {
 				err = processSwapinStable(swap)
 				if err != nil {
 					logWorkerError("stable", "process swapin stable error", err)
+					recordSwapFailure("stable", true, true, swap.TxID, swap.PairID, swap.Bind, mongodb.StageStable, err)
+				} else {
+					resetSwapFailure(true, true, swap.TxID, swap.PairID, swap.Bind, swap.FailCount)
 				}
 				time.Sleep(3 * time.Second) // in case of too frequently rpc calling
 			}
@@ -75,6 +90,9 @@ func startSwapoutStableJob() {
 				err = processSwapoutStable(swap)
 				if err != nil {
 					logWorkerError("stable", "process swapout stable error", err)
+					recordSwapFailure("stable", false, true, swap.TxID, swap.PairID, swap.Bind, mongodb.StageStable, err)
+				} else {
+					resetSwapFailure(false, true, swap.TxID, swap.PairID, swap.Bind, swap.FailCount)
 				}
 			}
 			if utils.IsCleanuping() {
@@ -110,7 +128,7 @@ func processSwapoutStable(swap *mongodb.MgoSwapResult) (err error) {
 
 func getSwapTxStatus(resBridge tokens.CrossChainBridge, swap *mongodb.MgoSwapResult) *tokens.TxStatus {
 	txStatus, err := resBridge.GetTransactionStatus(swap.SwapTx)
-	if err == nil && txStatus != nil && txStatus.BlockHeight > 0 {
+	if err == nil && txStatus.BlockHeight > 0 {
 		return txStatus
 	}
 	for i, oldSwapTx := range swap.OldSwapTxs {
@@ -118,7 +136,7 @@ func getSwapTxStatus(resBridge tokens.CrossChainBridge, swap *mongodb.MgoSwapRes
 			continue
 		}
 		txStatus, err = resBridge.GetTransactionStatus(oldSwapTx)
-		if err == nil && txStatus != nil && txStatus.BlockHeight > 0 {
+		if err == nil && txStatus.BlockHeight > 0 {
 			swap.SwapTx = oldSwapTx
 			if i < len(swap.OldSwapVals) {
 				swap.SwapValue = swap.OldSwapVals[i]
@@ -129,7 +147,18 @@ func getSwapTxStatus(resBridge tokens.CrossChainBridge, swap *mongodb.MgoSwapRes
 	return nil
 }
 
+// processSwapStable skip the swap if it is being processed by others,
+// it will be processed again by the polling of stable job.
 func processSwapStable(swap *mongodb.MgoSwapResult, isSwapin bool) (err error) {
+	unlock, ok := tryLockSwapStable(swap, isSwapin)
+	if !ok {
+		return nil
+	}
+	defer unlock()
+
+	if swap.PayoutLegCount > 1 {
+		return processPayoutLegsStable(swap, isSwapin)
+	}
 	oldSwapTx := swap.SwapTx
 	resBridge := tokens.GetCrossChainBridge(!isSwapin)
 	txStatus := getSwapTxStatus(resBridge, swap)
@@ -141,7 +170,7 @@ func processSwapStable(swap *mongodb.MgoSwapResult, isSwapin bool) (err error) {
 	}
 
 	if swap.SwapHeight != 0 {
-		if txStatus.Confirmations < *resBridge.GetChainConfig().Confirmations {
+		if !tokens.IsTxStatusStable(txStatus, swap.PairID, !isSwapin) {
 			return nil
 		}
 		if swap.SwapTx != oldSwapTx {
@@ -3,6 +3,7 @@ package worker
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
@@ -29,6 +30,9 @@ var (
 	errDBError            = errors.New("database error")
 	errSendTxWithDiffHash = errors.New("send tx with different hash")
 	errSwapChannelIsFull  = errors.New("swap task channel is full")
+
+	errSwapNotStableVerified = errors.New("swap is not verified at stable depth")
+	errSwapTxBlockReorged    = errors.New("block of verified swap tx is reorged")
 )
 
 // StartSwapJob swap job
@@ -40,13 +44,13 @@ func StartSwapJob() {
 	if tokens.SrcNonceSetter != nil {
 		tokens.SrcNonceSetter.InitNonces(swapoutNonces)
 	}
-	for _, pairCfg := range tokens.GetTokenPairsConfig() {
+	for _, pairCfg := range tokens.GetSortedTokenPairsConfig() {
 		AddSwapJob(pairCfg)
 	}
 
 	mongodb.MgoWaitGroup.Add(2)
-	go startSwapinSwapJob()
-	go startSwapoutSwapJob()
+	go startDoSwapJob(true)
+	go startDoSwapJob(false)
 }
 
 // AddSwapJob add swap job
@@ -65,96 +69,67 @@ func AddSwapJob(pairCfg *tokens.TokenPairConfig) {
 	}
 }
 
-func startSwapinSwapJob() {
-	logWorker("swap", "start swapin swap job")
-	defer mongodb.MgoWaitGroup.Done()
-	for {
-		if utils.IsCleanuping() {
-			logWorker("swap", "stop swapin swap job")
-			return
-		}
-		processSwapins(mongodb.TxNotSwapped)
-		restInJob(restIntervalInDoSwapJob)
+func getSwapTypeName(isSwapin bool) string {
+	if isSwapin {
+		return "swapin"
 	}
+	return "swapout"
 }
 
-func startSwapoutSwapJob() {
-	logWorker("swap", "start swapout swap job")
+func startDoSwapJob(isSwapin bool) {
+	swapType := getSwapTypeName(isSwapin)
+	logWorker("swap", "start "+swapType+" swap job")
 	defer mongodb.MgoWaitGroup.Done()
 	for {
 		if utils.IsCleanuping() {
-			logWorker("swap", "stop swapout swap job")
+			logWorker("swap", "stop "+swapType+" swap job")
 			return
 		}
-		processSwapouts(mongodb.TxNotSwapped)
+		processSwaps(isSwapin, mongodb.TxNotSwapped)
 		restInJob(restIntervalInDoSwapJob)
 	}
 }
 
-func processSwapins(status mongodb.SwapStatus) {
-	swapins, err := findSwapinsToSwap(status)
+func processSwaps(isSwapin bool, status mongodb.SwapStatus) {
+	swapType := getSwapTypeName(isSwapin)
+	swaps, err := findSwapsToSwap(isSwapin, status)
 	if err != nil {
-		logWorkerError("swapin", "find swapins error", err, "status", status)
-		return
-	}
-	if len(swapins) == 0 {
+		logWorkerError(swapType, "find "+swapType+"s error", err, "status", status)
 		return
 	}
-	logWorker("swapin", "find swapins to swap", "status", status, "count", len(swapins))
-	for _, swap := range swapins {
-		if utils.IsCleanuping() {
-			return
-		}
-		err := processSwapinSwap(swap)
-		switch {
-		case err == nil,
-			errors.Is(err, errAlreadySwapped),
-			errors.Is(err, errSwapChannelIsFull),
-			errors.Is(err, errDBError),
-			errors.Is(err, tokens.ErrUnknownPairID),
-			errors.Is(err, tokens.ErrAddressIsInBlacklist),
-			errors.Is(err, tokens.ErrSwapIsClosed):
-		default:
-			logWorkerError("swapin", "process swapin swap error", err, "pairID", swap.PairID, "txid", swap.TxID, "bind", swap.Bind)
-		}
-	}
-}
-
-func processSwapouts(status mongodb.SwapStatus) {
-	swapouts, err := findSwapoutsToSwap(status)
-	if err != nil {
-		logWorkerError("swapout", "find swapouts error", err, "status", status)
-	}
-	if len(swapouts) == 0 {
+	if len(swaps) == 0 {
 		return
 	}
-	logWorker("swapout", "find swapouts to swap", "status", status, "count", len(swapouts))
-	for _, swap := range swapouts {
+	logWorker(swapType, "find "+swapType+"s to swap", "status", status, "count", len(swaps))
+	for _, swap := range swaps {
 		if utils.IsCleanuping() {
 			return
 		}
-		err := processSwapoutSwap(swap)
+		err := processSwap(swap, isSwapin)
 		switch {
-		case err == nil,
-			errors.Is(err, errAlreadySwapped),
+		case err == nil:
+			resetSwapFailure(isSwapin, false, swap.TxID, swap.PairID, swap.Bind, swap.FailCount)
+		case errors.Is(err, errAlreadySwapped),
 			errors.Is(err, errSwapChannelIsFull),
 			errors.Is(err, errDBError),
+			errors.Is(err, errSwapNotStableVerified),
+			errors.Is(err, errRiskHookHold),
+			errors.Is(err, errRiskRejected),
 			errors.Is(err, tokens.ErrUnknownPairID),
 			errors.Is(err, tokens.ErrAddressIsInBlacklist),
 			errors.Is(err, tokens.ErrSwapIsClosed):
 		default:
-			logWorkerError("swapout", "process swapout swap error", err, "pairID", swap.PairID, "txid", swap.TxID, "bind", swap.Bind)
+			logWorkerError(swapType, "process "+swapType+" swap error", err, "pairID", swap.PairID, "txid", swap.TxID, "bind", swap.Bind)
+			recordSwapFailure(swapType, isSwapin, false, swap.TxID, swap.PairID, swap.Bind, mongodb.StageSwap, err)
 		}
 	}
 }
 
-func findSwapinsToSwap(status mongodb.SwapStatus) ([]*mongodb.MgoSwap, error) {
-	septime := getSepTimeInFind(maxDoSwapLifetime)
-	return mongodb.FindSwapinsWithStatus(status, septime)
-}
-
-func findSwapoutsToSwap(status mongodb.SwapStatus) ([]*mongodb.MgoSwap, error) {
+func findSwapsToSwap(isSwapin bool, status mongodb.SwapStatus) ([]*mongodb.MgoSwap, error) {
 	septime := getSepTimeInFind(maxDoSwapLifetime)
+	if isSwapin {
+		return mongodb.FindSwapinsWithStatus(status, septime)
+	}
 	return mongodb.FindSwapoutsWithStatus(status, septime)
 }
 
@@ -174,14 +149,6 @@ func isSwapInBlacklist(swap *mongodb.MgoSwapResult) (isBlacked bool, err error)
 	return isBlacked, nil
 }
 
-func processSwapinSwap(swap *mongodb.MgoSwap) (err error) {
-	return processSwap(swap, true)
-}
-
-func processSwapoutSwap(swap *mongodb.MgoSwap) (err error) {
-	return processSwap(swap, false)
-}
-
 func processSwap(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
 	pairID := swap.PairID
 	txid := swap.TxID
@@ -192,6 +159,12 @@ func processSwap(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
 		return errAlreadySwapped
 	}
 
+	if err = checkSwapStableVerified(swap); err != nil {
+		logWorkerWarn("swap", "refuse to sign swap not verified at stable depth", "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin)
+		_ = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.RegisteredUnstable, now(), err.Error())
+		return err
+	}
+
 	res, err := mongodb.FindSwapResult(isSwapin, txid, pairID, bind)
 	if err != nil {
 		return err
@@ -211,18 +184,21 @@ func processSwap(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
 		return err
 	}
 
+	if err = checkRiskHook(swap, res, isSwapin); err != nil {
+		return err
+	}
+
 	logWorker("swap", "start process swap", "pairID", pairID, "txid", txid, "bind", bind, "status", swap.Status, "isSwapin", isSwapin, "value", res.Value)
 
 	srcBridge := tokens.GetCrossChainBridge(isSwapin)
-	swapInfo, err := verifySwapTransaction(srcBridge, pairID, txid, bind, tokens.SwapTxType(swap.TxType))
+	swapValue, err := getVerifiedSwapValue(srcBridge, swap, res, isSwapin)
 	if err != nil {
-		return fmt.Errorf("[doSwap] reverify swap failed, %w", err)
-	}
-	if swapInfo.Value.String() != res.Value {
-		return fmt.Errorf("[doSwap] reverify swap value mismatch, in db %v != %v", res.Value, swapInfo.Value)
+		return err
 	}
-	if !strings.EqualFold(swapInfo.Bind, bind) {
-		return fmt.Errorf("[doSwap] reverify swap bind address mismatch, in db %v != %v", bind, swapInfo.Bind)
+
+	payoutLeg, err := getFirstPayoutLeg(pairID, swapValue, isSwapin, swap.From, swap.TxTo)
+	if err != nil {
+		return err
 	}
 
 	swapType := getSwapType(isSwapin)
@@ -235,16 +211,63 @@ func processSwap(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
 			TxType:     tokens.SwapTxType(swap.TxType),
 			Bind:       bind,
 			Reswapping: res.Status == mongodb.Reswapping,
+			PayoutLeg:  payoutLeg,
 		},
 		From:        dcrmAddress,
 		OriginFrom:  swap.From,
 		OriginTxTo:  swap.TxTo,
-		OriginValue: swapInfo.Value,
+		OriginValue: swapValue,
 	}
 
 	return dispatchSwapTask(args)
 }
 
+// getVerifiedSwapValue get swap value verified by verify job. if the verified tx's
+// block is still canonical, the persisted result is used instead of reverifying.
+func getVerifiedSwapValue(bridge tokens.CrossChainBridge, swap *mongodb.MgoSwap, res *mongodb.MgoSwapResult, isSwapin bool) (*big.Int, error) {
+	pairID := swap.PairID
+	txid := swap.TxID
+	bind := swap.Bind
+
+	if getter, ok := bridge.(tokens.BlockHashGetter); ok && res.TxBlockHash != "" {
+		value, ok := new(big.Int).SetString(res.Value, 10)
+		if !ok {
+			return nil, fmt.Errorf("[doSwap] wrong swap value %v in db", res.Value)
+		}
+		blockHash, err := getter.GetBlockHash(res.TxHeight)
+		if err != nil {
+			return nil, fmt.Errorf("[doSwap] check verified tx block failed, %w", err)
+		}
+		if !strings.EqualFold(blockHash, res.TxBlockHash) {
+			logWorkerWarn("swap", "verified tx block is reorged", "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "height", res.TxHeight, "verified", res.TxBlockHash, "canonical", blockHash)
+			_ = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.RegisteredUnstable, now(), errSwapTxBlockReorged.Error())
+			return nil, errSwapTxBlockReorged
+		}
+		return value, nil
+	}
+
+	swapInfo, err := verifySwapTransaction(bridge, pairID, txid, bind, tokens.SwapTxType(swap.TxType))
+	if err != nil {
+		return nil, fmt.Errorf("[doSwap] reverify swap failed, %w", err)
+	}
+	if swapInfo.Value.String() != res.Value {
+		return nil, fmt.Errorf("[doSwap] reverify swap value mismatch, in db %v != %v", res.Value, swapInfo.Value)
+	}
+	if !strings.EqualFold(swapInfo.Bind, bind) {
+		return nil, fmt.Errorf("[doSwap] reverify swap bind address mismatch, in db %v != %v", bind, swapInfo.Bind)
+	}
+	return swapInfo.Value, nil
+}
+
+// checkSwapStableVerified only swaps verified at stable depth by verify job can be signed,
+// others are sent back to verify job.
+func checkSwapStableVerified(swap *mongodb.MgoSwap) error {
+	if swap.Status != mongodb.TxNotSwapped || !swap.StableVerified {
+		return errSwapNotStableVerified
+	}
+	return nil
+}
+
 func checkSwapResult(res *mongodb.MgoSwapResult, isSwapin bool) (dcrmAddress string, err error) {
 	pairID := res.PairID
 	txid := res.TxID
@@ -282,7 +305,8 @@ func preventReswap(res *mongodb.MgoSwapResult, isSwapin bool) error {
 	case mongodb.TxWithBigValue,
 		mongodb.TxWithWrongMemo,
 		mongodb.BindAddrIsContract,
-		mongodb.TxWithWrongValue:
+		mongodb.TxWithWrongValue,
+		mongodb.SwapValueIsDust:
 		_ = mongodb.UpdateSwapStatus(isSwapin, res.TxID, res.PairID, res.Bind, res.Status, now(), "")
 		return fmt.Errorf("forbid doswap for swap with status %v", res.Status.String())
 	default:
@@ -309,7 +333,7 @@ func preventReswapByHistory(res *mongodb.MgoSwapResult, isSwapin bool) error {
 		resBridge := tokens.GetCrossChainBridge(!isSwapin)
 		for _, swaphist := range swapHistories {
 			txStatus, err := resBridge.GetTransactionStatus(swaphist.SwapTx)
-			if err != nil {
+			if err != nil || txStatus.BlockHeight == 0 {
 				continue
 			}
 			if txStatus.Receipt != nil {
@@ -318,7 +342,7 @@ func preventReswapByHistory(res *mongodb.MgoSwapResult, isSwapin bool) error {
 					alreadySwapped = true
 					break
 				}
-			} else if txStatus != nil && txStatus.BlockHeight > 0 {
+			} else {
 				alreadySwapped = true
 				break
 			}
@@ -434,6 +458,10 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 	isSwapin := swapType == tokens.SwapinType
 	resBridge := tokens.GetCrossChainBridge(!isSwapin)
 
+	if isPayoutLegResend(args) {
+		return doSwapPayoutLeg(args)
+	}
+
 	cacheKey := getSwapCacheKey(isSwapin, txid, bind)
 	err = checkAndUpdateProcessSwapTaskCache(cacheKey)
 	if err != nil {
@@ -458,25 +486,8 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 
 	swapNonce := args.GetTxNonce()
 
-	var signedTx interface{}
-	var signTxHash string
-	tokenCfg := resBridge.GetTokenConfig(pairID)
-	for i := 1; i <= 3; i++ { // with retry
-		if tokenCfg.GetDcrmAddressPrivateKey() != nil {
-			signedTx, signTxHash, err = resBridge.SignTransaction(rawTx, pairID)
-		} else {
-			signedTx, signTxHash, err = resBridge.DcrmSignTransaction(rawTx, args)
-		}
-		if err == nil {
-			break
-		}
-		logWorkerError("doSwap", "sign tx failed", err, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "signCount", i)
-		restInJob(retrySignInterval)
-	}
+	signedTx, signTxHash, err := signSwapTx(resBridge, rawTx, args)
 	if err != nil {
-		if errors.Is(err, dcrm.ErrGetSignStatusHasDisagree) {
-			reverifySwap(args)
-		}
 		return err
 	}
 
@@ -496,6 +507,9 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 		SwapType:  swapType,
 		SwapNonce: swapNonce,
 	}
+	if args.PayoutLeg != nil {
+		matchTx.PayoutLegCount = args.PayoutLeg.Count
+	}
 	if args.SwapValue != nil {
 		matchTx.SwapValue = args.SwapValue.String()
 	} else {
@@ -519,9 +533,34 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 		logWorkerError("doSwap", "send tx success but with different hash", errSendTxWithDiffHash, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "swapNonce", swapNonce, "txHash", txHash, "signTxHash", signTxHash)
 		_ = mongodb.UpdateSwapResultOldTxs(txid, pairID, bind, txHash, matchTx.SwapValue, isSwapin)
 	}
+	if err == nil && args.PayoutLeg != nil {
+		sendPayoutLegs(args)
+	}
 	return err
 }
 
+// signSwapTx sign swap tx with private key or dcrm, with retry
+func signSwapTx(resBridge tokens.CrossChainBridge, rawTx interface{}, args *tokens.BuildTxArgs) (signedTx interface{}, signTxHash string, err error) {
+	pairID := args.PairID
+	tokenCfg := resBridge.GetTokenConfig(pairID)
+	for i := 1; i <= 3; i++ { // with retry
+		if tokenCfg.GetDcrmAddressPrivateKey() != nil {
+			signedTx, signTxHash, err = resBridge.SignTransaction(rawTx, pairID)
+		} else {
+			signedTx, signTxHash, err = resBridge.DcrmSignTransaction(rawTx, args)
+		}
+		if err == nil {
+			return signedTx, signTxHash, nil
+		}
+		logWorkerError("doSwap", "sign tx failed", err, "pairID", pairID, "txid", args.SwapID, "bind", args.Bind, "isSwapin", args.IsSwapin(), "signCount", i)
+		restInJob(retrySignInterval)
+	}
+	if errors.Is(err, dcrm.ErrGetSignStatusHasDisagree) {
+		reverifySwap(args)
+	}
+	return nil, "", err
+}
+
 func reverifySwap(args *tokens.BuildTxArgs) {
 	pairID := args.PairID
 	txid := args.SwapID
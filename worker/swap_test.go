@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func TestSwapCannotSignWithUnstableVerification(t *testing.T) {
+	// registration verified with allowUnstable=true
+	swap := &mongodb.MgoSwap{Status: mongodb.GetStatusByTokenVerifyError(nil)}
+	if swap.Status != mongodb.RegisteredUnstable {
+		t.Fatalf("registration should have status %v, have %v", mongodb.RegisteredUnstable, swap.Status)
+	}
+	if err := checkSwapStableVerified(swap); !errors.Is(err, errSwapNotStableVerified) {
+		t.Errorf("unstable registration should not be signed, have %v", err)
+	}
+
+	// status is promoted by other paths without verifying at stable depth
+	swap.Status = mongodb.TxNotSwapped
+	if err := checkSwapStableVerified(swap); !errors.Is(err, errSwapNotStableVerified) {
+		t.Errorf("swap without stable verified marker should not be signed, have %v", err)
+	}
+
+	// promoted by verify job
+	swap.StableVerified = true
+	if err := checkSwapStableVerified(swap); err != nil {
+		t.Errorf("stable verified swap should be signed, have %v", err)
+	}
+}
+
+type countingBridge struct {
+	tokens.CrossChainBridge
+	blockHash      string
+	verifyCalls    int
+	blockHashCalls int
+}
+
+func (b *countingBridge) VerifyTransaction(pairID, txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+	b.verifyCalls++
+	return &tokens.TxSwapInfo{PairID: pairID, Hash: txHash, Height: 100, BlockHash: b.blockHash, Bind: "bind", Value: big.NewInt(1000)}, nil
+}
+
+func (b *countingBridge) GetBlockHash(height uint64) (string, error) {
+	b.blockHashCalls++
+	return "0xABCD", nil
+}
+
+func TestSwapUsePersistedVerifyResult(t *testing.T) {
+	swap := &mongodb.MgoSwap{PairID: "pair", TxID: "txid", Bind: "bind"}
+
+	// verify job verifies the tx and persists the result
+	bridge := &countingBridge{blockHash: "0xabcd"}
+	swapInfo, err := bridge.VerifyTransaction(swap.PairID, swap.TxID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := newInitialSwapResult(swapInfo, mongodb.MatchTxEmpty, true, "")
+	if res.TxBlockHash != swapInfo.BlockHash || res.TxHeight != swapInfo.Height {
+		t.Errorf("want persisted block %v at height %v, have %v at %v", swapInfo.BlockHash, swapInfo.Height, res.TxBlockHash, res.TxHeight)
+	}
+	if bridge.blockHashCalls != 0 {
+		t.Errorf("persisted block hash should come from the verified tx, have %v block hash calls", bridge.blockHashCalls)
+	}
+
+	// swap job signs with the persisted result
+	value, err := getVerifiedSwapValue(bridge, swap, res, true)
+	if err != nil || value.String() != res.Value {
+		t.Fatalf("use persisted verify result failed, value %v, err %v", value, err)
+	}
+	if bridge.verifyCalls != 1 || bridge.blockHashCalls != 1 {
+		t.Errorf("persisted verify result should only check block hash, have %v verify calls and %v block hash calls", bridge.verifyCalls, bridge.blockHashCalls)
+	}
+
+	// block hash is not persisted if the swap is not to be signed
+	if res = newInitialSwapResult(swapInfo, mongodb.TxWithWrongMemo, true, ""); res.TxBlockHash != "" {
+		t.Errorf("want no block hash of status %v, have %v", res.Status, res.TxBlockHash)
+	}
+
+	// chain without block hash of verified tx, or result verified before block hash is persisted
+	bridge = &countingBridge{}
+	swapInfo, _ = bridge.VerifyTransaction(swap.PairID, swap.TxID, false)
+	res = newInitialSwapResult(swapInfo, mongodb.MatchTxEmpty, true, "")
+	value, err = getVerifiedSwapValue(bridge, swap, res, true)
+	if err != nil || value.String() != res.Value {
+		t.Fatalf("reverify swap failed, value %v, err %v", value, err)
+	}
+	if bridge.verifyCalls != 2 || bridge.blockHashCalls != 0 {
+		t.Errorf("swap without block hash should be reverified, have %v verify calls and %v block hash calls", bridge.verifyCalls, bridge.blockHashCalls)
+	}
+}
@@ -0,0 +1,195 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+const unsupportedDepositPushTimeout = 60 // seconds
+
+var (
+	erc20TransferTopic = common.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+	unsupportedDepositScanInterval  = 60 * time.Second
+	unsupportedDepositScanBatchSize = uint64(1000)
+)
+
+// StartUnsupportedDepositScanJob record transfers of unsupported tokens to deposit addresses of source chain
+func StartUnsupportedDepositScanJob() {
+	config := params.GetServerConfig().UnsupportedDeposit
+	if config == nil || !config.Enable {
+		return
+	}
+	if _, ok := tokens.SrcBridge.(chainLogGetter); !ok {
+		logWorker("unsupporteddeposit", "source chain does not support scanning token transfers")
+		return
+	}
+	logWorker("unsupporteddeposit", "start unsupported deposit scan job")
+	go doUnsupportedDepositScanJob(config.StartHeight)
+}
+
+func doUnsupportedDepositScanJob(startHeight uint64) {
+	next := uint64(0) // zero means scan height is not loaded yet
+	for {
+		if utils.IsCleanuping() {
+			return
+		}
+		latest, err := tokens.SrcBridge.GetLatestBlockNumber()
+		if err != nil {
+			logWorkerError("unsupporteddeposit", "get latest block number failed", err)
+		} else {
+			next = scanUnsupportedDeposits(next, startHeight, latest)
+		}
+		time.Sleep(unsupportedDepositScanInterval)
+	}
+}
+
+// getUnsupportedDepositFilter lower case deposit addresses and supported token contracts of source chain
+func getUnsupportedDepositFilter() (depositAddresses, supportedTokens map[string]struct{}) {
+	depositAddresses = make(map[string]struct{})
+	supportedTokens = make(map[string]struct{})
+	for _, pairCfg := range tokens.GetTokenPairsConfig() {
+		if pairCfg.SrcToken.DepositAddress != "" {
+			depositAddresses[strings.ToLower(pairCfg.SrcToken.DepositAddress)] = struct{}{}
+		}
+		if pairCfg.SrcToken.ContractAddress != "" {
+			supportedTokens[strings.ToLower(pairCfg.SrcToken.ContractAddress)] = struct{}{}
+		}
+	}
+	return depositAddresses, supportedTokens
+}
+
+// scanUnsupportedDeposits scan stable blocks from height 'next', returns next height to scan
+func scanUnsupportedDeposits(next, startHeight, latest uint64) uint64 {
+	confirmations := *tokens.SrcBridge.GetChainConfig().Confirmations
+	if latest < confirmations {
+		return next
+	}
+	stable := latest - confirmations
+	from := next
+	if from == 0 {
+		scanned, err := mongodb.FindUnsupportedDepositScanHeight()
+		switch {
+		case err == nil:
+			from = scanned + 1
+		case errors.Is(err, mongodb.ErrItemNotFound):
+			from = startHeight
+			if from == 0 {
+				from = stable
+			}
+		default:
+			logWorkerError("unsupporteddeposit", "find unsupported deposit scan height failed", err)
+			return next
+		}
+	}
+	depositAddresses, supportedTokens := getUnsupportedDepositFilter()
+	if len(depositAddresses) == 0 {
+		return from
+	}
+	depositTopics := make([]common.Hash, 0, len(depositAddresses))
+	for address := range depositAddresses {
+		depositTopics = append(depositTopics, common.BytesToHash(common.HexToAddress(address).Bytes()))
+	}
+	getter := tokens.SrcBridge.(chainLogGetter)
+	for from <= stable {
+		to := from + unsupportedDepositScanBatchSize - 1
+		if to > stable {
+			to = stable
+		}
+		logs, err := getter.GetLogs(&types.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Topics:    [][]common.Hash{{erc20TransferTopic}, nil, depositTopics},
+		})
+		if err != nil {
+			logWorkerError("unsupporteddeposit", "get transfer logs failed", err, "from", from, "to", to)
+			return from
+		}
+		found := 0
+		for _, rlog := range logs {
+			deposit := parseUnsupportedDeposit(rlog, depositAddresses, supportedTokens)
+			if deposit == nil {
+				continue
+			}
+			err = mongodb.AddUnsupportedDeposit(deposit)
+			if errors.Is(err, mongodb.ErrItemIsDup) {
+				continue // rescanned after restart
+			}
+			if err != nil {
+				return from
+			}
+			found++
+			logWorker("unsupporteddeposit", "found deposit of unsupported token", "token", deposit.Token, "from", deposit.From, "to", deposit.To, "value", deposit.Value, "txid", deposit.TxID)
+			pushUnsupportedDeposit(deposit)
+		}
+		if err = mongodb.UpdateUnsupportedDepositScanHeight(to); err != nil {
+			return from
+		}
+		logWorker("unsupporteddeposit", "scanned unsupported deposits", "from", from, "to", to, "found", found)
+		from = to + 1
+	}
+	return from
+}
+
+// parseUnsupportedDeposit parse erc20 transfer log of unsupported token to deposit address, nil if not
+func parseUnsupportedDeposit(rlog *types.RPCLog, depositAddresses, supportedTokens map[string]struct{}) *mongodb.MgoUnsupportedDeposit {
+	if rlog.Removed != nil && *rlog.Removed {
+		return nil
+	}
+	if rlog.Address == nil || rlog.TxHash == nil || rlog.LogIndex == nil {
+		return nil
+	}
+	// erc721 transfer has indexed token id and no data
+	if len(rlog.Topics) != 3 || rlog.Topics[0] != erc20TransferTopic || rlog.Data == nil || len(*rlog.Data) != 32 {
+		return nil
+	}
+	token := strings.ToLower(rlog.Address.String())
+	if _, exist := supportedTokens[token]; exist {
+		return nil
+	}
+	to := strings.ToLower(common.BytesToAddress(rlog.Topics[2][:]).String())
+	if _, exist := depositAddresses[to]; !exist {
+		return nil
+	}
+	deposit := &mongodb.MgoUnsupportedDeposit{
+		TxID:     rlog.TxHash.String(),
+		LogIndex: uint(*rlog.LogIndex),
+		Token:    token,
+		From:     strings.ToLower(common.BytesToAddress(rlog.Topics[1][:]).String()),
+		To:       to,
+		Value:    new(big.Int).SetBytes(*rlog.Data).String(),
+	}
+	if rlog.BlockNumber != nil {
+		deposit.BlockHeight = uint64(*rlog.BlockNumber)
+	}
+	return deposit
+}
+
+func pushUnsupportedDeposit(deposit *mongodb.MgoUnsupportedDeposit) {
+	config := params.GetServerConfig().UnsupportedDeposit
+	if config == nil || config.PushURL == "" {
+		return
+	}
+	resp, err := client.HTTPPost(config.PushURL, deposit, nil, nil, unsupportedDepositPushTimeout)
+	if err == nil {
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("response status %v", resp.Status)
+		}
+	}
+	if err != nil {
+		logWorkerError("unsupporteddeposit", "push unsupported deposit failed", err, "txid", deposit.TxID, "url", config.PushURL)
+	}
+}
@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/common/hexutil"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+const (
+	testDepositAddress   = "0x1111111111111111111111111111111111111111"
+	testSupportedToken   = "0x2222222222222222222222222222222222222222"
+	testUnsupportedToken = "0x3333333333333333333333333333333333333333"
+	testDepositSender    = "0x4444444444444444444444444444444444444444"
+)
+
+func newTestTransferLog(token, to string, value int64) *types.RPCLog {
+	tokenAddr := common.HexToAddress(token)
+	txHash := common.HexToHash("0xabcd")
+	data := hexutil.Bytes(common.BigToHash(big.NewInt(value)).Bytes())
+	logIndex := hexutil.Uint(3)
+	blockNumber := hexutil.Uint64(100)
+	return &types.RPCLog{
+		Address: &tokenAddr,
+		Topics: []common.Hash{
+			erc20TransferTopic,
+			common.BytesToHash(common.HexToAddress(testDepositSender).Bytes()),
+			common.BytesToHash(common.HexToAddress(to).Bytes()),
+		},
+		Data:        &data,
+		BlockNumber: &blockNumber,
+		TxHash:      &txHash,
+		LogIndex:    &logIndex,
+	}
+}
+
+func TestParseUnsupportedDeposit(t *testing.T) {
+	depositAddresses := map[string]struct{}{testDepositAddress: {}}
+	supportedTokens := map[string]struct{}{testSupportedToken: {}}
+
+	deposit := parseUnsupportedDeposit(newTestTransferLog(testUnsupportedToken, testDepositAddress, 100), depositAddresses, supportedTokens)
+	if deposit == nil {
+		t.Fatal("unsupported deposit is not recognized")
+	}
+	if deposit.Token != testUnsupportedToken || deposit.From != testDepositSender || deposit.To != testDepositAddress {
+		t.Errorf("wrong addresses of unsupported deposit: %+v", deposit)
+	}
+	if deposit.Value != "100" || deposit.LogIndex != 3 || deposit.BlockHeight != 100 {
+		t.Errorf("wrong unsupported deposit: %+v", deposit)
+	}
+
+	if parseUnsupportedDeposit(newTestTransferLog(testSupportedToken, testDepositAddress, 100), depositAddresses, supportedTokens) != nil {
+		t.Error("transfer of supported token is recorded")
+	}
+	if parseUnsupportedDeposit(newTestTransferLog(testUnsupportedToken, testDepositSender, 100), depositAddresses, supportedTokens) != nil {
+		t.Error("transfer to other address is recorded")
+	}
+
+	removed := true
+	rlog := newTestTransferLog(testUnsupportedToken, testDepositAddress, 100)
+	rlog.Removed = &removed
+	if parseUnsupportedDeposit(rlog, depositAddresses, supportedTokens) != nil {
+		t.Error("removed log is recorded")
+	}
+
+	rlog = newTestTransferLog(testUnsupportedToken, testDepositAddress, 100)
+	rlog.Topics = append(rlog.Topics, common.BigToHash(common.Big1))
+	if parseUnsupportedDeposit(rlog, depositAddresses, supportedTokens) != nil {
+		t.Error("erc721 transfer is recorded")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/internal/metrics"
 	"github.com/anyswap/CrossChain-Bridge/mongodb"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 )
@@ -13,8 +14,25 @@ import (
 var (
 	swapinVerifyStarter  sync.Once
 	swapoutVerifyStarter sync.Once
+
+	swapsInVerify sync.Map // swap key -> struct{}, swaps being verified
+
+	errSwapIsVerifying = errors.New("swap is being verified")
 )
 
+func getSwapVerifyKey(swap *mongodb.MgoSwap, isSwapin bool) string {
+	return fmt.Sprintf("%v:%v", isSwapin, mongodb.GetSwapKey(swap.TxID, swap.PairID, swap.Bind))
+}
+
+// tryLockSwapVerify prevent verify job and orphan repair handling the same swap concurrently
+func tryLockSwapVerify(swap *mongodb.MgoSwap, isSwapin bool) (unlock func(), ok bool) {
+	key := getSwapVerifyKey(swap, isSwapin)
+	if _, loaded := swapsInVerify.LoadOrStore(key, struct{}{}); loaded {
+		return nil, false
+	}
+	return func() { swapsInVerify.Delete(key) }, true
+}
+
 // StartVerifyJob verify job
 func StartVerifyJob() {
 	mongodb.MgoWaitGroup.Add(2)
@@ -41,13 +59,16 @@ func startSwapinVerifyJob() {
 				}
 				err = processSwapinVerify(swap)
 				switch {
-				case err == nil,
-					errors.Is(err, tokens.ErrTxNotStable),
+				case err == nil:
+					resetSwapFailure(true, false, swap.TxID, swap.PairID, swap.Bind, swap.FailCount)
+				case errors.Is(err, tokens.ErrTxNotStable),
 					errors.Is(err, tokens.ErrTxNotFound),
 					errors.Is(err, tokens.ErrUnknownPairID),
-					errors.Is(err, tokens.ErrSwapIsClosed):
+					errors.Is(err, tokens.ErrSwapIsClosed),
+					errors.Is(err, errSwapIsVerifying):
 				default:
 					logWorkerError("verify", "process swapin verify error", err, "txid", swap.TxID)
+					recordSwapFailure("verify", true, false, swap.TxID, swap.PairID, swap.Bind, mongodb.StageVerify, err)
 				}
 			}
 			if utils.IsCleanuping() {
@@ -78,13 +99,16 @@ func startSwapoutVerifyJob() {
 				}
 				err = processSwapoutVerify(swap)
 				switch {
-				case err == nil,
-					errors.Is(err, tokens.ErrTxNotStable),
+				case err == nil:
+					resetSwapFailure(false, false, swap.TxID, swap.PairID, swap.Bind, swap.FailCount)
+				case errors.Is(err, tokens.ErrTxNotStable),
 					errors.Is(err, tokens.ErrTxNotFound),
 					errors.Is(err, tokens.ErrUnknownPairID),
-					errors.Is(err, tokens.ErrSwapIsClosed):
+					errors.Is(err, tokens.ErrSwapIsClosed),
+					errors.Is(err, errSwapIsVerifying):
 				default:
 					logWorkerError("verify", "process swapout verify error", err, "txid", swap.TxID)
+					recordSwapFailure("verify", false, false, swap.TxID, swap.PairID, swap.Bind, mongodb.StageVerify, err)
 				}
 			}
 			if utils.IsCleanuping() {
@@ -96,16 +120,28 @@ func startSwapoutVerifyJob() {
 	})
 }
 
+// swaps registered by unstable verification and swaps to reverify
+var statusesToVerify = []mongodb.SwapStatus{mongodb.RegisteredUnstable, mongodb.TxNotStable}
+
 func findSwapinsToVerify() ([]*mongodb.MgoSwap, error) {
-	status := mongodb.TxNotStable
-	septime := getSepTimeInFind(maxVerifyLifetime)
-	return mongodb.FindSwapinsWithStatus(status, septime)
+	return findSwapsToVerify(mongodb.FindSwapinsWithStatus)
 }
 
 func findSwapoutsToVerify() ([]*mongodb.MgoSwap, error) {
-	status := mongodb.TxNotStable
+	return findSwapsToVerify(mongodb.FindSwapoutsWithStatus)
+}
+
+func findSwapsToVerify(findSwapsWithStatus func(mongodb.SwapStatus, int64) ([]*mongodb.MgoSwap, error)) (result []*mongodb.MgoSwap, err error) {
 	septime := getSepTimeInFind(maxVerifyLifetime)
-	return mongodb.FindSwapoutsWithStatus(status, septime)
+	for _, status := range statusesToVerify {
+		swaps, errf := findSwapsWithStatus(status, septime)
+		if errf != nil {
+			err = errf
+			continue
+		}
+		result = append(result, swaps...)
+	}
+	return result, err
 }
 
 func isInBlacklist(swapInfo *tokens.TxSwapInfo) (isBlacked bool, err error) {
@@ -131,6 +167,12 @@ func processSwapoutVerify(swap *mongodb.MgoSwap) error {
 }
 
 func processSwapVerify(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
+	unlock, ok := tryLockSwapVerify(swap, isSwapin)
+	if !ok {
+		return errSwapIsVerifying
+	}
+	defer unlock()
+
 	pairID := swap.PairID
 	txid := swap.TxID
 	bind := swap.Bind
@@ -147,6 +189,9 @@ func processSwapVerify(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
 	}
 
 	swapInfo, err := verifySwapTransaction(bridge, pairID, txid, bind, tokens.SwapTxType(swap.TxType))
+	if err != nil {
+		metrics.VerifyErrors.Inc("verify", tokens.SwapTxType(swap.TxType).String(), mongodb.GetVerifyErrorName(err))
+	}
 	if swapInfo == nil {
 		return err
 	}
@@ -170,6 +215,7 @@ func processSwapVerify(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
 
 func updateSwapStatus(pairID, txid, bind string, swapInfo *tokens.TxSwapInfo, isSwapin bool, err error) error {
 	resultStatus := mongodb.MatchTxEmpty
+	memo := ""
 
 	switch {
 	case errors.Is(err, tokens.ErrTxNotStable),
@@ -183,36 +229,47 @@ func updateSwapStatus(pairID, txid, bind string, swapInfo *tokens.TxSwapInfo, is
 		return err
 	case err == nil:
 		status := mongodb.TxNotSwapped
-		if swapInfo.Value.Cmp(tokens.GetBigValueThreshold(pairID, isSwapin)) > 0 {
-			tokenCfg := tokens.GetTokenConfig(pairID, isSwapin)
-			if !tokenCfg.IsInBigValueWhitelist(swapInfo.From) &&
-				!tokenCfg.IsInBigValueWhitelist(swapInfo.TxTo) {
-				status = mongodb.TxWithBigValue
-				resultStatus = mongodb.TxWithBigValue
-			}
+		bigValue := tokens.CheckBigValue(pairID, swapInfo.Value, isSwapin, swapInfo.From, swapInfo.TxTo)
+		if bigValue.IsBigValue {
+			status = mongodb.TxWithBigValue
+			resultStatus = mongodb.TxWithBigValue
+			memo = bigValue.Memo()
+			logWorker("verify", "found big value swap", "txid", txid, "bind", bind, "isSwapin", isSwapin, "rule", bigValue.Rule(), "detail", bigValue.Detail)
 		}
-		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, status, now(), "")
+		// verified with allowUnstable=false, mark it as signable
+		err = mongodb.UpdateSwapStatusStableVerified(isSwapin, txid, pairID, bind, status, now(), memo)
 	case errors.Is(err, tokens.ErrTxWithWrongMemo):
 		resultStatus = mongodb.TxWithWrongMemo
 		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxWithWrongMemo, now(), err.Error())
 	case errors.Is(err, tokens.ErrBindAddrIsContract):
 		resultStatus = mongodb.BindAddrIsContract
 		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.BindAddrIsContract, now(), err.Error())
-	case errors.Is(err, tokens.ErrTxWithWrongValue):
+	case errors.Is(err, tokens.ErrSwappedValueIsDust):
+		resultStatus = mongodb.SwapValueIsDust
+		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.SwapValueIsDust, now(), err.Error())
+	case errors.Is(err, tokens.ErrTxWithWrongValue),
+		errors.Is(err, tokens.ErrSwapoutValueIsDust):
 		resultStatus = mongodb.TxWithWrongValue
 		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxWithWrongValue, now(), err.Error())
 	case errors.Is(err, tokens.ErrTxSenderNotRegistered):
 		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxSenderNotRegistered, now(), err.Error())
-	case errors.Is(err, tokens.ErrBindAddressMismatch):
-		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxVerifyFailed, now(), err.Error())
 	default:
-		logWorkerWarn("verify", "maybe not considered tx verify error", "txid", txid, "bind", bind, "isSwapin", isSwapin, "err", err)
-		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxVerifyFailed, now(), err.Error())
+		status, exist := mongodb.GetVerifyErrorStatus(err)
+		if !exist {
+			logWorkerWarn("verify", "maybe not considered tx verify error", "txid", txid, "bind", bind, "isSwapin", isSwapin, "err", err)
+		}
+		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, status, now(), err.Error())
 	}
 
 	if err != nil {
 		logWorkerError("verify", "update swap status", err, "txid", txid, "bind", bind, "isSwapin", isSwapin)
 		return err
 	}
-	return addInitialSwapResult(swapInfo, resultStatus, isSwapin)
+	err = addInitialSwapResult(swapInfo, resultStatus, isSwapin, memo)
+	if err == nil && resultStatus == mongodb.TxWithBigValue {
+		if config := getBigValueReviewConfig(); config != nil && config.PushURL != "" {
+			go notifyBigValuePending(isSwapin, txid, pairID, bind)
+		}
+	}
+	return err
 }
@@ -3,6 +3,7 @@ package worker
 import (
 	"time"
 
+	"github.com/anyswap/CrossChain-Bridge/dcrm"
 	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/rpc/client"
 	"github.com/anyswap/CrossChain-Bridge/tokens/bridge"
@@ -20,6 +21,9 @@ func StartWork(isServer bool) {
 
 	client.InitHTTPClient()
 	bridge.InitCrossChainBridge(isServer)
+	if isServer {
+		dcrm.SetSignAttemptHandler(recordSignAttempt)
+	}
 
 	if params.IsTestMode() {
 		if isServer {
@@ -45,10 +49,33 @@ func StartWork(isServer bool) {
 		return
 	}
 
-	StartSwapJob()
+	go repairOrphanedSwaps()
+	StartVerifyJob()
 	time.Sleep(interval)
 
-	StartVerifyJob()
+	StartDailyReportJob()
+	time.Sleep(interval)
+
+	StartBalanceMonitorJob()
+	time.Sleep(interval)
+
+	if needStartupReconcile() {
+		go func() {
+			runStartupReconcile()
+			waitReconcileConfirmed()
+			startPayoutJobs()
+		}()
+		return
+	}
+	startPayoutJobs()
+}
+
+// payout jobs are paused until startup reconcile is confirmed
+func startPayoutJobs() {
+	StartBroadcastWatchJob()
+	time.Sleep(interval)
+
+	StartSwapJob()
 	time.Sleep(interval)
 
 	StartStableJob()
@@ -63,5 +90,11 @@ func StartWork(isServer bool) {
 	StartAggregateJob()
 	time.Sleep(interval)
 
+	StartP2shExpireJob()
+	time.Sleep(interval)
+
+	StartRefundStableJob()
+	time.Sleep(interval)
+
 	StartCheckFailedSwapJob()
 }